@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/app"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/permissions"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -19,6 +23,7 @@ import (
 const (
 	HeaderRequestedWith    = "X-Requested-With"
 	HeaderRequestedWithXML = "XMLHttpRequest"
+	HeaderIfMatch          = "If-Match"
 	UploadFormFileKey      = "file"
 	True                   = "true"
 
@@ -41,6 +46,7 @@ type API struct {
 	MattermostAuth  bool
 	logger          mlog.LoggerIFace
 	audit           *audit.Audit
+	metrics         *metrics.Metrics
 }
 
 func NewAPI(
@@ -50,6 +56,7 @@ func NewAPI(
 	permissions permissions.PermissionsService,
 	logger mlog.LoggerIFace,
 	audit *audit.Audit,
+	metrics *metrics.Metrics,
 ) *API {
 	return &API{
 		app:             app,
@@ -58,11 +65,20 @@ func NewAPI(
 		permissions:     permissions,
 		logger:          logger,
 		audit:           audit,
+		metrics:         metrics,
 	}
 }
 
 func (a *API) RegisterRoutes(r *mux.Router) {
+	// Incoming webhooks are called by other systems without a session or
+	// the CSRF header, so they are registered before the other V2 routes
+	hooks := r.PathPrefix("/api/v2/hooks").Subrouter()
+	hooks.Use(a.metricsHandler)
+	hooks.Use(a.panicHandler)
+	a.registerIncomingWebhookHooksRoutes(hooks)
+
 	apiv2 := r.PathPrefix("/api/v2").Subrouter()
+	apiv2.Use(a.metricsHandler)
 	apiv2.Use(a.panicHandler)
 	apiv2.Use(a.requireCSRFToken)
 
@@ -94,6 +110,14 @@ func (a *API) RegisterRoutes(r *mux.Router) {
 	a.registerContentBlocksRoutes(apiv2)
 	a.registerStatisticsRoutes(apiv2)
 	a.registerComplianceRoutes(apiv2)
+	a.registerTrashRoutes(apiv2)
+	a.registerRetentionRoutes(apiv2)
+	a.registerCardPropertiesRoutes(apiv2)
+	a.registerRecurringCardsRoutes(apiv2)
+	a.registerDueDateRemindersRoutes(apiv2)
+	a.registerWebhooksRoutes(apiv2)
+	a.registerIncomingWebhooksRoutes(apiv2)
+	a.registerAutomationsRoutes(apiv2)
 
 	// V3 routes
 	a.registerCardsRoutes(apiv2)
@@ -115,6 +139,44 @@ func getUserID(r *http.Request) string {
 	return session.UserID
 }
 
+// statusRecorder keeps the status code written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// metricsHandler records the duration and the status of the requests,
+// labeled by their route template so the IDs in the path don't create
+// new series.
+func (a *API) metricsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.metrics == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route := "unknown"
+		if currentRoute := mux.CurrentRoute(r); currentRoute != nil {
+			if template, err := currentRoute.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			a.metrics.ObserveAPIRequestDuration(route, r.Method, recorder.status, time.Since(start).Seconds())
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
 func (a *API) panicHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -182,6 +244,14 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case model.IsErrBadRequest(err):
 		errorResponse.ErrorCode = http.StatusBadRequest
+		var invalidValues *model.ErrInvalidPropertyValues
+		if errors.As(err, &invalidValues) {
+			errorResponse.InvalidProperties = invalidValues.Properties
+		}
+		var unconvertible *model.ErrUnconvertibleValues
+		if errors.As(err, &unconvertible) {
+			errorResponse.Unconvertible = unconvertible.Values
+		}
 	case model.IsErrUnauthorized(err):
 		errorResponse.ErrorCode = http.StatusUnauthorized
 	case model.IsErrForbidden(err):
@@ -192,6 +262,12 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 		errorResponse.ErrorCode = http.StatusRequestEntityTooLarge
 	case model.IsErrNotImplemented(err):
 		errorResponse.ErrorCode = http.StatusNotImplemented
+	case model.IsErrConflict(err):
+		errorResponse.ErrorCode = http.StatusConflict
+		var conflict *model.ErrConflict
+		if errors.As(err, &conflict) {
+			errorResponse.Current = conflict.Current
+		}
 	default:
 		a.logger.Error("API ERROR",
 			mlog.Int("code", http.StatusInternalServerError),
@@ -212,6 +288,30 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	_, _ = w.Write(data)
 }
 
+// expectedUpdateAtFromRequest returns the version of the entity that a
+// patch request expects, taken from its If-Match header. The header holds
+// the ETag returned by the server, which is the quoted UpdateAt of the
+// entity. It returns nil if the request has no precondition.
+func expectedUpdateAtFromRequest(r *http.Request) (*int64, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, nil
+	}
+
+	ifMatch = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	updateAt, err := strconv.ParseInt(ifMatch, 10, 64)
+	if err != nil {
+		return nil, model.NewErrBadRequest("invalid If-Match header: " + r.Header.Get(HeaderIfMatch))
+	}
+	return &updateAt, nil
+}
+
+// setETagHeader sets the ETag of an entity from its UpdateAt, to be sent
+// back in the If-Match header of the next patch request.
+func setETagHeader(w http.ResponseWriter, updateAt int64) {
+	setResponseHeader(w, "ETag", strconv.Quote(strconv.FormatInt(updateAt, 10)))
+}
+
 func stringResponse(w http.ResponseWriter, message string) {
 	setResponseHeader(w, "Content-Type", "text/plain")
 	_, _ = fmt.Fprint(w, message)
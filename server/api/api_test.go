@@ -8,7 +8,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/stretchr/testify/require"
 )
@@ -75,3 +77,29 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsHandler(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	metricsService := metrics.NewMetrics(metrics.InstanceInfo{})
+	testAPI := API{logger: logger, metrics: metricsService}
+
+	r := mux.NewRouter()
+	r.Use(testAPI.metricsHandler)
+	r.HandleFunc("/boards/{boardID}", func(w http.ResponseWriter, r *http.Request) {
+		testAPI.errorResponse(w, r, model.NewErrNotFound("board"))
+	}).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID}/blocks", func(w http.ResponseWriter, r *http.Request) {
+		jsonStringResponse(w, http.StatusOK, "[]")
+	}).Methods(http.MethodGet)
+
+	for _, path := range []string{"/boards/board-1", "/boards/board-2", "/boards/board-1/blocks"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.NewMetricsServer("", metricsService, logger).Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	require.Contains(t, body, `focalboard_api_request_duration_seconds_count{method="GET",route="/boards/{boardID}",status="404"} 2`)
+	require.Contains(t, body, `focalboard_api_request_duration_seconds_count{method="GET",route="/boards/{boardID}/blocks",status="200"} 1`)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -38,6 +39,11 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 	//   description: Id of board to export
 	//   required: true
 	//   type: string
+	// - name: modified_since
+	//   in: query
+	//   description: If set, only the changes made at or after this time, in milliseconds since the epoch, are exported, along with tombstones for the deletions
+	//   required: false
+	//   type: integer
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -77,9 +83,18 @@ func (a *API) handleArchiveExportBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	modifiedSince, err := modifiedSinceFromRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("modifiedSince", modifiedSince)
+
 	opts := model.ExportArchiveOptions{
-		TeamID:   board.TeamID,
-		BoardIDs: []string{board.ID},
+		TeamID:        board.TeamID,
+		BoardIDs:      []string{board.ID},
+		ModifiedSince: modifiedSince,
+		UserID:        userID,
 	}
 
 	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
@@ -115,6 +130,11 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	//   description: archive file to import
 	//   required: true
 	//   type: file
+	// - name: incremental
+	//   in: query
+	//   description: If true, the IDs of the archive are kept so the boards imported before are updated, and the tombstones of incremental archives are applied
+	//   required: false
+	//   type: boolean
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -159,9 +179,13 @@ func (a *API) handleArchiveImport(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("filename", handle.Filename)
 	auditRec.AddMeta("size", handle.Size)
 
+	incremental := r.URL.Query().Get("incremental") == True
+	auditRec.AddMeta("incremental", incremental)
+
 	opt := model.ImportArchiveOptions{
-		TeamID:     teamID,
-		ModifiedBy: userID,
+		TeamID:      teamID,
+		ModifiedBy:  userID,
+		Incremental: incremental,
 	}
 
 	if err := a.app.ImportArchive(file, opt); err != nil {
@@ -191,6 +215,11 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	//   description: Id of team
 	//   required: true
 	//   type: string
+	// - name: modified_since
+	//   in: query
+	//   description: If set, only the changes made at or after this time, in milliseconds since the epoch, are exported, along with tombstones for the deletions
+	//   required: false
+	//   type: integer
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -216,9 +245,16 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	session, _ := ctx.Value(sessionContextKey).(*model.Session)
 	userID := session.UserID
 
+	modifiedSince, err := modifiedSinceFromRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	auditRec := a.makeAuditRecord(r, "archiveExportTeam", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelRead, auditRec)
 	auditRec.AddMeta("TeamID", teamID)
+	auditRec.AddMeta("modifiedSince", modifiedSince)
 
 	isGuest, err := a.userIsGuest(userID)
 	if err != nil {
@@ -237,8 +273,12 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	opts := model.ExportArchiveOptions{
-		TeamID:   teamID,
-		BoardIDs: ids,
+		TeamID:               teamID,
+		BoardIDs:             ids,
+		ModifiedSince:        modifiedSince,
+		IncludeDeletedBoards: true,
+		UserID:               userID,
+		IncludePublicBoards:  !isGuest,
 	}
 
 	filename := fmt.Sprintf("archive-%s%s", time.Now().Format("2006-01-02"), archiveExtension)
@@ -252,3 +292,18 @@ func (a *API) handleArchiveExportTeam(w http.ResponseWriter, r *http.Request) {
 
 	auditRec.Success()
 }
+
+// modifiedSinceFromRequest returns the cursor of an incremental export, or
+// zero for a full export.
+func modifiedSinceFromRequest(r *http.Request) (int64, error) {
+	value := r.URL.Query().Get("modified_since")
+	if value == "" {
+		return 0, nil
+	}
+
+	modifiedSince, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || modifiedSince < 0 {
+		return 0, model.NewErrBadRequest("invalid modified_since: " + value)
+	}
+	return modifiedSince, nil
+}
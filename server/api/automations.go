@@ -0,0 +1,515 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	automationExecutionsDefaultPerPage = 50
+	automationExecutionsMaxPerPage     = 200
+)
+
+func (a *API) registerAutomationsRoutes(r *mux.Router) {
+	// Automation rules APIs
+	r.HandleFunc("/boards/{boardID}/automations", a.sessionRequired(a.handleGetAutomationRules)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/automations", a.sessionRequired(a.handleCreateAutomationRule)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}", a.sessionRequired(a.handleGetAutomationRule)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}", a.sessionRequired(a.handlePatchAutomationRule)).Methods("PATCH")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}", a.sessionRequired(a.handleDeleteAutomationRule)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/automations/{ruleID}/executions", a.sessionRequired(a.handleGetAutomationExecutions)).Methods("GET")
+}
+
+func (a *API) handleGetAutomationRules(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/automations getAutomationRules
+	//
+	// Returns the automation rules of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/AutomationRule"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getAutomationRules", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	rules, err := a.app.GetAutomationRulesForBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetAutomationRules",
+		mlog.String("boardID", boardID),
+		mlog.Int("ruleCount", len(rules)),
+	)
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/automations createAutomationRule
+	//
+	// Creates an automation rule that applies actions to the cards of a
+	// board when they're created, when a property changes or when their
+	// due date passes. The actions are applied as the user.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the automation rule to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/AutomationRule"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/AutomationRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var rule model.AutomationRule
+	if err = json.Unmarshal(requestBody, &rule); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	rule.BoardID = boardID
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create automation rules"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("triggerType", rule.Trigger.Type)
+
+	created, err := a.app.CreateAutomationRule(&rule, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", created.ID),
+	)
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("ruleID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleGetAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/automations/{ruleID} getAutomationRule
+	//
+	// Returns an automation rule of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/AutomationRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	rule, err := a.getAutomationRuleOfBoard(boardID, ruleID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	a.logger.Debug("GetAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+	)
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /boards/{boardID}/automations/{ruleID} patchAutomationRule
+	//
+	// Modifies an automation rule of a board. The actions are still applied
+	// as the user that created the rule.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the automation rule patch
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/AutomationRulePatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/AutomationRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.AutomationRulePatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify automation rules"))
+		return
+	}
+
+	rule, err := a.getAutomationRuleOfBoard(boardID, ruleID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	patched, err := a.app.PatchAutomationRule(rule, &patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PatchAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+		mlog.Bool("disabled", patched.Disabled),
+	)
+
+	data, err := json.Marshal(patched)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteAutomationRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/automations/{ruleID} deleteAutomationRule
+	//
+	// Deletes an automation rule of a board and its execution log.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to delete automation rules"))
+		return
+	}
+
+	if _, err := a.getAutomationRuleOfBoard(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteAutomationRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	if err := a.app.DeleteAutomationRule(ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteAutomationRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+	)
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleGetAutomationExecutions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/automations/{ruleID}/executions getAutomationExecutions
+	//
+	// Returns the execution log of an automation rule, newest first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Automation rule ID
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of executions to return per page (default=50, max=200)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/AutomationExecution"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+	query := r.URL.Query()
+
+	opts := model.QueryAutomationExecutionsOptions{
+		RuleID:  ruleID,
+		PerPage: automationExecutionsDefaultPerPage,
+	}
+	if strPage := query.Get("page"); strPage != "" {
+		var err error
+		if opts.Page, err = strconv.Atoi(strPage); err != nil || opts.Page < 0 {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `page` parameter: %s", strPage)))
+			return
+		}
+	}
+	if strPerPage := query.Get("per_page"); strPerPage != "" {
+		var err error
+		if opts.PerPage, err = strconv.Atoi(strPerPage); err != nil || opts.PerPage <= 0 || opts.PerPage > automationExecutionsMaxPerPage {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)))
+			return
+		}
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	if _, err := a.getAutomationRuleOfBoard(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getAutomationExecutions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+	auditRec.AddMeta("page", opts.Page)
+	auditRec.AddMeta("per_page", opts.PerPage)
+
+	executions, err := a.app.GetAutomationExecutions(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetAutomationExecutions",
+		mlog.String("ruleID", ruleID),
+		mlog.Int("executionCount", len(executions)),
+	)
+
+	data, err := json.Marshal(executions)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+// getAutomationRuleOfBoard returns a rule, or a not found error if it
+// belongs to another board than the one of the request.
+func (a *API) getAutomationRuleOfBoard(boardID, ruleID string) (*model.AutomationRule, error) {
+	rule, err := a.app.GetAutomationRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.BoardID != boardID {
+		return nil, model.NewErrNotFound("automation rule ID=" + ruleID)
+	}
+	return rule, nil
+}
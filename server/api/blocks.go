@@ -151,6 +151,13 @@ func (a *API) handleGetBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// the linked cards the user can't see are left out of the relations
+	blocks, err = a.app.ResolveBlockRelations(userID, boardID, blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetBlocks",
 		mlog.String("boardID", boardID),
 		mlog.String("parentID", parentID),
@@ -244,6 +251,12 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if _, ok := model.GetBlockTypeDefinition(block.Type); !ok {
+			message := fmt.Sprintf("unknown type %s for block id %s", block.Type, block.ID)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+
 		if block.Type == model.TypeComment {
 			hasComments = true
 		} else {
@@ -282,6 +295,11 @@ func (a *API) handlePostBlocks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err = a.app.ValidateBlockParents(boardID, blocks); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	blocks = model.GenerateBlockIDs(blocks, a.logger)
 
 	auditRec := a.makeAuditRecord(r, "postBlocks", audit.Fail)
@@ -518,6 +536,11 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	//   required: true
 	//   schema:
 	//     "$ref": "#/definitions/BlockPatch"
+	// - name: If-Match
+	//   in: header
+	//   description: ETag of the block the patch applies to, the patch fails with a conflict if it has been modified since
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -525,6 +548,10 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 	//     description: success
 	//   '404':
 	//     description: block not found
+	//   '409':
+	//     description: the block has been modified since the expected version
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -567,6 +594,15 @@ func (a *API) handlePatchBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedUpdateAt, err := expectedUpdateAtFromRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if expectedUpdateAt != nil {
+		patch.ExpectedUpdateAt = expectedUpdateAt
+	}
+
 	auditRec := a.makeAuditRecord(r, "patchBlock", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("boardID", boardID)
@@ -20,7 +20,9 @@ func (a *API) registerBoardsRoutes(r *mux.Router) {
 	r.HandleFunc("/boards/{boardID}", a.sessionRequired(a.handleDeleteBoard)).Methods("DELETE")
 	r.HandleFunc("/boards/{boardID}/duplicate", a.sessionRequired(a.handleDuplicateBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/undelete", a.sessionRequired(a.handleUndeleteBoard)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/restore", a.sessionRequired(a.handleRestoreBoard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/metadata", a.sessionRequired(a.handleGetBoardMetadata)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/properties/{propertyID}/convert", a.sessionRequired(a.handleConvertCardProperty)).Methods("POST")
 }
 
 func (a *API) handleGetBoards(w http.ResponseWriter, r *http.Request) {
@@ -278,6 +280,7 @@ func (a *API) handleGetBoard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// response
+	setETagHeader(w, board.UpdateAt)
 	jsonBytesResponse(w, http.StatusOK, data)
 
 	auditRec.Success()
@@ -303,15 +306,27 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	//   required: true
 	//   schema:
 	//     "$ref": "#/definitions/BoardPatch"
+	// - name: If-Match
+	//   in: header
+	//   description: ETag of the board the patch applies to, the patch fails with a conflict if it has been modified since
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
 	//     description: success
+	//     headers:
+	//       ETag:
+	//         type: string
 	//     schema:
 	//       $ref: '#/definitions/Board'
 	//   '404':
 	//     description: board not found
+	//   '409':
+	//     description: the board has been modified since the expected version
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -342,6 +357,15 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedUpdateAt, err := expectedUpdateAtFromRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if expectedUpdateAt != nil {
+		patch.ExpectedUpdateAt = expectedUpdateAt
+	}
+
 	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
 		a.errorResponse(w, r, model.NewErrPermission("access denied to modifying board properties"))
 		return
@@ -384,11 +408,115 @@ func (a *API) handlePatchBoard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// response
+	setETagHeader(w, updatedBoard.UpdateAt)
 	jsonBytesResponse(w, http.StatusOK, data)
 
 	auditRec.Success()
 }
 
+func (a *API) handleConvertCardProperty(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/properties/{propertyID}/convert convertCardProperty
+	//
+	// Changes the type of a card property and converts the values of the
+	// cards of the board. If some values cannot be converted, the conversion
+	// fails and returns them, unless dropUnconvertible is set, in which case
+	// they are removed from the cards and returned.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: propertyID
+	//   in: path
+	//   description: Card property ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the new type of the property
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/PropertyConversion"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/PropertyConversionResult'
+	//   '400':
+	//     description: invalid conversion, or values that cannot be converted
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	//   '404':
+	//     description: board or property not found
+	//   '409':
+	//     description: the board or its cards have been modified during the conversion
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	propertyID := mux.Vars(r)["propertyID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modifying board properties"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var conversion *model.PropertyConversion
+	if err = json.Unmarshal(requestBody, &conversion); err != nil || conversion == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid property conversion"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "convertCardProperty", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("propertyID", propertyID)
+	auditRec.AddMeta("type", conversion.Type)
+	auditRec.AddMeta("dryRun", conversion.DryRun)
+	auditRec.AddMeta("dropUnconvertible", conversion.DropUnconvertible)
+
+	result, err := a.app.ConvertCardProperty(boardID, propertyID, conversion, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("ConvertCardProperty",
+		mlog.String("boardID", boardID),
+		mlog.String("propertyID", propertyID),
+		mlog.String("type", conversion.Type),
+		mlog.Int("convertedCards", result.ConvertedCards),
+		mlog.Int("unconvertible", len(result.Unconvertible)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
 func (a *API) handleDeleteBoard(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation DELETE /boards/{boardID} deleteBoard
 	//
@@ -603,6 +731,104 @@ func (a *API) handleUndeleteBoard(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleRestoreBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/restore restoreBoard
+	//
+	// Restores a board, its cards, views and content to their state at a
+	// previous time. The restore is applied as a new revision, so it can be
+	// reverted as well. With dryRun the changes are returned but not applied.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: ID of board to restore
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the restore time and whether it's a dry run
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BoardRestoreOptions"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/BoardRestoreResult"
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var opts model.BoardRestoreOptions
+	if err = json.Unmarshal(requestBody, &opts); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if err = opts.IsValid(); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	if !opts.DryRun {
+		if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) ||
+			!a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+			a.errorResponse(w, r, model.NewErrPermission("access denied to restore board"))
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "restoreBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("restoreAt", opts.RestoreAt)
+	auditRec.AddMeta("dryRun", opts.DryRun)
+
+	result, err := a.app.RestoreBoardToTime(boardID, opts.RestoreAt, userID, opts.DryRun)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RestoreBoard",
+		mlog.String("boardID", boardID),
+		mlog.Int("restoreAt", opts.RestoreAt),
+		mlog.Bool("dryRun", opts.DryRun),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
 func (a *API) handleGetBoardMetadata(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /boards/{boardID}/metadata getBoardMetadata
 	//
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) handleGetCardDependencies(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/dependencies getCardDependencies
+	//
+	// Fetches the dependencies of the specified card on the cards that
+	// block it, and of the cards it blocks.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardDependencies'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	card, err := a.app.GetCardByID(cardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card dependencies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardDependencies", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	dependencies, err := a.app.GetCardDependencies(card, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetCardDependencies",
+		mlog.String("boardID", card.BoardID),
+		mlog.String("cardID", card.ID),
+		mlog.Int("blockedByCount", len(dependencies.BlockedBy)),
+		mlog.Int("blocksCount", len(dependencies.Blocks)),
+	)
+
+	data, err := json.Marshal(dependencies)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleAddCardDependency(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /cards/{cardID}/dependencies addCardDependency
+	//
+	// Makes the specified card blocked by another card, of the same board
+	// or of another board of the same team. Dependencies that would create
+	// a cycle are rejected.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: ID of the blocked card
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the blocker card
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CardDependencyRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/CardDependency'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var request model.CardDependencyRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	card, err := a.app.GetCardByID(cardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify card dependencies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "addCardDependency", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("blockerID", request.BlockerID)
+
+	dependency, err := a.app.AddCardDependency(card, request.BlockerID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AddCardDependency",
+		mlog.String("cardID", card.ID),
+		mlog.String("blockerID", dependency.BlockerID),
+	)
+
+	data, err := json.Marshal(dependency)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteCardDependency(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /cards/{cardID}/dependencies/{blockerID} deleteCardDependency
+	//
+	// Removes a blocker of the specified card.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: ID of the blocked card
+	//   required: true
+	//   type: string
+	// - name: blockerID
+	//   in: path
+	//   description: ID of the blocker card
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	cardID := vars["cardID"]
+	blockerID := vars["blockerID"]
+
+	card, err := a.app.GetCardByID(cardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify card dependencies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteCardDependency", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.AddMeta("blockerID", blockerID)
+
+	if err = a.app.DeleteCardDependency(card, blockerID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteCardDependency",
+		mlog.String("cardID", card.ID),
+		mlog.String("blockerID", blockerID),
+	)
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
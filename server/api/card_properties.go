@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	invalidCardPropertiesDefaultPerPage = 20
+)
+
+func (a *API) registerCardPropertiesRoutes(r *mux.Router) {
+	// Card properties APIs
+	r.HandleFunc("/admin/teams/{teamID}/invalid_card_properties", a.sessionRequired(a.handleScanInvalidCardProperties)).Methods("GET")
+}
+
+func (a *API) handleScanInvalidCardProperties(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/teams/{teamID}/invalid_card_properties scanInvalidCardProperties
+	//
+	// Scans the cards of a page of the boards of a team, and returns the
+	// ones with property values that don't match the card properties of
+	// their board, such as unknown options, malformed dates, non numeric
+	// numbers or persons that aren't members of the board.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page of boards to scan (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of boards to scan per page (default=20)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/InvalidCardPropertiesResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+	query := r.URL.Query()
+
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to scan card properties"))
+		return
+	}
+
+	page := 0
+	if strPage := query.Get("page"); strPage != "" {
+		var err error
+		if page, err = strconv.Atoi(strPage); err != nil || page < 0 {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `page` parameter: %s", strPage)))
+			return
+		}
+	}
+	perPage := invalidCardPropertiesDefaultPerPage
+	if strPerPage := query.Get("per_page"); strPerPage != "" {
+		var err error
+		if perPage, err = strconv.Atoi(strPerPage); err != nil || perPage <= 0 {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)))
+			return
+		}
+	}
+
+	auditRec := a.makeAuditRecord(r, "scanInvalidCardProperties", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("page", page)
+	auditRec.AddMeta("per_page", perPage)
+
+	result, err := a.app.ScanInvalidCardProperties(teamID, page, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("ScanInvalidCardProperties",
+		mlog.String("teamID", teamID),
+		mlog.Int("cardsCount", len(result.Results)),
+		mlog.Bool("hasNext", result.HasNext),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
@@ -23,8 +23,14 @@ func (a *API) registerCardsRoutes(r *mux.Router) {
 	// Cards APIs
 	r.HandleFunc("/boards/{boardID}/cards", a.sessionRequired(a.handleCreateCard)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/cards", a.sessionRequired(a.handleGetCards)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/cards/query", a.sessionRequired(a.handleQueryCards)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/cards/from-template/{templateID}", a.sessionRequired(a.handleCreateCardFromTemplate)).Methods("POST")
 	r.HandleFunc("/cards/{cardID}", a.sessionRequired(a.handlePatchCard)).Methods("PATCH")
 	r.HandleFunc("/cards/{cardID}", a.sessionRequired(a.handleGetCard)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/activity", a.sessionRequired(a.handleGetCardActivity)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/dependencies", a.sessionRequired(a.handleGetCardDependencies)).Methods("GET")
+	r.HandleFunc("/cards/{cardID}/dependencies", a.sessionRequired(a.handleAddCardDependency)).Methods("POST")
+	r.HandleFunc("/cards/{cardID}/dependencies/{blockerID}", a.sessionRequired(a.handleDeleteCardDependency)).Methods("DELETE")
 }
 
 func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
@@ -108,6 +114,18 @@ func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	if err = a.app.ResolveCardRelations(userID, boardID, card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
 
 	a.logger.Debug("CreateCard",
 		mlog.String("boardID", boardID),
@@ -127,6 +145,119 @@ func (a *API) handleCreateCard(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleCreateCardFromTemplate(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/cards/from-template/{templateID} createCardFromTemplate
+	//
+	// Creates a new card from a card template of the specified board. The
+	// placeholders of the titles of the card and its content and of its
+	// property values are resolved: {{today}}, {{today+7d}}, {{today-2w}},
+	// {{creator}}, {{board.title}} and the variables of the request.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: templateID
+	//   in: path
+	//   description: ID of the card template
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the values of the variables of the placeholders
+	//   required: false
+	//   schema:
+	//     "$ref": "#/definitions/CardFromTemplateRequest"
+	// - name: disable_notify
+	//   in: query
+	//   description: Disables notifications (for bulk data inserting)
+	//   required: false
+	//   type: bool
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/Card'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	templateID := vars["templateID"]
+
+	val := r.URL.Query().Get("disable_notify")
+	disableNotify := val == True
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	request := &model.CardFromTemplateRequest{}
+	if len(requestBody) > 0 {
+		if err = json.Unmarshal(requestBody, request); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create card"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createCardFromTemplate", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("templateID", templateID)
+
+	card, err := a.app.CreateCardFromTemplate(boardID, templateID, userID, request, disableNotify)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.ResolveCardRelations(userID, boardID, card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateCardFromTemplate",
+		mlog.String("boardID", boardID),
+		mlog.String("templateID", templateID),
+		mlog.String("cardID", card.ID),
+		mlog.String("userID", userID),
+	)
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("cardID", card.ID)
+	auditRec.Success()
+}
+
 func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /boards/{boardID}/cards getCards
 	//
@@ -151,6 +282,18 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	//   description: Number of cards to return per page(default=100)
 	//   required: false
 	//   type: integer
+	// - name: checklist
+	//   in: query
+	//   description: Only return the cards whose checklist is complete, incomplete or that have none
+	//   required: false
+	//   type: string
+	//   enum: [complete, incomplete, none]
+	// - name: checklist_sort
+	//   in: query
+	//   description: Sort the cards by the checked fraction of their checklist, the cards without one last
+	//   required: false
+	//   type: string
+	//   enum: [asc, desc]
 	// security:
 	// - BearerAuth: []
 	// responses:
@@ -170,6 +313,10 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	strPage := query.Get("page")
 	strPerPage := query.Get("per_page")
+	checklist := model.ChecklistQuery{
+		Filter: query.Get("checklist"),
+		Sort:   query.Get("checklist_sort"),
+	}
 
 	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
 		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch cards"))
@@ -200,12 +347,26 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", boardID)
 	auditRec.AddMeta("page", page)
 	auditRec.AddMeta("per_page", perPage)
+	auditRec.AddMeta("checklist", checklist.Filter)
+	auditRec.AddMeta("checklist_sort", checklist.Sort)
 
-	cards, err := a.app.GetCardsForBoard(boardID, page, perPage)
+	cards, err := a.app.GetCardsForBoard(boardID, userID, page, perPage, checklist)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
+	if err = a.app.ResolveCardRelations(userID, boardID, cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
 
 	a.logger.Debug("GetCards",
 		mlog.String("boardID", boardID),
@@ -227,6 +388,148 @@ func (a *API) handleGetCards(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleQueryCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/cards/query queryCards
+	//
+	// Fetches the cards of the specified board that match a filter, sorted and
+	// paginated, along with the number of cards per group. If a view ID is
+	// provided, the view filter, sort options and group by property are used
+	// for the options not present in the query. Filtering, sorting or grouping
+	// on properties other than the title and the text, select, person, URL,
+	// email and phone properties fails with a bad request error if more than
+	// 5000 cards match the rest of the query.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the query options
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/QueryCardsOptions"
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of cards to return per page(default=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CardQueryResult"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	query := r.URL.Query()
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to query cards"))
+		return
+	}
+
+	if strPage == "" {
+		strPage = defaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = defaultPerPage
+	}
+
+	page, err := strconv.Atoi(strPage)
+	if err != nil {
+		message := fmt.Sprintf("invalid `page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var opts model.QueryCardsOptions
+	if len(requestBody) > 0 {
+		if err = json.Unmarshal(requestBody, &opts); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+	opts.Page = page
+	opts.PerPage = perPage
+
+	auditRec := a.makeAuditRecord(r, "queryCards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("viewID", opts.ViewID)
+	auditRec.AddMeta("page", page)
+	auditRec.AddMeta("per_page", perPage)
+
+	result, err := a.app.QueryCardsForBoard(boardID, userID, opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.ResolveCardRelations(userID, boardID, result.Cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(result.Cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(result.Cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("QueryCards",
+		mlog.String("boardID", boardID),
+		mlog.String("userID", userID),
+		mlog.Int("page", page),
+		mlog.Int("per_page", perPage),
+		mlog.Int("total", result.Total),
+		mlog.Int("count", len(result.Cards)),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
 func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation PATCH /cards/{cardID}/cards patchCard
 	//
@@ -252,13 +555,25 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 	//   description: Disables notifications (for bulk data patching)
 	//   required: false
 	//   type: bool
+	// - name: If-Match
+	//   in: header
+	//   description: ETag of the card the patch applies to, the patch fails with a conflict if it has been modified since
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
 	//     description: success
+	//     headers:
+	//       ETag:
+	//         type: string
 	//     schema:
 	//       $ref: '#/definitions/Card'
+	//   '409':
+	//     description: the card has been modified since the expected version
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
 	//   default:
 	//     description: internal error
 	//     schema:
@@ -276,7 +591,7 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	card, err := a.app.GetCardByID(cardID)
+	card, err := a.app.GetCardByID(cardID, userID)
 	if err != nil {
 		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
 		a.errorResponse(w, r, model.NewErrBadRequest(message))
@@ -294,6 +609,15 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedUpdateAt, err := expectedUpdateAtFromRequest(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if expectedUpdateAt != nil {
+		patch.ExpectedUpdateAt = expectedUpdateAt
+	}
+
 	auditRec := a.makeAuditRecord(r, "patchCard", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
 	auditRec.AddMeta("boardID", card.BoardID)
@@ -305,6 +629,18 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 		a.errorResponse(w, r, err)
 		return
 	}
+	if err = a.app.ResolveCardRelations(userID, cardPatched.BoardID, cardPatched); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(cardPatched); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(cardPatched); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
 
 	a.logger.Debug("PatchCard",
 		mlog.String("boardID", cardPatched.BoardID),
@@ -319,6 +655,7 @@ func (a *API) handlePatchCard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// response
+	setETagHeader(w, cardPatched.UpdateAt)
 	jsonBytesResponse(w, http.StatusOK, data)
 
 	auditRec.Success()
@@ -353,7 +690,7 @@ func (a *API) handleGetCard(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 	cardID := mux.Vars(r)["cardID"]
 
-	card, err := a.app.GetCardByID(cardID)
+	card, err := a.app.GetCardByID(cardID, userID)
 	if err != nil {
 		message := fmt.Sprintf("could not fetch card %s: %s", cardID, err)
 		a.errorResponse(w, r, model.NewErrBadRequest(message))
@@ -370,6 +707,19 @@ func (a *API) handleGetCard(w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("boardID", card.BoardID)
 	auditRec.AddMeta("cardID", card.ID)
 
+	if err = a.app.ResolveCardRelations(userID, card.BoardID, card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(card); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	a.logger.Debug("GetCard",
 		mlog.String("boardID", card.BoardID),
 		mlog.String("cardID", card.ID),
@@ -383,6 +733,116 @@ func (a *API) handleGetCard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// response
+	setETagHeader(w, card.UpdateAt)
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleGetCardActivity(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /cards/{cardID}/activity getCardActivity
+	//
+	// Fetches the activity timeline of the specified card, newest first. It
+	// lists the changes of the card title and properties, and of its content
+	// blocks and comments.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cardID
+	//   in: path
+	//   description: Card ID
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of entries to return per page(default=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/CardActivity"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	cardID := mux.Vars(r)["cardID"]
+
+	query := r.URL.Query()
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	if strPage == "" {
+		strPage = defaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = defaultPerPage
+	}
+
+	page, err := strconv.Atoi(strPage)
+	if err != nil {
+		message := fmt.Sprintf("invalid `page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", err)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+
+	card, err := a.app.GetCardByID(cardID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch card activity"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardActivity", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", card.BoardID)
+	auditRec.AddMeta("cardID", card.ID)
+
+	activity, err := a.app.GetCardActivity(card.ID, userID, page, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetCardActivity",
+		mlog.String("boardID", card.BoardID),
+		mlog.String("cardID", card.ID),
+		mlog.Int("page", page),
+		mlog.Int("per_page", perPage),
+		mlog.Int("count", len(activity)),
+	)
+
+	data, err := json.Marshal(activity)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	jsonBytesResponse(w, http.StatusOK, data)
 
 	auditRec.Success()
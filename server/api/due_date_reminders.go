@@ -0,0 +1,289 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerDueDateRemindersRoutes(r *mux.Router) {
+	// Due date reminders APIs
+	r.HandleFunc("/boards/{boardID}/due-date-reminders", a.sessionRequired(a.handleGetDueDateReminderSettings)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/due-date-reminders", a.sessionRequired(a.handleSaveDueDateReminderSettings)).Methods("PUT")
+	r.HandleFunc("/boards/{boardID}/due-date-reminders", a.sessionRequired(a.handleDeleteDueDateReminderSettings)).Methods("DELETE")
+	r.HandleFunc("/boards/{boardID}/cards/overdue", a.sessionRequired(a.handleGetOverdueCards)).Methods("GET")
+}
+
+func (a *API) handleGetDueDateReminderSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/due-date-reminders getDueDateReminderSettings
+	//
+	// Returns the due date reminder settings of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DueDateReminderSettings"
+	//   '404':
+	//     description: the board has no due date reminders
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getDueDateReminderSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	settings, err := a.app.GetDueDateReminderSettings(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleSaveDueDateReminderSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /boards/{boardID}/due-date-reminders saveDueDateReminderSettings
+	//
+	// Creates or replaces the due date reminder settings of a board. The
+	// assignees of the cards are reminded at the given hour of their own
+	// time zone, the given numbers of days before the due date.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the due date reminder settings
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/DueDateReminderSettings"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DueDateReminderSettings"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var settings model.DueDateReminderSettings
+	if err = json.Unmarshal(requestBody, &settings); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	settings.BoardID = boardID
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify due date reminders"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "saveDueDateReminderSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("datePropertyID", settings.DatePropertyID)
+	auditRec.AddMeta("enabled", settings.Enabled)
+
+	saved, err := a.app.SaveDueDateReminderSettings(&settings, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("SaveDueDateReminderSettings",
+		mlog.String("boardID", boardID),
+		mlog.Bool("enabled", saved.Enabled),
+	)
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteDueDateReminderSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/due-date-reminders deleteDueDateReminderSettings
+	//
+	// Deletes the due date reminder settings of a board, which stops its
+	// reminders.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardProperties) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify due date reminders"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteDueDateReminderSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	if err := a.app.DeleteDueDateReminderSettings(boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteDueDateReminderSettings", mlog.String("boardID", boardID))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleGetOverdueCards(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/cards/overdue getOverdueCards
+	//
+	// Returns the cards of a board that are past their due date and aren't
+	// done, earliest due first. Days are over at midnight in the time zone
+	// of the user.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: property
+	//   in: query
+	//   description: The ID of the date property holding the due dates, the one of the due date reminders by default
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Card"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+	propertyID := r.URL.Query().Get("property")
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to fetch cards"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getOverdueCards", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("propertyID", propertyID)
+
+	cards, err := a.app.GetOverdueCards(boardID, propertyID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.ResolveCardRelations(userID, boardID, cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsBlocked(cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err = a.app.SetCardsChecklist(cards...); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetOverdueCards",
+		mlog.String("boardID", boardID),
+		mlog.Int("cardCount", len(cards)),
+	)
+
+	data, err := json.Marshal(cards)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
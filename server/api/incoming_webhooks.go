@@ -0,0 +1,464 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// HeaderIncomingWebhookToken is the header holding the token of an
+	// incoming webhook.
+	HeaderIncomingWebhookToken = "X-Focalboard-Token"
+)
+
+func (a *API) registerIncomingWebhooksRoutes(r *mux.Router) {
+	// Incoming webhooks APIs
+	r.HandleFunc("/boards/{boardID}/incoming-webhooks", a.sessionRequired(a.handleGetIncomingWebhooks)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/incoming-webhooks", a.sessionRequired(a.handleCreateIncomingWebhook)).Methods("POST")
+	r.HandleFunc("/incoming-webhooks/{webhookID}", a.sessionRequired(a.handleGetIncomingWebhook)).Methods("GET")
+	r.HandleFunc("/incoming-webhooks/{webhookID}", a.sessionRequired(a.handlePatchIncomingWebhook)).Methods("PATCH")
+	r.HandleFunc("/incoming-webhooks/{webhookID}", a.sessionRequired(a.handleDeleteIncomingWebhook)).Methods("DELETE")
+}
+
+// registerIncomingWebhookHooksRoutes registers the URLs the other systems
+// post to, which are authenticated by the token of the webhook rather than
+// a session and don't require the CSRF header.
+func (a *API) registerIncomingWebhookHooksRoutes(r *mux.Router) {
+	r.HandleFunc("/{webhookID}", a.handleExecuteIncomingWebhook).Methods("POST")
+}
+
+func (a *API) handleGetIncomingWebhooks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/incoming-webhooks getIncomingWebhooks
+	//
+	// Returns the incoming webhooks of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/IncomingWebhook"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board incoming webhooks"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getIncomingWebhooks", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	webhooks, err := a.app.GetIncomingWebhooksForBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetIncomingWebhooks",
+		mlog.String("boardID", boardID),
+		mlog.Int("webhookCount", len(webhooks)),
+	)
+
+	for _, webhook := range webhooks {
+		webhook.Sanitize()
+	}
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/incoming-webhooks createIncomingWebhook
+	//
+	// Creates an incoming webhook that creates or updates the cards of a
+	// board, as the user, from the payloads posted to /api/v2/hooks/{webhookID}.
+	// The token the requests must send is only returned in the response.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the incoming webhook to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/IncomingWebhook"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/IncomingWebhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var webhook model.IncomingWebhook
+	if err = json.Unmarshal(requestBody, &webhook); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	webhook.BoardID = boardID
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create board incoming webhooks"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	created, err := a.app.CreateIncomingWebhook(&webhook, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateIncomingWebhook",
+		mlog.String("boardID", boardID),
+		mlog.String("webhookID", created.ID),
+	)
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("webhookID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handleGetIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /incoming-webhooks/{webhookID} getIncomingWebhook
+	//
+	// Returns an incoming webhook.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Incoming webhook ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/IncomingWebhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+
+	webhook, err := a.getIncomingWebhookToManage(userID, webhookID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	a.logger.Debug("GetIncomingWebhook", mlog.String("webhookID", webhookID))
+
+	webhook.Sanitize()
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /incoming-webhooks/{webhookID} patchIncomingWebhook
+	//
+	// Changes the name or the mapping of an incoming webhook, or disables
+	// it.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Incoming webhook ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the incoming webhook patch
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/IncomingWebhookPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/IncomingWebhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.IncomingWebhookPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	webhook, err := a.getIncomingWebhookToManage(userID, webhookID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	patched, err := a.app.PatchIncomingWebhook(webhook, &patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PatchIncomingWebhook",
+		mlog.String("webhookID", webhookID),
+		mlog.Bool("disabled", patched.Disabled),
+	)
+
+	patched.Sanitize()
+	data, err := json.Marshal(patched)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /incoming-webhooks/{webhookID} deleteIncomingWebhook
+	//
+	// Deletes an incoming webhook.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Incoming webhook ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+
+	if _, err := a.getIncomingWebhookToManage(userID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	if err := a.app.DeleteIncomingWebhook(webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteIncomingWebhook", mlog.String("webhookID", webhookID))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleExecuteIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /hooks/{webhookID} executeIncomingWebhook
+	//
+	// Creates or updates a card of the board of an incoming webhook from a
+	// JSON payload. The request is authenticated by the token of the
+	// webhook, sent in the X-Focalboard-Token header.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Incoming webhook ID
+	//   required: true
+	//   type: string
+	// - name: X-Focalboard-Token
+	//   in: header
+	//   description: The token of the webhook
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the JSON payload
+	//   required: true
+	//   schema:
+	//     type: object
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/IncomingWebhookResult'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	webhookID := mux.Vars(r)["webhookID"]
+	token := r.Header.Get(HeaderIncomingWebhookToken)
+
+	webhook, err := a.app.GetIncomingWebhook(webhookID)
+	if err != nil && !model.IsErrNotFound(err) {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if webhook == nil || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(webhook.Token)) != 1 {
+		a.errorResponse(w, r, model.NewErrUnauthorized("invalid incoming webhook or token"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, model.IncomingWebhookPayloadMaxSize)
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			a.errorResponse(w, r, model.ErrRequestEntityTooLarge)
+			return
+		}
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "executeIncomingWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+	auditRec.AddMeta("boardID", webhook.BoardID)
+
+	result, err := a.app.ExecuteIncomingWebhook(webhook, payload)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("ExecuteIncomingWebhook",
+		mlog.String("webhookID", webhookID),
+		mlog.String("cardID", result.CardID),
+		mlog.Bool("created", result.Created),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("cardID", result.CardID)
+	auditRec.Success()
+}
+
+// getIncomingWebhookToManage returns an incoming webhook of a board the
+// user can share.
+func (a *API) getIncomingWebhookToManage(userID, webhookID string) (*model.IncomingWebhook, error) {
+	webhook, err := a.app.GetIncomingWebhook(webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !a.permissions.HasPermissionToBoard(userID, webhook.BoardID, model.PermissionShareBoard) {
+		return nil, model.NewErrPermission("access denied to incoming webhook")
+	}
+	return webhook, nil
+}
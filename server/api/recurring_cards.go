@@ -0,0 +1,334 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerRecurringCardsRoutes(r *mux.Router) {
+	// Recurring card rules APIs
+	r.HandleFunc("/boards/{boardID}/recurring-cards", a.sessionRequired(a.handleGetRecurringCardRules)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/recurring-cards", a.sessionRequired(a.handleCreateRecurringCardRule)).Methods("POST")
+	r.HandleFunc("/boards/{boardID}/recurring-cards/{ruleID}", a.sessionRequired(a.handlePatchRecurringCardRule)).Methods("PATCH")
+	r.HandleFunc("/boards/{boardID}/recurring-cards/{ruleID}", a.sessionRequired(a.handleDeleteRecurringCardRule)).Methods("DELETE")
+}
+
+func (a *API) handleGetRecurringCardRules(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/recurring-cards getRecurringCardRules
+	//
+	// Returns the recurring card rules of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/RecurringCardRule"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getRecurringCardRules", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	rules, err := a.app.GetRecurringCardRules(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetRecurringCardRules",
+		mlog.String("boardID", boardID),
+		mlog.Int("ruleCount", len(rules)),
+	)
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateRecurringCardRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/recurring-cards createRecurringCardRule
+	//
+	// Creates a rule that copies a card template of the board into a new
+	// card following a cron-like schedule. The cards are created by the
+	// user that creates the rule.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the recurring card rule to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RecurringCardRule"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/RecurringCardRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var rule model.RecurringCardRule
+	if err = json.Unmarshal(requestBody, &rule); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	rule.BoardID = boardID
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create recurring cards"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createRecurringCardRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("templateID", rule.TemplateID)
+	auditRec.AddMeta("schedule", rule.Schedule)
+
+	created, err := a.app.CreateRecurringCardRule(&rule, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateRecurringCardRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", created.ID),
+	)
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("ruleID", created.ID)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchRecurringCardRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /boards/{boardID}/recurring-cards/{ruleID} patchRecurringCardRule
+	//
+	// Changes a recurring card rule, e.g. to pause or resume it. A resumed
+	// rule doesn't create the cards it missed while paused.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Recurring card rule ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the recurring card rule patch
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RecurringCardRulePatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/RecurringCardRule'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.RecurringCardRulePatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify recurring cards"))
+		return
+	}
+
+	rule, err := a.getRecurringCardRuleOfBoard(boardID, ruleID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchRecurringCardRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	patched, err := a.app.PatchRecurringCardRule(rule, &patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PatchRecurringCardRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+		mlog.Bool("paused", patched.Paused),
+	)
+
+	data, err := json.Marshal(patched)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteRecurringCardRule(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /boards/{boardID}/recurring-cards/{ruleID} deleteRecurringCardRule
+	//
+	// Deletes a recurring card rule. The cards it created are kept.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: ruleID
+	//   in: path
+	//   description: Recurring card rule ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	boardID := vars["boardID"]
+	ruleID := vars["ruleID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardCards) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to delete recurring cards"))
+		return
+	}
+
+	if _, err := a.getRecurringCardRuleOfBoard(boardID, ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteRecurringCardRule", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("ruleID", ruleID)
+
+	if err := a.app.DeleteRecurringCardRule(ruleID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteRecurringCardRule",
+		mlog.String("boardID", boardID),
+		mlog.String("ruleID", ruleID),
+	)
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+// getRecurringCardRuleOfBoard returns a rule, or a not found error if it
+// belongs to another board than the one of the request.
+func (a *API) getRecurringCardRuleOfBoard(boardID, ruleID string) (*model.RecurringCardRule, error) {
+	rule, err := a.app.GetRecurringCardRule(ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.BoardID != boardID {
+		return nil, model.NewErrNotFound("recurring card rule ID=" + ruleID)
+	}
+	return rule, nil
+}
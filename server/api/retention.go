@@ -0,0 +1,375 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *API) registerRetentionRoutes(r *mux.Router) {
+	// Data retention APIs
+	r.HandleFunc("/admin/retention/teams/{teamID}/policies", a.sessionRequired(a.handleGetRetentionPolicies)).Methods("GET")
+	r.HandleFunc("/admin/retention/teams/{teamID}/policy", a.sessionRequired(a.handleSaveTeamRetentionPolicy)).Methods("PUT")
+	r.HandleFunc("/admin/retention/teams/{teamID}/policy", a.sessionRequired(a.handleDeleteTeamRetentionPolicy)).Methods("DELETE")
+	r.HandleFunc("/admin/retention/boards/{boardID}/policy", a.sessionRequired(a.handleSaveBoardRetentionPolicy)).Methods("PUT")
+	r.HandleFunc("/admin/retention/boards/{boardID}/policy", a.sessionRequired(a.handleDeleteBoardRetentionPolicy)).Methods("DELETE")
+	r.HandleFunc("/admin/retention/run", a.sessionRequired(a.handleRunDataRetention)).Methods("POST")
+}
+
+func (a *API) handleGetRetentionPolicies(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/retention/teams/{teamID}/policies getRetentionPolicies
+	//
+	// Returns the default retention policy of a team, if it has one,
+	// followed by the retention policies of its boards.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/RetentionPolicy"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to retention policies"))
+		return
+	}
+
+	policies, err := a.app.GetRetentionPolicies(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(policies)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetRetentionPolicies",
+		mlog.String("teamID", teamID),
+		mlog.Int("policiesCount", len(policies)),
+	)
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleSaveTeamRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /admin/retention/teams/{teamID}/policy saveTeamRetentionPolicy
+	//
+	// Creates or replaces the default retention policy of a team.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the retention policy, only retentionDays is used
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RetentionPolicy"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/RetentionPolicy"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	a.saveRetentionPolicy(w, r, teamID, "")
+}
+
+func (a *API) handleSaveBoardRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /admin/retention/boards/{boardID}/policy saveBoardRetentionPolicy
+	//
+	// Creates or replaces the retention policy of a board, that overrides
+	// the one of its team. A board with a legal hold is never deleted by
+	// the data retention.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the retention policy, only retentionDays and legalHold are used
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RetentionPolicy"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/RetentionPolicy"
+	//   '404':
+	//     description: board not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	a.saveRetentionPolicy(w, r, "", boardID)
+}
+
+func (a *API) saveRetentionPolicy(w http.ResponseWriter, r *http.Request, teamID, boardID string) {
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to retention policies"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var policy *model.RetentionPolicy
+	if err = json.Unmarshal(requestBody, &policy); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+	if policy == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid retention policy"))
+		return
+	}
+
+	policy.TeamID = teamID
+	policy.BoardID = boardID
+	policy.ModifiedBy = userID
+
+	auditRec := a.makeAuditRecord(r, "saveRetentionPolicy", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("retentionDays", policy.RetentionDays)
+	auditRec.AddMeta("legalHold", policy.LegalHold)
+
+	policy, err = a.app.SaveRetentionPolicy(policy)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("SaveRetentionPolicy",
+		mlog.String("teamID", policy.TeamID),
+		mlog.String("boardID", boardID),
+	)
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteTeamRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /admin/retention/teams/{teamID}/policy deleteTeamRetentionPolicy
+	//
+	// Deletes the default retention policy of a team, so its boards follow
+	// the global data retention again.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: retention policy not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	teamID := mux.Vars(r)["teamID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to retention policies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteTeamRetentionPolicy", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	if err := a.app.DeleteTeamRetentionPolicy(teamID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteTeamRetentionPolicy", mlog.String("teamID", teamID))
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteBoardRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /admin/retention/boards/{boardID}/policy deleteBoardRetentionPolicy
+	//
+	// Deletes the retention policy of a board, so it follows the policy of
+	// its team again.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: board or retention policy not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to retention policies"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteBoardRetentionPolicy", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	if err := a.app.DeleteBoardRetentionPolicy(boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteBoardRetentionPolicy", mlog.String("boardID", boardID))
+	jsonStringResponse(w, http.StatusOK, "{}")
+
+	auditRec.Success()
+}
+
+func (a *API) handleRunDataRetention(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /admin/retention/run runDataRetention
+	//
+	// Runs the data retention now, and returns what was deleted for each
+	// board.
+	//
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/DataRetentionReport"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionTo(userID, mm_model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("access denied to run data retention"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "runDataRetention", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	report, err := a.app.RunDataRetention()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	auditRec.AddMeta("deletedBoards", len(report.Boards))
+	auditRec.AddMeta("deletedRows", report.TotalDeleted)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RunDataRetention", mlog.Int("deletedBoards", len(report.Boards)))
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
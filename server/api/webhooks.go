@@ -0,0 +1,709 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	webhookDeliveriesDefaultPerPage = 50
+	webhookDeliveriesMaxPerPage     = 200
+)
+
+func (a *API) registerWebhooksRoutes(r *mux.Router) {
+	// Outgoing webhooks APIs
+	r.HandleFunc("/boards/{boardID}/webhooks", a.sessionRequired(a.handleGetBoardWebhooks)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/webhooks", a.sessionRequired(a.handleCreateBoardWebhook)).Methods("POST")
+	r.HandleFunc("/teams/{teamID}/webhooks", a.sessionRequired(a.handleGetTeamWebhooks)).Methods("GET")
+	r.HandleFunc("/teams/{teamID}/webhooks", a.sessionRequired(a.handleCreateTeamWebhook)).Methods("POST")
+	r.HandleFunc("/webhooks/{webhookID}", a.sessionRequired(a.handleGetWebhook)).Methods("GET")
+	r.HandleFunc("/webhooks/{webhookID}", a.sessionRequired(a.handlePatchWebhook)).Methods("PATCH")
+	r.HandleFunc("/webhooks/{webhookID}", a.sessionRequired(a.handleDeleteWebhook)).Methods("DELETE")
+	r.HandleFunc("/webhooks/{webhookID}/deliveries", a.sessionRequired(a.handleGetWebhookDeliveries)).Methods("GET")
+	r.HandleFunc("/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver", a.sessionRequired(a.handleRedeliverWebhookDelivery)).Methods("POST")
+}
+
+func (a *API) handleGetBoardWebhooks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/webhooks getBoardWebhooks
+	//
+	// Returns the outgoing webhooks of a board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Webhook"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board webhooks"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardWebhooks", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	webhooks, err := a.app.GetWebhooksForBoard(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardWebhooks",
+		mlog.String("boardID", boardID),
+		mlog.Int("webhookCount", len(webhooks)),
+	)
+
+	for _, webhook := range webhooks {
+		webhook.Sanitize()
+	}
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateBoardWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /boards/{boardID}/webhooks createBoardWebhook
+	//
+	// Creates an outgoing webhook that receives the events of a board. The
+	// secret the requests are signed with is only returned in the
+	// response.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the webhook to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/Webhook"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/Webhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	boardID := mux.Vars(r)["boardID"]
+
+	webhook, err := readWebhook(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	webhook.BoardID = boardID
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionShareBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create board webhooks"))
+		return
+	}
+
+	a.createWebhook(w, r, webhook, userID)
+}
+
+func (a *API) handleGetTeamWebhooks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/webhooks getTeamWebhooks
+	//
+	// Returns the outgoing webhooks of a team that receive the events of
+	// all its boards. Only team admins can manage them.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/Webhook"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team webhooks"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getTeamWebhooks", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	webhooks, err := a.app.GetWebhooksForTeam(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetTeamWebhooks",
+		mlog.String("teamID", teamID),
+		mlog.Int("webhookCount", len(webhooks)),
+	)
+
+	for _, webhook := range webhooks {
+		webhook.Sanitize()
+	}
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateTeamWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /teams/{teamID}/webhooks createTeamWebhook
+	//
+	// Creates an outgoing webhook that receives the events of the boards
+	// of a team. It only receives the events of the boards its creator can
+	// view. Only team admins can create them. The secret the requests are
+	// signed with is only returned in the response.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the webhook to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/Webhook"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/Webhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	webhook, err := readWebhook(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	webhook.TeamID = teamID
+	webhook.BoardID = ""
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create team webhooks"))
+		return
+	}
+
+	a.createWebhook(w, r, webhook, userID)
+}
+
+func (a *API) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /webhooks/{webhookID} getWebhook
+	//
+	// Returns an outgoing webhook.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/Webhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+
+	webhook, err := a.getWebhookToManage(userID, webhookID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	a.logger.Debug("GetWebhook", mlog.String("webhookID", webhookID))
+
+	webhook.Sanitize()
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handlePatchWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PATCH /webhooks/{webhookID} patchWebhook
+	//
+	// Changes the URL or the events of an outgoing webhook, or disables
+	// it. The pending deliveries of a disabled webhook fail.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the webhook patch
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/WebhookPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/Webhook'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch model.WebhookPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	webhook, err := a.getWebhookToManage(userID, webhookID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "patchWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	patched, err := a.app.PatchWebhook(webhook, &patch, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("PatchWebhook",
+		mlog.String("webhookID", webhookID),
+		mlog.Bool("disabled", patched.Disabled),
+	)
+
+	patched.Sanitize()
+	data, err := json.Marshal(patched)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /webhooks/{webhookID} deleteWebhook
+	//
+	// Deletes an outgoing webhook and its delivery log.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+
+	if _, err := a.getWebhookToManage(userID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "deleteWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+
+	if err := a.app.DeleteWebhook(webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("DeleteWebhook", mlog.String("webhookID", webhookID))
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /webhooks/{webhookID}/deliveries getWebhookDeliveries
+	//
+	// Returns the delivery log of an outgoing webhook, newest first. The
+	// bodies of the responses are only returned to system admins.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: The status of the deliveries, pending, success or failed
+	//   required: false
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of deliveries to return per page (default=50, max=200)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/WebhookDelivery"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	webhookID := mux.Vars(r)["webhookID"]
+	query := r.URL.Query()
+
+	opts := model.QueryWebhookDeliveriesOptions{
+		WebhookID: webhookID,
+		Status:    query.Get("status"),
+		PerPage:   webhookDeliveriesDefaultPerPage,
+	}
+	if opts.Status != "" && !model.IsValidWebhookDeliveryStatus(opts.Status) {
+		a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `status` parameter: %s", opts.Status)))
+		return
+	}
+	if strPage := query.Get("page"); strPage != "" {
+		var err error
+		if opts.Page, err = strconv.Atoi(strPage); err != nil || opts.Page < 0 {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `page` parameter: %s", strPage)))
+			return
+		}
+	}
+	if strPerPage := query.Get("per_page"); strPerPage != "" {
+		var err error
+		if opts.PerPage, err = strconv.Atoi(strPerPage); err != nil || opts.PerPage <= 0 || opts.PerPage > webhookDeliveriesMaxPerPage {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)))
+			return
+		}
+	}
+
+	if _, err := a.getWebhookToManage(userID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getWebhookDeliveries", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+	auditRec.AddMeta("status", opts.Status)
+	auditRec.AddMeta("page", opts.Page)
+	auditRec.AddMeta("per_page", opts.PerPage)
+
+	deliveries, err := a.app.GetWebhookDeliveries(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetWebhookDeliveries",
+		mlog.String("webhookID", webhookID),
+		mlog.Int("deliveryCount", len(deliveries)),
+	)
+
+	if !a.permissions.HasPermissionTo(userID, model.PermissionManageSystem) {
+		for _, delivery := range deliveries {
+			delivery.Sanitize()
+		}
+	}
+
+	data, err := json.Marshal(deliveries)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleRedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /webhooks/{webhookID}/deliveries/{deliveryID}/redeliver redeliverWebhookDelivery
+	//
+	// Sends the payload of a delivery again as a new delivery. The new
+	// delivery is attempted right away and retried if it fails.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: webhookID
+	//   in: path
+	//   description: Webhook ID
+	//   required: true
+	//   type: string
+	// - name: deliveryID
+	//   in: path
+	//   description: Delivery ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       $ref: '#/definitions/WebhookDelivery'
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	vars := mux.Vars(r)
+	webhookID := vars["webhookID"]
+	deliveryID := vars["deliveryID"]
+
+	if _, err := a.getWebhookToManage(userID, webhookID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	delivery, err := a.app.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if delivery.WebhookID != webhookID {
+		a.errorResponse(w, r, model.NewErrNotFound("webhook delivery ID="+deliveryID))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "redeliverWebhookDelivery", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("webhookID", webhookID)
+	auditRec.AddMeta("deliveryID", deliveryID)
+
+	redelivery, err := a.app.RedeliverWebhookDelivery(delivery)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("RedeliverWebhookDelivery",
+		mlog.String("webhookID", webhookID),
+		mlog.String("deliveryID", deliveryID),
+		mlog.String("status", redelivery.Status),
+	)
+
+	if !a.permissions.HasPermissionTo(userID, model.PermissionManageSystem) {
+		redelivery.Sanitize()
+	}
+
+	data, err := json.Marshal(redelivery)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("redeliveryID", redelivery.ID)
+	auditRec.Success()
+}
+
+// createWebhook creates a webhook once the permissions to create it were
+// checked, and returns it with its secret.
+func (a *API) createWebhook(w http.ResponseWriter, r *http.Request, webhook *model.Webhook, userID string) {
+	auditRec := a.makeAuditRecord(r, "createWebhook", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", webhook.TeamID)
+	auditRec.AddMeta("boardID", webhook.BoardID)
+	auditRec.AddMeta("eventTypes", webhook.EventTypes)
+
+	created, err := a.app.CreateWebhook(webhook, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateWebhook",
+		mlog.String("teamID", created.TeamID),
+		mlog.String("boardID", created.BoardID),
+		mlog.String("webhookID", created.ID),
+	)
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("webhookID", created.ID)
+	auditRec.Success()
+}
+
+// getWebhookToManage returns a webhook the user can manage: the webhooks
+// of the boards they can share, and the webhooks of the teams they
+// administrate.
+func (a *API) getWebhookToManage(userID, webhookID string) (*model.Webhook, error) {
+	webhook, err := a.app.GetWebhook(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.BoardID != "" {
+		if !a.permissions.HasPermissionToBoard(userID, webhook.BoardID, model.PermissionShareBoard) {
+			return nil, model.NewErrPermission("access denied to webhook")
+		}
+		return webhook, nil
+	}
+
+	if !a.permissions.HasPermissionToTeam(userID, webhook.TeamID, model.PermissionManageTeam) {
+		return nil, model.NewErrPermission("access denied to webhook")
+	}
+	return webhook, nil
+}
+
+func readWebhook(r *http.Request) (*model.Webhook, error) {
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook model.Webhook
+	if err = json.Unmarshal(requestBody, &webhook); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	return &webhook, nil
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// automationExecutionLogRetention is how long the executions of the
+	// automation rules are kept.
+	automationExecutionLogRetention = 30 * 24 * time.Hour
+
+	// automationDueDateWindow is how long after a due date passes the due
+	// date rules can still run, so that a new rule doesn't run on all the
+	// cards already overdue.
+	automationDueDateWindow = 24 * time.Hour
+)
+
+func (a *App) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	return a.store.GetAutomationRule(ruleID)
+}
+
+func (a *App) GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error) {
+	return a.store.GetAutomationRulesForBoard(boardID)
+}
+
+// CreateAutomationRule creates an automation rule as the user, whose
+// actions are applied by the user.
+func (a *App) CreateAutomationRule(rule *model.AutomationRule, userID string) (*model.AutomationRule, error) {
+	rule.ID = ""
+	rule.CreatedBy = userID
+	rule.ModifiedBy = userID
+
+	if err := a.validateAutomationRule(rule); err != nil {
+		return nil, err
+	}
+	return a.store.InsertAutomationRule(rule)
+}
+
+func (a *App) PatchAutomationRule(rule *model.AutomationRule, patch *model.AutomationRulePatch, userID string) (*model.AutomationRule, error) {
+	patched := patch.Patch(rule)
+	patched.ModifiedBy = userID
+
+	if err := a.validateAutomationRule(patched); err != nil {
+		return nil, err
+	}
+	return a.store.UpdateAutomationRule(patched)
+}
+
+func (a *App) DeleteAutomationRule(ruleID string) error {
+	return a.store.DeleteAutomationRule(ruleID)
+}
+
+func (a *App) GetAutomationExecutions(opts model.QueryAutomationExecutionsOptions) ([]*model.AutomationExecution, error) {
+	return a.store.GetAutomationExecutions(opts)
+}
+
+func (a *App) LogAutomationExecution(execution *model.AutomationExecution) error {
+	_, err := a.store.InsertAutomationExecution(execution)
+	return err
+}
+
+// ExecuteAutomationRule applies the actions of a rule to a card if it
+// meets the conditions of the rule, as the creator of the rule, and logs
+// the execution. chain is the chain of rules that triggered each other up
+// to the event, nil if the event wasn't caused by a rule; the change of
+// the card continues it. It returns nil if the card doesn't meet the
+// conditions.
+func (a *App) ExecuteAutomationRule(rule *model.AutomationRule, board *model.Board, card *model.Block, chain *model.AutomationChain) (*model.AutomationExecution, error) {
+	// the card may have changed since the event
+	current, err := a.store.GetBlock(card.ID)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	currentCard, err := model.Block2Card(current)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Conditions != nil && !rule.Conditions.IsMet(currentCard, schema) {
+		return nil, nil
+	}
+
+	execution := &model.AutomationExecution{
+		RuleID:      rule.ID,
+		BoardID:     board.ID,
+		CardID:      card.ID,
+		TriggerType: rule.Trigger.Type,
+		Status:      model.AutomationExecutionSuccess,
+		Depth:       chain.Depth(),
+	}
+	if err := a.applyAutomationRule(rule, board, currentCard, schema, chain.Next(rule.ID)); err != nil {
+		a.logger.Warn("Automation rule failed",
+			mlog.String("rule_id", rule.ID),
+			mlog.String("card_id", card.ID),
+			mlog.Err(err),
+		)
+		execution.Status = model.AutomationExecutionFailed
+		execution.Error = err.Error()
+	}
+	return a.store.InsertAutomationExecution(execution)
+}
+
+// applyAutomationRule applies the actions of a rule to a card as the
+// creator of the rule: the property changes in a single patch, then the
+// comments. chain is the chain of rules the change belongs to.
+func (a *App) applyAutomationRule(rule *model.AutomationRule, board *model.Board, card *model.Card, schema model.PropSchema, chain *model.AutomationChain) error {
+	userID := rule.CreatedBy
+	if !a.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionManageBoardCards) {
+		return errors.New("the creator of the rule can't manage the cards of the board")
+	}
+	if err := rule.IsValidForSchema(schema); err != nil {
+		// the properties were changed after the rule was saved
+		return err
+	}
+
+	run, err := a.store.IncrementAutomationRuleRunCount(rule.ID)
+	if err != nil {
+		return err
+	}
+
+	ctx := model.PlaceholderContext{
+		Now:           time.Now().In(a.userLocation(userID)),
+		CreatorID:     userID,
+		BoardTitle:    board.Title,
+		RemoveUnknown: true,
+	}
+	if user, uErr := a.store.GetUserByID(userID); uErr == nil {
+		ctx.CreatorName = user.Username
+	} else {
+		a.logger.Debug("Unknown creator of automation rule", mlog.String("user_id", userID), mlog.Err(uErr))
+	}
+
+	result := rule.ApplyActions(card, ctx, schema, run)
+	if result.Changed {
+		// the patch replaces all the properties of the card
+		patch := &model.CardPatch{UpdatedProperties: result.Properties}
+		if _, err := a.patchCard(patch, card.ID, userID, false, chain); err != nil {
+			return err
+		}
+	}
+
+	for _, text := range result.Comments {
+		comment := &model.Block{
+			ID:        utils.NewID(utils.IDTypeBlock),
+			ParentID:  card.ID,
+			BoardID:   board.ID,
+			Type:      model.TypeComment,
+			Title:     text,
+			Fields:    map[string]interface{}{},
+			CreatedBy: userID,
+		}
+		if err := a.InsertBlockAndNotify(comment, userID, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDueDateAutomations triggers the due date rules of all the boards on
+// the cards whose due date passed, in the time zone of the creator of
+// each rule, and returns the number of rules triggered. A rule is
+// triggered once for each due date of a card.
+func (a *App) RunDueDateAutomations(now time.Time) (int, error) {
+	rules, err := a.store.GetEnabledAutomationRules(model.AutomationTriggerDueDatePassed)
+	if err != nil {
+		return 0, err
+	}
+
+	triggered := 0
+	for _, rule := range rules {
+		count, err := a.runDueDateAutomation(rule, now)
+		if err != nil {
+			a.logger.Error("Unable to run a due date automation rule",
+				mlog.String("rule_id", rule.ID),
+				mlog.String("board_id", rule.BoardID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		triggered += count
+	}
+	return triggered, nil
+}
+
+func (a *App) runDueDateAutomation(rule *model.AutomationRule, now time.Time) (int, error) {
+	board, err := a.store.GetBoard(rule.BoardID)
+	if err != nil {
+		return 0, err
+	}
+	if board.IsTemplate {
+		return 0, nil
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return 0, err
+	}
+	if err = rule.IsValidForSchema(schema); err != nil {
+		// the properties were changed after the rule was saved
+		return 0, err
+	}
+
+	blocks, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		return 0, err
+	}
+
+	now = now.In(a.userLocation(rule.CreatedBy))
+	triggered := 0
+	for _, card := range blocks {
+		due, ok := cardDueDate(card, rule.Trigger.PropertyID)
+		if !ok || isCardTemplate(card) || schema.IsCardDone(card) {
+			continue
+		}
+		overdueAt := due.OverdueAt(now.Location())
+		if now.Before(overdueAt) || !now.Before(overdueAt.Add(automationDueDateWindow)) {
+			continue
+		}
+
+		run := &model.AutomationDueDateRun{
+			RuleID:  rule.ID,
+			BoardID: board.ID,
+			CardID:  card.ID,
+			DueAt:   due.At,
+			RunAt:   utils.GetMillisForTime(now),
+		}
+		inserted, err := a.store.InsertAutomationDueDateRun(run)
+		if err != nil {
+			return triggered, err
+		}
+		if !inserted {
+			continue
+		}
+		a.notifyDueDatePassed(board, card, run)
+		triggered++
+	}
+	return triggered, nil
+}
+
+func (a *App) notifyDueDatePassed(board *model.Board, card *model.Block, run *model.AutomationDueDateRun) {
+	if a.notifications == nil {
+		return
+	}
+	a.notifications.BlockChanged(notify.BlockChangeEvent{
+		Action:        notify.DueDatePassed,
+		TeamID:        board.TeamID,
+		Board:         board,
+		Card:          card,
+		BlockChanged:  card,
+		ModifiedBy:    &model.BoardMember{BoardID: board.ID, UserID: model.SystemUserID},
+		DueDatePassed: run,
+	})
+}
+
+// CleanupAutomationExecutions deletes the executions older than the
+// retention of the execution log at a time in milliseconds since the
+// epoch, and returns the number of executions deleted.
+func (a *App) CleanupAutomationExecutions(now int64) (int64, error) {
+	return a.store.DeleteAutomationExecutionsBefore(now - automationExecutionLogRetention.Milliseconds())
+}
+
+// validateAutomationRule checks that a rule only uses properties of the
+// schema of its board.
+func (a *App) validateAutomationRule(rule *model.AutomationRule) error {
+	if err := rule.IsValid(); err != nil {
+		return err
+	}
+	board, err := a.store.GetBoard(rule.BoardID)
+	if err != nil {
+		return err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return model.NewErrBadRequest(err.Error())
+	}
+	return rule.IsValidForSchema(schema)
+}
@@ -49,8 +49,9 @@ func (a *App) DuplicateBlock(boardID string, blockID string, userID string, asTe
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		for _, block := range blocks {
-			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+			a.broadcastBlockChange(board.TeamID, board, block)
 		}
+		a.notifyChecklistChanges(board.TeamID, make([]*model.Block, len(blocks)), blocks, userID, true)
 		return nil
 	})
 
@@ -62,6 +63,12 @@ func (a *App) PatchBlock(blockID string, blockPatch *model.BlockPatch, modifiedB
 }
 
 func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch, modifiedByID string, disableNotify bool) (*model.Block, error) {
+	return a.patchBlockAndNotify(blockID, blockPatch, modifiedByID, disableNotify, nil)
+}
+
+// patchBlockAndNotify patches a block, as part of the chain of automation
+// rules that made the change if any.
+func (a *App) patchBlockAndNotify(blockID string, blockPatch *model.BlockPatch, modifiedByID string, disableNotify bool, chain *model.AutomationChain) (*model.Block, error) {
 	oldBlock, err := a.store.GetBlock(blockID)
 	if err != nil {
 		return nil, err
@@ -72,6 +79,13 @@ func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch,
 		return nil, err
 	}
 
+	if properties, ok := blockPatch.UpdatedFields["properties"].(map[string]interface{}); ok && oldBlock.Type == model.TypeCard {
+		oldProperties, _ := oldBlock.Fields["properties"].(map[string]interface{})
+		if err = a.validateCardProperties(board, oldProperties, properties, isCardTemplate(oldBlock), modifiedByID); err != nil {
+			return nil, err
+		}
+	}
+
 	err = a.store.PatchBlock(blockID, blockPatch, modifiedByID)
 	if err != nil {
 		return nil, err
@@ -82,17 +96,19 @@ func (a *App) PatchBlockAndNotify(blockID string, blockPatch *model.BlockPatch,
 	if err != nil {
 		return nil, err
 	}
+	a.syncCardRelations(board, oldBlock, block, modifiedByID)
 	a.blockChangeNotifier.Enqueue(func() error {
 		// broadcast on websocket
-		a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+		a.broadcastBlockChange(board.TeamID, board, block)
 
 		// broadcast on webhooks
 		a.webhook.NotifyUpdate(block)
 
 		// send notifications
 		if !disableNotify {
-			a.notifyBlockChanged(notify.Update, block, oldBlock, modifiedByID)
+			a.notifyBlockChangedInChain(notify.Update, block, oldBlock, modifiedByID, chain)
 		}
+		a.notifyChecklistChanges(board.TeamID, []*model.Block{oldBlock}, []*model.Block{block}, modifiedByID, disableNotify)
 		return nil
 	})
 	return block, nil
@@ -108,23 +124,32 @@ func (a *App) PatchBlocksAndNotify(teamID string, blockPatches *model.BlockPatch
 		return err
 	}
 
+	boards := map[string]*model.Board{}
+	if err := a.validatePatchedCardsProperties(oldBlocks, blockPatches, boards, modifiedByID); err != nil {
+		return err
+	}
+
 	if err := a.store.PatchBlocks(blockPatches, modifiedByID); err != nil {
 		return err
 	}
+	a.syncPatchedCardsRelations(boards, oldBlocks, blockPatches, modifiedByID)
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.metrics.IncrementBlocksPatched(len(oldBlocks))
+		newBlocks := make([]*model.Block, 0, len(blockPatches.BlockIDs))
 		for i, blockID := range blockPatches.BlockIDs {
 			newBlock, err := a.store.GetBlock(blockID)
 			if err != nil {
 				return err
 			}
-			a.wsAdapter.BroadcastBlockChange(teamID, newBlock)
+			newBlocks = append(newBlocks, newBlock)
+			a.broadcastBlockChange(teamID, boards[newBlock.BoardID], newBlock)
 			a.webhook.NotifyUpdate(newBlock)
 			if !disableNotify {
 				a.notifyBlockChanged(notify.Update, newBlock, oldBlocks[i], modifiedByID)
 			}
 		}
+		a.notifyChecklistChanges(teamID, oldBlocks, newBlocks, modifiedByID, disableNotify)
 		return nil
 	})
 	return nil
@@ -139,16 +164,21 @@ func (a *App) InsertBlockAndNotify(block *model.Block, modifiedByID string, disa
 	if bErr != nil {
 		return bErr
 	}
+	if err := a.validateInsertedCardsProperties(board, []*model.Block{block}, modifiedByID); err != nil {
+		return err
+	}
 
 	err := a.store.InsertBlock(block, modifiedByID)
 	if err == nil {
+		a.syncCardRelations(board, nil, block, modifiedByID)
 		a.blockChangeNotifier.Enqueue(func() error {
-			a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+			a.broadcastBlockChange(board.TeamID, board, block)
 			a.metrics.IncrementBlocksInserted(1)
 			a.webhook.NotifyUpdate(block)
 			if !disableNotify {
 				a.notifyBlockChanged(notify.Add, block, nil, modifiedByID)
 			}
+			a.notifyChecklistChanges(board.TeamID, []*model.Block{nil}, []*model.Block{block}, modifiedByID, disableNotify)
 			return nil
 		})
 	}
@@ -177,6 +207,9 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 	if err != nil {
 		return nil, err
 	}
+	if err = a.validateInsertedCardsProperties(board, blocks, modifiedByID); err != nil {
+		return nil, err
+	}
 
 	needsNotify := make([]*model.Block, 0, len(blocks))
 	for i := range blocks {
@@ -185,8 +218,9 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 			return nil, err
 		}
 		needsNotify = append(needsNotify, blocks[i])
+		a.syncCardRelations(board, nil, blocks[i], modifiedByID)
 
-		a.wsAdapter.BroadcastBlockChange(board.TeamID, blocks[i])
+		a.broadcastBlockChange(board.TeamID, board, blocks[i])
 		a.metrics.IncrementBlocksInserted(1)
 	}
 
@@ -198,12 +232,46 @@ func (a *App) InsertBlocksAndNotify(blocks []*model.Block, modifiedByID string,
 				a.notifyBlockChanged(notify.Add, block, nil, modifiedByID)
 			}
 		}
+		a.notifyChecklistChanges(board.TeamID, make([]*model.Block, len(needsNotify)), needsNotify, modifiedByID, disableNotify)
 		return nil
 	})
 
 	return blocks, nil
 }
 
+// ValidateBlockParents checks that the types of the blocks allow the types
+// of their parents, which are looked up in the board for the parents that
+// aren't part of the blocks. The parents that don't exist aren't checked.
+func (a *App) ValidateBlockParents(boardID string, blocks []*model.Block) error {
+	inBatch := make(map[string]bool, len(blocks))
+	for _, block := range blocks {
+		inBatch[block.ID] = true
+	}
+
+	parentIDs := []string{}
+	seen := map[string]bool{}
+	for _, block := range blocks {
+		if block.ParentID == "" || block.ParentID == boardID || inBatch[block.ParentID] || seen[block.ParentID] {
+			continue
+		}
+		seen[block.ParentID] = true
+		parentIDs = append(parentIDs, block.ParentID)
+	}
+
+	parentTypes := map[string]model.BlockType{}
+	if len(parentIDs) > 0 {
+		parents, err := a.store.GetBlocksByIDs(parentIDs)
+		if err != nil && !model.IsErrNotFound(err) {
+			return err
+		}
+		for _, parent := range parents {
+			parentTypes[parent.ID] = parent.Type
+		}
+	}
+
+	return model.ValidateBlockParents(blocks, parentTypes)
+}
+
 func (a *App) GetBlockByID(blockID string) (*model.Block, error) {
 	return a.store.GetBlock(blockID)
 }
@@ -232,6 +300,7 @@ func (a *App) DeleteBlockAndNotify(blockID string, modifiedBy string, disableNot
 	if err != nil {
 		return err
 	}
+	a.syncCardRelations(board, block, nil, modifiedBy)
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, block.BoardID)
@@ -239,6 +308,7 @@ func (a *App) DeleteBlockAndNotify(blockID string, modifiedBy string, disableNot
 		if !disableNotify {
 			a.notifyBlockChanged(notify.Delete, block, block, modifiedBy)
 		}
+		a.notifyChecklistChanges(board.TeamID, []*model.Block{block}, []*model.Block{nil}, modifiedBy, disableNotify)
 		return nil
 	})
 
@@ -285,12 +355,14 @@ func (a *App) UndeleteBlock(blockID string, modifiedBy string) (*model.Block, er
 	if err != nil {
 		return nil, err
 	}
+	a.syncCardRelations(board, nil, block, modifiedBy)
 
 	a.blockChangeNotifier.Enqueue(func() error {
-		a.wsAdapter.BroadcastBlockChange(board.TeamID, block)
+		a.broadcastBlockChange(board.TeamID, board, block)
 		a.metrics.IncrementBlocksInserted(1)
 		a.webhook.NotifyUpdate(block)
 		a.notifyBlockChanged(notify.Add, block, nil, modifiedBy)
+		a.notifyChecklistChanges(board.TeamID, []*model.Block{nil}, []*model.Block{block}, modifiedBy, false)
 
 		return nil
 	})
@@ -307,6 +379,12 @@ func (a *App) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
 }
 
 func (a *App) notifyBlockChanged(action notify.Action, block *model.Block, oldBlock *model.Block, modifiedByID string) {
+	a.notifyBlockChangedInChain(action, block, oldBlock, modifiedByID, nil)
+}
+
+// notifyBlockChangedInChain notifies a block change made by the chain of
+// automation rules, nil if the change wasn't made by a rule.
+func (a *App) notifyBlockChangedInChain(action notify.Action, block *model.Block, oldBlock *model.Block, modifiedByID string, chain *model.AutomationChain) {
 	// don't notify if notifications service disabled, or block change is generated via system user.
 	if a.notifications == nil || modifiedByID == model.SystemUserID {
 		return
@@ -329,15 +407,21 @@ func (a *App) notifyBlockChanged(action notify.Action, block *model.Block, oldBl
 	}
 
 	evt := notify.BlockChangeEvent{
-		Action:       action,
-		TeamID:       board.TeamID,
-		Board:        board,
-		Card:         card,
-		BlockChanged: block,
-		BlockOld:     oldBlock,
-		ModifiedBy:   boardMember,
+		Action:          action,
+		TeamID:          board.TeamID,
+		Board:           board,
+		Card:            card,
+		BlockChanged:    block,
+		BlockOld:        oldBlock,
+		ModifiedBy:      boardMember,
+		AutomationChain: chain,
 	}
 	a.notifications.BlockChanged(evt)
+	a.notifyWebhooksBlockChanged(action, board, card, block, oldBlock, modifiedByID)
+
+	if action == notify.Update {
+		a.notifyBlockerDone(board, block, oldBlock, modifiedByID)
+	}
 }
 
 const (
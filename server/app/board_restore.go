@@ -0,0 +1,183 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// RestoreBoardToTime brings a board, its cards, views and content back to
+// their state at the given time. The historical state is applied as a new
+// revision of every changed board and block, so the restore can itself be
+// reverted. When dryRun is true the changes are only computed.
+func (a *App) RestoreBoardToTime(boardID string, restoreAt int64, userID string, dryRun bool) (*model.BoardRestoreResult, error) {
+	opts := model.BoardRestoreOptions{RestoreAt: restoreAt, DryRun: dryRun}
+	if err := opts.IsValid(); err != nil {
+		return nil, err
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	boardRevisions, err := a.store.GetBoardHistory(boardID, model.QueryBoardHistoryOptions{
+		BeforeUpdateAt: restoreAt + 1,
+		Limit:          1,
+		Descending:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(boardRevisions) == 0 || boardRevisions[0].DeleteAt != 0 {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("board %s did not exist at %d", boardID, restoreAt))
+	}
+
+	blockRevisions, _, err := a.store.GetBlockHistoryNewestDescendants(boardID, model.QueryBlockHistoryChildOptions{
+		BeforeUpdateAt: restoreAt + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	currentBlocks, err := a.store.GetBlocksForBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the board fields are replaced and not modified in place, so a
+	// shallow copy keeps the current board untouched
+	restoredBoard := *board
+	boardChanged := model.RestoreBoardContent(&restoredBoard, boardRevisions[0])
+	blocks, deletedBlockIDs, changes := computeBlocksRestore(currentBlocks, blockRevisions)
+
+	result := &model.BoardRestoreResult{
+		BoardID:      boardID,
+		RestoreAt:    restoreAt,
+		DryRun:       dryRun,
+		BoardChanged: boardChanged,
+		Board:        &restoredBoard,
+		Changes:      changes,
+	}
+
+	if dryRun || (!boardChanged && len(changes) == 0) {
+		return result, nil
+	}
+
+	var boardToRestore *model.Board
+	if boardChanged {
+		boardToRestore = &restoredBoard
+	}
+
+	if err := a.store.RestoreBoardAndBlocks(boardToRestore, blocks, deletedBlockIDs, userID); err != nil {
+		return nil, err
+	}
+
+	result.Board, err = a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	restoredBlockIDs := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		restoredBlockIDs = append(restoredBlockIDs, block.ID)
+	}
+	restoredBlocks, err := a.store.GetBlocksByIDs(restoredBlockIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("Board restored to a previous time",
+		mlog.String("boardID", boardID),
+		mlog.Int("restoreAt", restoreAt),
+		mlog.Int("changes", len(changes)),
+		mlog.String("userID", userID),
+	)
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		if boardChanged {
+			a.wsAdapter.BroadcastBoardChange(board.TeamID, result.Board)
+		}
+
+		for _, block := range restoredBlocks {
+			a.broadcastBlockChange(board.TeamID, result.Board, block)
+			a.metrics.IncrementBlocksPatched(1)
+			a.webhook.NotifyUpdate(block)
+		}
+
+		for _, blockID := range deletedBlockIDs {
+			a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, boardID)
+			a.metrics.IncrementBlocksDeleted(1)
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+// computeBlocksRestore compares the current blocks of a board with the
+// newest revision of each block up to the restore time, and returns the
+// revisions to write back, the IDs of the blocks to delete and the list of
+// changes.
+func computeBlocksRestore(currentBlocks []*model.Block, revisions []*model.Block) ([]*model.Block, []string, []*model.BoardRestoreBlockChange) {
+	revisionsByID := make(map[string]*model.Block, len(revisions))
+	for _, revision := range revisions {
+		revisionsByID[revision.ID] = revision
+	}
+
+	currentByID := map[string]*model.Block{}
+	for _, block := range currentBlocks {
+		currentByID[block.ID] = block
+	}
+
+	blocks := []*model.Block{}
+	deletedBlockIDs := []string{}
+	changes := []*model.BoardRestoreBlockChange{}
+
+	for _, block := range currentBlocks {
+		revision, ok := revisionsByID[block.ID]
+		if !ok || revision.DeleteAt != 0 {
+			deletedBlockIDs = append(deletedBlockIDs, block.ID)
+			changes = append(changes, newBoardRestoreBlockChange(block, model.BoardRestoreActionDelete))
+			continue
+		}
+
+		if !model.BlockContentEquals(block, revision) {
+			blocks = append(blocks, revision)
+			changes = append(changes, newBoardRestoreBlockChange(revision, model.BoardRestoreActionUpdate))
+		}
+	}
+
+	for _, revision := range revisionsByID {
+		if _, ok := currentByID[revision.ID]; ok || revision.DeleteAt != 0 {
+			continue
+		}
+		blocks = append(blocks, revision)
+		changes = append(changes, newBoardRestoreBlockChange(revision, model.BoardRestoreActionRestore))
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return changes[i].Action < changes[j].Action
+		}
+		return changes[i].BlockID < changes[j].BlockID
+	})
+
+	return blocks, deletedBlockIDs, changes
+}
+
+func newBoardRestoreBlockChange(block *model.Block, action model.BoardRestoreAction) *model.BoardRestoreBlockChange {
+	return &model.BoardRestoreBlockChange{
+		BlockID:  block.ID,
+		ParentID: block.ParentID,
+		Type:     block.Type,
+		Title:    block.Title,
+		Action:   action,
+	}
+}
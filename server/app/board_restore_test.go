@@ -0,0 +1,122 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeBlocksRestore(t *testing.T) {
+	current := []*model.Block{
+		{ID: "unchanged", Title: "same", UpdateAt: 10},
+		{ID: "updated", Title: "new title", UpdateAt: 30},
+		{ID: "created", Title: "created later", UpdateAt: 30},
+		{ID: "recreated", Title: "deleted at the time", UpdateAt: 30},
+	}
+
+	// the newest revision of each block
+	revisions := []*model.Block{
+		{ID: "recreated", Title: "deleted at the time", UpdateAt: 20, DeleteAt: 20},
+		{ID: "deleted", Title: "deleted later", UpdateAt: 15},
+		{ID: "updated", Title: "old title", UpdateAt: 12},
+		{ID: "unchanged", Title: "same", UpdateAt: 10},
+	}
+
+	blocks, deletedBlockIDs, changes := computeBlocksRestore(current, revisions)
+
+	require.ElementsMatch(t, []string{"created", "recreated"}, deletedBlockIDs)
+
+	require.Len(t, blocks, 2)
+	titles := map[string]string{}
+	for _, block := range blocks {
+		titles[block.ID] = block.Title
+	}
+	require.Equal(t, map[string]string{"updated": "old title", "deleted": "deleted later"}, titles)
+
+	require.Len(t, changes, 4)
+	require.Equal(t, "created", changes[0].BlockID)
+	require.Equal(t, model.BoardRestoreActionDelete, changes[0].Action)
+	require.Equal(t, "recreated", changes[1].BlockID)
+	require.Equal(t, model.BoardRestoreActionDelete, changes[1].Action)
+	require.Equal(t, "deleted", changes[2].BlockID)
+	require.Equal(t, model.BoardRestoreActionRestore, changes[2].Action)
+	require.Equal(t, "updated", changes[3].BlockID)
+	require.Equal(t, model.BoardRestoreActionUpdate, changes[3].Action)
+	require.Equal(t, "old title", changes[3].Title)
+}
+
+func TestRestoreBoardToTime(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	boardID := "board-id"
+	userID := "user-id"
+	restoreAt := int64(1000)
+
+	board := &model.Board{ID: boardID, TeamID: "team-id", Title: "new title", UpdateAt: 2000}
+	boardRevision := &model.Board{ID: boardID, TeamID: "team-id", Title: "old title", UpdateAt: 900}
+	block := &model.Block{ID: "block-id", BoardID: boardID, Title: "new title", UpdateAt: 2000}
+	blockRevision := &model.Block{ID: "block-id", BoardID: boardID, Title: "old title", UpdateAt: 900}
+
+	expectHistory := func() {
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
+		th.Store.EXPECT().GetBoardHistory(boardID, model.QueryBoardHistoryOptions{
+			BeforeUpdateAt: restoreAt + 1,
+			Limit:          1,
+			Descending:     true,
+		}).Return([]*model.Board{boardRevision}, nil)
+		th.Store.EXPECT().GetBlockHistoryNewestDescendants(boardID, model.QueryBlockHistoryChildOptions{
+			BeforeUpdateAt: restoreAt + 1,
+		}).Return([]*model.Block{blockRevision}, false, nil)
+		th.Store.EXPECT().GetBlocksForBoard(boardID).Return([]*model.Block{block}, nil)
+	}
+
+	t.Run("invalid restore time", func(t *testing.T) {
+		result, err := th.App.RestoreBoardToTime(boardID, 0, userID, true)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, result)
+	})
+
+	t.Run("board did not exist at the restore time", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(boardID).Return(board, nil)
+		th.Store.EXPECT().GetBoardHistory(boardID, gomock.Any()).Return([]*model.Board{}, nil)
+
+		result, err := th.App.RestoreBoardToTime(boardID, restoreAt, userID, false)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, result)
+	})
+
+	t.Run("dry run doesn't change anything", func(t *testing.T) {
+		expectHistory()
+
+		result, err := th.App.RestoreBoardToTime(boardID, restoreAt, userID, true)
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.True(t, result.BoardChanged)
+		require.Equal(t, "old title", result.Board.Title)
+		require.Equal(t, "new title", board.Title)
+		require.Len(t, result.Changes, 1)
+		require.Equal(t, model.BoardRestoreActionUpdate, result.Changes[0].Action)
+	})
+
+	t.Run("restore applies the changes", func(t *testing.T) {
+		expectHistory()
+		th.Store.EXPECT().RestoreBoardAndBlocks(
+			gomock.AssignableToTypeOf(&model.Board{}),
+			[]*model.Block{blockRevision},
+			[]string{},
+			userID,
+		).Return(nil)
+		th.Store.EXPECT().GetBoard(boardID).Return(boardRevision, nil)
+		th.Store.EXPECT().GetBlocksByIDs([]string{"block-id"}).Return([]*model.Block{blockRevision}, nil)
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		result, err := th.App.RestoreBoardToTime(boardID, restoreAt, userID, false)
+		require.NoError(t, err)
+		require.False(t, result.DryRun)
+		require.Equal(t, "old title", result.Board.Title)
+		require.Len(t, result.Changes, 1)
+	})
+}
@@ -204,13 +204,15 @@ func (a *App) DuplicateBoard(boardID, userID, toTeam string, asTemplate bool) (*
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		teamID := ""
+		boards := make(map[string]*model.Board, len(bab.Boards))
 		for _, board := range bab.Boards {
 			teamID = board.TeamID
+			boards[board.ID] = board
 			a.wsAdapter.BroadcastBoardChange(teamID, board)
 		}
 		for _, block := range bab.Blocks {
 			blk := block
-			a.wsAdapter.BroadcastBlockChange(teamID, blk)
+			a.broadcastBlockChange(teamID, boards[blk.BoardID], blk)
 			a.notifyBlockChanged(notify.Add, blk, nil, userID)
 		}
 		for _, member := range members {
@@ -236,6 +238,13 @@ func (a *App) CreateBoard(board *model.Board, userID string, addMember bool) (*m
 	}
 	board.ID = utils.NewID(utils.IDTypeBoard)
 
+	if err := model.ValidateFormulas(board); err != nil {
+		return nil, err
+	}
+	if err := model.ValidateRollups(board); err != nil {
+		return nil, err
+	}
+
 	var newBoard *model.Board
 	var member *model.BoardMember
 	var err error
@@ -342,6 +351,12 @@ func (a *App) PatchBoard(patch *model.BoardPatch, boardID, userID string) (*mode
 		}
 	}
 
+	if len(patch.UpdatedCardProperties) != 0 || len(patch.DeletedCardProperties) != 0 {
+		if err := a.prepareCardPropertiesPatch(boardID, patch, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedBoard, err := a.store.PatchBoard(boardID, patch, userID)
 	if err != nil {
 		return nil, err
@@ -408,6 +423,34 @@ func (a *App) PatchBoard(patch *model.BoardPatch, boardID, userID string) (*mode
 	return updatedBoard, nil
 }
 
+// prepareCardPropertiesPatch checks the card properties a board would have
+// once patched, and links the new relation properties to their target
+// board.
+func (a *App) prepareCardPropertiesPatch(boardID string, patch *model.BoardPatch, userID string) error {
+	board, err := a.store.GetBoard(boardID)
+	if model.IsErrNotFound(err) {
+		return model.NewErrNotFound("board ID=" + boardID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// only the card properties are patched, so the board isn't modified
+	patched := &model.Board{CardProperties: board.CardProperties}
+	cardPropertiesPatch := &model.BoardPatch{
+		UpdatedCardProperties: patch.UpdatedCardProperties,
+		DeletedCardProperties: patch.DeletedCardProperties,
+	}
+	cardPropertiesPatch.Patch(patched)
+	if err = model.ValidateFormulas(patched); err != nil {
+		return err
+	}
+	if err = model.ValidateRollups(patched); err != nil {
+		return err
+	}
+	return a.linkRelationProperties(board, patch, userID)
+}
+
 func (a *App) postChannelMessage(message, channelID string) {
 	err := a.store.PostMessage(message, "", channelID)
 	if err != nil {
@@ -549,6 +592,7 @@ func (a *App) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, e
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastMemberChange(board.TeamID, member.BoardID, member)
+		a.notifyWebhooksMemberAdded(board, newMember)
 		return nil
 	})
 
@@ -8,6 +8,24 @@ import (
 )
 
 func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, addMember bool) (*model.BoardsAndBlocks, error) {
+	var memberIDs []string
+	if addMember {
+		memberIDs = []string{userID}
+	}
+	return a.createBoardsAndBlocks(bab, userID, addMember, memberIDs)
+}
+
+// createBoardsAndBlocks creates boards and their blocks. memberIDs are the
+// users that will be members of the new boards, which the person properties
+// of their cards can refer to. The card property values of the boards
+// created by the system user, like the built-in templates, aren't checked.
+func (a *App) createBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, addMember bool, memberIDs []string) (*model.BoardsAndBlocks, error) {
+	if userID != model.SystemUserID {
+		if err := a.validateCreatedCardsProperties(bab, memberIDs, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	var newBab *model.BoardsAndBlocks
 	var members []*model.BoardMember
 	var err error
@@ -26,13 +44,15 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 	teamID := newBab.Boards[0].TeamID
 
 	// This can be synchronous because this action is not common
+	boards := make(map[string]*model.Board, len(newBab.Boards))
 	for _, board := range newBab.Boards {
+		boards[board.ID] = board
 		a.wsAdapter.BroadcastBoardChange(teamID, board)
 	}
 
 	for _, block := range newBab.Blocks {
 		b := block
-		a.wsAdapter.BroadcastBlockChange(teamID, b)
+		a.broadcastBlockChange(teamID, boards[b.BoardID], b)
 		a.metrics.IncrementBlocksInserted(1)
 		a.webhook.NotifyUpdate(b)
 		a.notifyBlockChanged(notify.Add, b, nil, userID)
@@ -66,11 +86,39 @@ func (a *App) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID stri
 		oldBlocksMap[block.ID] = block
 	}
 
+	// the values of the cards are validated against the card properties
+	// their boards are patched with
+	boards, err := a.boardsWithPatchedCardProperties(pbab)
+	if err != nil {
+		return nil, err
+	}
+	blockPatches := pbab.BlockPatchBatch()
+	if err = a.validatePatchedCardsProperties(oldBlocks, blockPatches, boards, userID); err != nil {
+		return nil, err
+	}
+
+	for i, boardID := range pbab.BoardIDs {
+		patch := pbab.BoardPatches[i]
+		if patch != nil && (len(patch.UpdatedCardProperties) != 0 || len(patch.DeletedCardProperties) != 0) {
+			if err = a.prepareCardPropertiesPatch(boardID, patch, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	bab, err := a.store.PatchBoardsAndBlocks(pbab, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	// the reverse relations are synced with the patched boards, which have
+	// the reverse properties of their new relation properties
+	patchedBoards := make(map[string]*model.Board, len(bab.Boards))
+	for _, board := range bab.Boards {
+		patchedBoards[board.ID] = board
+	}
+	a.syncPatchedCardsRelations(patchedBoards, oldBlocks, blockPatches, userID)
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		teamID := bab.Boards[0].TeamID
 
@@ -83,7 +131,7 @@ func (a *App) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID stri
 
 			b := block
 			a.metrics.IncrementBlocksPatched(1)
-			a.wsAdapter.BroadcastBlockChange(teamID, b)
+			a.broadcastBlockChange(teamID, patchedBoards[b.BoardID], b)
 			a.webhook.NotifyUpdate(b)
 			a.notifyBlockChanged(notify.Update, b, oldBlock, userID)
 		}
@@ -97,26 +145,63 @@ func (a *App) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID stri
 	return bab, nil
 }
 
+// boardsWithPatchedCardProperties returns the boards of a patch, by ID,
+// with the card properties they are patched with.
+func (a *App) boardsWithPatchedCardProperties(pbab *model.PatchBoardsAndBlocks) (map[string]*model.Board, error) {
+	boards := make(map[string]*model.Board, len(pbab.BoardIDs))
+	for i, boardID := range pbab.BoardIDs {
+		board, err := a.store.GetBoard(boardID)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(pbab.BoardPatches) && pbab.BoardPatches[i] != nil {
+			// only the card properties of a copy are patched, so the board
+			// isn't modified
+			patched := *board
+			cardPropertiesPatch := &model.BoardPatch{
+				UpdatedCardProperties: pbab.BoardPatches[i].UpdatedCardProperties,
+				DeletedCardProperties: pbab.BoardPatches[i].DeletedCardProperties,
+			}
+			cardPropertiesPatch.Patch(&patched)
+			board = &patched
+		}
+		boards[boardID] = board
+	}
+	return boards, nil
+}
+
 func (a *App) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
 	firstBoard, err := a.store.GetBoard(dbab.Boards[0])
 	if err != nil {
 		return err
 	}
 
-	// we need the block entity to notify of the block changes, so we
-	// fetch and store the blocks first
+	// we need the block entity to notify of the block changes, and their
+	// board to unlink the deleted cards, so we fetch and store them first
 	blocks := []*model.Block{}
+	boards := map[string]*model.Board{}
 	for _, blockID := range dbab.Blocks {
 		block, err := a.store.GetBlock(blockID)
 		if err != nil {
 			return err
 		}
 		blocks = append(blocks, block)
+
+		if _, ok := boards[block.BoardID]; !ok {
+			board, err := a.store.GetBoard(block.BoardID)
+			if err != nil {
+				return err
+			}
+			boards[block.BoardID] = board
+		}
 	}
 
 	if err := a.store.DeleteBoardsAndBlocks(dbab, userID); err != nil {
 		return err
 	}
+	for _, block := range blocks {
+		a.syncCardRelations(boards[block.BoardID], block, nil, userID)
+	}
 
 	a.blockChangeNotifier.Enqueue(func() error {
 		for _, block := range blocks {
@@ -43,6 +43,9 @@ func TestAddMemberToBoard(t *testing.T) {
 		// for WS change broadcast
 		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
 
+		// for webhooks
+		th.Store.EXPECT().GetActiveWebhooks("team_id_1", boardID).Return([]*model.Webhook{}, nil)
+
 		th.Store.EXPECT().GetUserCategoryBoards("user_id_1", "team_id_1").Return([]model.CategoryBoards{
 			{
 				Category: model.Category{
@@ -117,6 +120,9 @@ func TestAddMemberToBoard(t *testing.T) {
 		// for WS change broadcast
 		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
 
+		// for webhooks
+		th.Store.EXPECT().GetActiveWebhooks("team_id_1", boardID).Return([]*model.Webhook{}, nil)
+
 		th.Store.EXPECT().GetUserCategoryBoards("user_id_1", "team_id_1").Return([]model.CategoryBoards{
 			{
 				Category: model.Category{
@@ -0,0 +1,223 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// cardActivityMaxRevisions is the maximum number of revisions of each
+// block loaded to build the activity timeline of a card.
+const cardActivityMaxRevisions = 1000
+
+// GetCardActivity returns a page of the activity timeline of a card, newest
+// first. The timeline is built from the history of the card and of its
+// content blocks and comments, including the deleted ones. Only the
+// revisions needed up to the page are loaded, and at most
+// cardActivityMaxRevisions for each block. The formulas of all the
+// revisions are evaluated at the same time, in the time zone of the user.
+func (a *App) GetCardActivity(cardID, userID string, page int, perPage int) ([]*model.CardActivity, error) {
+	if page < 0 || perPage <= 0 {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("invalid pagination, page %d per page %d", page, perPage))
+	}
+	if page >= math.MaxInt32/perPage {
+		return []*model.CardActivity{}, nil
+	}
+
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if card.Type != model.TypeCard {
+		return nil, model.NewErrNotFound("card ID=" + cardID)
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse property schema for board %s: %w", board.ID, err)
+	}
+
+	fctx := model.FormulaContext{}
+	if schema.HasFormulas() {
+		fctx = model.NewFormulaContext(a.userLocation(userID))
+	}
+
+	// the number of newest entries to build to return the page
+	count := (page + 1) * perPage
+
+	activity, err := a.newestBlockActivity(cardID, cardID, count, schema, fctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children, _, err := a.store.GetBlockHistoryNewestChildren(cardID, model.QueryBlockHistoryChildOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].UpdateAt != children[j].UpdateAt {
+			return children[i].UpdateAt > children[j].UpdateAt
+		}
+		return children[i].ID < children[j].ID
+	})
+
+	sortActivity(activity)
+	for _, child := range children {
+		// the entries of the children changed last before the oldest entry
+		// of the page can't be in it
+		if len(activity) >= count && child.UpdateAt < activity[count-1].UpdateAt {
+			break
+		}
+		childActivity, err := a.newestBlockActivity(cardID, child.ID, count, schema, fctx)
+		if err != nil {
+			return nil, err
+		}
+		activity = append(activity, childActivity...)
+		sortActivity(activity)
+		if len(activity) > count {
+			activity = activity[:count]
+		}
+	}
+
+	start := page * perPage
+	if start >= len(activity) {
+		return []*model.CardActivity{}, nil
+	}
+	end := start + perPage
+	if end > len(activity) {
+		end = len(activity)
+	}
+	return activity[start:end], nil
+}
+
+// newestBlockActivity returns the activity of at least the count newest
+// entries of a block, if it has as many, loading its newest revisions
+// only.
+func (a *App) newestBlockActivity(cardID, blockID string, count int, schema model.PropSchema, fctx model.FormulaContext) ([]*model.CardActivity, error) {
+	// the revisions that don't change the block aren't entries, so more
+	// revisions are loaded until there are enough entries
+	limit := count + 1
+	for {
+		if limit > cardActivityMaxRevisions {
+			limit = cardActivityMaxRevisions
+		}
+		revisions, err := a.store.GetBlockHistory(blockID, model.QueryBlockHistoryOptions{Limit: uint64(limit), Descending: true})
+		if err != nil {
+			return nil, err
+		}
+		// oldest first
+		for i, j := 0, len(revisions)-1; i < j; i, j = i+1, j-1 {
+			revisions[i], revisions[j] = revisions[j], revisions[i]
+		}
+
+		if len(revisions) < limit {
+			return a.blockActivity(cardID, nil, revisions, schema, fctx), nil
+		}
+		// the oldest revision loaded is only the previous one of the next
+		activity := a.blockActivity(cardID, revisions[0], revisions[1:], schema, fctx)
+		if len(activity) >= count || limit == cardActivityMaxRevisions {
+			return activity, nil
+		}
+		limit *= 2
+	}
+}
+
+func sortActivity(activity []*model.CardActivity) {
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].UpdateAt > activity[j].UpdateAt
+	})
+}
+
+// blockActivity describes the changes between the consecutive revisions of
+// a block, sorted from oldest to newest. previous is the revision before
+// the first one, nil if the first one is the creation of the block.
+// Revisions that don't change the title, the properties or the fields of
+// the block are skipped.
+func (a *App) blockActivity(cardID string, previous *model.Block, revisions []*model.Block, schema model.PropSchema, fctx model.FormulaContext) []*model.CardActivity {
+	activity := []*model.CardActivity{}
+
+	for _, revision := range revisions {
+		prev := previous
+		previous = revision
+
+		entry := &model.CardActivity{
+			CardID:     cardID,
+			BlockID:    revision.ID,
+			BlockType:  revision.Type,
+			ModifiedBy: revision.ModifiedBy,
+			UpdateAt:   revision.UpdateAt,
+		}
+
+		switch {
+		case prev == nil:
+			if revision.DeleteAt != 0 {
+				continue
+			}
+			entry.Action = model.CardActivityActionCreated
+			entry.NewTitle = revision.Title
+			entry.PropDiffs = a.blockPropDiffs(nil, revision, schema, fctx)
+		case revision.DeleteAt != 0:
+			if prev.DeleteAt != 0 {
+				continue
+			}
+			entry.Action = model.CardActivityActionDeleted
+			entry.OldTitle = prev.Title
+		case prev.DeleteAt != 0:
+			entry.Action = model.CardActivityActionRestored
+			entry.NewTitle = revision.Title
+		default:
+			entry.Action = model.CardActivityActionUpdated
+			if revision.Title != prev.Title {
+				entry.OldTitle = prev.Title
+				entry.NewTitle = revision.Title
+			}
+			entry.PropDiffs = a.blockPropDiffs(prev, revision, schema, fctx)
+
+			if revision.Title == prev.Title && len(entry.PropDiffs) == 0 {
+				// the content order and other card fields are not part
+				// of the timeline, but the fields of content blocks, like
+				// the value of a checkbox, are
+				if revision.Type == model.TypeCard || reflect.DeepEqual(prev.Fields, revision.Fields) {
+					continue
+				}
+			}
+		}
+
+		activity = append(activity, entry)
+	}
+	return activity
+}
+
+func (a *App) blockPropDiffs(oldBlock, newBlock *model.Block, schema model.PropSchema, fctx model.FormulaContext) []model.PropDiff {
+	oldProps, err := model.ParseProperties(oldBlock, schema, a.store, fctx)
+	if err != nil {
+		a.logger.Warn("Cannot parse properties of block revision",
+			mlog.String("block_id", oldBlock.ID),
+			mlog.Err(err),
+		)
+	}
+
+	newProps, err := model.ParseProperties(newBlock, schema, a.store, fctx)
+	if err != nil {
+		a.logger.Warn("Cannot parse properties of block revision",
+			mlog.String("block_id", newBlock.ID),
+			mlog.Err(err),
+		)
+	}
+
+	return model.DiffProperties(oldProps, newProps)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockActivity(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	schema := model.PropSchema{
+		"status": {ID: "status", Name: "Status", Type: "select", Options: map[string]model.PropDefOption{
+			"todo": {ID: "todo", Value: "To do"},
+			"done": {ID: "done", Value: "Done"},
+		}},
+	}
+
+	withStatus := func(status string) map[string]interface{} {
+		return map[string]interface{}{"properties": map[string]interface{}{"status": status}}
+	}
+
+	t.Run("card revisions", func(t *testing.T) {
+		revisions := []*model.Block{
+			{ID: "card", Type: model.TypeCard, Title: "Title", ModifiedBy: "user-1", UpdateAt: 1, Fields: withStatus("todo")},
+			{ID: "card", Type: model.TypeCard, Title: "New title", ModifiedBy: "user-2", UpdateAt: 2, Fields: withStatus("todo")},
+			{ID: "card", Type: model.TypeCard, Title: "New title", ModifiedBy: "user-2", UpdateAt: 3, Fields: withStatus("done")},
+			// only the content order changes, which is not part of the timeline
+			{ID: "card", Type: model.TypeCard, Title: "New title", ModifiedBy: "user-1", UpdateAt: 4, Fields: map[string]interface{}{
+				"properties":   map[string]interface{}{"status": "done"},
+				"contentOrder": []interface{}{"block"},
+			}},
+			{ID: "card", Type: model.TypeCard, Title: "New title", ModifiedBy: "user-1", UpdateAt: 5, DeleteAt: 5, Fields: withStatus("done")},
+			{ID: "card", Type: model.TypeCard, Title: "New title", ModifiedBy: "user-2", UpdateAt: 6, Fields: withStatus("done")},
+		}
+
+		activity := th.App.blockActivity("card", nil, revisions, schema, model.FormulaContext{})
+		require.Len(t, activity, 5)
+
+		require.Equal(t, model.CardActivityActionCreated, activity[0].Action)
+		require.Equal(t, "Title", activity[0].NewTitle)
+		require.Equal(t, []model.PropDiff{{ID: "status", Name: "Status", OldValue: "", NewValue: "TO DO"}}, activity[0].PropDiffs)
+
+		require.Equal(t, model.CardActivityActionUpdated, activity[1].Action)
+		require.Equal(t, "user-2", activity[1].ModifiedBy)
+		require.Equal(t, "Title", activity[1].OldTitle)
+		require.Equal(t, "New title", activity[1].NewTitle)
+		require.Empty(t, activity[1].PropDiffs)
+
+		require.Equal(t, model.CardActivityActionUpdated, activity[2].Action)
+		require.Empty(t, activity[2].NewTitle)
+		require.Equal(t, []model.PropDiff{{ID: "status", Name: "Status", OldValue: "TO DO", NewValue: "DONE"}}, activity[2].PropDiffs)
+
+		require.Equal(t, model.CardActivityActionDeleted, activity[3].Action)
+		require.Equal(t, int64(5), activity[3].UpdateAt)
+
+		require.Equal(t, model.CardActivityActionRestored, activity[4].Action)
+		require.Equal(t, "user-2", activity[4].ModifiedBy)
+	})
+
+	t.Run("content block revisions", func(t *testing.T) {
+		revisions := []*model.Block{
+			{ID: "checkbox", Type: model.TypeCheckbox, Title: "Item", UpdateAt: 1, Fields: map[string]interface{}{"value": false}},
+			{ID: "checkbox", Type: model.TypeCheckbox, Title: "Item", UpdateAt: 2, Fields: map[string]interface{}{"value": true}},
+			{ID: "checkbox", Type: model.TypeCheckbox, Title: "Item", UpdateAt: 3, Fields: map[string]interface{}{"value": true}},
+		}
+
+		activity := th.App.blockActivity("card", nil, revisions, schema, model.FormulaContext{})
+		require.Len(t, activity, 2)
+		require.Equal(t, model.CardActivityActionCreated, activity[0].Action)
+		require.Equal(t, model.CardActivityActionUpdated, activity[1].Action)
+		require.Equal(t, "checkbox", activity[1].BlockID)
+		require.Equal(t, "card", activity[1].CardID)
+	})
+
+	t.Run("revisions following a previous one", func(t *testing.T) {
+		previous := &model.Block{ID: "card", Type: model.TypeCard, Title: "Title", UpdateAt: 1, Fields: withStatus("todo")}
+		revisions := []*model.Block{
+			{ID: "card", Type: model.TypeCard, Title: "Title", UpdateAt: 2, Fields: withStatus("done")},
+		}
+
+		activity := th.App.blockActivity("card", previous, revisions, schema, model.FormulaContext{})
+		require.Len(t, activity, 1)
+		require.Equal(t, model.CardActivityActionUpdated, activity[0].Action)
+		require.Equal(t, []model.PropDiff{{ID: "status", Name: "Status", OldValue: "TO DO", NewValue: "DONE"}}, activity[0].PropDiffs)
+	})
+}
+
+func TestGetCardActivity(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	card := &model.Block{ID: "card", BoardID: "board", Type: model.TypeCard}
+	revision := func(id string, title string, updateAt int64) *model.Block {
+		return &model.Block{ID: id, BoardID: "board", ParentID: "card", Type: model.TypeComment, Title: title, UpdateAt: updateAt}
+	}
+	newest := func(limit uint64) model.QueryBlockHistoryOptions {
+		return model.QueryBlockHistoryOptions{Limit: limit, Descending: true}
+	}
+
+	th.Store.EXPECT().GetBlock("card").Return(card, nil)
+	th.Store.EXPECT().GetBoard("board").Return(&model.Board{ID: "board"}, nil)
+	// the card changed 3 times, but the page only needs the 2 newest entries
+	th.Store.EXPECT().GetBlockHistory("card", newest(3)).Return([]*model.Block{
+		{ID: "card", Type: model.TypeCard, Title: "3", UpdateAt: 30},
+		{ID: "card", Type: model.TypeCard, Title: "2", UpdateAt: 20},
+		{ID: "card", Type: model.TypeCard, Title: "1", UpdateAt: 10},
+	}, nil)
+	th.Store.EXPECT().GetBlockHistoryNewestChildren("card", model.QueryBlockHistoryChildOptions{}).Return([]*model.Block{
+		revision("old-comment", "old", 5),
+		revision("comment", "new", 25),
+	}, false, nil)
+	th.Store.EXPECT().GetBlockHistory("comment", newest(3)).Return([]*model.Block{revision("comment", "new", 25)}, nil)
+
+	// the history of old-comment isn't loaded, as it is too old to be in the page
+	activity, err := th.App.GetCardActivity("card", "user_id_1", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, activity, 1)
+	require.Equal(t, "comment", activity[0].BlockID)
+	require.Equal(t, model.CardActivityActionCreated, activity[0].Action)
+}
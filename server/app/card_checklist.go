@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// SetCardsChecklist sets the checklist progress of cards, computed from
+// their checkbox blocks.
+func (a *App) SetCardsChecklist(cards ...*model.Card) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	cardIDs := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+	progress, err := a.store.GetChecklistProgress(cardIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, card := range cards {
+		card.Checklist = progress[card.ID]
+	}
+	return nil
+}
+
+// checklistContribution returns what a block adds to the checklist of its
+// parent card.
+func checklistContribution(block *model.Block) model.ChecklistProgress {
+	if block == nil || block.Type != model.TypeCheckbox {
+		return model.ChecklistProgress{}
+	}
+	if model.IsCheckboxChecked(block) {
+		return model.ChecklistProgress{Done: 1, Total: 1}
+	}
+	return model.ChecklistProgress{Total: 1}
+}
+
+// notifyChecklistChanges broadcasts the checklist progress of the cards
+// whose checkbox blocks changed, the old blocks being nil for inserted
+// blocks and the new ones nil for deleted blocks, and sends a
+// ChecklistComplete event for the checklists that the changes completed.
+func (a *App) notifyChecklistChanges(teamID string, oldBlocks, newBlocks []*model.Block, modifiedByID string, disableNotify bool) {
+	deltas := map[string]model.ChecklistProgress{}
+	boardIDs := map[string]string{}
+	changedBlocks := map[string]*model.Block{}
+	addDelta := func(block *model.Block, sign int) {
+		contribution := checklistContribution(block)
+		if contribution.Total == 0 {
+			return
+		}
+		delta := deltas[block.ParentID]
+		delta.Done += sign * contribution.Done
+		delta.Total += sign * contribution.Total
+		deltas[block.ParentID] = delta
+		boardIDs[block.ParentID] = block.BoardID
+		changedBlocks[block.ParentID] = block
+	}
+	for i := range newBlocks {
+		addDelta(oldBlocks[i], -1)
+		addDelta(newBlocks[i], 1)
+	}
+
+	cardIDs := make([]string, 0, len(deltas))
+	for cardID, delta := range deltas {
+		if delta.Done != 0 || delta.Total != 0 {
+			cardIDs = append(cardIDs, cardID)
+		}
+	}
+	if len(cardIDs) == 0 {
+		return
+	}
+
+	progress, err := a.store.GetChecklistProgress(cardIDs)
+	if err != nil {
+		a.logger.Error("Error notifying for checklist changes; cannot get progress", mlog.Err(err))
+		return
+	}
+
+	for _, cardID := range cardIDs {
+		after := progress[cardID]
+		a.wsAdapter.BroadcastCardChecklistChange(teamID, boardIDs[cardID], cardID, after)
+
+		before := model.ChecklistProgress{
+			Done:  after.Done - deltas[cardID].Done,
+			Total: after.Total - deltas[cardID].Total,
+		}
+		if disableNotify || before.IsComplete() || !after.IsComplete() {
+			continue
+		}
+		a.notifyChecklistComplete(cardID, changedBlocks[cardID], after, modifiedByID)
+	}
+}
+
+func (a *App) notifyChecklistComplete(cardID string, checkbox *model.Block, checklist model.ChecklistProgress, modifiedByID string) {
+	if a.notifications == nil || modifiedByID == model.SystemUserID {
+		return
+	}
+
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		a.logger.Error("Error notifying for checklist complete; cannot get card", mlog.String("card_id", cardID), mlog.Err(err))
+		return
+	}
+	if card.Type != model.TypeCard {
+		return
+	}
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		a.logger.Error("Error notifying for checklist complete; cannot get board", mlog.String("board_id", card.BoardID), mlog.Err(err))
+		return
+	}
+
+	boardMember, _ := a.GetMemberForBoard(board.ID, modifiedByID)
+	if boardMember == nil {
+		// create temporary guest board member
+		boardMember = &model.BoardMember{
+			BoardID: board.ID,
+			UserID:  modifiedByID,
+		}
+	}
+
+	a.notifications.BlockChanged(notify.BlockChangeEvent{
+		Action:       notify.ChecklistComplete,
+		TeamID:       board.TeamID,
+		Board:        board,
+		Card:         card,
+		BlockChanged: checkbox,
+		ModifiedBy:   boardMember,
+		Checklist:    &checklist,
+	})
+}
@@ -0,0 +1,317 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// GetCardDependencies returns the dependencies of a card on the cards
+// that block it and of the cards it blocks, leaving out the ones whose
+// other card is deleted or belongs to a board the user can't view.
+func (a *App) GetCardDependencies(card *model.Card, userID string) (*model.CardDependencies, error) {
+	dependencies, err := a.store.GetCardDependencies(model.QueryCardDependenciesOptions{
+		BlockerIDs: []string{card.ID},
+		BlockedIDs: []string{card.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	otherIDs := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		if dependency.BlockedID == card.ID {
+			otherIDs = append(otherIDs, dependency.BlockerID)
+		} else {
+			otherIDs = append(otherIDs, dependency.BlockedID)
+		}
+	}
+	existing := map[string]bool{}
+	if len(otherIDs) > 0 {
+		blocks, err := a.store.GetBlocksByIDs(otherIDs)
+		if err != nil && !model.IsErrNotFound(err) {
+			return nil, err
+		}
+		for _, block := range blocks {
+			existing[block.ID] = true
+		}
+	}
+
+	canView := map[string]bool{}
+	visible := func(otherID, boardID string) bool {
+		if !existing[otherID] {
+			return false
+		}
+		allowed, ok := canView[boardID]
+		if !ok {
+			allowed = a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard)
+			canView[boardID] = allowed
+		}
+		return allowed
+	}
+
+	result := &model.CardDependencies{
+		BlockedBy: []*model.CardDependency{},
+		Blocks:    []*model.CardDependency{},
+	}
+	for _, dependency := range dependencies {
+		if dependency.BlockedID == card.ID {
+			if visible(dependency.BlockerID, dependency.BlockerBoardID) {
+				result.BlockedBy = append(result.BlockedBy, dependency)
+			}
+		} else if visible(dependency.BlockedID, dependency.BlockedBoardID) {
+			result.Blocks = append(result.Blocks, dependency)
+		}
+	}
+	return result, nil
+}
+
+// AddCardDependency makes a card blocked by another card of a board of the
+// same team. The dependency is rejected if the blocker is already blocked,
+// directly or not, by the card.
+func (a *App) AddCardDependency(card *model.Card, blockerID, userID string) (*model.CardDependency, error) {
+	blocker, err := a.store.GetBlock(blockerID)
+	if model.IsErrNotFound(err) {
+		return nil, model.NewErrBadRequest("blocker card not found, ID=" + blockerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if blocker.Type != model.TypeCard {
+		return nil, model.NewErrBadRequest("blocker ID=" + blockerID + " is not a card")
+	}
+
+	if blocker.BoardID != card.BoardID {
+		if !a.permissions.HasPermissionToBoard(userID, blocker.BoardID, model.PermissionViewBoard) {
+			return nil, model.NewErrPermission("access denied to the board of the blocker card")
+		}
+		board, err := a.store.GetBoard(card.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		blockerBoard, err := a.store.GetBoard(blocker.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		if blockerBoard.TeamID != board.TeamID {
+			return nil, model.NewErrBadRequest("the blocker card belongs to another team")
+		}
+	}
+
+	dependency := &model.CardDependency{
+		BlockerID:      blocker.ID,
+		BlockerBoardID: blocker.BoardID,
+		BlockedID:      card.ID,
+		BlockedBoardID: card.BoardID,
+		CreatedBy:      userID,
+	}
+	if err = dependency.IsValid(); err != nil {
+		return nil, err
+	}
+
+	blockers, err := a.store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockedIDs: []string{card.ID}})
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range blockers {
+		if existing.BlockerID == blocker.ID {
+			return nil, model.NewErrBadRequest("the card is already blocked by card ID=" + blocker.ID)
+		}
+	}
+
+	cycle, err := model.FindDependencyCycle(blocker.ID, card.ID, a.getCardBlockerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if cycle {
+		return nil, model.NewErrBadRequest(model.ErrCardDependencyCycle.Error())
+	}
+
+	inserted, err := a.store.InsertCardDependency(dependency)
+	if err != nil {
+		return nil, err
+	}
+
+	// a dependency added at the same time can close a cycle that neither
+	// check saw, even in a transaction, so the check is done again once the
+	// dependency is inserted and it is removed if it closes one. Of two
+	// dependencies closing a cycle, at least the last one inserted sees it
+	cycle, err = model.FindDependencyCycle(blocker.ID, card.ID, a.getCardBlockerIDs)
+	if err == nil && !cycle {
+		return inserted, nil
+	}
+	if dErr := a.store.DeleteCardDependency(blocker.ID, card.ID); dErr != nil {
+		a.logger.Error("Error removing a card dependency that closes a cycle",
+			mlog.String("blockerID", blocker.ID),
+			mlog.String("blockedID", card.ID),
+			mlog.Err(dErr),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, model.NewErrBadRequest(model.ErrCardDependencyCycle.Error())
+}
+
+// DeleteCardDependency removes a blocker of a card.
+func (a *App) DeleteCardDependency(card *model.Card, blockerID string) error {
+	return a.store.DeleteCardDependency(blockerID, card.ID)
+}
+
+// getCardBlockerIDs returns the IDs of the cards that block the cards.
+func (a *App) getCardBlockerIDs(cardIDs []string) ([]string, error) {
+	dependencies, err := a.store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockedIDs: cardIDs})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		ids = append(ids, dependency.BlockerID)
+	}
+	return ids, nil
+}
+
+// SetCardsBlocked sets the blocked flag of cards, which are blocked when
+// one of their blockers isn't done. Deleted blockers don't block.
+func (a *App) SetCardsBlocked(cards ...*model.Card) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	cardIDs := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+	dependencies, err := a.store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockedIDs: cardIDs})
+	if err != nil {
+		return err
+	}
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	blockerIDs := []string{}
+	seen := map[string]bool{}
+	for _, dependency := range dependencies {
+		if !seen[dependency.BlockerID] {
+			seen[dependency.BlockerID] = true
+			blockerIDs = append(blockerIDs, dependency.BlockerID)
+		}
+	}
+	blockers, err := a.store.GetBlocksByIDs(blockerIDs)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+
+	schemas := map[string]model.PropSchema{}
+	notDone := map[string]bool{}
+	for _, blocker := range blockers {
+		schema, ok := schemas[blocker.BoardID]
+		if !ok {
+			schema, err = a.getBoardPropertySchema(blocker.BoardID)
+			if err != nil {
+				return err
+			}
+			schemas[blocker.BoardID] = schema
+		}
+		if schema != nil && !schema.IsCardDone(blocker) {
+			notDone[blocker.ID] = true
+		}
+	}
+
+	blocked := map[string]bool{}
+	for _, dependency := range dependencies {
+		if notDone[dependency.BlockerID] {
+			blocked[dependency.BlockedID] = true
+		}
+	}
+	for _, card := range cards {
+		card.Blocked = blocked[card.ID]
+	}
+	return nil
+}
+
+// getBoardPropertySchema returns the card property schema of a board, or
+// nil if the board is deleted.
+func (a *App) getBoardPropertySchema(boardID string) (model.PropSchema, error) {
+	board, err := a.store.GetBoard(boardID)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Warn("Invalid card properties, ignoring board", mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, nil
+	}
+	return schema, nil
+}
+
+// notifyBlockerDone sends an Unblock event for each card blocked by a card
+// that has just been done.
+func (a *App) notifyBlockerDone(board *model.Board, block, oldBlock *model.Block, modifiedByID string) {
+	if block.Type != model.TypeCard || oldBlock == nil {
+		return
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil || schema.IsCardDone(oldBlock) || !schema.IsCardDone(block) {
+		return
+	}
+
+	dependencies, err := a.store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockerIDs: []string{block.ID}})
+	if err != nil {
+		a.logger.Error("Error notifying for blocker done; cannot get dependencies", mlog.String("card_id", block.ID), mlog.Err(err))
+		return
+	}
+	if len(dependencies) == 0 {
+		return
+	}
+
+	blockedIDs := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		blockedIDs = append(blockedIDs, dependency.BlockedID)
+	}
+	blockedCards, err := a.store.GetBlocksByIDs(blockedIDs)
+	if err != nil && !model.IsErrNotFound(err) {
+		a.logger.Error("Error notifying for blocker done; cannot get blocked cards", mlog.String("card_id", block.ID), mlog.Err(err))
+		return
+	}
+
+	boards := map[string]*model.Board{board.ID: board}
+	for _, blockedCard := range blockedCards {
+		blockedBoard, ok := boards[blockedCard.BoardID]
+		if !ok {
+			blockedBoard, err = a.store.GetBoard(blockedCard.BoardID)
+			if err != nil {
+				a.logger.Error("Error notifying for blocker done; cannot get board", mlog.String("board_id", blockedCard.BoardID), mlog.Err(err))
+				continue
+			}
+			boards[blockedBoard.ID] = blockedBoard
+		}
+
+		boardMember, _ := a.GetMemberForBoard(blockedBoard.ID, modifiedByID)
+		if boardMember == nil {
+			// create temporary guest board member
+			boardMember = &model.BoardMember{
+				BoardID: blockedBoard.ID,
+				UserID:  modifiedByID,
+			}
+		}
+
+		a.notifications.BlockChanged(notify.BlockChangeEvent{
+			Action:       notify.Unblock,
+			TeamID:       blockedBoard.TeamID,
+			Board:        blockedBoard,
+			Card:         blockedCard,
+			BlockChanged: block,
+			BlockOld:     oldBlock,
+			ModifiedBy:   boardMember,
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestAddCardDependency(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	card := &model.Card{ID: "card-id", BoardID: "board-id"}
+	blocker := &model.Block{ID: "blocker-id", BoardID: "board-id", Type: model.TypeCard}
+	dependency := &model.CardDependency{
+		BlockerID:      blocker.ID,
+		BlockerBoardID: blocker.BoardID,
+		BlockedID:      card.ID,
+		BlockedBoardID: card.BoardID,
+		CreatedBy:      "user-id",
+	}
+	blockersOfCard := model.QueryCardDependenciesOptions{BlockedIDs: []string{card.ID}}
+	blockersOfBlocker := model.QueryCardDependenciesOptions{BlockedIDs: []string{blocker.ID}}
+
+	t.Run("the dependency is inserted", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock(blocker.ID).Return(blocker, nil)
+		th.Store.EXPECT().GetCardDependencies(blockersOfCard).Return(nil, nil)
+		th.Store.EXPECT().GetCardDependencies(blockersOfBlocker).Return(nil, nil).Times(2)
+		th.Store.EXPECT().InsertCardDependency(dependency).Return(dependency, nil)
+
+		inserted, err := th.App.AddCardDependency(card, blocker.ID, "user-id")
+		require.NoError(t, err)
+		require.Equal(t, dependency, inserted)
+	})
+
+	t.Run("a cycle closed by a concurrent dependency is removed", func(t *testing.T) {
+		concurrent := &model.CardDependency{BlockerID: card.ID, BlockedID: blocker.ID}
+
+		th.Store.EXPECT().GetBlock(blocker.ID).Return(blocker, nil)
+		th.Store.EXPECT().GetCardDependencies(blockersOfCard).Return(nil, nil)
+		gomock.InOrder(
+			th.Store.EXPECT().GetCardDependencies(blockersOfBlocker).Return(nil, nil),
+			th.Store.EXPECT().InsertCardDependency(dependency).Return(dependency, nil),
+			th.Store.EXPECT().GetCardDependencies(blockersOfBlocker).Return([]*model.CardDependency{concurrent}, nil),
+			th.Store.EXPECT().DeleteCardDependency(blocker.ID, card.ID).Return(nil),
+		)
+
+		inserted, err := th.App.AddCardDependency(card, blocker.ID, "user-id")
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, inserted)
+	})
+}
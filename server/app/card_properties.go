@@ -0,0 +1,206 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// validateCardProperties checks the property values of a card of a board
+// against its card properties, returning an ErrInvalidPropertyValues that
+// lists the invalid ones. oldProperties are the current values of the card,
+// which aren't checked again if unchanged. The values of card templates
+// with placeholders are only checked once resolved. The changed relation
+// values must link to cards userID can edit.
+func (a *App) validateCardProperties(board *model.Board, oldProperties, properties map[string]interface{}, isTemplate bool, userID string) error {
+	return a.validateCardPropertiesWithMembers(board, oldProperties, properties, isTemplate, userID, a.boardMemberChecker(board.ID))
+}
+
+// validateCardPropertiesWithMembers checks the property values of a card
+// like validateCardProperties, with the members of the board told by
+// isMember.
+func (a *App) validateCardPropertiesWithMembers(board *model.Board, oldProperties, properties map[string]interface{}, isTemplate bool, userID string, isMember model.PropertyMemberChecker) error {
+	if isTemplate {
+		properties = model.PropertiesWithoutPlaceholders(properties)
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Warn("cannot validate the card properties of an invalid schema",
+			mlog.String("board_id", board.ID),
+			mlog.Err(err),
+		)
+		return nil
+	}
+
+	invalid, err := model.ValidateCardPropertyValues(schema, oldProperties, properties, isMember)
+	if err != nil {
+		return err
+	}
+	invalidRelations, err := a.validateRelationValues(board, schema, oldProperties, properties, userID)
+	if err != nil {
+		return err
+	}
+	invalid = append(invalid, invalidRelations...)
+	if len(invalid) > 0 {
+		return model.NewErrInvalidPropertyValues(invalid)
+	}
+	return nil
+}
+
+// validateInsertedCardsProperties checks the property values of the cards
+// among blocks inserted in a board.
+func (a *App) validateInsertedCardsProperties(board *model.Board, blocks []*model.Block, userID string) error {
+	for _, block := range blocks {
+		if block.Type != model.TypeCard {
+			continue
+		}
+		properties, _ := block.Fields["properties"].(map[string]interface{})
+		if err := a.validateCardProperties(board, nil, properties, isCardTemplate(block), userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCreatedCardsProperties checks the property values of the cards
+// created along with their boards. The boards don't have members yet, so
+// the persons must be among memberIDs, the users they are created with.
+func (a *App) validateCreatedCardsProperties(bab *model.BoardsAndBlocks, memberIDs []string, userID string) error {
+	members := make(map[string]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		members[memberID] = true
+	}
+	isMember := func(userID string) (bool, error) {
+		return members[userID], nil
+	}
+
+	for _, board := range bab.Boards {
+		for _, block := range bab.Blocks {
+			if block.BoardID != board.ID || block.Type != model.TypeCard {
+				continue
+			}
+			properties, _ := block.Fields["properties"].(map[string]interface{})
+			if err := a.validateCardPropertiesWithMembers(board, nil, properties, isCardTemplate(block), userID, isMember); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// boardMemberChecker returns a checker for the person properties of the
+// cards of a board, which loads the members of the board when first used.
+func (a *App) boardMemberChecker(boardID string) model.PropertyMemberChecker {
+	var members map[string]bool
+	return func(userID string) (bool, error) {
+		if members == nil {
+			boardMembers, err := a.store.GetMembersForBoard(boardID)
+			if err != nil {
+				return false, err
+			}
+			members = make(map[string]bool, len(boardMembers))
+			for _, member := range boardMembers {
+				members[member.UserID] = true
+			}
+		}
+		return members[userID], nil
+	}
+}
+
+// ScanInvalidCardProperties returns the cards of a page of the boards of a
+// team whose property values don't match the card properties of their board.
+func (a *App) ScanInvalidCardProperties(teamID string, page, perPage int) (*model.InvalidCardPropertiesResponse, error) {
+	boards, hasNext, err := a.store.GetBoardsForCompliance(model.QueryBoardsForComplianceOptions{
+		TeamID:  teamID,
+		Page:    page,
+		PerPage: perPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*model.InvalidCardProperties{}
+	for _, board := range boards {
+		schema, err := model.ParsePropertySchema(board)
+		if err != nil {
+			a.logger.Warn("cannot scan the card properties of an invalid schema",
+				mlog.String("board_id", board.ID),
+				mlog.Err(err),
+			)
+			continue
+		}
+
+		cards, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: board.ID, BlockType: model.TypeCard})
+		if err != nil {
+			return nil, err
+		}
+
+		isMember := a.boardMemberChecker(board.ID)
+		for _, card := range cards {
+			properties, _ := card.Fields["properties"].(map[string]interface{})
+			if isCardTemplate(card) {
+				properties = model.PropertiesWithoutPlaceholders(properties)
+			}
+			invalid, err := model.ValidateCardPropertyValues(schema, nil, properties, isMember)
+			if err != nil {
+				return nil, err
+			}
+			if len(invalid) > 0 {
+				results = append(results, &model.InvalidCardProperties{
+					BoardID:    board.ID,
+					CardID:     card.ID,
+					Title:      card.Title,
+					Properties: invalid,
+				})
+			}
+		}
+	}
+
+	return &model.InvalidCardPropertiesResponse{
+		HasNext: hasNext,
+		Results: results,
+	}, nil
+}
+
+// validatePatchedCardsProperties checks the property values set on cards by
+// a batch of block patches. The boards of the patched cards are added to
+// boards.
+func (a *App) validatePatchedCardsProperties(oldBlocks []*model.Block, blockPatches *model.BlockPatchBatch, boards map[string]*model.Board, userID string) error {
+	oldBlocksByID := make(map[string]*model.Block, len(oldBlocks))
+	for _, block := range oldBlocks {
+		oldBlocksByID[block.ID] = block
+	}
+
+	for i, blockID := range blockPatches.BlockIDs {
+		oldBlock, ok := oldBlocksByID[blockID]
+		if !ok || oldBlock.Type != model.TypeCard || i >= len(blockPatches.BlockPatches) {
+			continue
+		}
+		properties, ok := blockPatches.BlockPatches[i].UpdatedFields["properties"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		board, ok := boards[oldBlock.BoardID]
+		if !ok {
+			var err error
+			if board, err = a.store.GetBoard(oldBlock.BoardID); err != nil {
+				return err
+			}
+			boards[oldBlock.BoardID] = board
+		}
+
+		oldProperties, _ := oldBlock.Fields["properties"].(map[string]interface{})
+		if err := a.validateCardProperties(board, oldProperties, properties, isCardTemplate(oldBlock), userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
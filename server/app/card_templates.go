@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// CreateCardFromTemplate creates a card as the user by duplicating a card
+// template of the board with its content, and resolves the placeholders
+// of the title of the card and of its content and of its property values.
+// {{today}} is the day of the user in their time zone.
+func (a *App) CreateCardFromTemplate(boardID, templateID, userID string, request *model.CardFromTemplateRequest, disableNotify bool) (*model.Card, error) {
+	if err := request.IsValid(); err != nil {
+		return nil, err
+	}
+
+	template, err := a.store.GetBlock(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.BoardID != boardID || template.Type != model.TypeCard || !isCardTemplate(template) {
+		return nil, model.NewErrBadRequest("the template must be a card template of the board")
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	ctx := model.PlaceholderContext{
+		Now:        time.Now().In(a.userLocation(userID)),
+		CreatorID:  userID,
+		BoardTitle: board.Title,
+		Variables:  request.Variables,
+	}
+	if user, uErr := a.store.GetUserByID(userID); uErr == nil {
+		ctx.CreatorName = user.Username
+	} else {
+		a.logger.Debug("Unknown creator of card from template", mlog.String("user_id", userID), mlog.Err(uErr))
+	}
+
+	// the resolved values are checked before the card is created
+	templateProperties, _ := template.Fields["properties"].(map[string]interface{})
+	properties := ctx.ResolveProperties(schema, templateProperties)
+	if err = a.validateCardProperties(board, templateProperties, properties, false, userID); err != nil {
+		return nil, err
+	}
+
+	blocks, err := a.DuplicateBlock(boardID, templateID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	contentPatches := &model.BlockPatchBatch{}
+	for _, block := range blocks[1:] {
+		if !model.HasPlaceholders(block.Title) {
+			continue
+		}
+		title := ctx.Resolve(block.Title)
+		contentPatches.BlockIDs = append(contentPatches.BlockIDs, block.ID)
+		contentPatches.BlockPatches = append(contentPatches.BlockPatches, model.BlockPatch{Title: &title})
+	}
+	if len(contentPatches.BlockIDs) > 0 {
+		if err = a.PatchBlocksAndNotify(board.TeamID, contentPatches, userID, disableNotify); err != nil {
+			return nil, err
+		}
+	}
+
+	title := ctx.Resolve(blocks[0].Title)
+	cardBlock, err := a.PatchBlockAndNotify(blocks[0].ID, &model.BlockPatch{
+		Title:         &title,
+		UpdatedFields: map[string]interface{}{"properties": properties},
+	}, userID, disableNotify)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := model.Block2Card(cardBlock)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.evaluateCardFormulas(boardID, userID, card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
@@ -4,10 +4,13 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 func (a *App) CreateCard(card *model.Card, boardID string, userID string, disableNotify bool) (*model.Card, error) {
@@ -34,10 +37,34 @@ func (a *App) CreateCard(card *model.Card, boardID string, userID string, disabl
 		return nil, err
 	}
 
+	if err := a.evaluateCardFormulas(boardID, userID, newCard); err != nil {
+		return nil, err
+	}
+
 	return newCard, nil
 }
 
-func (a *App) GetCardsForBoard(boardID string, page int, perPage int) ([]*model.Card, error) {
+// GetCardsForBoard returns a page of the cards of a board. When the
+// checklist query filters or sorts the cards, they are queried like
+// QueryCardsForBoard, so the database applies it along with the
+// pagination, and the card templates are left out.
+func (a *App) GetCardsForBoard(boardID, userID string, page int, perPage int, checklist model.ChecklistQuery) ([]*model.Card, error) {
+	if err := checklist.IsValid(); err != nil {
+		return nil, err
+	}
+
+	if !checklist.IsEmpty() {
+		result, err := a.QueryCardsForBoard(boardID, userID, model.QueryCardsOptions{
+			Checklist: checklist,
+			Page:      page,
+			PerPage:   perPage,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Cards, nil
+	}
+
 	opts := model.QueryBlocksOptions{
 		BoardID:   boardID,
 		BlockType: model.TypeCard,
@@ -59,17 +86,82 @@ func (a *App) GetCardsForBoard(boardID string, page int, perPage int) ([]*model.
 			cards = append(cards, card)
 		}
 	}
+
+	if err := a.evaluateCardFormulas(boardID, userID, cards...); err != nil {
+		return nil, err
+	}
 	return cards, nil
 }
 
+// QueryCardsForBoard returns the cards of a board that match the filter
+// of the query options, sorted, paginated and counted per group. The
+// formulas of the cards are evaluated in the time zone of the user.
+func (a *App) QueryCardsForBoard(boardID, userID string, opts model.QueryCardsOptions) (*model.CardQueryResult, error) {
+	if err := opts.IsValid(); err != nil {
+		return nil, err
+	}
+	opts.Location = a.userLocation(userID)
+
+	result, err := a.store.QueryCards(boardID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot query cards for board %s: %w", boardID, err)
+	}
+	return result, nil
+}
+
+// SearchCardsForUser returns the cards and card content blocks that
+// match the search terms, from the boards of the team the user has access
+// to.
+func (a *App) SearchCardsForUser(teamID, userID, terms string, includePublicBoards bool, page, perPage int) ([]*model.CardSearchResult, error) {
+	opts := model.CardSearchOptions{
+		Terms:   model.SplitSearchTerms(terms),
+		Page:    page,
+		PerPage: perPage,
+	}
+	if len(opts.Terms) == 0 {
+		return []*model.CardSearchResult{}, nil
+	}
+
+	boards, err := a.store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
+	if err != nil {
+		return nil, err
+	}
+	for _, board := range boards {
+		if !board.IsTemplate {
+			opts.BoardIDs = append(opts.BoardIDs, board.ID)
+		}
+	}
+
+	results, err := a.store.SearchCards(opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot search cards for team %s: %w", teamID, err)
+	}
+	return results, nil
+}
+
 func (a *App) PatchCard(cardPatch *model.CardPatch, cardID string, userID string, disableNotify bool) (*model.Card, error) {
+	return a.patchCard(cardPatch, cardID, userID, disableNotify, nil)
+}
+
+// patchCard patches a card, as part of the chain of automation rules that
+// made the change if any.
+func (a *App) patchCard(cardPatch *model.CardPatch, cardID string, userID string, disableNotify bool, chain *model.AutomationChain) (*model.Card, error) {
 	blockPatch, err := model.CardPatch2BlockPatch(cardPatch)
 	if err != nil {
 		return nil, err
 	}
 
-	newBlock, err := a.PatchBlockAndNotify(cardID, blockPatch, userID, disableNotify)
+	newBlock, err := a.patchBlockAndNotify(cardID, blockPatch, userID, disableNotify, chain)
 	if err != nil {
+		var conflict *model.ErrConflict
+		if errors.As(err, &conflict) {
+			// clients of the cards API expect the current version as a card
+			if currentBlock, ok := conflict.Current.(*model.Block); ok {
+				if currentCard, cErr := model.Block2Card(currentBlock); cErr == nil {
+					return nil, model.NewErrConflict("card ID="+cardID, currentCard)
+				}
+			}
+		}
 		return nil, fmt.Errorf("cannot patch card %s: %w", cardID, err)
 	}
 
@@ -78,10 +170,14 @@ func (a *App) PatchCard(cardPatch *model.CardPatch, cardID string, userID string
 		return nil, err
 	}
 
+	if err := a.evaluateCardFormulas(newCard.BoardID, userID, newCard); err != nil {
+		return nil, err
+	}
+
 	return newCard, nil
 }
 
-func (a *App) GetCardByID(cardID string) (*model.Card, error) {
+func (a *App) GetCardByID(cardID, userID string) (*model.Card, error) {
 	cardBlock, err := a.GetBlockByID(cardID)
 	if err != nil {
 		return nil, err
@@ -92,5 +188,41 @@ func (a *App) GetCardByID(cardID string) (*model.Card, error) {
 		return nil, err
 	}
 
+	if err := a.evaluateCardFormulas(card.BoardID, userID, card); err != nil {
+		return nil, err
+	}
+
 	return card, nil
 }
+
+// evaluateCardFormulas sets the values of the formula properties of cards
+// of a board, as they are computed rather than stored, in the time zone of
+// the user reading them.
+func (a *App) evaluateCardFormulas(boardID, userID string, cards ...*model.Card) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Warn("cannot evaluate the formulas of an invalid schema",
+			mlog.String("board_id", boardID),
+			mlog.Err(err),
+		)
+		return nil
+	}
+	if !schema.HasFormulas() {
+		return nil
+	}
+
+	fctx := model.NewFormulaContext(a.userLocation(userID))
+	for _, card := range cards {
+		model.EvaluateCardFormulas(card, schema, fctx)
+	}
+	return nil
+}
@@ -34,7 +34,7 @@ func TestCreateCard(t *testing.T) {
 	block := model.Card2Block(card)
 
 	t.Run("success scenario", func(t *testing.T) {
-		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil).Times(2)
 		th.Store.EXPECT().InsertBlock(gomock.AssignableToTypeOf(reflect.TypeOf(block)), userID).Return(nil)
 		th.Store.EXPECT().GetMembersForBoard(board.ID).Return([]*model.BoardMember{}, nil)
 
@@ -89,8 +89,9 @@ func TestGetCards(t *testing.T) {
 		}
 
 		th.Store.EXPECT().GetBlocks(opts).Return(blocks, nil)
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
 
-		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0)
+		cards, err := th.App.GetCardsForBoard(board.ID, "user_id_1", 0, 0, model.ChecklistQuery{})
 		require.NoError(t, err)
 		assert.Len(t, cards, cardCount)
 	})
@@ -103,10 +104,37 @@ func TestGetCards(t *testing.T) {
 
 		th.Store.EXPECT().GetBlocks(opts).Return(nil, blockError{"error"})
 
-		cards, err := th.App.GetCardsForBoard(board.ID, 0, 0)
+		cards, err := th.App.GetCardsForBoard(board.ID, "user_id_1", 0, 0, model.ChecklistQuery{})
 		require.Error(t, err)
 		require.Nil(t, cards)
 	})
+
+	t.Run("checklist scenario", func(t *testing.T) {
+		query := model.ChecklistQuery{Filter: model.ChecklistFilterIncomplete, Sort: model.ChecklistSortDescending}
+		card, err := model.Block2Card(blocks[3])
+		require.NoError(t, err)
+
+		th.Store.EXPECT().GetUserTimezone("user_id_1").Return("", nil)
+		th.Store.EXPECT().QueryCards(board.ID, gomock.Any()).DoAndReturn(
+			func(_ string, opts model.QueryCardsOptions) (*model.CardQueryResult, error) {
+				// the checklist query and the pagination are applied by the store
+				require.Equal(t, query, opts.Checklist)
+				require.Equal(t, 2, opts.Page)
+				require.Equal(t, 1, opts.PerPage)
+				return &model.CardQueryResult{Cards: []*model.Card{card}, Total: 3}, nil
+			})
+
+		cards, err := th.App.GetCardsForBoard(board.ID, "user_id_1", 2, 1, query)
+		require.NoError(t, err)
+		require.Len(t, cards, 1)
+		require.Equal(t, blocks[3].ID, cards[0].ID)
+	})
+
+	t.Run("invalid checklist query", func(t *testing.T) {
+		cards, err := th.App.GetCardsForBoard(board.ID, "user_id_1", 0, 0, model.ChecklistQuery{Filter: "all"})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, cards)
+	})
 }
 
 func TestPatchCard(t *testing.T) {
@@ -145,7 +173,7 @@ func TestPatchCard(t *testing.T) {
 		expectedPatchedBlock := model.Card2Block(expectedPatchedCard)
 
 		var blockPatch *model.BlockPatch
-		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil).Times(2)
 		th.Store.EXPECT().PatchBlock(card.ID, gomock.AssignableToTypeOf(reflect.TypeOf(blockPatch)), userID).Return(nil)
 		th.Store.EXPECT().GetMembersForBoard(board.ID).Return([]*model.BoardMember{}, nil)
 		th.Store.EXPECT().GetBlock(card.ID).Return(expectedPatchedBlock, nil).AnyTimes()
@@ -199,8 +227,9 @@ func TestGetCard(t *testing.T) {
 
 	t.Run("success scenario", func(t *testing.T) {
 		th.Store.EXPECT().GetBlock(block.ID).Return(block, nil)
+		th.Store.EXPECT().GetBoard(boardID).Return(&model.Board{ID: boardID}, nil)
 
-		card, err := th.App.GetCardByID(block.ID)
+		card, err := th.App.GetCardByID(block.ID, "user_id_1")
 
 		require.NoError(t, err)
 		require.Equal(t, boardID, card.BoardID)
@@ -215,7 +244,7 @@ func TestGetCard(t *testing.T) {
 		bogusID := utils.NewID(utils.IDTypeBlock)
 		th.Store.EXPECT().GetBlock(bogusID).Return(nil, model.NewErrNotFound(bogusID))
 
-		card, err := th.App.GetCardByID(bogusID)
+		card, err := th.App.GetCardByID(bogusID, "user_id_1")
 
 		require.Error(t, err, "error")
 		require.True(t, model.IsErrNotFound(err))
@@ -225,7 +254,7 @@ func TestGetCard(t *testing.T) {
 	t.Run("error scenario", func(t *testing.T) {
 		th.Store.EXPECT().GetBlock(block.ID).Return(nil, blockError{"error"})
 
-		card, err := th.App.GetCardByID(block.ID)
+		card, err := th.App.GetCardByID(block.ID, "user_id_1")
 
 		require.Error(t, err, "error")
 		require.Nil(t, card)
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// dataRetentionBatchSize is the number of rows deleted by each query of
+// the data retention.
+const dataRetentionBatchSize = 1000
+
+// GetRetentionPolicies returns the default retention policy of a team,
+// if it has one, followed by the policies of its boards.
+func (a *App) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error) {
+	return a.store.GetRetentionPolicies(teamID)
+}
+
+// SaveRetentionPolicy creates or replaces the retention policy of a team
+// or a board. The team of a board policy is always the one of the board.
+func (a *App) SaveRetentionPolicy(policy *model.RetentionPolicy) (*model.RetentionPolicy, error) {
+	if policy.BoardID != "" {
+		board, err := a.store.GetBoard(policy.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		policy.TeamID = board.TeamID
+	}
+	return a.store.SaveRetentionPolicy(policy)
+}
+
+// DeleteTeamRetentionPolicy deletes the default retention policy of a
+// team, so its boards follow the global retention again.
+func (a *App) DeleteTeamRetentionPolicy(teamID string) error {
+	return a.store.DeleteRetentionPolicy(teamID, "")
+}
+
+// DeleteBoardRetentionPolicy deletes the retention policy of a board, so
+// it follows the policy of its team again.
+func (a *App) DeleteBoardRetentionPolicy(boardID string) error {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+	return a.store.DeleteRetentionPolicy(board.TeamID, boardID)
+}
+
+// RunDataRetention deletes the boards that are past their retention
+// date, and returns what was deleted for each board. The global
+// retention date only applies if data retention is enabled in the
+// configuration, while the retention policies always apply.
+func (a *App) RunDataRetention() (*model.DataRetentionReport, error) {
+	var globalRetentionDate int64
+	if a.config.EnableDataRetention && a.config.DataRetentionDays > 0 {
+		globalRetentionDate = utils.GetMillisForTime(time.Now().AddDate(0, 0, -a.config.DataRetentionDays))
+	}
+
+	report, err := a.store.RunDataRetention(globalRetentionDate, dataRetentionBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		for _, board := range report.Boards {
+			a.wsAdapter.BroadcastBoardDelete(board.TeamID, board.BoardID)
+		}
+		return nil
+	})
+
+	a.logger.Info("Data retention completed",
+		mlog.Int("deletedBoards", len(report.Boards)),
+		mlog.Int("legalHoldBoards", len(report.LegalHoldBoardIDs)),
+		mlog.Int("deletedRows", report.TotalDeleted),
+	)
+	return report, nil
+}
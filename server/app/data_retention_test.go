@@ -0,0 +1,70 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func TestRunDataRetention(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("only the policies apply when data retention is disabled", func(t *testing.T) {
+		th.App.config.EnableDataRetention = false
+		th.Store.EXPECT().RunDataRetention(int64(0), int64(dataRetentionBatchSize)).
+			Return(&model.DataRetentionReport{Boards: []*model.BoardRetentionResult{}}, nil)
+
+		report, err := th.App.RunDataRetention()
+		require.NoError(t, err)
+		require.Empty(t, report.Boards)
+	})
+
+	t.Run("the global retention date comes from the configuration", func(t *testing.T) {
+		th.App.config.EnableDataRetention = true
+		th.App.config.DataRetentionDays = 30
+		defer func() { th.App.config.EnableDataRetention = false }()
+
+		expected := utils.GetMillisForTime(time.Now().AddDate(0, 0, -30))
+		th.Store.EXPECT().RunDataRetention(gomock.Any(), int64(dataRetentionBatchSize)).
+			DoAndReturn(func(globalRetentionDate int64, batchSize int64) (*model.DataRetentionReport, error) {
+				require.InDelta(t, expected, globalRetentionDate, float64(time.Minute/time.Millisecond))
+				return &model.DataRetentionReport{
+					GlobalRetentionDate: globalRetentionDate,
+					Boards:              []*model.BoardRetentionResult{{BoardID: "board-id", TeamID: "team-id"}},
+				}, nil
+			})
+		th.Store.EXPECT().GetMembersForBoard("board-id").Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		report, err := th.App.RunDataRetention()
+		require.NoError(t, err)
+		require.Len(t, report.Boards, 1)
+	})
+}
+
+func TestSaveRetentionPolicy(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("board policies get the team of the board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-id").Return(&model.Board{ID: "board-id", TeamID: "team-id"}, nil)
+		th.Store.EXPECT().SaveRetentionPolicy(&model.RetentionPolicy{TeamID: "team-id", BoardID: "board-id", LegalHold: true}).
+			Return(&model.RetentionPolicy{TeamID: "team-id", BoardID: "board-id", LegalHold: true, UpdateAt: 1}, nil)
+
+		policy, err := th.App.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: "other-team-id", BoardID: "board-id", LegalHold: true})
+		require.NoError(t, err)
+		require.Equal(t, "team-id", policy.TeamID)
+	})
+
+	t.Run("board policies require the board to exist", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("missing-board-id").Return(nil, model.NewErrNotFound("board ID=missing-board-id"))
+
+		_, err := th.App.SaveRetentionPolicy(&model.RetentionPolicy{BoardID: "missing-board-id", RetentionDays: 10})
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
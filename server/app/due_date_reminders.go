@@ -0,0 +1,243 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// dueDateReminderWindow is how long after its time a reminder can still be
+// sent, so that enabling the reminders of a board doesn't send reminders
+// for all its past due dates.
+const dueDateReminderWindow = 24 * time.Hour
+
+func (a *App) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error) {
+	return a.store.GetDueDateReminderSettings(boardID)
+}
+
+// SaveDueDateReminderSettings saves the reminder settings of a board,
+// checking that their properties belong to the board.
+func (a *App) SaveDueDateReminderSettings(settings *model.DueDateReminderSettings, userID string) (*model.DueDateReminderSettings, error) {
+	if err := settings.IsValid(); err != nil {
+		return nil, err
+	}
+	board, err := a.store.GetBoard(settings.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	if err = settings.IsValidForSchema(schema); err != nil {
+		return nil, err
+	}
+
+	settings.ModifiedBy = userID
+	return a.store.SaveDueDateReminderSettings(settings)
+}
+
+func (a *App) DeleteDueDateReminderSettings(boardID string) error {
+	return a.store.DeleteDueDateReminderSettings(boardID)
+}
+
+// GetOverdueCards returns the cards of a board that are past the due date
+// of a date property and aren't done, earliest due first. Due days are
+// over at midnight in the time zone of the user. The date property is the
+// one of the reminder settings of the board if none is given.
+func (a *App) GetOverdueCards(boardID, propertyID, userID string) ([]*model.Card, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if propertyID == "" {
+		settings, err := a.store.GetDueDateReminderSettings(boardID)
+		if model.IsErrNotFound(err) {
+			return nil, model.NewErrBadRequest("a date property is required when the board has no due date reminders")
+		}
+		if err != nil {
+			return nil, err
+		}
+		propertyID = settings.DatePropertyID
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+	if prop, ok := schema[propertyID]; !ok || prop.Type != "date" {
+		return nil, model.NewErrBadRequest("the property must be a date property of the board")
+	}
+
+	blocks, err := a.store.GetBlocksWithType(boardID, model.TypeCard)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(a.userLocation(userID))
+	cards := []*model.Card{}
+	dueAt := map[string]int64{}
+	for _, block := range blocks {
+		due, ok := cardDueDate(block, propertyID)
+		if !ok || !due.IsOverdue(now) || isCardTemplate(block) || schema.IsCardDone(block) {
+			continue
+		}
+		card, err := model.Block2Card(block)
+		if err != nil {
+			a.logger.Warn("Ignoring invalid card", mlog.String("card_id", block.ID), mlog.Err(err))
+			continue
+		}
+		cards = append(cards, card)
+		dueAt[card.ID] = due.At
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		return dueAt[cards[i].ID] < dueAt[cards[j].ID]
+	})
+	return cards, nil
+}
+
+// SendDueDateReminders sends the due date reminders of all the boards
+// whose time is past, in the time zone of each assignee, and returns the
+// number of reminders sent. Each reminder is sent once.
+func (a *App) SendDueDateReminders(now time.Time) (int, error) {
+	allSettings, err := a.store.GetEnabledDueDateReminderSettings()
+	if err != nil {
+		return 0, err
+	}
+
+	locations := map[string]*time.Location{}
+	sent := 0
+	for _, settings := range allSettings {
+		count, err := a.sendBoardDueDateReminders(settings, now, locations)
+		if err != nil {
+			a.logger.Error("Unable to send the due date reminders of a board",
+				mlog.String("board_id", settings.BoardID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		sent += count
+	}
+	return sent, nil
+}
+
+func (a *App) sendBoardDueDateReminders(settings *model.DueDateReminderSettings, now time.Time, locations map[string]*time.Location) (int, error) {
+	board, err := a.store.GetBoard(settings.BoardID)
+	if err != nil {
+		return 0, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return 0, err
+	}
+	if err = settings.IsValidForSchema(schema); err != nil {
+		// the properties were changed after the settings were saved
+		return 0, err
+	}
+
+	blocks, err := a.store.GetBlocksWithType(board.ID, model.TypeCard)
+	if err != nil {
+		return 0, err
+	}
+
+	canView := map[string]bool{}
+	sent := 0
+	for _, card := range blocks {
+		due, ok := cardDueDate(card, settings.DatePropertyID)
+		if !ok || isCardTemplate(card) || schema.IsCardDone(card) {
+			continue
+		}
+
+		for _, userID := range model.CardAssignees(card, settings.AssigneePropertyID) {
+			loc, ok := locations[userID]
+			if !ok {
+				loc = a.userLocation(userID)
+				locations[userID] = loc
+			}
+
+			for _, daysBefore := range settings.DaysBefore {
+				remindAt := settings.ReminderTime(due, daysBefore, loc)
+				if now.Before(remindAt) || !now.Before(remindAt.Add(dueDateReminderWindow)) {
+					continue
+				}
+
+				allowed, ok := canView[userID]
+				if !ok {
+					allowed = a.permissions.HasPermissionToBoard(userID, board.ID, model.PermissionViewBoard)
+					canView[userID] = allowed
+				}
+				if !allowed {
+					continue
+				}
+
+				reminder := &model.DueDateReminder{
+					BoardID:    board.ID,
+					CardID:     card.ID,
+					UserID:     userID,
+					DueAt:      due.At,
+					DaysBefore: daysBefore,
+					SentAt:     utils.GetMillisForTime(now),
+				}
+				inserted, err := a.store.InsertDueDateReminder(reminder)
+				if err != nil {
+					return sent, err
+				}
+				if !inserted {
+					continue
+				}
+				a.notifyDueDate(board, card, reminder)
+				sent++
+			}
+		}
+	}
+	return sent, nil
+}
+
+func (a *App) notifyDueDate(board *model.Board, card *model.Block, reminder *model.DueDateReminder) {
+	if a.notifications == nil {
+		return
+	}
+	a.notifications.BlockChanged(notify.BlockChangeEvent{
+		Action:       notify.DueDateReminder,
+		TeamID:       board.TeamID,
+		Board:        board,
+		Card:         card,
+		BlockChanged: card,
+		ModifiedBy:   &model.BoardMember{BoardID: board.ID, UserID: model.SystemUserID},
+		Reminder:     reminder,
+	})
+}
+
+// userLocation returns the time zone of a user, or UTC if it isn't known.
+func (a *App) userLocation(userID string) *time.Location {
+	timezone, err := a.store.GetUserTimezone(userID)
+	if err != nil || timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		a.logger.Debug("Unknown user timezone", mlog.String("user_id", userID), mlog.String("timezone", timezone))
+		return time.UTC
+	}
+	return loc
+}
+
+func cardDueDate(card *model.Block, propertyID string) (model.DueDate, bool) {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	return model.ParseDueDate(properties[propertyID])
+}
+
+func isCardTemplate(card *model.Block) bool {
+	isTemplate, _ := card.Fields["isTemplate"].(bool)
+	return isTemplate
+}
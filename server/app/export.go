@@ -17,6 +17,10 @@ var (
 )
 
 func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) (errs error) {
+	// the end of the cursor range is taken before reading anything, so the
+	// changes made while exporting are included again in the next export.
+	modifiedUntil := model.GetMillis()
+
 	boards, err := a.getBoardsForArchive(opt.BoardIDs)
 	if err != nil {
 		return err
@@ -33,7 +37,7 @@ func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) (errs e
 		merr.Append(zw.Close())
 	}()
 
-	if err := a.writeArchiveVersion(zw); err != nil {
+	if err := a.writeArchiveVersion(zw, opt, modifiedUntil); err != nil {
 		merr.Append(err)
 		return
 	}
@@ -44,14 +48,24 @@ func (a *App) ExportArchive(w io.Writer, opt model.ExportArchiveOptions) (errs e
 			return
 		}
 	}
+
+	if opt.ModifiedSince > 0 {
+		if err := a.writeArchiveTombstones(zw, boards, opt); err != nil {
+			merr.Append(fmt.Errorf("cannot export tombstones: %w", err))
+			return
+		}
+	}
 	return nil
 }
 
 // writeArchiveVersion writes a version file to the zip.
-func (a *App) writeArchiveVersion(zw *zip.Writer) error {
+func (a *App) writeArchiveVersion(zw *zip.Writer, opt model.ExportArchiveOptions, modifiedUntil int64) error {
 	archiveHeader := model.ArchiveHeader{
-		Version: archiveVersion,
-		Date:    model.GetMillis(),
+		Version:       archiveVersion,
+		Date:          model.GetMillis(),
+		Incremental:   opt.ModifiedSince > 0,
+		ModifiedSince: opt.ModifiedSince,
+		ModifiedUntil: modifiedUntil,
 	}
 	b, _ := json.Marshal(&archiveHeader)
 
@@ -67,7 +81,59 @@ func (a *App) writeArchiveVersion(zw *zip.Writer) error {
 }
 
 // writeArchiveBoard writes a single board to the archive in a zip directory.
+// In an incremental export, only the blocks changed since the cursor are
+// written, and the board is skipped if neither it nor its blocks changed.
 func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.ExportArchiveOptions) error {
+	// TODO: paginate this
+	blocks, err := a.GetBlocksForBoard(board.ID)
+	if err != nil {
+		return err
+	}
+
+	// formula values are computed rather than stored, so they are
+	// exported as they are read by the exporting user
+	schema, err := model.ParsePropertySchema(&board)
+	if err == nil && schema.HasFormulas() {
+		fctx := model.NewFormulaContext(a.userLocation(opt.UserID))
+		for i, block := range blocks {
+			blocks[i] = model.EvaluateBlockFormulas(block, schema, fctx)
+		}
+	}
+
+	// as are the rollups, and the linked cards the exporting user can't
+	// see are left out of the relations
+	blocks, err = a.ResolveBlockRelations(opt.UserID, board.ID, blocks)
+	if err != nil {
+		return err
+	}
+
+	// so is the checklist progress of the cards
+	checklists := model.ComputeChecklistProgress(blocks)
+	for i, block := range blocks {
+		blocks[i] = model.BlockWithChecklist(block, checklists[block.ID])
+	}
+
+	if opt.ModifiedSince > 0 {
+		// cards whose checkboxes changed are exported for their progress
+		changedChecklists := map[string]bool{}
+		for _, block := range blocks {
+			if block.Type == model.TypeCheckbox && block.UpdateAt >= opt.ModifiedSince {
+				changedChecklists[block.ParentID] = true
+			}
+		}
+
+		changedBlocks := make([]*model.Block, 0, len(blocks))
+		for _, block := range blocks {
+			if block.UpdateAt >= opt.ModifiedSince || changedChecklists[block.ID] {
+				changedBlocks = append(changedBlocks, block)
+			}
+		}
+		if board.UpdateAt < opt.ModifiedSince && len(changedBlocks) == 0 {
+			return nil
+		}
+		blocks = changedBlocks
+	}
+
 	// create a directory per board
 	w, err := zw.Create(board.ID + "/board.jsonl")
 	if err != nil {
@@ -81,17 +147,11 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 
 	var files []string
 	// write the board's blocks
-	// TODO: paginate this
-	blocks, err := a.GetBlocksForBoard(board.ID)
-	if err != nil {
-		return err
-	}
-
 	for _, block := range blocks {
 		if err = a.writeArchiveBlockLine(w, block); err != nil {
 			return err
 		}
-		if block.Type == model.TypeImage || block.Type == model.TypeAttachment {
+		if model.IsFileBlock(block) {
 			filename, err2 := extractFilename(block)
 			if err2 != nil {
 				return err2
@@ -120,6 +180,90 @@ func (a *App) writeArchiveBoard(zw *zip.Writer, board model.Board, opt model.Exp
 	return nil
 }
 
+// writeArchiveTombstones writes the boards and blocks deleted since the
+// cursor of an incremental export to the archive.
+func (a *App) writeArchiveTombstones(zw *zip.Writer, boards []model.Board, opt model.ExportArchiveOptions) error {
+	tombstones := []model.ArchiveTombstone{}
+
+	if opt.IncludeDeletedBoards {
+		deletedBoards, err := a.store.GetDeletedBoardsForTeam(opt.TeamID)
+		if err != nil {
+			return err
+		}
+		for _, board := range deletedBoards {
+			// the same boards as the ones of the team exported
+			canView := (opt.IncludePublicBoards && board.Type == model.BoardTypeOpen) ||
+				a.permissions.HasPermissionToBoard(opt.UserID, board.ID, model.PermissionViewBoard)
+			if board.DeleteAt >= opt.ModifiedSince && canView {
+				tombstones = append(tombstones, model.ArchiveTombstone{
+					Type:     model.ArchiveTombstoneBoard,
+					ID:       board.ID,
+					BoardID:  board.ID,
+					DeleteAt: board.DeleteAt,
+				})
+			}
+		}
+	}
+
+	for _, board := range boards {
+		deletedBlocks, err := a.store.GetDeletedBlocksForBoard(board.ID, nil)
+		if err != nil {
+			return err
+		}
+		for _, block := range deletedBlocks {
+			if block.DeleteAt >= opt.ModifiedSince {
+				tombstones = append(tombstones, model.ArchiveTombstone{
+					Type:     model.ArchiveTombstoneBlock,
+					ID:       block.ID,
+					BoardID:  board.ID,
+					DeleteAt: block.DeleteAt,
+				})
+			}
+		}
+	}
+
+	if len(tombstones) == 0 {
+		return nil
+	}
+
+	w, err := zw.Create(archiveTombstonesFile)
+	if err != nil {
+		return err
+	}
+
+	for _, tombstone := range tombstones {
+		if err := a.writeArchiveTombstoneLine(w, tombstone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArchiveTombstoneLine writes a single tombstone to the archive.
+func (a *App) writeArchiveTombstoneLine(w io.Writer, tombstone model.ArchiveTombstone) error {
+	t, err := json.Marshal(&tombstone)
+	if err != nil {
+		return err
+	}
+	line := model.ArchiveLine{
+		Type: "tombstone",
+		Data: t,
+	}
+
+	t, err = json.Marshal(&line)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(newline)
+	return err
+}
+
 // writeArchiveBoardMemberLine writes a single boardMember to the archive.
 func (a *App) writeArchiveBoardMemberLine(w io.Writer, boardMember *model.BoardMember) error {
 	bm, err := json.Marshal(&boardMember)
@@ -52,6 +52,34 @@ func (a *App) SaveFile(reader io.Reader, teamID, boardID, filename string, asTem
 	return newFileName, nil
 }
 
+// saveFileWithName saves a file keeping its name, as when importing an
+// archive that keeps the IDs of its blocks. The file info is only created
+// if the file wasn't saved before.
+func (a *App) saveFileWithName(reader io.Reader, teamID, boardID, filename string, asTemplate bool) error {
+	filePath := getDestinationFilePath(asTemplate, teamID, boardID, filename)
+
+	fileSize, appErr := a.filesBackend.WriteFile(reader, filePath)
+	if appErr != nil {
+		return fmt.Errorf("unable to store the file in the files storage: %w", appErr)
+	}
+
+	fileInfoID := getFileInfoID(strings.Split(filename, ".")[0])
+	_, err := a.store.GetFileInfo(fileInfoID)
+	if err == nil {
+		return nil
+	}
+	if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	fileInfo := model.NewFileInfo(filename)
+	fileInfo.Id = fileInfoID
+	fileInfo.Path = filePath
+	fileInfo.Size = fileSize
+
+	return a.store.SaveFileInfo(fileInfo)
+}
+
 func (a *App) GetFileInfo(filename string) (*mm_model.FileInfo, error) {
 	if len(filename) == 0 {
 		return nil, errEmptyFilename
@@ -192,7 +220,7 @@ func (a *App) CopyAndUpdateCardFiles(boardID, userID string, blocks []*model.Blo
 	blockIDs := make([]string, 0)
 	blockPatches := make([]model.BlockPatch, 0)
 	for _, block := range blocks {
-		if block.Type == model.TypeImage || block.Type == model.TypeAttachment {
+		if model.IsFileBlock(block) {
 			if fileID, ok := block.Fields["fileId"].(string); ok {
 				blockIDs = append(blockIDs, block.ID)
 				blockPatches = append(blockPatches, model.BlockPatch{
@@ -235,7 +263,7 @@ func (a *App) CopyCardFiles(sourceBoardID string, copiedBlocks []*model.Block, a
 	var destBoard *model.Board
 	newFileNames := make(map[string]string)
 	for _, block := range copiedBlocks {
-		if block.Type != model.TypeImage && block.Type != model.TypeAttachment {
+		if !model.IsFileBlock(block) {
 			continue
 		}
 
@@ -299,3 +327,34 @@ func (a *App) CopyCardFiles(sourceBoardID string, copiedBlocks []*model.Block, a
 
 	return newFileNames, nil
 }
+
+// removeBlockFiles removes from the files storage the files referenced by
+// the file blocks. It is used once the blocks are purged, so errors are
+// logged and the remaining files are still removed.
+func (a *App) removeBlockFiles(teamID, boardID string, blocks []*model.Block) {
+	removed := map[string]bool{}
+	for _, block := range blocks {
+		if !model.IsFileBlock(block) {
+			continue
+		}
+
+		fileID, isOk := block.Fields["fileId"].(string)
+		if !isOk {
+			fileID, isOk = block.Fields["attachmentId"].(string)
+		}
+		if !isOk || fileID == "" || removed[fileID] {
+			continue
+		}
+		removed[fileID] = true
+
+		_, filePath, err := a.GetFilePath(teamID, boardID, fileID)
+		if err != nil {
+			a.logger.Error("removeBlockFiles: cannot get the file path", mlog.String("fileID", fileID), mlog.Err(err))
+			continue
+		}
+
+		if err := a.filesBackend.RemoveFile(filePath); err != nil {
+			a.logger.Error("removeBlockFiles: cannot remove the file", mlog.String("path", filePath), mlog.Err(err))
+		}
+	}
+}
@@ -20,9 +20,10 @@ import (
 )
 
 const (
-	archiveVersion    = 2
-	legacyFileBegin   = "{\"version\":1"
-	importMaxFileSize = 1024 * 1024 * 70
+	archiveVersion        = 2
+	archiveTombstonesFile = "tombstones.jsonl"
+	legacyFileBegin       = "{\"version\":1"
+	importMaxFileSize     = 1024 * 1024 * 70
 )
 
 var (
@@ -35,6 +36,8 @@ var (
 //
 // Archives are ZIP files containing a `version.json` file and zero or more
 // directories, each containing a `board.jsonl` and zero or more image files.
+// Incremental archives also contain a `tombstones.jsonl` file, and can only
+// be imported with the `Incremental` option.
 func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 	// peek at the first bytes to see if this is a legacy archive format
 	br := bufio.NewReader(r)
@@ -55,7 +58,9 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 		hdr, err := zr.Next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				a.fixImagesAttachments(boardMap, fileMap, opt.TeamID, opt.ModifiedBy)
+				if !opt.Incremental {
+					a.fixImagesAttachments(boardMap, fileMap, opt.TeamID, opt.ModifiedBy)
+				}
 				a.logger.Debug("import archive - done", mlog.Int("boards_imported", len(boardMap)))
 				return nil
 			}
@@ -65,17 +70,32 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 		dir, filename := filepath.Split(hdr.Name)
 		dir = path.Clean(dir)
 
+		if hdr.Name == archiveTombstonesFile {
+			if err := a.importArchiveTombstones(zr, opt); err != nil {
+				return fmt.Errorf("cannot import tombstones: %w", err)
+			}
+			continue
+		}
+
 		switch filename {
 		case "version.json":
-			ver, errVer := parseVersionFile(zr)
+			header, errVer := parseVersionFile(zr)
 			if errVer != nil {
 				return errVer
 			}
-			if ver != archiveVersion {
-				return model.NewErrUnsupportedArchiveVersion(ver, archiveVersion)
+			if header.Version != archiveVersion {
+				return model.NewErrUnsupportedArchiveVersion(header.Version, archiveVersion)
+			}
+			if header.Incremental && !opt.Incremental {
+				return model.NewErrBadRequest("an incremental archive can only be imported in incremental mode")
 			}
 		case "board.jsonl":
-			board, err := a.ImportBoardJSONL(zr, opt)
+			var board *model.Board
+			if opt.Incremental {
+				board, err = a.importIncrementalBoardJSONL(zr, opt)
+			} else {
+				board, err = a.ImportBoardJSONL(zr, opt)
+			}
 			if err != nil {
 				return fmt.Errorf("cannot import board %s: %w", dir, err)
 			}
@@ -90,6 +110,13 @@ func (a *App) ImportArchive(r io.Reader, opt model.ImportArchiveOptions) error {
 				)
 				continue
 			}
+			if opt.Incremental {
+				// the blocks keep their IDs, so the files keep their names
+				if err := a.saveFileWithName(zr, opt.TeamID, board.ID, filename, board.IsTemplate); err != nil {
+					return fmt.Errorf("cannot import file %s for board %s: %w", filename, dir, err)
+				}
+				continue
+			}
 			newFileName, err := a.SaveFile(zr, opt.TeamID, board.ID, filename, board.IsTemplate)
 			if err != nil {
 				return fmt.Errorf("cannot import file %s for board %s: %w", filename, dir, err)
@@ -125,14 +152,17 @@ func (a *App) fixImagesAttachments(boardMap map[string]*model.Board, fileMap map
 		}
 
 		for _, block := range newBlocks {
-			if block.Type == "image" || block.Type == "attachment" {
+			if model.IsFileBlock(block) {
 				fieldName := "fileId"
-				oldID := block.Fields[fieldName]
+				oldID, ok := block.Fields[fieldName].(string)
+				if !ok {
+					continue
+				}
 				blockIDs = append(blockIDs, block.ID)
 
 				blockPatches = append(blockPatches, model.BlockPatch{
 					UpdatedFields: map[string]interface{}{
-						fieldName: fileMap[oldID.(string)],
+						fieldName: fileMap[oldID],
 					},
 				})
 			}
@@ -149,6 +179,180 @@ func (a *App) fixImagesAttachments(boardMap map[string]*model.Board, fileMap map
 // ImportBoardJSONL imports a JSONL file containing blocks for one board. The resulting
 // board id is returned.
 func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*model.Board, error) {
+	boardsAndBlocks, boardMembers, err := a.parseBoardJSONL(r, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	a.fixBoardsandBlocks(boardsAndBlocks, opt)
+
+	boardsAndBlocks, err = model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
+	if err != nil {
+		return nil, fmt.Errorf("error generating archive block IDs: %w", err)
+	}
+
+	// the imported members are added once the boards are created
+	memberIDs := []string{opt.ModifiedBy}
+	for _, boardMember := range boardMembers {
+		memberIDs = append(memberIDs, boardMember.UserID)
+	}
+	boardsAndBlocks, err = a.createBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false, memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+	}
+
+	// add users to all the new boards (if not the fake system user).
+	for _, board := range boardsAndBlocks.Boards {
+		if err := a.addImportedMembersToBoard(board.ID, boardMembers, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	// find new board id
+	for _, board := range boardsAndBlocks.Boards {
+		return board, nil
+	}
+	return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+}
+
+// importIncrementalBoardJSONL imports a JSONL file containing blocks for one
+// board keeping their IDs, so the board and the blocks imported from a
+// previous archive are updated instead of copied. Updating an existing board
+// requires the importing user to be allowed to edit it.
+func (a *App) importIncrementalBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*model.Board, error) {
+	boardsAndBlocks, boardMembers, err := a.parseBoardJSONL(r, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	a.fixBoardsandBlocks(boardsAndBlocks, opt)
+
+	if len(boardsAndBlocks.Boards) == 0 {
+		return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
+	}
+	board := boardsAndBlocks.Boards[0]
+
+	existingBoard, err := a.store.GetBoard(board.ID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+	if existingBoard != nil {
+		if existingBoard.TeamID != opt.TeamID {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("board %s belongs to another team", board.ID))
+		}
+		if err = a.checkIncrementalImportPermissions(existingBoard, board, len(boardsAndBlocks.Blocks) > 0, opt.ModifiedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	blockIDs := make([]string, 0, len(boardsAndBlocks.Blocks))
+	for _, block := range boardsAndBlocks.Blocks {
+		blockIDs = append(blockIDs, block.ID)
+	}
+	existingBlocks, err := a.store.GetBlocksByIDs(blockIDs)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+	for _, block := range existingBlocks {
+		if block.BoardID != board.ID {
+			return nil, model.NewErrBadRequest(fmt.Sprintf("block %s belongs to another board", block.ID))
+		}
+	}
+
+	if err = model.ValidateFormulas(board); err != nil {
+		return nil, err
+	}
+	if err = model.ValidateRollups(board); err != nil {
+		return nil, err
+	}
+
+	board, err = a.store.InsertBoard(board, opt.ModifiedBy)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting archive board: %w", err)
+	}
+
+	// This can be synchronous because this action is not common
+	a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+
+	// the members of a board that already existed are kept as they are. The
+	// members of a new board are added before its blocks, so the person
+	// properties of its cards can be validated.
+	if existingBoard == nil {
+		if !board.IsTemplate {
+			if err = a.addBoardsToDefaultCategory(opt.ModifiedBy, board.TeamID, []*model.Board{board}); err != nil {
+				return nil, err
+			}
+		}
+		if err = a.addImportedMembersToBoard(board.ID, boardMembers, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	// the blocks go through the app so their card properties are validated,
+	// their relations synced and their changes notified
+	existingBlocksByID := make(map[string]*model.Block, len(existingBlocks))
+	for _, block := range existingBlocks {
+		existingBlocksByID[block.ID] = block
+	}
+	newBlocks := []*model.Block{}
+	blockPatches := &model.BlockPatchBatch{}
+	for _, block := range boardsAndBlocks.Blocks {
+		existingBlock, ok := existingBlocksByID[block.ID]
+		if !ok {
+			newBlocks = append(newBlocks, block)
+			continue
+		}
+		blockPatches.BlockIDs = append(blockPatches.BlockIDs, block.ID)
+		blockPatches.BlockPatches = append(blockPatches.BlockPatches, blockReplacementPatch(existingBlock, block))
+	}
+
+	if _, err = a.InsertBlocks(newBlocks, opt.ModifiedBy); err != nil {
+		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
+	}
+	if len(blockPatches.BlockIDs) > 0 {
+		if err = a.PatchBlocks(board.TeamID, blockPatches, opt.ModifiedBy); err != nil {
+			return nil, fmt.Errorf("error updating archive blocks: %w", err)
+		}
+	}
+	return board, nil
+}
+
+// blockReplacementPatch returns the patch that replaces the content of
+// oldBlock with the one of block.
+func blockReplacementPatch(oldBlock, block *model.Block) model.BlockPatch {
+	patch := model.BlockPatch{
+		ParentID:      &block.ParentID,
+		Schema:        &block.Schema,
+		Type:          &block.Type,
+		Title:         &block.Title,
+		UpdatedFields: block.Fields,
+	}
+	for field := range oldBlock.Fields {
+		if _, ok := block.Fields[field]; !ok {
+			patch.DeletedFields = append(patch.DeletedFields, field)
+		}
+	}
+	return patch
+}
+
+// checkIncrementalImportPermissions checks that the importing user can
+// update an existing board with the board and blocks of an archive.
+func (a *App) checkIncrementalImportPermissions(existingBoard, board *model.Board, hasBlocks bool, userID string) error {
+	if !a.permissions.HasPermissionToBoard(userID, existingBoard.ID, model.PermissionManageBoardProperties) {
+		return model.NewErrPermission("access denied to update board " + existingBoard.ID)
+	}
+	if board.Type != existingBoard.Type && !a.permissions.HasPermissionToBoard(userID, existingBoard.ID, model.PermissionManageBoardType) {
+		return model.NewErrPermission("access denied to change the type of board " + existingBoard.ID)
+	}
+	if hasBlocks && !a.permissions.HasPermissionToBoard(userID, existingBoard.ID, model.PermissionManageBoardCards) {
+		return model.NewErrPermission("access denied to update the cards of board " + existingBoard.ID)
+	}
+	return nil
+}
+
+// parseBoardJSONL reads a JSONL file containing blocks for one board, and
+// returns them with the members of the board that exist on this server.
+func (a *App) parseBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
 	// TODO: Stream this once `model.GenerateBlockIDs` can take a stream of blocks.
 	//       We don't want to load the whole file in memory, even though it's a single board.
 	boardsAndBlocks := &model.BoardsAndBlocks{
@@ -170,7 +374,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 	firstLine := true
 	for scanner.Scan() {
 		if lineReader.N <= 0 {
-			return nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, errSizeLimitExceeded)
+			return nil, nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, errSizeLimitExceeded)
 		}
 
 		line := bytes.TrimSpace(scanner.Bytes())
@@ -186,7 +390,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 			if !skip {
 				var archiveLine model.ArchiveLine
 				if err := json.Unmarshal(line, &archiveLine); err != nil {
-					return nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, err)
+					return nil, nil, fmt.Errorf("error parsing archive line %d: %w", lineNum, err)
 				}
 
 				// first line must be a board
@@ -198,7 +402,7 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 				case "board":
 					var board model.Board
 					if err2 := json.Unmarshal(archiveLine.Data, &board); err2 != nil {
-						return nil, fmt.Errorf("invalid board in archive line %d: %w", lineNum, err2)
+						return nil, nil, fmt.Errorf("invalid board in archive line %d: %w", lineNum, err2)
 					}
 					board.ModifiedBy = userID
 					board.UpdateAt = now
@@ -209,33 +413,37 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 					// legacy archives encoded boards as blocks; we need to convert them to real boards.
 					var block *model.Block
 					if err2 := json.Unmarshal(archiveLine.Data, &block); err2 != nil {
-						return nil, fmt.Errorf("invalid board block in archive line %d: %w", lineNum, err2)
+						return nil, nil, fmt.Errorf("invalid board block in archive line %d: %w", lineNum, err2)
 					}
 					block.ModifiedBy = userID
 					block.UpdateAt = now
 					board, err := a.blockToBoard(block, opt)
 					if err != nil {
-						return nil, fmt.Errorf("cannot convert archive line %d to block: %w", lineNum, err)
+						return nil, nil, fmt.Errorf("cannot convert archive line %d to block: %w", lineNum, err)
 					}
 					boardsAndBlocks.Boards = append(boardsAndBlocks.Boards, board)
 					boardID = board.ID
 				case "block":
 					var block *model.Block
 					if err2 := json.Unmarshal(archiveLine.Data, &block); err2 != nil {
-						return nil, fmt.Errorf("invalid block in archive line %d: %w", lineNum, err2)
+						return nil, nil, fmt.Errorf("invalid block in archive line %d: %w", lineNum, err2)
 					}
 					block.ModifiedBy = userID
 					block.UpdateAt = now
 					block.BoardID = boardID
+					if block.Type == model.TypeCard {
+						// the checklist progress is computed, not stored
+						delete(block.Fields, model.CardFieldChecklist)
+					}
 					boardsAndBlocks.Blocks = append(boardsAndBlocks.Blocks, block)
 				case "boardMember":
 					var boardMember *model.BoardMember
 					if err2 := json.Unmarshal(archiveLine.Data, &boardMember); err2 != nil {
-						return nil, fmt.Errorf("invalid board Member in archive line %d: %w", lineNum, err2)
+						return nil, nil, fmt.Errorf("invalid board Member in archive line %d: %w", lineNum, err2)
 					}
 					boardMembers = append(boardMembers, boardMember)
 				default:
-					return nil, model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
+					return nil, nil, model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
 				}
 				firstLine = false
 			}
@@ -243,7 +451,11 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 	}
 
 	if errRead := scanner.Err(); errRead != nil {
-		return nil, fmt.Errorf("error reading archive line %d: %w", lineNum, errRead)
+		return nil, nil, fmt.Errorf("error reading archive line %d: %w", lineNum, errRead)
+	}
+
+	if err := model.ValidateBlockParents(boardsAndBlocks.Blocks, nil); err != nil {
+		return nil, nil, fmt.Errorf("invalid block in archive: %w", err)
 	}
 
 	// loop to remove the people how are not part of the team and system
@@ -253,53 +465,124 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 		}
 	}
 
-	a.fixBoardsandBlocks(boardsAndBlocks, opt)
+	return boardsAndBlocks, boardMembers, nil
+}
 
-	var err error
-	boardsAndBlocks, err = model.GenerateBoardsAndBlocksIDs(boardsAndBlocks, a.logger)
-	if err != nil {
-		return nil, fmt.Errorf("error generating archive block IDs: %w", err)
+// addImportedMembersToBoard makes the importing user an admin of an
+// imported board, and adds the members of the archive to it.
+func (a *App) addImportedMembersToBoard(boardID string, boardMembers []*model.BoardMember, opt model.ImportArchiveOptions) error {
+	// make sure an admin user gets added
+	adminMember := &model.BoardMember{
+		BoardID:     boardID,
+		UserID:      opt.ModifiedBy,
+		SchemeAdmin: true,
+	}
+	if _, err := a.AddMemberToBoard(adminMember); err != nil {
+		return fmt.Errorf("cannot add adminMember to board: %w", err)
+	}
+	for _, boardMember := range boardMembers {
+		bm := &model.BoardMember{
+			BoardID:         boardID,
+			UserID:          boardMember.UserID,
+			Roles:           boardMember.Roles,
+			MinimumRole:     boardMember.MinimumRole,
+			SchemeAdmin:     boardMember.SchemeAdmin,
+			SchemeEditor:    boardMember.SchemeEditor,
+			SchemeCommenter: boardMember.SchemeCommenter,
+			SchemeViewer:    boardMember.SchemeViewer,
+			Synthetic:       boardMember.Synthetic,
+		}
+		if _, err := a.AddMemberToBoard(bm); err != nil {
+			return fmt.Errorf("cannot add member to board: %w", err)
+		}
 	}
+	return nil
+}
 
-	boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false)
-	if err != nil {
-		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
-	}
+// importArchiveTombstones deletes the boards and blocks listed in the
+// tombstones of an incremental archive. The ones that don't exist or that
+// belong to another team are skipped, and the importing user must be allowed
+// to delete the others.
+func (a *App) importArchiveTombstones(r io.Reader, opt model.ImportArchiveOptions) error {
+	lineReader := &io.LimitedReader{R: r, N: importMaxFileSize + 1}
+	scanner := bufio.NewScanner(lineReader)
 
-	// add users to all the new boards (if not the fake system user).
-	for _, board := range boardsAndBlocks.Boards {
-		// make sure an admin user gets added
-		adminMember := &model.BoardMember{
-			BoardID:     board.ID,
-			UserID:      opt.ModifiedBy,
-			SchemeAdmin: true,
-		}
-		if _, err2 := a.AddMemberToBoard(adminMember); err2 != nil {
-			return nil, fmt.Errorf("cannot add adminMember to board: %w", err2)
-		}
-		for _, boardMember := range boardMembers {
-			bm := &model.BoardMember{
-				BoardID:         board.ID,
-				UserID:          boardMember.UserID,
-				Roles:           boardMember.Roles,
-				MinimumRole:     boardMember.MinimumRole,
-				SchemeAdmin:     boardMember.SchemeAdmin,
-				SchemeEditor:    boardMember.SchemeEditor,
-				SchemeCommenter: boardMember.SchemeCommenter,
-				SchemeViewer:    boardMember.SchemeViewer,
-				Synthetic:       boardMember.Synthetic,
-			}
-			if _, err2 := a.AddMemberToBoard(bm); err2 != nil {
-				return nil, fmt.Errorf("cannot add member to board: %w", err2)
-			}
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if lineReader.N <= 0 {
+			return fmt.Errorf("error parsing tombstone line %d: %w", lineNum, errSizeLimitExceeded)
+		}
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var archiveLine model.ArchiveLine
+		if err := json.Unmarshal(line, &archiveLine); err != nil {
+			return fmt.Errorf("error parsing tombstone line %d: %w", lineNum, err)
+		}
+		if archiveLine.Type != "tombstone" {
+			return model.NewErrUnsupportedArchiveLineType(lineNum, archiveLine.Type)
+		}
+
+		var tombstone model.ArchiveTombstone
+		if err := json.Unmarshal(archiveLine.Data, &tombstone); err != nil {
+			return fmt.Errorf("invalid tombstone in line %d: %w", lineNum, err)
+		}
+
+		if err := a.applyArchiveTombstone(tombstone, opt); err != nil {
+			return fmt.Errorf("cannot apply tombstone in line %d: %w", lineNum, err)
 		}
 	}
 
-	// find new board id
-	for _, board := range boardsAndBlocks.Boards {
-		return board, nil
+	if errRead := scanner.Err(); errRead != nil {
+		return fmt.Errorf("error reading tombstone line %d: %w", lineNum, errRead)
+	}
+	return nil
+}
+
+func (a *App) applyArchiveTombstone(tombstone model.ArchiveTombstone, opt model.ImportArchiveOptions) error {
+	board, err := a.store.GetBoard(tombstone.BoardID)
+	if model.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if board.TeamID != opt.TeamID {
+		a.logger.Warn("skipping tombstone of another team",
+			mlog.String("type", tombstone.Type),
+			mlog.String("id", tombstone.ID),
+		)
+		return nil
+	}
+
+	switch tombstone.Type {
+	case model.ArchiveTombstoneBoard:
+		if !a.permissions.HasPermissionToBoard(opt.ModifiedBy, board.ID, model.PermissionDeleteBoard) {
+			return model.NewErrPermission("access denied to delete board " + board.ID)
+		}
+		return a.DeleteBoard(board.ID, opt.ModifiedBy)
+	case model.ArchiveTombstoneBlock:
+		block, err := a.store.GetBlock(tombstone.ID)
+		if model.IsErrNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if block.BoardID != board.ID {
+			return nil
+		}
+		if !a.permissions.HasPermissionToBoard(opt.ModifiedBy, board.ID, model.PermissionManageBoardCards) {
+			return model.NewErrPermission("access denied to delete block " + block.ID)
+		}
+		return a.DeleteBlockAndNotify(block.ID, opt.ModifiedBy, true)
+	default:
+		return model.NewErrBadRequest("invalid tombstone type " + tombstone.Type)
 	}
-	return nil, fmt.Errorf("missing board in archive: %w", model.ErrInvalidBoardBlock)
 }
 
 // fixBoardsandBlocks allows the caller of `ImportArchive` to modify or filters boards and blocks being
@@ -453,15 +736,15 @@ func arrayMapsValue(m map[string]interface{}, key string) ([]map[string]interfac
 	return arr, true
 }
 
-func parseVersionFile(r io.Reader) (int, error) {
+func parseVersionFile(r io.Reader) (*model.ArchiveHeader, error) {
 	file, err := io.ReadAll(r)
 	if err != nil {
-		return 0, fmt.Errorf("cannot read version.json: %w", err)
+		return nil, fmt.Errorf("cannot read version.json: %w", err)
 	}
 
 	var header model.ArchiveHeader
 	if err := json.Unmarshal(file, &header); err != nil {
-		return 0, fmt.Errorf("cannot parse version.json: %w", err)
+		return nil, fmt.Errorf("cannot parse version.json: %w", err)
 	}
-	return header.Version, nil
+	return &header, nil
 }
@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func (a *App) GetIncomingWebhook(webhookID string) (*model.IncomingWebhook, error) {
+	return a.store.GetIncomingWebhook(webhookID)
+}
+
+func (a *App) GetIncomingWebhooksForBoard(boardID string) ([]*model.IncomingWebhook, error) {
+	return a.store.GetIncomingWebhooksForBoard(boardID)
+}
+
+// CreateIncomingWebhook creates an incoming webhook as the user, with a
+// new token. The cards are created and updated by the user.
+func (a *App) CreateIncomingWebhook(webhook *model.IncomingWebhook, userID string) (*model.IncomingWebhook, error) {
+	webhook.ID = ""
+	webhook.Token = utils.NewID(utils.IDTypeToken)
+	webhook.CreatedBy = userID
+	webhook.ModifiedBy = userID
+
+	if err := a.validateIncomingWebhookMapping(webhook); err != nil {
+		return nil, err
+	}
+	return a.store.InsertIncomingWebhook(webhook)
+}
+
+func (a *App) PatchIncomingWebhook(webhook *model.IncomingWebhook, patch *model.IncomingWebhookPatch, userID string) (*model.IncomingWebhook, error) {
+	patched := patch.Patch(webhook)
+	patched.ModifiedBy = userID
+
+	if patch.Mapping != nil {
+		if err := a.validateIncomingWebhookMapping(patched); err != nil {
+			return nil, err
+		}
+	}
+	return a.store.UpdateIncomingWebhook(patched)
+}
+
+func (a *App) DeleteIncomingWebhook(webhookID string) error {
+	return a.store.DeleteIncomingWebhook(webhookID)
+}
+
+// ExecuteIncomingWebhook maps a JSON payload posted to an incoming webhook
+// to a card of its board, which is created, or updated if the webhook has
+// an external ID property and a card with the same external ID exists.
+// The cards are created and patched as the creator of the webhook, with
+// the notifications of the changes.
+func (a *App) ExecuteIncomingWebhook(webhook *model.IncomingWebhook, payload []byte) (*model.IncomingWebhookResult, error) {
+	if webhook.Disabled {
+		return nil, model.NewErrForbidden("the incoming webhook is disabled")
+	}
+	userID := webhook.CreatedBy
+	if !a.permissions.HasPermissionToBoard(userID, webhook.BoardID, model.PermissionManageBoardCards) {
+		return nil, model.NewErrForbidden("the creator of the incoming webhook can't manage the cards of the board")
+	}
+
+	board, err := a.store.GetBoard(webhook.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	variables, err := model.ParseIncomingWebhookPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	ctx := model.PlaceholderContext{
+		Now:           time.Now().In(a.userLocation(userID)),
+		CreatorID:     userID,
+		BoardTitle:    board.Title,
+		Variables:     variables,
+		RemoveUnknown: true,
+	}
+	if user, uErr := a.store.GetUserByID(userID); uErr == nil {
+		ctx.CreatorName = user.Username
+	} else {
+		a.logger.Debug("Unknown creator of incoming webhook", mlog.String("user_id", userID), mlog.Err(uErr))
+	}
+
+	mapped := webhook.Mapping.Resolve(ctx, schema)
+
+	if webhook.Mapping.ExternalIDProperty != "" {
+		if mapped.ExternalID == "" {
+			return nil, model.NewErrBadRequest("the payload has no external ID")
+		}
+		existing, fErr := a.findCardByExternalID(board.ID, webhook.Mapping.ExternalIDProperty, mapped.ExternalID)
+		if fErr != nil {
+			return nil, fErr
+		}
+		if existing != nil {
+			return a.updateIncomingWebhookCard(existing, mapped, userID)
+		}
+	}
+	return a.createIncomingWebhookCard(board, mapped, userID)
+}
+
+// createIncomingWebhookCard creates the card a payload is mapped to, with
+// its content as a text block.
+func (a *App) createIncomingWebhookCard(board *model.Board, mapped *model.IncomingWebhookCard, userID string) (*model.IncomingWebhookResult, error) {
+	card := &model.Card{
+		Title:      mapped.Title,
+		Properties: mapped.Properties,
+	}
+	var text *model.Block
+	if mapped.Content != "" {
+		text = &model.Block{
+			ID:        utils.NewID(utils.IDTypeBlock),
+			BoardID:   board.ID,
+			Type:      model.TypeText,
+			Title:     mapped.Content,
+			Fields:    map[string]interface{}{},
+			CreatedBy: userID,
+		}
+		card.ContentOrder = []string{text.ID}
+	}
+	card.PopulateWithBoardID(board.ID)
+	if err := card.CheckValid(); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	newCard, err := a.CreateCard(card, board.ID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if text != nil {
+		text.ParentID = newCard.ID
+		text.CreateAt = newCard.CreateAt
+		if err := a.InsertBlockAndNotify(text, userID, false); err != nil {
+			return nil, err
+		}
+	}
+	return &model.IncomingWebhookResult{CardID: newCard.ID, Created: true}, nil
+}
+
+// updateIncomingWebhookCard updates the title and the mapped properties
+// of a card. The other properties and the content aren't changed.
+func (a *App) updateIncomingWebhookCard(card *model.Block, mapped *model.IncomingWebhookCard, userID string) (*model.IncomingWebhookResult, error) {
+	// the patch replaces all the properties of the card
+	oldProperties, _ := card.Fields["properties"].(map[string]interface{})
+	properties := make(map[string]interface{}, len(oldProperties)+len(mapped.Properties))
+	for id, value := range oldProperties {
+		properties[id] = value
+	}
+	for id, value := range mapped.Properties {
+		properties[id] = value
+	}
+
+	patch := &model.CardPatch{UpdatedProperties: properties}
+	if mapped.Title != "" {
+		patch.Title = &mapped.Title
+	}
+
+	if _, err := a.PatchCard(patch, card.ID, userID, false); err != nil {
+		return nil, err
+	}
+	return &model.IncomingWebhookResult{CardID: card.ID, Created: false}, nil
+}
+
+// findCardByExternalID returns the card of a board whose external ID
+// property has a value, or nil if there's none. The card templates are
+// ignored. The card is looked up with a card query, which filters the
+// text properties in the database.
+func (a *App) findCardByExternalID(boardID, propertyID, externalID string) (*model.Block, error) {
+	result, err := a.store.QueryCards(boardID, model.QueryCardsOptions{
+		Filter: &model.FilterGroup{
+			Operation: model.FilterGroupOperationAnd,
+			Filters: []model.FilterGroupItem{{Clause: &model.FilterClause{
+				PropertyID: propertyID,
+				Condition:  model.FilterConditionIncludes,
+				Values:     []string{externalID},
+			}}},
+		},
+		PerPage: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Cards) == 0 {
+		return nil, nil
+	}
+	// the queried cards hold the values computed by the formulas, which
+	// must not be saved with the card
+	return a.store.GetBlock(result.Cards[0].ID)
+}
+
+// validateIncomingWebhookMapping checks that the mapping of an incoming
+// webhook only sets properties of the schema of its board.
+func (a *App) validateIncomingWebhookMapping(webhook *model.IncomingWebhook) error {
+	if err := webhook.IsValid(); err != nil {
+		return err
+	}
+	board, err := a.store.GetBoard(webhook.BoardID)
+	if err != nil {
+		return err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return model.NewErrBadRequest(err.Error())
+	}
+	return webhook.Mapping.IsValidForSchema(schema)
+}
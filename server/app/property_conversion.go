@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// ConvertCardProperty changes the type of a card property of a board and
+// converts the values of its cards. The property and the cards are patched
+// in a single transaction, which fails with a conflict if any of them is
+// modified during the conversion. If some values cannot be converted, the
+// conversion fails with an ErrUnconvertibleValues listing them, unless it
+// drops them, in which case they are removed from the cards and reported.
+func (a *App) ConvertCardProperty(boardID, propertyID string, conversion *model.PropertyConversion, userID string) (*model.PropertyConversionResult, error) {
+	board, err := a.store.GetBoard(boardID)
+	if model.IsErrNotFound(err) {
+		return nil, model.NewErrNotFound("board ID=" + boardID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var prop map[string]interface{}
+	for _, p := range board.CardProperties {
+		if id, _ := p["id"].(string); id == propertyID {
+			prop = p
+			break
+		}
+	}
+	if prop == nil {
+		return nil, model.NewErrNotFound("card property ID=" + propertyID)
+	}
+
+	converter, err := model.NewPropertyConverter(prop, conversion.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := a.store.GetBlocks(model.QueryBlocksOptions{BoardID: boardID, BlockType: model.TypeCard})
+	if err != nil {
+		return nil, err
+	}
+	// the options created from text values are ordered by the first card
+	// that uses them
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].CreateAt != cards[j].CreateAt {
+			return cards[i].CreateAt < cards[j].CreateAt
+		}
+		return cards[i].ID < cards[j].ID
+	})
+
+	result := &model.PropertyConversionResult{
+		Unconvertible: []model.UnconvertibleValue{},
+		DryRun:        conversion.DryRun,
+	}
+	pbab := &model.PatchBoardsAndBlocks{
+		BlockIDs:     []string{},
+		BlockPatches: []*model.BlockPatch{},
+	}
+
+	for _, card := range cards {
+		properties, _ := card.Fields["properties"].(map[string]interface{})
+		value, ok := properties[propertyID]
+		if !ok {
+			continue
+		}
+
+		converted, err := converter.Convert(value)
+		if err != nil {
+			result.Unconvertible = append(result.Unconvertible, model.UnconvertibleValue{
+				CardID: card.ID,
+				Value:  value,
+				Reason: err.Error(),
+			})
+			converted = nil
+		}
+		if reflect.DeepEqual(converted, value) {
+			continue
+		}
+
+		newProperties := make(map[string]interface{}, len(properties))
+		for k, v := range properties {
+			newProperties[k] = v
+		}
+		if converted == nil {
+			delete(newProperties, propertyID)
+		} else {
+			newProperties[propertyID] = converted
+		}
+
+		updateAt := card.UpdateAt
+		pbab.BlockIDs = append(pbab.BlockIDs, card.ID)
+		pbab.BlockPatches = append(pbab.BlockPatches, &model.BlockPatch{
+			UpdatedFields:    map[string]interface{}{"properties": newProperties},
+			ExpectedUpdateAt: &updateAt,
+		})
+		if err == nil {
+			result.ConvertedCards++
+		}
+	}
+
+	result.Property = converter.Property()
+	if conversion.DryRun {
+		return result, nil
+	}
+	if len(result.Unconvertible) != 0 && !conversion.DropUnconvertible {
+		return nil, model.NewErrUnconvertibleValues(result.Unconvertible)
+	}
+
+	boardUpdateAt := board.UpdateAt
+	pbab.BoardIDs = []string{boardID}
+	pbab.BoardPatches = []*model.BoardPatch{{
+		UpdatedCardProperties: []map[string]interface{}{result.Property},
+		ExpectedUpdateAt:      &boardUpdateAt,
+	}}
+	if _, err := a.PatchBoardsAndBlocks(pbab, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
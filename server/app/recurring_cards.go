@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// recurringCardsBatchSize is the number of due rules loaded at a time by
+// RunRecurringCardRules.
+const recurringCardsBatchSize = 100
+
+func (a *App) GetRecurringCardRules(boardID string) ([]*model.RecurringCardRule, error) {
+	return a.store.GetRecurringCardRulesForBoard(boardID)
+}
+
+func (a *App) GetRecurringCardRule(ruleID string) (*model.RecurringCardRule, error) {
+	return a.store.GetRecurringCardRule(ruleID)
+}
+
+// CreateRecurringCardRule creates a rule that creates cards as the user
+// from a card template of the board of the rule.
+func (a *App) CreateRecurringCardRule(rule *model.RecurringCardRule, userID string) (*model.RecurringCardRule, error) {
+	rule.ID = ""
+	rule.CreatedBy = userID
+	rule.ModifiedBy = userID
+	rule.LastRunAt = 0
+	rule.LastCardID = ""
+	rule.LastError = ""
+
+	if err := a.validateRecurringCardRule(rule); err != nil {
+		return nil, err
+	}
+	if err := rule.ComputeNextRunAt(utils.GetMillis()); err != nil {
+		return nil, err
+	}
+	return a.store.InsertRecurringCardRule(rule)
+}
+
+// PatchRecurringCardRule changes the schedule, overrides or paused state of
+// a rule. The next run is computed again from now when the schedule
+// changes or the rule is resumed, so runs missed while paused are skipped.
+func (a *App) PatchRecurringCardRule(rule *model.RecurringCardRule, patch *model.RecurringCardRulePatch, userID string) (*model.RecurringCardRule, error) {
+	patched := patch.Patch(rule)
+	patched.ModifiedBy = userID
+
+	if err := a.validateRecurringCardRule(patched); err != nil {
+		return nil, err
+	}
+	if patch.Schedule != nil || patch.Timezone != nil || patch.Paused != nil {
+		if err := patched.ComputeNextRunAt(utils.GetMillis()); err != nil {
+			return nil, err
+		}
+	}
+	return a.store.UpdateRecurringCardRule(patched)
+}
+
+func (a *App) DeleteRecurringCardRule(ruleID string) error {
+	return a.store.DeleteRecurringCardRule(ruleID)
+}
+
+// validateRecurringCardRule checks that the template of a rule is a card
+// template of its board and that the overrides are valid card properties.
+func (a *App) validateRecurringCardRule(rule *model.RecurringCardRule) error {
+	if err := rule.IsValid(); err != nil {
+		return err
+	}
+
+	template, err := a.store.GetBlock(rule.TemplateID)
+	if model.IsErrNotFound(err) {
+		return model.NewErrBadRequest("template not found: " + rule.TemplateID)
+	}
+	if err != nil {
+		return err
+	}
+	if template.BoardID != rule.BoardID || template.Type != model.TypeCard {
+		return model.NewErrBadRequest("the template must be a card of the board")
+	}
+	if !isCardTemplate(template) {
+		return model.NewErrBadRequest("the template must be a card template")
+	}
+
+	board, err := a.store.GetBoard(rule.BoardID)
+	if err != nil {
+		return err
+	}
+	return a.validateCardProperties(board, nil, rule.Overrides.Apply(nil, time.Now()), false, rule.ModifiedBy)
+}
+
+// RunRecurringCardRules creates the cards of the rules that are due at a
+// time in milliseconds since the epoch, and returns the number of cards
+// created. A rule that missed several runs, e.g. while the server was
+// down, creates a single card.
+func (a *App) RunRecurringCardRules(now int64) (int, error) {
+	created := 0
+	for {
+		rules, err := a.store.GetDueRecurringCardRules(now, recurringCardsBatchSize)
+		if err != nil {
+			return created, err
+		}
+		if len(rules) == 0 {
+			return created, nil
+		}
+
+		for _, rule := range rules {
+			ok, err := a.runRecurringCardRule(rule, now)
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created++
+			}
+		}
+	}
+}
+
+// runRecurringCardRule claims the due run of a rule and creates its card,
+// recording the outcome on the rule. It returns false if the run was
+// claimed by another server or the card could not be created.
+func (a *App) runRecurringCardRule(rule *model.RecurringCardRule, now int64) (bool, error) {
+	next := *rule
+	if err := next.ComputeNextRunAt(now); err != nil {
+		// the rule was valid when saved, so it only happens if its time
+		// zone is no longer known; stop it rather than retrying every run
+		next.NextRunAt = 0
+	}
+	claimed, err := a.store.ClaimRecurringCardRuleRun(rule.ID, rule.NextRunAt, next.NextRunAt)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	card, runErr := a.createRecurringCard(rule, now)
+	lastCardID, lastError := "", ""
+	if runErr != nil {
+		lastError = runErr.Error()
+		a.logger.Warn("Unable to create recurring card",
+			mlog.String("rule_id", rule.ID),
+			mlog.String("board_id", rule.BoardID),
+			mlog.Err(runErr),
+		)
+	} else {
+		lastCardID = card.ID
+	}
+
+	if err := a.store.UpdateRecurringCardRuleLastRun(rule.ID, now, lastCardID, lastError); err != nil {
+		return false, err
+	}
+	return runErr == nil, nil
+}
+
+// createRecurringCard duplicates the template of a rule, with its content
+// and attachments, as the creator of the rule and applies its overrides.
+func (a *App) createRecurringCard(rule *model.RecurringCardRule, now int64) (*model.Block, error) {
+	if !a.permissions.HasPermissionToBoard(rule.CreatedBy, rule.BoardID, model.PermissionManageBoardCards) {
+		return nil, model.NewErrPermission("the creator of the rule can no longer add cards to the board")
+	}
+
+	template, err := a.store.GetBlock(rule.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the template %s: %w", rule.TemplateID, err)
+	}
+
+	blocks, err := a.DuplicateBlock(rule.BoardID, rule.TemplateID, rule.CreatedBy, false)
+	if err != nil {
+		return nil, err
+	}
+	card := blocks[0]
+
+	loc, err := rule.Location()
+	if err != nil {
+		return nil, err
+	}
+	oldProperties, _ := template.Fields["properties"].(map[string]interface{})
+	patch := &model.BlockPatch{
+		UpdatedFields: map[string]interface{}{
+			"properties": rule.Overrides.Apply(oldProperties, utils.GetTimeForMillis(now).In(loc)),
+		},
+	}
+	if rule.Overrides.Title != "" {
+		patch.Title = &rule.Overrides.Title
+	}
+	return a.PatchBlock(card.ID, patch, rule.CreatedBy)
+}
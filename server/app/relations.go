@@ -0,0 +1,569 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// linkRelationProperties creates the reverse property of the new relation
+// properties of a board patch on their target board, and sets it as the
+// reversePropertyId of the patched property. Relations between the cards
+// of the same board use the property itself as the reverse property.
+func (a *App) linkRelationProperties(board *model.Board, patch *model.BoardPatch, userID string) error {
+	for i, prop := range patch.UpdatedCardProperties {
+		if propType, _ := prop["type"].(string); propType != model.PropTypeRelation {
+			continue
+		}
+		if reversePropertyID, _ := prop["reversePropertyId"].(string); reversePropertyID != "" {
+			continue
+		}
+
+		propID, _ := prop["id"].(string)
+		targetBoardID, _ := prop["targetBoardId"].(string)
+
+		// the property of the patch belongs to the caller
+		linked := make(map[string]interface{}, len(prop)+1)
+		for k, v := range prop {
+			linked[k] = v
+		}
+
+		if targetBoardID == board.ID {
+			linked["reversePropertyId"] = propID
+			patch.UpdatedCardProperties[i] = linked
+			continue
+		}
+
+		if !a.permissions.HasPermissionToBoard(userID, targetBoardID, model.PermissionManageBoardProperties) {
+			return model.NewErrPermission("access denied to the target board of the relation")
+		}
+
+		targetBoard, err := a.store.GetBoard(targetBoardID)
+		if model.IsErrNotFound(err) {
+			return model.NewErrBadRequest("relation target board not found, ID=" + targetBoardID)
+		}
+		if err != nil {
+			return err
+		}
+		if targetBoard.TeamID != board.TeamID {
+			return model.NewErrBadRequest("relation target board belongs to another team")
+		}
+
+		reversePropertyID := utils.NewID(utils.IDTypeNone)
+		reverse := map[string]interface{}{
+			"id":                reversePropertyID,
+			"name":              board.Title,
+			"type":              model.PropTypeRelation,
+			"options":           []interface{}{},
+			"targetBoardId":     board.ID,
+			"reversePropertyId": propID,
+		}
+		reversePatch := &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{reverse}}
+		updatedTarget, err := a.store.PatchBoard(targetBoardID, reversePatch, userID)
+		if err != nil {
+			return err
+		}
+		a.blockChangeNotifier.Enqueue(func() error {
+			a.wsAdapter.BroadcastBoardChange(updatedTarget.TeamID, updatedTarget)
+			return nil
+		})
+
+		linked["reversePropertyId"] = reversePropertyID
+		patch.UpdatedCardProperties[i] = linked
+	}
+	return nil
+}
+
+// validateRelationValues checks the relation values of a card changed by a
+// user. The reverse relations of the linked and unlinked cards are updated
+// as well, so the user must be able to view and edit the cards of the
+// target board, and the linked cards must be cards of the target board.
+func (a *App) validateRelationValues(board *model.Board, schema model.PropSchema, oldProperties, properties map[string]interface{}, userID string) ([]model.InvalidPropertyValue, error) {
+	ids := make([]string, 0, len(properties))
+	for id := range properties {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	invalid := []model.InvalidPropertyValue{}
+	for _, id := range ids {
+		def, ok := schema[id]
+		if !ok || def.Type != model.PropTypeRelation || def.Relation.TargetBoardID == "" {
+			continue
+		}
+		added, removed := model.DiffRelationIDs(
+			model.RelationIDs(oldProperties[id]),
+			model.RelationIDs(properties[id]),
+		)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		reason, err := a.relationChangeProblem(board, def.Relation.TargetBoardID, added, userID)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			invalid = append(invalid, model.InvalidPropertyValue{
+				PropertyID:   id,
+				PropertyName: def.Name,
+				Value:        properties[id],
+				Reason:       reason,
+			})
+		}
+	}
+	return invalid, nil
+}
+
+// relationChangeProblem returns why a user can't link the cards of added
+// to a card of a board, or change its links, through a relation to the
+// target board. It returns an empty string if they can.
+func (a *App) relationChangeProblem(board *model.Board, targetBoardID string, added []string, userID string) (string, error) {
+	if targetBoardID != board.ID && userID != model.SystemUserID {
+		if !a.permissions.HasPermissionToBoard(userID, targetBoardID, model.PermissionViewBoard) ||
+			!a.permissions.HasPermissionToBoard(userID, targetBoardID, model.PermissionManageBoardCards) {
+			return "access denied to the cards of the target board", nil
+		}
+	}
+	if len(added) == 0 {
+		return "", nil
+	}
+
+	cards, err := a.store.GetBlocksByIDs(added)
+	if err != nil && !model.IsErrNotFound(err) {
+		return "", err
+	}
+	found := make(map[string]bool, len(cards))
+	for _, card := range cards {
+		if card.BoardID == targetBoardID && card.Type == model.TypeCard {
+			found[card.ID] = true
+		}
+	}
+	for _, id := range added {
+		if !found[id] {
+			return fmt.Sprintf("card %s not found on the target board", id), nil
+		}
+	}
+	return "", nil
+}
+
+// syncCardRelations updates the reverse relation properties of the cards
+// that were linked or unlinked by the change of a card. oldBlock is nil for
+// new cards and newBlock is nil for deleted ones. Errors are logged, as the
+// change of the card itself has already been saved.
+func (a *App) syncCardRelations(board *model.Board, oldBlock, newBlock *model.Block, modifiedByID string) {
+	block := newBlock
+	if block == nil {
+		block = oldBlock
+	}
+	if block == nil || block.Type != model.TypeCard {
+		return
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return
+	}
+
+	for _, def := range schema {
+		if def.Type != model.PropTypeRelation || def.Relation.ReversePropertyID == "" {
+			continue
+		}
+
+		added, removed := model.DiffRelationIDs(
+			model.RelationIDs(relationPropertyValue(oldBlock, def.ID)),
+			model.RelationIDs(relationPropertyValue(newBlock, def.ID)),
+		)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		targetBoard := board
+		if def.Relation.TargetBoardID != board.ID {
+			// the values of the user were validated, but not the links
+			// removed by the deletion of a card
+			if modifiedByID != model.SystemUserID &&
+				!a.permissions.HasPermissionToBoard(modifiedByID, def.Relation.TargetBoardID, model.PermissionManageBoardCards) {
+				a.logger.Debug("syncCardRelations: skipping the reverse relations of a board the user can't edit",
+					mlog.String("boardID", def.Relation.TargetBoardID),
+					mlog.String("userID", modifiedByID),
+				)
+				continue
+			}
+			targetBoard, err = a.store.GetBoard(def.Relation.TargetBoardID)
+			if err != nil {
+				a.logger.Error("syncCardRelations: cannot get the target board",
+					mlog.String("boardID", def.Relation.TargetBoardID),
+					mlog.Err(err),
+				)
+				continue
+			}
+		}
+
+		// the reverse property may have been changed since the relation
+		// was created, in which case the relation is one way
+		targetSchema, err := model.ParsePropertySchema(targetBoard)
+		if err != nil {
+			continue
+		}
+		reverse, ok := targetSchema[def.Relation.ReversePropertyID]
+		if !ok || reverse.Type != model.PropTypeRelation ||
+			reverse.Relation.TargetBoardID != board.ID || reverse.Relation.ReversePropertyID != def.ID {
+			continue
+		}
+
+		for _, cardID := range added {
+			a.updateReverseRelation(targetBoard, cardID, reverse.ID, block.ID, true, modifiedByID)
+		}
+		for _, cardID := range removed {
+			a.updateReverseRelation(targetBoard, cardID, reverse.ID, block.ID, false, modifiedByID)
+		}
+	}
+}
+
+// syncPatchedCardsRelations updates the reverse relation properties of the
+// cards linked or unlinked by a batch of patches. boards holds the boards
+// of the patched cards that are already loaded.
+func (a *App) syncPatchedCardsRelations(boards map[string]*model.Board, oldBlocks []*model.Block, blockPatches *model.BlockPatchBatch, modifiedByID string) {
+	oldBlocksByID := make(map[string]*model.Block, len(oldBlocks))
+	for _, block := range oldBlocks {
+		oldBlocksByID[block.ID] = block
+	}
+
+	for i, blockID := range blockPatches.BlockIDs {
+		oldBlock, ok := oldBlocksByID[blockID]
+		if !ok || oldBlock.Type != model.TypeCard || i >= len(blockPatches.BlockPatches) {
+			continue
+		}
+		if _, ok := blockPatches.BlockPatches[i].UpdatedFields["properties"]; !ok {
+			continue
+		}
+
+		board, ok := boards[oldBlock.BoardID]
+		if !ok {
+			var err error
+			if board, err = a.store.GetBoard(oldBlock.BoardID); err != nil {
+				a.logger.Error("syncPatchedCardsRelations: cannot get the board", mlog.String("boardID", oldBlock.BoardID), mlog.Err(err))
+				continue
+			}
+			boards[oldBlock.BoardID] = board
+		}
+
+		newBlock, err := a.store.GetBlock(blockID)
+		if err != nil {
+			a.logger.Error("syncPatchedCardsRelations: cannot get the card", mlog.String("cardID", blockID), mlog.Err(err))
+			continue
+		}
+		a.syncCardRelations(board, oldBlock, newBlock, modifiedByID)
+	}
+}
+
+// updateReverseRelation adds or removes the link to linkedCardID from the
+// relation property of a card of the target board.
+func (a *App) updateReverseRelation(targetBoard *model.Board, cardID, propertyID, linkedCardID string, link bool, modifiedByID string) {
+	card, err := a.store.GetBlock(cardID)
+	if model.IsErrNotFound(err) {
+		return
+	}
+	if err != nil {
+		a.logger.Error("updateReverseRelation: cannot get the linked card", mlog.String("cardID", cardID), mlog.Err(err))
+		return
+	}
+	if card.BoardID != targetBoard.ID || card.Type != model.TypeCard {
+		return
+	}
+
+	properties := map[string]interface{}{}
+	if current, ok := card.Fields["properties"].(map[string]interface{}); ok {
+		for k, v := range current {
+			properties[k] = v
+		}
+	}
+
+	ids := model.RelationIDs(properties[propertyID])
+	newIDs := make([]string, 0, len(ids)+1)
+	found := false
+	for _, id := range ids {
+		if id == linkedCardID {
+			found = true
+			if !link {
+				continue
+			}
+		}
+		newIDs = append(newIDs, id)
+	}
+	if found == link {
+		return
+	}
+	if link {
+		newIDs = append(newIDs, linkedCardID)
+	}
+
+	if len(newIDs) == 0 {
+		delete(properties, propertyID)
+	} else {
+		properties[propertyID] = model.RelationValue(newIDs)
+	}
+
+	patch := &model.BlockPatch{UpdatedFields: map[string]interface{}{"properties": properties}}
+	if err = a.store.PatchBlock(cardID, patch, modifiedByID); err != nil {
+		a.logger.Error("updateReverseRelation: cannot patch the linked card", mlog.String("cardID", cardID), mlog.Err(err))
+		return
+	}
+
+	updated, err := a.store.GetBlock(cardID)
+	if err != nil {
+		a.logger.Error("updateReverseRelation: cannot get the linked card", mlog.String("cardID", cardID), mlog.Err(err))
+		return
+	}
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.broadcastBlockChange(targetBoard.TeamID, targetBoard, updated)
+		return nil
+	})
+}
+
+// ResolveCardRelations removes from the relation properties of the cards of
+// a board the cards that don't exist anymore or that the user cannot see,
+// and computes their rollup properties from the remaining linked cards.
+func (a *App) ResolveCardRelations(userID, boardID string, cards ...*model.Card) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	relations, rollups, err := a.relationProperties(boardID)
+	if err != nil || (len(relations) == 0 && len(rollups) == 0) {
+		return err
+	}
+
+	cardsProperties := make([]map[string]interface{}, len(cards))
+	for i, card := range cards {
+		if card.Properties == nil {
+			card.Properties = map[string]interface{}{}
+		}
+		cardsProperties[i] = card.Properties
+	}
+	return a.resolveRelationValues(userID, boardID, relations, rollups, cardsProperties)
+}
+
+// ResolveBlockRelations returns the blocks of a board with the relation
+// properties of its cards resolved like ResolveCardRelations. The card
+// blocks are copied rather than modified.
+func (a *App) ResolveBlockRelations(userID, boardID string, blocks []*model.Block) ([]*model.Block, error) {
+	relations, rollups, err := a.relationProperties(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(relations) == 0 && len(rollups) == 0 {
+		return blocks, nil
+	}
+
+	resolved := make([]*model.Block, len(blocks))
+	cardsProperties := []map[string]interface{}{}
+	for i, block := range blocks {
+		resolved[i] = block
+		if block.Type != model.TypeCard {
+			continue
+		}
+
+		b := *block
+		b.Fields = make(map[string]interface{}, len(block.Fields)+1)
+		for k, v := range block.Fields {
+			b.Fields[k] = v
+		}
+		current, _ := block.Fields["properties"].(map[string]interface{})
+		properties := make(map[string]interface{}, len(current))
+		for k, v := range current {
+			properties[k] = v
+		}
+		b.Fields["properties"] = properties
+
+		resolved[i] = &b
+		cardsProperties = append(cardsProperties, properties)
+	}
+
+	if err := a.resolveRelationValues(userID, boardID, relations, rollups, cardsProperties); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// relationProperties returns the relation and rollup properties of a board.
+// Boards with an invalid schema have none.
+func (a *App) relationProperties(boardID string) ([]model.PropDef, []model.PropDef, error) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, nil, nil //nolint:nilerr
+	}
+
+	relations := []model.PropDef{}
+	rollups := []model.PropDef{}
+	for _, def := range schema {
+		switch def.Type {
+		case model.PropTypeRelation:
+			relations = append(relations, def)
+		case model.PropTypeRollup:
+			rollups = append(rollups, def)
+		}
+	}
+	return relations, rollups, nil
+}
+
+// resolveRelationValues resolves the relation and rollup values of the
+// properties of cards of a board, modifying them.
+func (a *App) resolveRelationValues(userID, boardID string, relations, rollups []model.PropDef, cardsProperties []map[string]interface{}) error {
+	linkedIDs := []string{}
+	seen := map[string]bool{}
+	for _, properties := range cardsProperties {
+		for _, def := range relations {
+			for _, id := range model.RelationIDs(properties[def.ID]) {
+				if !seen[id] {
+					seen[id] = true
+					linkedIDs = append(linkedIDs, id)
+				}
+			}
+		}
+	}
+
+	linkedCards := map[string]*model.Block{}
+	if len(linkedIDs) > 0 {
+		blocks, err := a.store.GetBlocksByIDs(linkedIDs)
+		if err != nil && !model.IsErrNotFound(err) {
+			return err
+		}
+		for _, block := range blocks {
+			linkedCards[block.ID] = block
+		}
+	}
+
+	// the cards of the board itself are visible to whoever reads the
+	// board's cards, including through a sharing token
+	canView := map[string]bool{boardID: true}
+	hasPermission := func(boardID string) bool {
+		allowed, ok := canView[boardID]
+		if !ok {
+			allowed = a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard)
+			canView[boardID] = allowed
+		}
+		return allowed
+	}
+
+	for _, properties := range cardsProperties {
+		visible := make(map[string][]*model.Block, len(relations))
+		for _, def := range relations {
+			ids := []string{}
+			for _, id := range model.RelationIDs(properties[def.ID]) {
+				linked, ok := linkedCards[id]
+				if !ok || linked.Type != model.TypeCard || linked.BoardID != def.Relation.TargetBoardID {
+					continue
+				}
+				if !hasPermission(linked.BoardID) {
+					continue
+				}
+				ids = append(ids, id)
+				visible[def.ID] = append(visible[def.ID], linked)
+			}
+
+			if len(ids) == 0 {
+				delete(properties, def.ID)
+			} else {
+				properties[def.ID] = model.RelationValue(ids)
+			}
+		}
+
+		for _, def := range rollups {
+			value, err := model.ComputeRollup(*def.Rollup, visible[def.Rollup.RelationPropertyID])
+			if err != nil || value == nil {
+				delete(properties, def.ID)
+				continue
+			}
+			properties[def.ID] = value
+		}
+	}
+	return nil
+}
+
+// broadcastBlockChange broadcasts the change of a block of a board on the
+// websocket. The broadcast reaches all the members of the board, who may
+// not see the cards of other boards, so the values of the relations to
+// other boards and of the rollups are left out of card blocks. Clients read
+// them from the API. The board is looked up if it isn't given.
+func (a *App) broadcastBlockChange(teamID string, board *model.Board, block *model.Block) {
+	a.wsAdapter.BroadcastBlockChange(teamID, a.blockWithoutCrossBoardRelations(board, block))
+}
+
+func (a *App) blockWithoutCrossBoardRelations(board *model.Board, block *model.Block) *model.Block {
+	if block.Type != model.TypeCard {
+		return block
+	}
+	properties, ok := block.Fields["properties"].(map[string]interface{})
+	if !ok || len(properties) == 0 {
+		return block
+	}
+
+	if board == nil || board.ID != block.BoardID {
+		var err error
+		if board, err = a.store.GetBoard(block.BoardID); err != nil {
+			a.logger.Warn("broadcastBlockChange: cannot get the board of the card",
+				mlog.String("boardID", block.BoardID),
+				mlog.Err(err),
+			)
+			return block
+		}
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return block
+	}
+
+	hidden := []string{}
+	for id := range properties {
+		def, ok := schema[id]
+		if !ok {
+			continue
+		}
+		if def.Type == model.PropTypeRollup ||
+			(def.Type == model.PropTypeRelation && def.Relation.TargetBoardID != block.BoardID) {
+			hidden = append(hidden, id)
+		}
+	}
+	if len(hidden) == 0 {
+		return block
+	}
+
+	b := *block
+	b.Fields = make(map[string]interface{}, len(block.Fields))
+	for k, v := range block.Fields {
+		b.Fields[k] = v
+	}
+	visible := make(map[string]interface{}, len(properties))
+	for k, v := range properties {
+		visible[k] = v
+	}
+	for _, id := range hidden {
+		delete(visible, id)
+	}
+	b.Fields["properties"] = visible
+	return &b
+}
+
+func relationPropertyValue(block *model.Block, propertyID string) interface{} {
+	if block == nil {
+		return nil
+	}
+	properties, ok := block.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return properties[propertyID]
+}
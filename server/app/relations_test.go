@@ -0,0 +1,56 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+func TestBlockWithoutCrossBoardRelations(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board_id",
+		CardProperties: []map[string]interface{}{
+			{"id": "parent", "name": "Parent", "type": model.PropTypeRelation, "targetBoardId": "board_id"},
+			{"id": "tasks", "name": "Tasks", "type": model.PropTypeRelation, "targetBoardId": "tasks_board_id"},
+			{"id": "count", "name": "Count", "type": model.PropTypeRollup, "relationPropertyId": "tasks", "function": "count"},
+			{"id": "points", "name": "Points", "type": "number"},
+		},
+	}
+	properties := map[string]interface{}{
+		"parent": []interface{}{"card_1"},
+		"tasks":  []interface{}{"task_1"},
+		"count":  1.0,
+		"points": "3",
+	}
+	block := &model.Block{
+		ID:      "card_2",
+		BoardID: board.ID,
+		Type:    model.TypeCard,
+		Fields:  map[string]interface{}{"properties": properties},
+	}
+
+	t.Run("the relations to other boards and the rollups are left out", func(t *testing.T) {
+		broadcast := th.App.blockWithoutCrossBoardRelations(board, block)
+		require.Equal(t, map[string]interface{}{
+			"parent": []interface{}{"card_1"},
+			"points": "3",
+		}, broadcast.Fields["properties"])
+		require.Len(t, block.Fields["properties"], 4)
+	})
+
+	t.Run("the board is looked up if not given", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		broadcast := th.App.blockWithoutCrossBoardRelations(nil, block)
+		require.NotContains(t, broadcast.Fields["properties"], "tasks")
+	})
+
+	t.Run("other blocks are broadcast as they are", func(t *testing.T) {
+		text := &model.Block{ID: "text", BoardID: board.ID, Type: model.TypeText, Fields: block.Fields}
+		require.Same(t, text, th.App.blockWithoutCrossBoardRelations(board, text))
+	})
+}
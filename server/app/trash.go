@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// TrashBlockTypes are the types of the blocks listed in the trash of a
+// board. Content blocks and comments are restored and purged along with
+// their card.
+var TrashBlockTypes = []model.BlockType{model.TypeCard, model.TypeView}
+
+// GetDeletedBoardsForTeam returns the boards of a team that are in the
+// trash. Each board is its last revision, so its modifiedBy and deleteAt
+// fields are the user that deleted it and the deletion time.
+func (a *App) GetDeletedBoardsForTeam(teamID string) ([]*model.Board, error) {
+	return a.store.GetDeletedBoardsForTeam(teamID)
+}
+
+// GetDeletedBlocksForBoard returns the cards and views of a board that
+// are in the trash, with the same metadata as the boards.
+func (a *App) GetDeletedBlocksForBoard(boardID string) ([]*model.Block, error) {
+	if _, err := a.store.GetBoard(boardID); err != nil {
+		return nil, err
+	}
+	return a.store.GetDeletedBlocksForBoard(boardID, TrashBlockTypes)
+}
+
+// GetDeletedBoard returns the last revision of a board of the team if the
+// board is in the trash.
+func (a *App) GetDeletedBoard(teamID, boardID string) (*model.Board, error) {
+	boards, err := a.store.GetBoardHistory(boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(boards) == 0 || boards[0].DeleteAt == 0 || boards[0].TeamID != teamID {
+		return nil, model.NewErrNotFound("deleted board ID=" + boardID)
+	}
+	return boards[0], nil
+}
+
+// GetDeletedBlock returns the last revision of a card or view of the board
+// if the block is in the trash.
+func (a *App) GetDeletedBlock(boardID, blockID string) (*model.Block, error) {
+	block, err := a.GetLastBlockHistoryEntry(blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil || block.DeleteAt == 0 || block.BoardID != boardID || !isTrashBlockType(block.Type) {
+		return nil, model.NewErrNotFound("deleted block ID=" + blockID)
+	}
+	return block, nil
+}
+
+// RestoreDeletedBoard restores a board of the team from the trash.
+func (a *App) RestoreDeletedBoard(teamID, boardID, userID string) (*model.Board, error) {
+	if _, err := a.GetDeletedBoard(teamID, boardID); err != nil {
+		return nil, err
+	}
+
+	if err := a.UndeleteBoard(boardID, userID); err != nil {
+		return nil, err
+	}
+	return a.store.GetBoard(boardID)
+}
+
+// RestoreDeletedBlock restores a card or view of the board from the trash,
+// along with its content.
+func (a *App) RestoreDeletedBlock(boardID, blockID, userID string) (*model.Block, error) {
+	if _, err := a.GetDeletedBlock(boardID, blockID); err != nil {
+		return nil, err
+	}
+	return a.UndeleteBlock(blockID, userID)
+}
+
+// PurgeBoard permanently removes a board of the team from the trash,
+// with its blocks, their history and their files. It can't be undone.
+func (a *App) PurgeBoard(teamID, boardID string) error {
+	if _, err := a.GetDeletedBoard(teamID, boardID); err != nil {
+		return err
+	}
+
+	revisions, err := a.store.GetBlockHistoryDescendants(boardID, model.QueryBlockHistoryOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := a.store.PurgeBoard(boardID); err != nil {
+		return err
+	}
+
+	a.removeBlockFiles(teamID, boardID, revisions)
+
+	a.logger.Info("Board purged from the trash",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+	)
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBoardDelete(teamID, boardID)
+		return nil
+	})
+
+	return nil
+}
+
+// PurgeBlock permanently removes a card or view of the board from the
+// trash, with its content, their history and their files. It can't be
+// undone.
+func (a *App) PurgeBlock(boardID, blockID string) error {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = a.GetDeletedBlock(boardID, blockID); err != nil {
+		return err
+	}
+
+	revisions, err := a.store.GetBlockHistory(blockID, model.QueryBlockHistoryOptions{})
+	if err != nil {
+		return err
+	}
+
+	children, _, err := a.store.GetBlockHistoryNewestChildren(blockID, model.QueryBlockHistoryChildOptions{})
+	if err != nil {
+		return err
+	}
+
+	// children that are live are not purged, so their files are kept
+	for _, child := range children {
+		if child.DeleteAt != 0 {
+			revisions = append(revisions, child)
+		}
+	}
+
+	if err := a.store.PurgeBlock(blockID); err != nil {
+		return err
+	}
+
+	a.removeBlockFiles(board.TeamID, boardID, revisions)
+
+	a.logger.Info("Block purged from the trash",
+		mlog.String("boardID", boardID),
+		mlog.String("blockID", blockID),
+	)
+
+	a.blockChangeNotifier.Enqueue(func() error {
+		a.wsAdapter.BroadcastBlockDelete(board.TeamID, blockID, boardID)
+		return nil
+	})
+
+	return nil
+}
+
+func isTrashBlockType(blockType model.BlockType) bool {
+	for _, t := range TrashBlockTypes {
+		if t == blockType {
+			return true
+		}
+	}
+	return false
+}
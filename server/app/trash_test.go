@@ -0,0 +1,105 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/v8/platform/shared/filestore/mocks"
+)
+
+func TestPurgeBoard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	teamID := "team-id"
+	boardID := "board-id"
+
+	t.Run("board not in the trash", func(t *testing.T) {
+		th.Store.EXPECT().GetBoardHistory(boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true}).
+			Return([]*model.Board{{ID: boardID, TeamID: teamID}}, nil)
+
+		err := th.App.PurgeBoard(teamID, boardID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("board of another team", func(t *testing.T) {
+		th.Store.EXPECT().GetBoardHistory(boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true}).
+			Return([]*model.Board{{ID: boardID, TeamID: "other-team-id", DeleteAt: 10}}, nil)
+
+		err := th.App.PurgeBoard(teamID, boardID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("purge the board and its files", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		th.Store.EXPECT().GetBoardHistory(boardID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true}).
+			Return([]*model.Board{{ID: boardID, TeamID: teamID, DeleteAt: 10}}, nil)
+		th.Store.EXPECT().GetBlockHistoryDescendants(boardID, model.QueryBlockHistoryOptions{}).Return([]*model.Block{
+			{ID: "image-id", BoardID: boardID, Type: model.TypeImage, Fields: map[string]interface{}{"fileId": "7abc.png"}},
+			{ID: "image-id", BoardID: boardID, Type: model.TypeImage, Fields: map[string]interface{}{"fileId": "7abc.png"}},
+			{ID: "text-id", BoardID: boardID, Type: model.TypeText},
+		}, nil)
+		th.Store.EXPECT().PurgeBoard(boardID).Return(nil)
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetFileInfo("abc").Return(&mm_model.FileInfo{Id: "abc", Path: "data/7abc.png"}, nil)
+		mockedFileBackend.On("RemoveFile", "data/7abc.png").Return(nil).Once()
+
+		err := th.App.PurgeBoard(teamID, boardID)
+		require.NoError(t, err)
+		mockedFileBackend.AssertExpectations(t)
+	})
+}
+
+func TestPurgeBlock(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: "board-id", TeamID: "team-id"}
+	cardID := "card-id"
+	lastEntryOpts := model.QueryBlockHistoryOptions{Limit: 1, Descending: true}
+
+	t.Run("content blocks are not in the trash", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlockHistory("text-id", lastEntryOpts).
+			Return([]*model.Block{{ID: "text-id", BoardID: board.ID, Type: model.TypeText, DeleteAt: 10}}, nil)
+
+		err := th.App.PurgeBlock(board.ID, "text-id")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("card of another board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlockHistory(cardID, lastEntryOpts).
+			Return([]*model.Block{{ID: cardID, BoardID: "other-board-id", Type: model.TypeCard, DeleteAt: 10}}, nil)
+
+		err := th.App.PurgeBlock(board.ID, cardID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("purge the card and the files of its deleted children", func(t *testing.T) {
+		mockedFileBackend := &mocks.FileBackend{}
+		th.App.filesBackend = mockedFileBackend
+
+		card := &model.Block{ID: cardID, BoardID: board.ID, Type: model.TypeCard, DeleteAt: 10}
+		th.Store.EXPECT().GetBoard(board.ID).Return(board, nil)
+		th.Store.EXPECT().GetBlockHistory(cardID, lastEntryOpts).Return([]*model.Block{card}, nil)
+		th.Store.EXPECT().GetBlockHistory(cardID, model.QueryBlockHistoryOptions{}).Return([]*model.Block{card}, nil)
+		th.Store.EXPECT().GetBlockHistoryNewestChildren(cardID, model.QueryBlockHistoryChildOptions{}).Return([]*model.Block{
+			{ID: "deleted-id", ParentID: cardID, Type: model.TypeAttachment, DeleteAt: 10, Fields: map[string]interface{}{"attachmentId": "7deleted.txt"}},
+			{ID: "live-id", ParentID: cardID, Type: model.TypeImage, Fields: map[string]interface{}{"fileId": "7live.png"}},
+		}, false, nil)
+		th.Store.EXPECT().PurgeBlock(cardID).Return(nil)
+		th.Store.EXPECT().GetMembersForBoard(board.ID).Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetFileInfo("deleted").Return(nil, model.NewErrNotFound("file info"))
+		mockedFileBackend.On("RemoveFile", "team-id/board-id/7deleted.txt").Return(nil).Once()
+
+		err := th.App.PurgeBlock(board.ID, cardID)
+		require.NoError(t, err)
+		mockedFileBackend.AssertExpectations(t)
+	})
+}
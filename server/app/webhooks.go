@@ -0,0 +1,332 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// webhookDeliveriesBatchSize is the number of due deliveries loaded at
+	// a time by ProcessWebhookDeliveries.
+	webhookDeliveriesBatchSize = 100
+
+	// webhookDeliveryLease is the time a delivery is reserved for the
+	// server attempting it. It is attempted again after that if the server
+	// stopped before recording the attempt.
+	webhookDeliveryLease = 2 * time.Minute
+
+	// webhookDeliveryLogRetention is the time the deliveries are kept in
+	// the delivery log once they succeeded or failed.
+	webhookDeliveryLogRetention = 30 * 24 * time.Hour
+)
+
+func (a *App) GetWebhook(webhookID string) (*model.Webhook, error) {
+	return a.store.GetWebhook(webhookID)
+}
+
+func (a *App) GetWebhooksForBoard(boardID string) ([]*model.Webhook, error) {
+	return a.store.GetWebhooksForBoard(boardID)
+}
+
+// GetWebhooksForTeam returns the webhooks of a team that receive the
+// events of all its boards.
+func (a *App) GetWebhooksForTeam(teamID string) ([]*model.Webhook, error) {
+	return a.store.GetWebhooksForTeam(teamID)
+}
+
+// CreateWebhook creates a webhook as the user, with a new secret. A
+// webhook of a board belongs to the team of the board.
+func (a *App) CreateWebhook(webhook *model.Webhook, userID string) (*model.Webhook, error) {
+	webhook.ID = ""
+	webhook.Secret = utils.NewID(utils.IDTypeToken)
+	webhook.CreatedBy = userID
+	webhook.ModifiedBy = userID
+
+	if webhook.BoardID != "" {
+		board, err := a.store.GetBoard(webhook.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		webhook.TeamID = board.TeamID
+	}
+	return a.store.InsertWebhook(webhook)
+}
+
+func (a *App) PatchWebhook(webhook *model.Webhook, patch *model.WebhookPatch, userID string) (*model.Webhook, error) {
+	patched := patch.Patch(webhook)
+	patched.ModifiedBy = userID
+	return a.store.UpdateWebhook(patched)
+}
+
+// DeleteWebhook deletes a webhook and its delivery log.
+func (a *App) DeleteWebhook(webhookID string) error {
+	return a.store.DeleteWebhook(webhookID)
+}
+
+func (a *App) GetWebhookDeliveries(opts model.QueryWebhookDeliveriesOptions) ([]*model.WebhookDelivery, error) {
+	return a.store.GetWebhookDeliveries(opts)
+}
+
+func (a *App) GetWebhookDelivery(deliveryID string) (*model.WebhookDelivery, error) {
+	return a.store.GetWebhookDelivery(deliveryID)
+}
+
+// RedeliverWebhookDelivery sends the payload of a delivery again as a new
+// delivery, attempted right away and retried like the others if it
+// fails.
+func (a *App) RedeliverWebhookDelivery(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	webhook, err := a.store.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Disabled {
+		return nil, model.NewErrBadRequest("the webhook is disabled")
+	}
+
+	now := utils.GetMillis()
+	redelivery, err := a.store.InsertWebhookDelivery(&model.WebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		BoardID:       delivery.BoardID,
+		Event:         delivery.Event,
+		Payload:       delivery.Payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  delivery.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = a.attemptWebhookDelivery(redelivery, now); err != nil {
+		return nil, err
+	}
+	return a.store.GetWebhookDelivery(redelivery.ID)
+}
+
+// ProcessWebhookDeliveries attempts the webhook deliveries that are due
+// at a time in milliseconds since the epoch, and returns the number of
+// deliveries attempted.
+func (a *App) ProcessWebhookDeliveries(now int64) (int, error) {
+	attempted := 0
+	for {
+		deliveries, err := a.store.GetDueWebhookDeliveries(now, webhookDeliveriesBatchSize)
+		if err != nil {
+			return attempted, err
+		}
+		if len(deliveries) == 0 {
+			return attempted, nil
+		}
+
+		for _, delivery := range deliveries {
+			ok, err := a.attemptWebhookDelivery(delivery, now)
+			if err != nil {
+				return attempted, err
+			}
+			if ok {
+				attempted++
+			}
+		}
+	}
+}
+
+// CleanupWebhookDeliveries deletes the deliveries older than the
+// retention of the delivery log at a time in milliseconds since the
+// epoch, and returns the number of deliveries deleted.
+func (a *App) CleanupWebhookDeliveries(now int64) (int64, error) {
+	return a.store.DeleteWebhookDeliveriesBefore(now - webhookDeliveryLogRetention.Milliseconds())
+}
+
+// attemptWebhookDelivery claims a due delivery and posts it to its
+// webhook, recording the outcome of the attempt. It returns false if the
+// delivery was claimed by another server.
+func (a *App) attemptWebhookDelivery(delivery *model.WebhookDelivery, now int64) (bool, error) {
+	claimed, err := a.store.ClaimWebhookDelivery(delivery.ID, delivery.NextAttemptAt, now+webhookDeliveryLease.Milliseconds())
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	webhook, err := a.store.GetWebhook(delivery.WebhookID)
+	switch {
+	case model.IsErrNotFound(err):
+		delivery.Fail(utils.GetMillis(), "the webhook was deleted")
+	case err != nil:
+		return false, err
+	case webhook.Disabled:
+		delivery.Fail(utils.GetMillis(), "the webhook is disabled")
+	default:
+		code, body, deliverErr := a.webhook.Deliver(webhook.URL, webhook.Secret, delivery)
+		delivery.RecordAttempt(utils.GetMillis(), code, body, deliverErr)
+		if deliverErr != nil {
+			a.logger.Warn("Webhook delivery failed",
+				mlog.String("webhook_id", webhook.ID),
+				mlog.String("delivery_id", delivery.ID),
+				mlog.Int("attempts", delivery.Attempts),
+				mlog.String("status", delivery.Status),
+				mlog.Err(deliverErr),
+			)
+		}
+	}
+
+	if err := a.store.UpdateWebhookDelivery(delivery); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// notifyWebhooksBlockChanged queues the webhook deliveries of a block
+// change: a card created, a property of a card changed or a comment
+// added.
+func (a *App) notifyWebhooksBlockChanged(action notify.Action, board *model.Board, card, block, oldBlock *model.Block, modifiedByID string) {
+	payload := &model.WebhookPayload{
+		TeamID:    board.TeamID,
+		BoardID:   board.ID,
+		UserID:    modifiedByID,
+		Timestamp: utils.GetMillis(),
+	}
+
+	switch {
+	case block.Type == model.TypeCard && !isCardTemplate(block) && action == notify.Add:
+		payload.Event = model.WebhookEventCardCreated
+		payload.CardID = block.ID
+		payload.Data = map[string]interface{}{"card": webhookCard(block)}
+
+	case block.Type == model.TypeCard && !isCardTemplate(block) && action == notify.Update && oldBlock != nil:
+		changes := webhookPropertyChanges(board, oldBlock, block)
+		if len(changes) == 0 {
+			return
+		}
+		payload.Event = model.WebhookEventCardPropertyChanged
+		payload.CardID = block.ID
+		payload.Data = map[string]interface{}{"card": webhookCard(block), "changes": changes}
+
+	case block.Type == model.TypeComment && action == notify.Add:
+		payload.Event = model.WebhookEventCommentAdded
+		if card != nil {
+			payload.CardID = card.ID
+		}
+		payload.Data = map[string]interface{}{"comment": block}
+
+	default:
+		return
+	}
+
+	a.queueWebhookDeliveries(board, payload)
+}
+
+// notifyWebhooksMemberAdded queues the webhook deliveries of a member
+// added to a board.
+func (a *App) notifyWebhooksMemberAdded(board *model.Board, member *model.BoardMember) {
+	a.queueWebhookDeliveries(board, &model.WebhookPayload{
+		Event:     model.WebhookEventMemberAdded,
+		TeamID:    board.TeamID,
+		BoardID:   board.ID,
+		UserID:    member.UserID,
+		Timestamp: utils.GetMillis(),
+		Data:      map[string]interface{}{"member": member},
+	})
+}
+
+// queueWebhookDeliveries creates a pending delivery of an event for each
+// enabled webhook of the board or its team that receives the event and
+// whose creator can view the board. The deliveries are attempted by
+// ProcessWebhookDeliveries.
+func (a *App) queueWebhookDeliveries(board *model.Board, payload *model.WebhookPayload) {
+	if board.IsTemplate {
+		return
+	}
+
+	webhooks, err := a.store.GetActiveWebhooks(board.TeamID, board.ID)
+	if err != nil {
+		a.logger.Error("Error getting the webhooks of a board", mlog.String("board_id", board.ID), mlog.Err(err))
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		a.logger.Error("Error encoding a webhook payload", mlog.String("event", payload.Event), mlog.Err(err))
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.HasEvent(payload.Event) || !a.permissions.HasPermissionToBoard(webhook.CreatedBy, board.ID, model.PermissionViewBoard) {
+			continue
+		}
+		_, err := a.store.InsertWebhookDelivery(&model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			BoardID:       board.ID,
+			Event:         payload.Event,
+			Payload:       body,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: payload.Timestamp,
+		})
+		if err != nil {
+			a.logger.Error("Error queueing a webhook delivery",
+				mlog.String("webhook_id", webhook.ID),
+				mlog.String("event", payload.Event),
+				mlog.Err(err),
+			)
+		}
+	}
+}
+
+// webhookCard returns the card of a block for a webhook payload, or the
+// block if it isn't a valid card.
+func webhookCard(block *model.Block) interface{} {
+	card, err := model.Block2Card(block)
+	if err != nil {
+		return block
+	}
+	return card
+}
+
+// webhookPropertyChanges returns the properties of a card that changed,
+// ordered by property ID.
+func webhookPropertyChanges(board *model.Board, oldCard, newCard *model.Block) []model.WebhookPropertyChange {
+	oldProperties, _ := oldCard.Fields["properties"].(map[string]interface{})
+	newProperties, _ := newCard.Fields["properties"].(map[string]interface{})
+
+	propertyIDs := []string{}
+	for id, value := range newProperties {
+		if !reflect.DeepEqual(oldProperties[id], value) {
+			propertyIDs = append(propertyIDs, id)
+		}
+	}
+	for id := range oldProperties {
+		if _, ok := newProperties[id]; !ok {
+			propertyIDs = append(propertyIDs, id)
+		}
+	}
+	sort.Strings(propertyIDs)
+
+	names := map[string]string{}
+	for _, property := range board.CardProperties {
+		id, _ := property["id"].(string)
+		name, _ := property["name"].(string)
+		names[id] = name
+	}
+
+	changes := make([]model.WebhookPropertyChange, 0, len(propertyIDs))
+	for _, id := range propertyIDs {
+		changes = append(changes, model.WebhookPropertyChange{
+			PropertyID:   id,
+			PropertyName: names[id],
+			OldValue:     oldProperties[id],
+			NewValue:     newProperties[id],
+		})
+	}
+	return changes
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/api"
@@ -343,6 +345,21 @@ func (c *Client) CreateCard(boardID string, card *model.Card, disableNotify bool
 	return cardNew, BuildResponse(r)
 }
 
+func (c *Client) CreateCardFromTemplate(boardID, templateID string, request *model.CardFromTemplateRequest) (*model.Card, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/cards/from-template/"+templateID, toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var card *model.Card
+	if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return card, BuildResponse(r)
+}
+
 func (c *Client) GetCards(boardID string, page int, perPage int) ([]*model.Card, *Response) {
 	url := fmt.Sprintf("%s/cards?page=%d&per_page=%d", c.GetBoardRoute(boardID), page, perPage)
 	r, err := c.DoAPIGet(url, "")
@@ -360,6 +377,43 @@ func (c *Client) GetCards(boardID string, page int, perPage int) ([]*model.Card,
 	return cards, BuildResponse(r)
 }
 
+// GetCardsByChecklist returns a page of the cards of a board filtered and
+// sorted by the progress of their checklists.
+func (c *Client) GetCardsByChecklist(boardID string, checklist model.ChecklistQuery, page int, perPage int) ([]*model.Card, *Response) {
+	url := fmt.Sprintf("%s/cards?page=%d&per_page=%d&checklist=%s&checklist_sort=%s",
+		c.GetBoardRoute(boardID), page, perPage, checklist.Filter, checklist.Sort)
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var cards []*model.Card
+	if err := json.NewDecoder(r.Body).Decode(&cards); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return cards, BuildResponse(r)
+}
+
+func (c *Client) QueryCards(boardID string, opts model.QueryCardsOptions, page int, perPage int) (*model.CardQueryResult, *Response) {
+	url := fmt.Sprintf("%s/cards/query?page=%d&per_page=%d", c.GetBoardRoute(boardID), page, perPage)
+	r, err := c.DoAPIPost(url, toJSON(opts))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	defer closeBody(r)
+
+	var result *model.CardQueryResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
 func (c *Client) PatchCard(cardID string, cardPatch *model.CardPatch, disableNotify bool) (*model.Card, *Response) {
 	var queryParams string
 	if disableNotify {
@@ -380,6 +434,22 @@ func (c *Client) PatchCard(cardID string, cardPatch *model.CardPatch, disableNot
 	return cardNew, BuildResponse(r)
 }
 
+func (c *Client) GetCardActivity(cardID string, page int, perPage int) ([]*model.CardActivity, *Response) {
+	url := fmt.Sprintf("%s/activity?page=%d&per_page=%d", c.GetCardRoute(cardID), page, perPage)
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var activity []*model.CardActivity
+	if err := json.NewDecoder(r.Body).Decode(&activity); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return activity, BuildResponse(r)
+}
+
 func (c *Client) GetCard(cardID string) (*model.Card, *Response) {
 	r, err := c.DoAPIGet(c.GetCardRoute(cardID), "")
 	if err != nil {
@@ -396,6 +466,47 @@ func (c *Client) GetCard(cardID string) (*model.Card, *Response) {
 	return card, BuildResponse(r)
 }
 
+func (c *Client) GetCardDependencies(cardID string) (*model.CardDependencies, *Response) {
+	r, err := c.DoAPIGet(c.GetCardRoute(cardID)+"/dependencies", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var dependencies *model.CardDependencies
+	if err := json.NewDecoder(r.Body).Decode(&dependencies); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return dependencies, BuildResponse(r)
+}
+
+func (c *Client) AddCardDependency(cardID, blockerID string) (*model.CardDependency, *Response) {
+	request := model.CardDependencyRequest{BlockerID: blockerID}
+	r, err := c.DoAPIPost(c.GetCardRoute(cardID)+"/dependencies", toJSON(request))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var dependency *model.CardDependency
+	if err := json.NewDecoder(r.Body).Decode(&dependency); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return dependency, BuildResponse(r)
+}
+
+func (c *Client) DeleteCardDependency(cardID, blockerID string) *Response {
+	r, err := c.DoAPIDelete(c.GetCardRoute(cardID)+"/dependencies/"+blockerID, "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
 //
 // Boards and blocks.
 //
@@ -658,267 +769,757 @@ func (c *Client) PatchBoard(boardID string, patch *model.BoardPatch) (*model.Boa
 	return model.BoardFromJSON(r.Body), BuildResponse(r)
 }
 
-func (c *Client) DeleteBoard(boardID string) (bool, *Response) {
-	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID), "")
+func (c *Client) ConvertCardProperty(boardID, propertyID string, conversion *model.PropertyConversion) (*model.PropertyConversionResult, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/properties/"+propertyID+"/convert", toJSON(conversion))
 	if err != nil {
-		return false, BuildErrorResponse(r, err)
+		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return true, BuildResponse(r)
+	var result *model.PropertyConversionResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
 }
 
-func (c *Client) UndeleteBoard(boardID string) (bool, *Response) {
-	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/undelete", "")
+func (c *Client) GetRecurringCardRules(boardID string) ([]*model.RecurringCardRule, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/recurring-cards", "")
 	if err != nil {
-		return false, BuildErrorResponse(r, err)
+		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return true, BuildResponse(r)
-}
-
-func (c *Client) GetBoard(boardID, readToken string) (*model.Board, *Response) {
-	url := c.GetBoardRoute(boardID)
-	if readToken != "" {
-		url += fmt.Sprintf("?read_token=%s", readToken)
+	var rules []*model.RecurringCardRule
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	}
 
-	r, err := c.DoAPIGet(url, "")
+	return rules, BuildResponse(r)
+}
+
+func (c *Client) CreateRecurringCardRule(boardID string, rule *model.RecurringCardRule) (*model.RecurringCardRule, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/recurring-cards", toJSON(rule))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardFromJSON(r.Body), BuildResponse(r)
-}
-
-func (c *Client) GetBoardMetadata(boardID, readToken string) (*model.BoardMetadata, *Response) {
-	url := c.GetBoardMetadataRoute(boardID)
-	if readToken != "" {
-		url += fmt.Sprintf("?read_token=%s", readToken)
+	var created *model.RecurringCardRule
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	}
 
-	r, err := c.DoAPIGet(url, "")
+	return created, BuildResponse(r)
+}
+
+func (c *Client) PatchRecurringCardRule(boardID, ruleID string, patch *model.RecurringCardRulePatch) (*model.RecurringCardRule, *Response) {
+	r, err := c.DoAPIPatch(c.GetBoardRoute(boardID)+"/recurring-cards/"+ruleID, toJSON(patch))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMetadataFromJSON(r.Body), BuildResponse(r)
+	var rule *model.RecurringCardRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rule, BuildResponse(r)
 }
 
-func (c *Client) GetBoardsForTeam(teamID string) ([]*model.Board, *Response) {
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards", "")
+func (c *Client) DeleteRecurringCardRule(boardID, ruleID string) *Response {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID)+"/recurring-cards/"+ruleID, "")
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	return BuildResponse(r)
 }
 
-func (c *Client) SearchBoardsForUser(teamID, term string, field model.BoardSearchField) ([]*model.Board, *Response) {
-	query := fmt.Sprintf("q=%s&field=%s", term, field)
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?"+query, "")
+func (c *Client) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/due-date-reminders", "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	var settings *model.DueDateReminderSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return settings, BuildResponse(r)
 }
 
-func (c *Client) SearchBoardsForTeam(teamID, term string) ([]*model.Board, *Response) {
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?q="+term, "")
+func (c *Client) SaveDueDateReminderSettings(boardID string, settings *model.DueDateReminderSettings) (*model.DueDateReminderSettings, *Response) {
+	r, err := c.DoAPIPut(c.GetBoardRoute(boardID)+"/due-date-reminders", toJSON(settings))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	var saved *model.DueDateReminderSettings
+	if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return saved, BuildResponse(r)
 }
 
-func (c *Client) GetMembersForBoard(boardID string) ([]*model.BoardMember, *Response) {
-	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/members", "")
+func (c *Client) DeleteDueDateReminderSettings(boardID string) *Response {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID)+"/due-date-reminders", "")
 	if err != nil {
-		return nil, BuildErrorResponse(r, err)
+		return BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMembersFromJSON(r.Body), BuildResponse(r)
+	return BuildResponse(r)
 }
 
-func (c *Client) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPost(c.GetBoardRoute(member.BoardID)+"/members", toJSON(member))
+func (c *Client) GetOverdueCards(boardID, propertyID string) ([]*model.Card, *Response) {
+	route := c.GetBoardRoute(boardID) + "/cards/overdue"
+	if propertyID != "" {
+		route += "?property=" + propertyID
+	}
+	r, err := c.DoAPIGet(route, "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	var cards []*model.Card
+	if err := json.NewDecoder(r.Body).Decode(&cards); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return cards, BuildResponse(r)
 }
 
-func (c *Client) JoinBoard(boardID string) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPost(c.GetJoinBoardRoute(boardID), "")
+func (c *Client) GetWebhookRoute(webhookID string) string {
+	return fmt.Sprintf("/webhooks/%s", webhookID)
+}
+
+func (c *Client) GetBoardWebhooks(boardID string) ([]*model.Webhook, *Response) {
+	return c.getWebhooks(c.GetBoardRoute(boardID) + "/webhooks")
+}
+
+func (c *Client) CreateBoardWebhook(boardID string, webhook *model.Webhook) (*model.Webhook, *Response) {
+	return c.createWebhook(c.GetBoardRoute(boardID)+"/webhooks", webhook)
+}
+
+func (c *Client) GetTeamWebhooks(teamID string) ([]*model.Webhook, *Response) {
+	return c.getWebhooks(c.GetTeamRoute(teamID) + "/webhooks")
+}
+
+func (c *Client) CreateTeamWebhook(teamID string, webhook *model.Webhook) (*model.Webhook, *Response) {
+	return c.createWebhook(c.GetTeamRoute(teamID)+"/webhooks", webhook)
+}
+
+func (c *Client) getWebhooks(route string) ([]*model.Webhook, *Response) {
+	r, err := c.DoAPIGet(route, "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	var webhooks []*model.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhooks); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return webhooks, BuildResponse(r)
 }
 
-func (c *Client) LeaveBoard(boardID string) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPost(c.GetLeaveBoardRoute(boardID), "")
+func (c *Client) createWebhook(route string, webhook *model.Webhook) (*model.Webhook, *Response) {
+	r, err := c.DoAPIPost(route, toJSON(webhook))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	var created *model.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return created, BuildResponse(r)
 }
 
-func (c *Client) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember, *Response) {
-	r, err := c.DoAPIPut(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, toJSON(member))
+func (c *Client) GetWebhook(webhookID string) (*model.Webhook, *Response) {
+	r, err := c.DoAPIGet(c.GetWebhookRoute(webhookID), "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+	var webhook *model.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return webhook, BuildResponse(r)
 }
 
-func (c *Client) DeleteBoardMember(member *model.BoardMember) (bool, *Response) {
-	r, err := c.DoAPIDelete(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, "")
+func (c *Client) PatchWebhook(webhookID string, patch *model.WebhookPatch) (*model.Webhook, *Response) {
+	r, err := c.DoAPIPatch(c.GetWebhookRoute(webhookID), toJSON(patch))
 	if err != nil {
-		return false, BuildErrorResponse(r, err)
+		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return true, BuildResponse(r)
-}
+	var webhook *model.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
 
-func (c *Client) GetTeamUploadFileRoute(teamID, boardID string) string {
-	return fmt.Sprintf("%s/%s/files", c.GetTeamRoute(teamID), boardID)
+	return webhook, BuildResponse(r)
 }
 
-func (c *Client) TeamUploadFile(teamID, boardID string, data io.Reader) (*api.FileUploadResponse, *Response) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+func (c *Client) DeleteWebhook(webhookID string) *Response {
+	r, err := c.DoAPIDelete(c.GetWebhookRoute(webhookID), "")
 	if err != nil {
-		return nil, &Response{Error: err}
-	}
-	if _, err = io.Copy(part, data); err != nil {
-		return nil, &Response{Error: err}
+		return BuildErrorResponse(r, err)
 	}
-	writer.Close()
+	defer closeBody(r)
 
-	opt := func(r *http.Request) {
-		r.Header.Add("Content-Type", writer.FormDataContentType())
+	return BuildResponse(r)
+}
+
+func (c *Client) GetWebhookDeliveries(webhookID, status string, page, perPage int) ([]*model.WebhookDelivery, *Response) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
 	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
 
-	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamUploadFileRoute(teamID, boardID), body, "", opt)
+	r, err := c.DoAPIGet(c.GetWebhookRoute(webhookID)+"/deliveries?"+query.Encode(), "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	fileUploadResponse, err := api.FileUploadResponseFromJSON(r.Body)
-	if err != nil {
+	var deliveries []*model.WebhookDelivery
+	if err := json.NewDecoder(r.Body).Decode(&deliveries); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 
-	return fileUploadResponse, BuildResponse(r)
+	return deliveries, BuildResponse(r)
 }
 
-func (c *Client) TeamUploadFileInfo(teamID, boardID string, fileName string) (*mmModel.FileInfo, *Response) {
-	r, err := c.DoAPIGet(fmt.Sprintf("/files/teams/%s/%s/%s/info", teamID, boardID, fileName), "")
+func (c *Client) RedeliverWebhookDelivery(webhookID, deliveryID string) (*model.WebhookDelivery, *Response) {
+	r, err := c.DoAPIPost(c.GetWebhookRoute(webhookID)+"/deliveries/"+deliveryID+"/redeliver", "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
-	fileInfoResponse, error := api.FileInfoResponseFromJSON(r.Body)
-	if error != nil {
-		return nil, BuildErrorResponse(r, error)
+
+	var delivery *model.WebhookDelivery
+	if err := json.NewDecoder(r.Body).Decode(&delivery); err != nil {
+		return nil, BuildErrorResponse(r, err)
 	}
-	return fileInfoResponse, BuildResponse(r)
+
+	return delivery, BuildResponse(r)
 }
 
-func (c *Client) GetSubscriptionsRoute() string {
-	return "/subscriptions"
+func (c *Client) GetIncomingWebhookRoute(webhookID string) string {
+	return fmt.Sprintf("/incoming-webhooks/%s", webhookID)
 }
 
-func (c *Client) CreateSubscription(sub *model.Subscription) (*model.Subscription, *Response) {
-	r, err := c.DoAPIPost(c.GetSubscriptionsRoute(), toJSON(&sub))
+func (c *Client) GetIncomingWebhooks(boardID string) ([]*model.IncomingWebhook, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/incoming-webhooks", "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	subNew, err := model.SubscriptionFromJSON(r.Body)
-	if err != nil {
+	var webhooks []*model.IncomingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhooks); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
-	return subNew, BuildResponse(r)
-}
-
-func (c *Client) DeleteSubscription(blockID string, subscriberID string) *Response {
-	url := fmt.Sprintf("%s/%s/%s", c.GetSubscriptionsRoute(), blockID, subscriberID)
-
-	r, err := c.DoAPIDelete(url, "")
-	if err != nil {
-		return BuildErrorResponse(r, err)
-	}
-	defer closeBody(r)
 
-	return BuildResponse(r)
+	return webhooks, BuildResponse(r)
 }
 
-func (c *Client) GetSubscriptions(subscriberID string) ([]*model.Subscription, *Response) {
-	url := fmt.Sprintf("%s/%s", c.GetSubscriptionsRoute(), subscriberID)
-
-	r, err := c.DoAPIGet(url, "")
+func (c *Client) CreateIncomingWebhook(boardID string, webhook *model.IncomingWebhook) (*model.IncomingWebhook, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/incoming-webhooks", toJSON(webhook))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	var subs []*model.Subscription
-	err = json.NewDecoder(r.Body).Decode(&subs)
-	if err != nil {
+	var created *model.IncomingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 
-	return subs, BuildResponse(r)
+	return created, BuildResponse(r)
 }
 
-func (c *Client) GetTemplatesForTeam(teamID string) ([]*model.Board, *Response) {
-	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/templates", "")
+func (c *Client) GetIncomingWebhook(webhookID string) (*model.IncomingWebhook, *Response) {
+	r, err := c.DoAPIGet(c.GetIncomingWebhookRoute(webhookID), "")
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+	var webhook *model.IncomingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return webhook, BuildResponse(r)
 }
 
-func (c *Client) ExportBoardArchive(boardID string) ([]byte, *Response) {
-	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/archive/export", "")
+func (c *Client) PatchIncomingWebhook(webhookID string, patch *model.IncomingWebhookPatch) (*model.IncomingWebhook, *Response) {
+	r, err := c.DoAPIPatch(c.GetIncomingWebhookRoute(webhookID), toJSON(patch))
 	if err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
 	defer closeBody(r)
 
-	buf, err := io.ReadAll(r.Body)
-	if err != nil {
+	var webhook *model.IncomingWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
 		return nil, BuildErrorResponse(r, err)
 	}
-	return buf, BuildResponse(r)
+
+	return webhook, BuildResponse(r)
 }
 
-func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
+func (c *Client) DeleteIncomingWebhook(webhookID string) *Response {
+	r, err := c.DoAPIDelete(c.GetIncomingWebhookRoute(webhookID), "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+// ExecuteIncomingWebhook posts a JSON payload to an incoming webhook with
+// its token.
+func (c *Client) ExecuteIncomingWebhook(webhookID, token, payload string) (*model.IncomingWebhookResult, *Response) {
+	opt := func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set(api.HeaderIncomingWebhookToken, token)
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+"/hooks/"+webhookID, strings.NewReader(payload), "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.IncomingWebhookResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) DeleteBoard(boardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID), "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) UndeleteBoard(boardID string) (bool, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/undelete", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) RestoreBoard(boardID string, opts *model.BoardRestoreOptions) (*model.BoardRestoreResult, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/restore", toJSON(opts))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.BoardRestoreResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+// Trash
+
+func (c *Client) GetTeamTrashRoute(teamID string) string {
+	return fmt.Sprintf("%s/trash/boards", c.GetTeamRoute(teamID))
+}
+
+func (c *Client) GetBoardTrashRoute(boardID string) string {
+	return fmt.Sprintf("%s/trash/blocks", c.GetBoardRoute(boardID))
+}
+
+func (c *Client) GetDeletedBoards(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamTrashRoute(teamID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RestoreDeletedBoard(teamID, boardID string) (*model.Board, *Response) {
+	r, err := c.DoAPIPost(c.GetTeamTrashRoute(teamID)+"/"+boardID+"/restore", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) PurgeBoard(teamID, boardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetTeamTrashRoute(teamID)+"/"+boardID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetDeletedBlocks(boardID string) ([]*model.Block, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardTrashRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BlocksFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) RestoreDeletedBlock(boardID, blockID string) (*model.Block, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardTrashRoute(boardID)+"/"+blockID+"/restore", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var block *model.Block
+	if err := json.NewDecoder(r.Body).Decode(&block); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return block, BuildResponse(r)
+}
+
+func (c *Client) PurgeBlock(boardID, blockID string) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardTrashRoute(boardID)+"/"+blockID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetBoard(boardID, readToken string) (*model.Board, *Response) {
+	url := c.GetBoardRoute(boardID)
+	if readToken != "" {
+		url += fmt.Sprintf("?read_token=%s", readToken)
+	}
+
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBoardMetadata(boardID, readToken string) (*model.BoardMetadata, *Response) {
+	url := c.GetBoardMetadataRoute(boardID)
+	if readToken != "" {
+		url += fmt.Sprintf("?read_token=%s", readToken)
+	}
+
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMetadataFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) GetBoardsForTeam(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) SearchBoardsForUser(teamID, term string, field model.BoardSearchField) ([]*model.Board, *Response) {
+	query := fmt.Sprintf("q=%s&field=%s", term, field)
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?"+query, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) SearchBoardsForTeam(teamID, term string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/boards/search?q="+term, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) SearchCardsForTeam(teamID, term string, page int, perPage int) ([]*model.CardSearchResult, *Response) {
+	query := url.Values{}
+	query.Set("q", term)
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/cards/search?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var results []*model.CardSearchResult
+	if err := json.NewDecoder(r.Body).Decode(&results); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return results, BuildResponse(r)
+}
+
+func (c *Client) GetMembersForBoard(boardID string) ([]*model.BoardMember, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/members", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMembersFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) AddMemberToBoard(member *model.BoardMember) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(member.BoardID)+"/members", toJSON(member))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) JoinBoard(boardID string) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPost(c.GetJoinBoardRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) LeaveBoard(boardID string) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPost(c.GetLeaveBoardRoute(boardID), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember, *Response) {
+	r, err := c.DoAPIPut(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, toJSON(member))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardMemberFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) DeleteBoardMember(member *model.BoardMember) (bool, *Response) {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(member.BoardID)+"/members/"+member.UserID, "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) GetTeamUploadFileRoute(teamID, boardID string) string {
+	return fmt.Sprintf("%s/%s/files", c.GetTeamRoute(teamID), boardID)
+}
+
+func (c *Client) TeamUploadFile(teamID, boardID string, data io.Reader) (*api.FileUploadResponse, *Response) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
+	if err != nil {
+		return nil, &Response{Error: err}
+	}
+	if _, err = io.Copy(part, data); err != nil {
+		return nil, &Response{Error: err}
+	}
+	writer.Close()
+
+	opt := func(r *http.Request) {
+		r.Header.Add("Content-Type", writer.FormDataContentType())
+	}
+
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamUploadFileRoute(teamID, boardID), body, "", opt)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	fileUploadResponse, err := api.FileUploadResponseFromJSON(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return fileUploadResponse, BuildResponse(r)
+}
+
+func (c *Client) TeamUploadFileInfo(teamID, boardID string, fileName string) (*mmModel.FileInfo, *Response) {
+	r, err := c.DoAPIGet(fmt.Sprintf("/files/teams/%s/%s/%s/info", teamID, boardID, fileName), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+	fileInfoResponse, error := api.FileInfoResponseFromJSON(r.Body)
+	if error != nil {
+		return nil, BuildErrorResponse(r, error)
+	}
+	return fileInfoResponse, BuildResponse(r)
+}
+
+func (c *Client) GetSubscriptionsRoute() string {
+	return "/subscriptions"
+}
+
+func (c *Client) CreateSubscription(sub *model.Subscription) (*model.Subscription, *Response) {
+	r, err := c.DoAPIPost(c.GetSubscriptionsRoute(), toJSON(&sub))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	subNew, err := model.SubscriptionFromJSON(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return subNew, BuildResponse(r)
+}
+
+func (c *Client) DeleteSubscription(blockID string, subscriberID string) *Response {
+	url := fmt.Sprintf("%s/%s/%s", c.GetSubscriptionsRoute(), blockID, subscriberID)
+
+	r, err := c.DoAPIDelete(url, "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) GetSubscriptions(subscriberID string) ([]*model.Subscription, *Response) {
+	url := fmt.Sprintf("%s/%s", c.GetSubscriptionsRoute(), subscriberID)
+
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var subs []*model.Subscription
+	err = json.NewDecoder(r.Body).Decode(&subs)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return subs, BuildResponse(r)
+}
+
+func (c *Client) GetTemplatesForTeam(teamID string) ([]*model.Board, *Response) {
+	r, err := c.DoAPIGet(c.GetTeamRoute(teamID)+"/templates", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return model.BoardsFromJSON(r.Body), BuildResponse(r)
+}
+
+func (c *Client) ExportBoardArchive(boardID string) ([]byte, *Response) {
+	return c.exportArchive(c.GetBoardRoute(boardID)+"/archive/export", 0)
+}
+
+// ExportBoardArchiveModifiedSince exports an incremental archive of the
+// changes made to a board after modifiedSince.
+func (c *Client) ExportBoardArchiveModifiedSince(boardID string, modifiedSince int64) ([]byte, *Response) {
+	return c.exportArchive(c.GetBoardRoute(boardID)+"/archive/export", modifiedSince)
+}
+
+func (c *Client) ExportTeamArchiveModifiedSince(teamID string, modifiedSince int64) ([]byte, *Response) {
+	return c.exportArchive(c.GetTeamRoute(teamID)+"/archive/export", modifiedSince)
+}
+
+func (c *Client) exportArchive(route string, modifiedSince int64) ([]byte, *Response) {
+	if modifiedSince > 0 {
+		route += fmt.Sprintf("?modified_since=%d", modifiedSince)
+	}
+
+	r, err := c.DoAPIGet(route, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	return buf, BuildResponse(r)
+}
+
+func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
+	return c.importArchive(c.GetTeamRoute(teamID)+"/archive/import", data)
+}
+
+// ImportIncrementalArchive imports an archive keeping its IDs, so it
+// updates the boards imported from the previous archives.
+func (c *Client) ImportIncrementalArchive(teamID string, data io.Reader) *Response {
+	return c.importArchive(c.GetTeamRoute(teamID)+"/archive/import?incremental=true", data)
+}
+
+func (c *Client) importArchive(route string, data io.Reader) *Response {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	part, err := writer.CreateFormFile(api.UploadFormFileKey, "file")
@@ -934,7 +1535,7 @@ func (c *Client) ImportArchive(teamID string, data io.Reader) *Response {
 		r.Header.Add("Content-Type", writer.FormDataContentType())
 	}
 
-	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+c.GetTeamRoute(teamID)+"/archive/import", body, "", opt)
+	r, err := c.doAPIRequestReader(http.MethodPost, c.APIURL+route, body, "", opt)
 	if err != nil {
 		return BuildErrorResponse(r, err)
 	}
@@ -1008,6 +1609,79 @@ func (c *Client) GetBlocksComplianceHistory(
 	return res, BuildResponse(r)
 }
 
+func (c *Client) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, *Response) {
+	r, err := c.DoAPIGet("/admin/retention/teams/"+teamID+"/policies", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var policies []*model.RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policies); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return policies, BuildResponse(r)
+}
+
+func (c *Client) SaveTeamRetentionPolicy(teamID string, policy *model.RetentionPolicy) (*model.RetentionPolicy, *Response) {
+	return c.saveRetentionPolicy("/admin/retention/teams/"+teamID+"/policy", policy)
+}
+
+func (c *Client) SaveBoardRetentionPolicy(boardID string, policy *model.RetentionPolicy) (*model.RetentionPolicy, *Response) {
+	return c.saveRetentionPolicy("/admin/retention/boards/"+boardID+"/policy", policy)
+}
+
+func (c *Client) saveRetentionPolicy(url string, policy *model.RetentionPolicy) (*model.RetentionPolicy, *Response) {
+	r, err := c.DoAPIPut(url, toJSON(policy))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var saved *model.RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return saved, BuildResponse(r)
+}
+
+func (c *Client) DeleteTeamRetentionPolicy(teamID string) (bool, *Response) {
+	r, err := c.DoAPIDelete("/admin/retention/teams/"+teamID+"/policy", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) DeleteBoardRetentionPolicy(boardID string) (bool, *Response) {
+	r, err := c.DoAPIDelete("/admin/retention/boards/"+boardID+"/policy", "")
+	if err != nil {
+		return false, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return true, BuildResponse(r)
+}
+
+func (c *Client) RunDataRetention() (*model.DataRetentionReport, *Response) {
+	r, err := c.DoAPIPost("/admin/retention/run", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var report *model.DataRetentionReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return report, BuildResponse(r)
+}
+
 func (c *Client) HideBoard(teamID, categoryID, boardID string) *Response {
 	r, err := c.DoAPIPut(c.GetTeamRoute(teamID)+"/categories/"+categoryID+"/boards/"+boardID+"/hide", "")
 	if err != nil {
@@ -1027,3 +1701,108 @@ func (c *Client) UnhideBoard(teamID, categoryID, boardID string) *Response {
 	defer closeBody(r)
 	return BuildResponse(r)
 }
+
+func (c *Client) ScanInvalidCardProperties(teamID string, page, perPage int) (*model.InvalidCardPropertiesResponse, *Response) {
+	url := fmt.Sprintf("/admin/teams/%s/invalid_card_properties?page=%d&per_page=%d", teamID, page, perPage)
+	r, err := c.DoAPIGet(url, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var result *model.InvalidCardPropertiesResponse
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return result, BuildResponse(r)
+}
+
+func (c *Client) GetAutomationRules(boardID string) ([]*model.AutomationRule, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/automations", "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var rules []*model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rules, BuildResponse(r)
+}
+
+func (c *Client) CreateAutomationRule(boardID string, rule *model.AutomationRule) (*model.AutomationRule, *Response) {
+	r, err := c.DoAPIPost(c.GetBoardRoute(boardID)+"/automations", toJSON(rule))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var created *model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return created, BuildResponse(r)
+}
+
+func (c *Client) GetAutomationRule(boardID, ruleID string) (*model.AutomationRule, *Response) {
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/automations/"+ruleID, "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var rule *model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rule, BuildResponse(r)
+}
+
+func (c *Client) PatchAutomationRule(boardID, ruleID string, patch *model.AutomationRulePatch) (*model.AutomationRule, *Response) {
+	r, err := c.DoAPIPatch(c.GetBoardRoute(boardID)+"/automations/"+ruleID, toJSON(patch))
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var rule *model.AutomationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return rule, BuildResponse(r)
+}
+
+func (c *Client) DeleteAutomationRule(boardID, ruleID string) *Response {
+	r, err := c.DoAPIDelete(c.GetBoardRoute(boardID)+"/automations/"+ruleID, "")
+	if err != nil {
+		return BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	return BuildResponse(r)
+}
+
+func (c *Client) GetAutomationExecutions(boardID, ruleID string, page, perPage int) ([]*model.AutomationExecution, *Response) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	r, err := c.DoAPIGet(c.GetBoardRoute(boardID)+"/automations/"+ruleID+"/executions?"+query.Encode(), "")
+	if err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+	defer closeBody(r)
+
+	var executions []*model.AutomationExecution
+	if err := json.NewDecoder(r.Body).Decode(&executions); err != nil {
+		return nil, BuildErrorResponse(r, err)
+	}
+
+	return executions, BuildResponse(r)
+}
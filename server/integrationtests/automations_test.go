@@ -0,0 +1,333 @@
+package integrationtests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationRules(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	userID := th.GetUser1().ID
+
+	createBoard := func() *model.Board {
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_todo", "value": "To Do"},
+				map[string]interface{}{"id": "status_done", "value": "Done"},
+				map[string]interface{}{"id": "status_overdue", "value": "Overdue"},
+			}},
+			{"id": "assignee", "name": "Assignee", "type": "person", "options": []interface{}{}},
+			{"id": "completed", "name": "Completed", "type": "date", "options": []interface{}{}},
+			{"id": "due", "name": "Due", "type": "date", "options": []interface{}{}},
+		}})
+		th.CheckOK(resp)
+		return board
+	}
+
+	// waitForExecutions waits for the rule to be executed count times,
+	// as the rules run asynchronously, and returns the executions.
+	waitForExecutions := func(boardID, ruleID string, count int) []*model.AutomationExecution {
+		var executions []*model.AutomationExecution
+		require.Eventually(t, func() bool {
+			got, resp := th.Client.GetAutomationExecutions(boardID, ruleID, 0, 100)
+			th.CheckOK(resp)
+			executions = got
+			return len(executions) >= count
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Len(t, executions, count)
+		return executions
+	}
+
+	t.Run("complete a card when its status changes to done", func(t *testing.T) {
+		board := createBoard()
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Name:    "Complete",
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"Done"}},
+			Actions: []model.AutomationAction{
+				{Type: model.AutomationActionSetProperty, PropertyID: "completed", Value: "{{today}}"},
+				{Type: model.AutomationActionClearProperty, PropertyID: "assignee"},
+				{Type: model.AutomationActionAddComment, Text: "{{card.title}} is done"},
+			},
+		})
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, rule.BoardID)
+		require.Equal(t, userID, rule.CreatedBy)
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{
+			Title:      "Fix login",
+			Properties: map[string]any{"status": "status_todo", "assignee": userID},
+		}, false)
+		th.CheckOK(resp)
+
+		status := "status_done"
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{"status": status, "assignee": userID}}, false)
+		th.CheckOK(resp)
+
+		executions := waitForExecutions(board.ID, rule.ID, 1)
+		require.Equal(t, model.AutomationExecutionSuccess, executions[0].Status)
+		require.Equal(t, card.ID, executions[0].CardID)
+		require.Zero(t, executions[0].Depth)
+
+		card, resp = th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "status_done", card.Properties["status"])
+		require.Equal(t, model.DatePropertyValue(time.Now().UTC()), card.Properties["completed"])
+		require.NotContains(t, card.Properties, "assignee")
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		comments := []string{}
+		for _, block := range blocks {
+			if block.Type == model.TypeComment && block.ParentID == card.ID {
+				comments = append(comments, block.Title)
+				require.Equal(t, userID, block.CreatedBy)
+			}
+		}
+		require.Equal(t, []string{"Fix login is done"}, comments)
+
+		// the card isn't completed again when another property changes
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{"status": status, "assignee": userID}}, false)
+		th.CheckOK(resp)
+		time.Sleep(200 * time.Millisecond)
+		waitForExecutions(board.ID, rule.ID, 1)
+	})
+
+	t.Run("assign the created cards round-robin", func(t *testing.T) {
+		board := createBoard()
+		user2ID := th.GetUser2().ID
+		_, resp := th.Client.AddMemberToBoard(&model.BoardMember{UserID: user2ID, BoardID: board.ID, SchemeEditor: true})
+		th.CheckOK(resp)
+
+		users := []string{userID, user2ID}
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionAssignRoundRobin, PropertyID: "assignee", UserIDs: users}},
+		})
+		th.CheckOK(resp)
+
+		cardIDs := []string{}
+		for i := 0; i < 4; i++ {
+			card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "bug " + strconv.Itoa(i)}, false)
+			th.CheckOK(resp)
+			cardIDs = append(cardIDs, card.ID)
+		}
+		waitForExecutions(board.ID, rule.ID, 4)
+
+		assigned := map[string]int{}
+		for _, cardID := range cardIDs {
+			card, resp := th.Client.GetCard(cardID)
+			th.CheckOK(resp)
+			assigned[card.Properties["assignee"].(string)]++
+		}
+		require.Equal(t, map[string]int{userID: 2, user2ID: 2}, assigned)
+
+		rule, resp = th.Client.GetAutomationRule(board.ID, rule.ID)
+		th.CheckOK(resp)
+		require.Equal(t, int64(4), rule.RunCount)
+	})
+
+	t.Run("rules triggering each other stop", func(t *testing.T) {
+		board := createBoard()
+		reopen, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"Done"}},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "To Do"}},
+		})
+		th.CheckOK(resp)
+		close, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"To Do"}},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "Done"}},
+		})
+		th.CheckOK(resp)
+
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "ping pong"}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client.PatchCard(card.ID, &model.CardPatch{UpdatedProperties: map[string]any{"status": "status_done"}}, false)
+		th.CheckOK(resp)
+
+		// reopen runs, close runs, then reopen is skipped
+		executions := waitForExecutions(board.ID, reopen.ID, 2)
+		require.Equal(t, model.AutomationExecutionSkipped, executions[0].Status)
+		require.Contains(t, executions[0].Error, "loop detected")
+		require.Equal(t, 2, executions[0].Depth)
+		require.Equal(t, model.AutomationExecutionSuccess, executions[1].Status)
+		require.Zero(t, executions[1].Depth)
+
+		executions = waitForExecutions(board.ID, close.ID, 1)
+		require.Equal(t, model.AutomationExecutionSuccess, executions[0].Status)
+		require.Equal(t, 1, executions[0].Depth)
+
+		time.Sleep(200 * time.Millisecond)
+		waitForExecutions(board.ID, reopen.ID, 2)
+		card, resp = th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "status_done", card.Properties["status"])
+	})
+
+	t.Run("move the cards whose due date passed", func(t *testing.T) {
+		board := createBoard()
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerDueDatePassed, PropertyID: "due"},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "Overdue"}},
+		})
+		th.CheckOK(resp)
+
+		// the due day of the late card ended at midnight
+		now := time.Now().UTC().Truncate(24 * time.Hour).Add(12 * time.Hour)
+		due := model.DatePropertyValue(now.AddDate(0, 0, -1))
+		overdue, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "late", Properties: map[string]any{"due": due, "status": "status_todo"}}, false)
+		th.CheckOK(resp)
+		onTime, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "on time", Properties: map[string]any{"due": model.DatePropertyValue(now), "status": "status_todo"}}, false)
+		th.CheckOK(resp)
+
+		app := th.Server.App()
+		triggered, err := app.RunDueDateAutomations(now)
+		require.NoError(t, err)
+		require.Equal(t, 1, triggered)
+
+		// the rule runs once for each due date
+		triggered, err = app.RunDueDateAutomations(now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, triggered)
+
+		// the cards overdue for too long aren't moved by new rules
+		triggered, err = app.RunDueDateAutomations(now.AddDate(0, 0, 2))
+		require.NoError(t, err)
+		require.Zero(t, triggered)
+
+		executions := waitForExecutions(board.ID, rule.ID, 1)
+		require.Equal(t, overdue.ID, executions[0].CardID)
+		require.Equal(t, model.AutomationTriggerDueDatePassed, executions[0].TriggerType)
+
+		card, resp := th.Client.GetCard(overdue.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "status_overdue", card.Properties["status"])
+		card, resp = th.Client.GetCard(onTime.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "status_todo", card.Properties["status"])
+	})
+
+	t.Run("conditions and disabled rules", func(t *testing.T) {
+		board := createBoard()
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Conditions: &model.FilterGroup{Operation: model.FilterGroupOperationAnd, Filters: []model.FilterGroupItem{
+				{Clause: &model.FilterClause{PropertyID: "status", Condition: model.FilterConditionIncludes, Values: []string{"status_todo"}}},
+			}},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "assignee", Value: "{{creator}}"}},
+		})
+		th.CheckOK(resp)
+
+		skipped, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "no status"}, false)
+		th.CheckOK(resp)
+		matching, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "to do", Properties: map[string]any{"status": "status_todo"}}, false)
+		th.CheckOK(resp)
+		executions := waitForExecutions(board.ID, rule.ID, 1)
+		require.Equal(t, matching.ID, executions[0].CardID)
+
+		card, resp := th.Client.GetCard(skipped.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, card.Properties, "assignee")
+		card, resp = th.Client.GetCard(matching.ID)
+		th.CheckOK(resp)
+		require.Equal(t, userID, card.Properties["assignee"])
+
+		disabled := true
+		rule, resp = th.Client.PatchAutomationRule(board.ID, rule.ID, &model.AutomationRulePatch{Disabled: &disabled})
+		th.CheckOK(resp)
+		require.True(t, rule.Disabled)
+
+		_, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "disabled", Properties: map[string]any{"status": "status_todo"}}, false)
+		th.CheckOK(resp)
+		time.Sleep(200 * time.Millisecond)
+		waitForExecutions(board.ID, rule.ID, 1)
+	})
+
+	t.Run("manage the rules", func(t *testing.T) {
+		board := createBoard()
+		otherBoard := createBoard()
+		rule, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+			Name:    "Assign",
+			Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			Actions: []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "assignee", Value: "{{creator}}"}},
+		})
+		th.CheckOK(resp)
+
+		rules, resp := th.Client.GetAutomationRules(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule.ID, rules[0].ID)
+
+		name := "Assign to me"
+		patched, resp := th.Client.PatchAutomationRule(board.ID, rule.ID, &model.AutomationRulePatch{Name: &name})
+		th.CheckOK(resp)
+		require.Equal(t, name, patched.Name)
+		require.Equal(t, rule.Actions, patched.Actions)
+
+		t.Run("invalid rules are rejected", func(t *testing.T) {
+			_, resp := th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+				Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated},
+			})
+			th.CheckBadRequest(resp)
+
+			_, resp = th.Client.CreateAutomationRule(board.ID, &model.AutomationRule{
+				Trigger: model.AutomationTrigger{Type: model.AutomationTriggerDueDatePassed, PropertyID: "status"},
+				Actions: []model.AutomationAction{{Type: model.AutomationActionClearProperty, PropertyID: "assignee"}},
+			})
+			th.CheckBadRequest(resp)
+
+			actions := []model.AutomationAction{{Type: model.AutomationActionClearProperty, PropertyID: "missing"}}
+			_, resp = th.Client.PatchAutomationRule(board.ID, rule.ID, &model.AutomationRulePatch{Actions: &actions})
+			th.CheckBadRequest(resp)
+
+			_, resp = th.Client.GetAutomationExecutions(board.ID, rule.ID, -1, 10)
+			th.CheckBadRequest(resp)
+			_, resp = th.Client.GetAutomationExecutions(board.ID, rule.ID, 0, 1000)
+			th.CheckBadRequest(resp)
+		})
+
+		t.Run("the rules of another board aren't found", func(t *testing.T) {
+			_, resp := th.Client.GetAutomationRule(otherBoard.ID, rule.ID)
+			th.CheckNotFound(resp)
+			_, resp = th.Client.PatchAutomationRule(otherBoard.ID, rule.ID, &model.AutomationRulePatch{Name: &name})
+			th.CheckNotFound(resp)
+			resp = th.Client.DeleteAutomationRule(otherBoard.ID, rule.ID)
+			th.CheckNotFound(resp)
+			_, resp = th.Client.GetAutomationExecutions(otherBoard.ID, rule.ID, 0, 10)
+			th.CheckNotFound(resp)
+		})
+
+		t.Run("users without access can't manage the rules", func(t *testing.T) {
+			_, resp := th.Client2.GetAutomationRules(board.ID)
+			th.CheckForbidden(resp)
+			_, resp = th.Client2.GetAutomationRule(board.ID, rule.ID)
+			th.CheckForbidden(resp)
+			_, resp = th.Client2.CreateAutomationRule(board.ID, rule)
+			th.CheckForbidden(resp)
+			_, resp = th.Client2.PatchAutomationRule(board.ID, rule.ID, &model.AutomationRulePatch{Name: &name})
+			th.CheckForbidden(resp)
+			resp = th.Client2.DeleteAutomationRule(board.ID, rule.ID)
+			th.CheckForbidden(resp)
+			_, resp = th.Client2.GetAutomationExecutions(board.ID, rule.ID, 0, 10)
+			th.CheckForbidden(resp)
+		})
+
+		t.Run("delete a rule", func(t *testing.T) {
+			resp := th.Client.DeleteAutomationRule(board.ID, rule.ID)
+			th.CheckOK(resp)
+
+			_, resp = th.Client.GetAutomationRule(board.ID, rule.ID)
+			th.CheckNotFound(resp)
+			rules, resp := th.Client.GetAutomationRules(board.ID)
+			th.CheckOK(resp)
+			require.Empty(t, rules)
+		})
+	})
+}
@@ -159,6 +159,33 @@ func TestPostBlock(t *testing.T) {
 		require.NotNil(t, block4)
 		require.Equal(t, "Updated title", block4.Title)
 	})
+
+	t.Run("Blocks should match the definition of their type", func(t *testing.T) {
+		newBlock := func(blockType model.BlockType, parentID string, fields map[string]interface{}) []*model.Block {
+			return []*model.Block{{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  board.ID,
+				ParentID: parentID,
+				CreateAt: 1,
+				UpdateAt: 1,
+				Type:     blockType,
+				Fields:   fields,
+			}}
+		}
+
+		_, resp := th.Client.InsertBlocks(board.ID, newBlock("not-registered", board.ID, nil), false)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.InsertBlocks(board.ID, newBlock(model.TypeCard, blockID1, nil), false)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.InsertBlocks(board.ID, newBlock(model.TypeCheckbox, blockID1, map[string]interface{}{"value": "yes"}), false)
+		th.CheckBadRequest(resp)
+
+		newBlocks, resp := th.Client.InsertBlocks(board.ID, newBlock(model.TypeCheckbox, blockID1, map[string]interface{}{"value": true}), false)
+		th.CheckOK(resp)
+		require.Len(t, newBlocks, 1)
+	})
 }
 
 func TestPatchBlock(t *testing.T) {
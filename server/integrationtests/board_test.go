@@ -3,6 +3,7 @@ package integrationtests
 import (
 	"encoding/json"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -434,7 +435,7 @@ func TestGetAllBlocksForBoard(t *testing.T) {
 				ParentID: parentBlockID,
 				CreateAt: 2,
 				UpdateAt: 2,
-				Type:     model.TypeCard,
+				Type:     model.TypeText,
 			},
 			{
 				ID:       childBlockID2,
@@ -442,7 +443,7 @@ func TestGetAllBlocksForBoard(t *testing.T) {
 				ParentID: parentBlockID,
 				CreateAt: 2,
 				UpdateAt: 2,
-				Type:     model.TypeCard,
+				Type:     model.TypeText,
 			},
 		}
 
@@ -918,6 +919,34 @@ func TestPatchBoard(t *testing.T) {
 		require.Nil(t, board)
 	})
 
+	t.Run("outdated expected version on a board with permissions", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		user1 := th.GetUser1()
+
+		initialTitle := "title"
+		newBoard := &model.Board{
+			Title:  initialTitle,
+			Type:   model.BoardTypeOpen,
+			TeamID: teamID,
+		}
+		board, err := th.Server.App().CreateBoard(newBoard, user1.ID, true)
+		require.NoError(t, err)
+
+		outdated := board.UpdateAt - 1
+		newTitle := "a new title"
+		patch := &model.BoardPatch{Title: &newTitle, ExpectedUpdateAt: &outdated}
+
+		rBoard, resp := th.Client.PatchBoard(board.ID, patch)
+		th.CheckConflict(resp)
+		require.Nil(t, rBoard)
+
+		dbBoard, err := th.Server.App().GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, initialTitle, dbBoard.Title)
+	})
+
 	t.Run("invalid patch on a board with permissions", func(t *testing.T) {
 		th := SetupTestHelper(t).InitBasic()
 		defer th.TearDown()
@@ -2242,3 +2271,233 @@ func TestJoinBoard(t *testing.T) {
 		require.Nil(t, member)
 	})
 }
+
+func TestRestoreBoard(t *testing.T) {
+	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+		th.Logout(th.Client)
+
+		result, resp := th.Client.RestoreBoard(board.ID, &model.BoardRestoreOptions{RestoreAt: utils.GetMillis()})
+		th.CheckUnauthorized(resp)
+		require.Nil(t, result)
+	})
+
+	t.Run("a user without access to the board should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		result, resp := th.Client2.RestoreBoard(board.ID, &model.BoardRestoreOptions{RestoreAt: utils.GetMillis(), DryRun: true})
+		th.CheckForbidden(resp)
+		require.Nil(t, result)
+	})
+
+	t.Run("invalid restore time", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		result, resp := th.Client.RestoreBoard(board.ID, &model.BoardRestoreOptions{RestoreAt: utils.GetMillis() + 60000})
+		th.CheckBadRequest(resp)
+		require.Nil(t, result)
+	})
+
+	t.Run("preview and restore a board", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 2)
+		updatedCard, deletedCard := cards[0], cards[1]
+
+		time.Sleep(10 * time.Millisecond)
+		restoreAt := utils.GetMillis()
+		time.Sleep(10 * time.Millisecond)
+
+		newTitle := "a new title"
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{Title: &newTitle})
+		th.CheckOK(resp)
+		_, resp = th.Client.PatchCard(updatedCard.ID, &model.CardPatch{Title: &newTitle}, true)
+		th.CheckOK(resp)
+		_, resp = th.Client.DeleteBlock(board.ID, deletedCard.ID, true)
+		th.CheckOK(resp)
+		createdCard, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "created later"}, true)
+		th.CheckOK(resp)
+
+		opts := &model.BoardRestoreOptions{RestoreAt: restoreAt, DryRun: true}
+		preview, resp := th.Client.RestoreBoard(board.ID, opts)
+		th.CheckOK(resp)
+		require.True(t, preview.DryRun)
+		require.True(t, preview.BoardChanged)
+		require.Equal(t, board.Title, preview.Board.Title)
+
+		actions := map[string]model.BoardRestoreAction{}
+		for _, change := range preview.Changes {
+			actions[change.BlockID] = change.Action
+		}
+		require.Equal(t, map[string]model.BoardRestoreAction{
+			updatedCard.ID: model.BoardRestoreActionUpdate,
+			deletedCard.ID: model.BoardRestoreActionRestore,
+			createdCard.ID: model.BoardRestoreActionDelete,
+		}, actions)
+
+		// the dry run has not changed anything
+		rBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		require.Equal(t, newTitle, rBoard.Title)
+
+		opts.DryRun = false
+		result, resp := th.Client.RestoreBoard(board.ID, opts)
+		th.CheckOK(resp)
+		require.False(t, result.DryRun)
+		require.Len(t, result.Changes, 3)
+		require.Equal(t, board.Title, result.Board.Title)
+
+		rCard, resp := th.Client.GetCard(updatedCard.ID)
+		th.CheckOK(resp)
+		require.Equal(t, updatedCard.Title, rCard.Title)
+
+		rCard, resp = th.Client.GetCard(deletedCard.ID)
+		th.CheckOK(resp)
+		require.Equal(t, deletedCard.Title, rCard.Title)
+
+		_, resp = th.Client.GetCard(createdCard.ID)
+		require.Error(t, resp.Error)
+
+		// a second restore has nothing left to change
+		result, resp = th.Client.RestoreBoard(board.ID, opts)
+		th.CheckOK(resp)
+		require.False(t, result.BoardChanged)
+		require.Empty(t, result.Changes)
+	})
+}
+
+func TestConvertCardProperty(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	patch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "priority", "name": "Priority", "type": "text"},
+			{"id": "estimate", "name": "Estimate", "type": "text"},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	cardProperties := []map[string]any{
+		{"priority": "High", "estimate": "3.50"},
+		{"priority": "Low", "estimate": "a few days"},
+		{"priority": "High "},
+		{},
+	}
+	cardIDs := make([]string, len(cardProperties))
+	for i, properties := range cardProperties {
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "card", Properties: properties}, true)
+		th.CheckOK(resp)
+		cardIDs[i] = card.ID
+	}
+
+	t.Run("unsupported conversions are rejected", func(t *testing.T) {
+		_, resp := th.Client.ConvertCardProperty(board.ID, "priority", &model.PropertyConversion{Type: "date"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.ConvertCardProperty(board.ID, "unknown", &model.PropertyConversion{Type: "select"})
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("a user without permissions should be rejected", func(t *testing.T) {
+		_, resp := th.Client2.ConvertCardProperty(board.ID, "priority", &model.PropertyConversion{Type: "select"})
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("a dry run doesn't change anything", func(t *testing.T) {
+		result, resp := th.Client.ConvertCardProperty(board.ID, "estimate", &model.PropertyConversion{Type: "number", DryRun: true})
+		th.CheckOK(resp)
+		require.True(t, result.DryRun)
+		require.Equal(t, 1, result.ConvertedCards)
+		require.Len(t, result.Unconvertible, 1)
+		require.Equal(t, cardIDs[1], result.Unconvertible[0].CardID)
+
+		card, resp := th.Client.GetCard(cardIDs[1])
+		th.CheckOK(resp)
+		require.Equal(t, "a few days", card.Properties["estimate"])
+	})
+
+	t.Run("text to select creates the options and converts the values", func(t *testing.T) {
+		result, resp := th.Client.ConvertCardProperty(board.ID, "priority", &model.PropertyConversion{Type: "select"})
+		th.CheckOK(resp)
+		require.False(t, result.DryRun)
+		require.Equal(t, 3, result.ConvertedCards)
+		require.Empty(t, result.Unconvertible)
+
+		updatedBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		var priority map[string]interface{}
+		for _, prop := range updatedBoard.CardProperties {
+			if prop["id"] == "priority" {
+				priority = prop
+			}
+		}
+		require.Equal(t, "select", priority["type"])
+		options := priority["options"].([]interface{})
+		require.Len(t, options, 2)
+		highID := options[0].(map[string]interface{})["id"]
+		require.Equal(t, "High", options[0].(map[string]interface{})["value"])
+
+		for _, i := range []int{0, 2} {
+			card, resp := th.Client.GetCard(cardIDs[i])
+			th.CheckOK(resp)
+			require.Equal(t, highID, card.Properties["priority"])
+		}
+
+		// the change of the property is recorded in the board history
+		history, err := th.Server.Store().GetBoardHistory(board.ID, model.QueryBoardHistoryOptions{Limit: 1, Descending: true})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		for _, prop := range history[0].CardProperties {
+			if prop["id"] == "priority" {
+				require.Equal(t, "select", prop["type"])
+			}
+		}
+	})
+
+	t.Run("unconvertible values fail the conversion", func(t *testing.T) {
+		_, resp := th.Client.ConvertCardProperty(board.ID, "estimate", &model.PropertyConversion{Type: "number"})
+		th.CheckBadRequest(resp)
+		var errorResponse model.ErrorResponse
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(resp.Error.Error(), "payload: ")), &errorResponse))
+		require.Len(t, errorResponse.Unconvertible, 1)
+		require.Equal(t, cardIDs[1], errorResponse.Unconvertible[0].CardID)
+		require.Equal(t, "a few days", errorResponse.Unconvertible[0].Value)
+
+		// neither the property nor the cards are changed
+		card, resp := th.Client.GetCard(cardIDs[0])
+		th.CheckOK(resp)
+		require.Equal(t, "3.50", card.Properties["estimate"])
+		updatedBoard, resp := th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+		for _, prop := range updatedBoard.CardProperties {
+			if prop["id"] == "estimate" {
+				require.Equal(t, "text", prop["type"])
+			}
+		}
+	})
+
+	t.Run("unconvertible values are removed and reported if dropped", func(t *testing.T) {
+		result, resp := th.Client.ConvertCardProperty(board.ID, "estimate", &model.PropertyConversion{Type: "number", DropUnconvertible: true})
+		th.CheckOK(resp)
+		require.Equal(t, 1, result.ConvertedCards)
+		require.Len(t, result.Unconvertible, 1)
+		require.Equal(t, "a few days", result.Unconvertible[0].Value)
+
+		card, resp := th.Client.GetCard(cardIDs[1])
+		th.CheckOK(resp)
+		require.NotContains(t, card.Properties, "estimate")
+	})
+}
@@ -161,6 +161,47 @@ func TestCreateBoardsAndBlocks(t *testing.T) {
 			require.Equal(t, user1.ID, members2[0].UserID)
 		})
 	})
+	t.Run("card property values are validated", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		newBab := func(properties map[string]interface{}) *model.BoardsAndBlocks {
+			return &model.BoardsAndBlocks{
+				Boards: []*model.Board{
+					{ID: "board-id", Title: "validated board", TeamID: teamID, Type: model.BoardTypeOpen, CardProperties: validationTestCardProperties()},
+				},
+				Blocks: []*model.Block{
+					{ID: "card-id", Title: "card", BoardID: "board-id", ParentID: "board-id", Type: model.TypeCard, CreateAt: 1, UpdateAt: 1,
+						Fields: map[string]interface{}{"properties": properties}},
+				},
+			}
+		}
+
+		bab, resp := th.Client.CreateBoardsAndBlocks(newBab(map[string]interface{}{
+			"status": "status_unknown",
+			"due":    "tomorrow",
+			"owner":  th.GetUser2().ID,
+		}))
+		invalid := invalidProperties(t, th, resp)
+		require.Nil(t, bab)
+		require.Len(t, invalid, 3)
+		require.Equal(t, "status", invalid[0].PropertyID)
+		require.Equal(t, "due", invalid[1].PropertyID)
+		require.Equal(t, "owner", invalid[2].PropertyID)
+
+		boards, resp := th.Client.SearchBoardsForTeam(teamID, "validated")
+		th.CheckOK(resp)
+		require.Empty(t, boards)
+
+		// the creator of the boards is a member of them
+		bab, resp = th.Client.CreateBoardsAndBlocks(newBab(map[string]interface{}{
+			"status": "status_done",
+			"due":    `{"from":1672531200000}`,
+			"owner":  th.GetUser1().ID,
+		}))
+		th.CheckOK(resp)
+		require.Len(t, bab.Blocks, 1)
+	})
 }
 
 func TestPatchBoardsAndBlocks(t *testing.T) {
@@ -654,6 +695,108 @@ func TestPatchBoardsAndBlocks(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, newTitle, rBlock2.Title)
 	})
+	t.Run("card property values are validated", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		userID := th.GetUser1().ID
+		board, err := th.Server.App().CreateBoard(&model.Board{
+			Title:          "validated board",
+			TeamID:         teamID,
+			Type:           model.BoardTypeOpen,
+			CardProperties: validationTestCardProperties(),
+		}, userID, true)
+		require.NoError(t, err)
+
+		card := &model.Block{ID: "card-id", BoardID: board.ID, ParentID: board.ID, Type: model.TypeCard, Title: "card", Fields: map[string]interface{}{}}
+		require.NoError(t, th.Server.App().InsertBlock(card, userID))
+
+		patchCard := func(properties map[string]interface{}, boardPatch *model.BoardPatch) *model.PatchBoardsAndBlocks {
+			return &model.PatchBoardsAndBlocks{
+				BoardIDs:     []string{board.ID},
+				BoardPatches: []*model.BoardPatch{boardPatch},
+				BlockIDs:     []string{card.ID},
+				BlockPatches: []*model.BlockPatch{
+					{UpdatedFields: map[string]interface{}{"properties": properties}},
+				},
+			}
+		}
+
+		newTitle := "new title"
+		bab, resp := th.Client.PatchBoardsAndBlocks(patchCard(map[string]interface{}{
+			"status": "status_unknown",
+			"due":    "tomorrow",
+			"owner":  th.GetUser2().ID,
+		}, &model.BoardPatch{Title: &newTitle}))
+		invalid := invalidProperties(t, th, resp)
+		require.Nil(t, bab)
+		require.Len(t, invalid, 3)
+		require.Equal(t, "status", invalid[0].PropertyID)
+		require.Equal(t, "due", invalid[1].PropertyID)
+		require.Equal(t, "owner", invalid[2].PropertyID)
+
+		// nothing is updated
+		rBoard, err := th.Server.App().GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "validated board", rBoard.Title)
+
+		// the values are validated against the card properties of the patch
+		properties := validationTestCardProperties()
+		properties[0]["options"] = []interface{}{
+			map[string]interface{}{"id": "status_new", "value": "New"},
+		}
+		bab, resp = th.Client.PatchBoardsAndBlocks(patchCard(map[string]interface{}{
+			"status": "status_new",
+			"owner":  userID,
+		}, &model.BoardPatch{UpdatedCardProperties: properties[:1]}))
+		th.CheckOK(resp)
+		require.Equal(t, map[string]interface{}{"status": "status_new", "owner": userID}, bab.Blocks[0].Fields["properties"])
+	})
+	t.Run("invalid formulas are rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		userID := th.GetUser1().ID
+		board, err := th.Server.App().CreateBoard(&model.Board{
+			Title:  "formula board",
+			TeamID: teamID,
+			Type:   model.BoardTypeOpen,
+			CardProperties: []map[string]interface{}{
+				{"id": "estimate", "name": "Estimate", "type": "number"},
+				{"id": "double", "name": "Double", "type": model.PropTypeFormula, "formula": `prop("Estimate") * 2`},
+			},
+		}, userID, true)
+		require.NoError(t, err)
+
+		block := &model.Block{ID: "block-id", BoardID: board.ID, Title: "block"}
+		require.NoError(t, th.Server.App().InsertBlock(block, userID))
+
+		newTitle := "new title"
+		for _, boardPatch := range []*model.BoardPatch{
+			{UpdatedCardProperties: []map[string]interface{}{
+				{"id": "double", "name": "Double", "type": model.PropTypeFormula, "formula": `prop("Double") * 2`},
+			}},
+			// the formula can't reference a deleted property
+			{DeletedCardProperties: []string{"estimate"}},
+		} {
+			pbab := &model.PatchBoardsAndBlocks{
+				BoardIDs:     []string{board.ID},
+				BoardPatches: []*model.BoardPatch{boardPatch},
+				BlockIDs:     []string{block.ID},
+				BlockPatches: []*model.BlockPatch{{Title: &newTitle}},
+			}
+			bab, resp := th.Client.PatchBoardsAndBlocks(pbab)
+			th.CheckBadRequest(resp)
+			require.Nil(t, bab)
+		}
+
+		rBoard, err := th.Server.App().GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, rBoard.CardProperties, 2)
+		rBlock, err := th.Server.App().GetBlockByID(block.ID)
+		require.NoError(t, err)
+		require.Equal(t, "block", rBlock.Title)
+	})
 }
 
 func TestDeleteBoardsAndBlocks(t *testing.T) {
@@ -820,3 +963,82 @@ func TestDeleteBoardsAndBlocks(t *testing.T) {
 		require.Nil(t, block2)
 	})
 }
+
+// validationTestCardProperties returns card properties whose values are
+// validated.
+func validationTestCardProperties() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "status_done", "value": "Done"},
+		}},
+		{"id": "due", "name": "Due", "type": "date"},
+		{"id": "owner", "name": "Owner", "type": "person"},
+	}
+}
+
+func TestBoardsAndBlocksRelations(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	projects := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	tasks := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+	task, resp := th.Client.CreateCard(tasks.ID, &model.Card{Title: "task"}, true)
+	th.CheckOK(resp)
+	project, resp := th.Client.CreateCard(projects.ID, &model.Card{Title: "project"}, true)
+	th.CheckOK(resp)
+
+	relationPatch := func(targetBoardID string, fields map[string]interface{}) *model.PatchBoardsAndBlocks {
+		return &model.PatchBoardsAndBlocks{
+			BoardIDs: []string{projects.ID},
+			BoardPatches: []*model.BoardPatch{{
+				UpdatedCardProperties: []map[string]interface{}{
+					{"id": "tasks", "name": "Tasks", "type": model.PropTypeRelation, "targetBoardId": targetBoardID},
+				},
+			}},
+			BlockIDs:     []string{project.ID},
+			BlockPatches: []*model.BlockPatch{{UpdatedFields: fields}},
+		}
+	}
+	linkedFields := map[string]interface{}{"properties": map[string]interface{}{"tasks": []interface{}{task.ID}}}
+
+	t.Run("the target board of a relation must be editable by the user", func(t *testing.T) {
+		other, err := th.Server.App().CreateBoard(&model.Board{TeamID: testTeamID, Type: model.BoardTypePrivate}, th.GetUser2().ID, true)
+		require.NoError(t, err)
+
+		_, resp := th.Client.PatchBoardsAndBlocks(relationPatch(other.ID, map[string]interface{}{"icon": "🔗"}))
+		th.CheckForbidden(resp)
+
+		// nor can its cards be linked
+		_, resp = th.Client.PatchBoardsAndBlocks(relationPatch(other.ID, linkedFields))
+		th.CheckBadRequest(resp)
+	})
+
+	var reversePropertyID string
+	t.Run("patched relations link back to the card", func(t *testing.T) {
+		bab, resp := th.Client.PatchBoardsAndBlocks(relationPatch(tasks.ID, linkedFields))
+		th.CheckOK(resp)
+		for _, prop := range bab.Boards[0].CardProperties {
+			if prop["id"] == "tasks" {
+				reversePropertyID, _ = prop["reversePropertyId"].(string)
+			}
+		}
+		require.NotEmpty(t, reversePropertyID)
+
+		fetched, resp := th.Client.GetCard(task.ID)
+		th.CheckOK(resp)
+		require.Equal(t, []any{project.ID}, fetched.Properties[reversePropertyID])
+	})
+
+	t.Run("deleting a linked card removes the link", func(t *testing.T) {
+		_, resp := th.Client.DeleteBoardsAndBlocks(&model.DeleteBoardsAndBlocks{
+			Boards: []string{tasks.ID},
+			Blocks: []string{task.ID},
+		})
+		th.CheckOK(resp)
+
+		fetched, resp := th.Client.GetCard(project.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, fetched.Properties, "tasks")
+	})
+}
@@ -0,0 +1,126 @@
+package integrationtests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardChecklist(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+	newCard := func(title string) *model.Card {
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: title}, true)
+		th.CheckOK(resp)
+		return card
+	}
+	newCheckbox := func(card *model.Card, checked bool) *model.Block {
+		return &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: card.ID,
+			Type:     model.TypeCheckbox,
+			Title:    "item",
+			Fields:   map[string]interface{}{"value": checked},
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+		}
+	}
+
+	empty := newCard("empty")
+	half := newCard("half")
+	done := newCard("done")
+	blocks, resp := th.Client.InsertBlocks(board.ID, []*model.Block{
+		newCheckbox(half, true),
+		newCheckbox(half, false),
+		newCheckbox(done, true),
+	}, false)
+	th.CheckOK(resp)
+	unchecked := blocks[1]
+
+	t.Run("cards have their checklist progress", func(t *testing.T) {
+		card, resp := th.Client.GetCard(half.ID)
+		th.CheckOK(resp)
+		require.Equal(t, model.ChecklistProgress{Done: 1, Total: 2}, card.Checklist)
+
+		card, resp = th.Client.GetCard(empty.ID)
+		th.CheckOK(resp)
+		require.Equal(t, model.ChecklistProgress{}, card.Checklist)
+
+		cards, resp := th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 3)
+		for _, card := range cards {
+			if card.ID == done.ID {
+				require.Equal(t, model.ChecklistProgress{Done: 1, Total: 1}, card.Checklist)
+			}
+		}
+	})
+
+	t.Run("filter and sort the cards by checklist", func(t *testing.T) {
+		cards, resp := th.Client.GetCardsByChecklist(board.ID, model.ChecklistQuery{Filter: model.ChecklistFilterIncomplete}, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.Equal(t, half.ID, cards[0].ID)
+
+		cards, resp = th.Client.GetCardsByChecklist(board.ID, model.ChecklistQuery{Sort: model.ChecklistSortDescending}, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 3)
+		require.Equal(t, []string{done.ID, half.ID, empty.ID}, []string{cards[0].ID, cards[1].ID, cards[2].ID})
+
+		cards, resp = th.Client.GetCardsByChecklist(board.ID, model.ChecklistQuery{Sort: model.ChecklistSortAscending}, 1, 1)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.Equal(t, done.ID, cards[0].ID)
+
+		_, resp = th.Client.GetCardsByChecklist(board.ID, model.ChecklistQuery{Filter: "all"}, 0, 10)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("checking the last checkbox completes the checklist", func(t *testing.T) {
+		_, resp := th.Client.PatchBlock(board.ID, unchecked.ID, &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"value": true},
+		}, false)
+		th.CheckOK(resp)
+
+		cards, resp := th.Client.GetCardsByChecklist(board.ID, model.ChecklistQuery{Filter: model.ChecklistFilterComplete}, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 2)
+
+		_, resp = th.Client.DeleteBlock(board.ID, unchecked.ID, false)
+		th.CheckOK(resp)
+		card, resp := th.Client.GetCard(half.ID)
+		th.CheckOK(resp)
+		require.Equal(t, model.ChecklistProgress{Done: 1, Total: 1}, card.Checklist)
+	})
+
+	t.Run("exports include the checklist progress", func(t *testing.T) {
+		archive, resp := th.Client.ExportBoardArchive(board.ID)
+		th.CheckOK(resp)
+		_, lines := readTestArchive(t, archive)
+
+		checklists := map[string]interface{}{}
+		for _, line := range lines[board.ID+"/board.jsonl"] {
+			if line.Type != "block" {
+				continue
+			}
+			var block model.Block
+			require.NoError(t, json.Unmarshal(line.Data, &block))
+			if block.Type == model.TypeCard {
+				checklists[block.ID] = block.Fields[model.CardFieldChecklist]
+			}
+		}
+		require.Equal(t, map[string]interface{}{
+			empty.ID: map[string]interface{}{"done": 0.0, "total": 0.0},
+			half.ID:  map[string]interface{}{"done": 1.0, "total": 1.0},
+			done.ID:  map[string]interface{}{"done": 1.0, "total": 1.0},
+		}, checklists)
+	})
+}
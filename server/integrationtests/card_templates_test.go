@@ -0,0 +1,100 @@
+package integrationtests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCardFromTemplate(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+		{"id": "due", "name": "Due", "type": "date", "options": []interface{}{}},
+		{"id": "owner", "name": "Owner", "type": "person", "options": []interface{}{}},
+		{"id": "notes", "name": "Notes", "type": "text", "options": []interface{}{}},
+	}})
+	th.CheckOK(resp)
+
+	template, resp := th.Client.CreateCard(board.ID, &model.Card{
+		Title:      "Onboard {{client}}",
+		IsTemplate: true,
+		Properties: map[string]any{
+			"due":   "{{today+7d}}",
+			"owner": "{{creator}}",
+			"notes": "Created by {{creator}} on {{board.title}}",
+		},
+	}, true)
+	th.CheckOK(resp)
+
+	_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		ParentID: template.ID,
+		Type:     model.TypeText,
+		Title:    "Call {{client}} on {{today}}",
+		CreateAt: utils.GetMillis(),
+		UpdateAt: utils.GetMillis(),
+	}}, true)
+	th.CheckOK(resp)
+
+	t.Run("placeholders are resolved", func(t *testing.T) {
+		card, resp := th.Client.CreateCardFromTemplate(board.ID, template.ID, &model.CardFromTemplateRequest{
+			Variables: map[string]string{"client": "ACME"},
+		})
+		th.CheckOK(resp)
+		now := time.Now().UTC()
+
+		user := th.GetUser1()
+		require.NotEqual(t, template.ID, card.ID)
+		require.False(t, card.IsTemplate)
+		require.Equal(t, "Onboard ACME", card.Title)
+		require.Equal(t, model.DatePropertyValue(now.AddDate(0, 0, 7)), card.Properties["due"])
+		require.Equal(t, user.ID, card.Properties["owner"])
+		require.Equal(t, "Created by "+user.Username+" on "+board.Title, card.Properties["notes"])
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		var content *model.Block
+		for _, block := range blocks {
+			if block.ParentID == card.ID && block.Type == model.TypeText {
+				content = block
+			}
+		}
+		require.NotNil(t, content)
+		require.Equal(t, "Call ACME on "+now.Format("2006-01-02"), content.Title)
+
+		// the template is unchanged
+		fetched, resp := th.Client.GetCard(template.ID)
+		th.CheckOK(resp)
+		require.Equal(t, "Onboard {{client}}", fetched.Title)
+		require.Equal(t, "{{today+7d}}", fetched.Properties["due"])
+	})
+
+	t.Run("unknown variables are kept", func(t *testing.T) {
+		card, resp := th.Client.CreateCardFromTemplate(board.ID, template.ID, nil)
+		th.CheckOK(resp)
+		require.Equal(t, "Onboard {{client}}", card.Title)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "not a template"}, true)
+		th.CheckOK(resp)
+		_, resp = th.Client.CreateCardFromTemplate(board.ID, card.ID, nil)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateCardFromTemplate(board.ID, template.ID, &model.CardFromTemplateRequest{
+			Variables: map[string]string{"bad name": "x"},
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client2.CreateCardFromTemplate(board.ID, template.ID, nil)
+		th.CheckForbidden(resp)
+	})
+}
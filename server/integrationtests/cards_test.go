@@ -1,10 +1,14 @@
 package integrationtests
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/mattermost/focalboard/server/client"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
 	"github.com/stretchr/testify/assert"
@@ -165,6 +169,677 @@ func TestGetCards(t *testing.T) {
 	})
 }
 
+func TestQueryCards(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+	for i := 0; i < 5; i++ {
+		card := &model.Card{
+			Title: fmt.Sprintf("card %d", i),
+		}
+		_, resp := th.Client.CreateCard(board.ID, card, true)
+		th.CheckOK(resp)
+	}
+
+	t.Run("filter, sort and paginate", func(t *testing.T) {
+		opts := model.QueryCardsOptions{
+			Filter: &model.FilterGroup{
+				Operation: model.FilterGroupOperationOr,
+				Filters: []model.FilterGroupItem{
+					{Clause: &model.FilterClause{PropertyID: "title", Condition: model.FilterConditionEndsWith, Values: []string{"1"}}},
+					{Clause: &model.FilterClause{PropertyID: "title", Condition: model.FilterConditionEndsWith, Values: []string{"3"}}},
+					{Clause: &model.FilterClause{PropertyID: "title", Condition: model.FilterConditionEndsWith, Values: []string{"4"}}},
+				},
+			},
+			SortOptions: []model.SortOption{{PropertyID: model.CardQuerySortTitlePropertyID, Reversed: true}},
+		}
+
+		result, resp := th.Client.QueryCards(board.ID, opts, 0, 2)
+		th.CheckOK(resp)
+		require.NotNil(t, result)
+		assert.Equal(t, 3, result.Total)
+		require.Len(t, result.Cards, 2)
+		assert.Equal(t, "card 4", result.Cards[0].Title)
+		assert.Equal(t, "card 3", result.Cards[1].Title)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		opts := model.QueryCardsOptions{
+			Filter: &model.FilterGroup{Operation: "xor"},
+		}
+
+		result, resp := th.Client.QueryCards(board.ID, opts, 0, 10)
+		th.CheckBadRequest(resp)
+		require.Nil(t, result)
+	})
+
+	t.Run("a user without access to the board should be rejected", func(t *testing.T) {
+		privateBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		result, resp := th.Client2.QueryCards(privateBoard.ID, model.QueryCardsOptions{}, 0, 10)
+		th.CheckForbidden(resp)
+		require.Nil(t, result)
+	})
+}
+
+func TestFormulaProperties(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+	patch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "spent", "name": "Spent", "type": "number"},
+			{"id": "remaining", "name": "Remaining", "type": model.PropTypeFormula, "formula": `prop("Estimate") - prop("Spent")`},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	t.Run("invalid formulas are rejected", func(t *testing.T) {
+		invalidPatch := &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "invalid", "name": "Invalid", "type": model.PropTypeFormula, "formula": `prop("Missing") +`},
+			},
+		}
+		_, resp := th.Client.PatchBoard(board.ID, invalidPatch)
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), "Invalid")
+
+		// deleting a property referenced by a formula is rejected too
+		_, resp = th.Client.PatchBoard(board.ID, &model.BoardPatch{DeletedCardProperties: []string{"spent"}})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("formula values are computed when cards are read", func(t *testing.T) {
+		card := &model.Card{
+			Title:      "formula card",
+			Properties: map[string]any{"estimate": "8", "spent": "3", "remaining": "stale"},
+		}
+		newCard, resp := th.Client.CreateCard(board.ID, card, true)
+		th.CheckOK(resp)
+		require.Equal(t, 5.0, newCard.Properties["remaining"])
+
+		properties := map[string]any{"estimate": "8", "spent": "6"}
+		patchedCard, resp := th.Client.PatchCard(newCard.ID, &model.CardPatch{UpdatedProperties: properties}, true)
+		th.CheckOK(resp)
+		require.Equal(t, 2.0, patchedCard.Properties["remaining"])
+
+		fetchedCard, resp := th.Client.GetCard(newCard.ID)
+		th.CheckOK(resp)
+		require.Equal(t, 2.0, fetchedCard.Properties["remaining"])
+
+		cards, resp := th.Client.GetCards(board.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.Equal(t, 2.0, cards[0].Properties["remaining"])
+	})
+}
+
+func TestRelationProperties(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	projects := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	tasks := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+	tasksPatch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_done", "value": "Done"},
+				map[string]interface{}{"id": "status_todo", "value": "To Do"},
+			}},
+			{"id": "points", "name": "Points", "type": "number"},
+		},
+	}
+	_, resp := th.Client.PatchBoard(tasks.ID, tasksPatch)
+	th.CheckOK(resp)
+
+	projectsPatch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "tasks", "name": "Tasks", "type": model.PropTypeRelation, "targetBoardId": tasks.ID},
+			{"id": "count", "name": "Count", "type": model.PropTypeRollup, "relationPropertyId": "tasks", "function": "count"},
+			{"id": "points", "name": "Points", "type": model.PropTypeRollup, "relationPropertyId": "tasks", "targetPropertyId": "points", "function": "sum"},
+			{
+				"id": "progress", "name": "Progress", "type": model.PropTypeRollup, "relationPropertyId": "tasks",
+				"targetPropertyId": "status", "function": "percentDone", "doneOptionIds": []interface{}{"status_done"},
+			},
+		},
+	}
+	patchedProjects, resp := th.Client.PatchBoard(projects.ID, projectsPatch)
+	th.CheckOK(resp)
+
+	t.Run("the reverse property is created on the target board", func(t *testing.T) {
+		var relation map[string]interface{}
+		for _, prop := range patchedProjects.CardProperties {
+			if prop["id"] == "tasks" {
+				relation = prop
+			}
+		}
+		require.NotNil(t, relation)
+		reversePropertyID, _ := relation["reversePropertyId"].(string)
+		require.NotEmpty(t, reversePropertyID)
+
+		tasksBoard, resp := th.Client.GetBoard(tasks.ID, "")
+		th.CheckOK(resp)
+		var reverse map[string]interface{}
+		for _, prop := range tasksBoard.CardProperties {
+			if prop["id"] == reversePropertyID {
+				reverse = prop
+			}
+		}
+		require.NotNil(t, reverse)
+		require.Equal(t, model.PropTypeRelation, reverse["type"])
+		require.Equal(t, projects.ID, reverse["targetBoardId"])
+		require.Equal(t, "tasks", reverse["reversePropertyId"])
+	})
+
+	t.Run("invalid rollups are rejected", func(t *testing.T) {
+		invalidPatch := &model.BoardPatch{
+			UpdatedCardProperties: []map[string]interface{}{
+				{"id": "invalid", "name": "Invalid", "type": model.PropTypeRollup, "relationPropertyId": "count", "function": "median"},
+			},
+		}
+		_, resp := th.Client.PatchBoard(projects.ID, invalidPatch)
+		th.CheckBadRequest(resp)
+	})
+
+	var reversePropertyID string
+	for _, prop := range patchedProjects.CardProperties {
+		if prop["id"] == "tasks" {
+			reversePropertyID, _ = prop["reversePropertyId"].(string)
+		}
+	}
+
+	task1, resp := th.Client.CreateCard(tasks.ID, &model.Card{Title: "task 1", Properties: map[string]any{"status": "status_done", "points": "3"}}, true)
+	th.CheckOK(resp)
+	task2, resp := th.Client.CreateCard(tasks.ID, &model.Card{Title: "task 2", Properties: map[string]any{"status": "status_todo", "points": "5"}}, true)
+	th.CheckOK(resp)
+
+	project, resp := th.Client.CreateCard(projects.ID, &model.Card{
+		Title:      "project",
+		Properties: map[string]any{"tasks": []any{task1.ID, task2.ID}},
+	}, true)
+	th.CheckOK(resp)
+
+	t.Run("rollups are computed from the linked cards", func(t *testing.T) {
+		require.ElementsMatch(t, []any{task1.ID, task2.ID}, project.Properties["tasks"])
+		require.Equal(t, 2.0, project.Properties["count"])
+		require.Equal(t, 8.0, project.Properties["points"])
+		require.Equal(t, 50.0, project.Properties["progress"])
+
+		blocks, resp := th.Client.GetBlocksForBoard(projects.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		properties := blocks[0].Fields["properties"].(map[string]interface{})
+		require.ElementsMatch(t, []any{task1.ID, task2.ID}, properties["tasks"])
+		require.Equal(t, 8.0, properties["points"])
+	})
+
+	t.Run("the linked cards link back to the card", func(t *testing.T) {
+		for _, task := range []*model.Card{task1, task2} {
+			fetched, resp := th.Client.GetCard(task.ID)
+			th.CheckOK(resp)
+			require.Equal(t, []any{project.ID}, fetched.Properties[reversePropertyID])
+		}
+
+		patched, resp := th.Client.PatchCard(project.ID, &model.CardPatch{UpdatedProperties: map[string]any{"tasks": []any{task2.ID}}}, true)
+		th.CheckOK(resp)
+		require.Equal(t, 1.0, patched.Properties["count"])
+		require.Equal(t, 0.0, patched.Properties["progress"])
+
+		fetched, resp := th.Client.GetCard(task1.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, fetched.Properties, reversePropertyID)
+	})
+
+	t.Run("the linked cards the user cannot see are hidden", func(t *testing.T) {
+		member := &model.BoardMember{BoardID: projects.ID, UserID: th.GetUser2().ID, SchemeEditor: true}
+		_, resp := th.Client.AddMemberToBoard(member)
+		th.CheckOK(resp)
+
+		cards, resp := th.Client2.GetCards(projects.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.NotContains(t, cards[0].Properties, "tasks")
+		require.Equal(t, 0.0, cards[0].Properties["count"])
+		require.NotContains(t, cards[0].Properties, "progress")
+
+		blocks, resp := th.Client2.GetBlocksForBoard(projects.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		properties := blocks[0].Fields["properties"].(map[string]interface{})
+		require.NotContains(t, properties, "tasks")
+		require.Equal(t, 0.0, properties["count"])
+	})
+
+	t.Run("users can only link the cards of boards they can edit", func(t *testing.T) {
+		// user2 edits the projects board, but isn't a member of the tasks board
+		_, resp := th.Client2.CreateCard(projects.ID, &model.Card{Title: "other project", Properties: map[string]any{"tasks": []any{task1.ID}}}, true)
+		invalid := invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "tasks", invalid[0].PropertyID)
+
+		_, resp = th.Client2.PatchCard(project.ID, &model.CardPatch{UpdatedProperties: map[string]any{"tasks": []any{task1.ID, task2.ID}}}, true)
+		invalid = invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "tasks", invalid[0].PropertyID)
+
+		fetched, resp := th.Client.GetCard(task1.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, fetched.Properties, reversePropertyID)
+
+		// only the cards of the target board can be linked
+		_, resp = th.Client.PatchCard(project.ID, &model.CardPatch{UpdatedProperties: map[string]any{"tasks": []any{task2.ID, project.ID}}}, true)
+		invalid = invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "tasks", invalid[0].PropertyID)
+	})
+
+	t.Run("deleting a linked card removes the link", func(t *testing.T) {
+		_, resp := th.Client.DeleteBlock(tasks.ID, task2.ID, true)
+		th.CheckOK(resp)
+
+		fetched, resp := th.Client.GetCard(project.ID)
+		th.CheckOK(resp)
+		require.NotContains(t, fetched.Properties, "tasks")
+		require.Equal(t, 0.0, fetched.Properties["count"])
+		require.Equal(t, 0.0, fetched.Properties["points"])
+	})
+}
+
+func TestCardDependencies(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	statusProperty := map[string]interface{}{
+		"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "status_todo", "value": "To Do"},
+			map[string]interface{}{"id": "status_done", "value": "Done", "done": true},
+		},
+	}
+	backend := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	frontend := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	for _, board := range []*model.Board{backend, frontend} {
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{statusProperty}})
+		th.CheckOK(resp)
+	}
+
+	apiCard, resp := th.Client.CreateCard(backend.ID, &model.Card{Title: "api", Properties: map[string]any{"status": "status_todo"}}, true)
+	th.CheckOK(resp)
+	schemaCard, resp := th.Client.CreateCard(backend.ID, &model.Card{Title: "schema", Properties: map[string]any{"status": "status_done"}}, true)
+	th.CheckOK(resp)
+	pageCard, resp := th.Client.CreateCard(frontend.ID, &model.Card{Title: "page"}, true)
+	th.CheckOK(resp)
+
+	t.Run("add dependencies within and across boards", func(t *testing.T) {
+		dependency, resp := th.Client.AddCardDependency(pageCard.ID, apiCard.ID)
+		th.CheckOK(resp)
+		require.Equal(t, apiCard.ID, dependency.BlockerID)
+		require.Equal(t, backend.ID, dependency.BlockerBoardID)
+		require.Equal(t, pageCard.ID, dependency.BlockedID)
+		require.Equal(t, frontend.ID, dependency.BlockedBoardID)
+		require.Equal(t, th.GetUser1().ID, dependency.CreatedBy)
+
+		_, resp = th.Client.AddCardDependency(apiCard.ID, schemaCard.ID)
+		th.CheckOK(resp)
+
+		dependencies, resp := th.Client.GetCardDependencies(apiCard.ID)
+		th.CheckOK(resp)
+		require.Len(t, dependencies.BlockedBy, 1)
+		require.Equal(t, schemaCard.ID, dependencies.BlockedBy[0].BlockerID)
+		require.Len(t, dependencies.Blocks, 1)
+		require.Equal(t, pageCard.ID, dependencies.Blocks[0].BlockedID)
+	})
+
+	t.Run("invalid dependencies are rejected", func(t *testing.T) {
+		// duplicated
+		_, resp := th.Client.AddCardDependency(pageCard.ID, apiCard.ID)
+		th.CheckBadRequest(resp)
+
+		// self dependency
+		_, resp = th.Client.AddCardDependency(pageCard.ID, pageCard.ID)
+		th.CheckBadRequest(resp)
+
+		// cycles
+		_, resp = th.Client.AddCardDependency(apiCard.ID, pageCard.ID)
+		th.CheckBadRequest(resp)
+		require.Contains(t, resp.Error.Error(), model.ErrCardDependencyCycle.Error())
+		_, resp = th.Client.AddCardDependency(schemaCard.ID, pageCard.ID)
+		th.CheckBadRequest(resp)
+
+		// unknown blocker
+		_, resp = th.Client.AddCardDependency(pageCard.ID, utils.NewID(utils.IDTypeCard))
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("the blocked flag follows the state of the blockers", func(t *testing.T) {
+		card, resp := th.Client.GetCard(pageCard.ID)
+		th.CheckOK(resp)
+		require.True(t, card.Blocked)
+
+		card, resp = th.Client.GetCard(apiCard.ID)
+		th.CheckOK(resp)
+		require.False(t, card.Blocked)
+
+		cards, resp := th.Client.GetCards(frontend.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, cards, 1)
+		require.True(t, cards[0].Blocked)
+
+		patch := &model.CardPatch{UpdatedProperties: map[string]any{"status": "status_done"}}
+		_, resp = th.Client.PatchCard(apiCard.ID, patch, false)
+		th.CheckOK(resp)
+
+		card, resp = th.Client.GetCard(pageCard.ID)
+		th.CheckOK(resp)
+		require.False(t, card.Blocked)
+
+		patch = &model.CardPatch{UpdatedProperties: map[string]any{"status": "status_todo"}}
+		_, resp = th.Client.PatchCard(apiCard.ID, patch, false)
+		th.CheckOK(resp)
+
+		card, resp = th.Client.GetCard(pageCard.ID)
+		th.CheckOK(resp)
+		require.True(t, card.Blocked)
+	})
+
+	t.Run("deleted blockers don't block", func(t *testing.T) {
+		_, resp := th.Client.DeleteBlock(backend.ID, apiCard.ID, true)
+		th.CheckOK(resp)
+
+		card, resp := th.Client.GetCard(pageCard.ID)
+		th.CheckOK(resp)
+		require.False(t, card.Blocked)
+
+		dependencies, resp := th.Client.GetCardDependencies(pageCard.ID)
+		th.CheckOK(resp)
+		require.Empty(t, dependencies.BlockedBy)
+
+		_, resp = th.Client.UndeleteBlock(backend.ID, apiCard.ID)
+		th.CheckOK(resp)
+
+		card, resp = th.Client.GetCard(pageCard.ID)
+		th.CheckOK(resp)
+		require.True(t, card.Blocked)
+	})
+
+	t.Run("remove a dependency", func(t *testing.T) {
+		resp := th.Client.DeleteCardDependency(pageCard.ID, apiCard.ID)
+		th.CheckOK(resp)
+
+		resp = th.Client.DeleteCardDependency(pageCard.ID, apiCard.ID)
+		th.CheckNotFound(resp)
+
+		card, resp := th.Client.GetCard(pageCard.ID)
+		th.CheckOK(resp)
+		require.False(t, card.Blocked)
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		_, resp := th.Client2.GetCardDependencies(apiCard.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.AddCardDependency(pageCard.ID, apiCard.ID)
+		th.CheckForbidden(resp)
+
+		resp = th.Client2.DeleteCardDependency(apiCard.ID, schemaCard.ID)
+		th.CheckForbidden(resp)
+
+		// the blocker must belong to a board the user can view
+		otherBoard, resp := th.Client2.CreateBoard(&model.Board{TeamID: testTeamID, Type: model.BoardTypePrivate})
+		th.CheckOK(resp)
+		otherCard, resp := th.Client2.CreateCard(otherBoard.ID, &model.Card{Title: "other"}, true)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.AddCardDependency(pageCard.ID, otherCard.ID)
+		th.CheckForbidden(resp)
+	})
+}
+
+func TestCardPropertyValidation(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	patch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_done", "value": "Done"},
+			}},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	t.Run("invalid values are rejected with the list of invalid properties", func(t *testing.T) {
+		card := &model.Card{
+			Title: "invalid card",
+			Properties: map[string]any{
+				"status":   "status_unknown",
+				"estimate": "a lot",
+				"due":      "tomorrow",
+				"owner":    th.GetUser2().ID,
+			},
+		}
+		_, resp := th.Client.CreateCard(board.ID, card, true)
+		invalid := invalidProperties(t, th, resp)
+		require.Len(t, invalid, 4)
+		require.Equal(t, "status", invalid[0].PropertyID)
+		require.Equal(t, "Status", invalid[0].PropertyName)
+		require.Equal(t, "status_unknown", invalid[0].Value)
+		require.Equal(t, "estimate", invalid[1].PropertyID)
+		require.Equal(t, "due", invalid[2].PropertyID)
+		require.Equal(t, "owner", invalid[3].PropertyID)
+	})
+
+	t.Run("valid values are accepted", func(t *testing.T) {
+		card := &model.Card{
+			Title: "valid card",
+			Properties: map[string]any{
+				"status":   "status_done",
+				"estimate": "3.5",
+				"due":      `{"from":1672531200000}`,
+				"owner":    th.GetUser1().ID,
+				"unknown":  "not in the schema",
+			},
+		}
+		newCard, resp := th.Client.CreateCard(board.ID, card, true)
+		th.CheckOK(resp)
+
+		properties := map[string]any{"status": "status_done", "estimate": "four"}
+		_, resp = th.Client.PatchCard(newCard.ID, &model.CardPatch{UpdatedProperties: properties}, true)
+		invalid := invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "estimate", invalid[0].PropertyID)
+
+		blockPatch := &model.BlockPatch{
+			UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{"status": []interface{}{"status_done"}}},
+		}
+		_, resp = th.Client.PatchBlock(board.ID, newCard.ID, blockPatch, true)
+		invalid = invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "status", invalid[0].PropertyID)
+	})
+
+	t.Run("inserted card blocks are validated", func(t *testing.T) {
+		block := &model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeCard,
+			Title:    "inserted card",
+			Fields:   map[string]interface{}{"properties": map[string]interface{}{"status": "status_done", "owner": th.GetUser2().ID}},
+			CreateAt: 1,
+			UpdateAt: 1,
+		}
+		_, resp := th.Client.InsertBlocks(board.ID, []*model.Block{block}, true)
+		invalid := invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "owner", invalid[0].PropertyID)
+
+		block.Fields["properties"] = map[string]interface{}{"status": "status_done", "owner": th.GetUser1().ID}
+		_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{block}, true)
+		th.CheckOK(resp)
+	})
+}
+
+func TestScanInvalidCardProperties(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	th.Client = clients.Admin
+
+	board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	patch := &model.BoardPatch{
+		UpdatedCardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_done", "value": "Done"},
+				map[string]interface{}{"id": "status_todo", "value": "To Do"},
+			}},
+		},
+	}
+	_, resp := th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	doneCard, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "done", Properties: map[string]any{"status": "status_done"}}, true)
+	th.CheckOK(resp)
+	_, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "todo", Properties: map[string]any{"status": "status_todo"}}, true)
+	th.CheckOK(resp)
+
+	// removing an option leaves the cards that use it with an invalid value
+	patch.UpdatedCardProperties[0]["options"] = []interface{}{
+		map[string]interface{}{"id": "status_todo", "value": "To Do"},
+	}
+	_, resp = th.Client.PatchBoard(board.ID, patch)
+	th.CheckOK(resp)
+
+	t.Run("a user without manage_system permission should be rejected", func(t *testing.T) {
+		_, resp := clients.TeamMember.ScanInvalidCardProperties(testTeamID, 0, 10)
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("the cards with invalid values are reported", func(t *testing.T) {
+		result, resp := clients.Admin.ScanInvalidCardProperties(testTeamID, 0, 10)
+		th.CheckOK(resp)
+		require.False(t, result.HasNext)
+		require.Len(t, result.Results, 1)
+		require.Equal(t, board.ID, result.Results[0].BoardID)
+		require.Equal(t, doneCard.ID, result.Results[0].CardID)
+		require.Len(t, result.Results[0].Properties, 1)
+		require.Equal(t, "status", result.Results[0].Properties[0].PropertyID)
+	})
+
+	t.Run("unchanged invalid values don't prevent other changes", func(t *testing.T) {
+		title := "still done"
+		properties := map[string]any{"status": "status_done", "notes": "the status is invalid"}
+		_, resp := clients.Admin.PatchCard(doneCard.ID, &model.CardPatch{Title: &title, UpdatedProperties: properties}, true)
+		th.CheckOK(resp)
+	})
+}
+
+func TestSearchCards(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	openBoard := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+	privateBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+	openCard, resp := th.Client.CreateCard(openBoard.ID, &model.Card{Title: "Quarterly rollback plan"}, true)
+	th.CheckOK(resp)
+	privateCard, resp := th.Client.CreateCard(privateBoard.ID, &model.Card{Title: "Secret rollback"}, true)
+	th.CheckOK(resp)
+
+	cardIDs := func(results []*model.CardSearchResult) []string {
+		ids := make([]string, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.CardID)
+		}
+		return ids
+	}
+
+	t.Run("a board member should find the cards of all the boards", func(t *testing.T) {
+		results, resp := th.Client.SearchCardsForTeam(testTeamID, "rollback", 0, 10)
+		th.CheckOK(resp)
+		assert.ElementsMatch(t, []string{openCard.ID, privateCard.ID}, cardIDs(results))
+
+		for _, result := range results {
+			assert.Contains(t, result.Snippet, "<mark>rollback</mark>")
+			if result.CardID == openCard.ID {
+				assert.Equal(t, openBoard.ID, result.BoardID)
+				assert.Equal(t, "Quarterly rollback plan", result.CardTitle)
+			}
+		}
+	})
+
+	t.Run("a user should not find the cards of private boards they are not a member of", func(t *testing.T) {
+		results, resp := th.Client2.SearchCardsForTeam(testTeamID, "rollback", 0, 10)
+		th.CheckOK(resp)
+		assert.Equal(t, []string{openCard.ID}, cardIDs(results))
+	})
+
+	t.Run("an empty search should return no results", func(t *testing.T) {
+		results, resp := th.Client.SearchCardsForTeam(testTeamID, " ", 0, 10)
+		th.CheckOK(resp)
+		assert.Empty(t, results)
+	})
+
+	t.Run("a negative page or page size should be rejected", func(t *testing.T) {
+		_, resp := th.Client.SearchCardsForTeam(testTeamID, "rollback", -1, 10)
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.SearchCardsForTeam(testTeamID, "rollback", 0, -1)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("the text of the rich content blocks should be found", func(t *testing.T) {
+		contentBlock := func(blockType model.BlockType, title string, fields map[string]interface{}) *model.Block {
+			return &model.Block{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  openBoard.ID,
+				ParentID: openCard.ID,
+				Type:     blockType,
+				Title:    title,
+				Fields:   fields,
+				CreateAt: 1,
+				UpdateAt: 1,
+			}
+		}
+		blocks := []*model.Block{
+			contentBlock(model.TypeHeading, "Database migration", map[string]interface{}{}),
+			contentBlock(model.TypeBulletList, "", map[string]interface{}{
+				model.ListFieldItems: []interface{}{"stop the workers", "restore the snapshot"},
+			}),
+			contentBlock(model.TypeTable, "", map[string]interface{}{
+				model.TableFieldColumns: []interface{}{"Service", "Owner"},
+				model.TableFieldRows:    []interface{}{[]interface{}{"billing", "payments team"}},
+			}),
+		}
+		_, resp := th.Client.InsertBlocks(openBoard.ID, blocks, true)
+		th.CheckOK(resp)
+
+		for search, blockType := range map[string]model.BlockType{
+			"migration": model.TypeHeading,
+			"snapshot":  model.TypeBulletList,
+			"payments":  model.TypeTable,
+		} {
+			results, resp := th.Client.SearchCardsForTeam(testTeamID, search, 0, 10)
+			th.CheckOK(resp)
+			require.Len(t, results, 1, search)
+			assert.Equal(t, openCard.ID, results[0].CardID)
+			assert.Equal(t, blockType, results[0].BlockType)
+			assert.Contains(t, results[0].Snippet, "<mark>"+search+"</mark>")
+		}
+	})
+}
+
 func TestPatchCard(t *testing.T) {
 	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
 		th := SetupTestHelper(t).InitBasic()
@@ -233,6 +908,71 @@ func TestPatchCard(t *testing.T) {
 		require.Error(t, resp.Error)
 		require.Nil(t, cardNew)
 	})
+
+	t.Run("outdated expected version", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 1)
+		card := cards[0]
+
+		time.Sleep(10 * time.Millisecond)
+
+		newTitle := "first title"
+		patch := &model.CardPatch{
+			Title:            &newTitle,
+			ExpectedUpdateAt: &card.UpdateAt,
+		}
+
+		patchedCard, resp := th.Client.PatchCard(card.ID, patch, false)
+		th.CheckOK(resp)
+		require.Equal(t, newTitle, patchedCard.Title)
+		require.Equal(t, strconv.Quote(strconv.FormatInt(patchedCard.UpdateAt, 10)), resp.Header.Get("ETag"))
+
+		time.Sleep(10 * time.Millisecond)
+
+		staleTitle := "stale title"
+		patch.Title = &staleTitle
+		cardNew, resp := th.Client.PatchCard(card.ID, patch, false)
+		th.CheckConflict(resp)
+		require.Nil(t, cardNew)
+		require.Contains(t, resp.Error.Error(), newTitle)
+
+		rCard, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, newTitle, rCard.Title)
+	})
+
+	t.Run("if-match header", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 1)
+		card := cards[0]
+
+		time.Sleep(10 * time.Millisecond)
+
+		newTitle := "another title"
+		patch := &model.CardPatch{
+			Title: &newTitle,
+		}
+
+		th.Client.HTTPHeader["If-Match"] = "not-a-version"
+		cardNew, resp := th.Client.PatchCard(card.ID, patch, false)
+		th.CheckBadRequest(resp)
+		require.Nil(t, cardNew)
+
+		th.Client.HTTPHeader["If-Match"] = strconv.Quote(strconv.FormatInt(card.UpdateAt-1, 10))
+		cardNew, resp = th.Client.PatchCard(card.ID, patch, false)
+		th.CheckConflict(resp)
+		require.Nil(t, cardNew)
+
+		th.Client.HTTPHeader["If-Match"] = strconv.Quote(strconv.FormatInt(card.UpdateAt, 10))
+		cardNew, resp = th.Client.PatchCard(card.ID, patch, false)
+		delete(th.Client.HTTPHeader, "If-Match")
+		th.CheckOK(resp)
+		require.Equal(t, newTitle, cardNew.Title)
+	})
 }
 
 func TestGetCard(t *testing.T) {
@@ -285,3 +1025,96 @@ func modifyCardProps(m map[string]any) map[string]any {
 	}
 	return out
 }
+
+func TestGetCardActivity(t *testing.T) {
+	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 1)
+		th.Logout(th.Client)
+
+		activity, resp := th.Client.GetCardActivity(cards[0].ID, 0, 10)
+		th.CheckUnauthorized(resp)
+		require.Nil(t, activity)
+	})
+
+	t.Run("a user without access to the board should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		_, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypePrivate, 1)
+
+		activity, resp := th.Client2.GetCardActivity(cards[0].ID, 0, 10)
+		th.CheckForbidden(resp)
+		require.Nil(t, activity)
+	})
+
+	t.Run("timeline of card and comment changes", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 1)
+		card := cards[0]
+
+		time.Sleep(10 * time.Millisecond)
+		newTitle := "a new title"
+		_, resp := th.Client.PatchCard(card.ID, &model.CardPatch{Title: &newTitle}, true)
+		th.CheckOK(resp)
+
+		time.Sleep(10 * time.Millisecond)
+		comment := &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: card.ID,
+			Type:     model.TypeComment,
+			Title:    "a comment",
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+		}
+		newBlocks, resp := th.Client.InsertBlocks(board.ID, []*model.Block{comment}, true)
+		th.CheckOK(resp)
+		require.Len(t, newBlocks, 1)
+		comment = newBlocks[0]
+
+		time.Sleep(10 * time.Millisecond)
+		_, resp = th.Client.DeleteBlock(board.ID, comment.ID, true)
+		th.CheckOK(resp)
+
+		activity, resp := th.Client.GetCardActivity(card.ID, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, activity, 4)
+
+		require.Equal(t, comment.ID, activity[0].BlockID)
+		require.Equal(t, model.CardActivityActionDeleted, activity[0].Action)
+
+		require.Equal(t, comment.ID, activity[1].BlockID)
+		require.EqualValues(t, model.TypeComment, activity[1].BlockType)
+		require.Equal(t, model.CardActivityActionCreated, activity[1].Action)
+		require.Equal(t, "a comment", activity[1].NewTitle)
+
+		require.Equal(t, card.ID, activity[2].BlockID)
+		require.Equal(t, model.CardActivityActionUpdated, activity[2].Action)
+		require.Equal(t, card.Title, activity[2].OldTitle)
+		require.Equal(t, newTitle, activity[2].NewTitle)
+		require.Equal(t, th.GetUser1().ID, activity[2].ModifiedBy)
+
+		require.Equal(t, card.ID, activity[3].BlockID)
+		require.Equal(t, model.CardActivityActionCreated, activity[3].Action)
+
+		page, resp := th.Client.GetCardActivity(card.ID, 1, 3)
+		th.CheckOK(resp)
+		require.Len(t, page, 1)
+		require.Equal(t, activity[3], page[0])
+	})
+}
+
+// invalidProperties returns the invalid properties of the bad request
+// error of a response.
+func invalidProperties(t *testing.T, th *TestHelper, resp *client.Response) []model.InvalidPropertyValue {
+	t.Helper()
+	th.CheckBadRequest(resp)
+	var errorResponse model.ErrorResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(resp.Error.Error(), "payload: ")), &errorResponse))
+	return errorResponse.InvalidProperties
+}
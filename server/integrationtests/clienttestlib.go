@@ -79,7 +79,7 @@ func (*FakePermissionPluginAPI) HasPermissionTo(userID string, permission *mmMod
 
 func (*FakePermissionPluginAPI) HasPermissionToTeam(userID string, teamID string, permission *mmModel.Permission) bool {
 	if permission.Id == model.PermissionManageTeam.Id {
-		return false
+		return userID == userAdmin
 	}
 	if userID == userNoTeamMember {
 		return false
@@ -134,6 +134,8 @@ func getTestConfig() (*config.Configuration, error) {
 		LoggingCfgJSON:    logging,
 		SessionExpireTime: int64(30 * time.Second),
 		AuthMode:          "native",
+		// the outgoing webhooks are delivered to local test servers
+		WebhookAllowedIPRanges: []string{"127.0.0.1"},
 	}, nil
 }
 
@@ -556,6 +558,11 @@ func (th *TestHelper) CheckRequestEntityTooLarge(r *client.Response) {
 	require.Error(th.T, r.Error)
 }
 
+func (th *TestHelper) CheckConflict(r *client.Response) {
+	require.Equal(th.T, http.StatusConflict, r.StatusCode)
+	require.Error(th.T, r.Error)
+}
+
 func (th *TestHelper) CheckNotImplemented(r *client.Response) {
 	require.Equal(th.T, http.StatusNotImplemented, r.StatusCode)
 	require.Error(th.T, r.Error)
@@ -51,7 +51,7 @@ func TestMoveContentBlock(t *testing.T) {
 		BoardID:  board.ID,
 		CreateAt: 1,
 		UpdateAt: 1,
-		Type:     model.TypeCard,
+		Type:     model.TypeText,
 		ParentID: cardID1,
 	}
 	contentBlock2 := &model.Block{
@@ -59,7 +59,7 @@ func TestMoveContentBlock(t *testing.T) {
 		BoardID:  board.ID,
 		CreateAt: 1,
 		UpdateAt: 1,
-		Type:     model.TypeCard,
+		Type:     model.TypeText,
 		ParentID: cardID1,
 	}
 	contentBlock3 := &model.Block{
@@ -67,7 +67,7 @@ func TestMoveContentBlock(t *testing.T) {
 		BoardID:  board.ID,
 		CreateAt: 1,
 		UpdateAt: 1,
-		Type:     model.TypeCard,
+		Type:     model.TypeText,
 		ParentID: cardID1,
 	}
 	contentBlock4 := &model.Block{
@@ -75,7 +75,7 @@ func TestMoveContentBlock(t *testing.T) {
 		BoardID:  board.ID,
 		CreateAt: 1,
 		UpdateAt: 1,
-		Type:     model.TypeCard,
+		Type:     model.TypeText,
 		ParentID: cardID2,
 	}
 	contentBlock5 := &model.Block{
@@ -83,7 +83,7 @@ func TestMoveContentBlock(t *testing.T) {
 		BoardID:  board.ID,
 		CreateAt: 1,
 		UpdateAt: 1,
-		Type:     model.TypeCard,
+		Type:     model.TypeText,
 		ParentID: cardID2,
 	}
 	contentBlock6 := &model.Block{
@@ -91,7 +91,7 @@ func TestMoveContentBlock(t *testing.T) {
 		BoardID:  board.ID,
 		CreateAt: 1,
 		UpdateAt: 1,
-		Type:     model.TypeCard,
+		Type:     model.TypeText,
 		ParentID: cardID2,
 	}
 
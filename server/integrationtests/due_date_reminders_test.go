@@ -0,0 +1,137 @@
+package integrationtests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func dueDateValue(year int, month time.Month, day int) string {
+	return `{"from":` + strconv.FormatInt(utils.GetMillisForTime(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)), 10) + `}`
+}
+
+func TestDueDateReminders(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+		{"id": "due", "name": "Due", "type": "date", "options": []interface{}{}},
+		{"id": "owner", "name": "Owner", "type": "person", "options": []interface{}{}},
+		{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "status_todo", "value": "To Do"},
+			map[string]interface{}{"id": "status_done", "value": "Done", "done": true},
+		}},
+	}})
+	th.CheckOK(resp)
+
+	userID := th.GetUser1().ID
+	late, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "late", Properties: map[string]any{
+		"due": dueDateValue(2020, time.January, 10), "owner": userID,
+	}}, true)
+	th.CheckOK(resp)
+	later, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "later", Properties: map[string]any{
+		"due": dueDateValue(2020, time.February, 1),
+	}}, true)
+	th.CheckOK(resp)
+	_, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "done", Properties: map[string]any{
+		"due": dueDateValue(2020, time.January, 10), "owner": userID, "status": "status_done",
+	}}, true)
+	th.CheckOK(resp)
+	_, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "template", IsTemplate: true, Properties: map[string]any{
+		"due": dueDateValue(2020, time.January, 10), "owner": userID,
+	}}, true)
+	th.CheckOK(resp)
+	_, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "future", Properties: map[string]any{
+		"due": dueDateValue(2100, time.January, 1), "owner": userID,
+	}}, true)
+	th.CheckOK(resp)
+
+	t.Run("save the reminder settings", func(t *testing.T) {
+		_, resp := th.Client.GetDueDateReminderSettings(board.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.SaveDueDateReminderSettings(board.ID, &model.DueDateReminderSettings{
+			DatePropertyID: "status", AssigneePropertyID: "owner", DaysBefore: []int{1}, Hour: 9, Enabled: true,
+		})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client2.SaveDueDateReminderSettings(board.ID, &model.DueDateReminderSettings{
+			DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{1}, Hour: 9, Enabled: true,
+		})
+		th.CheckForbidden(resp)
+
+		settings, resp := th.Client.SaveDueDateReminderSettings(board.ID, &model.DueDateReminderSettings{
+			DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{1, 0}, Hour: 9, Enabled: true,
+		})
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, settings.BoardID)
+		require.Equal(t, userID, settings.ModifiedBy)
+
+		fetched, resp := th.Client.GetDueDateReminderSettings(board.ID)
+		th.CheckOK(resp)
+		require.Equal(t, []int{1, 0}, fetched.DaysBefore)
+	})
+
+	t.Run("reminders are sent once", func(t *testing.T) {
+		app := th.Server.App()
+
+		// before the reminder of the day before
+		sent, err := app.SendDueDateReminders(time.Date(2020, time.January, 9, 8, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Zero(t, sent)
+
+		sent, err = app.SendDueDateReminders(time.Date(2020, time.January, 9, 9, 30, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, 1, sent)
+
+		sent, err = app.SendDueDateReminders(time.Date(2020, time.January, 9, 9, 45, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Zero(t, sent)
+
+		// on the due date
+		sent, err = app.SendDueDateReminders(time.Date(2020, time.January, 10, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, 1, sent)
+
+		// reminders that are too old aren't sent
+		sent, err = app.SendDueDateReminders(time.Date(2020, time.February, 1, 12, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Zero(t, sent)
+	})
+
+	t.Run("list the overdue cards", func(t *testing.T) {
+		cards, resp := th.Client.GetOverdueCards(board.ID, "")
+		th.CheckOK(resp)
+		require.Len(t, cards, 2)
+		require.Equal(t, late.ID, cards[0].ID)
+		require.Equal(t, later.ID, cards[1].ID)
+
+		_, resp = th.Client.GetOverdueCards(board.ID, "status")
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client2.GetOverdueCards(board.ID, "")
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("delete the reminder settings", func(t *testing.T) {
+		resp := th.Client.DeleteDueDateReminderSettings(board.ID)
+		th.CheckOK(resp)
+
+		resp = th.Client.DeleteDueDateReminderSettings(board.ID)
+		th.CheckNotFound(resp)
+
+		// the date property is required without settings
+		_, resp = th.Client.GetOverdueCards(board.ID, "")
+		th.CheckBadRequest(resp)
+
+		cards, resp := th.Client.GetOverdueCards(board.ID, "due")
+		th.CheckOK(resp)
+		require.Len(t, cards, 2)
+	})
+}
@@ -1,9 +1,14 @@
 package integrationtests
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"io"
 	"testing"
+	"time"
 
+	"github.com/mattermost/focalboard/server/client"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
 	"github.com/stretchr/testify/require"
@@ -63,4 +68,348 @@ func TestExportBoard(t *testing.T) {
 		require.Len(t, blocksImported, 1)
 		require.Equal(t, block.Title, blocksImported[0].Title)
 	})
+	t.Run("incremental export and import", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		now := utils.GetMillis()
+		board := &model.Board{
+			ID:        utils.NewID(utils.IDTypeBoard),
+			TeamID:    "test-team",
+			Title:     "Incremental Export Board",
+			CreatedBy: th.GetUser1().ID,
+			Type:      model.BoardTypeOpen,
+			CreateAt:  now,
+			UpdateAt:  now,
+		}
+		newCard := func(title string) *model.Block {
+			return &model.Block{
+				ID:        utils.NewID(utils.IDTypeCard),
+				ParentID:  board.ID,
+				Type:      model.TypeCard,
+				BoardID:   board.ID,
+				Title:     title,
+				CreatedBy: th.GetUser1().ID,
+				CreateAt:  utils.GetMillis(),
+				UpdateAt:  utils.GetMillis(),
+			}
+		}
+		card1 := newCard("card 1")
+		card2 := newCard("card 2")
+
+		babs, resp := th.Client.CreateBoardsAndBlocks(&model.BoardsAndBlocks{
+			Boards: []*model.Board{board},
+			Blocks: []*model.Block{card1, card2},
+		})
+		th.CheckOK(resp)
+		boardID := babs.Boards[0].ID
+		for _, block := range babs.Blocks {
+			if block.Title == card1.Title {
+				card1 = block
+			} else {
+				card2 = block
+			}
+		}
+
+		fullArchive, resp := th.Client.ExportBoardArchive(boardID)
+		th.CheckOK(resp)
+		header, lines := readTestArchive(t, fullArchive)
+		require.False(t, header.Incremental)
+		require.NotZero(t, header.ModifiedUntil)
+		require.Len(t, lines[boardID+"/board.jsonl"], 4)
+
+		// change the board after the full export
+		time.Sleep(5 * time.Millisecond)
+		title := "card 1 changed"
+		_, resp = th.Client.PatchBlock(boardID, card1.ID, &model.BlockPatch{Title: &title}, false)
+		th.CheckOK(resp)
+		_, resp = th.Client.DeleteBlock(boardID, card2.ID, false)
+		th.CheckOK(resp)
+		card3 := newCard("card 3")
+		card3.BoardID = boardID
+		card3.ParentID = boardID
+		newBlocks, resp := th.Client.InsertBlocks(boardID, []*model.Block{card3}, false)
+		th.CheckOK(resp)
+		card3 = newBlocks[0]
+
+		incrementalArchive, resp := th.Client.ExportBoardArchiveModifiedSince(boardID, header.ModifiedUntil)
+		th.CheckOK(resp)
+		incrementalHeader, incrementalLines := readTestArchive(t, incrementalArchive)
+		require.True(t, incrementalHeader.Incremental)
+		require.Equal(t, header.ModifiedUntil, incrementalHeader.ModifiedSince)
+
+		blockIDs := []string{}
+		for _, line := range incrementalLines[boardID+"/board.jsonl"] {
+			if line.Type == "block" {
+				var block model.Block
+				require.NoError(t, json.Unmarshal(line.Data, &block))
+				blockIDs = append(blockIDs, block.ID)
+			}
+		}
+		require.ElementsMatch(t, []string{card1.ID, card3.ID}, blockIDs)
+
+		tombstoneLines := incrementalLines["tombstones.jsonl"]
+		require.Len(t, tombstoneLines, 1)
+		var tombstone model.ArchiveTombstone
+		require.NoError(t, json.Unmarshal(tombstoneLines[0].Data, &tombstone))
+		require.Equal(t, model.ArchiveTombstoneBlock, tombstone.Type)
+		require.Equal(t, card2.ID, tombstone.ID)
+
+		// an incremental archive can't be imported as a full one
+		resp = th.Client.ImportArchive("test-team", bytes.NewReader(incrementalArchive))
+		th.CheckBadRequest(resp)
+
+		// restore the board from both archives
+		_, resp = th.Client.DeleteBoard(boardID)
+		th.CheckOK(resp)
+
+		resp = th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(fullArchive))
+		th.CheckOK(resp)
+		blocks, err := th.Server.App().GetBlocksForBoard(boardID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+
+		resp = th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(incrementalArchive))
+		th.CheckOK(resp)
+		blocks, err = th.Server.App().GetBlocksForBoard(boardID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 2)
+		titles := map[string]string{}
+		for _, block := range blocks {
+			titles[block.ID] = block.Title
+		}
+		require.Equal(t, map[string]string{card1.ID: title, card3.ID: card3.Title}, titles)
+
+		// the importing user can access the restored board
+		restoredBoard, resp := th.Client.GetBoard(boardID, "")
+		th.CheckOK(resp)
+		require.Equal(t, "test-team", restoredBoard.TeamID)
+	})
+
+	t.Run("incremental imports check the permissions on existing boards", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := &model.Board{
+			ID:        utils.NewID(utils.IDTypeBoard),
+			TeamID:    "test-team",
+			Title:     "Private Board",
+			CreatedBy: th.GetUser1().ID,
+			Type:      model.BoardTypePrivate,
+			CreateAt:  utils.GetMillis(),
+			UpdateAt:  utils.GetMillis(),
+		}
+		card := &model.Block{
+			ID:        utils.NewID(utils.IDTypeCard),
+			ParentID:  board.ID,
+			Type:      model.TypeCard,
+			BoardID:   board.ID,
+			Title:     "card",
+			CreatedBy: th.GetUser1().ID,
+			CreateAt:  utils.GetMillis(),
+			UpdateAt:  utils.GetMillis(),
+		}
+		babs, resp := th.Client.CreateBoardsAndBlocks(&model.BoardsAndBlocks{
+			Boards: []*model.Board{board},
+			Blocks: []*model.Block{card},
+		})
+		th.CheckOK(resp)
+		boardID := babs.Boards[0].ID
+		cardID := babs.Blocks[0].ID
+
+		archive, resp := th.Client.ExportBoardArchive(boardID)
+		th.CheckOK(resp)
+
+		// a user that isn't a member of the board can't overwrite it
+		resp = th.Client2.ImportIncrementalArchive("test-team", bytes.NewReader(archive))
+		th.CheckForbidden(resp)
+		_, err := th.Server.App().GetMemberForBoard(boardID, th.GetUser2().ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		// nor delete it or its cards with tombstones
+		tombstones := []model.ArchiveTombstone{
+			{Type: model.ArchiveTombstoneBlock, ID: cardID, BoardID: boardID, DeleteAt: utils.GetMillis()},
+			{Type: model.ArchiveTombstoneBoard, ID: boardID, BoardID: boardID, DeleteAt: utils.GetMillis()},
+		}
+		for _, tombstone := range tombstones {
+			resp = th.Client2.ImportIncrementalArchive("test-team", bytes.NewReader(tombstoneTestArchive(t, tombstone)))
+			th.CheckForbidden(resp)
+		}
+		_, err = th.Server.App().GetBoard(boardID)
+		require.NoError(t, err)
+		_, err = th.Server.App().GetBlockByID(cardID)
+		require.NoError(t, err)
+
+		// the admin of the board can
+		resp = th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(archive))
+		th.CheckOK(resp)
+		resp = th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(tombstoneTestArchive(t, tombstones[0])))
+		th.CheckOK(resp)
+		_, err = th.Server.App().GetBlockByID(cardID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+	t.Run("incremental imports validate the card property values", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := &model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: "test-team",
+			Title:  "Imported Board",
+			Type:   model.BoardTypeOpen,
+			CardProperties: []map[string]interface{}{
+				{
+					"id":   "status",
+					"name": "Status",
+					"type": "select",
+					"options": []interface{}{
+						map[string]interface{}{"id": "todo", "value": "To do"},
+					},
+				},
+			},
+		}
+		card := &model.Block{
+			ID:       utils.NewID(utils.IDTypeCard),
+			ParentID: board.ID,
+			BoardID:  board.ID,
+			Type:     model.TypeCard,
+			Title:    "card",
+			Fields: map[string]interface{}{
+				"properties": map[string]interface{}{"status": "missing"},
+			},
+		}
+
+		resp := th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(boardTestArchive(t, board, card)))
+		invalid := invalidProperties(t, th, resp)
+		require.Len(t, invalid, 1)
+		require.Equal(t, "status", invalid[0].PropertyID)
+		_, err := th.Server.App().GetBlockByID(card.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		card.Fields["properties"] = map[string]interface{}{"status": "todo"}
+		resp = th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(boardTestArchive(t, board, card)))
+		th.CheckOK(resp)
+
+		// the cards that already exist are validated too
+		card.Fields["properties"] = map[string]interface{}{"status": "missing"}
+		resp = th.Client.ImportIncrementalArchive("test-team", bytes.NewReader(boardTestArchive(t, board, card)))
+		invalidProperties(t, th, resp)
+		imported, err := th.Server.App().GetBlockByID(card.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"status": "todo"}, imported.Fields["properties"])
+	})
+}
+
+func TestExportTeamTombstones(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	since := utils.GetMillis()
+	time.Sleep(5 * time.Millisecond)
+
+	boardIDs := map[model.BoardType]string{}
+	for _, boardType := range []model.BoardType{model.BoardTypeOpen, model.BoardTypePrivate} {
+		board, resp := th.Client.CreateBoard(&model.Board{TeamID: "test-team", Title: "deleted", Type: boardType})
+		th.CheckOK(resp)
+		_, resp = th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+		boardIDs[boardType] = board.ID
+	}
+
+	deletedBoards := func(c *client.Client) []string {
+		archive, resp := c.ExportTeamArchiveModifiedSince("test-team", since)
+		th.CheckOK(resp)
+		_, lines := readTestArchive(t, archive)
+
+		ids := []string{}
+		for _, line := range lines["tombstones.jsonl"] {
+			var tombstone model.ArchiveTombstone
+			require.NoError(t, json.Unmarshal(line.Data, &tombstone))
+			if tombstone.Type == model.ArchiveTombstoneBoard {
+				ids = append(ids, tombstone.ID)
+			}
+		}
+		return ids
+	}
+
+	require.ElementsMatch(t, []string{boardIDs[model.BoardTypeOpen], boardIDs[model.BoardTypePrivate]}, deletedBoards(th.Client))
+
+	// the private board of another user isn't disclosed
+	require.Equal(t, []string{boardIDs[model.BoardTypeOpen]}, deletedBoards(th.Client2))
+}
+
+// tombstoneTestArchive returns an incremental archive containing only a
+// tombstone.
+func tombstoneTestArchive(t *testing.T, tombstone model.ArchiveTombstone) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("version.json")
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(w).Encode(model.ArchiveHeader{Version: 2, Date: utils.GetMillis(), Incremental: true}))
+
+	data, err := json.Marshal(tombstone)
+	require.NoError(t, err)
+	w, err = zw.Create("tombstones.jsonl")
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(w).Encode(model.ArchiveLine{Type: "tombstone", Data: data}))
+
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// boardTestArchive returns an incremental archive containing a board and
+// its blocks.
+func boardTestArchive(t *testing.T, board *model.Board, blocks ...*model.Block) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("version.json")
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(w).Encode(model.ArchiveHeader{Version: 2, Date: utils.GetMillis(), Incremental: true}))
+
+	w, err = zw.Create(board.ID + "/board.jsonl")
+	require.NoError(t, err)
+	data, err := json.Marshal(board)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(w).Encode(model.ArchiveLine{Type: "board", Data: data}))
+	for _, block := range blocks {
+		data, err = json.Marshal(block)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(w).Encode(model.ArchiveLine{Type: "block", Data: data}))
+	}
+
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// readTestArchive returns the header of an archive and the lines of each
+// of its JSONL files.
+func readTestArchive(t *testing.T, archive []byte) (model.ArchiveHeader, map[string][]model.ArchiveLine) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	var header model.ArchiveHeader
+	lines := map[string][]model.ArchiveLine{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		if f.Name == "version.json" {
+			require.NoError(t, json.Unmarshal(data, &header))
+			continue
+		}
+		for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+			if len(line) == 0 {
+				continue
+			}
+			var archiveLine model.ArchiveLine
+			require.NoError(t, json.Unmarshal(line, &archiveLine))
+			lines[f.Name] = append(lines[f.Name], archiveLine)
+		}
+	}
+	return header, lines
 }
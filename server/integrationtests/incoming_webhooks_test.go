@@ -0,0 +1,251 @@
+package integrationtests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomingWebhooks(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+		{"id": "alert_id", "name": "Alert ID", "type": "text"},
+		{"id": "severity", "name": "Severity", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "sev_high", "value": "High"},
+			map[string]interface{}{"id": "sev_low", "value": "Low"},
+		}},
+		{"id": "owner", "name": "Owner", "type": "person"},
+	}})
+	th.CheckOK(resp)
+
+	hook, resp := th.Client.CreateIncomingWebhook(board.ID, &model.IncomingWebhook{
+		Name: "Monitoring",
+		Mapping: model.IncomingWebhookMapping{
+			Title:              "{{payload.alert.name}} on {{payload.host}}",
+			Properties:         map[string]interface{}{"severity": "{{payload.alert.severity}}", "owner": "{{creator}}"},
+			Content:            "{{payload.alert.description}}",
+			ExternalIDProperty: "alert_id",
+			ExternalID:         "{{payload.alert.id}}",
+		},
+	})
+	th.CheckOK(resp)
+	require.Equal(t, board.ID, hook.BoardID)
+	require.Equal(t, th.GetUser1().ID, hook.CreatedBy)
+	require.NotEmpty(t, hook.Token)
+
+	t.Run("the token is only returned on creation", func(t *testing.T) {
+		webhooks, resp := th.Client.GetIncomingWebhooks(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, webhooks, 1)
+		require.Empty(t, webhooks[0].Token)
+
+		fetched, resp := th.Client.GetIncomingWebhook(hook.ID)
+		th.CheckOK(resp)
+		require.Empty(t, fetched.Token)
+		require.Equal(t, hook.Mapping, fetched.Mapping)
+	})
+
+	t.Run("invalid mappings are rejected", func(t *testing.T) {
+		_, resp := th.Client.CreateIncomingWebhook(board.ID, &model.IncomingWebhook{Mapping: model.IncomingWebhookMapping{Title: ""}})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateIncomingWebhook(board.ID, &model.IncomingWebhook{Mapping: model.IncomingWebhookMapping{
+			Title: "{{payload.title}}", Properties: map[string]interface{}{"missing": "{{payload.value}}"},
+		}})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateIncomingWebhook(board.ID, &model.IncomingWebhook{Mapping: model.IncomingWebhookMapping{
+			Title: "{{payload.title}}", ExternalIDProperty: "severity", ExternalID: "{{payload.id}}",
+		}})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("users without access can't manage the webhooks", func(t *testing.T) {
+		_, resp := th.Client2.CreateIncomingWebhook(board.ID, &model.IncomingWebhook{Mapping: model.IncomingWebhookMapping{Title: "{{payload.title}}"}})
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.GetIncomingWebhooks(board.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.GetIncomingWebhook(hook.ID)
+		th.CheckForbidden(resp)
+
+		resp = th.Client2.DeleteIncomingWebhook(hook.ID)
+		th.CheckForbidden(resp)
+	})
+
+	var cardID string
+
+	t.Run("a payload creates a card", func(t *testing.T) {
+		result, resp := th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{
+			"host": "db-1",
+			"alert": {"id": "alert-42", "name": "Disk full", "severity": "high", "description": "95% used"}
+		}`)
+		th.CheckOK(resp)
+		require.True(t, result.Created)
+		cardID = result.CardID
+
+		card, resp := th.Client.GetCard(cardID)
+		th.CheckOK(resp)
+		require.Equal(t, "Disk full on db-1", card.Title)
+		require.Equal(t, th.GetUser1().ID, card.CreatedBy)
+		require.Equal(t, "alert-42", card.Properties["alert_id"])
+		require.Equal(t, "sev_high", card.Properties["severity"])
+		require.Equal(t, th.GetUser1().ID, card.Properties["owner"])
+		require.Len(t, card.ContentOrder, 1)
+
+		blocks, resp := th.Client.GetAllBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		var text *model.Block
+		for _, block := range blocks {
+			if block.ID == card.ContentOrder[0] {
+				text = block
+			}
+		}
+		require.NotNil(t, text)
+		require.Equal(t, model.BlockType(model.TypeText), text.Type)
+		require.Equal(t, cardID, text.ParentID)
+		require.Equal(t, "95% used", text.Title)
+	})
+
+	t.Run("a payload with the same external ID updates the card", func(t *testing.T) {
+		// the properties that aren't mapped are kept
+		_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+			{"id": "notes", "name": "Notes", "type": "text"},
+		}})
+		th.CheckOK(resp)
+		card, resp := th.Client.GetCard(cardID)
+		th.CheckOK(resp)
+		card.Properties["notes"] = "investigating"
+		_, resp = th.Client.PatchCard(cardID, &model.CardPatch{UpdatedProperties: card.Properties}, false)
+		th.CheckOK(resp)
+
+		result, resp := th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{
+			"host": "db-1",
+			"alert": {"id": "alert-42", "name": "Disk almost full", "severity": "Low"}
+		}`)
+		th.CheckOK(resp)
+		require.False(t, result.Created)
+		require.Equal(t, cardID, result.CardID)
+
+		card, resp = th.Client.GetCard(cardID)
+		th.CheckOK(resp)
+		require.Equal(t, "Disk almost full on db-1", card.Title)
+		require.Equal(t, "sev_low", card.Properties["severity"])
+		require.Equal(t, "investigating", card.Properties["notes"])
+		require.Len(t, card.ContentOrder, 1)
+
+		// another external ID creates another card
+		result, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": {"id": "alert-43", "name": "CPU high"}}`)
+		th.CheckOK(resp)
+		require.True(t, result.Created)
+		require.NotEqual(t, cardID, result.CardID)
+	})
+
+	t.Run("invalid payloads are rejected", func(t *testing.T) {
+		_, resp := th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": `)
+		th.CheckBadRequest(resp)
+
+		// the external ID is missing
+		_, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": {"name": "Disk full"}}`)
+		th.CheckBadRequest(resp)
+
+		// the severity isn't an option of the property
+		_, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": {"id": "alert-44", "severity": "critical"}}`)
+		th.CheckBadRequest(resp)
+
+		payload := `{"alert": {"id": "alert-45", "name": "` + strings.Repeat("a", model.IncomingWebhookPayloadMaxSize) + `"}}`
+		_, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, payload)
+		th.CheckRequestEntityTooLarge(resp)
+	})
+
+	t.Run("requests need the token of the webhook but no session", func(t *testing.T) {
+		_, resp := th.Client.ExecuteIncomingWebhook(hook.ID, "wrong-token", `{"alert": {"id": "alert-42"}}`)
+		th.CheckUnauthorized(resp)
+
+		_, resp = th.Client.ExecuteIncomingWebhook("missing", hook.Token, `{"alert": {"id": "alert-42"}}`)
+		th.CheckUnauthorized(resp)
+
+		// the token isn't accepted as a query parameter, where it would be logged
+		url := th.Client.APIURL + "/hooks/" + hook.ID + "?token=" + hook.Token
+		r, err := http.Post(url, "application/json", strings.NewReader(`{"alert": {"id": "alert-50", "name": "From CI"}}`))
+		require.NoError(t, err)
+		defer r.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, r.StatusCode)
+
+		r, err = http.Post(th.Client.APIURL+"/hooks/"+hook.ID, "application/json", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		defer r.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, r.StatusCode)
+	})
+
+	t.Run("the changes are notified", func(t *testing.T) {
+		receiver := &webhookReceiver{status: http.StatusOK}
+		ts := httptest.NewServer(receiver)
+		defer ts.Close()
+
+		outgoing, resp := th.Client.CreateBoardWebhook(board.ID, &model.Webhook{URL: ts.URL, EventTypes: []string{model.WebhookEventCardCreated}})
+		th.CheckOK(resp)
+
+		_, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": {"id": "alert-60", "name": "Memory high"}}`)
+		th.CheckOK(resp)
+
+		require.Eventually(t, func() bool {
+			deliveries, resp := th.Client.GetWebhookDeliveries(outgoing.ID, "", 0, 10)
+			th.CheckOK(resp)
+			return len(deliveries) == 1
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("disabled webhooks reject the requests", func(t *testing.T) {
+		disabled := true
+		patched, resp := th.Client.PatchIncomingWebhook(hook.ID, &model.IncomingWebhookPatch{Disabled: &disabled})
+		th.CheckOK(resp)
+		require.True(t, patched.Disabled)
+		require.Empty(t, patched.Token)
+
+		_, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": {"id": "alert-42"}}`)
+		th.CheckForbidden(resp)
+
+		enabled := false
+		_, resp = th.Client.PatchIncomingWebhook(hook.ID, &model.IncomingWebhookPatch{Disabled: &enabled})
+		th.CheckOK(resp)
+	})
+
+	t.Run("the webhook stops working when its creator loses access", func(t *testing.T) {
+		_, resp := th.Client.AddMemberToBoard(&model.BoardMember{BoardID: board.ID, UserID: th.GetUser2().ID, SchemeAdmin: true})
+		th.CheckOK(resp)
+
+		user2Hook, resp := th.Client2.CreateIncomingWebhook(board.ID, &model.IncomingWebhook{Mapping: model.IncomingWebhookMapping{Title: "{{payload.title}}"}})
+		th.CheckOK(resp)
+
+		_, resp = th.Client.ExecuteIncomingWebhook(user2Hook.ID, user2Hook.Token, `{"title": "From user2"}`)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.UpdateBoardMember(&model.BoardMember{BoardID: board.ID, UserID: th.GetUser2().ID, SchemeViewer: true})
+		th.CheckOK(resp)
+
+		_, resp = th.Client.ExecuteIncomingWebhook(user2Hook.ID, user2Hook.Token, `{"title": "From user2"}`)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("delete a webhook", func(t *testing.T) {
+		resp := th.Client.DeleteIncomingWebhook(hook.ID)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.GetIncomingWebhook(hook.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = th.Client.ExecuteIncomingWebhook(hook.ID, hook.Token, `{"alert": {"id": "alert-42"}}`)
+		th.CheckUnauthorized(resp)
+	})
+}
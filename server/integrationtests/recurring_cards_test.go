@@ -0,0 +1,156 @@
+package integrationtests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringCardRules(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+		{"id": "due", "name": "Due", "type": "date", "options": []interface{}{}},
+		{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "status_todo", "value": "To Do"},
+		}},
+	}})
+	th.CheckOK(resp)
+
+	template, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "Weekly ops", IsTemplate: true}, true)
+	th.CheckOK(resp)
+	_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  board.ID,
+		ParentID: template.ID,
+		Type:     model.TypeText,
+		Title:    "check the backups",
+		CreateAt: 1,
+		UpdateAt: 1,
+	}}, true)
+	th.CheckOK(resp)
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "not a template"}, true)
+	th.CheckOK(resp)
+
+	rule, resp := th.Client.CreateRecurringCardRule(board.ID, &model.RecurringCardRule{
+		TemplateID: template.ID,
+		Schedule:   "0 9 * * mon",
+		Timezone:   "Europe/Paris",
+		Overrides: model.RecurringCardOverrides{
+			Title:       "Ops checklist",
+			Properties:  map[string]interface{}{"status": "status_todo"},
+			DateOffsets: map[string]int{"due": 4},
+		},
+	})
+	th.CheckOK(resp)
+	require.Equal(t, board.ID, rule.BoardID)
+	require.Equal(t, th.GetUser1().ID, rule.CreatedBy)
+	require.Greater(t, rule.NextRunAt, utils.GetMillis())
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	nextRun := utils.GetTimeForMillis(rule.NextRunAt).In(paris)
+	require.Equal(t, time.Monday, nextRun.Weekday())
+	require.Equal(t, 9, nextRun.Hour())
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		_, resp := th.Client.CreateRecurringCardRule(board.ID, &model.RecurringCardRule{TemplateID: card.ID, Schedule: "@daily"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateRecurringCardRule(board.ID, &model.RecurringCardRule{TemplateID: template.ID, Schedule: "every monday"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateRecurringCardRule(board.ID, &model.RecurringCardRule{
+			TemplateID: template.ID,
+			Schedule:   "@daily",
+			Overrides:  model.RecurringCardOverrides{Properties: map[string]interface{}{"status": "status_unknown"}},
+		})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("users without access can't manage the rules", func(t *testing.T) {
+		_, resp := th.Client2.GetRecurringCardRules(board.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.CreateRecurringCardRule(board.ID, &model.RecurringCardRule{TemplateID: template.ID, Schedule: "@daily"})
+		th.CheckForbidden(resp)
+
+		resp = th.Client2.DeleteRecurringCardRule(board.ID, rule.ID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("due rules create a card from the template", func(t *testing.T) {
+		created, err := th.Server.App().RunRecurringCardRules(rule.NextRunAt)
+		require.NoError(t, err)
+		require.Equal(t, 1, created)
+
+		rules, resp := th.Client.GetRecurringCardRules(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule.NextRunAt, rules[0].LastRunAt)
+		require.Equal(t, utils.GetMillisForTime(nextRun.AddDate(0, 0, 7)), rules[0].NextRunAt)
+		require.Empty(t, rules[0].LastError)
+		require.NotEmpty(t, rules[0].LastCardID)
+
+		newCard, resp := th.Client.GetCard(rules[0].LastCardID)
+		th.CheckOK(resp)
+		require.Equal(t, "Ops checklist", newCard.Title)
+		require.False(t, newCard.IsTemplate)
+		require.Equal(t, "status_todo", newCard.Properties["status"])
+		due := time.Date(nextRun.Year(), nextRun.Month(), nextRun.Day()+4, 0, 0, 0, 0, time.UTC)
+		require.Equal(t, `{"from":`+strconv.FormatInt(utils.GetMillisForTime(due), 10)+`}`, newCard.Properties["due"])
+
+		blocks, resp := th.Client.GetBlocksForBoard(board.ID)
+		th.CheckOK(resp)
+		var content []*model.Block
+		for _, block := range blocks {
+			if block.ParentID == newCard.ID {
+				content = append(content, block)
+			}
+		}
+		require.Len(t, content, 1)
+		require.Equal(t, "check the backups", content[0].Title)
+
+		// the run isn't repeated
+		created, err = th.Server.App().RunRecurringCardRules(rule.NextRunAt)
+		require.NoError(t, err)
+		require.Zero(t, created)
+	})
+
+	t.Run("pause and resume a rule", func(t *testing.T) {
+		paused := true
+		patched, resp := th.Client.PatchRecurringCardRule(board.ID, rule.ID, &model.RecurringCardRulePatch{Paused: &paused})
+		th.CheckOK(resp)
+		require.True(t, patched.Paused)
+		require.Zero(t, patched.NextRunAt)
+
+		created, err := th.Server.App().RunRecurringCardRules(utils.GetMillis() + int64(365*24*time.Hour/time.Millisecond))
+		require.NoError(t, err)
+		require.Zero(t, created)
+
+		paused = false
+		patched, resp = th.Client.PatchRecurringCardRule(board.ID, rule.ID, &model.RecurringCardRulePatch{Paused: &paused})
+		th.CheckOK(resp)
+		require.False(t, patched.Paused)
+		require.Greater(t, patched.NextRunAt, utils.GetMillis())
+	})
+
+	t.Run("delete a rule", func(t *testing.T) {
+		resp := th.Client.DeleteRecurringCardRule(board.ID, rule.ID)
+		th.CheckOK(resp)
+
+		resp = th.Client.DeleteRecurringCardRule(board.ID, rule.ID)
+		th.CheckNotFound(resp)
+
+		rules, resp := th.Client.GetRecurringCardRules(board.ID)
+		th.CheckOK(resp)
+		require.Empty(t, rules)
+	})
+}
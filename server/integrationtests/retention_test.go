@@ -0,0 +1,102 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestHelperForRetention(t *testing.T) (*TestHelper, Clients) {
+	th := SetupTestHelperPluginMode(t)
+	clients := setupClients(th)
+
+	th.Client = clients.TeamMember
+	th.Client2 = clients.TeamMember
+
+	return th, clients
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	t.Run("a user without manage_system permission should be rejected", func(t *testing.T) {
+		th, clients := setupTestHelperForRetention(t)
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		policies, resp := clients.TeamMember.GetRetentionPolicies(testTeamID)
+		th.CheckUnauthorized(resp)
+		require.Nil(t, policies)
+
+		_, resp = clients.TeamMember.SaveTeamRetentionPolicy(testTeamID, &model.RetentionPolicy{RetentionDays: 30})
+		th.CheckUnauthorized(resp)
+
+		_, resp = clients.TeamMember.SaveBoardRetentionPolicy(board.ID, &model.RetentionPolicy{LegalHold: true})
+		th.CheckUnauthorized(resp)
+
+		_, resp = clients.TeamMember.RunDataRetention()
+		th.CheckUnauthorized(resp)
+	})
+
+	t.Run("manage team and board policies", func(t *testing.T) {
+		th, clients := setupTestHelperForRetention(t)
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypeOpen)
+
+		teamPolicy, resp := clients.Admin.SaveTeamRetentionPolicy(testTeamID, &model.RetentionPolicy{RetentionDays: 30})
+		th.CheckOK(resp)
+		require.Equal(t, testTeamID, teamPolicy.TeamID)
+		require.Equal(t, 30, teamPolicy.RetentionDays)
+		require.Equal(t, userAdmin, teamPolicy.ModifiedBy)
+
+		// legal hold is only valid for boards
+		_, resp = clients.Admin.SaveTeamRetentionPolicy(testTeamID, &model.RetentionPolicy{LegalHold: true})
+		th.CheckBadRequest(resp)
+
+		_, resp = clients.Admin.SaveBoardRetentionPolicy("missing-board-id", &model.RetentionPolicy{LegalHold: true})
+		th.CheckNotFound(resp)
+
+		boardPolicy, resp := clients.Admin.SaveBoardRetentionPolicy(board.ID, &model.RetentionPolicy{LegalHold: true})
+		th.CheckOK(resp)
+		require.Equal(t, testTeamID, boardPolicy.TeamID)
+		require.Equal(t, board.ID, boardPolicy.BoardID)
+		require.True(t, boardPolicy.LegalHold)
+
+		policies, resp := clients.Admin.GetRetentionPolicies(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, policies, 2)
+		require.Empty(t, policies[0].BoardID)
+		require.Equal(t, board.ID, policies[1].BoardID)
+
+		_, resp = clients.Admin.DeleteBoardRetentionPolicy(board.ID)
+		th.CheckOK(resp)
+		_, resp = clients.Admin.DeleteBoardRetentionPolicy(board.ID)
+		th.CheckNotFound(resp)
+
+		_, resp = clients.Admin.DeleteTeamRetentionPolicy(testTeamID)
+		th.CheckOK(resp)
+
+		policies, resp = clients.Admin.GetRetentionPolicies(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, policies)
+	})
+
+	t.Run("run the data retention", func(t *testing.T) {
+		th, clients := setupTestHelperForRetention(t)
+		defer th.TearDown()
+
+		board, _ := th.CreateBoardAndCards(testTeamID, model.BoardTypeOpen, 1)
+
+		_, resp := clients.Admin.SaveBoardRetentionPolicy(board.ID, &model.RetentionPolicy{LegalHold: true})
+		th.CheckOK(resp)
+
+		report, resp := clients.Admin.RunDataRetention()
+		th.CheckOK(resp)
+		require.Empty(t, report.Boards)
+		require.Equal(t, []string{board.ID}, report.LegalHoldBoardIDs)
+
+		_, resp = th.Client.GetBoard(board.ID, "")
+		th.CheckOK(resp)
+	})
+}
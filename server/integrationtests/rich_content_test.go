@@ -0,0 +1,124 @@
+package integrationtests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichContentBlocks(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "Design"}, true)
+	th.CheckOK(resp)
+
+	newBlock := func(blockType model.BlockType, title string, fields map[string]interface{}) *model.Block {
+		return &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: card.ID,
+			Type:     blockType,
+			Title:    title,
+			Fields:   fields,
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+		}
+	}
+
+	blocks, resp := th.Client.InsertBlocks(board.ID, []*model.Block{
+		newBlock(model.TypeHeading, "Overview", map[string]interface{}{"level": 2}),
+		newBlock(model.TypeCode, "SELECT 1;", map[string]interface{}{"language": "sql"}),
+		newBlock(model.TypeBulletList, "", map[string]interface{}{"items": []string{"fast", "safe"}}),
+		newBlock(model.TypeNumberedList, "", map[string]interface{}{"items": []string{"design", "build"}, "start": 1}),
+		newBlock(model.TypeTable, "", map[string]interface{}{
+			"columns": []string{"Owner", "Status"},
+			"rows":    [][]string{{"alice", "done"}},
+		}),
+		newBlock(model.TypeEmbed, "Mockups", map[string]interface{}{"url": "https://example.com/mockups", "embedType": model.EmbedTypeFrame}),
+	}, true)
+	th.CheckOK(resp)
+	require.Len(t, blocks, 6)
+
+	t.Run("invalid blocks are rejected", func(t *testing.T) {
+		invalidBlocks := []*model.Block{
+			newBlock(model.TypeHeading, "Too deep", map[string]interface{}{"level": 9}),
+			newBlock(model.TypeTable, "", map[string]interface{}{"columns": []string{"A", "B"}, "rows": [][]string{{"1"}}}),
+			newBlock(model.TypeEmbed, "", map[string]interface{}{"url": "file:///etc/passwd"}),
+			newBlock(model.TypeBulletList, "", map[string]interface{}{"items": "not a list"}),
+		}
+		for _, block := range invalidBlocks {
+			_, resp := th.Client.InsertBlocks(board.ID, []*model.Block{block}, true)
+			th.CheckBadRequest(resp)
+		}
+
+		// rich content belongs to cards
+		view := newBlock(model.TypeView, "", nil)
+		view.ParentID = board.ID
+		views, resp := th.Client.InsertBlocks(board.ID, []*model.Block{view}, true)
+		th.CheckOK(resp)
+		inView := newBlock(model.TypeCode, "", nil)
+		inView.ParentID = views[0].ID
+		_, resp = th.Client.InsertBlocks(board.ID, []*model.Block{inView}, true)
+		th.CheckBadRequest(resp)
+	})
+
+	contentOrder := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		contentOrder = append(contentOrder, block.ID)
+	}
+
+	t.Run("content is ordered in the card", func(t *testing.T) {
+		_, resp := th.Client.PatchCard(card.ID, &model.CardPatch{ContentOrder: &contentOrder}, true)
+		th.CheckOK(resp)
+
+		// the code block is moved after the table
+		_, resp = th.Client.MoveContentBlock(blocks[1].ID, blocks[4].ID, "after", th.GetUser1().ID)
+		th.CheckOK(resp)
+
+		fetched, resp := th.Client.GetCard(card.ID)
+		th.CheckOK(resp)
+		require.Equal(t, []string{blocks[0].ID, blocks[2].ID, blocks[3].ID, blocks[4].ID, blocks[1].ID, blocks[5].ID}, fetched.ContentOrder)
+	})
+
+	t.Run("content is exported and imported", func(t *testing.T) {
+		archive, resp := th.Client.ExportBoardArchive(board.ID)
+		th.CheckOK(resp)
+		resp = th.Client.ImportArchive(model.GlobalTeamID, bytes.NewReader(archive))
+		th.CheckOK(resp)
+
+		boards, err := th.Server.App().GetBoardsForUserAndTeam(th.GetUser1().ID, model.GlobalTeamID, true)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		imported, err := th.Server.App().GetBlocksForBoard(boards[0].ID)
+		require.NoError(t, err)
+
+		byType := map[model.BlockType]*model.Block{}
+		for _, block := range imported {
+			byType[block.Type] = block
+		}
+		require.Equal(t, "SELECT 1;", byType[model.TypeCode].Title)
+		require.Equal(t, "sql", model.CodeLanguage(byType[model.TypeCode]))
+		require.Equal(t, []string{"fast", "safe"}, model.ListItems(byType[model.TypeBulletList]))
+		columns, rows := model.TableContent(byType[model.TypeTable])
+		require.Equal(t, []string{"Owner", "Status"}, columns)
+		require.Equal(t, [][]string{{"alice", "done"}}, rows)
+		require.Equal(t, "https://example.com/mockups", model.EmbedURL(byType[model.TypeEmbed]))
+
+		importedCard, err := model.Block2Card(byType[model.TypeCard])
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			byType[model.TypeHeading].ID,
+			byType[model.TypeBulletList].ID,
+			byType[model.TypeNumberedList].ID,
+			byType[model.TypeTable].ID,
+			byType[model.TypeCode].ID,
+			byType[model.TypeEmbed].ID,
+		}, importedCard.ContentOrder)
+	})
+}
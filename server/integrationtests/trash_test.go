@@ -0,0 +1,137 @@
+package integrationtests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardsTrash(t *testing.T) {
+	t.Run("a non authenticated user should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+		th.Logout(th.Client)
+
+		boards, resp := th.Client.GetDeletedBoards(testTeamID)
+		th.CheckUnauthorized(resp)
+		require.Nil(t, boards)
+	})
+
+	t.Run("list, restore and purge deleted boards", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		otherBoard := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		_, resp := th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+
+		boards, resp := th.Client.GetDeletedBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, boards, 1)
+		require.Equal(t, board.ID, boards[0].ID)
+		require.Equal(t, th.GetUser1().ID, boards[0].ModifiedBy)
+		require.NotZero(t, boards[0].DeleteAt)
+
+		// a user without access to the board doesn't see it and can't
+		// restore or purge it
+		boards, resp = th.Client2.GetDeletedBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, boards)
+
+		_, resp = th.Client2.RestoreDeletedBoard(testTeamID, board.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.PurgeBoard(testTeamID, board.ID)
+		th.CheckForbidden(resp)
+
+		// live boards are not in the trash
+		_, resp = th.Client.PurgeBoard(testTeamID, otherBoard.ID)
+		th.CheckNotFound(resp)
+
+		restored, resp := th.Client.RestoreDeletedBoard(testTeamID, board.ID)
+		th.CheckOK(resp)
+		require.Equal(t, board.ID, restored.ID)
+		require.Zero(t, restored.DeleteAt)
+
+		boards, resp = th.Client.GetDeletedBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, boards)
+
+		_, resp = th.Client.DeleteBoard(board.ID)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.PurgeBoard(testTeamID, board.ID)
+		th.CheckOK(resp)
+
+		boards, resp = th.Client.GetDeletedBoards(testTeamID)
+		th.CheckOK(resp)
+		require.Empty(t, boards)
+
+		// a purged board can't be restored anymore
+		_, resp = th.Client.RestoreDeletedBoard(testTeamID, board.ID)
+		th.CheckForbidden(resp)
+	})
+}
+
+func TestBlocksTrash(t *testing.T) {
+	t.Run("a user without access to the board should be rejected", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+
+		blocks, resp := th.Client2.GetDeletedBlocks(board.ID)
+		th.CheckForbidden(resp)
+		require.Nil(t, blocks)
+	})
+
+	t.Run("list, restore and purge deleted cards", func(t *testing.T) {
+		th := SetupTestHelper(t).InitBasic()
+		defer th.TearDown()
+
+		board, cards := th.CreateBoardAndCards(testTeamID, model.BoardTypePrivate, 2)
+		deletedCard, liveCard := cards[0], cards[1]
+
+		_, resp := th.Client.DeleteBlock(board.ID, deletedCard.ID, true)
+		th.CheckOK(resp)
+
+		blocks, resp := th.Client.GetDeletedBlocks(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, blocks, 1)
+		require.Equal(t, deletedCard.ID, blocks[0].ID)
+		require.EqualValues(t, model.TypeCard, blocks[0].Type)
+		require.Equal(t, th.GetUser1().ID, blocks[0].ModifiedBy)
+		require.NotZero(t, blocks[0].DeleteAt)
+
+		// live cards are not in the trash
+		_, resp = th.Client.RestoreDeletedBlock(board.ID, liveCard.ID)
+		th.CheckNotFound(resp)
+		_, resp = th.Client.PurgeBlock(board.ID, liveCard.ID)
+		th.CheckNotFound(resp)
+
+		restored, resp := th.Client.RestoreDeletedBlock(board.ID, deletedCard.ID)
+		th.CheckOK(resp)
+		require.Equal(t, deletedCard.ID, restored.ID)
+
+		card, resp := th.Client.GetCard(deletedCard.ID)
+		th.CheckOK(resp)
+		require.Equal(t, deletedCard.Title, card.Title)
+
+		_, resp = th.Client.DeleteBlock(board.ID, deletedCard.ID, true)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.PurgeBlock(board.ID, deletedCard.ID)
+		th.CheckOK(resp)
+
+		blocks, resp = th.Client.GetDeletedBlocks(board.ID)
+		th.CheckOK(resp)
+		require.Empty(t, blocks)
+
+		_, resp = th.Client.RestoreDeletedBlock(board.ID, deletedCard.ID)
+		th.CheckNotFound(resp)
+	})
+}
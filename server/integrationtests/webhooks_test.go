@@ -0,0 +1,354 @@
+package integrationtests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/webhook"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	response string
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.requests = append(wr.requests, r)
+	wr.bodies = append(wr.bodies, body)
+	w.WriteHeader(wr.status)
+	_, _ = w.Write([]byte(wr.response))
+}
+
+func (wr *webhookReceiver) setStatus(status int) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.status = status
+}
+
+// last returns the last request received and its payload.
+func (wr *webhookReceiver) last(t *testing.T) (*http.Request, []byte, *model.WebhookPayload) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	require.NotEmpty(t, wr.requests)
+	body := wr.bodies[len(wr.bodies)-1]
+	var payload model.WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	return wr.requests[len(wr.requests)-1], body, &payload
+}
+
+func TestWebhooks(t *testing.T) {
+	th := SetupTestHelper(t).InitBasic()
+	defer th.TearDown()
+
+	receiver := &webhookReceiver{status: http.StatusOK, response: "received"}
+	ts := httptest.NewServer(receiver)
+	defer ts.Close()
+
+	board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+	_, resp := th.Client.PatchBoard(board.ID, &model.BoardPatch{UpdatedCardProperties: []map[string]interface{}{
+		{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+			map[string]interface{}{"id": "status_todo", "value": "To Do"},
+			map[string]interface{}{"id": "status_done", "value": "Done"},
+		}},
+	}})
+	th.CheckOK(resp)
+
+	hook, resp := th.Client.CreateBoardWebhook(board.ID, &model.Webhook{URL: ts.URL})
+	th.CheckOK(resp)
+	require.Equal(t, board.ID, hook.BoardID)
+	require.Equal(t, testTeamID, hook.TeamID)
+	require.NotEmpty(t, hook.Secret)
+
+	commentsHook, resp := th.Client.CreateBoardWebhook(board.ID, &model.Webhook{URL: ts.URL, EventTypes: []string{model.WebhookEventCommentAdded}})
+	th.CheckOK(resp)
+
+	// waitForDelivery waits for the deliveries of the events queued
+	// asynchronously, attempts them and returns the last one.
+	waitForDelivery := func(webhookID string, count int) *model.WebhookDelivery {
+		var deliveries []*model.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, resp = th.Client.GetWebhookDeliveries(webhookID, "", 0, 100)
+			th.CheckOK(resp)
+			return len(deliveries) >= count
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Len(t, deliveries, count)
+
+		_, err := th.Server.App().ProcessWebhookDeliveries(utils.GetMillis())
+		require.NoError(t, err)
+
+		delivery, resp := th.Client.GetWebhookDeliveries(webhookID, "", 0, 1)
+		th.CheckOK(resp)
+		return delivery[0]
+	}
+
+	t.Run("the secret is only returned on creation", func(t *testing.T) {
+		webhooks, resp := th.Client.GetBoardWebhooks(board.ID)
+		th.CheckOK(resp)
+		require.Len(t, webhooks, 2)
+		require.Empty(t, webhooks[0].Secret)
+
+		fetched, resp := th.Client.GetWebhook(hook.ID)
+		th.CheckOK(resp)
+		require.Empty(t, fetched.Secret)
+		require.Equal(t, ts.URL, fetched.URL)
+	})
+
+	t.Run("invalid webhooks are rejected", func(t *testing.T) {
+		_, resp := th.Client.CreateBoardWebhook(board.ID, &model.Webhook{URL: "gopher://example.com"})
+		th.CheckBadRequest(resp)
+
+		_, resp = th.Client.CreateBoardWebhook(board.ID, &model.Webhook{URL: ts.URL, EventTypes: []string{"card.deleted"}})
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("users without access can't manage the webhooks", func(t *testing.T) {
+		_, resp := th.Client2.CreateBoardWebhook(board.ID, &model.Webhook{URL: ts.URL})
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.GetBoardWebhooks(board.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.GetWebhook(hook.ID)
+		th.CheckForbidden(resp)
+
+		_, resp = th.Client2.GetWebhookDeliveries(hook.ID, "", 0, 10)
+		th.CheckForbidden(resp)
+
+		resp = th.Client2.DeleteWebhook(hook.ID)
+		th.CheckForbidden(resp)
+	})
+
+	var card *model.Card
+	t.Run("card created", func(t *testing.T) {
+		card, resp = th.Client.CreateCard(board.ID, &model.Card{Title: "Launch"}, false)
+		th.CheckOK(resp)
+
+		delivery := waitForDelivery(hook.ID, 1)
+		require.Equal(t, model.WebhookDeliverySuccess, delivery.Status)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.ResponseCode)
+		// the response is only returned to system admins
+		require.Empty(t, delivery.ResponseBody)
+
+		request, body, payload := receiver.last(t)
+		require.Equal(t, model.WebhookEventCardCreated, payload.Event)
+		require.Equal(t, card.ID, payload.CardID)
+		require.Equal(t, board.ID, payload.BoardID)
+		require.Equal(t, th.GetUser1().ID, payload.UserID)
+
+		require.Equal(t, model.WebhookEventCardCreated, request.Header.Get(webhook.HeaderEvent))
+		require.Equal(t, delivery.ID, request.Header.Get(webhook.HeaderDelivery))
+		timestamp, err := strconv.ParseInt(request.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		require.Equal(t, webhook.Sign(hook.Secret, timestamp, body), request.Header.Get(webhook.HeaderSignature))
+	})
+
+	t.Run("card property changed", func(t *testing.T) {
+		_, resp := th.Client.PatchCard(card.ID, &model.CardPatch{
+			UpdatedProperties: map[string]any{"status": "status_done"},
+		}, false)
+		th.CheckOK(resp)
+
+		delivery := waitForDelivery(hook.ID, 2)
+		require.Equal(t, model.WebhookEventCardPropertyChanged, delivery.Event)
+
+		_, _, payload := receiver.last(t)
+		require.Equal(t, model.WebhookEventCardPropertyChanged, payload.Event)
+		require.Equal(t, card.ID, payload.CardID)
+		require.Equal(t, []interface{}{map[string]interface{}{
+			"propertyId":   "status",
+			"propertyName": "Status",
+			"oldValue":     nil,
+			"newValue":     "status_done",
+		}}, payload.Data["changes"])
+	})
+
+	t.Run("comment added", func(t *testing.T) {
+		_, resp := th.Client.InsertBlocks(board.ID, []*model.Block{{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  board.ID,
+			ParentID: card.ID,
+			Type:     model.TypeComment,
+			Title:    "Shipped!",
+			CreateAt: utils.GetMillis(),
+			UpdateAt: utils.GetMillis(),
+		}}, false)
+		th.CheckOK(resp)
+
+		delivery := waitForDelivery(commentsHook.ID, 1)
+		require.Equal(t, model.WebhookEventCommentAdded, delivery.Event)
+		require.Equal(t, model.WebhookDeliverySuccess, delivery.Status)
+
+		// the comment webhook only receives the comments
+		deliveries, resp := th.Client.GetWebhookDeliveries(commentsHook.ID, "", 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, deliveries, 1)
+
+		delivery = waitForDelivery(hook.ID, 3)
+		require.Equal(t, model.WebhookEventCommentAdded, delivery.Event)
+		var payload model.WebhookPayload
+		require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		require.Equal(t, card.ID, payload.CardID)
+	})
+
+	t.Run("member added", func(t *testing.T) {
+		_, resp := th.Client.AddMemberToBoard(&model.BoardMember{BoardID: board.ID, UserID: th.GetUser2().ID, SchemeViewer: true})
+		th.CheckOK(resp)
+
+		delivery := waitForDelivery(hook.ID, 4)
+		require.Equal(t, model.WebhookEventMemberAdded, delivery.Event)
+
+		_, _, payload := receiver.last(t)
+		require.Equal(t, th.GetUser2().ID, payload.UserID)
+	})
+
+	var failed *model.WebhookDelivery
+	t.Run("failed deliveries are retried", func(t *testing.T) {
+		receiver.setStatus(http.StatusInternalServerError)
+		_, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "Retry"}, false)
+		th.CheckOK(resp)
+
+		failed = waitForDelivery(hook.ID, 5)
+		require.Equal(t, model.WebhookDeliveryPending, failed.Status)
+		require.Equal(t, 1, failed.Attempts)
+		require.Equal(t, http.StatusInternalServerError, failed.ResponseCode)
+		require.NotEmpty(t, failed.Error)
+		require.Greater(t, failed.NextAttemptAt, failed.LastAttemptAt)
+
+		// not due yet
+		attempted, err := th.Server.App().ProcessWebhookDeliveries(utils.GetMillis())
+		require.NoError(t, err)
+		require.Zero(t, attempted)
+
+		attempted, err = th.Server.App().ProcessWebhookDeliveries(failed.NextAttemptAt)
+		require.NoError(t, err)
+		require.Equal(t, 1, attempted)
+
+		pending, resp := th.Client.GetWebhookDeliveries(hook.ID, model.WebhookDeliveryPending, 0, 10)
+		th.CheckOK(resp)
+		require.Len(t, pending, 1)
+		require.Equal(t, 2, pending[0].Attempts)
+		require.Greater(t, pending[0].NextAttemptAt-pending[0].LastAttemptAt, failed.NextAttemptAt-failed.LastAttemptAt)
+
+		_, resp = th.Client.GetWebhookDeliveries(hook.ID, "lost", 0, 10)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("deliveries can be redelivered", func(t *testing.T) {
+		receiver.setStatus(http.StatusOK)
+		redelivery, resp := th.Client.RedeliverWebhookDelivery(hook.ID, failed.ID)
+		th.CheckOK(resp)
+		require.NotEqual(t, failed.ID, redelivery.ID)
+		require.Equal(t, failed.ID, redelivery.RedeliveryOf)
+		require.Equal(t, model.WebhookDeliverySuccess, redelivery.Status)
+		require.JSONEq(t, string(failed.Payload), string(redelivery.Payload))
+
+		_, resp = th.Client.RedeliverWebhookDelivery(commentsHook.ID, failed.ID)
+		th.CheckNotFound(resp)
+	})
+
+	t.Run("disabled webhooks are not delivered", func(t *testing.T) {
+		disabled := true
+		patched, resp := th.Client.PatchWebhook(hook.ID, &model.WebhookPatch{Disabled: &disabled})
+		th.CheckOK(resp)
+		require.True(t, patched.Disabled)
+		require.Empty(t, patched.Secret)
+
+		// the pending retry fails
+		_, err := th.Server.App().ProcessWebhookDeliveries(utils.GetMillis() + model.WebhookRetryBaseDelay.Milliseconds()*10)
+		require.NoError(t, err)
+		pending, resp := th.Client.GetWebhookDeliveries(hook.ID, model.WebhookDeliveryPending, 0, 10)
+		th.CheckOK(resp)
+		require.Empty(t, pending)
+
+		_, resp = th.Client.RedeliverWebhookDelivery(hook.ID, failed.ID)
+		th.CheckBadRequest(resp)
+	})
+
+	t.Run("delete a webhook", func(t *testing.T) {
+		resp := th.Client.DeleteWebhook(commentsHook.ID)
+		th.CheckOK(resp)
+
+		_, resp = th.Client.GetWebhook(commentsHook.ID)
+		th.CheckNotFound(resp)
+	})
+}
+
+func TestTeamWebhooks(t *testing.T) {
+	th := SetupTestHelperPluginMode(t)
+	defer th.TearDown()
+	clients := setupClients(th)
+	th.Client = clients.Admin
+
+	receiver := &webhookReceiver{status: http.StatusOK, response: "received"}
+	ts := httptest.NewServer(receiver)
+	defer ts.Close()
+
+	hook, resp := th.Client.CreateTeamWebhook(testTeamID, &model.Webhook{URL: ts.URL, EventTypes: []string{model.WebhookEventCardCreated}})
+	th.CheckOK(resp)
+	require.Empty(t, hook.BoardID)
+	require.NotEmpty(t, hook.Secret)
+
+	t.Run("team webhooks are managed by team admins", func(t *testing.T) {
+		webhooks, resp := th.Client.GetTeamWebhooks(testTeamID)
+		th.CheckOK(resp)
+		require.Len(t, webhooks, 1)
+
+		_, resp = clients.TeamMember.CreateTeamWebhook(testTeamID, &model.Webhook{URL: ts.URL})
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.GetTeamWebhooks(testTeamID)
+		th.CheckForbidden(resp)
+
+		_, resp = clients.TeamMember.GetWebhook(hook.ID)
+		th.CheckForbidden(resp)
+	})
+
+	t.Run("only the events of the boards the creator can view are delivered", func(t *testing.T) {
+		otherBoard, err := th.Server.App().CreateBoard(&model.Board{Title: "private", TeamID: testTeamID, Type: model.BoardTypePrivate}, userTeamMember, true)
+		require.NoError(t, err)
+		_, resp := clients.TeamMember.CreateCard(otherBoard.ID, &model.Card{Title: "hidden"}, false)
+		th.CheckOK(resp)
+
+		board := th.CreateBoard(testTeamID, model.BoardTypePrivate)
+		card, resp := th.Client.CreateCard(board.ID, &model.Card{Title: "visible"}, false)
+		th.CheckOK(resp)
+
+		var deliveries []*model.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, resp = th.Client.GetWebhookDeliveries(hook.ID, "", 0, 10)
+			th.CheckOK(resp)
+			return len(deliveries) > 0
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, board.ID, deliveries[0].BoardID)
+
+		var payload model.WebhookPayload
+		require.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+		require.Equal(t, card.ID, payload.CardID)
+
+		// system admins get the responses of the deliveries
+		_, err = th.Server.App().ProcessWebhookDeliveries(utils.GetMillis())
+		require.NoError(t, err)
+		deliveries, resp = th.Client.GetWebhookDeliveries(hook.ID, "", 0, 1)
+		th.CheckOK(resp)
+		require.Equal(t, model.WebhookDeliverySuccess, deliveries[0].Status)
+		require.Equal(t, "received", deliveries[0].ResponseBody)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/mattermost/focalboard/server/server"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	"github.com/mattermost/focalboard/server/services/store/sqlstore"
 )
 import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
@@ -65,6 +66,10 @@ func main() {
 		"",
 		"Location of the JSON config file",
 	)
+	pMigrateToDBType := flag.String("migrate-to-dbtype", model.PostgresDBType, "Database type to migrate the data to")
+	pMigrateToDBConfig := flag.String("migrate-to-dbconfig", "", "Database config to migrate the data to, the server exits once the data is copied")
+	pMigrateToTablePrefix := flag.String("migrate-to-table-prefix", "", "Table prefix of the database to migrate the data to, the one of the source by default")
+	pMigrateBatchSize := flag.Int("migrate-batch-size", sqlstore.DefaultCopyBatchSize, "Number of rows copied at once when migrating the data")
 	flag.Parse()
 
 	config, err := config.ReadConfigFile(*pConfigFilePath)
@@ -131,6 +136,28 @@ func main() {
 		config.Port = *pPort
 	}
 
+	if pMigrateToDBConfig != nil && len(*pMigrateToDBConfig) > 0 {
+		destination := *config
+		destination.DBType = *pMigrateToDBType
+		destination.DBConfigString = *pMigrateToDBConfig
+		if len(*pMigrateToTablePrefix) > 0 {
+			destination.DBTablePrefix = *pMigrateToTablePrefix
+		}
+
+		results, err := server.MigrateDatabase(config, &destination, *pMigrateBatchSize, logger)
+		for _, result := range results {
+			logger.Info("Table migrated",
+				mlog.String("table", result.Table),
+				mlog.Int("sourceRows", result.SourceRows),
+				mlog.Int("destinationRows", result.DestinationRows),
+			)
+		}
+		if err != nil {
+			logger.Fatal("server.MigrateDatabase ERROR", mlog.Err(err))
+		}
+		return
+	}
+
 	db, err := server.NewStore(config, singleUser, logger)
 	if err != nil {
 		logger.Fatal("server.NewStore ERROR", mlog.Err(err))
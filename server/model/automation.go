@@ -0,0 +1,569 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// AutomationTriggerCardCreated runs a rule when a card is created.
+	AutomationTriggerCardCreated = "card.created"
+
+	// AutomationTriggerPropertyChanged runs a rule when a property of a
+	// card changes, optionally from or to some values.
+	AutomationTriggerPropertyChanged = "card.property_changed"
+
+	// AutomationTriggerDueDatePassed runs a rule when the due date of a
+	// card that isn't done passes.
+	AutomationTriggerDueDatePassed = "card.due_date_passed"
+)
+
+const (
+	AutomationActionSetProperty      = "set_property"
+	AutomationActionClearProperty    = "clear_property"
+	AutomationActionAssignRoundRobin = "assign_round_robin"
+	AutomationActionAddComment       = "add_comment"
+)
+
+const (
+	AutomationExecutionSuccess = "success"
+	AutomationExecutionFailed  = "failed"
+	AutomationExecutionSkipped = "skipped"
+)
+
+const (
+	// MaxAutomationActions is the maximum number of actions of a rule.
+	MaxAutomationActions = 10
+
+	// MaxAutomationChainDepth is how many rules can run in a row when the
+	// actions of a rule trigger other rules.
+	MaxAutomationChainDepth = 3
+
+	// PlaceholderCardTitle is the placeholder of the title of the card
+	// the actions of a rule are applied to.
+	PlaceholderCardTitle = "card.title"
+
+	AutomationRuleNameMaxLength = 255
+)
+
+// AutomationRule applies actions to the cards of a board when an event
+// triggers it and the cards meet its conditions. The actions are applied
+// as the user that created the rule.
+// swagger:model
+type AutomationRule struct {
+	// The ID of the rule
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the board of the rule
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The name of the rule
+	// required: false
+	Name string `json:"name"`
+
+	// The event that runs the rule
+	// required: true
+	Trigger AutomationTrigger `json:"trigger"`
+
+	// The filters the cards must meet for the actions to be applied, as
+	// the filter of a view
+	// required: false
+	Conditions *FilterGroup `json:"conditions,omitempty"`
+
+	// The actions applied to the cards, in order
+	// required: true
+	Actions []AutomationAction `json:"actions"`
+
+	// True if the rule doesn't run
+	// required: false
+	Disabled bool `json:"disabled"`
+
+	// The number of times the rule ran, which round-robin assignments
+	// rotate with
+	// required: true
+	RunCount int64 `json:"runCount"`
+
+	// The ID of the user that created the rule, who the actions are
+	// applied by
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The ID of the user that last modified the rule
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// AutomationTrigger is the event that runs an automation rule.
+// swagger:model
+type AutomationTrigger struct {
+	// The type of the event: `card.created`, `card.property_changed` or
+	// `card.due_date_passed`
+	// required: true
+	Type string `json:"type"`
+
+	// The ID of the property that changes, or `title`, or the ID of the
+	// date property holding the due date
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// The values the property changes from, any value if empty. The
+	// options of select properties can be given by name
+	// required: false
+	From []string `json:"from,omitempty"`
+
+	// The values the property changes to, any value if empty. The options
+	// of select properties can be given by name
+	// required: false
+	To []string `json:"to,omitempty"`
+}
+
+// AutomationAction is an action applied to a card by an automation rule.
+// Values and texts are templates whose placeholders are resolved like the
+// ones of a card template, with {{card.title}}.
+// swagger:model
+type AutomationAction struct {
+	// The type of the action: `set_property`, `clear_property`,
+	// `assign_round_robin` or `add_comment`
+	// required: true
+	Type string `json:"type"`
+
+	// The ID of the property the action sets or clears
+	// required: false
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// The template of the value set, a text or a list of texts. The
+	// options of select properties can be given by name
+	// required: false
+	Value interface{} `json:"value,omitempty"`
+
+	// The IDs of the users assigned in turn
+	// required: false
+	UserIDs []string `json:"userIds,omitempty"`
+
+	// The template of the comment added
+	// required: false
+	Text string `json:"text,omitempty"`
+}
+
+// AutomationRulePatch is a patch to modify an automation rule.
+// swagger:model
+type AutomationRulePatch struct {
+	// The new name
+	// required: false
+	Name *string `json:"name"`
+
+	// The new trigger
+	// required: false
+	Trigger *AutomationTrigger `json:"trigger"`
+
+	// The new conditions, an empty group to remove them
+	// required: false
+	Conditions *FilterGroup `json:"conditions"`
+
+	// The new actions
+	// required: false
+	Actions *[]AutomationAction `json:"actions"`
+
+	// Disables or enables the rule
+	// required: false
+	Disabled *bool `json:"disabled"`
+}
+
+// AutomationExecution is an entry of the execution log of the automation
+// rules of a board.
+// swagger:model
+type AutomationExecution struct {
+	// The ID of the execution
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the rule
+	// required: true
+	RuleID string `json:"ruleId"`
+
+	// The ID of the board of the rule
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the card the rule ran on
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The type of the trigger of the rule
+	// required: true
+	TriggerType string `json:"triggerType"`
+
+	// The outcome of the execution: `success`, `failed` or `skipped`
+	// required: true
+	Status string `json:"status"`
+
+	// Why the execution failed or was skipped
+	// required: false
+	Error string `json:"error,omitempty"`
+
+	// The number of rules that ran before in the chain of rules
+	// triggering each other
+	// required: true
+	Depth int `json:"depth"`
+
+	// The time of the execution in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// QueryAutomationExecutionsOptions selects a page of the executions of a
+// rule, newest first.
+type QueryAutomationExecutionsOptions struct {
+	RuleID  string
+	Page    int
+	PerPage int
+}
+
+// AutomationDueDateRun is the run of a due date rule on a card, recorded
+// so that a rule runs once for each due date of a card.
+type AutomationDueDateRun struct {
+	RuleID  string `json:"ruleId"`
+	BoardID string `json:"boardId"`
+	CardID  string `json:"cardId"`
+	DueAt   int64  `json:"dueAt"`
+	RunAt   int64  `json:"runAt"`
+}
+
+// AutomationResult is the outcome of the actions of a rule on a card.
+type AutomationResult struct {
+	// Properties are all the properties of the card once changed
+	Properties map[string]interface{}
+
+	// Changed is true if the actions changed the properties
+	Changed bool
+
+	// Comments are the texts of the comments to add
+	Comments []string
+}
+
+// AutomationChain is the chain of rules that led to a change of a card:
+// each rule changed the card, which triggered the next one. A rule runs
+// once per chain, and a chain stops after MaxAutomationChainDepth rules.
+// A nil chain is the chain of the changes not made by rules.
+type AutomationChain struct {
+	// RuleIDs are the IDs of the rules that ran, in order
+	RuleIDs []string
+}
+
+// Depth returns the number of rules that ran in the chain.
+func (c *AutomationChain) Depth() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.RuleIDs)
+}
+
+// Contains returns true if the rule already ran in the chain.
+func (c *AutomationChain) Contains(ruleID string) bool {
+	if c == nil {
+		return false
+	}
+	for _, id := range c.RuleIDs {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the chain continued by a rule, without changing the chain.
+func (c *AutomationChain) Next(ruleID string) *AutomationChain {
+	ruleIDs := make([]string, 0, c.Depth()+1)
+	if c != nil {
+		ruleIDs = append(ruleIDs, c.RuleIDs...)
+	}
+	return &AutomationChain{RuleIDs: append(ruleIDs, ruleID)}
+}
+
+// Patch applies the patch to a copy of the rule.
+func (p *AutomationRulePatch) Patch(rule *AutomationRule) *AutomationRule {
+	patched := *rule
+	if p.Name != nil {
+		patched.Name = *p.Name
+	}
+	if p.Trigger != nil {
+		patched.Trigger = *p.Trigger
+	}
+	if p.Conditions != nil {
+		patched.Conditions = p.Conditions
+		if len(p.Conditions.Filters) == 0 {
+			patched.Conditions = nil
+		}
+	}
+	if p.Actions != nil {
+		patched.Actions = *p.Actions
+	}
+	if p.Disabled != nil {
+		patched.Disabled = *p.Disabled
+	}
+	return &patched
+}
+
+// IsValid returns an error if the rule is missing a field or has an
+// invalid trigger, condition or action, regardless of the board.
+func (r *AutomationRule) IsValid() error {
+	if r.BoardID == "" {
+		return NewErrBadRequest("missing board ID")
+	}
+	if utf8.RuneCountInString(r.Name) > AutomationRuleNameMaxLength {
+		return NewErrBadRequest("the name is too long")
+	}
+	if err := r.Trigger.IsValid(); err != nil {
+		return err
+	}
+	if r.Conditions != nil {
+		if err := r.Conditions.IsValid(); err != nil {
+			return NewErrBadRequest("invalid conditions: " + err.Error())
+		}
+	}
+	if len(r.Actions) == 0 || len(r.Actions) > MaxAutomationActions {
+		return NewErrBadRequest(fmt.Sprintf("between 1 and %d actions are required", MaxAutomationActions))
+	}
+	for i := range r.Actions {
+		if err := r.Actions[i].IsValid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsValidForSchema returns an error if the rule references properties
+// that aren't in the card property schema or can't be used by its
+// trigger and actions.
+func (r *AutomationRule) IsValidForSchema(schema PropSchema) error {
+	switch r.Trigger.Type {
+	case AutomationTriggerPropertyChanged:
+		if r.Trigger.PropertyID == CardQueryTitlePropertyID {
+			break
+		}
+		def, ok := schema[r.Trigger.PropertyID]
+		if !ok {
+			return NewErrBadRequest("unknown trigger property " + r.Trigger.PropertyID)
+		}
+		if computedPropTypes[def.Type] {
+			return NewErrBadRequest("the computed property " + def.Name + " can't trigger a rule")
+		}
+	case AutomationTriggerDueDatePassed:
+		if def, ok := schema[r.Trigger.PropertyID]; !ok || def.Type != "date" {
+			return NewErrBadRequest("the due date property must be a date property of the board")
+		}
+	}
+
+	if r.Conditions != nil {
+		for _, id := range r.Conditions.propertyIDs() {
+			if _, ok := schema[id]; !ok && id != CardQueryTitlePropertyID {
+				return NewErrBadRequest("unknown condition property " + id)
+			}
+		}
+	}
+
+	for _, action := range r.Actions {
+		if action.Type == AutomationActionAddComment {
+			continue
+		}
+		def, ok := schema[action.PropertyID]
+		if !ok {
+			return NewErrBadRequest("unknown action property " + action.PropertyID)
+		}
+		if computedPropTypes[def.Type] {
+			return NewErrBadRequest("the computed property " + def.Name + " can't be set")
+		}
+		if action.Type == AutomationActionAssignRoundRobin && def.Type != "person" && def.Type != "multiPerson" {
+			return NewErrBadRequest("the assignee property must be a person property of the board")
+		}
+	}
+	return nil
+}
+
+// ChangesCard returns true if some actions of the rule change the
+// properties of the cards.
+func (r *AutomationRule) ChangesCard() bool {
+	for _, action := range r.Actions {
+		if action.Type != AutomationActionAddComment {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyActions returns the outcome of the actions of the rule on a card.
+// run is the number of previous runs of the rule, which round-robin
+// assignments rotate with.
+func (r *AutomationRule) ApplyActions(card *Card, ctx PlaceholderContext, schema PropSchema, run int64) *AutomationResult {
+	properties := make(map[string]interface{}, len(card.Properties))
+	for id, value := range card.Properties {
+		properties[id] = value
+	}
+	if ctx.Variables == nil {
+		ctx.Variables = map[string]string{}
+	}
+	ctx.Variables[PlaceholderCardTitle] = card.Title
+
+	result := &AutomationResult{Properties: properties, Comments: []string{}}
+	for _, action := range r.Actions {
+		def := schema[action.PropertyID]
+		switch action.Type {
+		case AutomationActionSetProperty:
+			if value := resolvedPropertyValue(def, ctx.ResolveProperty(def, action.Value)); value != nil {
+				properties[action.PropertyID] = value
+			} else {
+				delete(properties, action.PropertyID)
+			}
+		case AutomationActionClearProperty:
+			delete(properties, action.PropertyID)
+		case AutomationActionAssignRoundRobin:
+			userID := action.UserIDs[run%int64(len(action.UserIDs))]
+			if def.Type == "multiPerson" {
+				properties[action.PropertyID] = []interface{}{userID}
+			} else {
+				properties[action.PropertyID] = userID
+			}
+		case AutomationActionAddComment:
+			if text := strings.TrimSpace(ctx.Resolve(action.Text)); text != "" {
+				result.Comments = append(result.Comments, text)
+			}
+		}
+	}
+	result.Changed = len(properties) != len(card.Properties) ||
+		(len(properties) > 0 && !reflect.DeepEqual(properties, card.Properties))
+	return result
+}
+
+// IsValid returns an error if the trigger has an unknown type or is
+// missing its property.
+func (t *AutomationTrigger) IsValid() error {
+	switch t.Type {
+	case AutomationTriggerCardCreated:
+		if t.PropertyID != "" || len(t.From) > 0 || len(t.To) > 0 {
+			return NewErrBadRequest("the card created trigger has no property")
+		}
+	case AutomationTriggerPropertyChanged:
+		if t.PropertyID == "" {
+			return NewErrBadRequest("missing trigger property ID")
+		}
+	case AutomationTriggerDueDatePassed:
+		if t.PropertyID == "" {
+			return NewErrBadRequest("missing due date property ID")
+		}
+		if len(t.From) > 0 || len(t.To) > 0 {
+			return NewErrBadRequest("the due date trigger has no values")
+		}
+	default:
+		return NewErrBadRequest("invalid trigger type " + t.Type)
+	}
+	return nil
+}
+
+// MatchesChange returns true if a change of a card fires a property
+// changed trigger: the property changed, from one of the From values if
+// any and to one of the To values if any.
+func (t *AutomationTrigger) MatchesChange(oldCard, newCard *Card, schema PropSchema) bool {
+	if t.Type != AutomationTriggerPropertyChanged {
+		return false
+	}
+	oldValue := cardPropertyValue(oldCard, t.PropertyID, schema)
+	newValue := cardPropertyValue(newCard, t.PropertyID, schema)
+	if reflect.DeepEqual(oldValue, newValue) {
+		return false
+	}
+
+	def := schema[t.PropertyID]
+	if len(t.From) > 0 {
+		from := def.optionIDs(t.From)
+		if !valueIncludesAny(oldValue, from) || valueIncludesAny(newValue, from) {
+			return false
+		}
+	}
+	if len(t.To) > 0 {
+		to := def.optionIDs(t.To)
+		if !valueIncludesAny(newValue, to) || valueIncludesAny(oldValue, to) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValid returns an error if the action has an unknown type or is
+// missing a field.
+func (a *AutomationAction) IsValid() error {
+	switch a.Type {
+	case AutomationActionSetProperty:
+		if a.PropertyID == "" {
+			return NewErrBadRequest("missing action property ID")
+		}
+		if _, ok := a.Value.(string); ok {
+			return nil
+		}
+		if _, ok := stringList(a.Value); !ok {
+			return NewErrBadRequest("the value of property " + a.PropertyID + " must be a text or a list of texts")
+		}
+	case AutomationActionClearProperty:
+		if a.PropertyID == "" {
+			return NewErrBadRequest("missing action property ID")
+		}
+	case AutomationActionAssignRoundRobin:
+		if a.PropertyID == "" {
+			return NewErrBadRequest("missing assignee property ID")
+		}
+		if len(a.UserIDs) == 0 {
+			return NewErrBadRequest("the round-robin assignment needs users")
+		}
+		for _, userID := range a.UserIDs {
+			if userID == "" {
+				return NewErrBadRequest("invalid user ID in the round-robin assignment")
+			}
+		}
+	case AutomationActionAddComment:
+		if strings.TrimSpace(a.Text) == "" {
+			return NewErrBadRequest("missing comment text")
+		}
+	default:
+		return NewErrBadRequest("invalid action type " + a.Type)
+	}
+	return nil
+}
+
+// optionIDs returns the IDs of the options of a select property with IDs
+// or names, and the other values as they are.
+func (pd PropDef) optionIDs(values []string) []string {
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		ids = append(ids, pd.optionID(value))
+	}
+	return ids
+}
+
+// propertyIDs returns the IDs of the properties the filters of the group
+// and its descendants apply to.
+func (fg *FilterGroup) propertyIDs() []string {
+	ids := []string{}
+	for _, item := range fg.Filters {
+		if item.Group != nil {
+			ids = append(ids, item.Group.propertyIDs()...)
+		}
+		if item.Clause != nil {
+			ids = append(ids, item.Clause.PropertyID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testAutomationSchema() PropSchema {
+	return PropSchema{
+		"status": PropDef{ID: "status", Name: "Status", Type: "select", Options: map[string]PropDefOption{
+			"todo": {ID: "todo", Value: "To Do"},
+			"done": {ID: "done", Value: "Done", Done: true},
+		}},
+		"completed": PropDef{ID: "completed", Name: "Completed", Type: "date"},
+		"assignee":  PropDef{ID: "assignee", Name: "Assignee", Type: "person"},
+		"reviewers": PropDef{ID: "reviewers", Name: "Reviewers", Type: "multiPerson"},
+		"notes":     PropDef{ID: "notes", Name: "Notes", Type: "text"},
+		"created":   PropDef{ID: "created", Name: "Created", Type: "createdTime"},
+	}
+}
+
+func TestAutomationRuleIsValid(t *testing.T) {
+	valid := func() *AutomationRule {
+		return &AutomationRule{
+			BoardID: "board-1",
+			Trigger: AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"Done"}},
+			Actions: []AutomationAction{
+				{Type: AutomationActionSetProperty, PropertyID: "completed", Value: "{{today}}"},
+				{Type: AutomationActionClearProperty, PropertyID: "assignee"},
+			},
+		}
+	}
+	require.NoError(t, valid().IsValid())
+
+	testCases := []struct {
+		name   string
+		modify func(r *AutomationRule)
+	}{
+		{"missing board", func(r *AutomationRule) { r.BoardID = "" }},
+		{"name too long", func(r *AutomationRule) { r.Name = strings.Repeat("a", AutomationRuleNameMaxLength+1) }},
+		{"invalid trigger type", func(r *AutomationRule) { r.Trigger.Type = "card.deleted" }},
+		{"property trigger without property", func(r *AutomationRule) { r.Trigger.PropertyID = "" }},
+		{"card created trigger with values", func(r *AutomationRule) { r.Trigger.Type = AutomationTriggerCardCreated }},
+		{"due date trigger with values", func(r *AutomationRule) { r.Trigger.Type = AutomationTriggerDueDatePassed }},
+		{"no actions", func(r *AutomationRule) { r.Actions = nil }},
+		{"too many actions", func(r *AutomationRule) {
+			for len(r.Actions) <= MaxAutomationActions {
+				r.Actions = append(r.Actions, r.Actions[0])
+			}
+		}},
+		{"invalid action type", func(r *AutomationRule) { r.Actions[0].Type = "delete_card" }},
+		{"invalid value", func(r *AutomationRule) { r.Actions[0].Value = 3 }},
+		{"round-robin without users", func(r *AutomationRule) { r.Actions[1].Type = AutomationActionAssignRoundRobin }},
+		{"comment without text", func(r *AutomationRule) { r.Actions[1] = AutomationAction{Type: AutomationActionAddComment, Text: " "} }},
+		{"invalid conditions", func(r *AutomationRule) { r.Conditions = &FilterGroup{Operation: "xor"} }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := valid()
+			tc.modify(rule)
+			require.True(t, IsErrBadRequest(rule.IsValid()))
+		})
+	}
+}
+
+func TestAutomationRuleIsValidForSchema(t *testing.T) {
+	schema := testAutomationSchema()
+
+	testCases := []struct {
+		name    string
+		trigger AutomationTrigger
+		action  AutomationAction
+		valid   bool
+	}{
+		{"status trigger", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status"}, AutomationAction{Type: AutomationActionClearProperty, PropertyID: "assignee"}, true},
+		{"title trigger", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: CardQueryTitlePropertyID}, AutomationAction{Type: AutomationActionAddComment, Text: "renamed"}, true},
+		{"unknown trigger property", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "missing"}, AutomationAction{Type: AutomationActionClearProperty, PropertyID: "assignee"}, false},
+		{"computed trigger property", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "created"}, AutomationAction{Type: AutomationActionClearProperty, PropertyID: "assignee"}, false},
+		{"due date trigger", AutomationTrigger{Type: AutomationTriggerDueDatePassed, PropertyID: "completed"}, AutomationAction{Type: AutomationActionSetProperty, PropertyID: "status", Value: "todo"}, true},
+		{"due date trigger on a text", AutomationTrigger{Type: AutomationTriggerDueDatePassed, PropertyID: "notes"}, AutomationAction{Type: AutomationActionSetProperty, PropertyID: "status", Value: "todo"}, false},
+		{"unknown action property", AutomationTrigger{Type: AutomationTriggerCardCreated}, AutomationAction{Type: AutomationActionSetProperty, PropertyID: "missing", Value: "x"}, false},
+		{"computed action property", AutomationTrigger{Type: AutomationTriggerCardCreated}, AutomationAction{Type: AutomationActionClearProperty, PropertyID: "created"}, false},
+		{"round-robin on a person", AutomationTrigger{Type: AutomationTriggerCardCreated}, AutomationAction{Type: AutomationActionAssignRoundRobin, PropertyID: "reviewers", UserIDs: []string{"user-1"}}, true},
+		{"round-robin on a text", AutomationTrigger{Type: AutomationTriggerCardCreated}, AutomationAction{Type: AutomationActionAssignRoundRobin, PropertyID: "notes", UserIDs: []string{"user-1"}}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := &AutomationRule{Trigger: tc.trigger, Actions: []AutomationAction{tc.action}}
+			err := rule.IsValidForSchema(schema)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.True(t, IsErrBadRequest(err))
+			}
+		})
+	}
+
+	t.Run("unknown condition property", func(t *testing.T) {
+		rule := &AutomationRule{
+			Trigger: AutomationTrigger{Type: AutomationTriggerCardCreated},
+			Conditions: &FilterGroup{Operation: FilterGroupOperationAnd, Filters: []FilterGroupItem{
+				{Group: &FilterGroup{Operation: FilterGroupOperationOr, Filters: []FilterGroupItem{
+					{Clause: &FilterClause{PropertyID: "missing", Condition: FilterConditionIsSet}},
+				}}},
+			}},
+			Actions: []AutomationAction{{Type: AutomationActionClearProperty, PropertyID: "assignee"}},
+		}
+		require.True(t, IsErrBadRequest(rule.IsValidForSchema(schema)))
+	})
+}
+
+func TestAutomationTriggerMatchesChange(t *testing.T) {
+	schema := testAutomationSchema()
+	card := func(status interface{}) *Card {
+		properties := map[string]interface{}{"notes": "n"}
+		if status != nil {
+			properties["status"] = status
+		}
+		return &Card{Title: "Card", Properties: properties}
+	}
+
+	testCases := []struct {
+		name     string
+		trigger  AutomationTrigger
+		old, new *Card
+		matches  bool
+	}{
+		{"any change", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status"}, card("todo"), card("done"), true},
+		{"set", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status"}, card(nil), card("todo"), true},
+		{"unchanged", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status"}, card("todo"), card("todo"), false},
+		{"to an option name", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"done"}}, card("todo"), card("done"), true},
+		{"to another option", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"Done"}}, card("done"), card("todo"), false},
+		{"from an option", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status", From: []string{"To Do"}}, card("todo"), card(nil), true},
+		{"from another option", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "status", From: []string{"To Do"}}, card(nil), card("done"), false},
+		{"other property", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: "assignee"}, card("todo"), card("done"), false},
+		{"title", AutomationTrigger{Type: AutomationTriggerPropertyChanged, PropertyID: CardQueryTitlePropertyID}, card("todo"), &Card{Title: "Renamed", Properties: card("todo").Properties}, true},
+		{"not a property trigger", AutomationTrigger{Type: AutomationTriggerCardCreated}, card("todo"), card("done"), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.matches, tc.trigger.MatchesChange(tc.old, tc.new, schema))
+		})
+	}
+}
+
+func TestAutomationRuleApplyActions(t *testing.T) {
+	schema := testAutomationSchema()
+	ctx := PlaceholderContext{
+		Now:           time.Date(2026, time.March, 10, 8, 0, 0, 0, time.UTC),
+		CreatorID:     "user-1",
+		CreatorName:   "alice",
+		RemoveUnknown: true,
+	}
+	card := &Card{
+		Title:      "Fix login",
+		Properties: map[string]interface{}{"status": "done", "assignee": "user-2"},
+	}
+
+	t.Run("set and clear properties", func(t *testing.T) {
+		rule := &AutomationRule{Actions: []AutomationAction{
+			{Type: AutomationActionSetProperty, PropertyID: "completed", Value: "{{today}}"},
+			{Type: AutomationActionClearProperty, PropertyID: "assignee"},
+			{Type: AutomationActionSetProperty, PropertyID: "notes", Value: "{{card.title}} by {{creator}}"},
+			{Type: AutomationActionAddComment, Text: "Completed {{card.title}}"},
+			{Type: AutomationActionAddComment, Text: "{{unknown}}"},
+		}}
+		result := rule.ApplyActions(card, ctx, schema, 0)
+		require.True(t, result.Changed)
+		require.Equal(t, map[string]interface{}{
+			"status":    "done",
+			"completed": DatePropertyValue(ctx.Now),
+			"notes":     "Fix login by alice",
+		}, result.Properties)
+		require.Equal(t, []string{"Completed Fix login"}, result.Comments)
+
+		// the card isn't modified
+		require.Equal(t, "user-2", card.Properties["assignee"])
+	})
+
+	t.Run("select options by name", func(t *testing.T) {
+		rule := &AutomationRule{Actions: []AutomationAction{
+			{Type: AutomationActionSetProperty, PropertyID: "status", Value: "To Do"},
+		}}
+		result := rule.ApplyActions(card, ctx, schema, 0)
+		require.True(t, result.Changed)
+		require.Equal(t, "todo", result.Properties["status"])
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		rule := &AutomationRule{Actions: []AutomationAction{
+			{Type: AutomationActionSetProperty, PropertyID: "status", Value: "Done"},
+			{Type: AutomationActionClearProperty, PropertyID: "notes"},
+		}}
+		result := rule.ApplyActions(card, ctx, schema, 0)
+		require.False(t, result.Changed)
+		require.Empty(t, result.Comments)
+
+		clear := &AutomationRule{Actions: []AutomationAction{{Type: AutomationActionClearProperty, PropertyID: "notes"}}}
+		empty := &Card{Properties: map[string]interface{}{}}
+		require.False(t, clear.ApplyActions(empty, ctx, schema, 0).Changed)
+	})
+
+	t.Run("round-robin", func(t *testing.T) {
+		rule := &AutomationRule{Actions: []AutomationAction{
+			{Type: AutomationActionAssignRoundRobin, PropertyID: "assignee", UserIDs: []string{"user-1", "user-2", "user-3"}},
+			{Type: AutomationActionAssignRoundRobin, PropertyID: "reviewers", UserIDs: []string{"user-4", "user-5"}},
+		}}
+		assigned := []interface{}{}
+		reviewers := []interface{}{}
+		for run := int64(0); run < 4; run++ {
+			result := rule.ApplyActions(card, ctx, schema, run)
+			assigned = append(assigned, result.Properties["assignee"])
+			reviewers = append(reviewers, result.Properties["reviewers"])
+		}
+		require.Equal(t, []interface{}{"user-1", "user-2", "user-3", "user-1"}, assigned)
+		require.Equal(t, []interface{}{
+			[]interface{}{"user-4"}, []interface{}{"user-5"}, []interface{}{"user-4"}, []interface{}{"user-5"},
+		}, reviewers)
+	})
+}
+
+func TestAutomationRulePatch(t *testing.T) {
+	rule := &AutomationRule{
+		ID:      "rule-1",
+		BoardID: "board-1",
+		Name:    "Complete",
+		Trigger: AutomationTrigger{Type: AutomationTriggerCardCreated},
+		Conditions: &FilterGroup{Operation: FilterGroupOperationAnd, Filters: []FilterGroupItem{
+			{Clause: &FilterClause{PropertyID: "status", Condition: FilterConditionIsSet}},
+		}},
+		Actions: []AutomationAction{{Type: AutomationActionClearProperty, PropertyID: "assignee"}},
+	}
+
+	name := "Renamed"
+	disabled := true
+	patched := (&AutomationRulePatch{Name: &name, Disabled: &disabled, Conditions: &FilterGroup{}}).Patch(rule)
+	require.Equal(t, "Renamed", patched.Name)
+	require.True(t, patched.Disabled)
+	require.Nil(t, patched.Conditions)
+	require.Equal(t, rule.Actions, patched.Actions)
+
+	// the rule isn't modified
+	require.Equal(t, "Complete", rule.Name)
+	require.NotNil(t, rule.Conditions)
+}
+
+func TestAutomationChain(t *testing.T) {
+	var chain *AutomationChain
+	require.Zero(t, chain.Depth())
+	require.False(t, chain.Contains("rule-1"))
+
+	first := chain.Next("rule-1")
+	second := first.Next("rule-2")
+	third := first.Next("rule-3")
+	require.Equal(t, 2, second.Depth())
+	require.True(t, second.Contains("rule-1"))
+	require.True(t, second.Contains("rule-2"))
+	require.False(t, second.Contains("rule-3"))
+
+	// continuing a chain doesn't change it
+	require.Equal(t, []string{"rule-1"}, first.RuleIDs)
+	require.Equal(t, []string{"rule-1", "rule-3"}, third.RuleIDs)
+}
@@ -108,6 +108,11 @@ type BlockPatch struct {
 	// The block removed fields
 	// required: false
 	DeletedFields []string `json:"deletedFields"`
+
+	// If set, the patch is only applied if the block has not been
+	// updated since this time
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt"`
 }
 
 // BlockPatchBatch is a batch of IDs and patches for modify blocks
@@ -139,13 +144,22 @@ func BlocksFromJSON(data io.Reader) []*Block {
 }
 
 // IsValid checks the block for errors before inserting, and makes
-// sure it complies with the requirements of a valid block.
+// sure it complies with the requirements of a valid block and of the
+// definition of its registered type.
 func (b *Block) IsValid() error {
 	if b.BoardID == "" {
 		return ErrBlockEmptyBoardID
 	}
 
-	if utf8.RuneCountInString(b.Title) > BlockTitleMaxRunes {
+	// blocks without a type predate the registry and are only checked
+	// for their size
+	def, ok := GetBlockTypeDefinition(b.Type)
+	if !ok && b.Type != "" {
+		return ErrInvalidBlockType{string(b.Type)}
+	}
+
+	titleRunes := utf8.RuneCountInString(b.Title)
+	if titleRunes > BlockTitleMaxRunes {
 		return ErrBlockTitleSizeLimitExceeded
 	}
 
@@ -154,11 +168,15 @@ func (b *Block) IsValid() error {
 		return err
 	}
 
-	if utf8.RuneCountInString(string(fieldsJSON)) > BlockFieldsMaxRunes {
+	fieldsRunes := utf8.RuneCountInString(string(fieldsJSON))
+	if fieldsRunes > BlockFieldsMaxRunes {
 		return ErrBlockFieldsSizeLimitExceeded
 	}
 
-	return nil
+	if def == nil {
+		return nil
+	}
+	return def.validateBlock(b, titleRunes, fieldsRunes)
 }
 
 // LogClone implements the `mlog.LogCloner` interface to provide a subset of Block fields for logging.
@@ -176,6 +194,12 @@ func (b *Block) LogClone() interface{} {
 	}
 }
 
+// MatchesVersion returns false if the patch expects a version of the
+// block other than the given one.
+func (p *BlockPatch) MatchesVersion(block *Block) bool {
+	return p.ExpectedUpdateAt == nil || *p.ExpectedUpdateAt == block.UpdateAt
+}
+
 // Patch returns an update version of the block.
 func (p *BlockPatch) Patch(block *Block) *Block {
 	if p.ParentID != nil {
@@ -194,6 +218,9 @@ func (p *BlockPatch) Patch(block *Block) *Block {
 		block.Title = *p.Title
 	}
 
+	if block.Fields == nil && len(p.UpdatedFields) > 0 {
+		block.Fields = make(map[string]interface{}, len(p.UpdatedFields))
+	}
 	for key, field := range p.UpdatedFields {
 		block.Fields[key] = field
 	}
@@ -0,0 +1,268 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+)
+
+// Rich content block types, created in cards along the text blocks.
+const (
+	TypeHeading      = "heading"
+	TypeCode         = "code"
+	TypeBulletList   = "bulletList"
+	TypeNumberedList = "numberedList"
+	TypeTable        = "table"
+	TypeEmbed        = "embed"
+)
+
+// The fields of the rich content blocks. The text of headings and code
+// blocks is their title.
+const (
+	HeadingFieldLevel     = "level"
+	CodeFieldLanguage     = "language"
+	ListFieldItems        = "items"
+	ListFieldStart        = "start"
+	TableFieldColumns     = "columns"
+	TableFieldRows        = "rows"
+	EmbedFieldURL         = "url"
+	EmbedFieldType        = "embedType"
+	HeadingMinLevel       = 1
+	HeadingMaxLevel       = 6
+	CodeLanguageMaxLength = 32
+	ListMaxItems          = 1000
+	TableMaxColumns       = 50
+	TableMaxRows          = 1000
+	EmbedURLMaxLength     = 2048
+)
+
+// The kinds of content an embed block can show.
+const (
+	EmbedTypeLink  = "link"
+	EmbedTypeImage = "image"
+	EmbedTypeVideo = "video"
+	EmbedTypeFrame = "frame"
+)
+
+var codeLanguageRegexp = regexp.MustCompile(`^[A-Za-z0-9_+#.-]*$`)
+
+func init() {
+	contentParents := []BlockType{TypeCard}
+	listFields := map[string]FieldType{ListFieldItems: FieldTypeArray, ListFieldStart: FieldTypeNumber}
+
+	for _, def := range []BlockTypeDefinition{
+		{
+			Type:        TypeHeading,
+			ParentTypes: contentParents,
+			Fields:      map[string]FieldType{HeadingFieldLevel: FieldTypeNumber},
+			Validate:    validateHeadingBlock,
+		},
+		{
+			Type:        TypeCode,
+			ParentTypes: contentParents,
+			Fields:      map[string]FieldType{CodeFieldLanguage: FieldTypeString},
+			Validate:    validateCodeBlock,
+		},
+		{Type: TypeBulletList, ParentTypes: contentParents, Fields: listFields, Validate: validateListBlock},
+		{Type: TypeNumberedList, ParentTypes: contentParents, Fields: listFields, Validate: validateListBlock},
+		{
+			Type:        TypeTable,
+			ParentTypes: contentParents,
+			Fields:      map[string]FieldType{TableFieldColumns: FieldTypeArray, TableFieldRows: FieldTypeArray},
+			Validate:    validateTableBlock,
+		},
+		{
+			Type:        TypeEmbed,
+			ParentTypes: contentParents,
+			Fields:      map[string]FieldType{EmbedFieldURL: FieldTypeString, EmbedFieldType: FieldTypeString},
+			Validate:    validateEmbedBlock,
+		},
+	} {
+		MustRegisterBlockType(def)
+	}
+}
+
+// HeadingLevel returns the level of a heading block, 1 if it isn't set.
+func HeadingLevel(block *Block) int {
+	level, ok := blockFieldInt(block, HeadingFieldLevel)
+	if !ok {
+		return HeadingMinLevel
+	}
+	return level
+}
+
+// CodeLanguage returns the language of a code block, empty if it isn't
+// set.
+func CodeLanguage(block *Block) string {
+	language, _ := block.Fields[CodeFieldLanguage].(string)
+	return language
+}
+
+// ListItems returns the items of a bullet or numbered list block.
+func ListItems(block *Block) []string {
+	items, _ := stringList(block.Fields[ListFieldItems])
+	return items
+}
+
+// ListStart returns the number of the first item of a numbered list
+// block, 1 if it isn't set.
+func ListStart(block *Block) int {
+	start, ok := blockFieldInt(block, ListFieldStart)
+	if !ok {
+		return 1
+	}
+	return start
+}
+
+// TableContent returns the column headers and the rows of a table block.
+func TableContent(block *Block) ([]string, [][]string) {
+	columns, _ := stringList(block.Fields[TableFieldColumns])
+	rows, _ := tableRows(block.Fields[TableFieldRows])
+	return columns, rows
+}
+
+// EmbedURL returns the URL of an embed block.
+func EmbedURL(block *Block) string {
+	u, _ := block.Fields[EmbedFieldURL].(string)
+	return u
+}
+
+// EmbedType returns the kind of content of an embed block, EmbedTypeLink
+// if it isn't set.
+func EmbedType(block *Block) string {
+	embedType, _ := block.Fields[EmbedFieldType].(string)
+	if embedType == "" {
+		return EmbedTypeLink
+	}
+	return embedType
+}
+
+func validateHeadingBlock(block *Block) error {
+	if block.Fields[HeadingFieldLevel] == nil {
+		return nil
+	}
+	level, ok := blockFieldInt(block, HeadingFieldLevel)
+	if !ok || level < HeadingMinLevel || level > HeadingMaxLevel {
+		return invalidBlockField(block, HeadingFieldLevel, fmt.Sprintf("must be an integer between %d and %d", HeadingMinLevel, HeadingMaxLevel))
+	}
+	return nil
+}
+
+func validateCodeBlock(block *Block) error {
+	language := CodeLanguage(block)
+	if len(language) > CodeLanguageMaxLength || !codeLanguageRegexp.MatchString(language) {
+		return invalidBlockField(block, CodeFieldLanguage, "must be a language name")
+	}
+	return nil
+}
+
+func validateListBlock(block *Block) error {
+	if value := block.Fields[ListFieldItems]; value != nil {
+		items, ok := stringList(value)
+		if !ok {
+			return invalidBlockField(block, ListFieldItems, "must be a list of texts")
+		}
+		if len(items) > ListMaxItems {
+			return invalidBlockField(block, ListFieldItems, fmt.Sprintf("can't have more than %d items", ListMaxItems))
+		}
+	}
+
+	if block.Fields[ListFieldStart] == nil {
+		return nil
+	}
+	if block.Type != TypeNumberedList {
+		return invalidBlockField(block, ListFieldStart, "is only allowed in numbered lists")
+	}
+	if start, ok := blockFieldInt(block, ListFieldStart); !ok || start < 0 {
+		return invalidBlockField(block, ListFieldStart, "must be an integer of at least 0")
+	}
+	return nil
+}
+
+func validateTableBlock(block *Block) error {
+	columns, ok := stringList(block.Fields[TableFieldColumns])
+	if !ok || len(columns) == 0 {
+		return invalidBlockField(block, TableFieldColumns, "must be a list of column headers")
+	}
+	if len(columns) > TableMaxColumns {
+		return invalidBlockField(block, TableFieldColumns, fmt.Sprintf("can't have more than %d columns", TableMaxColumns))
+	}
+
+	value := block.Fields[TableFieldRows]
+	if value == nil {
+		return nil
+	}
+	rows, ok := tableRows(value)
+	if !ok {
+		return invalidBlockField(block, TableFieldRows, "must be a list of rows of texts")
+	}
+	if len(rows) > TableMaxRows {
+		return invalidBlockField(block, TableFieldRows, fmt.Sprintf("can't have more than %d rows", TableMaxRows))
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return invalidBlockField(block, TableFieldRows, fmt.Sprintf("row %d must have %d cells", i+1, len(columns)))
+		}
+	}
+	return nil
+}
+
+func validateEmbedBlock(block *Block) error {
+	rawURL := EmbedURL(block)
+	if rawURL == "" {
+		return invalidBlockField(block, EmbedFieldURL, "is required")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || len(rawURL) > EmbedURLMaxLength || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalidBlockField(block, EmbedFieldURL, "must be an http or https URL")
+	}
+
+	switch EmbedType(block) {
+	case EmbedTypeLink, EmbedTypeImage, EmbedTypeVideo, EmbedTypeFrame:
+		return nil
+	}
+	return invalidBlockField(block, EmbedFieldType, "must be one of link, image, video or frame")
+}
+
+func invalidBlockField(block *Block, field, reason string) error {
+	return ErrInvalidBlockField{Type: block.Type, Field: field, Reason: reason}
+}
+
+// blockFieldInt returns the value of a field of a block that holds an
+// integer, decoded from JSON as a float.
+func blockFieldInt(block *Block, field string) (int, bool) {
+	switch value := block.Fields[field].(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case float64:
+		if value != math.Trunc(value) || math.IsInf(value, 0) {
+			return 0, false
+		}
+		return int(value), true
+	}
+	return 0, false
+}
+
+func tableRows(v interface{}) ([][]string, bool) {
+	switch value := v.(type) {
+	case [][]string:
+		return value, true
+	case []interface{}:
+		rows := make([][]string, 0, len(value))
+		for _, item := range value {
+			row, ok := stringList(item)
+			if !ok {
+				return nil, false
+			}
+			rows = append(rows, row)
+		}
+		return rows, true
+	}
+	return nil, false
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichContentBlocks(t *testing.T) {
+	newBlock := func(blockType BlockType, title string, fields map[string]interface{}) *Block {
+		return &Block{ID: "block-id", BoardID: "board-id", ParentID: "card-id", Type: blockType, Title: title, Fields: fields}
+	}
+
+	testCases := []struct {
+		name  string
+		block *Block
+		field string
+	}{
+		{"heading", newBlock(TypeHeading, "Title", map[string]interface{}{"level": 2.0}), ""},
+		{"heading without level", newBlock(TypeHeading, "Title", nil), ""},
+		{"heading with a level too deep", newBlock(TypeHeading, "Title", map[string]interface{}{"level": 7.0}), HeadingFieldLevel},
+		{"heading with a fractional level", newBlock(TypeHeading, "Title", map[string]interface{}{"level": 1.5}), HeadingFieldLevel},
+		{"code", newBlock(TypeCode, "fmt.Println()", map[string]interface{}{"language": "go"}), ""},
+		{"code without language", newBlock(TypeCode, "echo", nil), ""},
+		{"code with an invalid language", newBlock(TypeCode, "echo", map[string]interface{}{"language": "<script>"}), CodeFieldLanguage},
+		{"bullet list", newBlock(TypeBulletList, "", map[string]interface{}{"items": []interface{}{"one", "two"}}), ""},
+		{"bullet list with a start", newBlock(TypeBulletList, "", map[string]interface{}{"items": []interface{}{"one"}, "start": 3.0}), ListFieldStart},
+		{"bullet list with invalid items", newBlock(TypeBulletList, "", map[string]interface{}{"items": []interface{}{"one", 2.0}}), ListFieldItems},
+		{"numbered list", newBlock(TypeNumberedList, "", map[string]interface{}{"items": []string{"one"}, "start": 3}), ""},
+		{"numbered list with a negative start", newBlock(TypeNumberedList, "", map[string]interface{}{"start": -1.0}), ListFieldStart},
+		{"table", newBlock(TypeTable, "", map[string]interface{}{
+			"columns": []interface{}{"Name", "Role"},
+			"rows":    []interface{}{[]interface{}{"Alice", "Dev"}, []interface{}{"Bob", "PM"}},
+		}), ""},
+		{"table without columns", newBlock(TypeTable, "", map[string]interface{}{"rows": []interface{}{}}), TableFieldColumns},
+		{"table with a short row", newBlock(TypeTable, "", map[string]interface{}{
+			"columns": []interface{}{"Name", "Role"},
+			"rows":    []interface{}{[]interface{}{"Alice"}},
+		}), TableFieldRows},
+		{"embed", newBlock(TypeEmbed, "Demo", map[string]interface{}{"url": "https://example.com/demo", "embedType": "video"}), ""},
+		{"embed without type", newBlock(TypeEmbed, "", map[string]interface{}{"url": "http://example.com"}), ""},
+		{"embed without url", newBlock(TypeEmbed, "", map[string]interface{}{"embedType": "link"}), EmbedFieldURL},
+		{"embed with a script url", newBlock(TypeEmbed, "", map[string]interface{}{"url": "javascript:alert(1)"}), EmbedFieldURL},
+		{"embed with an unknown type", newBlock(TypeEmbed, "", map[string]interface{}{"url": "https://example.com", "embedType": "applet"}), EmbedFieldType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.block.IsValid()
+			if tc.field == "" {
+				require.NoError(t, err)
+				return
+			}
+			var fieldErr ErrInvalidBlockField
+			require.ErrorAs(t, err, &fieldErr)
+			require.Equal(t, tc.field, fieldErr.Field)
+			require.True(t, IsErrBadRequest(err))
+		})
+	}
+
+	t.Run("accessors", func(t *testing.T) {
+		require.Equal(t, 1, HeadingLevel(newBlock(TypeHeading, "", nil)))
+		require.Equal(t, 3, HeadingLevel(newBlock(TypeHeading, "", map[string]interface{}{"level": 3.0})))
+		require.Equal(t, 1, ListStart(newBlock(TypeNumberedList, "", nil)))
+		require.Equal(t, []string{"a", "b"}, ListItems(newBlock(TypeBulletList, "", map[string]interface{}{"items": []interface{}{"a", "b"}})))
+		require.Equal(t, EmbedTypeLink, EmbedType(newBlock(TypeEmbed, "", nil)))
+
+		columns, rows := TableContent(newBlock(TypeTable, "", map[string]interface{}{
+			"columns": []interface{}{"A"},
+			"rows":    []interface{}{[]interface{}{"1"}},
+		}))
+		require.Equal(t, []string{"A"}, columns)
+		require.Equal(t, [][]string{{"1"}}, rows)
+	})
+
+	t.Run("rich content belongs to cards", func(t *testing.T) {
+		code := newBlock(TypeCode, "", nil)
+		require.NoError(t, ValidateBlockParents([]*Block{code}, map[string]BlockType{"card-id": TypeCard}))
+		require.Error(t, ValidateBlockParents([]*Block{code}, map[string]BlockType{"card-id": TypeView}))
+
+		// the types are matched case insensitively
+		blockType, err := BlockTypeFromString("numberedlist")
+		require.NoError(t, err)
+		require.Equal(t, BlockType(TypeNumberedList), blockType)
+	})
+}
@@ -307,3 +307,30 @@ func TestStampModificationMetadata(t *testing.T) {
 		assert.NotEmpty(t, blocks[0].UpdateAt)
 	})
 }
+
+func TestBlockPatchMatchesVersion(t *testing.T) {
+	block := &Block{UpdateAt: 100}
+
+	t.Run("no precondition", func(t *testing.T) {
+		assert.True(t, (&BlockPatch{}).MatchesVersion(block))
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		updateAt := int64(100)
+		assert.True(t, (&BlockPatch{ExpectedUpdateAt: &updateAt}).MatchesVersion(block))
+	})
+
+	t.Run("outdated version", func(t *testing.T) {
+		updateAt := int64(99)
+		assert.False(t, (&BlockPatch{ExpectedUpdateAt: &updateAt}).MatchesVersion(block))
+	})
+}
+
+func TestBlockPatchPatch(t *testing.T) {
+	t.Run("block without fields", func(t *testing.T) {
+		block := &Block{ID: "block_id"}
+		patch := &BlockPatch{UpdatedFields: map[string]interface{}{"properties": map[string]interface{}{}}}
+		patched := patch.Patch(block)
+		assert.Equal(t, map[string]interface{}{"properties": map[string]interface{}{}}, patched.Fields)
+	})
+}
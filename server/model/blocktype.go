@@ -5,7 +5,6 @@ package model
 
 import (
 	"errors"
-	"strings"
 
 	"github.com/mattermost/focalboard/server/utils"
 )
@@ -24,52 +23,78 @@ const (
 	TypeImage      = "image"
 	TypeAttachment = "attachment"
 	TypeDivider    = "divider"
+	TypeH1         = "h1"
+	TypeH2         = "h2"
+	TypeH3         = "h3"
+	TypeListItem   = "list-item"
+	TypeQuote      = "quote"
+	TypeVideo      = "video"
 )
 
+func init() {
+	contentParents := []BlockType{TypeCard}
+	fileFields := map[string]FieldType{"fileId": FieldTypeString, "attachmentId": FieldTypeString, "filename": FieldTypeString}
+
+	for _, def := range []BlockTypeDefinition{
+		// legacy board blocks, before boards had their own table
+		{Type: TypeBoard, IDType: utils.IDTypeBoard},
+		{
+			Type:        TypeCard,
+			IDType:      utils.IDTypeCard,
+			ParentTypes: []BlockType{TypeBoard},
+			Fields: map[string]FieldType{
+				"icon":         FieldTypeString,
+				"isTemplate":   FieldTypeBool,
+				"properties":   FieldTypeObject,
+				"contentOrder": FieldTypeArray,
+			},
+		},
+		{
+			Type:        TypeView,
+			IDType:      utils.IDTypeView,
+			ParentTypes: []BlockType{TypeBoard},
+			Fields:      map[string]FieldType{"viewType": FieldTypeString},
+		},
+		{Type: TypeText, ParentTypes: contentParents},
+		{Type: TypeCheckbox, ParentTypes: contentParents, Fields: map[string]FieldType{"value": FieldTypeBool}},
+		{Type: TypeComment, ParentTypes: contentParents},
+		{Type: TypeDivider, ParentTypes: contentParents},
+		{Type: TypeImage, IDType: utils.IDTypeAttachment, ParentTypes: contentParents, Fields: fileFields, HasFile: true},
+		{Type: TypeAttachment, IDType: utils.IDTypeAttachment, ParentTypes: contentParents, Fields: fileFields, HasFile: true},
+		// videos can be links instead of uploaded files
+		{Type: TypeVideo, ParentTypes: contentParents, Fields: fileFields},
+		{Type: TypeH1, ParentTypes: contentParents},
+		{Type: TypeH2, ParentTypes: contentParents},
+		{Type: TypeH3, ParentTypes: contentParents},
+		{Type: TypeListItem, ParentTypes: contentParents},
+		{Type: TypeQuote, ParentTypes: contentParents},
+		// blocks created by clients before their type is known
+		{Type: TypeUnknown, IDType: utils.IDTypeNone},
+	} {
+		MustRegisterBlockType(def)
+	}
+}
+
 func (bt BlockType) String() string {
 	return string(bt)
 }
 
-// BlockTypeFromString returns an appropriate BlockType for the specified string.
+// BlockTypeFromString returns the registered BlockType for the specified string.
 func BlockTypeFromString(s string) (BlockType, error) {
-	switch strings.ToLower(s) {
-	case "board":
-		return TypeBoard, nil
-	case "card":
-		return TypeCard, nil
-	case "view":
-		return TypeView, nil
-	case "text":
-		return TypeText, nil
-	case "checkbox":
-		return TypeCheckbox, nil
-	case "comment":
-		return TypeComment, nil
-	case "image":
-		return TypeImage, nil
-	case "attachment":
-		return TypeAttachment, nil
-	case "divider":
-		return TypeDivider, nil
+	def, ok := GetBlockTypeDefinition(BlockType(s))
+	if !ok || def.Type == TypeUnknown {
+		return TypeUnknown, ErrInvalidBlockType{s}
 	}
-	return TypeUnknown, ErrInvalidBlockType{s}
+	return def.Type, nil
 }
 
-// BlockType2IDType returns an appropriate IDType for the specified BlockType.
+// BlockType2IDType returns the IDType registered for the specified BlockType.
 func BlockType2IDType(blockType BlockType) utils.IDType {
-	switch blockType {
-	case TypeBoard:
-		return utils.IDTypeBoard
-	case TypeCard:
-		return utils.IDTypeCard
-	case TypeView:
-		return utils.IDTypeView
-	case TypeText, TypeCheckbox, TypeComment, TypeDivider:
-		return utils.IDTypeBlock
-	case TypeImage, TypeAttachment:
-		return utils.IDTypeAttachment
+	def, ok := GetBlockTypeDefinition(blockType)
+	if !ok {
+		return utils.IDTypeNone
 	}
-	return utils.IDTypeNone
+	return def.IDType
 }
 
 // ErrInvalidBlockType is returned wherever an invalid block type was provided.
@@ -83,6 +108,6 @@ func (e ErrInvalidBlockType) Error() string {
 
 // IsErrInvalidBlockType returns true if `err` is a IsErrInvalidBlockType or wraps one.
 func IsErrInvalidBlockType(err error) bool {
-	var eibt *ErrInvalidBlockType
+	var eibt ErrInvalidBlockType
 	return errors.As(err, &eibt)
 }
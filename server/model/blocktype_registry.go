@@ -0,0 +1,247 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// FieldType is the type of the JSON value of a field of a block.
+type FieldType string
+
+const (
+	FieldTypeString FieldType = "string"
+	FieldTypeBool   FieldType = "bool"
+	FieldTypeNumber FieldType = "number"
+	FieldTypeArray  FieldType = "array"
+	FieldTypeObject FieldType = "object"
+)
+
+// BlockTypeDefinition describes a block type: where its blocks can be
+// created, what their fields are and how their IDs are generated. Block
+// types are registered with RegisterBlockType.
+type BlockTypeDefinition struct {
+	// Type is the block type, matched case insensitively
+	Type BlockType
+
+	// IDType is the prefix of the IDs generated for the blocks of the
+	// type, IDTypeBlock if not set
+	IDType utils.IDType
+
+	// ParentTypes are the types of the blocks that the blocks of the type
+	// can be children of, besides the board they belong to. Any parent is
+	// allowed if empty
+	ParentTypes []BlockType
+
+	// Fields are the types of the known fields of the blocks. Other fields
+	// aren't checked, and null values are always allowed
+	Fields map[string]FieldType
+
+	// MaxTitleRunes and MaxFieldsRunes lower the size limits of the title
+	// and of the fields of the blocks when set
+	MaxTitleRunes  int
+	MaxFieldsRunes int
+
+	// HasFile is true for the blocks that hold the ID of a file in their
+	// fileId field, which is copied with them and exported with them
+	HasFile bool
+
+	// Validate checks the blocks of the type further if set
+	Validate func(block *Block) error
+}
+
+// ErrInvalidBlockField is returned when a field of a block doesn't match
+// the definition of its type.
+type ErrInvalidBlockField struct {
+	Type   BlockType
+	Field  string
+	Reason string
+}
+
+func (e ErrInvalidBlockField) Error() string {
+	return fmt.Sprintf("invalid field %s of %s block: %s", e.Field, e.Type, e.Reason)
+}
+
+// ErrInvalidBlockParent is returned when a block is the child of a block
+// whose type its type doesn't allow.
+type ErrInvalidBlockParent struct {
+	Type       BlockType
+	ParentType BlockType
+}
+
+func (e ErrInvalidBlockParent) Error() string {
+	return fmt.Sprintf("a %s block can't be the child of a %s block", e.Type, e.ParentType)
+}
+
+type blockTypeRegistry struct {
+	mux   sync.RWMutex
+	types map[string]*BlockTypeDefinition
+}
+
+var blockTypes = &blockTypeRegistry{types: map[string]*BlockTypeDefinition{}}
+
+// RegisterBlockType adds a block type to the registry. It fails if the
+// type is already registered.
+func RegisterBlockType(def BlockTypeDefinition) error {
+	if def.Type == "" {
+		return fmt.Errorf("cannot register a block type without a name: %w", ErrInvalidBlockType{""})
+	}
+	if def.IDType == 0 {
+		def.IDType = utils.IDTypeBlock
+	}
+
+	key := strings.ToLower(string(def.Type))
+	blockTypes.mux.Lock()
+	defer blockTypes.mux.Unlock()
+	if _, ok := blockTypes.types[key]; ok {
+		return fmt.Errorf("block type %s is already registered", def.Type)
+	}
+	blockTypes.types[key] = &def
+	return nil
+}
+
+// MustRegisterBlockType registers a block type and panics if it fails,
+// for the block types registered when the program starts.
+func MustRegisterBlockType(def BlockTypeDefinition) {
+	if err := RegisterBlockType(def); err != nil {
+		panic(err)
+	}
+}
+
+// GetBlockTypeDefinition returns the definition of a registered block type.
+func GetBlockTypeDefinition(blockType BlockType) (*BlockTypeDefinition, bool) {
+	blockTypes.mux.RLock()
+	defer blockTypes.mux.RUnlock()
+	def, ok := blockTypes.types[strings.ToLower(string(blockType))]
+	return def, ok
+}
+
+// RegisteredBlockTypes returns the registered block types, sorted.
+func RegisteredBlockTypes() []BlockType {
+	blockTypes.mux.RLock()
+	defer blockTypes.mux.RUnlock()
+	result := make([]BlockType, 0, len(blockTypes.types))
+	for _, def := range blockTypes.types {
+		result = append(result, def.Type)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// IsFileBlock returns true if the block holds the ID of a file.
+func IsFileBlock(block *Block) bool {
+	def, ok := GetBlockTypeDefinition(block.Type)
+	return ok && def.HasFile
+}
+
+// AllowsParent returns true if the blocks of the type can be children of
+// blocks of a parent type.
+func (d *BlockTypeDefinition) AllowsParent(parentType BlockType) bool {
+	if len(d.ParentTypes) == 0 {
+		return true
+	}
+	for _, t := range d.ParentTypes {
+		if strings.EqualFold(string(t), string(parentType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBlock checks a block against the definition of its type.
+func (d *BlockTypeDefinition) validateBlock(block *Block, titleRunes, fieldsRunes int) error {
+	if d.MaxTitleRunes > 0 && titleRunes > d.MaxTitleRunes {
+		return ErrBlockTitleSizeLimitExceeded
+	}
+	if d.MaxFieldsRunes > 0 && fieldsRunes > d.MaxFieldsRunes {
+		return ErrBlockFieldsSizeLimitExceeded
+	}
+
+	for name, fieldType := range d.Fields {
+		value, ok := block.Fields[name]
+		if !ok || value == nil {
+			continue
+		}
+		if !fieldType.matches(value) {
+			return ErrInvalidBlockField{Type: d.Type, Field: name, Reason: "must be of type " + string(fieldType)}
+		}
+	}
+
+	if d.Validate != nil {
+		return d.Validate(block)
+	}
+	return nil
+}
+
+// matches returns true if a value is of the field type. Fields set from
+// patches can hold pointers, which are dereferenced.
+func (t FieldType) matches(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return true
+		}
+		value = value.Elem()
+	}
+
+	switch t {
+	case FieldTypeString:
+		return value.Kind() == reflect.String
+	case FieldTypeBool:
+		return value.Kind() == reflect.Bool
+	case FieldTypeNumber:
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	case FieldTypeArray:
+		return value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+	case FieldTypeObject:
+		return value.Kind() == reflect.Map || value.Kind() == reflect.Struct
+	}
+	return true
+}
+
+// ValidateBlockParents checks that the blocks are children of blocks of
+// types allowed by their types. parentTypes are the types of the parents
+// that aren't part of blocks, by ID; the blocks whose parent type isn't
+// known aren't checked. The blocks at the root of their board are always
+// allowed, as clients can create them before the blocks they belong to.
+func ValidateBlockParents(blocks []*Block, parentTypes map[string]BlockType) error {
+	types := make(map[string]BlockType, len(parentTypes)+len(blocks))
+	for id, t := range parentTypes {
+		types[id] = t
+	}
+	for _, block := range blocks {
+		types[block.ID] = block.Type
+	}
+
+	for _, block := range blocks {
+		def, ok := GetBlockTypeDefinition(block.Type)
+		if !ok {
+			return ErrInvalidBlockType{string(block.Type)}
+		}
+		if block.ParentID == "" || block.ParentID == block.BoardID {
+			continue
+		}
+
+		parentType, ok := types[block.ParentID]
+		if !ok {
+			continue
+		}
+		if !def.AllowsParent(parentType) {
+			return ErrInvalidBlockParent{Type: block.Type, ParentType: parentType}
+		}
+	}
+	return nil
+}
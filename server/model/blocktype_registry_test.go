@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTypeRegistry(t *testing.T) {
+	errNoLanguage := errors.New("missing language")
+	testType := BlockType("registry-test")
+	require.NoError(t, RegisterBlockType(BlockTypeDefinition{
+		Type:          testType,
+		ParentTypes:   []BlockType{TypeCard},
+		Fields:        map[string]FieldType{"language": FieldTypeString, "lines": FieldTypeNumber},
+		MaxTitleRunes: 10,
+		Validate: func(block *Block) error {
+			if _, ok := block.Fields["language"]; !ok {
+				return errNoLanguage
+			}
+			return nil
+		},
+	}))
+
+	t.Run("registration", func(t *testing.T) {
+		require.Error(t, RegisterBlockType(BlockTypeDefinition{Type: "REGISTRY-TEST"}))
+		require.Error(t, RegisterBlockType(BlockTypeDefinition{}))
+
+		def, ok := GetBlockTypeDefinition("Registry-Test")
+		require.True(t, ok)
+		require.Equal(t, utils.IDTypeBlock, def.IDType)
+		require.Contains(t, RegisteredBlockTypes(), testType)
+
+		blockType, err := BlockTypeFromString("REGISTRY-TEST")
+		require.NoError(t, err)
+		require.Equal(t, testType, blockType)
+
+		_, err = BlockTypeFromString("not-registered")
+		require.True(t, IsErrInvalidBlockType(err))
+		_, err = BlockTypeFromString(TypeUnknown)
+		require.True(t, IsErrInvalidBlockType(err))
+	})
+
+	t.Run("core types", func(t *testing.T) {
+		require.Equal(t, utils.IDTypeCard, BlockType2IDType(TypeCard))
+		require.Equal(t, utils.IDTypeAttachment, BlockType2IDType(TypeImage))
+		require.Equal(t, utils.IDTypeBlock, BlockType2IDType(TypeH1))
+		require.Equal(t, utils.IDTypeNone, BlockType2IDType("not-registered"))
+
+		require.True(t, IsFileBlock(&Block{Type: TypeAttachment}))
+		require.False(t, IsFileBlock(&Block{Type: TypeText}))
+	})
+
+	t.Run("block validation", func(t *testing.T) {
+		block := &Block{BoardID: "board", Type: testType, Title: "title", Fields: map[string]interface{}{"language": "go", "lines": 3.0}}
+		require.NoError(t, block.IsValid())
+
+		block.Title = "a title that is too long"
+		require.ErrorIs(t, block.IsValid(), ErrBlockTitleSizeLimitExceeded)
+
+		block = &Block{BoardID: "board", Type: testType, Fields: map[string]interface{}{"language": "go", "lines": "3"}}
+		var fieldErr ErrInvalidBlockField
+		require.ErrorAs(t, block.IsValid(), &fieldErr)
+		require.Equal(t, "lines", fieldErr.Field)
+		require.True(t, IsErrBadRequest(block.IsValid()))
+
+		block = &Block{BoardID: "board", Type: testType, Fields: map[string]interface{}{"language": nil}}
+		require.NoError(t, block.IsValid())
+
+		block = &Block{BoardID: "board", Type: testType}
+		require.ErrorIs(t, block.IsValid(), errNoLanguage)
+
+		language := "go"
+		block = &Block{BoardID: "board", Type: testType, Fields: map[string]interface{}{"language": &language}}
+		require.NoError(t, block.IsValid())
+
+		block = &Block{BoardID: "board", Type: TypeCheckbox, Fields: map[string]interface{}{"value": "yes"}}
+		require.True(t, IsErrBadRequest(block.IsValid()))
+
+		block = &Block{BoardID: "board", Type: "not-registered"}
+		require.True(t, IsErrInvalidBlockType(block.IsValid()))
+	})
+
+	t.Run("parents", func(t *testing.T) {
+		card := &Block{ID: "card", BoardID: "board", ParentID: "board", Type: TypeCard}
+		text := &Block{ID: "text", BoardID: "board", ParentID: "card", Type: TypeText}
+		require.NoError(t, ValidateBlockParents([]*Block{card, text}, nil))
+
+		// the parents out of the blocks are looked up
+		require.NoError(t, ValidateBlockParents([]*Block{text}, map[string]BlockType{"card": TypeCard}))
+		err := ValidateBlockParents([]*Block{text}, map[string]BlockType{"card": TypeView})
+		var parentErr ErrInvalidBlockParent
+		require.ErrorAs(t, err, &parentErr)
+		require.Equal(t, BlockType(TypeView), parentErr.ParentType)
+		require.True(t, IsErrBadRequest(err))
+
+		// unknown parents and the root of the board aren't checked
+		require.NoError(t, ValidateBlockParents([]*Block{text}, nil))
+		comment := &Block{ID: "comment", BoardID: "board", ParentID: "board", Type: TypeComment}
+		require.NoError(t, ValidateBlockParents([]*Block{comment}, nil))
+
+		nested := &Block{ID: "nested", BoardID: "board", ParentID: "card", Type: TypeCard}
+		require.Error(t, ValidateBlockParents([]*Block{card, nested}, nil))
+
+		unregistered := &Block{ID: "other", BoardID: "board", Type: "not-registered"}
+		require.True(t, IsErrInvalidBlockType(ValidateBlockParents([]*Block{unregistered}, nil)))
+	})
+}
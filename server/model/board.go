@@ -166,6 +166,11 @@ type BoardPatch struct {
 	// The board removed card properties
 	// required: false
 	DeletedCardProperties []string `json:"deletedCardProperties"`
+
+	// If set, the patch is only applied if the board has not been
+	// updated since this time
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt"`
 }
 
 // BoardMember stores the information of the membership of a user on a board
@@ -262,6 +267,12 @@ func BoardMetadataFromJSON(data io.Reader) *BoardMetadata {
 	return boardMetadata
 }
 
+// MatchesVersion returns false if the patch expects a version of the
+// board other than the given one.
+func (p *BoardPatch) MatchesVersion(board *Board) bool {
+	return p.ExpectedUpdateAt == nil || *p.ExpectedUpdateAt == board.UpdateAt
+}
+
 // Patch returns an updated version of the board.
 func (p *BoardPatch) Patch(board *Board) *Board {
 	if p.Type != nil {
@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BoardRestoreAction is the change a point-in-time restore applies to a block.
+type BoardRestoreAction string
+
+const (
+	// BoardRestoreActionUpdate reverts a block to its content at the restore time.
+	BoardRestoreActionUpdate BoardRestoreAction = "update"
+
+	// BoardRestoreActionRestore brings back a block deleted after the restore time.
+	BoardRestoreActionRestore BoardRestoreAction = "restore"
+
+	// BoardRestoreActionDelete deletes a block created after the restore time.
+	BoardRestoreActionDelete BoardRestoreAction = "delete"
+)
+
+// BoardRestoreOptions are the options of a point-in-time restore of a board.
+// swagger:model
+type BoardRestoreOptions struct {
+	// The time to restore the board to, in milliseconds since the epoch
+	// required: true
+	RestoreAt int64 `json:"restoreAt"`
+
+	// If true, the changes are computed and returned but not applied
+	// required: false
+	DryRun bool `json:"dryRun"`
+}
+
+// IsValid validates the restore options.
+func (o *BoardRestoreOptions) IsValid() error {
+	if o.RestoreAt <= 0 {
+		return NewErrBadRequest(fmt.Sprintf("invalid restore time %d", o.RestoreAt))
+	}
+	if o.RestoreAt > GetMillis() {
+		return NewErrBadRequest("the restore time cannot be in the future")
+	}
+	return nil
+}
+
+// BoardRestoreBlockChange is a block changed by a point-in-time restore.
+// swagger:model
+type BoardRestoreBlockChange struct {
+	// The ID of the block
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The ID of the parent of the block
+	// required: true
+	ParentID string `json:"parentId"`
+
+	// The type of the block
+	// required: true
+	Type BlockType `json:"type"`
+
+	// The title of the block at the restore time, or its current title
+	// if it is deleted by the restore
+	// required: true
+	Title string `json:"title"`
+
+	// The change applied to the block: update, restore or delete
+	// required: true
+	Action BoardRestoreAction `json:"action"`
+}
+
+// BoardRestoreResult describes the changes of a point-in-time restore
+// of a board, either applied or previewed.
+// swagger:model
+type BoardRestoreResult struct {
+	// The ID of the restored board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The time the board was restored to
+	// required: true
+	RestoreAt int64 `json:"restoreAt"`
+
+	// True if the changes were not applied
+	// required: true
+	DryRun bool `json:"dryRun"`
+
+	// True if the board's own fields and properties are reverted
+	// required: true
+	BoardChanged bool `json:"boardChanged"`
+
+	// The board as it is after the restore
+	// required: true
+	Board *Board `json:"board"`
+
+	// The blocks updated, restored or deleted by the restore
+	// required: true
+	Changes []*BoardRestoreBlockChange `json:"changes"`
+}
+
+// RestoreBoardContent copies to the board the content of a revision of
+// it. The access related fields, like the type, the minimum role and the
+// linked channel, are left as they are. It returns true if the board
+// has changed.
+func RestoreBoardContent(board *Board, revision *Board) bool {
+	changed := board.Title != revision.Title ||
+		board.Description != revision.Description ||
+		board.Icon != revision.Icon ||
+		board.ShowDescription != revision.ShowDescription ||
+		!reflect.DeepEqual(board.Properties, revision.Properties) ||
+		!reflect.DeepEqual(board.CardProperties, revision.CardProperties)
+
+	board.Title = revision.Title
+	board.Description = revision.Description
+	board.Icon = revision.Icon
+	board.ShowDescription = revision.ShowDescription
+	board.Properties = revision.Properties
+	board.CardProperties = revision.CardProperties
+	return changed
+}
+
+// BlockContentEquals returns true if both blocks have the same content,
+// regardless of their metadata.
+func BlockContentEquals(a *Block, b *Block) bool {
+	return a.ParentID == b.ParentID &&
+		a.Schema == b.Schema &&
+		a.Type == b.Type &&
+		a.Title == b.Title &&
+		reflect.DeepEqual(a.Fields, b.Fields)
+}
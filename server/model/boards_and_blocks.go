@@ -134,6 +134,21 @@ func (dbab *PatchBoardsAndBlocks) IsValid() error {
 	return nil
 }
 
+// BlockPatchBatch returns the block patches as a batch. Missing patches
+// are empty.
+func (dbab *PatchBoardsAndBlocks) BlockPatchBatch() *BlockPatchBatch {
+	batch := &BlockPatchBatch{
+		BlockIDs:     dbab.BlockIDs,
+		BlockPatches: make([]BlockPatch, len(dbab.BlockPatches)),
+	}
+	for i, patch := range dbab.BlockPatches {
+		if patch != nil {
+			batch.BlockPatches[i] = *patch
+		}
+	}
+	return batch
+}
+
 func GenerateBoardsAndBlocksIDs(bab *BoardsAndBlocks, logger mlog.LoggerIFace) (*BoardsAndBlocks, error) {
 	if err := bab.IsValid(); err != nil {
 		return nil, err
@@ -77,6 +77,14 @@ type Card struct {
 	// required: false
 	Properties map[string]any `json:"properties"`
 
+	// True if one of the cards that block this card isn't done. Computed by the server
+	// required: false
+	Blocked bool `json:"blocked"`
+
+	// The progress of the checklist made of the checkbox blocks of this card. Computed by the server
+	// required: false
+	Checklist ChecklistProgress `json:"checklist"`
+
 	// The creation time in milliseconds since the current epoch
 	// required: false
 	CreateAt int64 `json:"createAt"`
@@ -163,6 +171,11 @@ type CardPatch struct {
 	// A map of property ids to property option ids to be updated
 	// required: false
 	UpdatedProperties map[string]any `json:"updatedProperties"`
+
+	// If set, the patch is only applied if the card has not been
+	// updated since this time
+	// required: false
+	ExpectedUpdateAt *int64 `json:"expectedUpdateAt"`
 }
 
 // Patch returns an updated version of the card.
@@ -318,7 +331,8 @@ func CardPatch2BlockPatch(cardPatch *CardPatch) (*BlockPatch, error) {
 	}
 
 	blockPatch := &BlockPatch{
-		Title: cardPatch.Title,
+		Title:            cardPatch.Title,
+		ExpectedUpdateAt: cardPatch.ExpectedUpdateAt,
 	}
 
 	updatedFields := make(map[string]any, 0)
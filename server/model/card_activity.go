@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+// CardActivityAction is the kind of change recorded by an entry of the
+// activity timeline of a card.
+type CardActivityAction string
+
+const (
+	CardActivityActionCreated  CardActivityAction = "created"
+	CardActivityActionUpdated  CardActivityAction = "updated"
+	CardActivityActionDeleted  CardActivityAction = "deleted"
+	CardActivityActionRestored CardActivityAction = "restored"
+)
+
+// CardActivity is an entry of the activity timeline of a card, describing
+// a change of the card or of one of its content blocks or comments.
+// swagger:model
+type CardActivity struct {
+	// The ID of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The ID of the changed block, either the card itself or one of its
+	// content blocks or comments
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The type of the changed block
+	// required: true
+	BlockType BlockType `json:"blockType"`
+
+	// The change: created, updated, deleted or restored
+	// required: true
+	Action CardActivityAction `json:"action"`
+
+	// The ID of the user that made the change
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The time of the change, in milliseconds since the epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The title of the block before the change, for updates of the title
+	// or the text of the block
+	// required: false
+	OldTitle string `json:"oldTitle,omitempty"`
+
+	// The title of the block after the change. For content blocks and
+	// comments this is their text
+	// required: false
+	NewTitle string `json:"newTitle,omitempty"`
+
+	// The properties of the card changed, with their values before and
+	// after the change
+	// required: false
+	PropDiffs []PropDiff `json:"propDiffs,omitempty"`
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"sort"
+)
+
+const (
+	// CardFieldChecklist is the field of the exported card blocks that
+	// holds the progress of their checklists.
+	CardFieldChecklist = "checklist"
+
+	ChecklistFilterNone       = "none"
+	ChecklistFilterIncomplete = "incomplete"
+	ChecklistFilterComplete   = "complete"
+
+	ChecklistSortAscending  = "asc"
+	ChecklistSortDescending = "desc"
+)
+
+// ChecklistProgress is the number of checked checkbox blocks of a card
+// out of all its checkbox blocks.
+// swagger:model
+type ChecklistProgress struct {
+	// The number of checked checkboxes
+	// required: true
+	Done int `json:"done"`
+
+	// The number of checkboxes
+	// required: true
+	Total int `json:"total"`
+}
+
+// IsComplete returns true if the checklist has checkboxes and they are
+// all checked.
+func (p ChecklistProgress) IsComplete() bool {
+	return p.Total > 0 && p.Done == p.Total
+}
+
+// Ratio returns the checked fraction of the checklist, 0 if it's empty.
+func (p ChecklistProgress) Ratio() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total)
+}
+
+// IsCheckboxChecked returns true if a checkbox block is checked.
+func IsCheckboxChecked(block *Block) bool {
+	checked, _ := block.Fields["value"].(bool)
+	return checked
+}
+
+// ComputeChecklistProgress returns the progress of the checklists of the
+// cards that are parents of checkbox blocks, by card ID. Blocks that
+// aren't checkboxes are ignored.
+func ComputeChecklistProgress(blocks []*Block) map[string]ChecklistProgress {
+	progress := map[string]ChecklistProgress{}
+	for _, block := range blocks {
+		if block.Type != TypeCheckbox || block.DeleteAt != 0 {
+			continue
+		}
+		p := progress[block.ParentID]
+		p.Total++
+		if IsCheckboxChecked(block) {
+			p.Done++
+		}
+		progress[block.ParentID] = p
+	}
+	return progress
+}
+
+// BlockWithChecklist returns a copy of a card block with the progress of
+// its checklist in its fields. Other blocks are returned as they are.
+func BlockWithChecklist(block *Block, progress ChecklistProgress) *Block {
+	if block == nil || block.Type != TypeCard {
+		return block
+	}
+
+	b := *block
+	b.Fields = make(map[string]interface{}, len(block.Fields)+1)
+	for k, v := range block.Fields {
+		b.Fields[k] = v
+	}
+	b.Fields[CardFieldChecklist] = map[string]interface{}{
+		"done":  progress.Done,
+		"total": progress.Total,
+	}
+	return &b
+}
+
+// ChecklistQuery filters and sorts cards by the progress of their
+// checklists.
+type ChecklistQuery struct {
+	// Filter is one of the ChecklistFilter constants, or empty to keep
+	// all the cards
+	Filter string
+
+	// Sort is one of the ChecklistSort constants, or empty to keep the
+	// order of the cards
+	Sort string
+}
+
+// IsEmpty returns true if the query neither filters nor sorts cards.
+func (q ChecklistQuery) IsEmpty() bool {
+	return q.Filter == "" && q.Sort == ""
+}
+
+func (q ChecklistQuery) IsValid() error {
+	switch q.Filter {
+	case "", ChecklistFilterNone, ChecklistFilterIncomplete, ChecklistFilterComplete:
+	default:
+		return NewErrBadRequest("invalid checklist filter: " + q.Filter)
+	}
+	switch q.Sort {
+	case "", ChecklistSortAscending, ChecklistSortDescending:
+	default:
+		return NewErrBadRequest("invalid checklist sort: " + q.Sort)
+	}
+	return nil
+}
+
+// IsMet returns true if a checklist matches the filter of the query.
+// Cards without checkboxes only match the "none" filter.
+func (q ChecklistQuery) IsMet(p ChecklistProgress) bool {
+	switch q.Filter {
+	case ChecklistFilterNone:
+		return p.Total == 0
+	case ChecklistFilterIncomplete:
+		return p.Total > 0 && !p.IsComplete()
+	case ChecklistFilterComplete:
+		return p.IsComplete()
+	}
+	return true
+}
+
+// Apply filters the cards, whose checklist progress must be set, and
+// sorts them by the checked fraction of their checklists. Cards without
+// checkboxes are sorted last in both directions.
+func (q ChecklistQuery) Apply(cards []*Card) []*Card {
+	filtered := make([]*Card, 0, len(cards))
+	for _, card := range cards {
+		if q.IsMet(card.Checklist) {
+			filtered = append(filtered, card)
+		}
+	}
+
+	if q.Sort == "" {
+		return filtered
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i].Checklist, filtered[j].Checklist
+		if a.Total == 0 || b.Total == 0 {
+			return a.Total != 0 && b.Total == 0
+		}
+		if q.Sort == ChecklistSortDescending {
+			return a.Ratio() > b.Ratio()
+		}
+		return a.Ratio() < b.Ratio()
+	})
+	return filtered
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeChecklistProgress(t *testing.T) {
+	checkbox := func(cardID string, checked interface{}) *Block {
+		return &Block{Type: TypeCheckbox, ParentID: cardID, Fields: map[string]interface{}{"value": checked}}
+	}
+	blocks := []*Block{
+		checkbox("card-1", true),
+		checkbox("card-1", false),
+		checkbox("card-1", "true"),
+		checkbox("card-2", true),
+		{Type: TypeText, ParentID: "card-3"},
+	}
+
+	progress := ComputeChecklistProgress(blocks)
+	require.Equal(t, map[string]ChecklistProgress{
+		"card-1": {Done: 1, Total: 3},
+		"card-2": {Done: 1, Total: 1},
+	}, progress)
+	require.False(t, progress["card-1"].IsComplete())
+	require.True(t, progress["card-2"].IsComplete())
+	require.False(t, progress["card-3"].IsComplete())
+}
+
+func TestBlockWithChecklist(t *testing.T) {
+	card := &Block{Type: TypeCard, Fields: map[string]interface{}{"icon": "x"}}
+	withChecklist := BlockWithChecklist(card, ChecklistProgress{Done: 1, Total: 2})
+	require.Equal(t, map[string]interface{}{"done": 1, "total": 2}, withChecklist.Fields[CardFieldChecklist])
+	require.Equal(t, "x", withChecklist.Fields["icon"])
+	require.NotContains(t, card.Fields, CardFieldChecklist)
+
+	text := &Block{Type: TypeText}
+	require.Same(t, text, BlockWithChecklist(text, ChecklistProgress{}))
+}
+
+func TestChecklistQuery(t *testing.T) {
+	cards := []*Card{
+		{ID: "empty"},
+		{ID: "half", Checklist: ChecklistProgress{Done: 1, Total: 2}},
+		{ID: "done", Checklist: ChecklistProgress{Done: 3, Total: 3}},
+		{ID: "todo", Checklist: ChecklistProgress{Done: 0, Total: 4}},
+	}
+	ids := func(cards []*Card) []string {
+		result := []string{}
+		for _, card := range cards {
+			result = append(result, card.ID)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name     string
+		query    ChecklistQuery
+		expected []string
+	}{
+		{"no query", ChecklistQuery{}, []string{"empty", "half", "done", "todo"}},
+		{"none", ChecklistQuery{Filter: ChecklistFilterNone}, []string{"empty"}},
+		{"incomplete", ChecklistQuery{Filter: ChecklistFilterIncomplete}, []string{"half", "todo"}},
+		{"complete", ChecklistQuery{Filter: ChecklistFilterComplete}, []string{"done"}},
+		{"ascending", ChecklistQuery{Sort: ChecklistSortAscending}, []string{"todo", "half", "done", "empty"}},
+		{"descending", ChecklistQuery{Sort: ChecklistSortDescending}, []string{"done", "half", "todo", "empty"}},
+		{"filtered and sorted", ChecklistQuery{Filter: ChecklistFilterIncomplete, Sort: ChecklistSortDescending}, []string{"half", "todo"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.query.IsValid())
+			require.Equal(t, tc.expected, ids(tc.query.Apply(cards)))
+		})
+	}
+
+	require.True(t, IsErrBadRequest(ChecklistQuery{Filter: "all"}.IsValid()))
+	require.True(t, IsErrBadRequest(ChecklistQuery{Sort: "up"}.IsValid()))
+}
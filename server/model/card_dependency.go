@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+)
+
+var ErrCardDependencyCycle = errors.New("the dependency would create a cycle")
+
+// CardDependency is a "blocks / blocked by" relationship between two
+// cards, of the same board or of two boards of the same team.
+// swagger:model
+type CardDependency struct {
+	// The ID of the card that blocks the other one
+	// required: true
+	BlockerID string `json:"blockerId"`
+
+	// The board ID of the blocker card
+	// required: true
+	BlockerBoardID string `json:"blockerBoardId"`
+
+	// The ID of the blocked card
+	// required: true
+	BlockedID string `json:"blockedId"`
+
+	// The board ID of the blocked card
+	// required: true
+	BlockedBoardID string `json:"blockedBoardId"`
+
+	// The ID of the user that created the dependency
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// CardDependencyRequest is the request to add a blocker to a card.
+// swagger:model
+type CardDependencyRequest struct {
+	// The ID of the card that blocks the card
+	// required: true
+	BlockerID string `json:"blockerId"`
+}
+
+// CardDependencies are the dependencies of a card.
+// swagger:model
+type CardDependencies struct {
+	// The dependencies on the cards that block the card
+	// required: true
+	BlockedBy []*CardDependency `json:"blockedBy"`
+
+	// The dependencies of the cards blocked by the card
+	// required: true
+	Blocks []*CardDependency `json:"blocks"`
+}
+
+// QueryCardDependenciesOptions selects the dependencies whose blocker is
+// one of BlockerIDs or whose blocked card is one of BlockedIDs.
+type QueryCardDependenciesOptions struct {
+	BlockerIDs []string
+	BlockedIDs []string
+}
+
+// IsValid returns an error if the dependency is missing a card or links a
+// card to itself.
+func (d *CardDependency) IsValid() error {
+	if d.BlockerID == "" || d.BlockerBoardID == "" {
+		return NewErrBadRequest("missing blocker card")
+	}
+	if d.BlockedID == "" || d.BlockedBoardID == "" {
+		return NewErrBadRequest("missing blocked card")
+	}
+	if d.BlockerID == d.BlockedID {
+		return NewErrBadRequest("a card cannot block itself")
+	}
+	return nil
+}
+
+// IsCardDone returns true if the card is in a done state, meaning one of
+// its select or multi select values is an option marked as done in the
+// card properties of its board.
+func (s PropSchema) IsCardDone(card *Block) bool {
+	if card == nil {
+		return false
+	}
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	for id, value := range properties {
+		def, ok := s[id]
+		if !ok {
+			continue
+		}
+		var optionIDs []string
+		switch def.Type {
+		case "select":
+			if optionID, ok := value.(string); ok {
+				optionIDs = []string{optionID}
+			}
+		case "multiSelect":
+			optionIDs, _ = stringList(value)
+		}
+		for _, optionID := range optionIDs {
+			if def.Options[optionID].Done {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindDependencyCycle returns true if a card blocked by blockerID would
+// end up blocking itself. getBlockers returns the IDs of the cards that
+// block a set of cards.
+func FindDependencyCycle(blockerID, blockedID string, getBlockers func(cardIDs []string) ([]string, error)) (bool, error) {
+	if blockerID == blockedID {
+		return true, nil
+	}
+	visited := map[string]bool{blockerID: true}
+	frontier := []string{blockerID}
+	for len(frontier) > 0 {
+		blockers, err := getBlockers(frontier)
+		if err != nil {
+			return false, err
+		}
+		frontier = frontier[:0]
+		for _, id := range blockers {
+			if id == blockedID {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCardDone(t *testing.T) {
+	board := &Board{
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_todo", "value": "To Do"},
+				map[string]interface{}{"id": "status_done", "value": "Done", "done": true},
+			}},
+			{"id": "labels", "name": "Labels", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "label_a", "value": "A"},
+				map[string]interface{}{"id": "label_shipped", "value": "Shipped", "done": true},
+			}},
+			{"id": "notes", "name": "Notes", "type": "text"},
+		},
+	}
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	card := func(properties map[string]interface{}) *Block {
+		return &Block{Type: TypeCard, Fields: map[string]interface{}{"properties": properties}}
+	}
+
+	assert.False(t, schema.IsCardDone(nil))
+	assert.False(t, schema.IsCardDone(card(nil)))
+	assert.False(t, schema.IsCardDone(card(map[string]interface{}{"status": "status_todo"})))
+	assert.True(t, schema.IsCardDone(card(map[string]interface{}{"status": "status_done"})))
+	assert.False(t, schema.IsCardDone(card(map[string]interface{}{"labels": []interface{}{"label_a"}})))
+	assert.True(t, schema.IsCardDone(card(map[string]interface{}{"labels": []interface{}{"label_a", "label_shipped"}})))
+	assert.False(t, schema.IsCardDone(card(map[string]interface{}{"notes": "status_done"})))
+}
+
+func TestCardDependencyIsValid(t *testing.T) {
+	valid := &CardDependency{BlockerID: "card-1", BlockerBoardID: "board-1", BlockedID: "card-2", BlockedBoardID: "board-2"}
+	require.NoError(t, valid.IsValid())
+
+	for _, dependency := range []*CardDependency{
+		{BlockerBoardID: "board-1", BlockedID: "card-2", BlockedBoardID: "board-2"},
+		{BlockerID: "card-1", BlockerBoardID: "board-1", BlockedID: "card-2"},
+		{BlockerID: "card-1", BlockerBoardID: "board-1", BlockedID: "card-1", BlockedBoardID: "board-1"},
+	} {
+		assert.True(t, IsErrBadRequest(dependency.IsValid()))
+	}
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	// blocked card -> its blockers
+	blockers := map[string][]string{
+		"c": {"b"},
+		"b": {"a"},
+		"d": {"a", "c"},
+	}
+	getBlockers := func(cardIDs []string) ([]string, error) {
+		ids := []string{}
+		for _, id := range cardIDs {
+			ids = append(ids, blockers[id]...)
+		}
+		return ids, nil
+	}
+
+	testCases := []struct {
+		name      string
+		blockerID string
+		blockedID string
+		cycle     bool
+	}{
+		{"self dependency", "a", "a", true},
+		{"direct cycle", "b", "a", true},
+		{"indirect cycle", "d", "a", true},
+		{"no cycle", "a", "d", false},
+		{"shared blockers", "d", "e", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cycle, err := FindDependencyCycle(tc.blockerID, tc.blockedID, getBlockers)
+			require.NoError(t, err)
+			assert.Equal(t, tc.cycle, cycle)
+		})
+	}
+
+	t.Run("errors are returned", func(t *testing.T) {
+		_, err := FindDependencyCycle("a", "b", func([]string) ([]string, error) { return nil, errors.New("store error") })
+		require.Error(t, err)
+	})
+}
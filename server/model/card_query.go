@@ -0,0 +1,749 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FilterCondition string
+type FilterGroupOperation string
+
+const (
+	FilterConditionIncludes      FilterCondition = "includes"
+	FilterConditionNotIncludes   FilterCondition = "notIncludes"
+	FilterConditionIsEmpty       FilterCondition = "isEmpty"
+	FilterConditionIsNotEmpty    FilterCondition = "isNotEmpty"
+	FilterConditionIsSet         FilterCondition = "isSet"
+	FilterConditionIsNotSet      FilterCondition = "isNotSet"
+	FilterConditionIs            FilterCondition = "is"
+	FilterConditionContains      FilterCondition = "contains"
+	FilterConditionNotContains   FilterCondition = "notContains"
+	FilterConditionStartsWith    FilterCondition = "startsWith"
+	FilterConditionNotStartsWith FilterCondition = "notStartsWith"
+	FilterConditionEndsWith      FilterCondition = "endsWith"
+	FilterConditionNotEndsWith   FilterCondition = "notEndsWith"
+	FilterConditionIsBefore      FilterCondition = "isBefore"
+	FilterConditionIsAfter       FilterCondition = "isAfter"
+)
+
+const (
+	FilterGroupOperationAnd FilterGroupOperation = "and"
+	FilterGroupOperationOr  FilterGroupOperation = "or"
+)
+
+const (
+	// CardQueryTitlePropertyID is the pseudo property ID used by
+	// filters to reference the card title.
+	CardQueryTitlePropertyID = "title"
+
+	// CardQuerySortTitlePropertyID is the pseudo property ID used by
+	// view sort options to reference the card title.
+	CardQuerySortTitlePropertyID = "__title"
+
+	// halfDay is used to compare createdTime and updatedTime values,
+	// which include the time, against day based filter values.
+	halfDay = 12 * 60 * 60 * 1000
+)
+
+var ErrInvalidFilterCondition = errors.New("invalid filter condition")
+var ErrInvalidFilterGroupOperation = errors.New("invalid filter group operation")
+
+// FilterClause is a single condition applied to a card property.
+// swagger:model
+type FilterClause struct {
+	// The id of the property to filter on, or `title` for the card title
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The condition to apply
+	// required: true
+	Condition FilterCondition `json:"condition"`
+
+	// The values the condition is evaluated against
+	// required: false
+	Values []string `json:"values"`
+}
+
+// FilterGroup is a set of filter clauses and nested groups joined
+// by the same operation, as stored in the `filter` field of a view.
+// swagger:model
+type FilterGroup struct {
+	// The operation used to join the filters, `and` or `or`
+	// required: true
+	Operation FilterGroupOperation `json:"operation"`
+
+	// The filters of the group
+	// required: false
+	Filters []FilterGroupItem `json:"filters"`
+}
+
+// FilterGroupItem contains either a FilterClause or a nested FilterGroup.
+type FilterGroupItem struct {
+	Clause *FilterClause
+	Group  *FilterGroup
+}
+
+// SortOption defines how to sort the cards by a property.
+// swagger:model
+type SortOption struct {
+	// The id of the property to sort by, or `__title` for the card title
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// Sorts in descending order if true
+	// required: false
+	Reversed bool `json:"reversed"`
+}
+
+// QueryCardsOptions are the options to filter, sort and group the
+// cards of a board.
+// swagger:model
+type QueryCardsOptions struct {
+	// The id of a view of the board. If present, its filter, sort
+	// options, group by property and card order are used for the
+	// fields not set in the query
+	// required: false
+	ViewID string `json:"viewId,omitempty"`
+
+	// The filter to apply to the cards
+	// required: false
+	Filter *FilterGroup `json:"filter,omitempty"`
+
+	// The sort options to apply, in order of precedence
+	// required: false
+	SortOptions []SortOption `json:"sortOptions,omitempty"`
+
+	// The id of the property to group the cards by
+	// required: false
+	GroupByID string `json:"groupById,omitempty"`
+
+	// The manual order of the cards, used when there are no sort options
+	// required: false
+	CardOrder []string `json:"cardOrder,omitempty"`
+
+	// The page to select
+	// required: false
+	Page int `json:"-"`
+
+	// The number of cards per page, non positive values mean unlimited
+	// required: false
+	PerPage int `json:"-"`
+
+	// The time zone the formulas of the cards are evaluated in, UTC if nil
+	// required: false
+	Location *time.Location `json:"-"`
+
+	// The filter and sort of the cards by the progress of their
+	// checklists, applied before the sort options
+	// required: false
+	Checklist ChecklistQuery `json:"-"`
+}
+
+// CardGroupCount is the number of cards that have a given option
+// of the group by property.
+// swagger:model
+type CardGroupCount struct {
+	// The option id of the group, empty for cards without value
+	// required: true
+	OptionID string `json:"optionId"`
+
+	// The option value of the group, empty for cards without value
+	// required: true
+	Value string `json:"value"`
+
+	// The number of cards that matched the query in the group
+	// required: true
+	Count int `json:"count"`
+}
+
+// CardQueryResult is the result of querying the cards of a board.
+// swagger:model
+type CardQueryResult struct {
+	// The cards of the requested page
+	// required: true
+	Cards []*Card `json:"cards"`
+
+	// The total number of cards that matched the query
+	// required: true
+	Total int `json:"total"`
+
+	// The number of cards per option of the group by property, if any
+	// required: false
+	Groups []CardGroupCount `json:"groups,omitempty"`
+}
+
+func (i FilterGroupItem) MarshalJSON() ([]byte, error) {
+	if i.Group != nil {
+		return json.Marshal(i.Group)
+	}
+	return json.Marshal(i.Clause)
+}
+
+func (i *FilterGroupItem) UnmarshalJSON(data []byte) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	// same check as the webapp: a group is any object with both an
+	// operation and a list of filters
+	_, hasOperation := keys["operation"]
+	_, hasFilters := keys["filters"]
+	if hasOperation && hasFilters {
+		i.Group = &FilterGroup{}
+		return json.Unmarshal(data, i.Group)
+	}
+
+	i.Clause = &FilterClause{}
+	return json.Unmarshal(data, i.Clause)
+}
+
+// IsValid checks the filter group and all of its descendants for
+// unknown operations or conditions.
+func (fg *FilterGroup) IsValid() error {
+	if fg.Operation != FilterGroupOperationAnd && fg.Operation != FilterGroupOperationOr {
+		return fmt.Errorf("%w: %s", ErrInvalidFilterGroupOperation, fg.Operation)
+	}
+
+	for _, item := range fg.Filters {
+		if item.Group != nil {
+			if err := item.Group.IsValid(); err != nil {
+				return err
+			}
+			continue
+		}
+		if item.Clause != nil {
+			if err := item.Clause.IsValid(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsValid checks that the clause has a known condition.
+func (fc *FilterClause) IsValid() error {
+	switch fc.Condition {
+	case FilterConditionIncludes, FilterConditionNotIncludes,
+		FilterConditionIsEmpty, FilterConditionIsNotEmpty,
+		FilterConditionIsSet, FilterConditionIsNotSet,
+		FilterConditionIs,
+		FilterConditionContains, FilterConditionNotContains,
+		FilterConditionStartsWith, FilterConditionNotStartsWith,
+		FilterConditionEndsWith, FilterConditionNotEndsWith,
+		FilterConditionIsBefore, FilterConditionIsAfter:
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidFilterCondition, fc.Condition)
+}
+
+// IsValid checks the query options for errors.
+func (o *QueryCardsOptions) IsValid() error {
+	if o.Filter != nil {
+		if err := o.Filter.IsValid(); err != nil {
+			return NewErrBadRequest(err.Error())
+		}
+	}
+	if o.Page < 0 {
+		return NewErrBadRequest("invalid page")
+	}
+	return o.Checklist.IsValid()
+}
+
+// ApplyView fills the filter, sort options, group by property and
+// card order of the options that are not set with the ones stored
+// in the fields of the view block.
+func (o *QueryCardsOptions) ApplyView(view *Block) error {
+	if view.Type != TypeView {
+		return NewErrBadRequest(fmt.Sprintf("block %s is not a view", view.ID))
+	}
+
+	fieldsJSON, err := json.Marshal(view.Fields)
+	if err != nil {
+		return err
+	}
+
+	var viewFields struct {
+		Filter      *FilterGroup `json:"filter"`
+		SortOptions []SortOption `json:"sortOptions"`
+		GroupByID   string       `json:"groupById"`
+		CardOrder   []string     `json:"cardOrder"`
+	}
+	if err := json.Unmarshal(fieldsJSON, &viewFields); err != nil {
+		return fmt.Errorf("cannot parse fields of view %s: %w", view.ID, err)
+	}
+
+	if o.Filter == nil && viewFields.Filter != nil && viewFields.Filter.Operation != "" {
+		o.Filter = viewFields.Filter
+	}
+	if o.SortOptions == nil {
+		o.SortOptions = viewFields.SortOptions
+	}
+	if o.GroupByID == "" {
+		o.GroupByID = viewFields.GroupByID
+	}
+	if o.CardOrder == nil {
+		o.CardOrder = viewFields.CardOrder
+	}
+	return nil
+}
+
+// IsMet returns true if the card satisfies the filter group.
+func (fg *FilterGroup) IsMet(card *Card, schema PropSchema) bool {
+	if len(fg.Filters) == 0 {
+		return true
+	}
+
+	if fg.Operation == FilterGroupOperationOr {
+		for _, item := range fg.Filters {
+			if item.isMet(card, schema) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, item := range fg.Filters {
+		if !item.isMet(card, schema) {
+			return false
+		}
+	}
+	return true
+}
+
+func (i FilterGroupItem) isMet(card *Card, schema PropSchema) bool {
+	if i.Group != nil {
+		return i.Group.IsMet(card, schema)
+	}
+	if i.Clause != nil {
+		return i.Clause.IsMet(card, schema)
+	}
+	return true
+}
+
+// IsMet returns true if the card satisfies the filter clause.
+func (fc *FilterClause) IsMet(card *Card, schema PropSchema) bool {
+	value := cardPropertyValue(card, fc.PropertyID, schema)
+	def, hasDef := schema[fc.PropertyID]
+	isTimestamp := hasDef && (def.Type == "createdTime" || def.Type == "updatedTime")
+
+	var filterValue string
+	if len(fc.Values) > 0 {
+		filterValue = strings.ToLower(fc.Values[0])
+	}
+
+	switch fc.Condition {
+	case FilterConditionIncludes:
+		if len(fc.Values) == 0 {
+			return true
+		}
+		return valueIncludesAny(value, fc.Values)
+	case FilterConditionNotIncludes:
+		if len(fc.Values) == 0 {
+			return true
+		}
+		return !valueIncludesAny(value, fc.Values)
+	case FilterConditionIsEmpty, FilterConditionIsNotSet:
+		return isEmptyValue(value)
+	case FilterConditionIsNotEmpty, FilterConditionIsSet:
+		return !isEmptyValue(value)
+	case FilterConditionContains:
+		return len(fc.Values) == 0 || strings.Contains(valueToLowerString(value), filterValue)
+	case FilterConditionNotContains:
+		return len(fc.Values) == 0 || !strings.Contains(valueToLowerString(value), filterValue)
+	case FilterConditionStartsWith:
+		return len(fc.Values) == 0 || strings.HasPrefix(valueToLowerString(value), filterValue)
+	case FilterConditionNotStartsWith:
+		return len(fc.Values) == 0 || !strings.HasPrefix(valueToLowerString(value), filterValue)
+	case FilterConditionEndsWith:
+		return len(fc.Values) == 0 || strings.HasSuffix(valueToLowerString(value), filterValue)
+	case FilterConditionNotEndsWith:
+		return len(fc.Values) == 0 || !strings.HasSuffix(valueToLowerString(value), filterValue)
+	case FilterConditionIs:
+		if len(fc.Values) == 0 {
+			return true
+		}
+		if hasDef && isDatePropType(def.Type) {
+			from, to, ok := parseDateValue(value)
+			ts, err := strconv.ParseInt(fc.Values[0], 10, 64)
+			if !ok || err != nil {
+				return false
+			}
+			if isTimestamp {
+				return from > ts-halfDay && from < ts+halfDay
+			}
+			if to != 0 {
+				return from <= ts && to >= ts
+			}
+			return from == ts
+		}
+		return valueToLowerString(value) == filterValue
+	case FilterConditionIsBefore, FilterConditionIsAfter:
+		if len(fc.Values) == 0 {
+			return true
+		}
+		if !hasDef || !isDatePropType(def.Type) {
+			return false
+		}
+		from, to, ok := parseDateValue(value)
+		ts, err := strconv.ParseInt(fc.Values[0], 10, 64)
+		if !ok || err != nil {
+			return false
+		}
+		if fc.Condition == FilterConditionIsBefore {
+			if isTimestamp {
+				return from < ts-halfDay
+			}
+			return from < ts
+		}
+		if isTimestamp {
+			return from > ts+halfDay
+		}
+		if to != 0 {
+			return to > ts
+		}
+		return from > ts
+	}
+	return true
+}
+
+// SortCards sorts the cards in place following the sort options, the
+// first option having the highest precedence. If there are no sort
+// options, the cards are sorted by the manual card order, and cards
+// not present in it are placed at the end sorted by title.
+func SortCards(cards []*Card, sortOptions []SortOption, cardOrder []string, schema PropSchema) {
+	if len(sortOptions) == 0 {
+		orderIndex := make(map[string]int, len(cardOrder))
+		for i, id := range cardOrder {
+			orderIndex[id] = i
+		}
+		sort.SliceStable(cards, func(i, j int) bool {
+			indexA, okA := orderIndex[cards[i].ID]
+			indexB, okB := orderIndex[cards[j].ID]
+			switch {
+			case okA && okB:
+				return indexA < indexB
+			case okA != okB:
+				return okA
+			}
+			return titleOrCreatedOrder(cards[i], cards[j]) < 0
+		})
+		return
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		for _, option := range sortOptions {
+			result := compareCardsByProperty(cards[i], cards[j], option, schema)
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return titleOrCreatedOrder(cards[i], cards[j]) < 0
+	})
+}
+
+// GroupCardCounts counts the cards per option of the group by property.
+// Groups are returned following the options order, preceded by the
+// group of the cards without a value.
+func GroupCardCounts(cards []*Card, groupByID string, schema PropSchema) []CardGroupCount {
+	if _, ok := schema[groupByID]; !ok {
+		return nil
+	}
+
+	counts := map[string]int{}
+	for _, card := range cards {
+		switch v := card.Properties[groupByID].(type) {
+		case string:
+			counts[v]++
+		case []any:
+			if len(v) == 0 {
+				counts[""]++
+			}
+			for _, item := range v {
+				counts[fmt.Sprintf("%v", item)]++
+			}
+		case []string:
+			if len(v) == 0 {
+				counts[""]++
+			}
+			for _, item := range v {
+				counts[item]++
+			}
+		default:
+			counts[""]++
+		}
+	}
+	return CardGroupCountsFromCounts(counts, groupByID, schema)
+}
+
+// CardGroupCountsFromCounts returns the groups of the options of the group
+// by property from the number of cards per option ID, the cards without
+// value being counted under an empty ID.
+func CardGroupCountsFromCounts(counts map[string]int, groupByID string, schema PropSchema) []CardGroupCount {
+	def, ok := schema[groupByID]
+	if !ok {
+		return nil
+	}
+
+	options := make([]PropDefOption, 0, len(def.Options))
+	for _, option := range def.Options {
+		options = append(options, option)
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].Index < options[j].Index })
+
+	groups := make([]CardGroupCount, 0, len(options)+1)
+	groups = append(groups, CardGroupCount{Count: counts[""]})
+	for _, option := range options {
+		groups = append(groups, CardGroupCount{
+			OptionID: option.ID,
+			Value:    option.Value,
+			Count:    counts[option.ID],
+		})
+	}
+	return groups
+}
+
+func compareCardsByProperty(a, b *Card, option SortOption, schema PropSchema) int {
+	var result int
+	if option.PropertyID == CardQuerySortTitlePropertyID || option.PropertyID == CardQueryTitlePropertyID {
+		result = titleOrCreatedOrder(a, b)
+	} else {
+		def, ok := schema[option.PropertyID]
+		if !ok {
+			return 0
+		}
+
+		aValue := cardPropertyValue(a, option.PropertyID, schema)
+		bValue := cardPropertyValue(b, option.PropertyID, schema)
+
+		switch def.Type {
+		case "number", "date":
+			var aNum, bNum float64
+			var aOk, bOk bool
+			if def.Type == "date" {
+				var aFrom, bFrom int64
+				aFrom, _, aOk = parseDateValue(aValue)
+				bFrom, _, bOk = parseDateValue(bValue)
+				aNum, bNum = float64(aFrom), float64(bFrom)
+			} else {
+				aNum, aOk = parseNumberValue(aValue)
+				bNum, bOk = parseNumberValue(bValue)
+			}
+			// empty values always go at the bottom
+			if aOk != bOk {
+				if aOk {
+					return -1
+				}
+				return 1
+			}
+			if aNum < bNum {
+				result = -1
+			} else if aNum > bNum {
+				result = 1
+			}
+		case "createdTime":
+			result = compareInt64(a.CreateAt, b.CreateAt)
+		case "updatedTime":
+			result = compareInt64(a.UpdateAt, b.UpdateAt)
+		default:
+			aString := sortStringValue(aValue, def)
+			bString := sortStringValue(bValue, def)
+			// empty values always go at the bottom
+			if (aString == "") != (bString == "") {
+				if aString != "" {
+					return -1
+				}
+				return 1
+			}
+			result = strings.Compare(strings.ToLower(aString), strings.ToLower(bString))
+		}
+	}
+
+	if option.Reversed {
+		return -result
+	}
+	return result
+}
+
+func titleOrCreatedOrder(a, b *Card) int {
+	if a.Title != "" && b.Title != "" {
+		if result := strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)); result != 0 {
+			return result
+		}
+		return compareInt64(a.CreateAt, b.CreateAt)
+	}
+
+	// untitled cards always go at the bottom
+	if a.Title != "" {
+		return -1
+	}
+	if b.Title != "" {
+		return 1
+	}
+	return compareInt64(a.CreateAt, b.CreateAt)
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cardPropertyValue returns the value of a property of the card,
+// resolving the title and the properties computed from the card
+// metadata.
+func cardPropertyValue(card *Card, propertyID string, schema PropSchema) any {
+	if propertyID == CardQueryTitlePropertyID || propertyID == CardQuerySortTitlePropertyID {
+		return card.Title
+	}
+
+	value := card.Properties[propertyID]
+	if !isEmptyValue(value) {
+		return value
+	}
+
+	def, ok := schema[propertyID]
+	if !ok {
+		return value
+	}
+
+	switch def.Type {
+	case "createdBy":
+		return card.CreatedBy
+	case "updatedBy":
+		return card.ModifiedBy
+	case "createdTime":
+		return strconv.FormatInt(card.CreateAt, 10)
+	case "updatedTime":
+		return strconv.FormatInt(card.UpdateAt, 10)
+	}
+	return value
+}
+
+func isDatePropType(propType string) bool {
+	return propType == "date" || propType == "createdTime" || propType == "updatedTime"
+}
+
+// parseDateValue parses a date property value, which can be either a
+// millisecond timestamp or a JSON object with `from` and `to` fields.
+func parseDateValue(value any) (from int64, to int64, ok bool) {
+	s, isString := value.(string)
+	if !isString || s == "" {
+		return 0, 0, false
+	}
+
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ts, 0, true
+	}
+
+	var m map[string]int64
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return 0, 0, false
+	}
+	from, ok = m["from"]
+	return from, m["to"], ok
+}
+
+func parseNumberValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func sortStringValue(value any, def PropDef) string {
+	switch v := value.(type) {
+	case string:
+		if def.Type == "select" {
+			return def.Options[v].Value
+		}
+		return v
+	case []any:
+		if len(v) == 0 {
+			return ""
+		}
+		if def.Type == "multiSelect" {
+			id, _ := v[0].(string)
+			return def.Options[id].Value
+		}
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprintf("%v", item))
+		}
+		return strings.Join(parts, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+func valueIncludesAny(value any, values []string) bool {
+	for _, candidate := range values {
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				if fmt.Sprintf("%v", item) == candidate {
+					return true
+				}
+			}
+		case []string:
+			for _, item := range v {
+				if item == candidate {
+					return true
+				}
+			}
+		case nil:
+		default:
+			if fmt.Sprintf("%v", v) == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func valueToLowerString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ToLower(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprintf("%v", item))
+		}
+		return strings.ToLower(strings.Join(parts, ","))
+	}
+	return strings.ToLower(fmt.Sprintf("%v", value))
+}
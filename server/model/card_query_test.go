@@ -0,0 +1,163 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterGroupJSON(t *testing.T) {
+	data := `{
+		"operation": "or",
+		"filters": [
+			{"propertyId": "status", "condition": "includes", "values": ["done"]},
+			{"operation": "and", "filters": [
+				{"propertyId": "title", "condition": "contains", "values": ["bug"]}
+			]}
+		]
+	}`
+
+	var fg FilterGroup
+	require.NoError(t, json.Unmarshal([]byte(data), &fg))
+	require.NoError(t, fg.IsValid())
+
+	require.Len(t, fg.Filters, 2)
+	require.NotNil(t, fg.Filters[0].Clause)
+	assert.Equal(t, "status", fg.Filters[0].Clause.PropertyID)
+	require.NotNil(t, fg.Filters[1].Group)
+	assert.Equal(t, FilterGroupOperationAnd, fg.Filters[1].Group.Operation)
+
+	out, err := json.Marshal(fg)
+	require.NoError(t, err)
+	var roundTrip FilterGroup
+	require.NoError(t, json.Unmarshal(out, &roundTrip))
+	assert.Equal(t, fg, roundTrip)
+
+	t.Run("invalid condition", func(t *testing.T) {
+		fg := FilterGroup{
+			Operation: FilterGroupOperationAnd,
+			Filters:   []FilterGroupItem{{Clause: &FilterClause{PropertyID: "status", Condition: "resembles"}}},
+		}
+		require.ErrorIs(t, fg.IsValid(), ErrInvalidFilterCondition)
+	})
+}
+
+func TestFilterGroupIsMet(t *testing.T) {
+	schema := PropSchema{
+		"status": {ID: "status", Type: "select", Options: map[string]PropDefOption{
+			"todo": {ID: "todo", Index: 0, Value: "To do"},
+			"done": {ID: "done", Index: 1, Value: "Done"},
+		}},
+		"due":     {ID: "due", Type: "date"},
+		"created": {ID: "created", Type: "createdTime"},
+		"tags":    {ID: "tags", Type: "multiSelect"},
+	}
+
+	card := &Card{
+		Title:    "Fix the rollback bug",
+		CreateAt: 1000,
+		Properties: map[string]any{
+			"status": "done",
+			"due":    `{"from":1642161600000}`,
+			"tags":   []any{"backend", "urgent"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		clause   FilterClause
+		expected bool
+	}{
+		{"includes select", FilterClause{PropertyID: "status", Condition: FilterConditionIncludes, Values: []string{"done"}}, true},
+		{"not includes select", FilterClause{PropertyID: "status", Condition: FilterConditionNotIncludes, Values: []string{"done"}}, false},
+		{"includes multi select", FilterClause{PropertyID: "tags", Condition: FilterConditionIncludes, Values: []string{"urgent"}}, true},
+		{"includes without values", FilterClause{PropertyID: "status", Condition: FilterConditionIncludes}, true},
+		{"title contains", FilterClause{PropertyID: "title", Condition: FilterConditionContains, Values: []string{"ROLLBACK"}}, true},
+		{"title starts with", FilterClause{PropertyID: "title", Condition: FilterConditionStartsWith, Values: []string{"bug"}}, false},
+		{"title ends with", FilterClause{PropertyID: "title", Condition: FilterConditionEndsWith, Values: []string{"bug"}}, true},
+		{"is empty", FilterClause{PropertyID: "missing", Condition: FilterConditionIsEmpty}, true},
+		{"is not empty", FilterClause{PropertyID: "tags", Condition: FilterConditionIsNotEmpty}, true},
+		{"date is before", FilterClause{PropertyID: "due", Condition: FilterConditionIsBefore, Values: []string{"1642161600001"}}, true},
+		{"date is after", FilterClause{PropertyID: "due", Condition: FilterConditionIsAfter, Values: []string{"1642161600001"}}, false},
+		{"date is", FilterClause{PropertyID: "due", Condition: FilterConditionIs, Values: []string{"1642161600000"}}, true},
+		{"created time is", FilterClause{PropertyID: "created", Condition: FilterConditionIs, Values: []string{"2000"}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clause := tc.clause
+			assert.Equal(t, tc.expected, clause.IsMet(card, schema))
+		})
+	}
+
+	t.Run("or group", func(t *testing.T) {
+		fg := FilterGroup{
+			Operation: FilterGroupOperationOr,
+			Filters: []FilterGroupItem{
+				{Clause: &FilterClause{PropertyID: "status", Condition: FilterConditionIncludes, Values: []string{"todo"}}},
+				{Clause: &FilterClause{PropertyID: "title", Condition: FilterConditionContains, Values: []string{"fix"}}},
+			},
+		}
+		assert.True(t, fg.IsMet(card, schema))
+
+		fg.Operation = FilterGroupOperationAnd
+		assert.False(t, fg.IsMet(card, schema))
+	})
+}
+
+func TestSortCards(t *testing.T) {
+	schema := PropSchema{
+		"status": {ID: "status", Type: "select", Options: map[string]PropDefOption{
+			"a": {ID: "a", Index: 0, Value: "Alpha"},
+			"b": {ID: "b", Index: 1, Value: "Beta"},
+		}},
+		"estimate": {ID: "estimate", Type: "number"},
+	}
+
+	card1 := &Card{ID: "card1", Title: "C", CreateAt: 1, Properties: map[string]any{"status": "b", "estimate": "10"}}
+	card2 := &Card{ID: "card2", Title: "A", CreateAt: 2, Properties: map[string]any{"status": "a", "estimate": "2"}}
+	card3 := &Card{ID: "card3", Title: "B", CreateAt: 3, Properties: map[string]any{}}
+
+	ids := func(cards []*Card) []string {
+		res := make([]string, 0, len(cards))
+		for _, c := range cards {
+			res = append(res, c.ID)
+		}
+		return res
+	}
+
+	t.Run("by title", func(t *testing.T) {
+		cards := []*Card{card1, card2, card3}
+		SortCards(cards, []SortOption{{PropertyID: CardQuerySortTitlePropertyID}}, nil, schema)
+		assert.Equal(t, []string{"card2", "card3", "card1"}, ids(cards))
+	})
+
+	t.Run("by number reversed keeps empty values last", func(t *testing.T) {
+		cards := []*Card{card3, card2, card1}
+		SortCards(cards, []SortOption{{PropertyID: "estimate", Reversed: true}}, nil, schema)
+		assert.Equal(t, []string{"card1", "card2", "card3"}, ids(cards))
+	})
+
+	t.Run("by select option value", func(t *testing.T) {
+		cards := []*Card{card1, card3, card2}
+		SortCards(cards, []SortOption{{PropertyID: "status"}}, nil, schema)
+		assert.Equal(t, []string{"card2", "card1", "card3"}, ids(cards))
+	})
+
+	t.Run("manual order", func(t *testing.T) {
+		cards := []*Card{card1, card2, card3}
+		SortCards(cards, nil, []string{"card3", "card1"}, schema)
+		assert.Equal(t, []string{"card3", "card1", "card2"}, ids(cards))
+	})
+
+	t.Run("group counts", func(t *testing.T) {
+		groups := GroupCardCounts([]*Card{card1, card2, card3}, "status", schema)
+		assert.Equal(t, []CardGroupCount{
+			{OptionID: "", Value: "", Count: 1},
+			{OptionID: "a", Value: "Alpha", Count: 1},
+			{OptionID: "b", Value: "Beta", Count: 1},
+		}, groups)
+	})
+}
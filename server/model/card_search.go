@@ -0,0 +1,100 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// CardSearchMaxTerms is the maximum number of words of a search
+	// string that are used to match cards, the rest are ignored.
+	CardSearchMaxTerms = 10
+
+	// CardSearchHighlightStart and CardSearchHighlightEnd wrap each
+	// matching word in the snippet of a card search result.
+	CardSearchHighlightStart = "<mark>"
+	CardSearchHighlightEnd   = "</mark>"
+)
+
+// CardSearchOptions are the options of a full-text search of cards.
+type CardSearchOptions struct {
+	Terms    []string // lowercase words that all must match, see SplitSearchTerms
+	BoardIDs []string // the boards to search in
+	Page     int      // page number to select when paginating
+	PerPage  int      // number of results per page
+}
+
+// CardSearchResult is a card, or a content block of a card, matching a
+// full-text search.
+// swagger:model
+type CardSearchResult struct {
+	// The ID of the board that owns the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the matching card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The ID of the matching block, either the card itself or one of
+	// its content blocks
+	// required: true
+	BlockID string `json:"blockId"`
+
+	// The type of the matching block
+	// required: true
+	BlockType BlockType `json:"blockType"`
+
+	// The title of the matching card
+	// required: true
+	CardTitle string `json:"cardTitle"`
+
+	// HTML escaped fragment of the matching text, with the matching
+	// words wrapped in <mark> tags
+	// required: true
+	Snippet string `json:"snippet"`
+
+	// The last update time of the matching block
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsSearchableBlockType returns true if blocks of the given type are
+// indexed for full-text search. Card content and comments are indexed
+// along with the card titles and property values, including the items of
+// lists and the cells of tables.
+func IsSearchableBlockType(blockType BlockType) bool {
+	switch blockType {
+	case TypeCard, TypeText, TypeCheckbox, TypeComment,
+		TypeHeading, TypeCode, TypeBulletList, TypeNumberedList, TypeTable:
+		return true
+	}
+	return false
+}
+
+// SplitSearchTerms breaks a search string into the lowercase words
+// that are matched against the search index. Any character that is not
+// a letter or a digit acts as a separator, and duplicated words are
+// removed.
+func SplitSearchTerms(search string) []string {
+	words := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	seen := map[string]bool{}
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == CardSearchMaxTerms {
+			break
+		}
+	}
+	return terms
+}
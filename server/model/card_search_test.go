@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSearchTerms(t *testing.T) {
+	testCases := []struct {
+		name     string
+		search   string
+		expected []string
+	}{
+		{"empty", "", []string{}},
+		{"only separators", " -- !? ", []string{}},
+		{"lowercase words", "Fix the Rollback", []string{"fix", "the", "rollback"}},
+		{"punctuation splits words", "release-notes: v2.1", []string{"release", "notes", "v2", "1"}},
+		{"duplicates removed", "bug BUG Bug fix", []string{"bug", "fix"}},
+		{"unicode letters", "Café über", []string{"café", "über"}},
+		{"query syntax is stripped", `"title"* OR a:*`, []string{"title", "or", "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SplitSearchTerms(tc.search))
+		})
+	}
+
+	t.Run("number of terms is limited", func(t *testing.T) {
+		terms := SplitSearchTerms("a b c d e f g h i j k l")
+		assert.Len(t, terms, CardSearchMaxTerms)
+	})
+}
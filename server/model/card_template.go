@@ -0,0 +1,203 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	PlaceholderToday      = "today"
+	PlaceholderCreator    = "creator"
+	PlaceholderBoardTitle = "board.title"
+
+	placeholderDateLayout = "2006-01-02"
+)
+
+var (
+	placeholderRegexp     = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.+-]+)\s*\}\}`)
+	todayPlaceholderRegex = regexp.MustCompile(`^today(?:([+-])(\d+)([dw]))?$`)
+	templateVariableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// CardFromTemplateRequest is the request to create a card from a card
+// template, resolving the placeholders of its title, content and property
+// values.
+// swagger:model
+type CardFromTemplateRequest struct {
+	// The values of the user variables of the placeholders, by name
+	// required: false
+	Variables map[string]string `json:"variables"`
+}
+
+// IsValid checks that the names of the variables can be used in
+// placeholders and don't shadow the built-in ones.
+func (r *CardFromTemplateRequest) IsValid() error {
+	for name := range r.Variables {
+		if !templateVariableRegex.MatchString(name) {
+			return NewErrBadRequest("invalid variable name: " + name)
+		}
+		if name == PlaceholderToday || name == PlaceholderCreator {
+			return NewErrBadRequest("reserved variable name: " + name)
+		}
+	}
+	return nil
+}
+
+// PlaceholderContext holds the values of the placeholders of a card
+// template. The supported placeholders are {{today}}, {{today+7d}},
+// {{today-2w}}, {{creator}}, {{board.title}} and the user variables.
+// Unknown placeholders are left as they are unless RemoveUnknown is set.
+type PlaceholderContext struct {
+	// Now is the creation time, in the location whose day {{today}} is
+	Now time.Time
+
+	// CreatorID and CreatorName are the user creating the card; person
+	// properties get the ID and texts the name
+	CreatorID   string
+	CreatorName string
+
+	BoardTitle string
+	Variables  map[string]string
+
+	// RemoveUnknown removes the unknown placeholders, e.g. the fields
+	// missing from the payload of an incoming webhook
+	RemoveUnknown bool
+}
+
+// HasPlaceholders returns true if a value of a card template, or one of
+// its items for lists, contains placeholders.
+func HasPlaceholders(v interface{}) bool {
+	switch value := v.(type) {
+	case string:
+		return placeholderRegexp.MatchString(value)
+	case []interface{}:
+		for _, item := range value {
+			if HasPlaceholders(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PropertiesWithoutPlaceholders returns the property values of a card
+// template that don't contain placeholders, as the other ones can only be
+// checked once resolved.
+func PropertiesWithoutPlaceholders(properties map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(properties))
+	for id, value := range properties {
+		if !HasPlaceholders(value) {
+			result[id] = value
+		}
+	}
+	return result
+}
+
+// DatePropertyValue returns the value of a date property set to the day
+// of a time, which date properties store at midnight UTC.
+func DatePropertyValue(t time.Time) string {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return `{"from":` + strconv.FormatInt(utils.GetMillisForTime(day), 10) + `}`
+}
+
+// Resolve returns a text with its placeholders replaced by their values.
+func (c PlaceholderContext) Resolve(s string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+		name := placeholderRegexp.FindStringSubmatch(placeholder)[1]
+		if day, ok := c.day(name); ok {
+			return day.Format(placeholderDateLayout)
+		}
+		switch name {
+		case PlaceholderCreator:
+			return c.CreatorName
+		case PlaceholderBoardTitle:
+			return c.BoardTitle
+		}
+		if value, ok := c.Variables[name]; ok {
+			return value
+		}
+		if c.RemoveUnknown {
+			return ""
+		}
+		return placeholder
+	})
+}
+
+// ResolveProperty returns a property value of a card template with its
+// placeholders resolved for the type of the property: a date property
+// whose value is a single date placeholder is set to that day, and a
+// person property to the ID of the creator for {{creator}}.
+func (c PlaceholderContext) ResolveProperty(def PropDef, v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		name, single := singlePlaceholder(value)
+		if single && def.Type == "date" {
+			if day, ok := c.day(name); ok {
+				return DatePropertyValue(day)
+			}
+		}
+		if single && name == PlaceholderCreator && (def.Type == "person" || def.Type == "multiPerson") {
+			return c.CreatorID
+		}
+		return c.Resolve(value)
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			result = append(result, c.ResolveProperty(def, item))
+		}
+		return result
+	}
+	return v
+}
+
+// ResolveProperties returns the property values of a card template with
+// their placeholders resolved.
+func (c PlaceholderContext) ResolveProperties(schema PropSchema, properties map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(properties))
+	for id, value := range properties {
+		result[id] = c.ResolveProperty(schema[id], value)
+	}
+	return result
+}
+
+// day returns the day of a {{today}} placeholder, with its offset in days
+// or weeks.
+func (c PlaceholderContext) day(name string) (time.Time, bool) {
+	match := todayPlaceholderRegex.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	if match[1] == "" {
+		return c.Now, true
+	}
+
+	offset, err := strconv.Atoi(match[2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	if match[3] == "w" {
+		offset *= 7
+	}
+	if match[1] == "-" {
+		offset = -offset
+	}
+	return c.Now.AddDate(0, 0, offset), true
+}
+
+// singlePlaceholder returns the name of the placeholder a value is made
+// of, if it is nothing else.
+func singlePlaceholder(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	match := placeholderRegexp.FindStringSubmatchIndex(s)
+	if match == nil || match[0] != 0 || match[1] != len(s) {
+		return "", false
+	}
+	return s[match[2]:match[3]], true
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceholderContext(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// March 10th in Tokyo, still March 9th in UTC
+	ctx := PlaceholderContext{
+		Now:         time.Date(2026, time.March, 10, 8, 0, 0, 0, tokyo),
+		CreatorID:   "user-1",
+		CreatorName: "alice",
+		BoardTitle:  "Sprint",
+		Variables:   map[string]string{"client": "ACME"},
+	}
+
+	t.Run("texts", func(t *testing.T) {
+		testCases := []struct {
+			text     string
+			expected string
+		}{
+			{"Due {{today}}", "Due 2026-03-10"},
+			{"{{ today+7d }} / {{today-1w}}", "2026-03-17 / 2026-03-03"},
+			{"{{creator}} on {{board.title}}", "alice on Sprint"},
+			{"Call {{client}}", "Call ACME"},
+			{"{{unknown}} and {{today+1y}}", "{{unknown}} and {{today+1y}}"},
+			{"no placeholder", "no placeholder"},
+		}
+		for _, tc := range testCases {
+			require.Equal(t, tc.expected, ctx.Resolve(tc.text), tc.text)
+		}
+	})
+
+	t.Run("property values", func(t *testing.T) {
+		schema := PropSchema{
+			"due":    PropDef{ID: "due", Type: "date"},
+			"owner":  PropDef{ID: "owner", Type: "person"},
+			"owners": PropDef{ID: "owners", Type: "multiPerson"},
+			"notes":  PropDef{ID: "notes", Type: "text"},
+		}
+		resolved := ctx.ResolveProperties(schema, map[string]interface{}{
+			"due":    "{{today+2d}}",
+			"owner":  "{{creator}}",
+			"owners": []interface{}{"{{creator}}", "user-2"},
+			"notes":  "{{creator}} for {{client}}",
+			"other":  "{{today}}",
+		})
+
+		day := utils.GetMillisForTime(time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, map[string]interface{}{
+			"due":    `{"from":` + strconv.FormatInt(day, 10) + `}`,
+			"owner":  "user-1",
+			"owners": []interface{}{"user-1", "user-2"},
+			"notes":  "alice for ACME",
+			"other":  "2026-03-10",
+		}, resolved)
+	})
+
+	t.Run("placeholders are skipped by the validation of templates", func(t *testing.T) {
+		properties := map[string]interface{}{
+			"due":    "{{today}}",
+			"owners": []interface{}{"{{creator}}"},
+			"status": "todo",
+		}
+		require.Equal(t, map[string]interface{}{"status": "todo"}, PropertiesWithoutPlaceholders(properties))
+	})
+}
+
+func TestCardFromTemplateRequest(t *testing.T) {
+	require.NoError(t, (&CardFromTemplateRequest{Variables: map[string]string{"client_name": "ACME"}}).IsValid())
+	require.True(t, IsErrBadRequest((&CardFromTemplateRequest{Variables: map[string]string{"client name": "ACME"}}).IsValid()))
+	require.True(t, IsErrBadRequest((&CardFromTemplateRequest{Variables: map[string]string{"today": "now"}}).IsValid()))
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	// MaxDueDateReminders is the maximum number of reminders of a board.
+	MaxDueDateReminders = 5
+
+	// MaxDueDateReminderDaysBefore is how many days before the due date a
+	// reminder can be sent at most.
+	MaxDueDateReminderDaysBefore = 365
+)
+
+// DueDateReminderSettings are the settings of the reminders sent to the
+// assignees of the cards of a board before and on their due date.
+// swagger:model
+type DueDateReminderSettings struct {
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the date property holding the due date of the cards
+	// required: true
+	DatePropertyID string `json:"datePropertyId"`
+
+	// The ID of the person or multi person property holding the assignees
+	// of the cards
+	// required: true
+	AssigneePropertyID string `json:"assigneePropertyId"`
+
+	// The number of days before the due date each reminder is sent, 0 for
+	// the due date itself
+	// required: true
+	DaysBefore []int `json:"daysBefore"`
+
+	// The hour of the day, in the time zone of each assignee, the
+	// reminders are sent at
+	// required: true
+	Hour int `json:"hour"`
+
+	// True if the reminders are sent
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// The ID of the user who last modified the settings
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// Updated time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// DueDateReminder is a reminder sent to an assignee of a card, recorded
+// so that each reminder is sent once.
+type DueDateReminder struct {
+	BoardID    string `json:"boardId"`
+	CardID     string `json:"cardId"`
+	UserID     string `json:"userId"`
+	DueAt      int64  `json:"dueAt"`
+	DaysBefore int    `json:"daysBefore"`
+	SentAt     int64  `json:"sentAt"`
+}
+
+// IsValid validates the reminder settings, regardless of the board.
+func (s *DueDateReminderSettings) IsValid() error {
+	if s.BoardID == "" {
+		return NewErrBadRequest("missing board ID")
+	}
+	if s.DatePropertyID == "" {
+		return NewErrBadRequest("missing date property ID")
+	}
+	if s.AssigneePropertyID == "" {
+		return NewErrBadRequest("missing assignee property ID")
+	}
+	if len(s.DaysBefore) == 0 || len(s.DaysBefore) > MaxDueDateReminders {
+		return NewErrBadRequest(fmt.Sprintf("between 1 and %d reminders are required", MaxDueDateReminders))
+	}
+	seen := map[int]bool{}
+	for _, days := range s.DaysBefore {
+		if days < 0 || days > MaxDueDateReminderDaysBefore {
+			return NewErrBadRequest(fmt.Sprintf("invalid days before the due date %d", days))
+		}
+		if seen[days] {
+			return NewErrBadRequest(fmt.Sprintf("duplicated days before the due date %d", days))
+		}
+		seen[days] = true
+	}
+	if s.Hour < 0 || s.Hour > 23 {
+		return NewErrBadRequest(fmt.Sprintf("invalid hour %d", s.Hour))
+	}
+	return nil
+}
+
+// IsValidForSchema returns an error if the properties of the settings
+// aren't a date and a person property of the card property schema.
+func (s *DueDateReminderSettings) IsValidForSchema(schema PropSchema) error {
+	if prop, ok := schema[s.DatePropertyID]; !ok || prop.Type != "date" {
+		return NewErrBadRequest("the date property must be a date property of the board")
+	}
+	if prop, ok := schema[s.AssigneePropertyID]; !ok || (prop.Type != "person" && prop.Type != "multiPerson") {
+		return NewErrBadRequest("the assignee property must be a person property of the board")
+	}
+	return nil
+}
+
+// ReminderTime returns the time a reminder is sent in a time zone.
+func (s *DueDateReminderSettings) ReminderTime(due DueDate, daysBefore int, loc *time.Location) time.Time {
+	day := due.Day(loc)
+	return time.Date(day.Year(), day.Month(), day.Day()-daysBefore, s.Hour, 0, 0, 0, loc)
+}
+
+// CardAssignees returns the IDs of the users a person or multi person
+// property of a card is set to.
+func CardAssignees(card *Block, propertyID string) []string {
+	properties, _ := card.Fields["properties"].(map[string]interface{})
+	switch value := properties[propertyID].(type) {
+	case string:
+		if value == "" {
+			return []string{}
+		}
+		return []string{value}
+	default:
+		userIDs, _ := stringList(value)
+		return userIDs
+	}
+}
+
+// DueDate is the due date of a card, the end of the range of a date
+// property value.
+type DueDate struct {
+	// The due date in milliseconds since the epoch. Dates without a time
+	// are at midnight UTC of the day
+	At int64
+
+	// True if the due date has a time, false if it's a day
+	IncludeTime bool
+}
+
+// ParseDueDate returns the due date of a date property value of a card,
+// and false if the value isn't set or isn't a date.
+func ParseDueDate(value interface{}) (DueDate, bool) {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return DueDate{}, false
+	}
+	var date struct {
+		From        *int64 `json:"from"`
+		To          *int64 `json:"to"`
+		IncludeTime bool   `json:"includeTime"`
+	}
+	if err := json.Unmarshal([]byte(s), &date); err != nil || date.From == nil {
+		return DueDate{}, false
+	}
+	due := DueDate{At: *date.From, IncludeTime: date.IncludeTime}
+	if date.To != nil {
+		due.At = *date.To
+	}
+	return due, true
+}
+
+// Day returns the midnight of the due day in a time zone. Days without a
+// time are the same day everywhere.
+func (d DueDate) Day(loc *time.Location) time.Time {
+	t := utils.GetTimeForMillis(d.At).UTC()
+	if d.IncludeTime {
+		t = t.In(loc)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// IsOverdue returns true if the due date is past at a time. Days without
+// a time are overdue once they're over in the time zone of now.
+func (d DueDate) IsOverdue(now time.Time) bool {
+	if d.IncludeTime {
+		return now.After(d.OverdueAt(now.Location()))
+	}
+	return !now.Before(d.OverdueAt(now.Location()))
+}
+
+// OverdueAt returns the time the due date passes in a time zone: the due
+// time, or the end of the due day for days without a time.
+func (d DueDate) OverdueAt(loc *time.Location) time.Time {
+	if d.IncludeTime {
+		return utils.GetTimeForMillis(d.At).In(loc)
+	}
+	return d.Day(loc).AddDate(0, 0, 1)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDueDate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected DueDate
+		ok       bool
+	}{
+		{"day", `{"from":1000}`, DueDate{At: 1000}, true},
+		{"range", `{"from":1000,"to":2000}`, DueDate{At: 2000}, true},
+		{"time", `{"from":1000,"includeTime":true}`, DueDate{At: 1000, IncludeTime: true}, true},
+		{"empty", "", DueDate{}, false},
+		{"no start", `{"to":2000}`, DueDate{}, false},
+		{"not a date", "tomorrow", DueDate{}, false},
+		{"not a string", 1000.0, DueDate{}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			due, ok := ParseDueDate(tc.value)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, due)
+		})
+	}
+}
+
+func TestDueDateIsOverdue(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// March 10th, without a time
+	day := DueDate{At: utils.GetMillisForTime(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC))}
+	require.False(t, day.IsOverdue(time.Date(2026, time.March, 10, 23, 59, 0, 0, tokyo)))
+	require.True(t, day.IsOverdue(time.Date(2026, time.March, 11, 0, 0, 0, 0, tokyo)))
+
+	exact := DueDate{At: utils.GetMillisForTime(time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)), IncludeTime: true}
+	require.False(t, exact.IsOverdue(time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)))
+	require.True(t, exact.IsOverdue(time.Date(2026, time.March, 10, 21, 1, 0, 0, tokyo)))
+}
+
+func TestDueDateReminderSettings(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		valid := DueDateReminderSettings{BoardID: "board", DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{1, 0}, Hour: 9}
+		require.NoError(t, valid.IsValid())
+
+		for _, invalid := range []func(s *DueDateReminderSettings){
+			func(s *DueDateReminderSettings) { s.DatePropertyID = "" },
+			func(s *DueDateReminderSettings) { s.DaysBefore = nil },
+			func(s *DueDateReminderSettings) { s.DaysBefore = []int{-1} },
+			func(s *DueDateReminderSettings) { s.DaysBefore = []int{0, 0} },
+			func(s *DueDateReminderSettings) { s.DaysBefore = []int{0, 1, 2, 3, 4, 5} },
+			func(s *DueDateReminderSettings) { s.Hour = 24 },
+		} {
+			settings := valid
+			invalid(&settings)
+			require.True(t, IsErrBadRequest(settings.IsValid()))
+		}
+
+		schema := PropSchema{
+			"due":   PropDef{ID: "due", Type: "date"},
+			"owner": PropDef{ID: "owner", Type: "multiPerson"},
+		}
+		require.NoError(t, valid.IsValidForSchema(schema))
+		valid.AssigneePropertyID = "due"
+		require.True(t, IsErrBadRequest(valid.IsValidForSchema(schema)))
+	})
+
+	t.Run("reminders are sent at the hour of the time zone", func(t *testing.T) {
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		require.NoError(t, err)
+
+		settings := DueDateReminderSettings{Hour: 9}
+		due := DueDate{At: utils.GetMillisForTime(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC))}
+		require.Equal(t, time.Date(2026, time.March, 9, 9, 0, 0, 0, tokyo), settings.ReminderTime(due, 1, tokyo))
+
+		// the evening of March 10th in New York is March 11th in Tokyo
+		exact := DueDate{At: utils.GetMillisForTime(time.Date(2026, time.March, 11, 1, 0, 0, 0, time.UTC)), IncludeTime: true}
+		require.Equal(t, time.Date(2026, time.March, 11, 9, 0, 0, 0, tokyo), settings.ReminderTime(exact, 0, tokyo))
+	})
+
+	t.Run("card assignees", func(t *testing.T) {
+		card := &Block{Fields: map[string]interface{}{"properties": map[string]interface{}{
+			"owner":  "user-1",
+			"owners": []interface{}{"user-1", "user-2"},
+		}}}
+		require.Equal(t, []string{"user-1"}, CardAssignees(card, "owner"))
+		require.Equal(t, []string{"user-1", "user-2"}, CardAssignees(card, "owners"))
+		require.Empty(t, CardAssignees(card, "missing"))
+	})
+}
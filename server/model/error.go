@@ -146,6 +146,26 @@ func (e *ErrInvalidCategory) Error() string {
 	return e.msg
 }
 
+// ErrConflict is returned when a patch expects a different version of
+// the entity than the current one. It carries the current version so
+// clients can merge their changes and retry.
+type ErrConflict struct {
+	entity  string
+	Current interface{}
+}
+
+// NewErrConflict creates a new ErrConflict instance.
+func NewErrConflict(entity string, current interface{}) *ErrConflict {
+	return &ErrConflict{
+		entity:  entity,
+		Current: current,
+	}
+}
+
+func (c *ErrConflict) Error() string {
+	return fmt.Sprintf("{%s} has been modified", c.entity)
+}
+
 type ErrNotImplemented struct {
 	msg string
 }
@@ -168,7 +188,12 @@ func (ni *ErrNotImplemented) Error() string {
 // - model.ErrBoardMemberIsLastAdmin
 // - model.ErrBoardIDMismatch
 // - model.ErrBlockTitleSizeLimitExceeded
-// - model.ErrBlockFieldsSizeLimitExceeded.
+// - model.ErrBlockFieldsSizeLimitExceeded
+// - model.ErrInvalidBlockType
+// - model.ErrInvalidBlockField
+// - model.ErrInvalidBlockParent
+// - model.ErrInvalidPropertyValues
+// - model.ErrUnconvertibleValues.
 func IsErrBadRequest(err error) bool {
 	if err == nil {
 		return false
@@ -180,6 +205,18 @@ func IsErrBadRequest(err error) bool {
 		return true
 	}
 
+	// check if this is a model.ErrInvalidPropertyValues
+	var ipv *ErrInvalidPropertyValues
+	if errors.As(err, &ipv) {
+		return true
+	}
+
+	// check if this is a model.ErrUnconvertibleValues
+	var uv *ErrUnconvertibleValues
+	if errors.As(err, &uv) {
+		return true
+	}
+
 	// check if this is a model.ErrViewsLimitReached
 	if errors.Is(err, ErrViewsLimitReached) {
 		return true
@@ -212,8 +249,25 @@ func IsErrBadRequest(err error) bool {
 		return true
 	}
 
-	// check if this is a model.ErrBlockTitleSizeLimitExceeded
-	return errors.Is(err, ErrBlockFieldsSizeLimitExceeded)
+	// check if this is a model.ErrBlockFieldsSizeLimitExceeded
+	if errors.Is(err, ErrBlockFieldsSizeLimitExceeded) {
+		return true
+	}
+
+	// check if this is a model.ErrInvalidBlockType
+	if IsErrInvalidBlockType(err) {
+		return true
+	}
+
+	// check if this is a model.ErrInvalidBlockField
+	var ibf ErrInvalidBlockField
+	if errors.As(err, &ibf) {
+		return true
+	}
+
+	// check if this is a model.ErrInvalidBlockParent
+	var ibp ErrInvalidBlockParent
+	return errors.As(err, &ibp)
 }
 
 // IsErrUnauthorized returns true if `err` is or wraps one of:
@@ -311,6 +365,18 @@ func IsErrRequestEntityTooLarge(err error) bool {
 	return errors.Is(err, ErrRequestEntityTooLarge)
 }
 
+// IsErrConflict returns true if `err` is or wraps one of:
+// - model.ErrConflict.
+func IsErrConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// check if this is a model.ErrConflict
+	var c *ErrConflict
+	return errors.As(err, &c)
+}
+
 // IsErrNotImplemented returns true if `err` is or wraps one of:
 // - model.ErrNotImplemented
 // - model.ErrInsufficientLicense.
@@ -10,4 +10,17 @@ type ErrorResponse struct {
 	// The error code
 	// required: false
 	ErrorCode int `json:"errorCode"`
+
+	// The current version of the entities of a conflicting patch
+	// required: false
+	Current interface{} `json:"current,omitempty"`
+
+	// The invalid card property values of a rejected card
+	// required: false
+	InvalidProperties []InvalidPropertyValue `json:"invalidProperties,omitempty"`
+
+	// The card values that cannot be converted by a rejected property
+	// conversion
+	// required: false
+	Unconvertible []UnconvertibleValue `json:"unconvertible,omitempty"`
 }
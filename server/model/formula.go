@@ -0,0 +1,1161 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// PropTypeFormula is the type of the card properties computed from an
+// expression over the other properties of the card. The expression is
+// stored in the `formula` field of the property definition.
+//
+// The expression language supports:
+//   - number, string and boolean literals: 1.5, "text", 'text', true, false
+//   - arithmetic: + - * / %, where + concatenates if an operand is a string
+//   - comparisons: == != < <= > >=, and logic: && || !
+//   - references to other properties by name: prop("Estimate")
+//   - functions: if, concat, length, upper, lower, trim, contains, replace,
+//     abs, round, floor, ceil, min, max, toNumber, format, empty, now,
+//     dateAdd, dateBetween and formatDate
+//
+// Dates are numbers of milliseconds since the epoch, and the date units are
+// minutes, hours, days and weeks.
+const PropTypeFormula = "formula"
+
+const (
+	maxFormulaLength = 1000
+	maxFormulaDepth  = 50
+	// maxFormulaTextLength is the maximum number of characters of the text
+	// values computed by a formula, so nested functions can't build values
+	// too large to be stored in a card.
+	maxFormulaTextLength = BlockFieldsMaxRunes
+)
+
+var ErrInvalidFormula = errors.New("invalid formula")
+
+// Formula is a parsed formula expression.
+type Formula struct {
+	root       formulaNode
+	references []string
+}
+
+// ParseFormula parses a formula expression. An empty expression is valid
+// and evaluates to an empty value.
+func ParseFormula(expr string) (*Formula, error) {
+	if len(expr) > maxFormulaLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidFormula, maxFormulaLength)
+	}
+
+	tokens, err := tokenizeFormula(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	formula := &Formula{}
+	if len(tokens) == 1 {
+		return formula, nil
+	}
+
+	p := &formulaParser{tokens: tokens}
+	formula.root, err = p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != formulaTokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidFormula, tok.text, tok.pos)
+	}
+	formula.references = p.references
+	return formula, nil
+}
+
+// References returns the names of the properties the formula references.
+func (f *Formula) References() []string {
+	return f.references
+}
+
+// HasFormulas returns true if the schema has formula properties.
+func (s PropSchema) HasFormulas() bool {
+	for _, def := range s {
+		if def.Type == PropTypeFormula {
+			return true
+		}
+	}
+	return false
+}
+
+// FormulaContext holds what formulas read from outside of the card: the
+// time returned by now() and the time zone formatDate formats dates in.
+// Evaluating several versions of a card with the same context keeps their
+// time dependent values comparable.
+type FormulaContext struct {
+	Now      int64
+	Location *time.Location
+}
+
+// NewFormulaContext returns a formula context at the current time, in the
+// given time zone or in UTC if nil.
+func NewFormulaContext(loc *time.Location) FormulaContext {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return FormulaContext{
+		Now:      utils.GetMillis(),
+		Location: loc,
+	}
+}
+
+// EvaluateCardFormulas sets the values of the formula properties of a card,
+// replacing the values stored for them. The formulas that cannot be
+// evaluated have no value.
+func EvaluateCardFormulas(card *Card, schema PropSchema, fctx FormulaContext) {
+	if card == nil || !schema.HasFormulas() {
+		return
+	}
+
+	card.Properties = schema.evaluateFormulas(formulaCard{
+		properties: card.Properties,
+		createdBy:  card.CreatedBy,
+		modifiedBy: card.ModifiedBy,
+		createAt:   card.CreateAt,
+		updateAt:   card.UpdateAt,
+	}, fctx)
+}
+
+// EvaluateBlockFormulas returns a copy of a card block with the values of
+// its formula properties set. Other blocks are returned as they are.
+func EvaluateBlockFormulas(block *Block, schema PropSchema, fctx FormulaContext) *Block {
+	if block == nil || block.Type != TypeCard || !schema.HasFormulas() {
+		return block
+	}
+
+	properties := schema.evaluateFormulas(formulaCardFromBlock(block), fctx)
+
+	b := *block
+	b.Fields = make(map[string]interface{}, len(block.Fields)+1)
+	for k, v := range block.Fields {
+		b.Fields[k] = v
+	}
+	b.Fields["properties"] = properties
+	return &b
+}
+
+// ValidateFormulas checks that the formula properties of a board parse,
+// only reference existing properties and don't depend on themselves. The
+// error lists the problems of each formula.
+func ValidateFormulas(board *Board) error {
+	schema, err := ParsePropertySchema(board)
+	if err != nil {
+		// invalid schemas are handled by the schema validation
+		return nil //nolint:nilerr
+	}
+
+	byName := schema.propertiesByName()
+	problems := []string{}
+	for _, def := range schema.sortedDefs() {
+		if def.Type != PropTypeFormula {
+			continue
+		}
+		if def.formulaErr != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", def.Name, def.formulaErr.Error()))
+			continue
+		}
+		for _, name := range def.formula.References() {
+			if _, ok := byName[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown property %q", def.Name, name))
+			}
+		}
+		if schema.formulaDependsOn(def.ID, def, byName, map[string]bool{}) {
+			problems = append(problems, fmt.Sprintf("%s: the formula depends on itself", def.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return NewErrBadRequest("invalid formula properties: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// formulaDependsOn returns true if the formula of a property references the
+// property with the target ID, directly or through other formulas.
+func (s PropSchema) formulaDependsOn(targetID string, def PropDef, byName map[string]PropDef, visited map[string]bool) bool {
+	if def.formula == nil || visited[def.ID] {
+		return false
+	}
+	visited[def.ID] = true
+
+	for _, name := range def.formula.References() {
+		ref, ok := byName[name]
+		if !ok {
+			continue
+		}
+		if ref.ID == targetID {
+			return true
+		}
+		if ref.Type == PropTypeFormula && s.formulaDependsOn(targetID, ref, byName, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedDefs returns the property definitions in the order of the board.
+func (s PropSchema) sortedDefs() []PropDef {
+	defs := make([]PropDef, 0, len(s))
+	for _, def := range s {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Index < defs[j].Index
+	})
+	return defs
+}
+
+// propertiesByName maps the names of the properties to their definitions.
+// If several properties have the same name, the first one is used.
+func (s PropSchema) propertiesByName() map[string]PropDef {
+	byName := make(map[string]PropDef, len(s))
+	for _, def := range s.sortedDefs() {
+		if _, ok := byName[def.Name]; !ok {
+			byName[def.Name] = def
+		}
+	}
+	return byName
+}
+
+// formulaCard is what the formulas are evaluated from.
+type formulaCard struct {
+	properties map[string]interface{}
+	createdBy  string
+	modifiedBy string
+	createAt   int64
+	updateAt   int64
+}
+
+func formulaCardFromBlock(block *Block) formulaCard {
+	properties, _ := block.Fields["properties"].(map[string]interface{})
+	return formulaCard{
+		properties: properties,
+		createdBy:  block.CreatedBy,
+		modifiedBy: block.ModifiedBy,
+		createAt:   block.CreateAt,
+		updateAt:   block.UpdateAt,
+	}
+}
+
+// evaluateFormulas returns a copy of the properties of a card with the
+// values of the formula properties.
+func (s PropSchema) evaluateFormulas(card formulaCard, fctx FormulaContext) map[string]interface{} {
+	if fctx.Now == 0 {
+		fctx.Now = utils.GetMillis()
+	}
+	if fctx.Location == nil {
+		fctx.Location = time.UTC
+	}
+
+	env := &formulaEnv{
+		schema:     s,
+		byName:     s.propertiesByName(),
+		card:       card,
+		now:        fctx.Now,
+		location:   fctx.Location,
+		values:     map[string]interface{}{},
+		evaluating: map[string]bool{},
+	}
+
+	properties := make(map[string]interface{}, len(card.properties))
+	for k, v := range card.properties {
+		properties[k] = v
+	}
+
+	for id, def := range s {
+		if def.Type != PropTypeFormula {
+			continue
+		}
+		value, err := env.formulaValue(def)
+		if err != nil || value == nil {
+			delete(properties, id)
+			continue
+		}
+		properties[id] = value
+	}
+	return properties
+}
+
+type formulaEnv struct {
+	schema     PropSchema
+	byName     map[string]PropDef
+	card       formulaCard
+	now        int64
+	location   *time.Location
+	values     map[string]interface{}
+	evaluating map[string]bool
+}
+
+// formulaValue evaluates the formula of a property once per card.
+func (env *formulaEnv) formulaValue(def PropDef) (interface{}, error) {
+	if value, ok := env.values[def.ID]; ok {
+		return value, nil
+	}
+	if def.formulaErr != nil {
+		return nil, def.formulaErr
+	}
+	if def.formula == nil || def.formula.root == nil {
+		return nil, nil
+	}
+	if env.evaluating[def.ID] {
+		return nil, fmt.Errorf("%w: %s depends on itself", ErrInvalidFormula, def.Name)
+	}
+
+	env.evaluating[def.ID] = true
+	value, err := def.formula.root.eval(env)
+	delete(env.evaluating, def.ID)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := value.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return nil, fmt.Errorf("%w: %s is not a finite number", ErrInvalidFormula, def.Name)
+	}
+
+	env.values[def.ID] = value
+	return value, nil
+}
+
+// propertyValue converts the value of a property of the card to a formula
+// value: a number, a string, a boolean or nil.
+func (env *formulaEnv) propertyValue(name string) (interface{}, error) {
+	def, ok := env.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown property %q", ErrInvalidFormula, name)
+	}
+
+	switch def.Type {
+	case PropTypeFormula:
+		return env.formulaValue(def)
+	case "createdTime":
+		return float64(env.card.createAt), nil
+	case "updatedTime":
+		return float64(env.card.updateAt), nil
+	case "createdBy":
+		return env.card.createdBy, nil
+	case "updatedBy":
+		return env.card.modifiedBy, nil
+	}
+
+	v, ok := env.card.properties[def.ID]
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	switch def.Type {
+	case "number":
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case string:
+			if strings.TrimSpace(n) == "" {
+				return nil, nil
+			}
+			return formulaToNumber(n)
+		}
+	case "checkbox":
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			return b == "true", nil
+		}
+	case "select":
+		if id, ok := v.(string); ok {
+			return def.Options[id].Value, nil
+		}
+	case "multiSelect", "multiPerson":
+		if items, ok := v.([]interface{}); ok {
+			values := make([]string, 0, len(items))
+			for _, item := range items {
+				s := fmt.Sprintf("%v", item)
+				if opt, ok := def.Options[s]; ok {
+					s = opt.Value
+				}
+				values = append(values, s)
+			}
+			return strings.Join(values, ", "), nil
+		}
+	case "date":
+		if s, ok := v.(string); ok {
+			var date map[string]int64
+			if err := json.Unmarshal([]byte(s), &date); err != nil {
+				return nil, fmt.Errorf("%w: invalid date in %q", ErrInvalidFormula, name)
+			}
+			from, ok := date["from"]
+			if !ok {
+				return nil, nil
+			}
+			return float64(from), nil
+		}
+	}
+
+	switch value := v.(type) {
+	case float64, bool, string:
+		return value, nil
+	default:
+		return fmt.Sprintf("%v", value), nil
+	}
+}
+
+// formatFormulaValue returns the text of a formula value.
+func formatFormulaValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case string:
+		return value
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func formulaToNumber(v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return value, nil
+	case bool:
+		if value {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is not a number", ErrInvalidFormula, value)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("%w: %v is not a number", ErrInvalidFormula, value)
+	}
+}
+
+func formulaToBool(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != ""
+	default:
+		return true
+	}
+}
+
+// Tokenizer
+
+type formulaTokenKind int
+
+const (
+	formulaTokenEOF formulaTokenKind = iota
+	formulaTokenNumber
+	formulaTokenString
+	formulaTokenIdent
+	formulaTokenOperator
+)
+
+type formulaToken struct {
+	kind  formulaTokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+var formulaOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "<", ">", "!", "(", ")", ","}
+
+func tokenizeFormula(expr string) ([]formulaToken, error) {
+	tokens := []formulaToken{}
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			n, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid number %q at position %d", ErrInvalidFormula, text, start)
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenNumber, text: text, value: n, pos: start})
+
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == r {
+					closed = true
+					i++
+					break
+				}
+				if c == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(runes[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteRune(c)
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidFormula, start)
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenString, text: string(runes[start:i]), value: sb.String(), pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: formulaTokenIdent, text: string(runes[start:i]), pos: start})
+
+		default:
+			matched := false
+			for _, op := range formulaOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, formulaToken{kind: formulaTokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q at position %d", ErrInvalidFormula, r, i)
+			}
+		}
+	}
+
+	return append(tokens, formulaToken{kind: formulaTokenEOF, pos: len(runes)}), nil
+}
+
+// Parser
+
+// formulaPrecedence lists the binary operators from the lowest to the
+// highest precedence.
+var formulaPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type formulaParser struct {
+	tokens     []formulaToken
+	pos        int
+	depth      int
+	references []string
+}
+
+func (p *formulaParser) peek() formulaToken {
+	return p.tokens[p.pos]
+}
+
+func (p *formulaParser) next() formulaToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != formulaTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *formulaParser) acceptOperator(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != formulaTokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *formulaParser) expectOperator(op string) error {
+	if _, ok := p.acceptOperator(op); !ok {
+		tok := p.peek()
+		if tok.kind == formulaTokenEOF {
+			return fmt.Errorf("%w: expected %q at the end", ErrInvalidFormula, op)
+		}
+		return fmt.Errorf("%w: expected %q at position %d", ErrInvalidFormula, op, tok.pos)
+	}
+	return nil
+}
+
+func (p *formulaParser) enter() error {
+	p.depth++
+	if p.depth > maxFormulaDepth {
+		return fmt.Errorf("%w: nested too deeply", ErrInvalidFormula)
+	}
+	return nil
+}
+
+func (p *formulaParser) parseExpression() (formulaNode, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+	return p.parseBinary(0)
+}
+
+func (p *formulaParser) parseBinary(level int) (formulaNode, error) {
+	if level == len(formulaPrecedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator(formulaPrecedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *formulaParser) parseUnary() (formulaNode, error) {
+	if op, ok := p.acceptOperator("-", "!"); ok {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer func() { p.depth-- }()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &formulaUnary{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *formulaParser) parsePrimary() (formulaNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case formulaTokenNumber, formulaTokenString:
+		return &formulaLiteral{value: tok.value}, nil
+
+	case formulaTokenIdent:
+		switch tok.text {
+		case "true":
+			return &formulaLiteral{value: true}, nil
+		case "false":
+			return &formulaLiteral{value: false}, nil
+		}
+		return p.parseCall(tok)
+
+	case formulaTokenOperator:
+		if tok.text == "(" {
+			node, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidFormula, tok.text, tok.pos)
+
+	default:
+		return nil, fmt.Errorf("%w: unexpected end of formula", ErrInvalidFormula)
+	}
+}
+
+func (p *formulaParser) parseCall(name formulaToken) (formulaNode, error) {
+	fn, isFunction := formulaFunctions[name.text]
+	if name.text != "prop" && name.text != "if" && !isFunction {
+		return nil, fmt.Errorf("%w: unknown function %q at position %d", ErrInvalidFormula, name.text, name.pos)
+	}
+	if err := p.expectOperator("("); err != nil {
+		return nil, err
+	}
+
+	args := []formulaNode{}
+	if _, ok := p.acceptOperator(")"); !ok {
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.acceptOperator(","); ok {
+				continue
+			}
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	switch name.text {
+	case "prop":
+		literal, ok := formulaStringLiteral(args)
+		if !ok {
+			return nil, fmt.Errorf("%w: prop expects the name of a property at position %d", ErrInvalidFormula, name.pos)
+		}
+		p.references = append(p.references, literal)
+		return &formulaProp{name: literal}, nil
+	case "if":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("%w: if expects 3 arguments at position %d", ErrInvalidFormula, name.pos)
+		}
+		return &formulaIf{condition: args[0], then: args[1], otherwise: args[2]}, nil
+	}
+
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("%w: wrong number of arguments for %s at position %d", ErrInvalidFormula, name.text, name.pos)
+	}
+	return &formulaCall{name: name.text, fn: fn, args: args}, nil
+}
+
+func formulaStringLiteral(args []formulaNode) (string, bool) {
+	if len(args) != 1 {
+		return "", false
+	}
+	literal, ok := args[0].(*formulaLiteral)
+	if !ok {
+		return "", false
+	}
+	s, ok := literal.value.(string)
+	return s, ok
+}
+
+// Evaluation
+
+type formulaNode interface {
+	eval(env *formulaEnv) (interface{}, error)
+}
+
+type formulaLiteral struct {
+	value interface{}
+}
+
+func (n *formulaLiteral) eval(_ *formulaEnv) (interface{}, error) {
+	return n.value, nil
+}
+
+type formulaProp struct {
+	name string
+}
+
+func (n *formulaProp) eval(env *formulaEnv) (interface{}, error) {
+	return env.propertyValue(n.name)
+}
+
+type formulaIf struct {
+	condition formulaNode
+	then      formulaNode
+	otherwise formulaNode
+}
+
+func (n *formulaIf) eval(env *formulaEnv) (interface{}, error) {
+	condition, err := n.condition.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if formulaToBool(condition) {
+		return n.then.eval(env)
+	}
+	return n.otherwise.eval(env)
+}
+
+type formulaUnary struct {
+	op      string
+	operand formulaNode
+}
+
+func (n *formulaUnary) eval(env *formulaEnv) (interface{}, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !formulaToBool(value), nil
+	}
+	number, err := formulaToNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	return -number, nil
+}
+
+type formulaBinary struct {
+	op    string
+	left  formulaNode
+	right formulaNode
+}
+
+func (n *formulaBinary) eval(env *formulaEnv) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// logical operators short-circuit
+	switch n.op {
+	case "&&":
+		if !formulaToBool(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(env)
+		return formulaToBool(right), err
+	case "||":
+		if formulaToBool(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(env)
+		return formulaToBool(right), err
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return formulaEqual(left, right), nil
+	case "!=":
+		return !formulaEqual(left, right), nil
+	case "+":
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		if leftIsString || rightIsString {
+			ls, rs := formatFormulaValue(left), formatFormulaValue(right)
+			if err := checkFormulaTextLength(utf8.RuneCountInString(ls) + utf8.RuneCountInString(rs)); err != nil {
+				return nil, err
+			}
+			return ls + rs, nil
+		}
+	case "<", "<=", ">", ">=":
+		ls, leftIsString := left.(string)
+		rs, rightIsString := right.(string)
+		if leftIsString && rightIsString {
+			return formulaCompare(n.op, strings.Compare(ls, rs)), nil
+		}
+	}
+
+	l, err := formulaToNumber(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := formulaToNumber(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrInvalidFormula)
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrInvalidFormula)
+		}
+		return math.Mod(l, r), nil
+	default:
+		cmp := 0
+		if l < r {
+			cmp = -1
+		} else if l > r {
+			cmp = 1
+		}
+		return formulaCompare(n.op, cmp), nil
+	}
+}
+
+func formulaEqual(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+	l, leftIsNumber := left.(float64)
+	r, rightIsNumber := right.(float64)
+	if leftIsNumber && rightIsNumber {
+		return l == r
+	}
+	return formatFormulaValue(left) == formatFormulaValue(right)
+}
+
+func formulaCompare(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+type formulaCall struct {
+	name string
+	fn   formulaFunction
+	args []formulaNode
+}
+
+func (n *formulaCall) eval(env *formulaEnv) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	value, err := n.fn.call(env, args)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := value.(string); ok {
+		if err := checkFormulaTextLength(utf8.RuneCountInString(s)); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// checkFormulaTextLength checks the number of characters of a text value
+// computed by a formula. The functions building long values check it before
+// building them.
+func checkFormulaTextLength(length int) error {
+	if length > maxFormulaTextLength {
+		return fmt.Errorf("%w: text longer than %d characters", ErrInvalidFormula, maxFormulaTextLength)
+	}
+	return nil
+}
+
+// Functions
+
+type formulaFunction struct {
+	minArgs int
+	// maxArgs is -1 for the functions with any number of arguments.
+	maxArgs int
+	call    func(env *formulaEnv, args []interface{}) (interface{}, error)
+}
+
+var formulaDateUnits = map[string]float64{
+	"minute": 60 * 1000,
+	"hour":   60 * 60 * 1000,
+	"day":    24 * 60 * 60 * 1000,
+	"week":   7 * 24 * 60 * 60 * 1000,
+}
+
+func formulaDateUnit(v interface{}) (float64, error) {
+	unit := strings.TrimSuffix(strings.ToLower(formatFormulaValue(v)), "s")
+	ms, ok := formulaDateUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown date unit %q", ErrInvalidFormula, formatFormulaValue(v))
+	}
+	return ms, nil
+}
+
+func formulaNumbers(args []interface{}) ([]float64, error) {
+	numbers := make([]float64, len(args))
+	for i, arg := range args {
+		n, err := formulaToNumber(arg)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+func formulaMathFunction(f func(float64) float64) formulaFunction {
+	return formulaFunction{minArgs: 1, maxArgs: 1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		n, err := formulaToNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return f(n), nil
+	}}
+}
+
+func formulaStringFunction(f func(string) interface{}) formulaFunction {
+	return formulaFunction{minArgs: 1, maxArgs: 1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		return f(formatFormulaValue(args[0])), nil
+	}}
+}
+
+var formulaFunctions = map[string]formulaFunction{
+	"concat": {minArgs: 1, maxArgs: -1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		values := make([]string, len(args))
+		length := 0
+		for i, arg := range args {
+			values[i] = formatFormulaValue(arg)
+			length += utf8.RuneCountInString(values[i])
+		}
+		if err := checkFormulaTextLength(length); err != nil {
+			return nil, err
+		}
+		return strings.Join(values, ""), nil
+	}},
+	"length": formulaStringFunction(func(s string) interface{} { return float64(len([]rune(s))) }),
+	"upper":  formulaStringFunction(func(s string) interface{} { return strings.ToUpper(s) }),
+	"lower":  formulaStringFunction(func(s string) interface{} { return strings.ToLower(s) }),
+	"trim":   formulaStringFunction(func(s string) interface{} { return strings.TrimSpace(s) }),
+	"empty": {minArgs: 1, maxArgs: 1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		return formatFormulaValue(args[0]) == "", nil
+	}},
+	"contains": {minArgs: 2, maxArgs: 2, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		return strings.Contains(formatFormulaValue(args[0]), formatFormulaValue(args[1])), nil
+	}},
+	"replace": {minArgs: 3, maxArgs: 3, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		text, old, replacement := formatFormulaValue(args[0]), formatFormulaValue(args[1]), formatFormulaValue(args[2])
+		// an empty old text matches before every character
+		matches := strings.Count(text, old)
+		length := utf8.RuneCountInString(text) + matches*(utf8.RuneCountInString(replacement)-utf8.RuneCountInString(old))
+		if err := checkFormulaTextLength(length); err != nil {
+			return nil, err
+		}
+		return strings.ReplaceAll(text, old, replacement), nil
+	}},
+	"format": {minArgs: 1, maxArgs: 1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		return formatFormulaValue(args[0]), nil
+	}},
+	"toNumber": {minArgs: 1, maxArgs: 1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		return formulaToNumber(args[0])
+	}},
+	"abs":   formulaMathFunction(math.Abs),
+	"floor": formulaMathFunction(math.Floor),
+	"ceil":  formulaMathFunction(math.Ceil),
+	"round": {minArgs: 1, maxArgs: 2, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		numbers, err := formulaNumbers(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == 1 {
+			return math.Round(numbers[0]), nil
+		}
+		scale := math.Pow(10, math.Round(numbers[1]))
+		return math.Round(numbers[0]*scale) / scale, nil
+	}},
+	"min": {minArgs: 1, maxArgs: -1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		numbers, err := formulaNumbers(args)
+		if err != nil {
+			return nil, err
+		}
+		result := numbers[0]
+		for _, n := range numbers[1:] {
+			result = math.Min(result, n)
+		}
+		return result, nil
+	}},
+	"max": {minArgs: 1, maxArgs: -1, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		numbers, err := formulaNumbers(args)
+		if err != nil {
+			return nil, err
+		}
+		result := numbers[0]
+		for _, n := range numbers[1:] {
+			result = math.Max(result, n)
+		}
+		return result, nil
+	}},
+	"now": {minArgs: 0, maxArgs: 0, call: func(env *formulaEnv, _ []interface{}) (interface{}, error) {
+		return float64(env.now), nil
+	}},
+	"dateAdd": {minArgs: 3, maxArgs: 3, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		numbers, err := formulaNumbers(args[:2])
+		if err != nil {
+			return nil, err
+		}
+		unit, err := formulaDateUnit(args[2])
+		if err != nil {
+			return nil, err
+		}
+		return numbers[0] + numbers[1]*unit, nil
+	}},
+	"dateBetween": {minArgs: 3, maxArgs: 3, call: func(_ *formulaEnv, args []interface{}) (interface{}, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		numbers, err := formulaNumbers(args[:2])
+		if err != nil {
+			return nil, err
+		}
+		unit, err := formulaDateUnit(args[2])
+		if err != nil {
+			return nil, err
+		}
+		return math.Trunc((numbers[0] - numbers[1]) / unit), nil
+	}},
+	"formatDate": {minArgs: 1, maxArgs: 1, call: func(env *formulaEnv, args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		date, err := formulaToNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return utils.GetTimeForMillis(int64(date)).In(env.location).Format("January 02, 2006"), nil
+	}},
+}
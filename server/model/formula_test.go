@@ -0,0 +1,297 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formulaTestBoard(formulas map[string]string) *Board {
+	board := &Board{
+		ID: "board_id",
+		CardProperties: []map[string]interface{}{
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "spent", "name": "Spent", "type": "number"},
+			{"id": "name", "name": "Owner Name", "type": "text"},
+			{"id": "done", "name": "Done", "type": "checkbox"},
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_done", "value": "Done"},
+				map[string]interface{}{"id": "status_todo", "value": "To Do"},
+			}},
+			{"id": "start", "name": "Start", "type": "date"},
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "created", "name": "Created", "type": "createdTime"},
+		},
+	}
+	for _, name := range []string{"f1", "f2", "f3"} {
+		if formula, ok := formulas[name]; ok {
+			board.CardProperties = append(board.CardProperties, map[string]interface{}{
+				"id": name, "name": strings.ToUpper(name), "type": PropTypeFormula, "formula": formula,
+			})
+		}
+	}
+	return board
+}
+
+func formulaTestCard() *Card {
+	return &Card{
+		BoardID:  "board_id",
+		CreateAt: 1000,
+		UpdateAt: 2000,
+		Properties: map[string]interface{}{
+			"estimate": "8",
+			"spent":    "6",
+			"name":     "Jane",
+			"done":     "true",
+			"status":   "status_todo",
+			"start":    `{"from":1672531200000}`,
+			"due":      `{"from":1673136000000}`,
+			"f1":       "stale value",
+		},
+	}
+}
+
+func evaluateTestFormula(t *testing.T, formula string) interface{} {
+	t.Helper()
+	board := formulaTestBoard(map[string]string{"f1": formula})
+	require.NoError(t, ValidateFormulas(board))
+
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	card := formulaTestCard()
+	EvaluateCardFormulas(card, schema, FormulaContext{})
+	return card.Properties["f1"]
+}
+
+func TestParseFormula(t *testing.T) {
+	t.Run("valid formulas", func(t *testing.T) {
+		for _, expr := range []string{
+			"",
+			"1 + 2 * 3",
+			`prop("Estimate") - prop("Spent")`,
+			`if(prop("Done"), "yes", 'no')`,
+			`!(1 < 2) || true && false`,
+			`concat("a", "b", 3)`,
+			`dateBetween(prop("Due"), prop("Start"), "days")`,
+		} {
+			_, err := ParseFormula(expr)
+			assert.NoError(t, err, expr)
+		}
+	})
+
+	t.Run("invalid formulas", func(t *testing.T) {
+		for _, expr := range []string{
+			"1 +",
+			"(1 + 2",
+			"1 2",
+			`"unterminated`,
+			"unknown(1)",
+			"prop(1)",
+			`prop("a", "b")`,
+			"if(true, 1)",
+			"round()",
+			"now(1)",
+			"1 $ 2",
+			strings.Repeat("(", 60) + "1" + strings.Repeat(")", 60),
+			strings.Repeat("1+", 600) + "1",
+		} {
+			_, err := ParseFormula(expr)
+			assert.ErrorIs(t, err, ErrInvalidFormula, expr)
+		}
+	})
+
+	t.Run("references", func(t *testing.T) {
+		formula, err := ParseFormula(`prop("Estimate") + if(prop("Done"), 1, prop("Spent"))`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Estimate", "Done", "Spent"}, formula.References())
+	})
+}
+
+func TestEvaluateFormulas(t *testing.T) {
+	testCases := []struct {
+		formula  string
+		expected interface{}
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"-2 + 10 % 4", 0.0},
+		{`prop("Estimate") - prop("Spent")`, 2.0},
+		{`prop("Spent") / prop("Estimate") * 100`, 75.0},
+		{`round(prop("Spent") / 7, 2)`, 0.86},
+		{`max(prop("Estimate"), prop("Spent"), 10)`, 10.0},
+		{`"Owner: " + prop("Owner Name")`, "Owner: Jane"},
+		{`upper(prop("Owner Name")) + length(prop("Owner Name"))`, "JANE4"},
+		{`contains(prop("Status"), "Do")`, true},
+		{`if(prop("Done"), "finished", "open")`, "finished"},
+		{`if(prop("Status") == "Done", 1, 0)`, 0.0},
+		{`prop("Estimate") > prop("Spent") && !prop("Done")`, false},
+		{`dateBetween(prop("Due"), prop("Start"), "days")`, 7.0},
+		{`dateBetween(prop("Due"), prop("Start"), "weeks")`, 1.0},
+		{`formatDate(dateAdd(prop("Start"), 2, "days"))`, "January 03, 2023"},
+		{`prop("Created") + 1`, 1001.0},
+		{`empty(prop("Owner Name"))`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.formula, func(t *testing.T) {
+			assert.Equal(t, tc.expected, evaluateTestFormula(t, tc.formula))
+		})
+	}
+
+	t.Run("runtime errors have no value", func(t *testing.T) {
+		assert.Nil(t, evaluateTestFormula(t, "1 / 0"))
+		assert.Nil(t, evaluateTestFormula(t, `prop("Owner Name") * 2`))
+		assert.Nil(t, evaluateTestFormula(t, `dateAdd(prop("Start"), 1, "years")`))
+	})
+
+	t.Run("text values are limited in length", func(t *testing.T) {
+		expr := `"x"`
+		for i := 0; i < 10; i++ {
+			expr = `replace(` + expr + `, "", "xxxxxxxxxx")`
+		}
+		assert.Nil(t, evaluateTestFormula(t, expr))
+
+		long := `replace(replace(replace(replace(replace("x", "", "xxxxxxxxxx"), "", "xxxxxxxxxx"), "", "xxxxxxxxxx"), "", "xxxxxxxxxx"), "", "xxxxxxxxxx")`
+		assert.Nil(t, evaluateTestFormula(t, `concat(`+long+`, `+long+`, `+long+`, `+long+`, `+long+`)`))
+		assert.Nil(t, evaluateTestFormula(t, long+` + `+long+` + `+long+` + `+long+` + `+long))
+		assert.Equal(t, "xaxbx", evaluateTestFormula(t, `replace("ab", "", "x")`))
+	})
+
+	t.Run("dates are formatted in the time zone of the context", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{"f1": `formatDate(prop("Start"))`})
+		schema, err := ParsePropertySchema(board)
+		require.NoError(t, err)
+
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		card := formulaTestCard()
+		EvaluateCardFormulas(card, schema, NewFormulaContext(loc))
+		assert.Equal(t, "December 31, 2022", card.Properties["f1"])
+
+		card = formulaTestCard()
+		EvaluateCardFormulas(card, schema, NewFormulaContext(nil))
+		assert.Equal(t, "January 01, 2023", card.Properties["f1"])
+	})
+
+	t.Run("empty formula has no value", func(t *testing.T) {
+		assert.Nil(t, evaluateTestFormula(t, ""))
+	})
+
+	t.Run("formulas referencing formulas", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{
+			"f1": `prop("F2") * 2`,
+			"f2": `prop("Estimate") + 1`,
+		})
+		schema, err := ParsePropertySchema(board)
+		require.NoError(t, err)
+
+		card := formulaTestCard()
+		EvaluateCardFormulas(card, schema, FormulaContext{})
+		assert.Equal(t, 18.0, card.Properties["f1"])
+		assert.Equal(t, 9.0, card.Properties["f2"])
+	})
+
+	t.Run("block formulas don't modify the block", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{"f1": `prop("Estimate") * 2`})
+		schema, err := ParsePropertySchema(board)
+		require.NoError(t, err)
+
+		block := Card2Block(formulaTestCard())
+		evaluated := EvaluateBlockFormulas(block, schema, FormulaContext{})
+		assert.Equal(t, 16.0, evaluated.Fields["properties"].(map[string]interface{})["f1"])
+		assert.Equal(t, "stale value", block.Fields["properties"].(map[string]interface{})["f1"])
+	})
+}
+
+func TestValidateFormulas(t *testing.T) {
+	t.Run("valid formulas", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{
+			"f1": `prop("F2") + prop("Estimate")`,
+			"f2": `prop("Spent")`,
+		})
+		require.NoError(t, ValidateFormulas(board))
+	})
+
+	t.Run("invalid formulas", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{
+			"f1": `prop("Missing")`,
+			"f2": `1 +`,
+		})
+		err := ValidateFormulas(board)
+		require.True(t, IsErrBadRequest(err))
+		assert.Contains(t, err.Error(), `F1: unknown property "Missing"`)
+		assert.Contains(t, err.Error(), "F2: invalid formula")
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{
+			"f1": `prop("F2")`,
+			"f2": `prop("F3") + 1`,
+			"f3": `prop("F1")`,
+		})
+		err := ValidateFormulas(board)
+		require.True(t, IsErrBadRequest(err))
+		assert.Contains(t, err.Error(), "F1: the formula depends on itself")
+		assert.Contains(t, err.Error(), "F3: the formula depends on itself")
+
+		// the cycle is also detected when evaluating
+		schema, err := ParsePropertySchema(board)
+		require.NoError(t, err)
+		card := formulaTestCard()
+		EvaluateCardFormulas(card, schema, FormulaContext{})
+		assert.NotContains(t, card.Properties, "f1")
+	})
+}
+
+func TestParsePropertiesWithFormulas(t *testing.T) {
+	board := formulaTestBoard(map[string]string{"f1": `prop("Estimate") - prop("Spent")`})
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	oldBlock := Card2Block(formulaTestCard())
+	newCard := formulaTestCard()
+	newCard.Properties["spent"] = "7"
+	newBlock := Card2Block(newCard)
+
+	fctx := NewFormulaContext(nil)
+	oldProps, err := ParseProperties(oldBlock, schema, nil, fctx)
+	require.NoError(t, err)
+	newProps, err := ParseProperties(newBlock, schema, nil, fctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2", oldProps["f1"].Value)
+
+	diffs := DiffProperties(oldProps, newProps)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, "Spent", diffs[0].Name)
+	assert.Equal(t, "F1", diffs[1].Name)
+	assert.Equal(t, "2", diffs[1].OldValue)
+	assert.Equal(t, "1", diffs[1].NewValue)
+
+	t.Run("now is the same for both blocks", func(t *testing.T) {
+		board := formulaTestBoard(map[string]string{"f1": `now()`})
+		schema, err := ParsePropertySchema(board)
+		require.NoError(t, err)
+
+		fctx := FormulaContext{Now: 1000}
+		oldProps, err := ParseProperties(oldBlock, schema, nil, fctx)
+		require.NoError(t, err)
+		fctx.Now = 2000
+		changedProps, err := ParseProperties(oldBlock, schema, nil, fctx)
+		require.NoError(t, err)
+		require.Len(t, DiffProperties(oldProps, changedProps), 1)
+
+		fctx = NewFormulaContext(nil)
+		oldProps, err = ParseProperties(oldBlock, schema, nil, fctx)
+		require.NoError(t, err)
+		newProps, err := ParseProperties(oldBlock, schema, nil, fctx)
+		require.NoError(t, err)
+		assert.Empty(t, DiffProperties(oldProps, newProps))
+	})
+}
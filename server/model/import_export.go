@@ -22,6 +22,36 @@ type Archive struct {
 type ArchiveHeader struct {
 	Version int   `json:"version"`
 	Date    int64 `json:"date"`
+
+	// Incremental is true if the archive only contains the changes made
+	// within the cursor range, in which case it must be imported on top
+	// of the archives it follows.
+	Incremental bool `json:"incremental,omitempty"`
+
+	// ModifiedSince is the start of the cursor range of an incremental
+	// archive, in milliseconds since the epoch.
+	ModifiedSince int64 `json:"modifiedSince,omitempty"`
+
+	// ModifiedUntil is the end of the cursor range of the archive, in
+	// milliseconds since the epoch, to be used as the start of the next
+	// incremental export.
+	ModifiedUntil int64 `json:"modifiedUntil,omitempty"`
+}
+
+// Archive tombstone types.
+const (
+	ArchiveTombstoneBoard = "board"
+	ArchiveTombstoneBlock = "block"
+)
+
+// ArchiveTombstone is a board or a block deleted within the cursor range
+// of an incremental archive. Tombstones are written to the
+// `tombstones.jsonl` file, at the end of the archive.
+type ArchiveTombstone struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	BoardID  string `json:"boardId"`
+	DeleteAt int64  `json:"deleteAt"`
 }
 
 // ArchiveLine is any line in an archive.
@@ -38,6 +68,25 @@ type ExportArchiveOptions struct {
 	// BoardIDs is the list of boards to include in the archive.
 	// Empty slice means export all boards from workspace/team.
 	BoardIDs []string
+
+	// ModifiedSince makes the export incremental if it isn't zero: only
+	// the boards and blocks changed at or after this time, in
+	// milliseconds since the epoch, are included, along with tombstones
+	// for the blocks deleted since then.
+	ModifiedSince int64
+
+	// IncludeDeletedBoards adds tombstones for the boards of the team
+	// deleted since ModifiedSince to an incremental export, limited to the
+	// boards UserID could view. It should only be set when exporting a
+	// whole team.
+	IncludeDeletedBoards bool
+
+	// UserID is the user exporting the boards.
+	UserID string
+
+	// IncludePublicBoards tells if the user can view the open boards of
+	// the team without being a member of them.
+	IncludePublicBoards bool
 }
 
 // ImportArchiveOptions provides options when importing an archive.
@@ -46,6 +95,13 @@ type ImportArchiveOptions struct {
 	ModifiedBy    string
 	BoardModifier BoardModifier
 	BlockModifier BlockModifier
+
+	// Incremental keeps the IDs of the boards and blocks of the archive,
+	// updating the existing ones instead of creating copies, and applies
+	// the tombstones of incremental archives. A full archive must be
+	// imported this way for the incremental archives that follow it to
+	// be applied.
+	Incremental bool
 }
 
 // ErrUnsupportedArchiveVersion is an error returned when trying to import an
@@ -0,0 +1,381 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// IncomingWebhookPayloadMaxSize is the maximum size in bytes of the
+	// payload posted to an incoming webhook.
+	IncomingWebhookPayloadMaxSize = 1 << 20
+
+	// IncomingWebhookPayloadMaxDepth is the depth up to which the fields
+	// of a payload get a placeholder. The deeper objects and arrays are
+	// only available as JSON.
+	IncomingWebhookPayloadMaxDepth = 8
+
+	// PlaceholderPayload is the placeholder of the payload posted to an
+	// incoming webhook, as JSON. Its fields are {{payload.field}},
+	// {{payload.object.field}} and {{payload.array.0}}.
+	PlaceholderPayload = "payload"
+
+	IncomingWebhookNameMaxLength = 255
+)
+
+// computedPropTypes are the types of the properties whose values are
+// computed, which can't be set.
+var computedPropTypes = map[string]bool{
+	"createdTime":   true,
+	"createdBy":     true,
+	"updatedTime":   true,
+	"updatedBy":     true,
+	PropTypeFormula: true,
+	PropTypeRollup:  true,
+}
+
+// IncomingWebhook creates or updates a card of a board from the JSON
+// payloads posted to its URL by other systems, as the user that created
+// it. The requests are authenticated by the token of the webhook.
+// swagger:model
+type IncomingWebhook struct {
+	// The ID of the webhook, part of its URL
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the board the cards are created in
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The name of the webhook
+	// required: false
+	Name string `json:"name"`
+
+	// The token the requests must send, only returned when the webhook is
+	// created
+	// required: false
+	Token string `json:"token,omitempty"`
+
+	// How the payload is mapped to the card
+	// required: true
+	Mapping IncomingWebhookMapping `json:"mapping"`
+
+	// True if the requests are rejected
+	// required: false
+	Disabled bool `json:"disabled"`
+
+	// The ID of the user that created the webhook, who the cards are
+	// created and updated by
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The ID of the user that last modified the webhook
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IncomingWebhookMapping maps the payload of an incoming webhook to a
+// card. Its values are templates whose placeholders are resolved like the
+// ones of a card template, with {{payload}} and its fields. The
+// placeholders of missing fields are removed.
+// swagger:model
+type IncomingWebhookMapping struct {
+	// The template of the title of the card
+	// required: true
+	Title string `json:"title"`
+
+	// The templates of the property values of the card, by property ID.
+	// The values of select properties can be the names of their options.
+	// required: false
+	Properties map[string]interface{} `json:"properties"`
+
+	// The template of the text added to the content of the cards created
+	// required: false
+	Content string `json:"content"`
+
+	// The ID of the property holding the external ID of the cards. If set,
+	// the card with the same external ID is updated instead of creating a
+	// new one.
+	// required: false
+	ExternalIDProperty string `json:"externalIdProperty"`
+
+	// The template of the external ID of the card, required with the
+	// external ID property
+	// required: false
+	ExternalID string `json:"externalId"`
+}
+
+// IncomingWebhookPatch is a patch to modify an incoming webhook.
+// swagger:model
+type IncomingWebhookPatch struct {
+	// The new name
+	// required: false
+	Name *string `json:"name"`
+
+	// The new mapping
+	// required: false
+	Mapping *IncomingWebhookMapping `json:"mapping"`
+
+	// Disables or enables the webhook
+	// required: false
+	Disabled *bool `json:"disabled"`
+}
+
+// IncomingWebhookResult is the response to a payload posted to an
+// incoming webhook.
+// swagger:model
+type IncomingWebhookResult struct {
+	// The ID of the card created or updated
+	// required: true
+	CardID string `json:"cardId"`
+
+	// True if the card was created, false if it was updated
+	// required: true
+	Created bool `json:"created"`
+}
+
+// IncomingWebhookCard is the card a payload is mapped to.
+type IncomingWebhookCard struct {
+	Title      string
+	Properties map[string]interface{}
+	Content    string
+	ExternalID string
+}
+
+// Patch returns an updated version of the webhook.
+func (p *IncomingWebhookPatch) Patch(webhook *IncomingWebhook) *IncomingWebhook {
+	patched := *webhook
+	if p.Name != nil {
+		patched.Name = *p.Name
+	}
+	if p.Mapping != nil {
+		patched.Mapping = *p.Mapping
+	}
+	if p.Disabled != nil {
+		patched.Disabled = *p.Disabled
+	}
+	return &patched
+}
+
+// IsValid returns an error if the webhook is missing a field or has an
+// invalid mapping.
+func (w *IncomingWebhook) IsValid() error {
+	if w.BoardID == "" {
+		return NewErrBadRequest("missing board ID")
+	}
+	if utf8.RuneCountInString(w.Name) > IncomingWebhookNameMaxLength {
+		return NewErrBadRequest("the name is too long")
+	}
+	return w.Mapping.IsValid()
+}
+
+// Sanitize removes the token of the webhook, which is only returned when
+// the webhook is created.
+func (w *IncomingWebhook) Sanitize() {
+	w.Token = ""
+}
+
+// IsValid returns an error if the mapping has no title, or has property
+// values that aren't texts or lists of texts.
+func (m *IncomingWebhookMapping) IsValid() error {
+	if strings.TrimSpace(m.Title) == "" {
+		return NewErrBadRequest("missing title template")
+	}
+	if (m.ExternalIDProperty == "") != (strings.TrimSpace(m.ExternalID) == "") {
+		return NewErrBadRequest("the external ID property and the external ID template must be set together")
+	}
+	for id, value := range m.Properties {
+		if _, ok := value.(string); ok {
+			continue
+		}
+		if _, ok := stringList(value); !ok {
+			return NewErrBadRequest("the template of property " + id + " must be a text or a list of texts")
+		}
+	}
+	return nil
+}
+
+// IsValidForSchema returns an error if the mapping sets properties that
+// aren't in the card property schema or are computed, or if its external
+// ID property isn't a text or number property.
+func (m *IncomingWebhookMapping) IsValidForSchema(schema PropSchema) error {
+	for id := range m.Properties {
+		def, ok := schema[id]
+		if !ok {
+			return NewErrBadRequest("unknown property " + id)
+		}
+		if computedPropTypes[def.Type] {
+			return NewErrBadRequest("the computed property " + def.Name + " can't be mapped")
+		}
+	}
+	if m.ExternalIDProperty != "" {
+		def, ok := schema[m.ExternalIDProperty]
+		if !ok || (!textPropTypes[def.Type] && def.Type != "number") {
+			return NewErrBadRequest("the external ID property must be a text or number property of the board")
+		}
+	}
+	return nil
+}
+
+// Resolve returns the card a payload is mapped to. The property values
+// are converted to the types of their properties, and the empty ones are
+// left out so that they don't clear the values of an updated card.
+func (m *IncomingWebhookMapping) Resolve(ctx PlaceholderContext, schema PropSchema) *IncomingWebhookCard {
+	card := &IncomingWebhookCard{
+		Title:      strings.TrimSpace(ctx.Resolve(m.Title)),
+		Properties: make(map[string]interface{}, len(m.Properties)),
+		Content:    strings.TrimSpace(ctx.Resolve(m.Content)),
+		ExternalID: strings.TrimSpace(ctx.Resolve(m.ExternalID)),
+	}
+	for id, template := range m.Properties {
+		def := schema[id]
+		if value := resolvedPropertyValue(def, ctx.ResolveProperty(def, template)); value != nil {
+			card.Properties[id] = value
+		}
+	}
+	if m.ExternalIDProperty != "" && card.ExternalID != "" {
+		card.Properties[m.ExternalIDProperty] = card.ExternalID
+	}
+	return card
+}
+
+// resolvedPropertyValue converts a resolved property value to the type of
+// the property, returning nil for empty values.
+func resolvedPropertyValue(def PropDef, v interface{}) interface{} {
+	values, _ := stringList(v)
+	if s, ok := v.(string); ok {
+		values = []string{s}
+	}
+
+	items := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch def.Type {
+		case "select", "multiSelect":
+			value = def.optionID(value)
+		case "date":
+			value = incomingWebhookDate(value)
+		}
+		items = append(items, value)
+	}
+
+	switch {
+	case len(items) == 0:
+		return nil
+	case def.Type == "multiSelect" || def.Type == "multiPerson":
+		return items
+	case len(items) == 1:
+		return items[0]
+	}
+	// lists are joined for the properties holding a single value
+	texts := make([]string, 0, len(items))
+	for _, item := range items {
+		texts = append(texts, item.(string))
+	}
+	return strings.Join(texts, ", ")
+}
+
+// optionID returns the ID of the option of a select property with an ID
+// or a name, ignoring the case of the name. The value is returned as it
+// is if there's no such option.
+func (pd PropDef) optionID(value string) string {
+	if pd.hasOption(value) {
+		return value
+	}
+	for _, option := range pd.Options {
+		if strings.EqualFold(option.Value, value) {
+			return option.ID
+		}
+	}
+	return value
+}
+
+// incomingWebhookDate returns the value of a date property set to a
+// date of a payload: a day, an RFC 3339 time or a time in milliseconds
+// since the epoch. The value is returned as it is if it isn't a date.
+func incomingWebhookDate(value string) string {
+	if day, err := time.Parse(placeholderDateLayout, value); err == nil {
+		return DatePropertyValue(day)
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return DatePropertyValue(t.UTC())
+	}
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return DatePropertyValue(time.UnixMilli(millis).UTC())
+	}
+	return value
+}
+
+// ParseIncomingWebhookPayload decodes the JSON payload posted to an
+// incoming webhook into the values of its placeholders: {{payload}} is
+// the payload itself and {{payload.field}} the values of its fields.
+// Objects and arrays are JSON, and null fields are empty.
+func ParseIncomingWebhookPayload(data []byte) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, NewErrBadRequest("the payload must be JSON: " + err.Error())
+	}
+	if decoder.More() {
+		return nil, NewErrBadRequest("the payload must be a single JSON value")
+	}
+
+	variables := map[string]string{}
+	addPayloadVariables(variables, PlaceholderPayload, payload, 0)
+	return variables, nil
+}
+
+func addPayloadVariables(variables map[string]string, name string, v interface{}, depth int) {
+	switch value := v.(type) {
+	case nil:
+		variables[name] = ""
+	case string:
+		variables[name] = value
+	case json.Number:
+		variables[name] = value.String()
+	case bool:
+		variables[name] = strconv.FormatBool(value)
+	case map[string]interface{}:
+		variables[name] = payloadJSON(value)
+		if depth < IncomingWebhookPayloadMaxDepth {
+			for key, item := range value {
+				addPayloadVariables(variables, name+"."+key, item, depth+1)
+			}
+		}
+	case []interface{}:
+		variables[name] = payloadJSON(value)
+		if depth < IncomingWebhookPayloadMaxDepth {
+			for i, item := range value {
+				addPayloadVariables(variables, name+"."+strconv.Itoa(i), item, depth+1)
+			}
+		}
+	}
+}
+
+func payloadJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncomingWebhookIsValid(t *testing.T) {
+	valid := func() *IncomingWebhook {
+		return &IncomingWebhook{
+			BoardID: "board-1",
+			Mapping: IncomingWebhookMapping{
+				Title:      "{{payload.alert}}",
+				Properties: map[string]interface{}{"severity": "{{payload.severity}}", "tags": []interface{}{"alert", "{{payload.team}}"}},
+			},
+		}
+	}
+	require.NoError(t, valid().IsValid())
+
+	testCases := []struct {
+		name   string
+		modify func(w *IncomingWebhook)
+	}{
+		{"missing board", func(w *IncomingWebhook) { w.BoardID = "" }},
+		{"missing title", func(w *IncomingWebhook) { w.Mapping.Title = " " }},
+		{"name too long", func(w *IncomingWebhook) { w.Name = strings.Repeat("a", IncomingWebhookNameMaxLength+1) }},
+		{"external ID property without template", func(w *IncomingWebhook) { w.Mapping.ExternalIDProperty = "ext" }},
+		{"external ID template without property", func(w *IncomingWebhook) { w.Mapping.ExternalID = "{{payload.id}}" }},
+		{"invalid property template", func(w *IncomingWebhook) { w.Mapping.Properties["severity"] = 3 }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			webhook := valid()
+			tc.modify(webhook)
+			require.True(t, IsErrBadRequest(webhook.IsValid()))
+		})
+	}
+}
+
+func TestIncomingWebhookMappingIsValidForSchema(t *testing.T) {
+	schema := PropSchema{
+		"ext":     PropDef{ID: "ext", Type: "text"},
+		"status":  PropDef{ID: "status", Type: "select"},
+		"created": PropDef{ID: "created", Name: "Created", Type: "createdTime"},
+	}
+
+	mapping := &IncomingWebhookMapping{
+		Title:              "{{payload.title}}",
+		Properties:         map[string]interface{}{"status": "{{payload.status}}"},
+		ExternalIDProperty: "ext",
+		ExternalID:         "{{payload.id}}",
+	}
+	require.NoError(t, mapping.IsValidForSchema(schema))
+
+	mapping.Properties["missing"] = "value"
+	require.True(t, IsErrBadRequest(mapping.IsValidForSchema(schema)))
+
+	delete(mapping.Properties, "missing")
+	mapping.Properties["created"] = "{{today}}"
+	require.True(t, IsErrBadRequest(mapping.IsValidForSchema(schema)))
+
+	delete(mapping.Properties, "created")
+	mapping.ExternalIDProperty = "status"
+	require.True(t, IsErrBadRequest(mapping.IsValidForSchema(schema)))
+}
+
+func TestParseIncomingWebhookPayload(t *testing.T) {
+	variables, err := ParseIncomingWebhookPayload([]byte(`{
+		"alert": {"name": "CPU high", "value": 97.5, "firing": true, "runbook": null},
+		"tags": ["prod", "db"]
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t, "CPU high", variables["payload.alert.name"])
+	require.Equal(t, "97.5", variables["payload.alert.value"])
+	require.Equal(t, "true", variables["payload.alert.firing"])
+	require.Equal(t, "", variables["payload.alert.runbook"])
+	require.Equal(t, "db", variables["payload.tags.1"])
+	require.JSONEq(t, `["prod","db"]`, variables["payload.tags"])
+	require.JSONEq(t, `{"name":"CPU high","value":97.5,"firing":true,"runbook":null}`, variables["payload.alert"])
+
+	t.Run("invalid payloads", func(t *testing.T) {
+		_, err := ParseIncomingWebhookPayload([]byte(`{"alert":`))
+		require.True(t, IsErrBadRequest(err))
+
+		_, err = ParseIncomingWebhookPayload([]byte(`{} {}`))
+		require.True(t, IsErrBadRequest(err))
+	})
+
+	t.Run("deep fields are only JSON", func(t *testing.T) {
+		payload := strings.Repeat(`{"a":`, IncomingWebhookPayloadMaxDepth+2) + "1" + strings.Repeat("}", IncomingWebhookPayloadMaxDepth+2)
+		variables, err := ParseIncomingWebhookPayload([]byte(payload))
+		require.NoError(t, err)
+
+		deepest := PlaceholderPayload + strings.Repeat(".a", IncomingWebhookPayloadMaxDepth)
+		require.Equal(t, `{"a":{"a":1}}`, variables[deepest])
+		require.NotContains(t, variables, deepest+".a")
+	})
+}
+
+func TestIncomingWebhookMappingResolve(t *testing.T) {
+	schema := PropSchema{
+		"ext":      PropDef{ID: "ext", Type: "text"},
+		"severity": PropDef{ID: "severity", Type: "select", Options: map[string]PropDefOption{"sev_high": {ID: "sev_high", Value: "High"}}},
+		"tags":     PropDef{ID: "tags", Type: "multiSelect", Options: map[string]PropDefOption{"tag_db": {ID: "tag_db", Value: "db"}}},
+		"due":      PropDef{ID: "due", Type: "date"},
+		"owner":    PropDef{ID: "owner", Type: "person"},
+		"notes":    PropDef{ID: "notes", Type: "text"},
+		"runbook":  PropDef{ID: "runbook", Type: "url"},
+	}
+	mapping := &IncomingWebhookMapping{
+		Title: "[{{payload.env}}] {{payload.alert}}",
+		Properties: map[string]interface{}{
+			"severity": "{{payload.severity}}",
+			"tags":     []interface{}{"{{payload.tags.0}}", "{{payload.tags.1}}"},
+			"due":      "{{payload.due}}",
+			"owner":    "{{creator}}",
+			"notes":    []interface{}{"{{payload.tags.0}}", "{{payload.tags.1}}"},
+			"runbook":  "{{payload.runbook}}",
+		},
+		Content:            "Reported on {{board.title}}: {{payload.missing}}",
+		ExternalIDProperty: "ext",
+		ExternalID:         "{{payload.id}}",
+	}
+
+	variables, err := ParseIncomingWebhookPayload([]byte(`{
+		"id": 42, "env": "prod", "alert": "Disk full", "severity": "HIGH",
+		"tags": ["db", "tag_db"], "due": "2026-03-10T18:30:00+09:00"
+	}`))
+	require.NoError(t, err)
+	ctx := PlaceholderContext{
+		Now:           time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		CreatorID:     "user-1",
+		CreatorName:   "alice",
+		BoardTitle:    "Incidents",
+		Variables:     variables,
+		RemoveUnknown: true,
+	}
+
+	card := mapping.Resolve(ctx, schema)
+	require.Equal(t, "[prod] Disk full", card.Title)
+	require.Equal(t, "Reported on Incidents:", card.Content)
+	require.Equal(t, "42", card.ExternalID)
+	require.Equal(t, map[string]interface{}{
+		"ext":      "42",
+		"severity": "sev_high",
+		"tags":     []interface{}{"tag_db", "tag_db"},
+		"due":      DatePropertyValue(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)),
+		"owner":    "user-1",
+		"notes":    "db, tag_db",
+	}, card.Properties)
+
+	t.Run("dates", func(t *testing.T) {
+		march10 := DatePropertyValue(time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC))
+		require.Equal(t, march10, incomingWebhookDate("2026-03-10"))
+		require.Equal(t, march10, incomingWebhookDate("1773151200000"))
+		require.Equal(t, "next week", incomingWebhookDate("next week"))
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/utils"
@@ -47,6 +48,8 @@ type PropDefOption struct {
 	Index int    `json:"index"`
 	Color string `json:"color"`
 	Value string `json:"value"`
+	// Done is true if the cards with this option are in a done state.
+	Done bool `json:"done,omitempty"`
 }
 
 // PropDef represents a property definition as defined in a board's Fields member.
@@ -56,6 +59,15 @@ type PropDef struct {
 	Name    string                   `json:"name"`
 	Type    string                   `json:"type"`
 	Options map[string]PropDefOption `json:"options"`
+	Formula string                   `json:"formula,omitempty"`
+
+	Relation *RelationPropDef `json:"relation,omitempty"`
+	Rollup   *RollupPropDef   `json:"rollup,omitempty"`
+
+	// formula is the parsed Formula of formula properties, or the error
+	// that parsing it returned.
+	formula    *Formula
+	formulaErr error
 }
 
 // GetValue resolves the value of a property if the passed value is an ID for an option,
@@ -148,6 +160,14 @@ func (pd PropDef) GetValue(v interface{}, resolver PropValueResolver) (string, e
 			sb.WriteString(strings.ToUpper(opt.Value))
 		}
 		return sb.String(), nil
+
+	case PropTypeFormula, PropTypeRollup:
+		// v is the value computed by the formula or the rollup
+		return formatFormulaValue(v), nil
+
+	case PropTypeRelation:
+		// v is a slice of card IDs
+		return strings.Join(RelationIDs(v), ", "), nil
 	}
 	return fmt.Sprintf("%v", v), nil
 }
@@ -186,6 +206,15 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 			Type:    getMapString("type", prop),
 			Options: make(map[string]PropDefOption),
 		}
+		switch pd.Type {
+		case PropTypeFormula:
+			pd.Formula = getMapString("formula", prop)
+			pd.formula, pd.formulaErr = ParseFormula(pd.Formula)
+		case PropTypeRelation:
+			pd.Relation = parseRelationPropDef(prop)
+		case PropTypeRollup:
+			pd.Rollup = parseRollupPropDef(prop)
+		}
 		optsIface, ok := prop["options"]
 		if ok {
 			opts, ok := optsIface.([]interface{})
@@ -203,6 +232,7 @@ func ParsePropertySchema(board *Board) (PropSchema, error) {
 					Value: getMapString("value", propOpt),
 					Color: getMapString("color", propOpt),
 				}
+				po.Done, _ = propOpt["done"].(bool)
 				pd.Options[po.ID] = po
 			}
 		}
@@ -226,24 +256,34 @@ func getMapString(key string, m map[string]interface{}) string {
 
 // ParseProperties parses a block's `Fields` to extract the properties. Properties typically exist on
 // card blocks.  A resolver can optionally be provided to fetch usernames for `person` prop type.
-func ParseProperties(block *Block, schema PropSchema, resolver PropValueResolver) (BlockProperties, error) {
+// Formula values are evaluated in the given formula context, which should be shared by the
+// blocks compared with each other.
+func ParseProperties(block *Block, schema PropSchema, resolver PropValueResolver, fctx FormulaContext) (BlockProperties, error) {
 	props := make(map[string]BlockProp)
 
 	if block == nil {
 		return props, nil
 	}
 
+	// only cards have formula values
+	hasFormulas := block.Type == TypeCard && schema.HasFormulas()
+
 	// `properties` contains a map (untyped at this point).
 	propsIface, ok := block.Fields["properties"]
-	if !ok {
+	if !ok && !hasFormulas {
 		return props, nil // this is expected for blocks that don't have any properties.
 	}
 
 	blockProps, ok := propsIface.(map[string]interface{})
-	if !ok {
+	if !ok && propsIface != nil {
 		return props, fmt.Errorf("`properties` field wrong type: %w", ErrInvalidProperty)
 	}
 
+	if hasFormulas {
+		// formula values are computed rather than stored
+		blockProps = schema.evaluateFormulas(formulaCardFromBlock(block), fctx)
+	}
+
 	if len(blockProps) == 0 {
 		return props, nil
 	}
@@ -271,3 +311,68 @@ func ParseProperties(block *Block, schema PropSchema, resolver PropValueResolver
 	}
 	return props, nil
 }
+
+// PropDiff is the change of a property value between two versions of a block.
+type PropDiff struct {
+	ID       string `json:"id"` // property id
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// DiffProperties returns the properties added, changed or deleted between two
+// versions of a block, as parsed by ParseProperties, sorted by their position
+// in the board's schema.
+func DiffProperties(oldProps, newProps BlockProperties) []PropDiff {
+	var propDiffs []PropDiff
+
+	// look for new or changed properties.
+	for k, prop := range newProps {
+		oldP, ok := oldProps[k]
+		if ok {
+			// prop changed
+			if prop.Value != oldP.Value {
+				propDiffs = append(propDiffs, PropDiff{
+					ID:       prop.ID,
+					Index:    prop.Index,
+					Name:     prop.Name,
+					NewValue: prop.Value,
+					OldValue: oldP.Value,
+				})
+			}
+		} else {
+			// prop added
+			propDiffs = append(propDiffs, PropDiff{
+				ID:       prop.ID,
+				Index:    prop.Index,
+				Name:     prop.Name,
+				NewValue: prop.Value,
+				OldValue: "",
+			})
+		}
+	}
+
+	// look for deleted properties
+	for k, prop := range oldProps {
+		_, ok := newProps[k]
+		if !ok {
+			// prop deleted
+			propDiffs = append(propDiffs, PropDiff{
+				ID:       prop.ID,
+				Index:    prop.Index,
+				Name:     prop.Name,
+				NewValue: "",
+				OldValue: prop.Value,
+			})
+		}
+	}
+
+	sort.Slice(propDiffs, func(i, j int) bool {
+		if propDiffs[i].Index != propDiffs[j].Index {
+			return propDiffs[i].Index < propDiffs[j].Index
+		}
+		return propDiffs[i].ID < propDiffs[j].ID
+	})
+	return propDiffs
+}
@@ -161,3 +161,54 @@ const (
 	   }
 	]`
 )
+
+func Test_DiffProperties(t *testing.T) {
+	oldProps := BlockProperties{
+		"status":   {ID: "status", Index: 1, Name: "Status", Value: "To do"},
+		"estimate": {ID: "estimate", Index: 2, Name: "Estimate", Value: "3"},
+		"owner":    {ID: "owner", Index: 0, Name: "Owner", Value: "alice"},
+	}
+	newProps := BlockProperties{
+		"status":   {ID: "status", Index: 1, Name: "Status", Value: "Done"},
+		"estimate": {ID: "estimate", Index: 2, Name: "Estimate", Value: "3"},
+		"due":      {ID: "due", Index: 3, Name: "Due", Value: "tomorrow"},
+	}
+
+	diffs := DiffProperties(oldProps, newProps)
+	assert.Equal(t, []PropDiff{
+		{ID: "owner", Index: 0, Name: "Owner", OldValue: "alice", NewValue: ""},
+		{ID: "status", Index: 1, Name: "Status", OldValue: "To do", NewValue: "Done"},
+		{ID: "due", Index: 3, Name: "Due", OldValue: "", NewValue: "tomorrow"},
+	}, diffs)
+
+	assert.Empty(t, DiffProperties(newProps, newProps))
+}
+
+func Test_ParsePropertiesFormulas(t *testing.T) {
+	board := &Board{
+		ID: utils.NewID(utils.IDTypeBoard),
+		CardProperties: []map[string]interface{}{
+			{"id": "f1", "name": "Two", "type": PropTypeFormula, "formula": "1 + 1"},
+		},
+	}
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	t.Run("cards have formula values", func(t *testing.T) {
+		card := &Block{ID: "card_id", BoardID: board.ID, Type: TypeCard, Fields: map[string]interface{}{}}
+		props, err := ParseProperties(card, schema, nil, FormulaContext{})
+		require.NoError(t, err)
+		require.Contains(t, props, "f1")
+		assert.Equal(t, "2", props["f1"].Value)
+	})
+
+	t.Run("other blocks don't have formula values", func(t *testing.T) {
+		for _, blockType := range []BlockType{TypeComment, TypeText, TypeCheckbox} {
+			block := &Block{ID: "block_id", BoardID: board.ID, Type: blockType, Fields: map[string]interface{}{}}
+			props, err := ParseProperties(block, schema, nil, FormulaContext{})
+			require.NoError(t, err)
+			assert.Empty(t, props, blockType)
+			assert.Empty(t, DiffProperties(nil, props), blockType)
+		}
+	})
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// propOptionColors are the colors given in turn to the options created
+// when converting a property to a select property.
+var propOptionColors = []string{
+	"propColorDefault", "propColorGray", "propColorBrown", "propColorOrange", "propColorYellow",
+	"propColorGreen", "propColorBlue", "propColorPurple", "propColorPink", "propColorRed",
+}
+
+// PropertyConversion is the request to change the type of a card property
+// of a board and to convert the values the cards have for it.
+// swagger:model
+type PropertyConversion struct {
+	// The new type of the property
+	// required: true
+	Type string `json:"type"`
+
+	// If true, the conversion is only reported and nothing is changed
+	// required: false
+	DryRun bool `json:"dryRun"`
+
+	// If true, the values that cannot be converted are removed from the
+	// cards. Otherwise the conversion fails if there is any
+	// required: false
+	DropUnconvertible bool `json:"dropUnconvertible"`
+}
+
+// UnconvertibleValue is a card value that cannot be converted to the new
+// type of its property. The value is removed from the card if the
+// conversion drops the unconvertible values.
+// swagger:model
+type UnconvertibleValue struct {
+	// The ID of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The value of the card
+	// required: true
+	Value interface{} `json:"value"`
+
+	// Why the value cannot be converted
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// PropertyConversionResult is the result of the conversion of a card
+// property.
+// swagger:model
+type PropertyConversionResult struct {
+	// The converted property
+	// required: true
+	Property map[string]interface{} `json:"property"`
+
+	// The number of cards whose value has been converted
+	// required: true
+	ConvertedCards int `json:"convertedCards"`
+
+	// The values that cannot be converted
+	// required: true
+	Unconvertible []UnconvertibleValue `json:"unconvertible"`
+
+	// True if nothing has been changed
+	// required: true
+	DryRun bool `json:"dryRun"`
+}
+
+// ErrUnconvertibleValues is returned when a card property is converted
+// without dropping the card values that cannot be converted, and there are
+// some.
+type ErrUnconvertibleValues struct {
+	Values []UnconvertibleValue
+}
+
+// NewErrUnconvertibleValues creates a new ErrUnconvertibleValues instance.
+func NewErrUnconvertibleValues(values []UnconvertibleValue) *ErrUnconvertibleValues {
+	return &ErrUnconvertibleValues{
+		Values: values,
+	}
+}
+
+func (e *ErrUnconvertibleValues) Error() string {
+	problems := make([]string, len(e.Values))
+	for i, value := range e.Values {
+		problems[i] = fmt.Sprintf("card %s: %s", value.CardID, value.Reason)
+	}
+	return "unconvertible card property values: " + strings.Join(problems, "; ")
+}
+
+// PropertyConverter converts the card values of a property to a new type.
+type PropertyConverter struct {
+	from     PropDef
+	toType   string
+	property map[string]interface{}
+
+	// options are the options of the converted property, by ID and by
+	// value for the ones created from text values.
+	options        []interface{}
+	optionsByValue map[string]string
+}
+
+// textPropTypes are the types of the properties whose values are free text.
+var textPropTypes = map[string]bool{
+	"text":  true,
+	"url":   true,
+	"email": true,
+	"phone": true,
+}
+
+// NewPropertyConverter creates the converter of the values of a card
+// property to a new type. It returns a bad request error if the values of
+// the property cannot be converted to the type.
+func NewPropertyConverter(prop map[string]interface{}, toType string) (*PropertyConverter, error) {
+	schema, err := ParsePropertySchema(&Board{CardProperties: []map[string]interface{}{prop}})
+	if err != nil {
+		return nil, NewErrBadRequest(err.Error())
+	}
+	from := schema[getMapString("id", prop)]
+	if from.Type == toType {
+		return nil, NewErrBadRequest(fmt.Sprintf("the property is already of type %s", toType))
+	}
+	if !canConvertProperty(from.Type, toType) {
+		return nil, NewErrBadRequest(fmt.Sprintf("cannot convert a %s property to %s", from.Type, toType))
+	}
+
+	c := &PropertyConverter{
+		from:           from,
+		toType:         toType,
+		options:        []interface{}{},
+		optionsByValue: map[string]string{},
+	}
+
+	// select and multiSelect properties keep their options between them
+	if toType == "select" || toType == "multiSelect" {
+		if options, ok := prop["options"].([]interface{}); ok {
+			c.options = append(c.options, options...)
+		}
+	}
+
+	c.property = make(map[string]interface{}, len(prop))
+	for k, v := range prop {
+		c.property[k] = v
+	}
+	c.property["type"] = toType
+	return c, nil
+}
+
+func canConvertProperty(fromType, toType string) bool {
+	switch {
+	case textPropTypes[toType]:
+		return textPropTypes[fromType] || fromType == "number" || fromType == "select" || fromType == "multiSelect" ||
+			fromType == "person" || fromType == "multiPerson" || fromType == "checkbox"
+	case toType == "number":
+		return textPropTypes[fromType] || fromType == "select"
+	case toType == "select":
+		return textPropTypes[fromType] || fromType == "number" || fromType == "multiSelect"
+	case toType == "multiSelect":
+		return textPropTypes[fromType] || fromType == "number" || fromType == "select"
+	case toType == "person":
+		return fromType == "multiPerson"
+	case toType == "multiPerson":
+		return fromType == "person"
+	case toType == "checkbox":
+		return textPropTypes[fromType]
+	}
+	return false
+}
+
+// Property returns the definition of the converted property, with the
+// options created by the conversion.
+func (c *PropertyConverter) Property() map[string]interface{} {
+	if c.toType == "select" || c.toType == "multiSelect" {
+		c.property["options"] = c.options
+	} else {
+		c.property["options"] = []interface{}{}
+	}
+	return c.property
+}
+
+// Convert returns the value converted to the new type of the property, or
+// nil if the converted value is empty.
+func (c *PropertyConverter) Convert(value interface{}) (interface{}, error) {
+	switch {
+	case textPropTypes[c.toType]:
+		return c.toText(value)
+
+	case c.toType == "number":
+		s, err := c.toText(value)
+		if err != nil || s == nil {
+			return s, err
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(s.(string)), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+
+	case c.toType == "select":
+		if c.from.Type == "multiSelect" {
+			ids, ok := stringList(value)
+			if !ok {
+				return nil, ErrInvalidPropertyValueType
+			}
+			switch len(ids) {
+			case 0:
+				return nil, nil
+			case 1:
+				return ids[0], nil
+			}
+			return nil, fmt.Errorf("%d options are selected", len(ids))
+		}
+		s, err := c.toText(value)
+		if err != nil || s == nil {
+			return s, err
+		}
+		return c.optionID(s.(string)), nil
+
+	case c.toType == "multiSelect":
+		var id string
+		if c.from.Type == "select" {
+			s, ok := value.(string)
+			if !ok {
+				return nil, ErrInvalidPropertyValueType
+			}
+			id = s
+		} else {
+			s, err := c.toText(value)
+			if err != nil || s == nil {
+				return s, err
+			}
+			id = c.optionID(s.(string))
+		}
+		if id == "" {
+			return nil, nil
+		}
+		return []interface{}{id}, nil
+
+	case c.toType == "person":
+		ids, ok := stringList(value)
+		if !ok {
+			return nil, ErrInvalidPropertyValueType
+		}
+		switch len(ids) {
+		case 0:
+			return nil, nil
+		case 1:
+			return ids[0], nil
+		}
+		return nil, fmt.Errorf("%d persons are selected", len(ids))
+
+	case c.toType == "multiPerson":
+		id, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidPropertyValueType
+		}
+		if id == "" {
+			return nil, nil
+		}
+		return []interface{}{id}, nil
+
+	case c.toType == "checkbox":
+		s, err := c.toText(value)
+		if err != nil || s == nil {
+			return s, err
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s.(string)))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", s)
+		}
+		return strconv.FormatBool(b), nil
+	}
+	return nil, fmt.Errorf("cannot convert a %s property to %s", c.from.Type, c.toType)
+}
+
+// toText returns the text of a value of the source property, or nil if it
+// is empty.
+func (c *PropertyConverter) toText(value interface{}) (interface{}, error) {
+	var text string
+	switch c.from.Type {
+	case "select":
+		id, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidPropertyValueType
+		}
+		if id != "" {
+			option, ok := c.from.Options[id]
+			if !ok {
+				return nil, fmt.Errorf("unknown option %q", id)
+			}
+			text = option.Value
+		}
+
+	case "multiSelect":
+		ids, ok := stringList(value)
+		if !ok {
+			return nil, ErrInvalidPropertyValueType
+		}
+		labels := make([]string, 0, len(ids))
+		for _, id := range ids {
+			option, ok := c.from.Options[id]
+			if !ok {
+				return nil, fmt.Errorf("unknown option %q", id)
+			}
+			labels = append(labels, option.Value)
+		}
+		text = strings.Join(labels, ", ")
+
+	case "multiPerson":
+		ids, ok := stringList(value)
+		if !ok {
+			return nil, ErrInvalidPropertyValueType
+		}
+		text = strings.Join(ids, ", ")
+
+	default:
+		switch v := value.(type) {
+		case string:
+			text = v
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			text = strconv.FormatBool(v)
+		default:
+			return nil, ErrInvalidPropertyValueType
+		}
+	}
+
+	if text == "" {
+		return nil, nil
+	}
+	return text, nil
+}
+
+// optionID returns the ID of the option of a text value, creating it if
+// needed. Values that differ only by their surrounding spaces share the
+// same option.
+func (c *PropertyConverter) optionID(text string) string {
+	value := strings.TrimSpace(text)
+	if value == "" {
+		return ""
+	}
+	if id, ok := c.optionsByValue[value]; ok {
+		return id
+	}
+
+	id := utils.NewID(utils.IDTypeNone)
+	c.options = append(c.options, map[string]interface{}{
+		"id":    id,
+		"value": value,
+		"color": propOptionColors[len(c.optionsByValue)%len(propOptionColors)],
+	})
+	c.optionsByValue[value] = id
+	return id
+}
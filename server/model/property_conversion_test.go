@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conversionTestSelect(propType string) map[string]interface{} {
+	return map[string]interface{}{
+		"id": "status", "name": "Status", "type": propType, "options": []interface{}{
+			map[string]interface{}{"id": "status_done", "value": "Done", "color": "propColorGreen"},
+			map[string]interface{}{"id": "status_todo", "value": "To Do", "color": "propColorRed"},
+		},
+	}
+}
+
+func TestNewPropertyConverter(t *testing.T) {
+	t.Run("unsupported conversions", func(t *testing.T) {
+		for _, tc := range []struct{ from, to string }{
+			{"text", "text"},
+			{"text", "person"},
+			{"date", "number"},
+			{"checkbox", "select"},
+			{PropTypeFormula, "text"},
+			{"text", "unknown"},
+		} {
+			_, err := NewPropertyConverter(map[string]interface{}{"id": "p", "type": tc.from}, tc.to)
+			assert.True(t, IsErrBadRequest(err), "%s -> %s", tc.from, tc.to)
+		}
+	})
+}
+
+func TestConvertPropertyValues(t *testing.T) {
+	t.Run("text to select creates an option per distinct value", func(t *testing.T) {
+		converter, err := NewPropertyConverter(map[string]interface{}{"id": "p", "name": "P", "type": "text"}, "select")
+		require.NoError(t, err)
+
+		first, err := converter.Convert("High")
+		require.NoError(t, err)
+		second, err := converter.Convert(" High ")
+		require.NoError(t, err)
+		third, err := converter.Convert("Low")
+		require.NoError(t, err)
+		empty, err := converter.Convert("")
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, third)
+		assert.Nil(t, empty)
+
+		prop := converter.Property()
+		assert.Equal(t, "select", prop["type"])
+		options := prop["options"].([]interface{})
+		require.Len(t, options, 2)
+		assert.Equal(t, first, options[0].(map[string]interface{})["id"])
+		assert.Equal(t, "High", options[0].(map[string]interface{})["value"])
+		assert.Equal(t, "Low", options[1].(map[string]interface{})["value"])
+	})
+
+	t.Run("select to multiSelect keeps the options", func(t *testing.T) {
+		converter, err := NewPropertyConverter(conversionTestSelect("select"), "multiSelect")
+		require.NoError(t, err)
+
+		value, err := converter.Convert("status_done")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"status_done"}, value)
+		assert.Len(t, converter.Property()["options"], 2)
+	})
+
+	t.Run("multiSelect to select", func(t *testing.T) {
+		converter, err := NewPropertyConverter(conversionTestSelect("multiSelect"), "select")
+		require.NoError(t, err)
+
+		value, err := converter.Convert([]interface{}{"status_todo"})
+		require.NoError(t, err)
+		assert.Equal(t, "status_todo", value)
+
+		_, err = converter.Convert([]interface{}{"status_todo", "status_done"})
+		assert.EqualError(t, err, "2 options are selected")
+	})
+
+	t.Run("select and multiSelect to text use the option values", func(t *testing.T) {
+		converter, err := NewPropertyConverter(conversionTestSelect("multiSelect"), "text")
+		require.NoError(t, err)
+
+		value, err := converter.Convert([]interface{}{"status_done", "status_todo"})
+		require.NoError(t, err)
+		assert.Equal(t, "Done, To Do", value)
+		assert.Empty(t, converter.Property()["options"])
+
+		_, err = converter.Convert([]interface{}{"status_unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("text to number", func(t *testing.T) {
+		converter, err := NewPropertyConverter(map[string]interface{}{"id": "p", "type": "text"}, "number")
+		require.NoError(t, err)
+
+		value, err := converter.Convert(" 42.50 ")
+		require.NoError(t, err)
+		assert.Equal(t, "42.5", value)
+
+		_, err = converter.Convert("forty two")
+		assert.EqualError(t, err, `"forty two" is not a number`)
+	})
+
+	t.Run("person to multiPerson and back", func(t *testing.T) {
+		converter, err := NewPropertyConverter(map[string]interface{}{"id": "p", "type": "person"}, "multiPerson")
+		require.NoError(t, err)
+		value, err := converter.Convert("user_1")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"user_1"}, value)
+
+		converter, err = NewPropertyConverter(map[string]interface{}{"id": "p", "type": "multiPerson"}, "person")
+		require.NoError(t, err)
+		value, err = converter.Convert([]interface{}{"user_1"})
+		require.NoError(t, err)
+		assert.Equal(t, "user_1", value)
+		_, err = converter.Convert([]interface{}{"user_1", "user_2"})
+		assert.Error(t, err)
+	})
+}
+
+func TestErrUnconvertibleValues(t *testing.T) {
+	err := NewErrUnconvertibleValues([]UnconvertibleValue{
+		{CardID: "card_1", Value: "forty two", Reason: `"forty two" is not a number`},
+		{CardID: "card_2", Value: "a few", Reason: `"a few" is not a number`},
+	})
+	assert.True(t, IsErrBadRequest(err))
+	assert.EqualError(t, err, `unconvertible card property values: card card_1: "forty two" is not a number; card card_2: "a few" is not a number`)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InvalidPropertyValue describes a card property value that doesn't match
+// the card properties of its board.
+// swagger:model
+type InvalidPropertyValue struct {
+	// The ID of the property
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The name of the property
+	// required: true
+	PropertyName string `json:"propertyName"`
+
+	// The invalid value
+	// required: true
+	Value interface{} `json:"value"`
+
+	// Why the value is invalid
+	// required: true
+	Reason string `json:"reason"`
+}
+
+// InvalidCardProperties lists the invalid property values of a card.
+// swagger:model
+type InvalidCardProperties struct {
+	// The ID of the board of the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The title of the card
+	// required: true
+	Title string `json:"title"`
+
+	// The invalid property values of the card
+	// required: true
+	Properties []InvalidPropertyValue `json:"properties"`
+}
+
+// InvalidCardPropertiesResponse is the response body to a scan of the
+// card property values of a team.
+// swagger:model
+type InvalidCardPropertiesResponse struct {
+	// True if there is a next page of boards to scan
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The cards with invalid property values.
+	// required: true
+	Results []*InvalidCardProperties `json:"results"`
+}
+
+// ErrInvalidPropertyValues is returned when a card is saved with property
+// values that don't match the card properties of its board.
+type ErrInvalidPropertyValues struct {
+	Properties []InvalidPropertyValue
+}
+
+// NewErrInvalidPropertyValues creates a new ErrInvalidPropertyValues instance.
+func NewErrInvalidPropertyValues(properties []InvalidPropertyValue) *ErrInvalidPropertyValues {
+	return &ErrInvalidPropertyValues{
+		Properties: properties,
+	}
+}
+
+func (e *ErrInvalidPropertyValues) Error() string {
+	problems := make([]string, len(e.Properties))
+	for i, prop := range e.Properties {
+		problems[i] = fmt.Sprintf("%s: %s", prop.PropertyName, prop.Reason)
+	}
+	return "invalid card property values: " + strings.Join(problems, "; ")
+}
+
+// PropertyMemberChecker tells whether a user is a member of a board, for the
+// validation of person properties.
+type PropertyMemberChecker func(userID string) (bool, error)
+
+// ValidateCardPropertyValues checks the values of properties against the
+// schema of their board: the options of select properties must exist, dates
+// must parse, numbers must be numeric and persons must be members of the
+// board. Only the values that differ from oldProperties are checked, so the
+// invalid values a card already has don't prevent other changes. Values of
+// properties missing from the schema and of computed properties are not
+// checked.
+func ValidateCardPropertyValues(schema PropSchema, oldProperties, properties map[string]interface{}, isMember PropertyMemberChecker) ([]InvalidPropertyValue, error) {
+	ids := make([]string, 0, len(properties))
+	for id := range properties {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	invalid := []InvalidPropertyValue{}
+	for _, id := range ids {
+		value := properties[id]
+		def, ok := schema[id]
+		if !ok {
+			continue
+		}
+		if oldValue, ok := oldProperties[id]; ok && reflect.DeepEqual(oldValue, value) {
+			continue
+		}
+
+		reason, err := def.validateValue(value, isMember)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			invalid = append(invalid, InvalidPropertyValue{
+				PropertyID:   id,
+				PropertyName: def.Name,
+				Value:        value,
+				Reason:       reason,
+			})
+		}
+	}
+
+	sort.SliceStable(invalid, func(i, j int) bool {
+		return schema[invalid[i].PropertyID].Index < schema[invalid[j].PropertyID].Index
+	})
+	return invalid, nil
+}
+
+// validateValue returns why a value is invalid for the property, or an
+// empty string if it is valid. Empty values are always valid.
+func (pd PropDef) validateValue(v interface{}, isMember PropertyMemberChecker) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	switch pd.Type {
+	case "select":
+		id, ok := v.(string)
+		if !ok {
+			return "must be an option ID", nil
+		}
+		if id != "" && !pd.hasOption(id) {
+			return fmt.Sprintf("unknown option %q", id), nil
+		}
+
+	case "multiSelect":
+		ids, ok := stringList(v)
+		if !ok {
+			return "must be a list of option IDs", nil
+		}
+		for _, id := range ids {
+			if !pd.hasOption(id) {
+				return fmt.Sprintf("unknown option %q", id), nil
+			}
+		}
+
+	case "date":
+		s, ok := v.(string)
+		if !ok {
+			return "must be a JSON date", nil
+		}
+		if s == "" {
+			return "", nil
+		}
+		if _, err := pd.ParseDate(s); err != nil {
+			return fmt.Sprintf("invalid date %q", s), nil
+		}
+
+	case "number":
+		switch n := v.(type) {
+		case float64:
+		case string:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(n), 64); n != "" && err != nil {
+				return fmt.Sprintf("%q is not a number", n), nil
+			}
+		default:
+			return "must be a number", nil
+		}
+
+	case "person":
+		userID, ok := v.(string)
+		if !ok {
+			return "must be a user ID", nil
+		}
+		if userID == "" {
+			return "", nil
+		}
+		return validatePropertyMember(userID, isMember)
+
+	case "multiPerson":
+		userIDs, ok := stringList(v)
+		if !ok {
+			return "must be a list of user IDs", nil
+		}
+		for _, userID := range userIDs {
+			if reason, err := validatePropertyMember(userID, isMember); reason != "" || err != nil {
+				return reason, err
+			}
+		}
+	}
+	return "", nil
+}
+
+func (pd PropDef) hasOption(id string) bool {
+	_, ok := pd.Options[id]
+	return ok
+}
+
+func validatePropertyMember(userID string, isMember PropertyMemberChecker) (string, error) {
+	if isMember == nil {
+		return "", nil
+	}
+	member, err := isMember(userID)
+	if err != nil {
+		return "", err
+	}
+	if !member {
+		return fmt.Sprintf("user %q is not a member of the board", userID), nil
+	}
+	return "", nil
+}
+
+func stringList(v interface{}) ([]string, bool) {
+	switch value := v.(type) {
+	case []string:
+		return value, true
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validationTestSchema(t *testing.T) PropSchema {
+	board := &Board{
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "status_done", "value": "Done"},
+			}},
+			{"id": "tags", "name": "Tags", "type": "multiSelect", "options": []interface{}{
+				map[string]interface{}{"id": "tag_a", "value": "A"},
+			}},
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+			{"id": "due", "name": "Due", "type": "date"},
+			{"id": "owner", "name": "Owner", "type": "person"},
+			{"id": "reviewers", "name": "Reviewers", "type": "multiPerson"},
+			{"id": "notes", "name": "Notes", "type": "text"},
+		},
+	}
+	schema, err := ParsePropertySchema(board)
+	require.NoError(t, err)
+	return schema
+}
+
+func validationTestMembers(userID string) (bool, error) {
+	return userID == "member", nil
+}
+
+func TestValidateCardPropertyValues(t *testing.T) {
+	schema := validationTestSchema(t)
+
+	t.Run("valid values", func(t *testing.T) {
+		properties := map[string]interface{}{
+			"status":    "status_done",
+			"tags":      []interface{}{"tag_a"},
+			"estimate":  "3.5",
+			"due":       `{"from":1672531200000,"to":1672617600000}`,
+			"owner":     "member",
+			"reviewers": []interface{}{"member"},
+			"notes":     "anything",
+			"unknown":   42.0,
+		}
+		invalid, err := ValidateCardPropertyValues(schema, nil, properties, validationTestMembers)
+		require.NoError(t, err)
+		assert.Empty(t, invalid)
+	})
+
+	t.Run("empty values are valid", func(t *testing.T) {
+		properties := map[string]interface{}{
+			"status": "", "tags": []interface{}{}, "estimate": "", "due": "", "owner": "", "reviewers": nil,
+		}
+		invalid, err := ValidateCardPropertyValues(schema, nil, properties, validationTestMembers)
+		require.NoError(t, err)
+		assert.Empty(t, invalid)
+	})
+
+	t.Run("invalid values in schema order", func(t *testing.T) {
+		properties := map[string]interface{}{
+			"reviewers": []interface{}{"member", "stranger"},
+			"owner":     "stranger",
+			"due":       `{"to":1672617600000}`,
+			"estimate":  "many",
+			"tags":      []interface{}{"tag_a", 3.0},
+			"status":    "status_unknown",
+		}
+		invalid, err := ValidateCardPropertyValues(schema, nil, properties, validationTestMembers)
+		require.NoError(t, err)
+		require.Len(t, invalid, 6)
+
+		ids := make([]string, len(invalid))
+		for i, value := range invalid {
+			ids[i] = value.PropertyID
+		}
+		assert.Equal(t, []string{"status", "tags", "estimate", "due", "owner", "reviewers"}, ids)
+		assert.Equal(t, `unknown option "status_unknown"`, invalid[0].Reason)
+		assert.Equal(t, `user "stranger" is not a member of the board`, invalid[4].Reason)
+
+		err = NewErrInvalidPropertyValues(invalid)
+		assert.True(t, IsErrBadRequest(err))
+		assert.Contains(t, err.Error(), `Status: unknown option "status_unknown"`)
+	})
+
+	t.Run("unchanged values aren't checked", func(t *testing.T) {
+		old := map[string]interface{}{"status": "status_unknown", "tags": []interface{}{"tag_b"}}
+		properties := map[string]interface{}{"status": "status_unknown", "tags": []interface{}{"tag_b"}, "estimate": "x"}
+		invalid, err := ValidateCardPropertyValues(schema, old, properties, validationTestMembers)
+		require.NoError(t, err)
+		require.Len(t, invalid, 1)
+		assert.Equal(t, "estimate", invalid[0].PropertyID)
+	})
+
+	t.Run("member errors are returned", func(t *testing.T) {
+		failing := func(string) (bool, error) { return false, errors.New("store error") }
+		_, err := ValidateCardPropertyValues(schema, nil, map[string]interface{}{"owner": "member"}, failing)
+		require.Error(t, err)
+	})
+}
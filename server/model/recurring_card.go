@@ -0,0 +1,204 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/services/scheduler"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+// RecurringCardRule generates a card from a card template of a board
+// following a cron-like schedule.
+// swagger:model
+type RecurringCardRule struct {
+	// The ID of the rule
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the board the cards are created in
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the card template the cards are copied from. It must belong to the board
+	// required: true
+	TemplateID string `json:"templateId"`
+
+	// The cron-like schedule of the rule: minute, hour, day of month, month and day of week
+	// required: true
+	Schedule string `json:"schedule"`
+
+	// The IANA time zone the schedule is evaluated in, UTC by default
+	// required: false
+	Timezone string `json:"timezone"`
+
+	// The changes made to the created cards
+	// required: false
+	Overrides RecurringCardOverrides `json:"overrides"`
+
+	// True if the rule doesn't create cards
+	// required: false
+	Paused bool `json:"paused"`
+
+	// The ID of the user the cards are created by
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The ID of the user that last modified the rule
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+
+	// The time of the next card creation in milliseconds since the current epoch, 0 if paused
+	// required: true
+	NextRunAt int64 `json:"nextRunAt"`
+
+	// The time of the last card creation in milliseconds since the current epoch
+	// required: false
+	LastRunAt int64 `json:"lastRunAt"`
+
+	// The ID of the last card created by the rule
+	// required: false
+	LastCardID string `json:"lastCardId"`
+
+	// The error of the last card creation, if it failed
+	// required: false
+	LastError string `json:"lastError"`
+}
+
+// RecurringCardOverrides are the changes made to the cards created by a
+// recurring card rule.
+// swagger:model
+type RecurringCardOverrides struct {
+	// The title of the cards, the title of the template by default
+	// required: false
+	Title string `json:"title,omitempty"`
+
+	// The property values set on the cards, by property ID
+	// required: false
+	Properties map[string]interface{} `json:"properties,omitempty"`
+
+	// The date properties set to the creation day of the cards plus a
+	// number of days, by property ID
+	// required: false
+	DateOffsets map[string]int `json:"dateOffsets,omitempty"`
+}
+
+// RecurringCardRulePatch is a patch to modify a recurring card rule.
+// swagger:model
+type RecurringCardRulePatch struct {
+	// The new schedule
+	// required: false
+	Schedule *string `json:"schedule"`
+
+	// The new time zone
+	// required: false
+	Timezone *string `json:"timezone"`
+
+	// The new overrides
+	// required: false
+	Overrides *RecurringCardOverrides `json:"overrides"`
+
+	// Pauses or resumes the rule
+	// required: false
+	Paused *bool `json:"paused"`
+}
+
+// Location returns the time zone of the schedule of the rule.
+func (r *RecurringCardRule) Location() (*time.Location, error) {
+	if r.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return nil, NewErrBadRequest("invalid timezone " + r.Timezone)
+	}
+	return loc, nil
+}
+
+// IsValid returns an error if the rule is missing a field or has an
+// invalid schedule or time zone.
+func (r *RecurringCardRule) IsValid() error {
+	if r.BoardID == "" {
+		return NewErrBadRequest("missing board ID")
+	}
+	if r.TemplateID == "" {
+		return NewErrBadRequest("missing template ID")
+	}
+	if _, err := scheduler.ParseSchedule(r.Schedule); err != nil {
+		return NewErrBadRequest(err.Error())
+	}
+	if _, err := r.Location(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ComputeNextRunAt sets the time of the next card creation after a time
+// in milliseconds since the epoch, or 0 if the rule is paused or its
+// schedule never matches.
+func (r *RecurringCardRule) ComputeNextRunAt(after int64) error {
+	if r.Paused {
+		r.NextRunAt = 0
+		return nil
+	}
+	schedule, err := scheduler.ParseSchedule(r.Schedule)
+	if err != nil {
+		return NewErrBadRequest(err.Error())
+	}
+	loc, err := r.Location()
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(utils.GetTimeForMillis(after).In(loc))
+	if next.IsZero() {
+		r.NextRunAt = 0
+		return nil
+	}
+	r.NextRunAt = utils.GetMillisForTime(next)
+	return nil
+}
+
+// Patch applies the patch to a copy of the rule.
+func (p *RecurringCardRulePatch) Patch(rule *RecurringCardRule) *RecurringCardRule {
+	patched := *rule
+	if p.Schedule != nil {
+		patched.Schedule = *p.Schedule
+	}
+	if p.Timezone != nil {
+		patched.Timezone = *p.Timezone
+	}
+	if p.Overrides != nil {
+		patched.Overrides = *p.Overrides
+	}
+	if p.Paused != nil {
+		patched.Paused = *p.Paused
+	}
+	return &patched
+}
+
+// Apply returns the card properties of a card created at a time with the
+// overrides applied. The dates are set at midnight UTC of the creation day
+// in the location of createAt, as date properties are.
+func (o *RecurringCardOverrides) Apply(properties map[string]interface{}, createAt time.Time) map[string]interface{} {
+	result := make(map[string]interface{}, len(properties)+len(o.Properties)+len(o.DateOffsets))
+	for k, v := range properties {
+		result[k] = v
+	}
+	for k, v := range o.Properties {
+		result[k] = v
+	}
+	for propertyID, days := range o.DateOffsets {
+		result[propertyID] = DatePropertyValue(createAt.AddDate(0, 0, days))
+	}
+	return result
+}
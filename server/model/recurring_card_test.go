@@ -0,0 +1,51 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringCardRuleComputeNextRunAt(t *testing.T) {
+	// Wednesday
+	now := utils.GetMillisForTime(time.Date(2026, time.March, 4, 10, 0, 0, 0, time.UTC))
+
+	rule := &RecurringCardRule{Schedule: "0 9 * * mon", Timezone: "Asia/Tokyo"}
+	require.NoError(t, rule.ComputeNextRunAt(now))
+	require.Equal(t, time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC), utils.GetTimeForMillis(rule.NextRunAt).UTC())
+
+	rule.Paused = true
+	require.NoError(t, rule.ComputeNextRunAt(now))
+	require.Zero(t, rule.NextRunAt)
+
+	rule = &RecurringCardRule{Schedule: "@daily", Timezone: "Mars/Olympus"}
+	require.True(t, IsErrBadRequest(rule.ComputeNextRunAt(now)))
+}
+
+func TestRecurringCardOverridesApply(t *testing.T) {
+	overrides := &RecurringCardOverrides{
+		Properties:  map[string]interface{}{"status": "todo"},
+		DateOffsets: map[string]int{"due": 2},
+	}
+	template := map[string]interface{}{"status": "done", "estimate": "3"}
+
+	// late on Friday in Los Angeles is already Saturday in UTC
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	properties := overrides.Apply(template, time.Date(2026, time.March, 6, 22, 0, 0, 0, loc))
+
+	due := utils.GetMillisForTime(time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, map[string]interface{}{
+		"status":   "todo",
+		"estimate": "3",
+		"due":      `{"from":` + strconv.FormatInt(due, 10) + `}`,
+	}, properties)
+	require.Equal(t, "done", template["status"])
+}
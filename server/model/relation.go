@@ -0,0 +1,286 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PropTypeRelation is the type of the card properties that link a card
+	// to cards of a target board. The value is the list of the IDs of the
+	// linked cards, and the reverse property on the target board is kept
+	// in sync with it.
+	PropTypeRelation = "relation"
+
+	// PropTypeRollup is the type of the card properties that aggregate a
+	// property of the cards linked by a relation property.
+	PropTypeRollup = "rollup"
+)
+
+// RollupFunction is the aggregation of a rollup property.
+type RollupFunction string
+
+const (
+	RollupFunctionCount       RollupFunction = "count"
+	RollupFunctionSum         RollupFunction = "sum"
+	RollupFunctionAverage     RollupFunction = "average"
+	RollupFunctionMin         RollupFunction = "min"
+	RollupFunctionMax         RollupFunction = "max"
+	RollupFunctionPercentDone RollupFunction = "percentDone"
+)
+
+// RelationPropDef is the definition of a relation property, from the
+// `targetBoardId` and `reversePropertyId` fields of the card property.
+type RelationPropDef struct {
+	// The ID of the board of the linked cards
+	TargetBoardID string `json:"targetBoardId"`
+
+	// The ID of the relation property of the target board that links the
+	// cards back, empty if the relation is one way
+	ReversePropertyID string `json:"reversePropertyId"`
+}
+
+// RollupPropDef is the definition of a rollup property, from the
+// `relationPropertyId`, `targetPropertyId`, `function` and
+// `doneOptionIds` fields of the card property.
+type RollupPropDef struct {
+	// The ID of the relation property of the board
+	RelationPropertyID string `json:"relationPropertyId"`
+
+	// The ID of the property of the linked cards that is aggregated,
+	// unused when counting them
+	TargetPropertyID string `json:"targetPropertyId"`
+
+	// The aggregation
+	Function RollupFunction `json:"function"`
+
+	// The options of a select target property that mark a card as done,
+	// for the percentDone function. Checkbox target properties are done
+	// when checked
+	DoneOptionIDs []string `json:"doneOptionIds"`
+}
+
+// RelationIDs returns the IDs of the cards a relation property value links to.
+func RelationIDs(v interface{}) []string {
+	switch value := v.(type) {
+	case []string:
+		return value
+	case []interface{}:
+		ids := make([]string, 0, len(value))
+		for _, item := range value {
+			if id, ok := item.(string); ok && id != "" {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	case string:
+		if value == "" {
+			return []string{}
+		}
+		return []string{value}
+	default:
+		return []string{}
+	}
+}
+
+// RelationValue returns the value of a relation property linking to cards.
+func RelationValue(ids []string) []interface{} {
+	value := make([]interface{}, len(ids))
+	for i, id := range ids {
+		value[i] = id
+	}
+	return value
+}
+
+// DiffRelationIDs returns the IDs present in newIDs but not in oldIDs, and
+// the ones present in oldIDs but not in newIDs.
+func DiffRelationIDs(oldIDs, newIDs []string) (added []string, removed []string) {
+	oldSet := make(map[string]bool, len(oldIDs))
+	for _, id := range oldIDs {
+		oldSet[id] = true
+	}
+	newSet := make(map[string]bool, len(newIDs))
+	for _, id := range newIDs {
+		newSet[id] = true
+		if !oldSet[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range oldIDs {
+		if !newSet[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+// ComputeRollup aggregates the target property of the linked cards. It
+// returns nil if there is nothing to aggregate.
+func ComputeRollup(def RollupPropDef, linkedCards []*Block) (interface{}, error) {
+	switch def.Function {
+	case RollupFunctionCount:
+		return float64(len(linkedCards)), nil
+
+	case RollupFunctionPercentDone:
+		if len(linkedCards) == 0 {
+			return nil, nil
+		}
+		done := 0
+		for _, card := range linkedCards {
+			if rollupIsDone(def, blockPropertyValue(card, def.TargetPropertyID)) {
+				done++
+			}
+		}
+		return math.Round(float64(done)*10000/float64(len(linkedCards))) / 100, nil
+
+	case RollupFunctionSum, RollupFunctionAverage, RollupFunctionMin, RollupFunctionMax:
+		numbers := make([]float64, 0, len(linkedCards))
+		for _, card := range linkedCards {
+			if n, ok := rollupNumber(blockPropertyValue(card, def.TargetPropertyID)); ok {
+				numbers = append(numbers, n)
+			}
+		}
+		if len(numbers) == 0 {
+			if def.Function == RollupFunctionSum {
+				return 0.0, nil
+			}
+			return nil, nil
+		}
+		result := numbers[0]
+		sum := 0.0
+		for _, n := range numbers {
+			sum += n
+			switch def.Function {
+			case RollupFunctionMin:
+				result = math.Min(result, n)
+			case RollupFunctionMax:
+				result = math.Max(result, n)
+			}
+		}
+		switch def.Function {
+		case RollupFunctionSum:
+			return sum, nil
+		case RollupFunctionAverage:
+			return sum / float64(len(numbers)), nil
+		}
+		return result, nil
+
+	default:
+		return nil, NewErrBadRequest(fmt.Sprintf("invalid rollup function %q", def.Function))
+	}
+}
+
+// IsValid validates the rollup function.
+func (f RollupFunction) IsValid() bool {
+	switch f {
+	case RollupFunctionCount, RollupFunctionSum, RollupFunctionAverage,
+		RollupFunctionMin, RollupFunctionMax, RollupFunctionPercentDone:
+		return true
+	}
+	return false
+}
+
+func blockPropertyValue(card *Block, propertyID string) interface{} {
+	properties, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return properties[propertyID]
+}
+
+func rollupIsDone(def RollupPropDef, v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true" || def.isDoneOption(value)
+	case []interface{}:
+		for _, item := range value {
+			if id, ok := item.(string); ok && def.isDoneOption(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (def RollupPropDef) isDoneOption(id string) bool {
+	for _, doneID := range def.DoneOptionIDs {
+		if id == doneID {
+			return true
+		}
+	}
+	return false
+}
+
+func rollupNumber(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func parseRelationPropDef(prop map[string]interface{}) *RelationPropDef {
+	return &RelationPropDef{
+		TargetBoardID:     getMapString("targetBoardId", prop),
+		ReversePropertyID: getMapString("reversePropertyId", prop),
+	}
+}
+
+func parseRollupPropDef(prop map[string]interface{}) *RollupPropDef {
+	def := &RollupPropDef{
+		RelationPropertyID: getMapString("relationPropertyId", prop),
+		TargetPropertyID:   getMapString("targetPropertyId", prop),
+		Function:           RollupFunction(getMapString("function", prop)),
+	}
+	if ids, ok := prop["doneOptionIds"].([]interface{}); ok {
+		for _, id := range ids {
+			if s, ok := id.(string); ok {
+				def.DoneOptionIDs = append(def.DoneOptionIDs, s)
+			}
+		}
+	}
+	return def
+}
+
+// ValidateRollups checks that the rollup properties of a board use a valid
+// function and a relation property of the board.
+func ValidateRollups(board *Board) error {
+	schema, err := ParsePropertySchema(board)
+	if err != nil {
+		// invalid schemas are handled by the schema validation
+		return nil //nolint:nilerr
+	}
+
+	problems := []string{}
+	for _, def := range schema.sortedDefs() {
+		switch def.Type {
+		case PropTypeRelation:
+			if def.Relation.TargetBoardID == "" {
+				problems = append(problems, fmt.Sprintf("%s: missing target board", def.Name))
+			}
+		case PropTypeRollup:
+			if !def.Rollup.Function.IsValid() {
+				problems = append(problems, fmt.Sprintf("%s: invalid function %q", def.Name, def.Rollup.Function))
+			}
+			relation, ok := schema[def.Rollup.RelationPropertyID]
+			if !ok || relation.Type != PropTypeRelation {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a relation property", def.Name, def.Rollup.RelationPropertyID))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return NewErrBadRequest("invalid relation properties: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
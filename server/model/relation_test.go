@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func relationTestCard(properties map[string]interface{}) *Block {
+	return &Block{Type: TypeCard, Fields: map[string]interface{}{"properties": properties}}
+}
+
+func TestDiffRelationIDs(t *testing.T) {
+	added, removed := DiffRelationIDs([]string{"a", "b", "c"}, []string{"b", "d"})
+	assert.Equal(t, []string{"d"}, added)
+	assert.Equal(t, []string{"a", "c"}, removed)
+
+	added, removed = DiffRelationIDs(nil, RelationIDs([]interface{}{"a", "", 1}))
+	assert.Equal(t, []string{"a"}, added)
+	assert.Empty(t, removed)
+}
+
+func TestComputeRollup(t *testing.T) {
+	cards := []*Block{
+		relationTestCard(map[string]interface{}{"points": "3", "status": "done", "checked": "true"}),
+		relationTestCard(map[string]interface{}{"points": "5", "status": "todo"}),
+		relationTestCard(map[string]interface{}{"points": "not a number", "status": "done"}),
+		relationTestCard(map[string]interface{}{}),
+	}
+
+	testCases := []struct {
+		def      RollupPropDef
+		expected interface{}
+	}{
+		{RollupPropDef{Function: RollupFunctionCount}, 4.0},
+		{RollupPropDef{Function: RollupFunctionSum, TargetPropertyID: "points"}, 8.0},
+		{RollupPropDef{Function: RollupFunctionAverage, TargetPropertyID: "points"}, 4.0},
+		{RollupPropDef{Function: RollupFunctionMin, TargetPropertyID: "points"}, 3.0},
+		{RollupPropDef{Function: RollupFunctionMax, TargetPropertyID: "points"}, 5.0},
+		{RollupPropDef{Function: RollupFunctionPercentDone, TargetPropertyID: "status", DoneOptionIDs: []string{"done"}}, 50.0},
+		{RollupPropDef{Function: RollupFunctionPercentDone, TargetPropertyID: "checked"}, 25.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.def.Function), func(t *testing.T) {
+			value, err := ComputeRollup(tc.def, cards)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	t.Run("no linked cards", func(t *testing.T) {
+		value, err := ComputeRollup(RollupPropDef{Function: RollupFunctionSum, TargetPropertyID: "points"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, value)
+
+		value, err = ComputeRollup(RollupPropDef{Function: RollupFunctionPercentDone}, nil)
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("invalid function", func(t *testing.T) {
+		_, err := ComputeRollup(RollupPropDef{Function: "median"}, cards)
+		require.True(t, IsErrBadRequest(err))
+	})
+}
+
+func TestValidateRollups(t *testing.T) {
+	board := &Board{
+		CardProperties: []map[string]interface{}{
+			{"id": "tasks", "name": "Tasks", "type": PropTypeRelation, "targetBoardId": "tasks_board"},
+			{"id": "count", "name": "Count", "type": PropTypeRollup, "relationPropertyId": "tasks", "function": "count"},
+		},
+	}
+	require.NoError(t, ValidateRollups(board))
+
+	board.CardProperties = append(board.CardProperties,
+		map[string]interface{}{"id": "orphan", "name": "Orphan", "type": PropTypeRelation},
+		map[string]interface{}{"id": "median", "name": "Median", "type": PropTypeRollup, "relationPropertyId": "count", "function": "median"},
+	)
+	err := ValidateRollups(board)
+	require.True(t, IsErrBadRequest(err))
+	assert.Contains(t, err.Error(), "Orphan: missing target board")
+	assert.Contains(t, err.Error(), `Median: invalid function "median"`)
+	assert.Contains(t, err.Error(), `Median: "count" is not a relation property`)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"fmt"
+)
+
+// RetentionPolicyScope is the origin of the retention period applied to a board.
+type RetentionPolicyScope string
+
+const (
+	// RetentionPolicyScopeGlobal is the retention period of the server configuration.
+	RetentionPolicyScopeGlobal RetentionPolicyScope = "global"
+
+	// RetentionPolicyScopeTeam is the default retention period of a team.
+	RetentionPolicyScopeTeam RetentionPolicyScope = "team"
+
+	// RetentionPolicyScopeBoard is the retention period of a board, that
+	// overrides the one of its team.
+	RetentionPolicyScopeBoard RetentionPolicyScope = "board"
+)
+
+// RetentionPolicy is the data retention policy of a team or a board. The
+// policy of a team applies to its boards unless they have their own one.
+// swagger:model
+type RetentionPolicy struct {
+	// The ID of the team
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the board, empty for the default policy of the team
+	// required: false
+	BoardID string `json:"boardId"`
+
+	// The number of days the boards are kept after their last activity,
+	// 0 to keep them forever
+	// required: true
+	RetentionDays int `json:"retentionDays"`
+
+	// If true, the board is never deleted by the data retention. Only
+	// valid for board policies
+	// required: false
+	LegalHold bool `json:"legalHold"`
+
+	// The ID of the user who last modified the policy
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// Updated time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsValid validates the retention policy.
+func (p *RetentionPolicy) IsValid() error {
+	if p.TeamID == "" {
+		return NewErrBadRequest("retention policy team ID cannot be empty")
+	}
+	if p.RetentionDays < 0 {
+		return NewErrBadRequest(fmt.Sprintf("invalid retention days %d", p.RetentionDays))
+	}
+	if p.LegalHold && p.BoardID == "" {
+		return NewErrBadRequest("legal hold can only be set on board retention policies")
+	}
+	return nil
+}
+
+// DataRetentionReport is the result of a data retention run.
+// swagger:model
+type DataRetentionReport struct {
+	// The global retention date, in milliseconds since the epoch, 0 if
+	// there is no global retention
+	// required: true
+	GlobalRetentionDate int64 `json:"globalRetentionDate"`
+
+	// The boards that were deleted
+	// required: true
+	Boards []*BoardRetentionResult `json:"boards"`
+
+	// The IDs of the boards that were exempted by a legal hold
+	// required: true
+	LegalHoldBoardIDs []string `json:"legalHoldBoardIds"`
+
+	// The total number of rows deleted
+	// required: true
+	TotalDeleted int64 `json:"totalDeleted"`
+}
+
+// BoardRetentionResult is a board deleted by a data retention run.
+// swagger:model
+type BoardRetentionResult struct {
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The scope of the policy that was applied
+	// required: true
+	Scope RetentionPolicyScope `json:"scope"`
+
+	// The retention date that was applied, in milliseconds since the epoch
+	// required: true
+	RetentionDate int64 `json:"retentionDate"`
+
+	// The time of the last activity of the board, in milliseconds since the epoch
+	// required: true
+	LastActivityAt int64 `json:"lastActivityAt"`
+
+	// The number of rows deleted from each table
+	// required: true
+	DeletedRows map[string]int64 `json:"deletedRows"`
+
+	// The total number of rows deleted
+	// required: true
+	TotalDeleted int64 `json:"totalDeleted"`
+}
@@ -0,0 +1,369 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The events sent to outgoing webhooks.
+const (
+	WebhookEventCardCreated         = "card.created"
+	WebhookEventCardPropertyChanged = "card.property_changed"
+	WebhookEventCommentAdded        = "comment.added"
+	WebhookEventMemberAdded         = "board.member_added"
+)
+
+// The statuses of a webhook delivery.
+const (
+	WebhookDeliveryPending = "pending"
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailed  = "failed"
+)
+
+const (
+	// WebhookMaxAttempts is the number of times a delivery is attempted
+	// before it fails.
+	WebhookMaxAttempts = 6
+
+	// WebhookRetryBaseDelay is the delay before the first retry of a
+	// delivery, doubled at each following retry.
+	WebhookRetryBaseDelay = 30 * time.Second
+
+	// WebhookResponseBodyMaxLength is the number of bytes of the response
+	// of a delivery kept in its log.
+	WebhookResponseBodyMaxLength = 1024
+
+	WebhookURLMaxLength = 2048
+)
+
+// WebhookEvents returns the events outgoing webhooks can subscribe to.
+func WebhookEvents() []string {
+	return []string{
+		WebhookEventCardCreated,
+		WebhookEventCardPropertyChanged,
+		WebhookEventCommentAdded,
+		WebhookEventMemberAdded,
+	}
+}
+
+// Webhook posts the events of a board, or of the boards of a team, to a
+// URL. The requests are signed with the secret of the webhook.
+// swagger:model
+type Webhook struct {
+	// The ID of the webhook
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the team of the webhook
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the board the events are sent for, empty for the events
+	// of all the boards of the team the creator can view
+	// required: false
+	BoardID string `json:"boardId"`
+
+	// The URL the events are posted to
+	// required: true
+	URL string `json:"url"`
+
+	// The secret the requests are signed with, only returned when the
+	// webhook is created
+	// required: false
+	Secret string `json:"secret,omitempty"`
+
+	// The events sent to the webhook, all of them if empty
+	// required: false
+	EventTypes []string `json:"eventTypes"`
+
+	// True if no events are sent to the webhook
+	// required: false
+	Disabled bool `json:"disabled"`
+
+	// The ID of the user that created the webhook
+	// required: true
+	CreatedBy string `json:"createdBy"`
+
+	// The ID of the user that last modified the webhook
+	// required: true
+	ModifiedBy string `json:"modifiedBy"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// WebhookPatch is a patch to modify an outgoing webhook.
+// swagger:model
+type WebhookPatch struct {
+	// The new URL
+	// required: false
+	URL *string `json:"url"`
+
+	// The new events
+	// required: false
+	EventTypes *[]string `json:"eventTypes"`
+
+	// Disables or enables the webhook
+	// required: false
+	Disabled *bool `json:"disabled"`
+}
+
+// WebhookPayload is the body of the requests of the outgoing webhooks.
+// swagger:model
+type WebhookPayload struct {
+	// The event
+	// required: true
+	Event string `json:"event"`
+
+	// The ID of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The ID of the board of the event
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the card of the event, if any
+	// required: false
+	CardID string `json:"cardId,omitempty"`
+
+	// The ID of the user that caused the event
+	// required: false
+	UserID string `json:"userId,omitempty"`
+
+	// The time of the event in milliseconds since the current epoch
+	// required: true
+	Timestamp int64 `json:"timestamp"`
+
+	// The data of the event: the card, the comment, the property changes
+	// or the board member
+	// required: true
+	Data map[string]interface{} `json:"data"`
+}
+
+// WebhookPropertyChange is a change of a card property in the payload of
+// a card.property_changed event.
+// swagger:model
+type WebhookPropertyChange struct {
+	// The ID of the property
+	// required: true
+	PropertyID string `json:"propertyId"`
+
+	// The name of the property
+	// required: true
+	PropertyName string `json:"propertyName"`
+
+	// The value before the change, null if it wasn't set
+	// required: false
+	OldValue interface{} `json:"oldValue"`
+
+	// The value after the change, null if it was removed
+	// required: false
+	NewValue interface{} `json:"newValue"`
+}
+
+// WebhookDelivery is the delivery of an event to an outgoing webhook, and
+// the log of its attempts.
+// swagger:model
+type WebhookDelivery struct {
+	// The ID of the delivery, sent in the X-Focalboard-Delivery header
+	// required: true
+	ID string `json:"id"`
+
+	// The ID of the webhook
+	// required: true
+	WebhookID string `json:"webhookId"`
+
+	// The ID of the board of the event
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The event
+	// required: true
+	Event string `json:"event"`
+
+	// The body of the request
+	// required: true
+	Payload json.RawMessage `json:"payload"`
+
+	// The status of the delivery: pending, success or failed
+	// required: true
+	Status string `json:"status"`
+
+	// The number of attempts made
+	// required: true
+	Attempts int `json:"attempts"`
+
+	// The time of the next attempt in milliseconds since the current epoch, 0 if there's none
+	// required: true
+	NextAttemptAt int64 `json:"nextAttemptAt"`
+
+	// The time of the last attempt in milliseconds since the current epoch
+	// required: false
+	LastAttemptAt int64 `json:"lastAttemptAt"`
+
+	// The HTTP status code of the last attempt, 0 if no response was received
+	// required: false
+	ResponseCode int `json:"responseCode"`
+
+	// The beginning of the response body of the last attempt
+	// required: false
+	ResponseBody string `json:"responseBody"`
+
+	// The error of the last attempt, if it failed
+	// required: false
+	Error string `json:"error"`
+
+	// The ID of the delivery this one redelivers, if any
+	// required: false
+	RedeliveryOf string `json:"redeliveryOf"`
+
+	// The creation time in milliseconds since the current epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// The last modified time in milliseconds since the current epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// QueryWebhookDeliveriesOptions selects a page of the deliveries of a
+// webhook, newest first.
+type QueryWebhookDeliveriesOptions struct {
+	WebhookID string
+	Status    string // all the statuses if empty
+	Page      int
+	PerPage   int
+}
+
+// Patch returns an updated version of the webhook.
+func (p *WebhookPatch) Patch(webhook *Webhook) *Webhook {
+	patched := *webhook
+	if p.URL != nil {
+		patched.URL = *p.URL
+	}
+	if p.EventTypes != nil {
+		patched.EventTypes = *p.EventTypes
+	}
+	if p.Disabled != nil {
+		patched.Disabled = *p.Disabled
+	}
+	return &patched
+}
+
+// IsValid returns an error if the webhook is missing a field, has an
+// invalid URL or subscribes to an unknown event.
+func (w *Webhook) IsValid() error {
+	if w.TeamID == "" {
+		return NewErrBadRequest("missing team ID")
+	}
+	if w.URL == "" {
+		return NewErrBadRequest("missing URL")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || len(w.URL) > WebhookURLMaxLength || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewErrBadRequest("the URL must be an http or https URL")
+	}
+	for _, event := range w.EventTypes {
+		if !IsWebhookEvent(event) {
+			return NewErrBadRequest("invalid webhook event " + event)
+		}
+	}
+	return nil
+}
+
+// Sanitize removes the secret of the webhook, which is only returned when
+// the webhook is created.
+func (w *Webhook) Sanitize() {
+	w.Secret = ""
+}
+
+// HasEvent returns true if the event is sent to the webhook.
+func (w *Webhook) HasEvent(event string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, e := range w.EventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWebhookEvent returns true for the events outgoing webhooks can
+// subscribe to.
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidWebhookDeliveryStatus returns true for the statuses of a webhook
+// delivery.
+func IsValidWebhookDeliveryStatus(status string) bool {
+	switch status {
+	case WebhookDeliveryPending, WebhookDeliverySuccess, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// RecordAttempt records the outcome of an attempt of the delivery made at
+// a time in milliseconds since the epoch. A failed attempt is retried
+// with an exponential backoff until WebhookMaxAttempts are made.
+func (d *WebhookDelivery) RecordAttempt(now int64, responseCode int, responseBody string, err error) {
+	d.Attempts++
+	d.LastAttemptAt = now
+	d.ResponseCode = responseCode
+	if len(responseBody) > WebhookResponseBodyMaxLength {
+		responseBody = responseBody[:WebhookResponseBodyMaxLength]
+	}
+	// the body is stored as text, which can't hold invalid UTF-8 or NUL
+	d.ResponseBody = strings.ReplaceAll(strings.ToValidUTF8(responseBody, ""), "\x00", "")
+	d.Error = ""
+	if err != nil {
+		d.Error = err.Error()
+	}
+
+	switch {
+	case err == nil && responseCode >= 200 && responseCode < 300:
+		d.Status = WebhookDeliverySuccess
+		d.NextAttemptAt = 0
+	case d.Attempts >= WebhookMaxAttempts:
+		d.Status = WebhookDeliveryFailed
+		d.NextAttemptAt = 0
+	default:
+		d.Status = WebhookDeliveryPending
+		delay := WebhookRetryBaseDelay << (d.Attempts - 1)
+		d.NextAttemptAt = now + delay.Milliseconds()
+	}
+}
+
+// Fail fails the delivery without attempting it, e.g. when its webhook
+// was disabled.
+func (d *WebhookDelivery) Fail(now int64, reason string) {
+	d.Status = WebhookDeliveryFailed
+	d.NextAttemptAt = 0
+	d.LastAttemptAt = now
+	d.Error = reason
+}
+
+// Sanitize removes the body of the response of the delivery, which is
+// only returned to system admins.
+func (d *WebhookDelivery) Sanitize() {
+	d.ResponseBody = ""
+}
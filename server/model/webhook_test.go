@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookIsValid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		webhook Webhook
+		valid   bool
+	}{
+		{"valid", Webhook{TeamID: "team", URL: "https://example.com/hook", EventTypes: []string{WebhookEventCardCreated}}, true},
+		{"all events", Webhook{TeamID: "team", URL: "http://example.com"}, true},
+		{"missing team", Webhook{URL: "https://example.com"}, false},
+		{"missing url", Webhook{TeamID: "team"}, false},
+		{"not http", Webhook{TeamID: "team", URL: "file:///etc/passwd"}, false},
+		{"no host", Webhook{TeamID: "team", URL: "https://"}, false},
+		{"unknown event", Webhook{TeamID: "team", URL: "https://example.com", EventTypes: []string{"card.deleted"}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.webhook.IsValid()
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, IsErrBadRequest(err))
+		})
+	}
+
+	t.Run("events", func(t *testing.T) {
+		webhook := Webhook{}
+		require.True(t, webhook.HasEvent(WebhookEventMemberAdded))
+		webhook.EventTypes = []string{WebhookEventCommentAdded}
+		require.True(t, webhook.HasEvent(WebhookEventCommentAdded))
+		require.False(t, webhook.HasEvent(WebhookEventMemberAdded))
+	})
+}
+
+func TestWebhookDeliveryRecordAttempt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		delivery := &WebhookDelivery{Status: WebhookDeliveryPending}
+		delivery.RecordAttempt(1000, 204, "", nil)
+		require.Equal(t, WebhookDeliverySuccess, delivery.Status)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, int64(1000), delivery.LastAttemptAt)
+		require.Zero(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("retries with an exponential backoff", func(t *testing.T) {
+		delivery := &WebhookDelivery{Status: WebhookDeliveryPending}
+		delay := WebhookRetryBaseDelay.Milliseconds()
+		for attempt := 1; attempt < WebhookMaxAttempts; attempt++ {
+			delivery.RecordAttempt(1000, 500, "oops", errors.New("unexpected status code 500"))
+			require.Equal(t, WebhookDeliveryPending, delivery.Status)
+			require.Equal(t, 1000+delay, delivery.NextAttemptAt)
+			require.Equal(t, "unexpected status code 500", delivery.Error)
+			delay *= 2
+		}
+
+		delivery.RecordAttempt(1000, 0, "", errors.New("connection refused"))
+		require.Equal(t, WebhookDeliveryFailed, delivery.Status)
+		require.Equal(t, WebhookMaxAttempts, delivery.Attempts)
+		require.Zero(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("the response body is truncated", func(t *testing.T) {
+		body := make([]byte, WebhookResponseBodyMaxLength+10)
+		for i := range body {
+			body[i] = 'a'
+		}
+		delivery := &WebhookDelivery{}
+		delivery.RecordAttempt(1000, 200, string(body)+"\x00", nil)
+		require.Len(t, delivery.ResponseBody, WebhookResponseBodyMaxLength)
+	})
+}
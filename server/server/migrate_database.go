@@ -0,0 +1,43 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/services/config"
+	"github.com/mattermost/focalboard/server/services/store/sqlstore"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// MigrateDatabase copies the data of the source database into the
+// destination database, usually from SQLite to Postgres, after migrating
+// both to the current schema. The table prefix of each configuration is
+// used for its database.
+func MigrateDatabase(source, destination *config.Configuration, batchSize int, logger mlog.LoggerIFace) ([]sqlstore.TableCopyResult, error) {
+	srcStore, err := NewSQLStore(source, false, logger)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the source database: %w", err)
+	}
+	defer func() { _ = srcStore.Shutdown() }()
+
+	dstStore, err := NewSQLStore(destination, false, logger)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the destination database: %w", err)
+	}
+	defer func() { _ = dstStore.Shutdown() }()
+
+	logger.Info("Database migration started",
+		mlog.String("sourceDBType", source.DBType),
+		mlog.String("sourceTablePrefix", source.DBTablePrefix),
+		mlog.String("destinationDBType", destination.DBType),
+		mlog.String("destinationTablePrefix", destination.DBTablePrefix),
+	)
+
+	results, err := sqlstore.CopyDatabase(srcStore, dstStore, batchSize)
+	if err != nil {
+		return results, err
+	}
+
+	logger.Info("Database migration completed", mlog.Int("tables", len(results)))
+	return results, nil
+}
@@ -22,9 +22,12 @@ import (
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/services/notify/notifyautomation"
 	"github.com/mattermost/focalboard/server/services/notify/notifylogger"
 	"github.com/mattermost/focalboard/server/services/scheduler"
 	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/services/store/cachelayer"
+	"github.com/mattermost/focalboard/server/services/store/metricslayer"
 	"github.com/mattermost/focalboard/server/services/store/sqlstore"
 	"github.com/mattermost/focalboard/server/services/telemetry"
 	"github.com/mattermost/focalboard/server/services/webhook"
@@ -40,6 +43,13 @@ import (
 const (
 	cleanupSessionTaskFrequency = 10 * time.Minute
 	updateMetricsTaskFrequency  = 15 * time.Minute
+	dataRetentionTaskFrequency  = 24 * time.Hour
+	recurringCardsTaskFrequency = 1 * time.Minute
+	dueDateRemindersFrequency   = 15 * time.Minute
+	webhookDeliveriesFrequency  = 10 * time.Second
+	webhookCleanupFrequency     = 1 * time.Hour
+	automationDueDatesFrequency = 1 * time.Minute
+	automationCleanupFrequency  = 1 * time.Hour
 
 	minSessionExpiryTime = int64(60 * 60 * 24 * 31) // 31 days
 
@@ -58,6 +68,13 @@ type Server struct {
 	metricsServer          *metrics.Service
 	metricsService         *metrics.Metrics
 	metricsUpdaterTask     *scheduler.ScheduledTask
+	dataRetentionTask      *scheduler.ScheduledTask
+	recurringCardsTask     *scheduler.ScheduledTask
+	dueDateRemindersTask   *scheduler.ScheduledTask
+	webhookDeliveriesTask  *scheduler.ScheduledTask
+	webhookCleanupTask     *scheduler.ScheduledTask
+	automationDueDatesTask *scheduler.ScheduledTask
+	automationCleanupTask  *scheduler.ScheduledTask
 	auditService           *audit.Audit
 	notificationService    *notify.Service
 	servicesStartStopMutex sync.Mutex
@@ -114,6 +131,9 @@ func New(params Params) (*Server, error) {
 	}
 	metricsService := metrics.NewMetrics(instanceInfo)
 
+	// the store layers are created with the store, before the metrics
+	setStoreMetrics(params.DBStore, metricsService)
+
 	// Init audit
 	auditService, errAudit := audit.NewAudit()
 	if errAudit != nil {
@@ -143,7 +163,17 @@ func New(params Params) (*Server, error) {
 	}
 	app := app.New(params.Cfg, wsAdapter, appServices)
 
-	focalboardAPI := api.NewAPI(app, params.SingleUserToken, params.Cfg.AuthMode, params.PermissionsService, params.Logger, auditService)
+	// the automation rules are applied by the app, so their backend is
+	// added once the app exists
+	automationBackend := notifyautomation.New(notifyautomation.BackendParams{
+		AppAPI: app,
+		Logger: params.Logger,
+	})
+	if err := notificationService.AddBackend(automationBackend); err != nil {
+		return nil, fmt.Errorf("cannot initialize the automation backend: %w", err)
+	}
+
+	focalboardAPI := api.NewAPI(app, params.SingleUserToken, params.Cfg.AuthMode, params.PermissionsService, params.Logger, auditService, metricsService)
 
 	// Local router for admin APIs
 	localRouter := mux.NewRouter()
@@ -208,7 +238,9 @@ func New(params Params) (*Server, error) {
 	return &server, nil
 }
 
-func NewStore(config *config.Configuration, isSingleUser bool, logger mlog.LoggerIFace) (store.Store, error) {
+// NewSQLStore opens the database of the configuration, and migrates it
+// to the current schema.
+func NewSQLStore(config *config.Configuration, isSingleUser bool, logger mlog.LoggerIFace) (*sqlstore.SQLStore, error) {
 	sqlDB, err := sql.Open(config.DBType, config.DBConfigString)
 	if err != nil {
 		logger.Error("connectDatabase failed", mlog.Err(err))
@@ -231,14 +263,43 @@ func NewStore(config *config.Configuration, isSingleUser bool, logger mlog.Logge
 		IsSingleUser:     isSingleUser,
 	}
 
+	return sqlstore.New(storeParams)
+}
+
+func NewStore(config *config.Configuration, isSingleUser bool, logger mlog.LoggerIFace) (store.Store, error) {
 	var db store.Store
-	db, err = sqlstore.New(storeParams)
+	db, err := NewSQLStore(config, isSingleUser, logger)
 	if err != nil {
 		return nil, err
 	}
+
+	if config.PrometheusAddress != "" {
+		db = metricslayer.New(db)
+	}
+
+	if config.EnableStoreCache {
+		db = cachelayer.New(db, config.StoreCacheSize)
+	}
 	return db, nil
 }
 
+// setStoreMetrics walks the layers of the store, setting the metrics of
+// those that record them.
+func setStoreMetrics(db store.Store, metricsService *metrics.Metrics) {
+	for {
+		switch layer := db.(type) {
+		case *cachelayer.CacheLayer:
+			layer.SetMetrics(metricsService)
+			db = layer.Store
+		case *metricslayer.MetricsLayer:
+			layer.SetMetrics(metricsService)
+			db = layer.Store
+		default:
+			return
+		}
+	}
+}
+
 func (s *Server) Start() error {
 	s.logger.Info("Server.Start")
 
@@ -294,6 +355,57 @@ func (s *Server) Start() error {
 	// metricsUpdater()   Calling this immediately causes integration unit tests to fail.
 	s.metricsUpdaterTask = scheduler.CreateRecurringTask("updateMetrics", metricsUpdater, updateMetricsTaskFrequency)
 
+	// the data retention permanently deletes boards, so the standalone
+	// server only runs it on a schedule when asked to
+	if s.config.EnableDataRetentionJob {
+		s.logger.Info("Data retention job enabled: boards past their retention are permanently deleted",
+			mlog.Bool("enable_data_retention", s.config.EnableDataRetention),
+			mlog.Int("data_retention_days", s.config.DataRetentionDays),
+			mlog.String("frequency", dataRetentionTaskFrequency.String()),
+		)
+		s.dataRetentionTask = scheduler.CreateRecurringTask("dataRetention", func() {
+			if _, err := s.app.RunDataRetention(); err != nil {
+				s.logger.Error("Unable to run the data retention", mlog.Err(err))
+			}
+		}, dataRetentionTaskFrequency)
+	}
+
+	s.recurringCardsTask = scheduler.CreateRecurringTask("recurringCards", func() {
+		if _, err := s.app.RunRecurringCardRules(utils.GetMillis()); err != nil {
+			s.logger.Error("Unable to run the recurring card rules", mlog.Err(err))
+		}
+	}, recurringCardsTaskFrequency)
+
+	s.dueDateRemindersTask = scheduler.CreateRecurringTask("dueDateReminders", func() {
+		if _, err := s.app.SendDueDateReminders(time.Now()); err != nil {
+			s.logger.Error("Unable to send the due date reminders", mlog.Err(err))
+		}
+	}, dueDateRemindersFrequency)
+
+	s.webhookDeliveriesTask = scheduler.CreateRecurringTask("webhookDeliveries", func() {
+		if _, err := s.app.ProcessWebhookDeliveries(utils.GetMillis()); err != nil {
+			s.logger.Error("Unable to process the webhook deliveries", mlog.Err(err))
+		}
+	}, webhookDeliveriesFrequency)
+
+	s.webhookCleanupTask = scheduler.CreateRecurringTask("webhookCleanup", func() {
+		if _, err := s.app.CleanupWebhookDeliveries(utils.GetMillis()); err != nil {
+			s.logger.Error("Unable to clean up the webhook deliveries", mlog.Err(err))
+		}
+	}, webhookCleanupFrequency)
+
+	s.automationDueDatesTask = scheduler.CreateRecurringTask("automationDueDates", func() {
+		if _, err := s.app.RunDueDateAutomations(time.Now()); err != nil {
+			s.logger.Error("Unable to run the due date automation rules", mlog.Err(err))
+		}
+	}, automationDueDatesFrequency)
+
+	s.automationCleanupTask = scheduler.CreateRecurringTask("automationCleanup", func() {
+		if _, err := s.app.CleanupAutomationExecutions(utils.GetMillis()); err != nil {
+			s.logger.Error("Unable to clean up the automation executions", mlog.Err(err))
+		}
+	}, automationCleanupFrequency)
+
 	if s.config.Telemetry {
 		firstRun := utils.GetMillis()
 		s.telemetry.RunTelemetryJob(firstRun)
@@ -335,6 +447,34 @@ func (s *Server) Shutdown() error {
 		s.metricsUpdaterTask.Cancel()
 	}
 
+	if s.dataRetentionTask != nil {
+		s.dataRetentionTask.Cancel()
+	}
+
+	if s.recurringCardsTask != nil {
+		s.recurringCardsTask.Cancel()
+	}
+
+	if s.dueDateRemindersTask != nil {
+		s.dueDateRemindersTask.Cancel()
+	}
+
+	if s.webhookDeliveriesTask != nil {
+		s.webhookDeliveriesTask.Cancel()
+	}
+
+	if s.webhookCleanupTask != nil {
+		s.webhookCleanupTask.Cancel()
+	}
+
+	if s.automationDueDatesTask != nil {
+		s.automationDueDatesTask.Cancel()
+	}
+
+	if s.automationCleanupTask != nil {
+		s.automationCleanupTask.Cancel()
+	}
+
 	if err := s.telemetry.Shutdown(); err != nil {
 		s.logger.Warn("Error occurred when shutting down telemetry", mlog.Err(err))
 	}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -13,6 +14,8 @@ const (
 	MetricsSubsystemBoards = "boards"
 	MetricsSubsystemTeams  = "teams"
 	MetricsSubsystemSystem = "system"
+	MetricsSubsystemStore  = "store"
+	MetricsSubsystemAPI    = "api"
 
 	MetricsCloudInstallationLabel = "installationId"
 )
@@ -44,6 +47,14 @@ type Metrics struct {
 	teamCount  prometheus.Gauge
 
 	blockLastActivity prometheus.Gauge
+
+	storeCacheHitsCount   *prometheus.CounterVec
+	storeCacheMissesCount *prometheus.CounterVec
+
+	storeMethodDuration    *prometheus.HistogramVec
+	storeMethodErrorsCount *prometheus.CounterVec
+
+	apiRequestDuration *prometheus.HistogramVec
 }
 
 // NewMetrics Factory method to create a new metrics collector.
@@ -172,6 +183,53 @@ func NewMetrics(info InstanceInfo) *Metrics {
 	})
 	m.registry.MustRegister(m.blockLastActivity)
 
+	m.storeCacheHitsCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "cache_hits_total",
+		Help:        "Total number of store reads served from the cache.",
+		ConstLabels: additionalLabels,
+	}, []string{"region"})
+	m.registry.MustRegister(m.storeCacheHitsCount)
+
+	m.storeCacheMissesCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "cache_misses_total",
+		Help:        "Total number of store reads not found in the cache.",
+		ConstLabels: additionalLabels,
+	}, []string{"region"})
+	m.registry.MustRegister(m.storeCacheMissesCount)
+
+	m.storeMethodDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "method_duration_seconds",
+		Help:        "Duration of the store methods.",
+		Buckets:     []float64{0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		ConstLabels: additionalLabels,
+	}, []string{"method", "success"})
+	m.registry.MustRegister(m.storeMethodDuration)
+
+	m.storeMethodErrorsCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemStore,
+		Name:        "method_errors_total",
+		Help:        "Total number of errors returned by the store methods.",
+		ConstLabels: additionalLabels,
+	}, []string{"method"})
+	m.registry.MustRegister(m.storeMethodErrorsCount)
+
+	m.apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemAPI,
+		Name:        "request_duration_seconds",
+		Help:        "Duration of the API requests.",
+		Buckets:     prometheus.DefBuckets,
+		ConstLabels: additionalLabels,
+	}, []string{"route", "method", "status"})
+	m.registry.MustRegister(m.apiRequestDuration)
+
 	return m
 }
 
@@ -231,3 +289,30 @@ func (m *Metrics) ObserveTeamCount(count int64) {
 		m.teamCount.Set(float64(count))
 	}
 }
+
+func (m *Metrics) IncrementStoreCacheHits(region string) {
+	if m != nil {
+		m.storeCacheHitsCount.WithLabelValues(region).Inc()
+	}
+}
+
+func (m *Metrics) IncrementStoreCacheMisses(region string) {
+	if m != nil {
+		m.storeCacheMissesCount.WithLabelValues(region).Inc()
+	}
+}
+
+func (m *Metrics) ObserveStoreMethodDuration(method string, success bool, elapsed float64) {
+	if m != nil {
+		m.storeMethodDuration.WithLabelValues(method, strconv.FormatBool(success)).Observe(elapsed)
+		if !success {
+			m.storeMethodErrorsCount.WithLabelValues(method).Inc()
+		}
+	}
+}
+
+func (m *Metrics) ObserveAPIRequestDuration(route, method string, status int, elapsed float64) {
+	if m != nil {
+		m.apiRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(elapsed)
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package notifyautomation
+
+import "github.com/mattermost/focalboard/server/model"
+
+type AppAPI interface {
+	GetAutomationRule(ruleID string) (*model.AutomationRule, error)
+	GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error)
+	ExecuteAutomationRule(rule *model.AutomationRule, board *model.Board, card *model.Block, chain *model.AutomationChain) (*model.AutomationExecution, error)
+	LogAutomationExecution(execution *model.AutomationExecution) error
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyautomation
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/wiggin77/merror"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	backendName = "notifyAutomation"
+)
+
+type BackendParams struct {
+	AppAPI AppAPI
+	Logger mlog.LoggerIFace
+}
+
+// Backend runs the automation rules of the boards on the card events.
+//
+// The actions of a rule change cards, which can trigger other rules. To
+// stop loops, the events of the changes made by a rule carry the chain of
+// rules that led to them, and the rules they trigger continue it: a rule
+// runs once per chain, and a chain stops after
+// model.MaxAutomationChainDepth rules.
+type Backend struct {
+	appAPI AppAPI
+	logger mlog.LoggerIFace
+}
+
+func New(params BackendParams) *Backend {
+	return &Backend{
+		appAPI: params.AppAPI,
+		logger: params.Logger,
+	}
+}
+
+func (b *Backend) Start() error {
+	return nil
+}
+
+func (b *Backend) ShutDown() error {
+	_ = b.logger.Flush()
+	return nil
+}
+
+func (b *Backend) Name() string {
+	return backendName
+}
+
+func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
+	if evt.Board == nil || evt.Card == nil || evt.BlockChanged == nil || evt.Board.IsTemplate {
+		return nil
+	}
+	// only the changes of the cards themselves trigger rules
+	if evt.BlockChanged.Type != model.TypeCard || evt.BlockChanged.ID != evt.Card.ID {
+		return nil
+	}
+	if isTemplate, _ := evt.Card.Fields["isTemplate"].(bool); isTemplate {
+		return nil
+	}
+
+	rules, err := b.triggeredRules(evt)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	merr := merror.New()
+	for _, rule := range rules {
+		if err := b.runRule(rule, evt); err != nil {
+			merr.Append(err)
+		}
+	}
+	return merr.ErrorOrNil()
+}
+
+// triggeredRules returns the enabled rules of the board the event
+// triggers.
+func (b *Backend) triggeredRules(evt notify.BlockChangeEvent) ([]*model.AutomationRule, error) {
+	switch evt.Action {
+	case notify.DueDatePassed:
+		if evt.DueDatePassed == nil {
+			return nil, nil
+		}
+		rule, err := b.appAPI.GetAutomationRule(evt.DueDatePassed.RuleID)
+		if model.IsErrNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rule.Disabled || rule.Trigger.Type != model.AutomationTriggerDueDatePassed {
+			return nil, nil
+		}
+		return []*model.AutomationRule{rule}, nil
+	case notify.Add, notify.Update:
+	default:
+		return nil, nil
+	}
+
+	boardRules, err := b.appAPI.GetAutomationRulesForBoard(evt.Board.ID)
+	if err != nil || len(boardRules) == 0 {
+		return nil, err
+	}
+
+	rules := []*model.AutomationRule{}
+	if evt.Action == notify.Add {
+		for _, rule := range boardRules {
+			if !rule.Disabled && rule.Trigger.Type == model.AutomationTriggerCardCreated {
+				rules = append(rules, rule)
+			}
+		}
+		return rules, nil
+	}
+
+	if evt.BlockOld == nil {
+		return nil, nil
+	}
+	schema, err := model.ParsePropertySchema(evt.Board)
+	if err != nil {
+		return nil, err
+	}
+	oldCard, err := model.Block2Card(evt.BlockOld)
+	if err != nil {
+		return nil, err
+	}
+	newCard, err := model.Block2Card(evt.BlockChanged)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range boardRules {
+		if !rule.Disabled && rule.Trigger.MatchesChange(oldCard, newCard, schema) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// runRule runs a rule triggered by an event, as the next link of the
+// chain of rules the event belongs to, if any.
+func (b *Backend) runRule(rule *model.AutomationRule, evt notify.BlockChangeEvent) error {
+	chain := evt.AutomationChain
+	depth := chain.Depth()
+
+	var skipped string
+	switch {
+	case chain.Contains(rule.ID):
+		skipped = "loop detected: the rule already ran in this chain of rules"
+	case depth >= model.MaxAutomationChainDepth:
+		skipped = "loop detected: too many rules triggered each other"
+	}
+	if skipped != "" {
+		b.logger.Debug("Automation rule skipped",
+			mlog.String("rule_id", rule.ID),
+			mlog.String("card_id", evt.Card.ID),
+			mlog.Int("depth", depth),
+		)
+		return b.appAPI.LogAutomationExecution(&model.AutomationExecution{
+			RuleID:      rule.ID,
+			BoardID:     rule.BoardID,
+			CardID:      evt.Card.ID,
+			TriggerType: rule.Trigger.Type,
+			Status:      model.AutomationExecutionSkipped,
+			Error:       skipped,
+			Depth:       depth,
+		})
+	}
+
+	_, err := b.appAPI.ExecuteAutomationRule(rule, evt.Board, evt.Card, chain)
+	return err
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifyautomation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/stretchr/testify/require"
+)
+
+const testCreatorID = "creator"
+
+type testAppAPI struct {
+	rules    []*model.AutomationRule
+	failing  map[string]bool
+	executed []string
+	logged   []*model.AutomationExecution
+}
+
+func (a *testAppAPI) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	for _, rule := range a.rules {
+		if rule.ID == ruleID {
+			return rule, nil
+		}
+	}
+	return nil, model.NewErrNotFound(ruleID)
+}
+
+func (a *testAppAPI) GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error) {
+	return a.rules, nil
+}
+
+func (a *testAppAPI) ExecuteAutomationRule(rule *model.AutomationRule, board *model.Board, card *model.Block, chain *model.AutomationChain) (*model.AutomationExecution, error) {
+	a.executed = append(a.executed, fmt.Sprintf("%s@%d", rule.ID, chain.Depth()))
+	if a.failing[rule.ID] {
+		return nil, errors.New("failed")
+	}
+	return &model.AutomationExecution{RuleID: rule.ID, Status: model.AutomationExecutionSuccess, Depth: chain.Depth()}, nil
+}
+
+func (a *testAppAPI) LogAutomationExecution(execution *model.AutomationExecution) error {
+	a.logged = append(a.logged, execution)
+	return nil
+}
+
+// chainRule returns a rule that sets property p<i+1> when p<i> changes.
+func chainRule(i int) *model.AutomationRule {
+	return &model.AutomationRule{
+		ID:        fmt.Sprintf("rule-%d", i),
+		BoardID:   "board-1",
+		Trigger:   model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: fmt.Sprintf("p%d", i)},
+		Actions:   []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: fmt.Sprintf("p%d", i+1), Value: "x"}},
+		CreatedBy: testCreatorID,
+	}
+}
+
+// changeEvent returns the event of a change of property p<i> of the card
+// by a user, made by the chain of rules if not nil.
+func changeEvent(i int, userID string, chain *model.AutomationChain) notify.BlockChangeEvent {
+	board := &model.Board{ID: "board-1"}
+	for j := 0; j <= model.MaxAutomationChainDepth+1; j++ {
+		board.CardProperties = append(board.CardProperties, map[string]interface{}{
+			"id": fmt.Sprintf("p%d", j), "name": fmt.Sprintf("P%d", j), "type": "text",
+		})
+	}
+	card := func(properties map[string]interface{}) *model.Block {
+		return &model.Block{ID: "card-1", BoardID: board.ID, Type: model.TypeCard, Fields: map[string]interface{}{"properties": properties}}
+	}
+	newCard := card(map[string]interface{}{fmt.Sprintf("p%d", i): "x"})
+	return notify.BlockChangeEvent{
+		Action:          notify.Update,
+		Board:           board,
+		Card:            newCard,
+		BlockChanged:    newCard,
+		BlockOld:        card(map[string]interface{}{}),
+		ModifiedBy:      &model.BoardMember{BoardID: board.ID, UserID: userID},
+		AutomationChain: chain,
+	}
+}
+
+func newTestBackend(t *testing.T, appAPI AppAPI) *Backend {
+	return New(BackendParams{AppAPI: appAPI, Logger: mlog.CreateConsoleTestLogger(t)})
+}
+
+func TestBackendChains(t *testing.T) {
+	t.Run("a chain stops at its maximum depth", func(t *testing.T) {
+		appAPI := &testAppAPI{}
+		for i := 0; i <= model.MaxAutomationChainDepth; i++ {
+			appAPI.rules = append(appAPI.rules, chainRule(i))
+		}
+		backend := newTestBackend(t, appAPI)
+
+		// a user changes p0, then each rule changes the next property
+		require.NoError(t, backend.BlockChanged(changeEvent(0, "user-1", nil)))
+		var chain *model.AutomationChain
+		for i := 1; i <= model.MaxAutomationChainDepth; i++ {
+			chain = chain.Next(fmt.Sprintf("rule-%d", i-1))
+			require.NoError(t, backend.BlockChanged(changeEvent(i, testCreatorID, chain)))
+		}
+
+		require.Equal(t, []string{"rule-0@0", "rule-1@1", "rule-2@2"}, appAPI.executed)
+		require.Len(t, appAPI.logged, 1)
+		require.Equal(t, "rule-3", appAPI.logged[0].RuleID)
+		require.Equal(t, model.AutomationExecutionSkipped, appAPI.logged[0].Status)
+		require.Equal(t, model.MaxAutomationChainDepth, appAPI.logged[0].Depth)
+	})
+
+	t.Run("a rule runs once per chain", func(t *testing.T) {
+		appAPI := &testAppAPI{rules: []*model.AutomationRule{chainRule(0)}}
+		backend := newTestBackend(t, appAPI)
+
+		require.NoError(t, backend.BlockChanged(changeEvent(0, testCreatorID, &model.AutomationChain{RuleIDs: []string{"rule-0"}})))
+		require.Empty(t, appAPI.executed)
+		require.Len(t, appAPI.logged, 1)
+		require.Equal(t, model.AutomationExecutionSkipped, appAPI.logged[0].Status)
+		require.Equal(t, 1, appAPI.logged[0].Depth)
+	})
+
+	t.Run("the changes made outside of rules start new chains", func(t *testing.T) {
+		appAPI := &testAppAPI{rules: []*model.AutomationRule{chainRule(0), chainRule(1)}}
+		backend := newTestBackend(t, appAPI)
+
+		// the creator of the rules edits the card right after a rule ran
+		require.NoError(t, backend.BlockChanged(changeEvent(0, "user-1", nil)))
+		require.NoError(t, backend.BlockChanged(changeEvent(1, testCreatorID, nil)))
+		require.Equal(t, []string{"rule-0@0", "rule-1@0"}, appAPI.executed)
+		require.Empty(t, appAPI.logged)
+	})
+
+	t.Run("failed rules return their error", func(t *testing.T) {
+		appAPI := &testAppAPI{rules: []*model.AutomationRule{chainRule(0)}, failing: map[string]bool{"rule-0": true}}
+		backend := newTestBackend(t, appAPI)
+
+		require.Error(t, backend.BlockChanged(changeEvent(0, "user-1", nil)))
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const maxContentDiffLines = 40
+
+// isRichContent returns true for the content blocks rendered as markdown
+// in notifications.
+func isRichContent(blockType model.BlockType) bool {
+	switch blockType {
+	case model.TypeHeading, model.TypeCode, model.TypeBulletList, model.TypeNumberedList, model.TypeTable, model.TypeEmbed:
+		return true
+	}
+	return false
+}
+
+// richContentMarkdown returns the markdown of a rich content block.
+func richContentMarkdown(block *model.Block) string {
+	switch block.Type {
+	case model.TypeHeading:
+		return strings.Repeat("#", model.HeadingLevel(block)) + " " + singleLine(block.Title)
+
+	case model.TypeCode:
+		fence := codeFence(block.Title)
+		return fence + model.CodeLanguage(block) + "\n" + block.Title + "\n" + fence
+
+	case model.TypeBulletList:
+		sb := &strings.Builder{}
+		for i, item := range model.ListItems(block) {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString("- " + singleLine(item))
+		}
+		return sb.String()
+
+	case model.TypeNumberedList:
+		sb := &strings.Builder{}
+		start := model.ListStart(block)
+		for i, item := range model.ListItems(block) {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(sb, "%d. %s", start+i, singleLine(item))
+		}
+		return sb.String()
+
+	case model.TypeTable:
+		columns, rows := model.TableContent(block)
+		if len(columns) == 0 {
+			return ""
+		}
+		lines := make([]string, 0, len(rows)+2)
+		lines = append(lines, tableLine(columns))
+		separator := make([]string, len(columns))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		lines = append(lines, tableLine(separator))
+		for _, row := range rows {
+			lines = append(lines, tableLine(row))
+		}
+		return strings.Join(lines, "\n")
+
+	case model.TypeEmbed:
+		label := singleLine(block.Title)
+		if label == "" {
+			label = model.EmbedURL(block)
+		}
+		if model.EmbedType(block) == model.EmbedTypeImage {
+			return fmt.Sprintf("![%s](%s)", label, model.EmbedURL(block))
+		}
+		return fmt.Sprintf("[%s](%s)", label, model.EmbedURL(block))
+	}
+	return block.Title
+}
+
+// richContentDiff returns the markdown of a change of a rich content
+// block: the block when it is added, and the changed lines otherwise.
+// Headings and embeds are a single line that is diffed like texts.
+func richContentDiff(oldBlock, newBlock *model.Block, logger mlog.LoggerIFace) string {
+	var oldMarkdown, newMarkdown string
+	if oldBlock != nil {
+		oldMarkdown = richContentMarkdown(oldBlock)
+	}
+	if newBlock != nil && newBlock.DeleteAt == 0 {
+		newMarkdown = richContentMarkdown(newBlock)
+	}
+
+	if oldBlock == nil {
+		return newMarkdown
+	}
+
+	blockType := oldBlock.Type
+	if blockType == model.TypeHeading || blockType == model.TypeEmbed {
+		return generateMarkdownDiff(oldMarkdown, newMarkdown, logger)
+	}
+	return generateLinesDiff(oldMarkdown, newMarkdown)
+}
+
+// generateLinesDiff returns the lines that changed between two texts as
+// a diff code block, empty if none did.
+func generateLinesDiff(oldText, newText string) string {
+	// the last lines end with a newline so that they match the others
+	if oldText != "" {
+		oldText += "\n"
+	}
+	if newText != "" {
+		newText += "\n"
+	}
+
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldText, newText)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	result := []string{}
+	changed := false
+	for _, diff := range diffs {
+		prefix := "  "
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+			changed = true
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+			changed = true
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(diff.Text, "\n"), "\n") {
+			result = append(result, prefix+line)
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	if len(result) > maxContentDiffLines {
+		result = append(result[:maxContentDiffLines], "  ...")
+	}
+	text := strings.Join(result, "\n")
+	fence := codeFence(text)
+	return fence + "diff\n" + text + "\n" + fence
+}
+
+// codeFence returns a fence for a code block longer than the backtick
+// runs of its text.
+func codeFence(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+func tableLine(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(singleLine(cell), "|", `\|`)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+func singleLine(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\n", " "))
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichContentMarkdown(t *testing.T) {
+	testCases := []struct {
+		name     string
+		block    *model.Block
+		expected string
+	}{
+		{
+			name:     "heading",
+			block:    &model.Block{Type: model.TypeHeading, Title: "Goals", Fields: map[string]interface{}{"level": 2.0}},
+			expected: "## Goals",
+		},
+		{
+			name:     "code",
+			block:    &model.Block{Type: model.TypeCode, Title: "x := 1", Fields: map[string]interface{}{"language": "go"}},
+			expected: "```go\nx := 1\n```",
+		},
+		{
+			name:     "code with a fence",
+			block:    &model.Block{Type: model.TypeCode, Title: "```"},
+			expected: "````\n```\n````",
+		},
+		{
+			name:     "bullet list",
+			block:    &model.Block{Type: model.TypeBulletList, Fields: map[string]interface{}{"items": []interface{}{"one", "two"}}},
+			expected: "- one\n- two",
+		},
+		{
+			name:     "numbered list",
+			block:    &model.Block{Type: model.TypeNumberedList, Fields: map[string]interface{}{"items": []interface{}{"one", "two"}, "start": 3.0}},
+			expected: "3. one\n4. two",
+		},
+		{
+			name: "table",
+			block: &model.Block{Type: model.TypeTable, Fields: map[string]interface{}{
+				"columns": []interface{}{"Name", "Notes"},
+				"rows":    []interface{}{[]interface{}{"Alice", "a|b"}},
+			}},
+			expected: "| Name | Notes |\n| --- | --- |\n| Alice | a\\|b |",
+		},
+		{
+			name:     "embed",
+			block:    &model.Block{Type: model.TypeEmbed, Title: "Demo", Fields: map[string]interface{}{"url": "https://example.com"}},
+			expected: "[Demo](https://example.com)",
+		},
+		{
+			name:     "embedded image",
+			block:    &model.Block{Type: model.TypeEmbed, Fields: map[string]interface{}{"url": "https://example.com/a.png", "embedType": "image"}},
+			expected: "![https://example.com/a.png](https://example.com/a.png)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, richContentMarkdown(tc.block))
+		})
+	}
+}
+
+func TestRichContentDiff(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	list := func(items ...interface{}) *model.Block {
+		return &model.Block{Type: model.TypeBulletList, Fields: map[string]interface{}{"items": items}}
+	}
+
+	t.Run("added", func(t *testing.T) {
+		require.Equal(t, "- one", richContentDiff(nil, list("one"), logger))
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		require.Equal(t, "```diff\n  - one\n- - two\n+ - three\n```", richContentDiff(list("one", "two"), list("one", "three"), logger))
+		require.Empty(t, richContentDiff(list("one"), list("one"), logger))
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		deleted := list("one")
+		deleted.DeleteAt = 1
+		require.Equal(t, "```diff\n- - one\n```", richContentDiff(list("one"), deleted, logger))
+	})
+
+	t.Run("headings are diffed inline", func(t *testing.T) {
+		oldBlock := &model.Block{Type: model.TypeHeading, Title: "Old goals"}
+		newBlock := &model.Block{Type: model.TypeHeading, Title: "New goals"}
+		require.Equal(t, "# ~~`Old`~~`New` goals", richContentDiff(oldBlock, newBlock, logger))
+	})
+
+	t.Run("card notification", func(t *testing.T) {
+		cardDiff := &Diff{Diffs: []*Diff{{
+			BlockType: model.TypeCode,
+			NewBlock:  &model.Block{Type: model.TypeCode, Title: "echo hi", Fields: map[string]interface{}{"language": "sh"}},
+		}}}
+		fields := appendContentChanges(nil, cardDiff, logger)
+		require.Len(t, fields, 1)
+		require.Equal(t, "```sh\necho hi\n```", fields[0].Value)
+	})
+}
@@ -5,7 +5,6 @@ package notifysubscriptions
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/mattermost/focalboard/server/model"
 
@@ -30,13 +29,8 @@ type Diff struct {
 	Diffs []*Diff // Diffs for child blocks
 }
 
-type PropDiff struct {
-	ID       string // property id
-	Index    int
-	Name     string
-	OldValue string
-	NewValue string
-}
+// PropDiff is the change of a property value, see model.DiffProperties.
+type PropDiff = model.PropDiff
 
 type SchemaDiff struct {
 	Board *model.Board
@@ -52,6 +46,7 @@ type diffGenerator struct {
 	store        AppAPI
 	hint         *model.NotificationHint
 	lastNotifyAt int64
+	formulas     model.FormulaContext
 	logger       mlog.LoggerIFace
 }
 
@@ -291,9 +286,7 @@ func (dg *diffGenerator) generateDiffForBlock(newBlock *model.Block, schema mode
 }
 
 func (dg *diffGenerator) generatePropDiffs(oldBlock, newBlock *model.Block, schema model.PropSchema) []PropDiff {
-	var propDiffs []PropDiff
-
-	oldProps, err := model.ParseProperties(oldBlock, schema, dg.store)
+	oldProps, err := model.ParseProperties(oldBlock, schema, dg.store, dg.formulas)
 	if err != nil {
 		dg.logger.Error("Cannot parse properties for old block",
 			mlog.String("block_id", oldBlock.ID),
@@ -301,7 +294,7 @@ func (dg *diffGenerator) generatePropDiffs(oldBlock, newBlock *model.Block, sche
 		)
 	}
 
-	newProps, err := model.ParseProperties(newBlock, schema, dg.store)
+	newProps, err := model.ParseProperties(newBlock, schema, dg.store, dg.formulas)
 	if err != nil {
 		dg.logger.Error("Cannot parse properties for new block",
 			mlog.String("block_id", oldBlock.ID),
@@ -309,56 +302,5 @@ func (dg *diffGenerator) generatePropDiffs(oldBlock, newBlock *model.Block, sche
 		)
 	}
 
-	// look for new or changed properties.
-	for k, prop := range newProps {
-		oldP, ok := oldProps[k]
-		if ok {
-			// prop changed
-			if prop.Value != oldP.Value {
-				propDiffs = append(propDiffs, PropDiff{
-					ID:       prop.ID,
-					Index:    prop.Index,
-					Name:     prop.Name,
-					NewValue: prop.Value,
-					OldValue: oldP.Value,
-				})
-			}
-		} else {
-			// prop added
-			propDiffs = append(propDiffs, PropDiff{
-				ID:       prop.ID,
-				Index:    prop.Index,
-				Name:     prop.Name,
-				NewValue: prop.Value,
-				OldValue: "",
-			})
-		}
-	}
-
-	// look for deleted properties
-	for k, prop := range oldProps {
-		_, ok := newProps[k]
-		if !ok {
-			// prop deleted
-			propDiffs = append(propDiffs, PropDiff{
-				ID:       prop.ID,
-				Index:    prop.Index,
-				Name:     prop.Name,
-				NewValue: "",
-				OldValue: prop.Value,
-			})
-		}
-	}
-	return sortPropDiffs(propDiffs)
-}
-
-func sortPropDiffs(propDiffs []PropDiff) []PropDiff {
-	if len(propDiffs) == 0 {
-		return propDiffs
-	}
-
-	sort.Slice(propDiffs, func(i, j int) bool {
-		return propDiffs[i].Index < propDiffs[j].Index
-	})
-	return propDiffs
+	return model.DiffProperties(oldProps, newProps)
 }
@@ -308,6 +308,19 @@ func appendContentChanges(fields []*mm_model.SlackAttachmentField, cardDiff *Dif
 			opString = "modified"
 		}
 
+		if isRichContent(child.BlockType) {
+			markdown := richContentDiff(child.OldBlock, child.NewBlock, logger)
+			if markdown == "" {
+				continue
+			}
+			fields = append(fields, &mm_model.SlackAttachmentField{
+				Short: false,
+				Title: "Description",
+				Value: markdown,
+			})
+			continue
+		}
+
 		var newTitle, oldTitle string
 		if child.OldBlock != nil {
 			oldTitle = child.OldBlock.Title
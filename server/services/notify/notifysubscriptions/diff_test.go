@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyAppAPI is an AppAPI without block history, for new blocks.
+type historyAppAPI struct {
+	AppAPI
+}
+
+func (api historyAppAPI) GetBlockHistory(string, model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	return nil, nil
+}
+
+func (api historyAppAPI) GetUserByID(userID string) (*model.User, error) {
+	return &model.User{ID: userID, Username: "username_" + userID}, nil
+}
+
+func Test_generateDiffForBlock(t *testing.T) {
+	board := &model.Board{
+		ID: "board_id",
+		CardProperties: []map[string]interface{}{
+			{"id": "f1", "name": "Two", "type": model.PropTypeFormula, "formula": "1 + 1"},
+		},
+	}
+	schema, err := model.ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	card := &model.Block{ID: "card_id", BoardID: board.ID, Type: model.TypeCard, Fields: map[string]interface{}{}}
+	dg := &diffGenerator{
+		board:    board,
+		card:     card,
+		store:    historyAppAPI{},
+		formulas: model.NewFormulaContext(nil),
+		logger:   mlog.CreateConsoleTestLogger(t),
+	}
+
+	t.Run("a new comment has no property diffs", func(t *testing.T) {
+		comment := &model.Block{
+			ID:         "comment_id",
+			BoardID:    board.ID,
+			ParentID:   card.ID,
+			Type:       model.TypeComment,
+			Title:      "a comment",
+			ModifiedBy: "user_id",
+			Fields:     map[string]interface{}{},
+		}
+		diff, err := dg.generateDiffForBlock(comment, schema)
+		require.NoError(t, err)
+		assert.Nil(t, diff.OldBlock)
+		assert.Empty(t, diff.PropDiffs)
+	})
+
+	t.Run("a new card has the diffs of its formulas", func(t *testing.T) {
+		diff, err := dg.generateDiffForBlock(card, schema)
+		require.NoError(t, err)
+		require.Len(t, diff.PropDiffs, 1)
+		assert.Equal(t, "Two", diff.PropDiffs[0].Name)
+		assert.Equal(t, "2", diff.PropDiffs[0].NewValue)
+	})
+}
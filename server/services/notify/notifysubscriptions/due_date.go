@@ -0,0 +1,45 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	defDueTodayNotify    = "The card %s is due today\n"
+	defDueTomorrowNotify = "The card %s is due tomorrow\n"
+	defDueInDaysNotify   = "The card %s is due in %d days\n"
+)
+
+// notifyDueDate reminds an assignee of a card of its due date. As for
+// unblocked cards, the reminder is delivered right away.
+func (b *Backend) notifyDueDate(evt notify.BlockChangeEvent) error {
+	if evt.Card == nil || evt.Reminder == nil {
+		return nil
+	}
+
+	link := fmt.Sprintf("[%s](%s)", evt.Card.Title, utils.MakeCardLink(b.serverRoot, evt.TeamID, evt.Board.ID, evt.Card.ID))
+	var text string
+	switch evt.Reminder.DaysBefore {
+	case 0:
+		text = fmt.Sprintf(defDueTodayNotify, link)
+	case 1:
+		text = fmt.Sprintf(defDueTomorrowNotify, link)
+	default:
+		text = fmt.Sprintf(defDueInDaysNotify, link, evt.Reminder.DaysBefore)
+	}
+	attachments := []*mm_model.SlackAttachment{{Pretext: text, Fallback: text}}
+
+	if err := b.delivery.SubscriptionDeliverSlackAttachments(evt.TeamID, evt.Reminder.UserID, model.SubTypeUser, attachments); err != nil {
+		return fmt.Errorf("cannot deliver due date reminder to user %s: %w", evt.Reminder.UserID, err)
+	}
+	return nil
+}
@@ -178,7 +178,10 @@ func (n *notifier) notifySubscribers(hint *model.NotificationHint) error {
 		store:        n.store,
 		hint:         hint,
 		lastNotifyAt: oldestNotifiedAt,
-		logger:       n.logger,
+		// the diffs are sent to all the subscribers, so the formulas are
+		// evaluated once for all of them
+		formulas: model.NewFormulaContext(time.UTC),
+		logger:   n.logger,
 	}
 	diffs, err := dg.generateDiffs()
 	if err != nil {
@@ -33,6 +33,7 @@ type BackendParams struct {
 
 // Backend provides the notification backend for subscriptions.
 type Backend struct {
+	serverRoot             string
 	appAPI                 AppAPI
 	permissions            permissions.PermissionsService
 	delivery               SubscriptionDelivery
@@ -44,6 +45,7 @@ type Backend struct {
 
 func New(params BackendParams) *Backend {
 	return &Backend{
+		serverRoot:             params.ServerRoot,
 		appAPI:                 params.AppAPI,
 		delivery:               params.Delivery,
 		permissions:            params.Permissions,
@@ -101,6 +103,19 @@ func (b *Backend) BlockChanged(evt notify.BlockChangeEvent) error {
 		return nil
 	}
 
+	switch evt.Action {
+	case notify.Unblock:
+		return b.notifyUnblocked(evt)
+	case notify.DueDateReminder:
+		return b.notifyDueDate(evt)
+	case notify.ChecklistComplete:
+		// subscribers are already notified of the change of the checkbox
+		return nil
+	case notify.DueDatePassed:
+		// only automation rules handle it
+		return nil
+	}
+
 	merr := merror.New()
 	var err error
 
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notifysubscriptions
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/wiggin77/merror"
+
+	mm_model "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	defUnblockCardNotify = "%s has completed the card %s, which blocks the card %s\n"
+)
+
+// notifyUnblocked tells the subscribers of a blocked card that one of its
+// blockers is done. Unlike other changes, these notifications are
+// delivered right away rather than batched through notification hints.
+func (b *Backend) notifyUnblocked(evt notify.BlockChangeEvent) error {
+	if evt.Card == nil {
+		return nil
+	}
+
+	subs, err := b.appAPI.GetSubscribersForBlock(evt.Card.ID)
+	if err != nil {
+		return fmt.Errorf("cannot fetch subscribers for card %s: %w", evt.Card.ID, err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	author := "unknown_user"
+	if user, err := b.appAPI.GetUserByID(evt.ModifiedBy.UserID); err == nil && user != nil {
+		author = "@" + user.Username
+	}
+	blocker := evt.BlockChanged
+	text := fmt.Sprintf(defUnblockCardNotify,
+		author,
+		fmt.Sprintf("[%s](%s)", blocker.Title, utils.MakeCardLink(b.serverRoot, evt.TeamID, blocker.BoardID, blocker.ID)),
+		fmt.Sprintf("[%s](%s)", evt.Card.Title, utils.MakeCardLink(b.serverRoot, evt.TeamID, evt.Board.ID, evt.Card.ID)),
+	)
+	attachments := []*mm_model.SlackAttachment{{Pretext: text, Fallback: text}}
+
+	merr := merror.New()
+	for _, sub := range subs {
+		// don't notify the author of their own changes.
+		if sub.SubscriberID == evt.ModifiedBy.UserID {
+			continue
+		}
+
+		// make sure the subscriber still has permissions for the board.
+		if !b.permissions.HasPermissionToBoard(sub.SubscriberID, evt.Board.ID, model.PermissionViewBoard) {
+			b.logger.Debug("notifyUnblocked - skipping non-board member",
+				mlog.String("subscriber_id", sub.SubscriberID),
+				mlog.String("board_id", evt.Board.ID),
+			)
+			continue
+		}
+
+		if err = b.delivery.SubscriptionDeliverSlackAttachments(evt.TeamID, sub.SubscriberID, sub.SubscriberType, attachments); err != nil {
+			merr.Append(fmt.Errorf("cannot deliver notification to subscriber %s [%s]: %w",
+				sub.SubscriberID, sub.SubscriberType, err))
+		}
+	}
+	return merr.ErrorOrNil()
+}
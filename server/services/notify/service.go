@@ -18,16 +18,44 @@ const (
 	Add    Action = "add"
 	Update Action = "update"
 	Delete Action = "delete"
+
+	// Unblock is the action of the events sent when a card that blocks
+	// another one is done. The event Card is the blocked card and
+	// BlockChanged is the blocker.
+	Unblock Action = "unblock"
+
+	// DueDateReminder is the action of the events sent to remind an
+	// assignee of a card of its due date. The event Card and BlockChanged
+	// are the card, and Reminder tells who is reminded.
+	DueDateReminder Action = "dueDateReminder"
+
+	// ChecklistComplete is the action of the events sent when the last
+	// unchecked checkbox of a card is checked. The event Card is the card,
+	// BlockChanged is the checkbox and Checklist is the card progress.
+	ChecklistComplete Action = "checklistComplete"
+
+	// DueDatePassed is the action of the events sent when the due date of
+	// a card that isn't done passes, for an automation rule watching it.
+	// The event Card and BlockChanged are the card, and DueDatePassed
+	// tells which rule and due date.
+	DueDatePassed Action = "dueDatePassed"
 )
 
 type BlockChangeEvent struct {
-	Action       Action
-	TeamID       string
-	Board        *model.Board
-	Card         *model.Block
-	BlockChanged *model.Block
-	BlockOld     *model.Block
-	ModifiedBy   *model.BoardMember
+	Action        Action
+	TeamID        string
+	Board         *model.Board
+	Card          *model.Block
+	BlockChanged  *model.Block
+	BlockOld      *model.Block
+	ModifiedBy    *model.BoardMember
+	Reminder      *model.DueDateReminder
+	Checklist     *model.ChecklistProgress
+	DueDatePassed *model.AutomationDueDateRun
+
+	// AutomationChain is the chain of automation rules that made the
+	// change, nil if the change wasn't made by a rule.
+	AutomationChain *model.AutomationChain
 }
 
 // Backend provides an interface for sending notifications.
@@ -0,0 +1,220 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// maxScheduleYears is how far Next looks for a matching time, so that
+// schedules that never match, such as February 30th, end.
+const maxScheduleYears = 5
+
+// scheduleMacros are the shortcuts for common schedules.
+var scheduleMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+type scheduleField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var scheduleFields = []scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// Schedule is a cron-like schedule, with the minute, hour, day of month,
+// month and day of week fields of cron. Each field is `*`, a value, a
+// range `a-b` or a list of them separated by commas, optionally followed
+// by a step `/n`. Months and days of week can be given by their three
+// letter English names, and both 0 and 7 are Sunday. As in cron, when
+// both the day of month and the day of week are restricted, a day
+// matches if either of them does.
+type Schedule struct {
+	spec     string
+	minutes  []bool
+	hours    []bool
+	days     []bool
+	months   []bool
+	weekdays []bool
+
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseSchedule parses a cron-like schedule, or one of the @hourly,
+// @daily, @weekly, @monthly and @yearly shortcuts.
+func ParseSchedule(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	expanded := spec
+	if macro, ok := scheduleMacros[strings.ToLower(spec)]; ok {
+		expanded = macro
+	}
+
+	fields := strings.Fields(expanded)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("%w: expected %d fields, got %d", ErrInvalidSchedule, len(scheduleFields), len(fields))
+	}
+
+	values := make([][]bool, len(fields))
+	for i, field := range fields {
+		matches, err := scheduleFields[i].parse(field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = matches
+	}
+
+	// Sunday is both 0 and 7
+	weekdays := values[4]
+	weekdays[0] = weekdays[0] || weekdays[7]
+
+	return &Schedule{
+		spec:       spec,
+		minutes:    values[0],
+		hours:      values[1],
+		days:       values[2],
+		months:     values[3],
+		weekdays:   weekdays[:7],
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func (f scheduleField) parse(field string) ([]bool, error) {
+	matches := make([]bool, f.max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("%w: invalid step in %s field %q", ErrInvalidSchedule, f.name, part)
+			}
+			step = n
+		}
+
+		var from, to int
+		switch {
+		case rangePart == "*":
+			from, to = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if from, err = f.value(bounds[0]); err != nil {
+				return nil, err
+			}
+			if to, err = f.value(bounds[1]); err != nil {
+				return nil, err
+			}
+			if from > to {
+				return nil, fmt.Errorf("%w: invalid range in %s field %q", ErrInvalidSchedule, f.name, part)
+			}
+		default:
+			value, err := f.value(rangePart)
+			if err != nil {
+				return nil, err
+			}
+			from, to = value, value
+			// a value with a step, such as 5/15, starts a step series
+			if step > 1 {
+				to = f.max
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			matches[v] = true
+		}
+	}
+	return matches, nil
+}
+
+func (f scheduleField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrInvalidSchedule, f.name, s)
+	}
+	return v, nil
+}
+
+// String returns the schedule as it was parsed.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time of the schedule strictly after t, in the
+// location of t, or the zero time if the schedule never matches.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + maxScheduleYears
+
+	for t.Year() <= limit {
+		if !s.months[t.Month()] {
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(time.Hour)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// advance returns next, or the start of the next hour of t when a daylight
+// saving time change turns next into a time that isn't after t.
+func advance(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(time.Hour)
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day := s.days[t.Day()]
+	weekday := s.weekdays[t.Weekday()]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+	} {
+		_, err := ParseSchedule(spec)
+		assert.True(t, errors.Is(err, ErrInvalidSchedule), spec)
+	}
+
+	schedule, err := ParseSchedule(" @Weekly ")
+	require.NoError(t, err)
+	assert.Equal(t, "@Weekly", schedule.String())
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, time.March, 4, 10, 30, 15, 0, time.UTC)
+
+	testCases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 4, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 4, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 4, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2026, time.March, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2026, time.March, 5, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"30 8 1,15 * *", time.Date(2026, time.March, 15, 8, 30, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// the day of month or the day of week
+		{"0 0 20 * 5", time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, schedule.Next(from))
+		})
+	}
+
+	t.Run("in the location of the time", func(t *testing.T) {
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		schedule, err := ParseSchedule("0 9 * * *")
+		require.NoError(t, err)
+
+		// the day daylight saving time starts
+		next := schedule.Next(time.Date(2026, time.March, 8, 0, 0, 0, 0, loc))
+		assert.Equal(t, time.Date(2026, time.March, 8, 9, 0, 0, 0, loc), next)
+		assert.Equal(t, 13, next.UTC().Hour())
+	})
+}
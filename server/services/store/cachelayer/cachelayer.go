@@ -0,0 +1,65 @@
+package cachelayer
+
+import (
+	"strings"
+
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+// DefaultCacheSize is the default number of entries of each cache region.
+const DefaultCacheSize = 10000
+
+// Metrics receives the hits and misses of the cache regions.
+type Metrics interface {
+	IncrementStoreCacheHits(region string)
+	IncrementStoreCacheMisses(region string)
+}
+
+// CacheLayer is a store decorator that caches in memory the results of
+// the methods annotated with @cache in the Store interface. The entries
+// are invalidated by the methods annotated with @invalidate once they
+// write to the underlying store.
+//
+// The layer only knows about the changes made through it, so it must not
+// be used when other processes write to the same database.
+type CacheLayer struct {
+	store.Store
+	caches map[string]*regionCache
+}
+
+// New creates a cache layer on top of the store, with up to size entries
+// in each cache region.
+func New(store store.Store, size int) *CacheLayer {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	caches := make(map[string]*regionCache, len(cacheRegions))
+	for _, region := range cacheRegions {
+		caches[region] = newRegionCache(region, size)
+	}
+
+	return &CacheLayer{
+		Store:  store,
+		caches: caches,
+	}
+}
+
+// SetMetrics sets the metrics that receive the hits and misses of the
+// cache. It must be called before the layer is used.
+func (c *CacheLayer) SetMetrics(metrics Metrics) {
+	for _, cache := range c.caches {
+		cache.metrics = metrics
+	}
+}
+
+// Purge removes all the entries of the cache.
+func (c *CacheLayer) Purge() {
+	for _, cache := range c.caches {
+		cache.purge()
+	}
+}
+
+func cacheKey(method string, params ...string) string {
+	return method + ":" + strings.Join(params, ":")
+}
@@ -0,0 +1,141 @@
+package cachelayer
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/services/store/sqlstore"
+	"github.com/mattermost/focalboard/server/services/store/storetests"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func SetupTests(t *testing.T) (store.Store, func()) {
+	origUnitTesting := os.Getenv("FOCALBOARD_UNIT_TESTING")
+	os.Setenv("FOCALBOARD_UNIT_TESTING", "1")
+
+	dbType, connectionString, err := sqlstore.PrepareNewTestDatabase()
+	require.NoError(t, err)
+
+	logger, _ := mlog.NewLogger()
+
+	sqlDB, err := sql.Open(dbType, connectionString)
+	require.NoError(t, err)
+	err = sqlDB.Ping()
+	require.NoError(t, err)
+
+	storeParams := sqlstore.Params{
+		DBType:           dbType,
+		ConnectionString: connectionString,
+		DBPingAttempts:   5,
+		TablePrefix:      "test_",
+		Logger:           logger,
+		DB:               sqlDB,
+	}
+	sqlStore, err := sqlstore.New(storeParams)
+	require.NoError(t, err)
+
+	tearDown := func() {
+		defer func() { _ = logger.Shutdown() }()
+		err = sqlStore.Shutdown()
+		require.Nil(t, err)
+		if err = os.Remove(connectionString); err == nil {
+			logger.Debug("Removed test database", mlog.String("file", connectionString))
+		}
+		os.Setenv("FOCALBOARD_UNIT_TESTING", origUnitTesting)
+	}
+
+	return New(sqlStore, DefaultCacheSize), tearDown
+}
+
+func TestCacheLayer(t *testing.T) {
+	t.Run("BlocksStore", func(t *testing.T) { storetests.StoreTestBlocksStore(t, SetupTests) })
+	t.Run("CardsStore", func(t *testing.T) { storetests.StoreTestCardsStore(t, SetupTests) })
+	t.Run("SharingStore", func(t *testing.T) { storetests.StoreTestSharingStore(t, SetupTests) })
+	t.Run("SystemStore", func(t *testing.T) { storetests.StoreTestSystemStore(t, SetupTests) })
+	t.Run("UserStore", func(t *testing.T) { storetests.StoreTestUserStore(t, SetupTests) })
+	t.Run("SessionStore", func(t *testing.T) { storetests.StoreTestSessionStore(t, SetupTests) })
+	t.Run("TeamStore", func(t *testing.T) { storetests.StoreTestTeamStore(t, SetupTests) })
+	t.Run("BoardStore", func(t *testing.T) { storetests.StoreTestBoardStore(t, SetupTests) })
+	t.Run("BoardsAndBlocksStore", func(t *testing.T) { storetests.StoreTestBoardsAndBlocksStore(t, SetupTests) })
+	t.Run("SubscriptionStore", func(t *testing.T) { storetests.StoreTestSubscriptionsStore(t, SetupTests) })
+	t.Run("NotificationHintStore", func(t *testing.T) { storetests.StoreTestNotificationHintsStore(t, SetupTests) })
+	t.Run("DataRetention", func(t *testing.T) { storetests.StoreTestDataRetention(t, SetupTests) })
+	t.Run("CloudStore", func(t *testing.T) { storetests.StoreTestCloudStore(t, SetupTests) })
+	t.Run("StoreTestFileStore", func(t *testing.T) { storetests.StoreTestFileStore(t, SetupTests) })
+	t.Run("StoreTestCategoryStore", func(t *testing.T) { storetests.StoreTestCategoryStore(t, SetupTests) })
+	t.Run("StoreTestCategoryBoardsStore", func(t *testing.T) { storetests.StoreTestCategoryBoardsStore(t, SetupTests) })
+	t.Run("ComplianceHistoryStore", func(t *testing.T) { storetests.StoreTestComplianceHistoryStore(t, SetupTests) })
+	t.Run("TrashStore", func(t *testing.T) { storetests.StoreTestTrashStore(t, SetupTests) })
+}
+
+type testMetrics struct {
+	hits   map[string]int
+	misses map[string]int
+}
+
+func (m *testMetrics) IncrementStoreCacheHits(region string)   { m.hits[region]++ }
+func (m *testMetrics) IncrementStoreCacheMisses(region string) { m.misses[region]++ }
+
+func TestCachedReads(t *testing.T) {
+	s, tearDown := SetupTests(t)
+	defer tearDown()
+
+	metrics := &testMetrics{hits: map[string]int{}, misses: map[string]int{}}
+	cacheLayer := s.(*CacheLayer)
+	cacheLayer.SetMetrics(metrics)
+
+	board, member, err := s.InsertBoardWithAdmin(&model.Board{ID: "board-id", TeamID: "team-id", Type: model.BoardTypeOpen, Title: "title"}, "user-id")
+	require.NoError(t, err)
+
+	t.Run("reads are cached and copied", func(t *testing.T) {
+		cached, err := s.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "title", cached.Title)
+		cached.Title = "changed by the caller"
+
+		cached, err = s.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "title", cached.Title)
+		require.Equal(t, 1, metrics.misses["board"])
+		require.Equal(t, 1, metrics.hits["board"])
+
+		members, err := s.GetMembersForBoard(board.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		members[0].SchemeAdmin = false
+
+		cachedMember, err := s.GetMemberForBoard(board.ID, member.UserID)
+		require.NoError(t, err)
+		require.True(t, cachedMember.SchemeAdmin)
+	})
+
+	t.Run("writes invalidate the cached reads", func(t *testing.T) {
+		title := "new title"
+		minimumRole := model.BoardRoleEditor
+		_, err := s.PatchBoard(board.ID, &model.BoardPatch{Title: &title, MinimumRole: &minimumRole}, "user-id")
+		require.NoError(t, err)
+
+		cached, err := s.GetBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, "new title", cached.Title)
+
+		cachedMember, err := s.GetMemberForBoard(board.ID, member.UserID)
+		require.NoError(t, err)
+		require.EqualValues(t, model.BoardRoleEditor, cachedMember.MinimumRole)
+
+		require.NoError(t, s.DeleteMember(board.ID, member.UserID))
+		_, err = s.GetMemberForBoard(board.ID, member.UserID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		_, err := s.GetBoard("missing-board-id")
+		require.True(t, model.IsErrNotFound(err))
+		require.Equal(t, 1, cacheLayer.caches["board"].len())
+	})
+}
@@ -0,0 +1,81 @@
+package cachelayer
+
+import (
+	"fmt"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// cloneValue returns a deep copy of a cached value, so neither the caller
+// that stored it nor the ones that read it can modify the cache. Every
+// type returned by a cached method must be handled here.
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *model.Board:
+		if v == nil {
+			return v
+		}
+		board := *v
+		board.Properties = cloneMap(v.Properties)
+		if v.CardProperties != nil {
+			board.CardProperties = make([]map[string]interface{}, len(v.CardProperties))
+			for i, property := range v.CardProperties {
+				board.CardProperties[i] = cloneMap(property)
+			}
+		}
+		return &board
+	case *model.Block:
+		if v == nil {
+			return v
+		}
+		block := *v
+		block.Fields = cloneMap(v.Fields)
+		return &block
+	case *model.BoardMember:
+		if v == nil {
+			return v
+		}
+		member := *v
+		return &member
+	case []*model.BoardMember:
+		if v == nil {
+			return v
+		}
+		members := make([]*model.BoardMember, len(v))
+		for i, member := range v {
+			members[i] = cloneValue(member).(*model.BoardMember)
+		}
+		return members
+	default:
+		panic(fmt.Sprintf("cachelayer: cannot clone cached value of type %T", value))
+	}
+}
+
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		clone[k] = cloneJSONValue(v)
+	}
+	return clone
+}
+
+// cloneJSONValue copies the maps and slices of a value decoded from JSON.
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return cloneMap(v)
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneJSONValue(item)
+		}
+		return clone
+	case []string:
+		return append([]string(nil), v...)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Code generated by "make generate" from the Store interface
+// DO NOT EDIT
+
+// To cache a read method, prefix it in the Store interface with a
+// @cache comment followed by its cache region, and add an @invalidate
+// comment with the region, and optionally the key between parenthesis,
+// to every method that changes the data it returns before running
+// `make generate`
+
+package cachelayer
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// cacheRegions are the regions of the cache, one per @cache annotation
+// of the Store interface.
+var cacheRegions = []string{
+	"block",
+	"board",
+	"member",
+}
+
+func (c *CacheLayer) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.CreateBoardsAndBlocks(bab, userID)
+}
+
+func (c *CacheLayer) CreateBoardsAndBlocksWithAdmin(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.CreateBoardsAndBlocksWithAdmin(bab, userID)
+}
+
+func (c *CacheLayer) DeleteBlock(blockID string, modifiedBy string) error {
+	defer func() {
+		c.caches["block"].purge()
+	}()
+	return c.Store.DeleteBlock(blockID, modifiedBy)
+}
+
+func (c *CacheLayer) DeleteBlockRecord(blockID string, modifiedBy string) error {
+	defer func() {
+		c.caches["block"].purge()
+	}()
+	return c.Store.DeleteBlockRecord(blockID, modifiedBy)
+}
+
+func (c *CacheLayer) DeleteBoard(boardID string, userID string) error {
+	defer func() {
+		c.caches["board"].invalidate(boardID)
+		c.caches["member"].invalidate(boardID)
+		c.caches["block"].purge()
+	}()
+	return c.Store.DeleteBoard(boardID, userID)
+}
+
+func (c *CacheLayer) DeleteBoardRecord(boardID string, modifiedBy string) error {
+	defer func() {
+		c.caches["board"].invalidate(boardID)
+		c.caches["member"].invalidate(boardID)
+		c.caches["block"].purge()
+	}()
+	return c.Store.DeleteBoardRecord(boardID, modifiedBy)
+}
+
+func (c *CacheLayer) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.DeleteBoardsAndBlocks(dbab, userID)
+}
+
+func (c *CacheLayer) DeleteMember(boardID string, userID string) error {
+	defer func() {
+		c.caches["member"].invalidate(boardID)
+	}()
+	return c.Store.DeleteMember(boardID, userID)
+}
+
+func (c *CacheLayer) GetBlock(blockID string) (*model.Block, error) {
+	cache := c.caches["block"]
+	key := cacheKey("GetBlock", blockID)
+	if value, ok := cache.get(blockID, key); ok {
+		return value.(*model.Block), nil
+	}
+
+	generation := cache.generation()
+	result, err := c.Store.GetBlock(blockID)
+	if err != nil {
+		return result, err
+	}
+	cache.add(blockID, key, result, generation)
+	return result, nil
+}
+
+func (c *CacheLayer) GetBoard(id string) (*model.Board, error) {
+	cache := c.caches["board"]
+	key := cacheKey("GetBoard", id)
+	if value, ok := cache.get(id, key); ok {
+		return value.(*model.Board), nil
+	}
+
+	generation := cache.generation()
+	result, err := c.Store.GetBoard(id)
+	if err != nil {
+		return result, err
+	}
+	cache.add(id, key, result, generation)
+	return result, nil
+}
+
+func (c *CacheLayer) GetMemberForBoard(boardID string, userID string) (*model.BoardMember, error) {
+	cache := c.caches["member"]
+	key := cacheKey("GetMemberForBoard", boardID, userID)
+	if value, ok := cache.get(boardID, key); ok {
+		return value.(*model.BoardMember), nil
+	}
+
+	generation := cache.generation()
+	result, err := c.Store.GetMemberForBoard(boardID, userID)
+	if err != nil {
+		return result, err
+	}
+	cache.add(boardID, key, result, generation)
+	return result, nil
+}
+
+func (c *CacheLayer) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	cache := c.caches["member"]
+	key := cacheKey("GetMembersForBoard", boardID)
+	if value, ok := cache.get(boardID, key); ok {
+		return value.([]*model.BoardMember), nil
+	}
+
+	generation := cache.generation()
+	result, err := c.Store.GetMembersForBoard(boardID)
+	if err != nil {
+		return result, err
+	}
+	cache.add(boardID, key, result, generation)
+	return result, nil
+}
+
+func (c *CacheLayer) InsertBlock(block *model.Block, userID string) error {
+	defer func() {
+		c.caches["block"].invalidate(block.ID)
+	}()
+	return c.Store.InsertBlock(block, userID)
+}
+
+func (c *CacheLayer) InsertBlocks(blocks []*model.Block, userID string) error {
+	defer func() {
+		c.caches["block"].purge()
+	}()
+	return c.Store.InsertBlocks(blocks, userID)
+}
+
+func (c *CacheLayer) InsertBoard(board *model.Board, userID string) (*model.Board, error) {
+	defer func() {
+		c.caches["board"].invalidate(board.ID)
+		c.caches["member"].invalidate(board.ID)
+	}()
+	return c.Store.InsertBoard(board, userID)
+}
+
+func (c *CacheLayer) InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error) {
+	defer func() {
+		c.caches["board"].invalidate(board.ID)
+		c.caches["member"].invalidate(board.ID)
+	}()
+	return c.Store.InsertBoardWithAdmin(board, userID)
+}
+
+func (c *CacheLayer) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
+	defer func() {
+		c.caches["block"].invalidate(blockID)
+	}()
+	return c.Store.PatchBlock(blockID, blockPatch, userID)
+}
+
+func (c *CacheLayer) PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error {
+	defer func() {
+		c.caches["block"].purge()
+	}()
+	return c.Store.PatchBlocks(blockPatches, userID)
+}
+
+func (c *CacheLayer) PatchBoard(boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error) {
+	defer func() {
+		c.caches["board"].invalidate(boardID)
+		c.caches["member"].invalidate(boardID)
+	}()
+	return c.Store.PatchBoard(boardID, boardPatch, userID)
+}
+
+func (c *CacheLayer) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.PatchBoardsAndBlocks(pbab, userID)
+}
+
+func (c *CacheLayer) PurgeBoard(boardID string) error {
+	defer func() {
+		c.caches["member"].invalidate(boardID)
+	}()
+	return c.Store.PurgeBoard(boardID)
+}
+
+func (c *CacheLayer) RemoveDefaultTemplates(boards []*model.Board) error {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.RemoveDefaultTemplates(boards)
+}
+
+func (c *CacheLayer) RestoreBoardAndBlocks(board *model.Board, blocks []*model.Block, deletedBlockIDs []string, userID string) error {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.RestoreBoardAndBlocks(board, blocks, deletedBlockIDs, userID)
+}
+
+func (c *CacheLayer) RunDataRetention(globalRetentionDate int64, batchSize int64) (*model.DataRetentionReport, error) {
+	defer func() {
+		c.caches["board"].purge()
+		c.caches["member"].purge()
+		c.caches["block"].purge()
+	}()
+	return c.Store.RunDataRetention(globalRetentionDate, batchSize)
+}
+
+func (c *CacheLayer) SaveMember(bm *model.BoardMember) (*model.BoardMember, error) {
+	defer func() {
+		c.caches["member"].invalidate(bm.BoardID)
+	}()
+	return c.Store.SaveMember(bm)
+}
+
+func (c *CacheLayer) UndeleteBlock(blockID string, modifiedBy string) error {
+	defer func() {
+		c.caches["block"].purge()
+	}()
+	return c.Store.UndeleteBlock(blockID, modifiedBy)
+}
+
+func (c *CacheLayer) UndeleteBoard(boardID string, modifiedBy string) error {
+	defer func() {
+		c.caches["board"].invalidate(boardID)
+		c.caches["member"].invalidate(boardID)
+		c.caches["block"].purge()
+	}()
+	return c.Store.UndeleteBoard(boardID, modifiedBy)
+}
@@ -0,0 +1,137 @@
+package cachelayer
+
+import (
+	"container/list"
+	"sync"
+)
+
+type cacheEntry struct {
+	bucket string
+	key    string
+	value  interface{}
+}
+
+// regionCache is a bounded LRU cache. Its entries are grouped in buckets,
+// usually the ID of the board or block they belong to, so the entries of
+// a bucket can be invalidated at once.
+type regionCache struct {
+	name    string
+	size    int
+	metrics Metrics
+
+	mu         sync.Mutex
+	lru        *list.List
+	entries    map[string]*list.Element
+	buckets    map[string]map[string]*list.Element
+	currentGen uint64
+}
+
+func newRegionCache(name string, size int) *regionCache {
+	return &regionCache{
+		name:    name,
+		size:    size,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+		buckets: map[string]map[string]*list.Element{},
+	}
+}
+
+// get returns a copy of the cached value, so callers can modify it.
+func (c *regionCache) get(bucket, key string) (interface{}, bool) {
+	c.mu.Lock()
+	element, ok := c.entries[key]
+	var value interface{}
+	if ok {
+		c.lru.MoveToFront(element)
+		value = element.Value.(*cacheEntry).value
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		if c.metrics != nil {
+			c.metrics.IncrementStoreCacheMisses(c.name)
+		}
+		return nil, false
+	}
+
+	if c.metrics != nil {
+		c.metrics.IncrementStoreCacheHits(c.name)
+	}
+	return cloneValue(value), true
+}
+
+// generation returns the current generation of the cache. It must be
+// read before querying the store, and given back to add.
+func (c *regionCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.currentGen
+}
+
+// add caches a copy of the value, unless the cache was invalidated since
+// the given generation, in which case the value may be outdated.
+func (c *regionCache) add(bucket, key string, value interface{}, generation uint64) {
+	value = cloneValue(value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.currentGen {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheEntry).value = value
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	element := c.lru.PushFront(&cacheEntry{bucket: bucket, key: key, value: value})
+	c.entries[key] = element
+	if c.buckets[bucket] == nil {
+		c.buckets[bucket] = map[string]*list.Element{}
+	}
+	c.buckets[bucket][key] = element
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// invalidate removes the entries of a bucket.
+func (c *regionCache) invalidate(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.currentGen++
+	for _, element := range c.buckets[bucket] {
+		c.remove(element)
+	}
+}
+
+// purge removes all the entries.
+func (c *regionCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.currentGen++
+	c.lru.Init()
+	c.entries = map[string]*list.Element{}
+	c.buckets = map[string]map[string]*list.Element{}
+}
+
+func (c *regionCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *regionCache) remove(element *list.Element) {
+	entry := element.Value.(*cacheEntry)
+	c.lru.Remove(element)
+	delete(c.entries, entry.key)
+	delete(c.buckets[entry.bucket], entry.key)
+	if len(c.buckets[entry.bucket]) == 0 {
+		delete(c.buckets, entry.bucket)
+	}
+}
@@ -0,0 +1,72 @@
+package cachelayer
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionCache(t *testing.T) {
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+		cache := newRegionCache("block", 2)
+		cache.add("a", "a", &model.Block{ID: "a"}, cache.generation())
+		cache.add("b", "b", &model.Block{ID: "b"}, cache.generation())
+
+		_, ok := cache.get("a", "a")
+		require.True(t, ok)
+
+		cache.add("c", "c", &model.Block{ID: "c"}, cache.generation())
+		require.Equal(t, 2, cache.len())
+
+		_, ok = cache.get("b", "b")
+		require.False(t, ok)
+		_, ok = cache.get("a", "a")
+		require.True(t, ok)
+		_, ok = cache.get("c", "c")
+		require.True(t, ok)
+	})
+
+	t.Run("invalidate removes the entries of a bucket", func(t *testing.T) {
+		cache := newRegionCache("member", 10)
+		cache.add("board-1", "board-1:user-1", &model.BoardMember{UserID: "user-1"}, cache.generation())
+		cache.add("board-1", "board-1:user-2", &model.BoardMember{UserID: "user-2"}, cache.generation())
+		cache.add("board-2", "board-2:user-1", &model.BoardMember{UserID: "user-1"}, cache.generation())
+
+		cache.invalidate("board-1")
+		require.Equal(t, 1, cache.len())
+		_, ok := cache.get("board-2", "board-2:user-1")
+		require.True(t, ok)
+
+		cache.purge()
+		require.Zero(t, cache.len())
+	})
+
+	t.Run("values read before an invalidation are not cached", func(t *testing.T) {
+		cache := newRegionCache("board", 10)
+		generation := cache.generation()
+		cache.invalidate("board-id")
+
+		cache.add("board-id", "board-id", &model.Board{ID: "board-id"}, generation)
+		_, ok := cache.get("board-id", "board-id")
+		require.False(t, ok)
+	})
+
+	t.Run("cached values are copies", func(t *testing.T) {
+		cache := newRegionCache("board", 10)
+		board := &model.Board{
+			ID:             "board-id",
+			Properties:     map[string]interface{}{"key": []interface{}{"value"}},
+			CardProperties: []map[string]interface{}{{"id": "property-id"}},
+		}
+		cache.add(board.ID, board.ID, board, cache.generation())
+		board.Properties["key"].([]interface{})[0] = "changed"
+		board.CardProperties[0]["id"] = "changed"
+
+		value, ok := cache.get(board.ID, board.ID)
+		require.True(t, ok)
+		cached := value.(*model.Board)
+		require.Equal(t, []interface{}{"value"}, cached.Properties["key"])
+		require.Equal(t, "property-id", cached.CardProperties[0]["id"])
+	})
+}
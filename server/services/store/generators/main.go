@@ -14,12 +14,15 @@ import (
 	"log"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"text/template"
 )
 
 const (
 	WithTransactionComment = "@withTransaction"
+	CacheComment           = "@cache"
+	InvalidateComment      = "@invalidate"
 	ErrorType              = "error"
 	StringType             = "string"
 	IntType                = "int"
@@ -48,6 +51,12 @@ func main() {
 	if err := buildTransactionalStore(); err != nil {
 		log.Fatal(err)
 	}
+	if err := buildCacheLayer(); err != nil {
+		log.Fatal(err)
+	}
+	if err := buildMetricsLayer(); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func buildTransactionalStore() error {
@@ -63,20 +72,54 @@ func buildTransactionalStore() error {
 	return os.WriteFile(path.Join("sqlstore/public_methods.go"), formatedCode, 0644) //nolint:gosec
 }
 
+func buildCacheLayer() error {
+	code, err := generateLayer("CacheLayer", "cache_layer.go.tmpl")
+	if err != nil {
+		return err
+	}
+	formatedCode, err := format.Source(code)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join("cachelayer/public_methods.go"), formatedCode, 0644) //nolint:gosec
+}
+
+func buildMetricsLayer() error {
+	code, err := generateLayer("MetricsLayer", "metrics_layer.go.tmpl")
+	if err != nil {
+		return err
+	}
+	formatedCode, err := format.Source(code)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join("metricslayer/public_methods.go"), formatedCode, 0644) //nolint:gosec
+}
+
 type methodParam struct {
 	Name string
 	Type string
 }
 
+type cacheInvalidation struct {
+	Region string
+	Key    string
+}
+
 type methodData struct {
 	Params          []methodParam
 	Results         []string
 	WithTransaction bool
+	CacheRegion     string
+	Invalidations   []cacheInvalidation
 }
 
 type storeMetadata struct {
-	Name    string
-	Methods map[string]methodData
+	Name         string
+	Methods      map[string]methodData
+	CacheRegions []string
 }
 
 var blacklistedStoreMethodNames = map[string]bool{
@@ -89,15 +132,21 @@ func extractMethodMetadata(method *ast.Field, src []byte) methodData {
 	params := []methodParam{}
 	results := []string{}
 	withTransaction := false
+	cacheRegion := ""
+	invalidations := []cacheInvalidation{}
 	ast.Inspect(method.Type, func(expr ast.Node) bool {
 		//nolint:gocritic
 		switch e := expr.(type) {
 		case *ast.FuncType:
 			if method.Doc != nil {
 				for _, comment := range method.Doc.List {
-					if strings.Contains(comment.Text, WithTransactionComment) {
+					switch {
+					case strings.Contains(comment.Text, WithTransactionComment):
 						withTransaction = true
-						break
+					case strings.Contains(comment.Text, CacheComment):
+						cacheRegion = commentArgs(comment.Text, CacheComment)[0]
+					case strings.Contains(comment.Text, InvalidateComment):
+						invalidations = parseInvalidations(commentArgs(comment.Text, InvalidateComment))
 					}
 				}
 			}
@@ -116,7 +165,77 @@ func extractMethodMetadata(method *ast.Field, src []byte) methodData {
 		}
 		return true
 	})
-	return methodData{Params: params, Results: results, WithTransaction: withTransaction}
+	return methodData{
+		Params:          params,
+		Results:         results,
+		WithTransaction: withTransaction,
+		CacheRegion:     cacheRegion,
+		Invalidations:   invalidations,
+	}
+}
+
+// commentArgs returns the space separated arguments that follow an
+// annotation in a comment.
+func commentArgs(text, annotation string) []string {
+	args := strings.Fields(text[strings.Index(text, annotation)+len(annotation):])
+	if len(args) == 0 {
+		return []string{""}
+	}
+	return args
+}
+
+// parseInvalidations parses the arguments of an @invalidate comment. Each
+// argument is either a cache region, to invalidate all of it, or a region
+// followed by a Go expression between parenthesis, to invalidate only the
+// entries cached for that key.
+func parseInvalidations(args []string) []cacheInvalidation {
+	invalidations := []cacheInvalidation{}
+	for _, arg := range args {
+		region, key, found := strings.Cut(arg, "(")
+		if found {
+			key = strings.TrimSuffix(key, ")")
+		}
+		invalidations = append(invalidations, cacheInvalidation{Region: region, Key: key})
+	}
+	return invalidations
+}
+
+// validateCacheMetadata checks that the cached methods can be generated:
+// they must take only string parameters, the first one being the key
+// used to invalidate them, and return a value and an error.
+func validateCacheMetadata(metadata *storeMetadata) error {
+	regions := map[string]bool{}
+	for name, method := range metadata.Methods {
+		if method.CacheRegion != "" {
+			if len(method.Params) == 0 {
+				return fmt.Errorf("cached method %s must have at least one parameter", name)
+			}
+			for _, param := range method.Params {
+				if !isString(param.Type) {
+					return fmt.Errorf("cached method %s can only have string parameters", name)
+				}
+			}
+			if len(method.Results) != 2 || !isError(method.Results[1]) {
+				return fmt.Errorf("cached method %s must return a value and an error", name)
+			}
+			regions[method.CacheRegion] = true
+		}
+	}
+
+	for name, method := range metadata.Methods {
+		for _, invalidation := range method.Invalidations {
+			if !regions[invalidation.Region] {
+				return fmt.Errorf("method %s invalidates unknown cache region %q", name, invalidation.Region)
+			}
+		}
+	}
+
+	metadata.CacheRegions = make([]string, 0, len(regions))
+	for region := range regions {
+		metadata.CacheRegions = append(metadata.CacheRegions, region)
+	}
+	sort.Strings(metadata.CacheRegions)
+	return nil
 }
 
 func extractStoreMetadata() (*storeMetadata, error) {
@@ -168,6 +287,10 @@ func generateLayer(name, templateFile string) ([]byte, error) {
 	}
 	metadata.Name = name
 
+	if err = validateCacheMetadata(metadata); err != nil {
+		return nil, err
+	}
+
 	myFuncs := template.FuncMap{
 		"joinResultsForSignature": func(results []string) string {
 			if len(results) == 0 {
@@ -212,6 +335,9 @@ func generateLayer(name, templateFile string) ([]byte, error) {
 			}
 			return ""
 		},
+		"firstParam": func(params []methodParam) string {
+			return params[0].Name
+		},
 		"joinParams": func(params []methodParam) string {
 			paramsNames := make([]string, 0, len(params))
 			for _, param := range params {
@@ -0,0 +1,45 @@
+package metricslayer
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+// Metrics receives the duration and the result of every store call.
+type Metrics interface {
+	ObserveStoreMethodDuration(method string, success bool, elapsed float64)
+}
+
+// MetricsLayer is a store decorator that records the latency and the
+// errors of every method of the store.
+type MetricsLayer struct {
+	store.Store
+	metrics Metrics
+}
+
+// New creates a metrics layer on top of the store. The metrics are set
+// with SetMetrics, as they are usually created after the store.
+func New(store store.Store) *MetricsLayer {
+	return &MetricsLayer{
+		Store: store,
+	}
+}
+
+// SetMetrics sets the metrics that receive the store calls. It must be
+// called before the layer is used.
+func (s *MetricsLayer) SetMetrics(metrics Metrics) {
+	s.metrics = metrics
+}
+
+// observe records a store call. Not found errors are an expected result
+// of many queries, so they are not counted as errors.
+func (s *MetricsLayer) observe(method string, start time.Time, err error) {
+	if s.metrics == nil {
+		return
+	}
+
+	success := err == nil || model.IsErrNotFound(err)
+	s.metrics.ObserveStoreMethodDuration(method, success, time.Since(start).Seconds())
+}
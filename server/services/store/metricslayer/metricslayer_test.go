@@ -0,0 +1,72 @@
+package metricslayer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store/mockstore"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	method  string
+	success bool
+}
+
+type testMetrics struct {
+	observations []observation
+}
+
+func (m *testMetrics) ObserveStoreMethodDuration(method string, success bool, elapsed float64) {
+	m.observations = append(m.observations, observation{method: method, success: success})
+}
+
+func TestMetricsLayer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStore := mockstore.NewMockStore(ctrl)
+
+	metrics := &testMetrics{}
+	metricsLayer := New(mockStore)
+	metricsLayer.SetMetrics(metrics)
+
+	t.Run("successful calls are recorded and their results returned", func(t *testing.T) {
+		metrics.observations = nil
+		board := &model.Board{ID: "board-id"}
+		mockStore.EXPECT().GetBoard("board-id").Return(board, nil)
+
+		result, err := metricsLayer.GetBoard("board-id")
+		require.NoError(t, err)
+		require.Equal(t, board, result)
+		require.Equal(t, []observation{{method: "GetBoard", success: true}}, metrics.observations)
+	})
+
+	t.Run("not found errors are not recorded as failures", func(t *testing.T) {
+		metrics.observations = nil
+		mockStore.EXPECT().GetBoard("board-id").Return(nil, model.NewErrNotFound("board ID=board-id"))
+
+		_, err := metricsLayer.GetBoard("board-id")
+		require.True(t, model.IsErrNotFound(err))
+		require.Equal(t, []observation{{method: "GetBoard", success: true}}, metrics.observations)
+	})
+
+	t.Run("errors are recorded as failures", func(t *testing.T) {
+		metrics.observations = nil
+		mockStore.EXPECT().DeleteBoard("board-id", "user-id").Return(errors.New("database is gone"))
+
+		err := metricsLayer.DeleteBoard("board-id", "user-id")
+		require.EqualError(t, err, "database is gone")
+		require.Equal(t, []observation{{method: "DeleteBoard", success: false}}, metrics.observations)
+	})
+
+	t.Run("calls are not recorded without metrics", func(t *testing.T) {
+		metrics.observations = nil
+		mockStore.EXPECT().GetBoard("board-id").Return(&model.Board{ID: "board-id"}, nil)
+
+		_, err := New(mockStore).GetBoard("board-id")
+		require.NoError(t, err)
+		require.Empty(t, metrics.observations)
+	})
+}
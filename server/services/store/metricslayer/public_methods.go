@@ -0,0 +1,1313 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Code generated by "make generate" from the Store interface
+// DO NOT EDIT
+
+// Every method of the Store interface is instrumented, so there is
+// nothing to annotate to add a method to this layer
+
+package metricslayer
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func (s *MetricsLayer) AddUpdateCategoryBoard(userID string, categoryID string, boardIDs []string) error {
+	start := time.Now()
+	err := s.Store.AddUpdateCategoryBoard(userID, categoryID, boardIDs)
+	s.observe("AddUpdateCategoryBoard", start, err)
+	return err
+}
+
+func (s *MetricsLayer) AddUpdateViewCategoryView(userID string, categoryID string, viewIDs []string) error {
+	start := time.Now()
+	err := s.Store.AddUpdateViewCategoryView(userID, categoryID, viewIDs)
+	s.observe("AddUpdateViewCategoryView", start, err)
+	return err
+}
+
+func (s *MetricsLayer) CanSeeUser(seerID string, seenID string) (bool, error) {
+	start := time.Now()
+	result, err := s.Store.CanSeeUser(seerID, seenID)
+	s.observe("CanSeeUser", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) ClaimRecurringCardRuleRun(ruleID string, nextRunAt int64, newNextRunAt int64) (bool, error) {
+	start := time.Now()
+	result, err := s.Store.ClaimRecurringCardRuleRun(ruleID, nextRunAt, newNextRunAt)
+	s.observe("ClaimRecurringCardRuleRun", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) ClaimWebhookDelivery(deliveryID string, nextAttemptAt int64, newNextAttemptAt int64) (bool, error) {
+	start := time.Now()
+	result, err := s.Store.ClaimWebhookDelivery(deliveryID, nextAttemptAt, newNextAttemptAt)
+	s.observe("ClaimWebhookDelivery", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) CleanUpSessions(expireTime int64) error {
+	start := time.Now()
+	err := s.Store.CleanUpSessions(expireTime)
+	s.observe("CleanUpSessions", start, err)
+	return err
+}
+
+func (s *MetricsLayer) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	start := time.Now()
+	result, err := s.Store.CreateBoardsAndBlocks(bab, userID)
+	s.observe("CreateBoardsAndBlocks", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) CreateBoardsAndBlocksWithAdmin(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.CreateBoardsAndBlocksWithAdmin(bab, userID)
+	s.observe("CreateBoardsAndBlocksWithAdmin", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) CreateCategory(category model.Category) error {
+	start := time.Now()
+	err := s.Store.CreateCategory(category)
+	s.observe("CreateCategory", start, err)
+	return err
+}
+
+func (s *MetricsLayer) CreateSession(session *model.Session) error {
+	start := time.Now()
+	err := s.Store.CreateSession(session)
+	s.observe("CreateSession", start, err)
+	return err
+}
+
+func (s *MetricsLayer) CreateSubscription(sub *model.Subscription) (*model.Subscription, error) {
+	start := time.Now()
+	result, err := s.Store.CreateSubscription(sub)
+	s.observe("CreateSubscription", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) CreateUser(user *model.User) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.CreateUser(user)
+	s.observe("CreateUser", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) CreateViewCategory(viewCategory model.ViewCategory) error {
+	start := time.Now()
+	err := s.Store.CreateViewCategory(viewCategory)
+	s.observe("CreateViewCategory", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteAutomationExecutionsBefore(createAt int64) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.DeleteAutomationExecutionsBefore(createAt)
+	s.observe("DeleteAutomationExecutionsBefore", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) DeleteAutomationRule(ruleID string) error {
+	start := time.Now()
+	err := s.Store.DeleteAutomationRule(ruleID)
+	s.observe("DeleteAutomationRule", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteBlock(blockID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.DeleteBlock(blockID, modifiedBy)
+	s.observe("DeleteBlock", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteBlockRecord(blockID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.DeleteBlockRecord(blockID, modifiedBy)
+	s.observe("DeleteBlockRecord", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteBoard(boardID string, userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteBoard(boardID, userID)
+	s.observe("DeleteBoard", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteBoardRecord(boardID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.DeleteBoardRecord(boardID, modifiedBy)
+	s.observe("DeleteBoardRecord", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteBoardsAndBlocks(dbab, userID)
+	s.observe("DeleteBoardsAndBlocks", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteCardDependency(blockerID string, blockedID string) error {
+	start := time.Now()
+	err := s.Store.DeleteCardDependency(blockerID, blockedID)
+	s.observe("DeleteCardDependency", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteCategory(categoryID string, userID string, teamID string) error {
+	start := time.Now()
+	err := s.Store.DeleteCategory(categoryID, userID, teamID)
+	s.observe("DeleteCategory", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteDueDateReminderSettings(boardID string) error {
+	start := time.Now()
+	err := s.Store.DeleteDueDateReminderSettings(boardID)
+	s.observe("DeleteDueDateReminderSettings", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteIncomingWebhook(webhookID string) error {
+	start := time.Now()
+	err := s.Store.DeleteIncomingWebhook(webhookID)
+	s.observe("DeleteIncomingWebhook", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteMember(boardID string, userID string) error {
+	start := time.Now()
+	err := s.Store.DeleteMember(boardID, userID)
+	s.observe("DeleteMember", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteNotificationHint(blockID string) error {
+	start := time.Now()
+	err := s.Store.DeleteNotificationHint(blockID)
+	s.observe("DeleteNotificationHint", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteRecurringCardRule(ruleID string) error {
+	start := time.Now()
+	err := s.Store.DeleteRecurringCardRule(ruleID)
+	s.observe("DeleteRecurringCardRule", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteRetentionPolicy(teamID string, boardID string) error {
+	start := time.Now()
+	err := s.Store.DeleteRetentionPolicy(teamID, boardID)
+	s.observe("DeleteRetentionPolicy", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteSession(sessionID string) error {
+	start := time.Now()
+	err := s.Store.DeleteSession(sessionID)
+	s.observe("DeleteSession", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteSubscription(blockID string, subscriberID string) error {
+	start := time.Now()
+	err := s.Store.DeleteSubscription(blockID, subscriberID)
+	s.observe("DeleteSubscription", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteViewCategory(categoryID string, userID string, boardID string) error {
+	start := time.Now()
+	err := s.Store.DeleteViewCategory(categoryID, userID, boardID)
+	s.observe("DeleteViewCategory", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteWebhook(webhookID string) error {
+	start := time.Now()
+	err := s.Store.DeleteWebhook(webhookID)
+	s.observe("DeleteWebhook", start, err)
+	return err
+}
+
+func (s *MetricsLayer) DeleteWebhookDeliveriesBefore(createAt int64) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.DeleteWebhookDeliveriesBefore(createAt)
+	s.observe("DeleteWebhookDeliveriesBefore", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.DuplicateBlock(boardID, blockID, userID, asTemplate)
+	s.observe("DuplicateBlock", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) DuplicateBoard(boardID string, userID string, toTeam string, asTemplate bool) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.DuplicateBoard(boardID, userID, toTeam, asTemplate)
+	s.observe("DuplicateBoard", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetActiveUserCount(updatedSecondsAgo)
+	s.observe("GetActiveUserCount", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetActiveWebhooks(teamID string, boardID string) ([]*model.Webhook, error) {
+	start := time.Now()
+	result, err := s.Store.GetActiveWebhooks(teamID, boardID)
+	s.observe("GetActiveWebhooks", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetAllTeams() ([]*model.Team, error) {
+	start := time.Now()
+	result, err := s.Store.GetAllTeams()
+	s.observe("GetAllTeams", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetAutomationExecutions(opts model.QueryAutomationExecutionsOptions) ([]*model.AutomationExecution, error) {
+	start := time.Now()
+	result, err := s.Store.GetAutomationExecutions(opts)
+	s.observe("GetAutomationExecutions", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	start := time.Now()
+	result, err := s.Store.GetAutomationRule(ruleID)
+	s.observe("GetAutomationRule", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error) {
+	start := time.Now()
+	result, err := s.Store.GetAutomationRulesForBoard(boardID)
+	s.observe("GetAutomationRulesForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlock(blockID string) (*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlock(blockID)
+	s.observe("GetBlock", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlockCountsByType() (map[string]int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockCountsByType()
+	s.observe("GetBlockCountsByType", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockHistory(blockID, opts)
+	s.observe("GetBlockHistory", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlockHistoryDescendants(boardID, opts)
+	s.observe("GetBlockHistoryDescendants", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBlockHistoryNewestChildren(parentID, opts)
+	s.observe("GetBlockHistoryNewestChildren", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBlockHistoryNewestDescendants(boardID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBlockHistoryNewestDescendants(boardID, opts)
+	s.observe("GetBlockHistoryNewestDescendants", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocks(opts)
+	s.observe("GetBlocks", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlocksByIDs(ids []string) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksByIDs(ids)
+	s.observe("GetBlocksByIDs", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlocksComplianceHistory(opts model.QueryBlocksComplianceHistoryOptions) ([]*model.BlockHistory, bool, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBlocksComplianceHistory(opts)
+	s.observe("GetBlocksComplianceHistory", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksForBoard(boardID)
+	s.observe("GetBlocksForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlocksWithParent(boardID string, parentID string) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithParent(boardID, parentID)
+	s.observe("GetBlocksWithParent", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlocksWithParentAndType(boardID string, parentID string, blockType string) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithParentAndType(boardID, parentID, blockType)
+	s.observe("GetBlocksWithParentAndType", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBlocksWithType(boardID string, blockType string) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetBlocksWithType(boardID, blockType)
+	s.observe("GetBlocksWithType", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBoard(id string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoard(id)
+	s.observe("GetBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBoardAndCard(block *model.Block) (*model.Board, *model.Block, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBoardAndCard(block)
+	s.observe("GetBoardAndCard", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBoardAndCardByID(blockID string) (*model.Board, *model.Block, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBoardAndCardByID(blockID)
+	s.observe("GetBoardAndCardByID", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBoardCount() (int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardCount()
+	s.observe("GetBoardCount", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardHistory(boardID, opts)
+	s.observe("GetBoardHistory", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBoardMemberHistory(boardID string, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardMemberHistory(boardID, userID, limit)
+	s.observe("GetBoardMemberHistory", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBoardsComplianceHistory(opts)
+	s.observe("GetBoardsComplianceHistory", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBoardsForCompliance(opts model.QueryBoardsForComplianceOptions) ([]*model.Board, bool, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.GetBoardsForCompliance(opts)
+	s.observe("GetBoardsForCompliance", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) GetBoardsForUserAndTeam(userID string, teamID string, includePublicBoards bool) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
+	s.observe("GetBoardsForUserAndTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetBoardsInTeamByIds(boardIDs, teamID)
+	s.observe("GetBoardsInTeamByIds", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetCardDependencies(opts model.QueryCardDependenciesOptions) ([]*model.CardDependency, error) {
+	start := time.Now()
+	result, err := s.Store.GetCardDependencies(opts)
+	s.observe("GetCardDependencies", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetCardLimitTimestamp() (int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetCardLimitTimestamp()
+	s.observe("GetCardLimitTimestamp", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetCategory(id string) (*model.Category, error) {
+	start := time.Now()
+	result, err := s.Store.GetCategory(id)
+	s.observe("GetCategory", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
+	start := time.Now()
+	result, err := s.Store.GetChannel(teamID, channelID)
+	s.observe("GetChannel", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetChecklistProgress(cardIDs []string) (map[string]model.ChecklistProgress, error) {
+	start := time.Now()
+	result, err := s.Store.GetChecklistProgress(cardIDs)
+	s.observe("GetChecklistProgress", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetDeletedBlocksForBoard(boardID string, blockTypes []model.BlockType) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetDeletedBlocksForBoard(boardID, blockTypes)
+	s.observe("GetDeletedBlocksForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetDeletedBoardsForTeam(teamID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetDeletedBoardsForTeam(teamID)
+	s.observe("GetDeletedBoardsForTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error) {
+	start := time.Now()
+	result, err := s.Store.GetDueDateReminderSettings(boardID)
+	s.observe("GetDueDateReminderSettings", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetDueRecurringCardRules(now int64, limit int) ([]*model.RecurringCardRule, error) {
+	start := time.Now()
+	result, err := s.Store.GetDueRecurringCardRules(now, limit)
+	s.observe("GetDueRecurringCardRules", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetDueWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error) {
+	start := time.Now()
+	result, err := s.Store.GetDueWebhookDeliveries(now, limit)
+	s.observe("GetDueWebhookDeliveries", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetEnabledAutomationRules(triggerType string) ([]*model.AutomationRule, error) {
+	start := time.Now()
+	result, err := s.Store.GetEnabledAutomationRules(triggerType)
+	s.observe("GetEnabledAutomationRules", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
+	start := time.Now()
+	result, err := s.Store.GetEnabledDueDateReminderSettings()
+	s.observe("GetEnabledDueDateReminderSettings", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetFileInfo(id string) (*mmModel.FileInfo, error) {
+	start := time.Now()
+	result, err := s.Store.GetFileInfo(id)
+	s.observe("GetFileInfo", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetIncomingWebhook(webhookID string) (*model.IncomingWebhook, error) {
+	start := time.Now()
+	result, err := s.Store.GetIncomingWebhook(webhookID)
+	s.observe("GetIncomingWebhook", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetIncomingWebhooksForBoard(boardID string) ([]*model.IncomingWebhook, error) {
+	start := time.Now()
+	result, err := s.Store.GetIncomingWebhooksForBoard(boardID)
+	s.observe("GetIncomingWebhooksForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetLicense() *mmModel.License {
+	start := time.Now()
+	result := s.Store.GetLicense()
+	s.observe("GetLicense", start, nil)
+	return result
+}
+
+func (s *MetricsLayer) GetMemberForBoard(boardID string, userID string) (*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.GetMemberForBoard(boardID, userID)
+	s.observe("GetMemberForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.GetMembersForBoard(boardID)
+	s.observe("GetMembersForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.GetMembersForUser(userID)
+	s.observe("GetMembersForUser", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetNextNotificationHint(remove bool) (*model.NotificationHint, error) {
+	start := time.Now()
+	result, err := s.Store.GetNextNotificationHint(remove)
+	s.observe("GetNextNotificationHint", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetNotificationHint(blockID string) (*model.NotificationHint, error) {
+	start := time.Now()
+	result, err := s.Store.GetNotificationHint(blockID)
+	s.observe("GetNotificationHint", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetRecurringCardRule(ruleID string) (*model.RecurringCardRule, error) {
+	start := time.Now()
+	result, err := s.Store.GetRecurringCardRule(ruleID)
+	s.observe("GetRecurringCardRule", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetRecurringCardRulesForBoard(boardID string) ([]*model.RecurringCardRule, error) {
+	start := time.Now()
+	result, err := s.Store.GetRecurringCardRulesForBoard(boardID)
+	s.observe("GetRecurringCardRulesForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetRegisteredUserCount() (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetRegisteredUserCount()
+	s.observe("GetRegisteredUserCount", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error) {
+	start := time.Now()
+	result, err := s.Store.GetRetentionPolicies(teamID)
+	s.observe("GetRetentionPolicies", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSession(token string, expireTime int64) (*model.Session, error) {
+	start := time.Now()
+	result, err := s.Store.GetSession(token, expireTime)
+	s.observe("GetSession", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSharing(rootID string) (*model.Sharing, error) {
+	start := time.Now()
+	result, err := s.Store.GetSharing(rootID)
+	s.observe("GetSharing", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSubTree2(boardID string, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubTree2(boardID, blockID, opts)
+	s.observe("GetSubTree2", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSubscribersCountForBlock(blockID string) (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscribersCountForBlock(blockID)
+	s.observe("GetSubscribersCountForBlock", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSubscribersForBlock(blockID string) ([]*model.Subscriber, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscribersForBlock(blockID)
+	s.observe("GetSubscribersForBlock", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSubscription(blockID string, subscriberID string) (*model.Subscription, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscription(blockID, subscriberID)
+	s.observe("GetSubscription", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSubscriptions(subscriberID string) ([]*model.Subscription, error) {
+	start := time.Now()
+	result, err := s.Store.GetSubscriptions(subscriberID)
+	s.observe("GetSubscriptions", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSystemSetting(key string) (string, error) {
+	start := time.Now()
+	result, err := s.Store.GetSystemSetting(key)
+	s.observe("GetSystemSetting", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetSystemSettings() (map[string]string, error) {
+	start := time.Now()
+	result, err := s.Store.GetSystemSettings()
+	s.observe("GetSystemSettings", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetTeam(ID string) (*model.Team, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeam(ID)
+	s.observe("GetTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetTeamCount() (int64, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeamCount()
+	s.observe("GetTeamCount", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetTeamsForUser(userID string) ([]*model.Team, error) {
+	start := time.Now()
+	result, err := s.Store.GetTeamsForUser(userID)
+	s.observe("GetTeamsForUser", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetTemplateBoards(teamID string, userID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.GetTemplateBoards(teamID, userID)
+	s.observe("GetTemplateBoards", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUsedCardsCount() (int, error) {
+	start := time.Now()
+	result, err := s.Store.GetUsedCardsCount()
+	s.observe("GetUsedCardsCount", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserByEmail(email string) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserByEmail(email)
+	s.observe("GetUserByEmail", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserByID(userID string) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserByID(userID)
+	s.observe("GetUserByID", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserByUsername(username string) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserByUsername(username)
+	s.observe("GetUserByUsername", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserCategories(userID string, teamID string) ([]model.Category, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserCategories(userID, teamID)
+	s.observe("GetUserCategories", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserCategoryBoards(userID string, teamID string) ([]model.CategoryBoards, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserCategoryBoards(userID, teamID)
+	s.observe("GetUserCategoryBoards", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserPreferences(userID string) (mmModel.Preferences, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserPreferences(userID)
+	s.observe("GetUserPreferences", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserTimezone(userID string) (string, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserTimezone(userID)
+	s.observe("GetUserTimezone", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserViewCategories(userID string, boardID string) ([]model.ViewCategory, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserViewCategories(userID, boardID)
+	s.observe("GetUserViewCategories", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUserViewCategoryViews(userID string, boardID string) ([]model.ViewCategoryViews, error) {
+	start := time.Now()
+	result, err := s.Store.GetUserViewCategoryViews(userID, boardID)
+	s.observe("GetUserViewCategoryViews", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUsersByTeam(teamID string, asGuestID string, showEmail bool, showName bool) ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUsersByTeam(teamID, asGuestID, showEmail, showName)
+	s.observe("GetUsersByTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetUsersList(userIDs []string, showEmail bool, showName bool) ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.GetUsersList(userIDs, showEmail, showName)
+	s.observe("GetUsersList", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetViewCategory(id string) (*model.ViewCategory, error) {
+	start := time.Now()
+	result, err := s.Store.GetViewCategory(id)
+	s.observe("GetViewCategory", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetWebhook(webhookID string) (*model.Webhook, error) {
+	start := time.Now()
+	result, err := s.Store.GetWebhook(webhookID)
+	s.observe("GetWebhook", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetWebhookDeliveries(opts model.QueryWebhookDeliveriesOptions) ([]*model.WebhookDelivery, error) {
+	start := time.Now()
+	result, err := s.Store.GetWebhookDeliveries(opts)
+	s.observe("GetWebhookDeliveries", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetWebhookDelivery(deliveryID string) (*model.WebhookDelivery, error) {
+	start := time.Now()
+	result, err := s.Store.GetWebhookDelivery(deliveryID)
+	s.observe("GetWebhookDelivery", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetWebhooksForBoard(boardID string) ([]*model.Webhook, error) {
+	start := time.Now()
+	result, err := s.Store.GetWebhooksForBoard(boardID)
+	s.observe("GetWebhooksForBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) GetWebhooksForTeam(teamID string) ([]*model.Webhook, error) {
+	start := time.Now()
+	result, err := s.Store.GetWebhooksForTeam(teamID)
+	s.observe("GetWebhooksForTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) IncrementAutomationRuleRunCount(ruleID string) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.IncrementAutomationRuleRunCount(ruleID)
+	s.observe("IncrementAutomationRuleRunCount", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertAutomationDueDateRun(run *model.AutomationDueDateRun) (bool, error) {
+	start := time.Now()
+	result, err := s.Store.InsertAutomationDueDateRun(run)
+	s.observe("InsertAutomationDueDateRun", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertAutomationExecution(execution *model.AutomationExecution) (*model.AutomationExecution, error) {
+	start := time.Now()
+	result, err := s.Store.InsertAutomationExecution(execution)
+	s.observe("InsertAutomationExecution", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertAutomationRule(rule *model.AutomationRule) (*model.AutomationRule, error) {
+	start := time.Now()
+	result, err := s.Store.InsertAutomationRule(rule)
+	s.observe("InsertAutomationRule", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertBlock(block *model.Block, userID string) error {
+	start := time.Now()
+	err := s.Store.InsertBlock(block, userID)
+	s.observe("InsertBlock", start, err)
+	return err
+}
+
+func (s *MetricsLayer) InsertBlocks(blocks []*model.Block, userID string) error {
+	start := time.Now()
+	err := s.Store.InsertBlocks(blocks, userID)
+	s.observe("InsertBlocks", start, err)
+	return err
+}
+
+func (s *MetricsLayer) InsertBoard(board *model.Board, userID string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.InsertBoard(board, userID)
+	s.observe("InsertBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error) {
+	start := time.Now()
+	result, resultVar1, err := s.Store.InsertBoardWithAdmin(board, userID)
+	s.observe("InsertBoardWithAdmin", start, err)
+	return result, resultVar1, err
+}
+
+func (s *MetricsLayer) InsertCardDependency(dependency *model.CardDependency) (*model.CardDependency, error) {
+	start := time.Now()
+	result, err := s.Store.InsertCardDependency(dependency)
+	s.observe("InsertCardDependency", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertDueDateReminder(reminder *model.DueDateReminder) (bool, error) {
+	start := time.Now()
+	result, err := s.Store.InsertDueDateReminder(reminder)
+	s.observe("InsertDueDateReminder", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	start := time.Now()
+	result, err := s.Store.InsertIncomingWebhook(webhook)
+	s.observe("InsertIncomingWebhook", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertRecurringCardRule(rule *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	start := time.Now()
+	result, err := s.Store.InsertRecurringCardRule(rule)
+	s.observe("InsertRecurringCardRule", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertWebhook(webhook *model.Webhook) (*model.Webhook, error) {
+	start := time.Now()
+	result, err := s.Store.InsertWebhook(webhook)
+	s.observe("InsertWebhook", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) InsertWebhookDelivery(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	start := time.Now()
+	result, err := s.Store.InsertWebhookDelivery(delivery)
+	s.observe("InsertWebhookDelivery", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
+	start := time.Now()
+	err := s.Store.PatchBlock(blockID, blockPatch, userID)
+	s.observe("PatchBlock", start, err)
+	return err
+}
+
+func (s *MetricsLayer) PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error {
+	start := time.Now()
+	err := s.Store.PatchBlocks(blockPatches, userID)
+	s.observe("PatchBlocks", start, err)
+	return err
+}
+
+func (s *MetricsLayer) PatchBoard(boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.PatchBoard(boardID, boardPatch, userID)
+	s.observe("PatchBoard", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	start := time.Now()
+	result, err := s.Store.PatchBoardsAndBlocks(pbab, userID)
+	s.observe("PatchBoardsAndBlocks", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) PatchUserPreferences(userID string, patch model.UserPreferencesPatch) (mmModel.Preferences, error) {
+	start := time.Now()
+	result, err := s.Store.PatchUserPreferences(userID, patch)
+	s.observe("PatchUserPreferences", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) PostMessage(message string, postType string, channelID string) error {
+	start := time.Now()
+	err := s.Store.PostMessage(message, postType, channelID)
+	s.observe("PostMessage", start, err)
+	return err
+}
+
+func (s *MetricsLayer) PurgeBlock(blockID string) error {
+	start := time.Now()
+	err := s.Store.PurgeBlock(blockID)
+	s.observe("PurgeBlock", start, err)
+	return err
+}
+
+func (s *MetricsLayer) PurgeBoard(boardID string) error {
+	start := time.Now()
+	err := s.Store.PurgeBoard(boardID)
+	s.observe("PurgeBoard", start, err)
+	return err
+}
+
+func (s *MetricsLayer) QueryCards(boardID string, opts model.QueryCardsOptions) (*model.CardQueryResult, error) {
+	start := time.Now()
+	result, err := s.Store.QueryCards(boardID, opts)
+	s.observe("QueryCards", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) RefreshSession(session *model.Session) error {
+	start := time.Now()
+	err := s.Store.RefreshSession(session)
+	s.observe("RefreshSession", start, err)
+	return err
+}
+
+func (s *MetricsLayer) RemoveDefaultTemplates(boards []*model.Board) error {
+	start := time.Now()
+	err := s.Store.RemoveDefaultTemplates(boards)
+	s.observe("RemoveDefaultTemplates", start, err)
+	return err
+}
+
+func (s *MetricsLayer) ReorderCategories(userID string, teamID string, newCategoryOrder []string) ([]string, error) {
+	start := time.Now()
+	result, err := s.Store.ReorderCategories(userID, teamID, newCategoryOrder)
+	s.observe("ReorderCategories", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) ReorderCategoryBoards(categoryID string, newBoardsOrder []string) ([]string, error) {
+	start := time.Now()
+	result, err := s.Store.ReorderCategoryBoards(categoryID, newBoardsOrder)
+	s.observe("ReorderCategoryBoards", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) ReorderViewCategories(userID string, boardID string, newCategoryOrder []string) ([]string, error) {
+	start := time.Now()
+	result, err := s.Store.ReorderViewCategories(userID, boardID, newCategoryOrder)
+	s.observe("ReorderViewCategories", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) ReorderViewCategoryViews(categoryID string, newViewsOrder []string) ([]string, error) {
+	start := time.Now()
+	result, err := s.Store.ReorderViewCategoryViews(categoryID, newViewsOrder)
+	s.observe("ReorderViewCategoryViews", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) RestoreBoardAndBlocks(board *model.Board, blocks []*model.Block, deletedBlockIDs []string, userID string) error {
+	start := time.Now()
+	err := s.Store.RestoreBoardAndBlocks(board, blocks, deletedBlockIDs, userID)
+	s.observe("RestoreBoardAndBlocks", start, err)
+	return err
+}
+
+func (s *MetricsLayer) RunDataRetention(globalRetentionDate int64, batchSize int64) (*model.DataRetentionReport, error) {
+	start := time.Now()
+	result, err := s.Store.RunDataRetention(globalRetentionDate, batchSize)
+	s.observe("RunDataRetention", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SaveDueDateReminderSettings(settings *model.DueDateReminderSettings) (*model.DueDateReminderSettings, error) {
+	start := time.Now()
+	result, err := s.Store.SaveDueDateReminderSettings(settings)
+	s.observe("SaveDueDateReminderSettings", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
+	start := time.Now()
+	err := s.Store.SaveFileInfo(fileInfo)
+	s.observe("SaveFileInfo", start, err)
+	return err
+}
+
+func (s *MetricsLayer) SaveMember(bm *model.BoardMember) (*model.BoardMember, error) {
+	start := time.Now()
+	result, err := s.Store.SaveMember(bm)
+	s.observe("SaveMember", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SaveRetentionPolicy(policy *model.RetentionPolicy) (*model.RetentionPolicy, error) {
+	start := time.Now()
+	result, err := s.Store.SaveRetentionPolicy(policy)
+	s.observe("SaveRetentionPolicy", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.SearchBoardsForUser(term, searchField, userID, includePublicBoards)
+	s.observe("SearchBoardsForUser", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SearchBoardsForUserInTeam(teamID string, term string, userID string) ([]*model.Board, error) {
+	start := time.Now()
+	result, err := s.Store.SearchBoardsForUserInTeam(teamID, term, userID)
+	s.observe("SearchBoardsForUserInTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SearchCards(opts model.CardSearchOptions) ([]*model.CardSearchResult, error) {
+	start := time.Now()
+	result, err := s.Store.SearchCards(opts)
+	s.observe("SearchCards", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SearchUserChannels(teamID string, userID string, query string) ([]*mmModel.Channel, error) {
+	start := time.Now()
+	result, err := s.Store.SearchUserChannels(teamID, userID, query)
+	s.observe("SearchUserChannels", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SearchUsersByTeam(teamID string, searchQuery string, asGuestID string, excludeBots bool, showEmail bool, showName bool) ([]*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.SearchUsersByTeam(teamID, searchQuery, asGuestID, excludeBots, showEmail, showName)
+	s.observe("SearchUsersByTeam", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) SendMessage(message string, postType string, receipts []string) error {
+	start := time.Now()
+	err := s.Store.SendMessage(message, postType, receipts)
+	s.observe("SendMessage", start, err)
+	return err
+}
+
+func (s *MetricsLayer) SetBoardVisibility(userID string, categoryID string, boardID string, visible bool) error {
+	start := time.Now()
+	err := s.Store.SetBoardVisibility(userID, categoryID, boardID, visible)
+	s.observe("SetBoardVisibility", start, err)
+	return err
+}
+
+func (s *MetricsLayer) SetSystemSetting(key string, value string) error {
+	start := time.Now()
+	err := s.Store.SetSystemSetting(key, value)
+	s.observe("SetSystemSetting", start, err)
+	return err
+}
+
+func (s *MetricsLayer) SetViewVisibility(userID string, categoryID string, viewID string, visible bool) error {
+	start := time.Now()
+	err := s.Store.SetViewVisibility(userID, categoryID, viewID, visible)
+	s.observe("SetViewVisibility", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UndeleteBlock(blockID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.UndeleteBlock(blockID, modifiedBy)
+	s.observe("UndeleteBlock", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UndeleteBoard(boardID string, modifiedBy string) error {
+	start := time.Now()
+	err := s.Store.UndeleteBoard(boardID, modifiedBy)
+	s.observe("UndeleteBoard", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateAutomationRule(rule *model.AutomationRule) (*model.AutomationRule, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateAutomationRule(rule)
+	s.observe("UpdateAutomationRule", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateCardLimitTimestamp(cardLimit)
+	s.observe("UpdateCardLimitTimestamp", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpdateCategory(category model.Category) error {
+	start := time.Now()
+	err := s.Store.UpdateCategory(category)
+	s.observe("UpdateCategory", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateIncomingWebhook(webhook)
+	s.observe("UpdateIncomingWebhook", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpdateRecurringCardRule(rule *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateRecurringCardRule(rule)
+	s.observe("UpdateRecurringCardRule", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpdateRecurringCardRuleLastRun(ruleID string, lastRunAt int64, lastCardID string, lastError string) error {
+	start := time.Now()
+	err := s.Store.UpdateRecurringCardRuleLastRun(ruleID, lastRunAt, lastCardID, lastError)
+	s.observe("UpdateRecurringCardRuleLastRun", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateSession(session *model.Session) error {
+	start := time.Now()
+	err := s.Store.UpdateSession(session)
+	s.observe("UpdateSession", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateSubscribersNotifiedAt(blockID string, notifiedAt int64) error {
+	start := time.Now()
+	err := s.Store.UpdateSubscribersNotifiedAt(blockID, notifiedAt)
+	s.observe("UpdateSubscribersNotifiedAt", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateUser(user *model.User) (*model.User, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateUser(user)
+	s.observe("UpdateUser", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpdateUserPassword(username string, password string) error {
+	start := time.Now()
+	err := s.Store.UpdateUserPassword(username, password)
+	s.observe("UpdateUserPassword", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateUserPasswordByID(userID string, password string) error {
+	start := time.Now()
+	err := s.Store.UpdateUserPasswordByID(userID, password)
+	s.observe("UpdateUserPasswordByID", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateUserUsername(userID string, username string) error {
+	start := time.Now()
+	err := s.Store.UpdateUserUsername(userID, username)
+	s.observe("UpdateUserUsername", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateViewCategory(viewCategory model.ViewCategory) error {
+	start := time.Now()
+	err := s.Store.UpdateViewCategory(viewCategory)
+	s.observe("UpdateViewCategory", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpdateWebhook(webhook *model.Webhook) (*model.Webhook, error) {
+	start := time.Now()
+	result, err := s.Store.UpdateWebhook(webhook)
+	s.observe("UpdateWebhook", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	start := time.Now()
+	err := s.Store.UpdateWebhookDelivery(delivery)
+	s.observe("UpdateWebhookDelivery", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
+	start := time.Now()
+	result, err := s.Store.UpsertNotificationHint(hint, notificationFreq)
+	s.observe("UpsertNotificationHint", start, err)
+	return result, err
+}
+
+func (s *MetricsLayer) UpsertSharing(sharing model.Sharing) error {
+	start := time.Now()
+	err := s.Store.UpsertSharing(sharing)
+	s.observe("UpsertSharing", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpsertTeamSettings(team model.Team) error {
+	start := time.Now()
+	err := s.Store.UpsertTeamSettings(team)
+	s.observe("UpsertTeamSettings", start, err)
+	return err
+}
+
+func (s *MetricsLayer) UpsertTeamSignupToken(team model.Team) error {
+	start := time.Now()
+	err := s.Store.UpsertTeamSignupToken(team)
+	s.observe("UpsertTeamSignupToken", start, err)
+	return err
+}
@@ -50,6 +50,20 @@ func (mr *MockStoreMockRecorder) AddUpdateCategoryBoard(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUpdateCategoryBoard", reflect.TypeOf((*MockStore)(nil).AddUpdateCategoryBoard), arg0, arg1, arg2)
 }
 
+// AddUpdateViewCategoryView mocks base method.
+func (m *MockStore) AddUpdateViewCategoryView(arg0, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUpdateViewCategoryView", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUpdateViewCategoryView indicates an expected call of AddUpdateViewCategoryView.
+func (mr *MockStoreMockRecorder) AddUpdateViewCategoryView(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUpdateViewCategoryView", reflect.TypeOf((*MockStore)(nil).AddUpdateViewCategoryView), arg0, arg1, arg2)
+}
+
 // CanSeeUser mocks base method.
 func (m *MockStore) CanSeeUser(arg0, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +79,36 @@ func (mr *MockStoreMockRecorder) CanSeeUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSeeUser", reflect.TypeOf((*MockStore)(nil).CanSeeUser), arg0, arg1)
 }
 
+// ClaimRecurringCardRuleRun mocks base method.
+func (m *MockStore) ClaimRecurringCardRuleRun(arg0 string, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRecurringCardRuleRun", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRecurringCardRuleRun indicates an expected call of ClaimRecurringCardRuleRun.
+func (mr *MockStoreMockRecorder) ClaimRecurringCardRuleRun(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRecurringCardRuleRun", reflect.TypeOf((*MockStore)(nil).ClaimRecurringCardRuleRun), arg0, arg1, arg2)
+}
+
+// ClaimWebhookDelivery mocks base method.
+func (m *MockStore) ClaimWebhookDelivery(arg0 string, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDelivery", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDelivery indicates an expected call of ClaimWebhookDelivery.
+func (mr *MockStoreMockRecorder) ClaimWebhookDelivery(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDelivery), arg0, arg1, arg2)
+}
+
 // CleanUpSessions mocks base method.
 func (m *MockStore) CleanUpSessions(arg0 int64) error {
 	m.ctrl.T.Helper()
@@ -168,6 +212,20 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0)
 }
 
+// CreateViewCategory mocks base method.
+func (m *MockStore) CreateViewCategory(arg0 model.ViewCategory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateViewCategory", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateViewCategory indicates an expected call of CreateViewCategory.
+func (mr *MockStoreMockRecorder) CreateViewCategory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateViewCategory", reflect.TypeOf((*MockStore)(nil).CreateViewCategory), arg0)
+}
+
 // DBType mocks base method.
 func (m *MockStore) DBType() string {
 	m.ctrl.T.Helper()
@@ -196,6 +254,35 @@ func (mr *MockStoreMockRecorder) DBVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBVersion", reflect.TypeOf((*MockStore)(nil).DBVersion))
 }
 
+// DeleteAutomationExecutionsBefore mocks base method.
+func (m *MockStore) DeleteAutomationExecutionsBefore(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutomationExecutionsBefore", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAutomationExecutionsBefore indicates an expected call of DeleteAutomationExecutionsBefore.
+func (mr *MockStoreMockRecorder) DeleteAutomationExecutionsBefore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutomationExecutionsBefore", reflect.TypeOf((*MockStore)(nil).DeleteAutomationExecutionsBefore), arg0)
+}
+
+// DeleteAutomationRule mocks base method.
+func (m *MockStore) DeleteAutomationRule(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAutomationRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAutomationRule indicates an expected call of DeleteAutomationRule.
+func (mr *MockStoreMockRecorder) DeleteAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAutomationRule", reflect.TypeOf((*MockStore)(nil).DeleteAutomationRule), arg0)
+}
+
 // DeleteBlock mocks base method.
 func (m *MockStore) DeleteBlock(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -266,6 +353,20 @@ func (mr *MockStoreMockRecorder) DeleteBoardsAndBlocks(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBoardsAndBlocks", reflect.TypeOf((*MockStore)(nil).DeleteBoardsAndBlocks), arg0, arg1)
 }
 
+// DeleteCardDependency mocks base method.
+func (m *MockStore) DeleteCardDependency(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCardDependency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCardDependency indicates an expected call of DeleteCardDependency.
+func (mr *MockStoreMockRecorder) DeleteCardDependency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCardDependency", reflect.TypeOf((*MockStore)(nil).DeleteCardDependency), arg0, arg1)
+}
+
 // DeleteCategory mocks base method.
 func (m *MockStore) DeleteCategory(arg0, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -280,6 +381,34 @@ func (mr *MockStoreMockRecorder) DeleteCategory(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockStore)(nil).DeleteCategory), arg0, arg1, arg2)
 }
 
+// DeleteDueDateReminderSettings mocks base method.
+func (m *MockStore) DeleteDueDateReminderSettings(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDueDateReminderSettings", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDueDateReminderSettings indicates an expected call of DeleteDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) DeleteDueDateReminderSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).DeleteDueDateReminderSettings), arg0)
+}
+
+// DeleteIncomingWebhook mocks base method.
+func (m *MockStore) DeleteIncomingWebhook(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIncomingWebhook", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIncomingWebhook indicates an expected call of DeleteIncomingWebhook.
+func (mr *MockStoreMockRecorder) DeleteIncomingWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIncomingWebhook", reflect.TypeOf((*MockStore)(nil).DeleteIncomingWebhook), arg0)
+}
+
 // DeleteMember mocks base method.
 func (m *MockStore) DeleteMember(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -308,6 +437,34 @@ func (mr *MockStoreMockRecorder) DeleteNotificationHint(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationHint", reflect.TypeOf((*MockStore)(nil).DeleteNotificationHint), arg0)
 }
 
+// DeleteRecurringCardRule mocks base method.
+func (m *MockStore) DeleteRecurringCardRule(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecurringCardRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecurringCardRule indicates an expected call of DeleteRecurringCardRule.
+func (mr *MockStoreMockRecorder) DeleteRecurringCardRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecurringCardRule", reflect.TypeOf((*MockStore)(nil).DeleteRecurringCardRule), arg0)
+}
+
+// DeleteRetentionPolicy mocks base method.
+func (m *MockStore) DeleteRetentionPolicy(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRetentionPolicy indicates an expected call of DeleteRetentionPolicy.
+func (mr *MockStoreMockRecorder) DeleteRetentionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicy", reflect.TypeOf((*MockStore)(nil).DeleteRetentionPolicy), arg0, arg1)
+}
+
 // DeleteSession mocks base method.
 func (m *MockStore) DeleteSession(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -336,6 +493,49 @@ func (mr *MockStoreMockRecorder) DeleteSubscription(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockStore)(nil).DeleteSubscription), arg0, arg1)
 }
 
+// DeleteViewCategory mocks base method.
+func (m *MockStore) DeleteViewCategory(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteViewCategory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteViewCategory indicates an expected call of DeleteViewCategory.
+func (mr *MockStoreMockRecorder) DeleteViewCategory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteViewCategory", reflect.TypeOf((*MockStore)(nil).DeleteViewCategory), arg0, arg1, arg2)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), arg0)
+}
+
+// DeleteWebhookDeliveriesBefore mocks base method.
+func (m *MockStore) DeleteWebhookDeliveriesBefore(arg0 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookDeliveriesBefore", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhookDeliveriesBefore indicates an expected call of DeleteWebhookDeliveriesBefore.
+func (mr *MockStoreMockRecorder) DeleteWebhookDeliveriesBefore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookDeliveriesBefore", reflect.TypeOf((*MockStore)(nil).DeleteWebhookDeliveriesBefore), arg0)
+}
+
 // DuplicateBlock mocks base method.
 func (m *MockStore) DuplicateBlock(arg0, arg1, arg2 string, arg3 bool) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -382,6 +582,21 @@ func (mr *MockStoreMockRecorder) GetActiveUserCount(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveUserCount", reflect.TypeOf((*MockStore)(nil).GetActiveUserCount), arg0)
 }
 
+// GetActiveWebhooks mocks base method.
+func (m *MockStore) GetActiveWebhooks(arg0, arg1 string) ([]*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveWebhooks indicates an expected call of GetActiveWebhooks.
+func (mr *MockStoreMockRecorder) GetActiveWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveWebhooks", reflect.TypeOf((*MockStore)(nil).GetActiveWebhooks), arg0, arg1)
+}
+
 // GetAllTeams mocks base method.
 func (m *MockStore) GetAllTeams() ([]*model.Team, error) {
 	m.ctrl.T.Helper()
@@ -397,6 +612,51 @@ func (mr *MockStoreMockRecorder) GetAllTeams() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTeams", reflect.TypeOf((*MockStore)(nil).GetAllTeams))
 }
 
+// GetAutomationExecutions mocks base method.
+func (m *MockStore) GetAutomationExecutions(arg0 model.QueryAutomationExecutionsOptions) ([]*model.AutomationExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomationExecutions", arg0)
+	ret0, _ := ret[0].([]*model.AutomationExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomationExecutions indicates an expected call of GetAutomationExecutions.
+func (mr *MockStoreMockRecorder) GetAutomationExecutions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomationExecutions", reflect.TypeOf((*MockStore)(nil).GetAutomationExecutions), arg0)
+}
+
+// GetAutomationRule mocks base method.
+func (m *MockStore) GetAutomationRule(arg0 string) (*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomationRule", arg0)
+	ret0, _ := ret[0].(*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomationRule indicates an expected call of GetAutomationRule.
+func (mr *MockStoreMockRecorder) GetAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomationRule", reflect.TypeOf((*MockStore)(nil).GetAutomationRule), arg0)
+}
+
+// GetAutomationRulesForBoard mocks base method.
+func (m *MockStore) GetAutomationRulesForBoard(arg0 string) ([]*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAutomationRulesForBoard", arg0)
+	ret0, _ := ret[0].([]*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAutomationRulesForBoard indicates an expected call of GetAutomationRulesForBoard.
+func (mr *MockStoreMockRecorder) GetAutomationRulesForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAutomationRulesForBoard", reflect.TypeOf((*MockStore)(nil).GetAutomationRulesForBoard), arg0)
+}
+
 // GetBlock mocks base method.
 func (m *MockStore) GetBlock(arg0 string) (*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -473,6 +733,22 @@ func (mr *MockStoreMockRecorder) GetBlockHistoryNewestChildren(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHistoryNewestChildren", reflect.TypeOf((*MockStore)(nil).GetBlockHistoryNewestChildren), arg0, arg1)
 }
 
+// GetBlockHistoryNewestDescendants mocks base method.
+func (m *MockStore) GetBlockHistoryNewestDescendants(arg0 string, arg1 model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHistoryNewestDescendants", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBlockHistoryNewestDescendants indicates an expected call of GetBlockHistoryNewestDescendants.
+func (mr *MockStoreMockRecorder) GetBlockHistoryNewestDescendants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHistoryNewestDescendants", reflect.TypeOf((*MockStore)(nil).GetBlockHistoryNewestDescendants), arg0, arg1)
+}
+
 // GetBlocks mocks base method.
 func (m *MockStore) GetBlocks(arg0 model.QueryBlocksOptions) ([]*model.Block, error) {
 	m.ctrl.T.Helper()
@@ -733,6 +1009,21 @@ func (mr *MockStoreMockRecorder) GetBoardsInTeamByIds(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsInTeamByIds", reflect.TypeOf((*MockStore)(nil).GetBoardsInTeamByIds), arg0, arg1)
 }
 
+// GetCardDependencies mocks base method.
+func (m *MockStore) GetCardDependencies(arg0 model.QueryCardDependenciesOptions) ([]*model.CardDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardDependencies", arg0)
+	ret0, _ := ret[0].([]*model.CardDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardDependencies indicates an expected call of GetCardDependencies.
+func (mr *MockStoreMockRecorder) GetCardDependencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardDependencies", reflect.TypeOf((*MockStore)(nil).GetCardDependencies), arg0)
+}
+
 // GetCardLimitTimestamp mocks base method.
 func (m *MockStore) GetCardLimitTimestamp() (int64, error) {
 	m.ctrl.T.Helper()
@@ -778,6 +1069,126 @@ func (mr *MockStoreMockRecorder) GetChannel(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockStore)(nil).GetChannel), arg0, arg1)
 }
 
+// GetChecklistProgress mocks base method.
+func (m *MockStore) GetChecklistProgress(arg0 []string) (map[string]model.ChecklistProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChecklistProgress", arg0)
+	ret0, _ := ret[0].(map[string]model.ChecklistProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChecklistProgress indicates an expected call of GetChecklistProgress.
+func (mr *MockStoreMockRecorder) GetChecklistProgress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChecklistProgress", reflect.TypeOf((*MockStore)(nil).GetChecklistProgress), arg0)
+}
+
+// GetDeletedBlocksForBoard mocks base method.
+func (m *MockStore) GetDeletedBlocksForBoard(arg0 string, arg1 []model.BlockType) ([]*model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBlocksForBoard", arg0, arg1)
+	ret0, _ := ret[0].([]*model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBlocksForBoard indicates an expected call of GetDeletedBlocksForBoard.
+func (mr *MockStoreMockRecorder) GetDeletedBlocksForBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBlocksForBoard", reflect.TypeOf((*MockStore)(nil).GetDeletedBlocksForBoard), arg0, arg1)
+}
+
+// GetDeletedBoardsForTeam mocks base method.
+func (m *MockStore) GetDeletedBoardsForTeam(arg0 string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBoardsForTeam", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBoardsForTeam indicates an expected call of GetDeletedBoardsForTeam.
+func (mr *MockStoreMockRecorder) GetDeletedBoardsForTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBoardsForTeam", reflect.TypeOf((*MockStore)(nil).GetDeletedBoardsForTeam), arg0)
+}
+
+// GetDueDateReminderSettings mocks base method.
+func (m *MockStore) GetDueDateReminderSettings(arg0 string) (*model.DueDateReminderSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDateReminderSettings", arg0)
+	ret0, _ := ret[0].(*model.DueDateReminderSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDateReminderSettings indicates an expected call of GetDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) GetDueDateReminderSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).GetDueDateReminderSettings), arg0)
+}
+
+// GetDueRecurringCardRules mocks base method.
+func (m *MockStore) GetDueRecurringCardRules(arg0 int64, arg1 int) ([]*model.RecurringCardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueRecurringCardRules", arg0, arg1)
+	ret0, _ := ret[0].([]*model.RecurringCardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueRecurringCardRules indicates an expected call of GetDueRecurringCardRules.
+func (mr *MockStoreMockRecorder) GetDueRecurringCardRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueRecurringCardRules", reflect.TypeOf((*MockStore)(nil).GetDueRecurringCardRules), arg0, arg1)
+}
+
+// GetDueWebhookDeliveries mocks base method.
+func (m *MockStore) GetDueWebhookDeliveries(arg0 int64, arg1 int) ([]*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueWebhookDeliveries indicates an expected call of GetDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetDueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetDueWebhookDeliveries), arg0, arg1)
+}
+
+// GetEnabledAutomationRules mocks base method.
+func (m *MockStore) GetEnabledAutomationRules(arg0 string) ([]*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnabledAutomationRules", arg0)
+	ret0, _ := ret[0].([]*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnabledAutomationRules indicates an expected call of GetEnabledAutomationRules.
+func (mr *MockStoreMockRecorder) GetEnabledAutomationRules(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabledAutomationRules", reflect.TypeOf((*MockStore)(nil).GetEnabledAutomationRules), arg0)
+}
+
+// GetEnabledDueDateReminderSettings mocks base method.
+func (m *MockStore) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnabledDueDateReminderSettings")
+	ret0, _ := ret[0].([]*model.DueDateReminderSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnabledDueDateReminderSettings indicates an expected call of GetEnabledDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) GetEnabledDueDateReminderSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabledDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).GetEnabledDueDateReminderSettings))
+}
+
 // GetFileInfo mocks base method.
 func (m *MockStore) GetFileInfo(arg0 string) (*model0.FileInfo, error) {
 	m.ctrl.T.Helper()
@@ -793,6 +1204,36 @@ func (mr *MockStoreMockRecorder) GetFileInfo(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileInfo", reflect.TypeOf((*MockStore)(nil).GetFileInfo), arg0)
 }
 
+// GetIncomingWebhook mocks base method.
+func (m *MockStore) GetIncomingWebhook(arg0 string) (*model.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingWebhook", arg0)
+	ret0, _ := ret[0].(*model.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingWebhook indicates an expected call of GetIncomingWebhook.
+func (mr *MockStoreMockRecorder) GetIncomingWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingWebhook", reflect.TypeOf((*MockStore)(nil).GetIncomingWebhook), arg0)
+}
+
+// GetIncomingWebhooksForBoard mocks base method.
+func (m *MockStore) GetIncomingWebhooksForBoard(arg0 string) ([]*model.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomingWebhooksForBoard", arg0)
+	ret0, _ := ret[0].([]*model.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomingWebhooksForBoard indicates an expected call of GetIncomingWebhooksForBoard.
+func (mr *MockStoreMockRecorder) GetIncomingWebhooksForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomingWebhooksForBoard", reflect.TypeOf((*MockStore)(nil).GetIncomingWebhooksForBoard), arg0)
+}
+
 // GetLicense mocks base method.
 func (m *MockStore) GetLicense() *model0.License {
 	m.ctrl.T.Helper()
@@ -882,22 +1323,67 @@ func (mr *MockStoreMockRecorder) GetNotificationHint(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationHint", reflect.TypeOf((*MockStore)(nil).GetNotificationHint), arg0)
 }
 
-// GetRegisteredUserCount mocks base method.
-func (m *MockStore) GetRegisteredUserCount() (int, error) {
+// GetRecurringCardRule mocks base method.
+func (m *MockStore) GetRecurringCardRule(arg0 string) (*model.RecurringCardRule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRegisteredUserCount")
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "GetRecurringCardRule", arg0)
+	ret0, _ := ret[0].(*model.RecurringCardRule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRegisteredUserCount indicates an expected call of GetRegisteredUserCount.
-func (mr *MockStoreMockRecorder) GetRegisteredUserCount() *gomock.Call {
+// GetRecurringCardRule indicates an expected call of GetRecurringCardRule.
+func (mr *MockStoreMockRecorder) GetRecurringCardRule(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredUserCount", reflect.TypeOf((*MockStore)(nil).GetRegisteredUserCount))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringCardRule", reflect.TypeOf((*MockStore)(nil).GetRecurringCardRule), arg0)
 }
 
-// GetSession mocks base method.
+// GetRecurringCardRulesForBoard mocks base method.
+func (m *MockStore) GetRecurringCardRulesForBoard(arg0 string) ([]*model.RecurringCardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringCardRulesForBoard", arg0)
+	ret0, _ := ret[0].([]*model.RecurringCardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringCardRulesForBoard indicates an expected call of GetRecurringCardRulesForBoard.
+func (mr *MockStoreMockRecorder) GetRecurringCardRulesForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringCardRulesForBoard", reflect.TypeOf((*MockStore)(nil).GetRecurringCardRulesForBoard), arg0)
+}
+
+// GetRegisteredUserCount mocks base method.
+func (m *MockStore) GetRegisteredUserCount() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegisteredUserCount")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegisteredUserCount indicates an expected call of GetRegisteredUserCount.
+func (mr *MockStoreMockRecorder) GetRegisteredUserCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegisteredUserCount", reflect.TypeOf((*MockStore)(nil).GetRegisteredUserCount))
+}
+
+// GetRetentionPolicies mocks base method.
+func (m *MockStore) GetRetentionPolicies(arg0 string) ([]*model.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRetentionPolicies", arg0)
+	ret0, _ := ret[0].([]*model.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRetentionPolicies indicates an expected call of GetRetentionPolicies.
+func (mr *MockStoreMockRecorder) GetRetentionPolicies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRetentionPolicies", reflect.TypeOf((*MockStore)(nil).GetRetentionPolicies), arg0)
+}
+
+// GetSession mocks base method.
 func (m *MockStore) GetSession(arg0 string, arg1 int64) (*model.Session, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
@@ -1212,6 +1698,36 @@ func (mr *MockStoreMockRecorder) GetUserTimezone(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTimezone", reflect.TypeOf((*MockStore)(nil).GetUserTimezone), arg0)
 }
 
+// GetUserViewCategories mocks base method.
+func (m *MockStore) GetUserViewCategories(arg0, arg1 string) ([]model.ViewCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserViewCategories", arg0, arg1)
+	ret0, _ := ret[0].([]model.ViewCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserViewCategories indicates an expected call of GetUserViewCategories.
+func (mr *MockStoreMockRecorder) GetUserViewCategories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserViewCategories", reflect.TypeOf((*MockStore)(nil).GetUserViewCategories), arg0, arg1)
+}
+
+// GetUserViewCategoryViews mocks base method.
+func (m *MockStore) GetUserViewCategoryViews(arg0, arg1 string) ([]model.ViewCategoryViews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserViewCategoryViews", arg0, arg1)
+	ret0, _ := ret[0].([]model.ViewCategoryViews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserViewCategoryViews indicates an expected call of GetUserViewCategoryViews.
+func (mr *MockStoreMockRecorder) GetUserViewCategoryViews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserViewCategoryViews", reflect.TypeOf((*MockStore)(nil).GetUserViewCategoryViews), arg0, arg1)
+}
+
 // GetUsersByTeam mocks base method.
 func (m *MockStore) GetUsersByTeam(arg0, arg1 string, arg2, arg3 bool) ([]*model.User, error) {
 	m.ctrl.T.Helper()
@@ -1242,6 +1758,156 @@ func (mr *MockStoreMockRecorder) GetUsersList(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersList", reflect.TypeOf((*MockStore)(nil).GetUsersList), arg0, arg1, arg2)
 }
 
+// GetViewCategory mocks base method.
+func (m *MockStore) GetViewCategory(arg0 string) (*model.ViewCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewCategory", arg0)
+	ret0, _ := ret[0].(*model.ViewCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewCategory indicates an expected call of GetViewCategory.
+func (mr *MockStoreMockRecorder) GetViewCategory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewCategory", reflect.TypeOf((*MockStore)(nil).GetViewCategory), arg0)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(arg0 string) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), arg0)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockStore) GetWebhookDeliveries(arg0 model.QueryWebhookDeliveriesOptions) ([]*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", arg0)
+	ret0, _ := ret[0].([]*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveries), arg0)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 string) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0)
+}
+
+// GetWebhooksForBoard mocks base method.
+func (m *MockStore) GetWebhooksForBoard(arg0 string) ([]*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksForBoard", arg0)
+	ret0, _ := ret[0].([]*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksForBoard indicates an expected call of GetWebhooksForBoard.
+func (mr *MockStoreMockRecorder) GetWebhooksForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksForBoard", reflect.TypeOf((*MockStore)(nil).GetWebhooksForBoard), arg0)
+}
+
+// GetWebhooksForTeam mocks base method.
+func (m *MockStore) GetWebhooksForTeam(arg0 string) ([]*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksForTeam", arg0)
+	ret0, _ := ret[0].([]*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksForTeam indicates an expected call of GetWebhooksForTeam.
+func (mr *MockStoreMockRecorder) GetWebhooksForTeam(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksForTeam", reflect.TypeOf((*MockStore)(nil).GetWebhooksForTeam), arg0)
+}
+
+// IncrementAutomationRuleRunCount mocks base method.
+func (m *MockStore) IncrementAutomationRuleRunCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAutomationRuleRunCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementAutomationRuleRunCount indicates an expected call of IncrementAutomationRuleRunCount.
+func (mr *MockStoreMockRecorder) IncrementAutomationRuleRunCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAutomationRuleRunCount", reflect.TypeOf((*MockStore)(nil).IncrementAutomationRuleRunCount), arg0)
+}
+
+// InsertAutomationDueDateRun mocks base method.
+func (m *MockStore) InsertAutomationDueDateRun(arg0 *model.AutomationDueDateRun) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAutomationDueDateRun", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAutomationDueDateRun indicates an expected call of InsertAutomationDueDateRun.
+func (mr *MockStoreMockRecorder) InsertAutomationDueDateRun(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAutomationDueDateRun", reflect.TypeOf((*MockStore)(nil).InsertAutomationDueDateRun), arg0)
+}
+
+// InsertAutomationExecution mocks base method.
+func (m *MockStore) InsertAutomationExecution(arg0 *model.AutomationExecution) (*model.AutomationExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAutomationExecution", arg0)
+	ret0, _ := ret[0].(*model.AutomationExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAutomationExecution indicates an expected call of InsertAutomationExecution.
+func (mr *MockStoreMockRecorder) InsertAutomationExecution(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAutomationExecution", reflect.TypeOf((*MockStore)(nil).InsertAutomationExecution), arg0)
+}
+
+// InsertAutomationRule mocks base method.
+func (m *MockStore) InsertAutomationRule(arg0 *model.AutomationRule) (*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAutomationRule", arg0)
+	ret0, _ := ret[0].(*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAutomationRule indicates an expected call of InsertAutomationRule.
+func (mr *MockStoreMockRecorder) InsertAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAutomationRule", reflect.TypeOf((*MockStore)(nil).InsertAutomationRule), arg0)
+}
+
 // InsertBlock mocks base method.
 func (m *MockStore) InsertBlock(arg0 *model.Block, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -1301,6 +1967,96 @@ func (mr *MockStoreMockRecorder) InsertBoardWithAdmin(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBoardWithAdmin", reflect.TypeOf((*MockStore)(nil).InsertBoardWithAdmin), arg0, arg1)
 }
 
+// InsertCardDependency mocks base method.
+func (m *MockStore) InsertCardDependency(arg0 *model.CardDependency) (*model.CardDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCardDependency", arg0)
+	ret0, _ := ret[0].(*model.CardDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCardDependency indicates an expected call of InsertCardDependency.
+func (mr *MockStoreMockRecorder) InsertCardDependency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCardDependency", reflect.TypeOf((*MockStore)(nil).InsertCardDependency), arg0)
+}
+
+// InsertDueDateReminder mocks base method.
+func (m *MockStore) InsertDueDateReminder(arg0 *model.DueDateReminder) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDueDateReminder", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertDueDateReminder indicates an expected call of InsertDueDateReminder.
+func (mr *MockStoreMockRecorder) InsertDueDateReminder(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDueDateReminder", reflect.TypeOf((*MockStore)(nil).InsertDueDateReminder), arg0)
+}
+
+// InsertIncomingWebhook mocks base method.
+func (m *MockStore) InsertIncomingWebhook(arg0 *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertIncomingWebhook", arg0)
+	ret0, _ := ret[0].(*model.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertIncomingWebhook indicates an expected call of InsertIncomingWebhook.
+func (mr *MockStoreMockRecorder) InsertIncomingWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIncomingWebhook", reflect.TypeOf((*MockStore)(nil).InsertIncomingWebhook), arg0)
+}
+
+// InsertRecurringCardRule mocks base method.
+func (m *MockStore) InsertRecurringCardRule(arg0 *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRecurringCardRule", arg0)
+	ret0, _ := ret[0].(*model.RecurringCardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertRecurringCardRule indicates an expected call of InsertRecurringCardRule.
+func (mr *MockStoreMockRecorder) InsertRecurringCardRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecurringCardRule", reflect.TypeOf((*MockStore)(nil).InsertRecurringCardRule), arg0)
+}
+
+// InsertWebhook mocks base method.
+func (m *MockStore) InsertWebhook(arg0 *model.Webhook) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", arg0)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockStoreMockRecorder) InsertWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockStore)(nil).InsertWebhook), arg0)
+}
+
+// InsertWebhookDelivery mocks base method.
+func (m *MockStore) InsertWebhookDelivery(arg0 *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", arg0)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockStoreMockRecorder) InsertWebhookDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockStore)(nil).InsertWebhookDelivery), arg0)
+}
+
 // PatchBlock mocks base method.
 func (m *MockStore) PatchBlock(arg0 string, arg1 *model.BlockPatch, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -1388,6 +2144,49 @@ func (mr *MockStoreMockRecorder) PostMessage(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockStore)(nil).PostMessage), arg0, arg1, arg2)
 }
 
+// PurgeBlock mocks base method.
+func (m *MockStore) PurgeBlock(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeBlock indicates an expected call of PurgeBlock.
+func (mr *MockStoreMockRecorder) PurgeBlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBlock", reflect.TypeOf((*MockStore)(nil).PurgeBlock), arg0)
+}
+
+// PurgeBoard mocks base method.
+func (m *MockStore) PurgeBoard(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBoard", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeBoard indicates an expected call of PurgeBoard.
+func (mr *MockStoreMockRecorder) PurgeBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBoard", reflect.TypeOf((*MockStore)(nil).PurgeBoard), arg0)
+}
+
+// QueryCards mocks base method.
+func (m *MockStore) QueryCards(arg0 string, arg1 model.QueryCardsOptions) (*model.CardQueryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryCards", arg0, arg1)
+	ret0, _ := ret[0].(*model.CardQueryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryCards indicates an expected call of QueryCards.
+func (mr *MockStoreMockRecorder) QueryCards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryCards", reflect.TypeOf((*MockStore)(nil).QueryCards), arg0, arg1)
+}
+
 // RefreshSession mocks base method.
 func (m *MockStore) RefreshSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1446,11 +2245,55 @@ func (mr *MockStoreMockRecorder) ReorderCategoryBoards(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCategoryBoards", reflect.TypeOf((*MockStore)(nil).ReorderCategoryBoards), arg0, arg1)
 }
 
+// ReorderViewCategories mocks base method.
+func (m *MockStore) ReorderViewCategories(arg0, arg1 string, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderViewCategories", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderViewCategories indicates an expected call of ReorderViewCategories.
+func (mr *MockStoreMockRecorder) ReorderViewCategories(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderViewCategories", reflect.TypeOf((*MockStore)(nil).ReorderViewCategories), arg0, arg1, arg2)
+}
+
+// ReorderViewCategoryViews mocks base method.
+func (m *MockStore) ReorderViewCategoryViews(arg0 string, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderViewCategoryViews", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderViewCategoryViews indicates an expected call of ReorderViewCategoryViews.
+func (mr *MockStoreMockRecorder) ReorderViewCategoryViews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderViewCategoryViews", reflect.TypeOf((*MockStore)(nil).ReorderViewCategoryViews), arg0, arg1)
+}
+
+// RestoreBoardAndBlocks mocks base method.
+func (m *MockStore) RestoreBoardAndBlocks(arg0 *model.Board, arg1 []*model.Block, arg2 []string, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBoardAndBlocks", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreBoardAndBlocks indicates an expected call of RestoreBoardAndBlocks.
+func (mr *MockStoreMockRecorder) RestoreBoardAndBlocks(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBoardAndBlocks", reflect.TypeOf((*MockStore)(nil).RestoreBoardAndBlocks), arg0, arg1, arg2, arg3)
+}
+
 // RunDataRetention mocks base method.
-func (m *MockStore) RunDataRetention(arg0, arg1 int64) (int64, error) {
+func (m *MockStore) RunDataRetention(arg0, arg1 int64) (*model.DataRetentionReport, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RunDataRetention", arg0, arg1)
-	ret0, _ := ret[0].(int64)
+	ret0, _ := ret[0].(*model.DataRetentionReport)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1461,6 +2304,21 @@ func (mr *MockStoreMockRecorder) RunDataRetention(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunDataRetention", reflect.TypeOf((*MockStore)(nil).RunDataRetention), arg0, arg1)
 }
 
+// SaveDueDateReminderSettings mocks base method.
+func (m *MockStore) SaveDueDateReminderSettings(arg0 *model.DueDateReminderSettings) (*model.DueDateReminderSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDueDateReminderSettings", arg0)
+	ret0, _ := ret[0].(*model.DueDateReminderSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDueDateReminderSettings indicates an expected call of SaveDueDateReminderSettings.
+func (mr *MockStoreMockRecorder) SaveDueDateReminderSettings(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDueDateReminderSettings", reflect.TypeOf((*MockStore)(nil).SaveDueDateReminderSettings), arg0)
+}
+
 // SaveFileInfo mocks base method.
 func (m *MockStore) SaveFileInfo(arg0 *model0.FileInfo) error {
 	m.ctrl.T.Helper()
@@ -1490,6 +2348,21 @@ func (mr *MockStoreMockRecorder) SaveMember(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMember", reflect.TypeOf((*MockStore)(nil).SaveMember), arg0)
 }
 
+// SaveRetentionPolicy mocks base method.
+func (m *MockStore) SaveRetentionPolicy(arg0 *model.RetentionPolicy) (*model.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRetentionPolicy", arg0)
+	ret0, _ := ret[0].(*model.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveRetentionPolicy indicates an expected call of SaveRetentionPolicy.
+func (mr *MockStoreMockRecorder) SaveRetentionPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRetentionPolicy", reflect.TypeOf((*MockStore)(nil).SaveRetentionPolicy), arg0)
+}
+
 // SearchBoardsForUser mocks base method.
 func (m *MockStore) SearchBoardsForUser(arg0 string, arg1 model.BoardSearchField, arg2 string, arg3 bool) ([]*model.Board, error) {
 	m.ctrl.T.Helper()
@@ -1520,6 +2393,21 @@ func (mr *MockStoreMockRecorder) SearchBoardsForUserInTeam(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBoardsForUserInTeam", reflect.TypeOf((*MockStore)(nil).SearchBoardsForUserInTeam), arg0, arg1, arg2)
 }
 
+// SearchCards mocks base method.
+func (m *MockStore) SearchCards(arg0 model.CardSearchOptions) ([]*model.CardSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCards", arg0)
+	ret0, _ := ret[0].([]*model.CardSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCards indicates an expected call of SearchCards.
+func (mr *MockStoreMockRecorder) SearchCards(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCards", reflect.TypeOf((*MockStore)(nil).SearchCards), arg0)
+}
+
 // SearchUserChannels mocks base method.
 func (m *MockStore) SearchUserChannels(arg0, arg1, arg2 string) ([]*model0.Channel, error) {
 	m.ctrl.T.Helper()
@@ -1592,6 +2480,20 @@ func (mr *MockStoreMockRecorder) SetSystemSetting(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSystemSetting", reflect.TypeOf((*MockStore)(nil).SetSystemSetting), arg0, arg1)
 }
 
+// SetViewVisibility mocks base method.
+func (m *MockStore) SetViewVisibility(arg0, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetViewVisibility", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetViewVisibility indicates an expected call of SetViewVisibility.
+func (mr *MockStoreMockRecorder) SetViewVisibility(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetViewVisibility", reflect.TypeOf((*MockStore)(nil).SetViewVisibility), arg0, arg1, arg2, arg3)
+}
+
 // Shutdown mocks base method.
 func (m *MockStore) Shutdown() error {
 	m.ctrl.T.Helper()
@@ -1634,6 +2536,21 @@ func (mr *MockStoreMockRecorder) UndeleteBoard(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteBoard", reflect.TypeOf((*MockStore)(nil).UndeleteBoard), arg0, arg1)
 }
 
+// UpdateAutomationRule mocks base method.
+func (m *MockStore) UpdateAutomationRule(arg0 *model.AutomationRule) (*model.AutomationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAutomationRule", arg0)
+	ret0, _ := ret[0].(*model.AutomationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAutomationRule indicates an expected call of UpdateAutomationRule.
+func (mr *MockStoreMockRecorder) UpdateAutomationRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAutomationRule", reflect.TypeOf((*MockStore)(nil).UpdateAutomationRule), arg0)
+}
+
 // UpdateCardLimitTimestamp mocks base method.
 func (m *MockStore) UpdateCardLimitTimestamp(arg0 int) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1663,6 +2580,50 @@ func (mr *MockStoreMockRecorder) UpdateCategory(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockStore)(nil).UpdateCategory), arg0)
 }
 
+// UpdateIncomingWebhook mocks base method.
+func (m *MockStore) UpdateIncomingWebhook(arg0 *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIncomingWebhook", arg0)
+	ret0, _ := ret[0].(*model.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateIncomingWebhook indicates an expected call of UpdateIncomingWebhook.
+func (mr *MockStoreMockRecorder) UpdateIncomingWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncomingWebhook", reflect.TypeOf((*MockStore)(nil).UpdateIncomingWebhook), arg0)
+}
+
+// UpdateRecurringCardRule mocks base method.
+func (m *MockStore) UpdateRecurringCardRule(arg0 *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecurringCardRule", arg0)
+	ret0, _ := ret[0].(*model.RecurringCardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRecurringCardRule indicates an expected call of UpdateRecurringCardRule.
+func (mr *MockStoreMockRecorder) UpdateRecurringCardRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecurringCardRule", reflect.TypeOf((*MockStore)(nil).UpdateRecurringCardRule), arg0)
+}
+
+// UpdateRecurringCardRuleLastRun mocks base method.
+func (m *MockStore) UpdateRecurringCardRuleLastRun(arg0 string, arg1 int64, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecurringCardRuleLastRun", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecurringCardRuleLastRun indicates an expected call of UpdateRecurringCardRuleLastRun.
+func (mr *MockStoreMockRecorder) UpdateRecurringCardRuleLastRun(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecurringCardRuleLastRun", reflect.TypeOf((*MockStore)(nil).UpdateRecurringCardRuleLastRun), arg0, arg1, arg2, arg3)
+}
+
 // UpdateSession mocks base method.
 func (m *MockStore) UpdateSession(arg0 *model.Session) error {
 	m.ctrl.T.Helper()
@@ -1734,6 +2695,63 @@ func (mr *MockStoreMockRecorder) UpdateUserPasswordByID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordByID", reflect.TypeOf((*MockStore)(nil).UpdateUserPasswordByID), arg0, arg1)
 }
 
+// UpdateUserUsername mocks base method.
+func (m *MockStore) UpdateUserUsername(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserUsername", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserUsername indicates an expected call of UpdateUserUsername.
+func (mr *MockStoreMockRecorder) UpdateUserUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserUsername", reflect.TypeOf((*MockStore)(nil).UpdateUserUsername), arg0, arg1)
+}
+
+// UpdateViewCategory mocks base method.
+func (m *MockStore) UpdateViewCategory(arg0 model.ViewCategory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateViewCategory", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateViewCategory indicates an expected call of UpdateViewCategory.
+func (mr *MockStoreMockRecorder) UpdateViewCategory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateViewCategory", reflect.TypeOf((*MockStore)(nil).UpdateViewCategory), arg0)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockStore) UpdateWebhook(arg0 *model.Webhook) (*model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", arg0)
+	ret0, _ := ret[0].(*model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockStoreMockRecorder) UpdateWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockStore)(nil).UpdateWebhook), arg0)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockStore) UpdateWebhookDelivery(arg0 *model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockStoreMockRecorder) UpdateWebhookDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDelivery), arg0)
+}
+
 // UpsertNotificationHint mocks base method.
 func (m *MockStore) UpsertNotificationHint(arg0 *model.NotificationHint, arg1 time.Duration) (*model.NotificationHint, error) {
 	m.ctrl.T.Helper()
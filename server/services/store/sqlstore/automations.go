@@ -0,0 +1,435 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func automationRuleFields() []string {
+	return []string{
+		"id",
+		"board_id",
+		"name",
+		"trigger_type",
+		"trigger_params",
+		"conditions",
+		"actions",
+		"disabled",
+		"run_count",
+		"created_by",
+		"modified_by",
+		"create_at",
+		"update_at",
+	}
+}
+
+func automationExecutionFields() []string {
+	return []string{
+		"id",
+		"rule_id",
+		"board_id",
+		"card_id",
+		"trigger_type",
+		"status",
+		"COALESCE(error_message, '')",
+		"depth",
+		"create_at",
+	}
+}
+
+func (s *SQLStore) automationRulesFromRows(rows *sql.Rows) ([]*model.AutomationRule, error) {
+	rules := []*model.AutomationRule{}
+	for rows.Next() {
+		var rule model.AutomationRule
+		var triggerType string
+		var triggerJSON, conditionsJSON, actionsJSON sql.NullString
+		err := rows.Scan(
+			&rule.ID,
+			&rule.BoardID,
+			&rule.Name,
+			&triggerType,
+			&triggerJSON,
+			&conditionsJSON,
+			&actionsJSON,
+			&rule.Disabled,
+			&rule.RunCount,
+			&rule.CreatedBy,
+			&rule.ModifiedBy,
+			&rule.CreateAt,
+			&rule.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("automationRulesFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+
+		if triggerJSON.String != "" {
+			if err = json.Unmarshal([]byte(triggerJSON.String), &rule.Trigger); err != nil {
+				s.logger.Error("automationRulesFromRows trigger error", mlog.Err(err))
+				return nil, err
+			}
+		}
+		rule.Trigger.Type = triggerType
+		if conditionsJSON.String != "" && conditionsJSON.String != "null" {
+			if err = json.Unmarshal([]byte(conditionsJSON.String), &rule.Conditions); err != nil {
+				s.logger.Error("automationRulesFromRows conditions error", mlog.Err(err))
+				return nil, err
+			}
+		}
+		if actionsJSON.String != "" {
+			if err = json.Unmarshal([]byte(actionsJSON.String), &rule.Actions); err != nil {
+				s.logger.Error("automationRulesFromRows actions error", mlog.Err(err))
+				return nil, err
+			}
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStore) getAutomationRules(db sq.BaseRunner, condition interface{}) ([]*model.AutomationRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(automationRuleFields()...).
+		From(s.tablePrefix+"automation_rules").
+		Where(condition).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getAutomationRules ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.automationRulesFromRows(rows)
+}
+
+func (s *SQLStore) getAutomationRule(db sq.BaseRunner, ruleID string) (*model.AutomationRule, error) {
+	rules, err := s.getAutomationRules(db, sq.Eq{"id": ruleID})
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, model.NewErrNotFound("automation rule ID=" + ruleID)
+	}
+	return rules[0], nil
+}
+
+func (s *SQLStore) getAutomationRulesForBoard(db sq.BaseRunner, boardID string) ([]*model.AutomationRule, error) {
+	return s.getAutomationRules(db, sq.Eq{"board_id": boardID})
+}
+
+// getEnabledAutomationRules returns the enabled rules of all the boards
+// with a trigger type.
+func (s *SQLStore) getEnabledAutomationRules(db sq.BaseRunner, triggerType string) ([]*model.AutomationRule, error) {
+	return s.getAutomationRules(db, sq.Eq{"trigger_type": triggerType, "disabled": false})
+}
+
+// automationRuleJSON returns the trigger parameters, conditions and
+// actions of a rule as stored.
+func automationRuleJSON(rule *model.AutomationRule) (triggerJSON, conditionsJSON, actionsJSON []byte, err error) {
+	if triggerJSON, err = json.Marshal(rule.Trigger); err != nil {
+		return nil, nil, nil, err
+	}
+	if conditionsJSON, err = json.Marshal(rule.Conditions); err != nil {
+		return nil, nil, nil, err
+	}
+	if actionsJSON, err = json.Marshal(rule.Actions); err != nil {
+		return nil, nil, nil, err
+	}
+	return triggerJSON, conditionsJSON, actionsJSON, nil
+}
+
+func (s *SQLStore) insertAutomationRule(db sq.BaseRunner, rule *model.AutomationRule) (*model.AutomationRule, error) {
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	inserted := *rule
+	if inserted.ID == "" {
+		inserted.ID = utils.NewID(utils.IDTypeNone)
+	}
+	inserted.RunCount = 0
+	inserted.CreateAt = utils.GetMillis()
+	inserted.UpdateAt = inserted.CreateAt
+
+	triggerJSON, conditionsJSON, actionsJSON, err := automationRuleJSON(&inserted)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"automation_rules").
+		Columns(automationRuleFields()...).
+		Values(
+			inserted.ID,
+			inserted.BoardID,
+			inserted.Name,
+			inserted.Trigger.Type,
+			triggerJSON,
+			conditionsJSON,
+			actionsJSON,
+			inserted.Disabled,
+			inserted.RunCount,
+			inserted.CreatedBy,
+			inserted.ModifiedBy,
+			inserted.CreateAt,
+			inserted.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertAutomationRule ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+// updateAutomationRule saves the name, trigger, conditions, actions and
+// disabled state of a rule. The run count isn't changed.
+func (s *SQLStore) updateAutomationRule(db sq.BaseRunner, rule *model.AutomationRule) (*model.AutomationRule, error) {
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	updated := *rule
+	updated.UpdateAt = utils.GetMillis()
+
+	triggerJSON, conditionsJSON, actionsJSON, err := automationRuleJSON(&updated)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"automation_rules").
+		Set("name", updated.Name).
+		Set("trigger_type", updated.Trigger.Type).
+		Set("trigger_params", triggerJSON).
+		Set("conditions", conditionsJSON).
+		Set("actions", actionsJSON).
+		Set("disabled", updated.Disabled).
+		Set("modified_by", updated.ModifiedBy).
+		Set("update_at", updated.UpdateAt).
+		Where(sq.Eq{"id": updated.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`updateAutomationRule ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrNotFound("automation rule ID=" + updated.ID)
+	}
+	return s.getAutomationRule(db, updated.ID)
+}
+
+// incrementAutomationRuleRunCount counts a run of a rule and returns the
+// number of runs before it.
+func (s *SQLStore) incrementAutomationRuleRunCount(db sq.BaseRunner, ruleID string) (int64, error) {
+	// the count is compared and swapped, as the SQLite store doesn't run
+	// the method in a transaction and rules can run concurrently
+	for {
+		var runCount int64
+		err := s.getQueryBuilder(db).
+			Select("run_count").
+			From(s.tablePrefix + "automation_rules").
+			Where(sq.Eq{"id": ruleID}).
+			QueryRow().
+			Scan(&runCount)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, model.NewErrNotFound("automation rule ID=" + ruleID)
+		}
+		if err != nil {
+			s.logger.Error(`incrementAutomationRuleRunCount ERROR`, mlog.Err(err))
+			return 0, err
+		}
+
+		result, err := s.getQueryBuilder(db).
+			Update(s.tablePrefix+"automation_rules").
+			Set("run_count", runCount+1).
+			Where(sq.Eq{"id": ruleID, "run_count": runCount}).
+			Exec()
+		if err != nil {
+			s.logger.Error(`incrementAutomationRuleRunCount ERROR`, mlog.Err(err))
+			return 0, err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if count == 1 {
+			return runCount, nil
+		}
+	}
+}
+
+func (s *SQLStore) deleteAutomationRule(db sq.BaseRunner, ruleID string) error {
+	for _, table := range []string{"automation_executions", "automation_due_date_runs"} {
+		query := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + table).
+			Where(sq.Eq{"rule_id": ruleID})
+
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error(`deleteAutomationRule ERROR`, mlog.String("table", table), mlog.Err(err))
+			return err
+		}
+	}
+
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "automation_rules").
+		Where(sq.Eq{"id": ruleID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("automation rule ID=" + ruleID)
+	}
+	return nil
+}
+
+func (s *SQLStore) insertAutomationExecution(db sq.BaseRunner, execution *model.AutomationExecution) (*model.AutomationExecution, error) {
+	inserted := *execution
+	if inserted.ID == "" {
+		inserted.ID = utils.NewID(utils.IDTypeNone)
+	}
+	inserted.CreateAt = utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"automation_executions").
+		Columns(
+			"id",
+			"rule_id",
+			"board_id",
+			"card_id",
+			"trigger_type",
+			"status",
+			"error_message",
+			"depth",
+			"create_at",
+		).
+		Values(
+			inserted.ID,
+			inserted.RuleID,
+			inserted.BoardID,
+			inserted.CardID,
+			inserted.TriggerType,
+			inserted.Status,
+			inserted.Error,
+			inserted.Depth,
+			inserted.CreateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertAutomationExecution ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+func (s *SQLStore) getAutomationExecutions(db sq.BaseRunner, opts model.QueryAutomationExecutionsOptions) ([]*model.AutomationExecution, error) {
+	query := s.getQueryBuilder(db).
+		Select(automationExecutionFields()...).
+		From(s.tablePrefix+"automation_executions").
+		Where(sq.Eq{"rule_id": opts.RuleID}).
+		OrderBy("create_at DESC", "id DESC")
+
+	if opts.Page != 0 {
+		query = query.Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		query = query.Limit(uint64(opts.PerPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getAutomationExecutions ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	executions := []*model.AutomationExecution{}
+	for rows.Next() {
+		var execution model.AutomationExecution
+		err := rows.Scan(
+			&execution.ID,
+			&execution.RuleID,
+			&execution.BoardID,
+			&execution.CardID,
+			&execution.TriggerType,
+			&execution.Status,
+			&execution.Error,
+			&execution.Depth,
+			&execution.CreateAt,
+		)
+		if err != nil {
+			s.logger.Error("getAutomationExecutions scan error", mlog.Err(err))
+			return nil, err
+		}
+		executions = append(executions, &execution)
+	}
+	return executions, nil
+}
+
+func (s *SQLStore) deleteAutomationExecutionsBefore(db sq.BaseRunner, createAt int64) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "automation_executions").
+		Where(sq.Lt{"create_at": createAt})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`deleteAutomationExecutionsBefore ERROR`, mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// insertAutomationDueDateRun records the run of a due date rule on a
+// card, and returns false if the rule already ran for that due date.
+func (s *SQLStore) insertAutomationDueDateRun(db sq.BaseRunner, run *model.AutomationDueDateRun) (bool, error) {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"automation_due_date_runs").
+		Columns("rule_id", "board_id", "card_id", "due_at", "run_at").
+		Values(
+			run.RuleID,
+			run.BoardID,
+			run.CardID,
+			run.DueAt,
+			run.RunAt,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE run_at = run_at")
+	} else {
+		query = query.Suffix("ON CONFLICT (rule_id, card_id, due_at) DO NOTHING")
+	}
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`insertAutomationDueDateRun ERROR`, mlog.Err(err))
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
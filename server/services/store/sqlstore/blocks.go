@@ -311,20 +311,64 @@ func (s *SQLStore) insertBlock(db sq.BaseRunner, block *model.Block, userID stri
 		return err
 	}
 
-	return nil
+	return s.indexBlocksForSearch(db, []*model.Block{block})
 }
 
 func (s *SQLStore) patchBlock(db sq.BaseRunner, blockID string, blockPatch *model.BlockPatch, userID string) error {
-	existingBlock, err := s.getBlock(db, blockID)
+	var existingBlock *model.Block
+	var err error
+	if blockPatch.ExpectedUpdateAt != nil {
+		existingBlock, err = s.getBlockForUpdate(db, blockID)
+	} else {
+		existingBlock, err = s.getBlock(db, blockID)
+	}
 	if err != nil {
 		return err
 	}
 
+	if !blockPatch.MatchesVersion(existingBlock) {
+		return model.NewErrConflict("block ID="+blockID, existingBlock)
+	}
+
 	block := blockPatch.Patch(existingBlock)
 	return s.insertBlock(db, block, userID)
 }
 
+// checkBlockPatchesVersions locks the blocks of the patches that have a
+// precondition and returns the current version of the ones that don't
+// match it.
+func (s *SQLStore) checkBlockPatchesVersions(db sq.BaseRunner, blockIDs []string, blockPatches []*model.BlockPatch) ([]*model.Block, error) {
+	conflicts := []*model.Block{}
+	for i, blockID := range blockIDs {
+		if blockPatches[i].ExpectedUpdateAt == nil {
+			continue
+		}
+
+		block, err := s.getBlockForUpdate(db, blockID)
+		if err != nil {
+			return nil, err
+		}
+		if !blockPatches[i].MatchesVersion(block) {
+			conflicts = append(conflicts, block)
+		}
+	}
+	return conflicts, nil
+}
+
 func (s *SQLStore) patchBlocks(db sq.BaseRunner, blockPatches *model.BlockPatchBatch, userID string) error {
+	patches := make([]*model.BlockPatch, len(blockPatches.BlockPatches))
+	for i := range blockPatches.BlockPatches {
+		patches[i] = &blockPatches.BlockPatches[i]
+	}
+
+	conflicts, err := s.checkBlockPatchesVersions(db, blockPatches.BlockIDs, patches)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return model.NewErrConflict("blocks", conflicts)
+	}
+
 	for i, blockID := range blockPatches.BlockIDs {
 		err := s.patchBlock(db, blockID, &blockPatches.BlockPatches[i], userID)
 		if err != nil {
@@ -443,6 +487,10 @@ func (s *SQLStore) deleteBlockAndChildren(db sq.BaseRunner, blockID string, modi
 		return err
 	}
 
+	if err := s.removeBlocksFromSearch(db, sq.Eq{"block_id": blockID}); err != nil {
+		return err
+	}
+
 	if keepChildren {
 		return nil
 	}
@@ -467,6 +515,16 @@ func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy st
 		return nil // undeleting not deleted block is not considered an error (for now)
 	}
 
+	if err := s.reinsertBlock(db, block, modifiedBy); err != nil {
+		return err
+	}
+
+	return s.undeleteBlockChildren(db, block.BoardID, block.ID, modifiedBy)
+}
+
+// reinsertBlock inserts back a deleted block with the content of the
+// given revision, keeping its original creation metadata.
+func (s *SQLStore) reinsertBlock(db sq.BaseRunner, block *model.Block, modifiedBy string) error {
 	fieldsJSON, err := json.Marshal(block.Fields)
 	if err != nil {
 		return err
@@ -519,7 +577,10 @@ func (s *SQLStore) undeleteBlock(db sq.BaseRunner, blockID string, modifiedBy st
 		return err
 	}
 
-	return s.undeleteBlockChildren(db, block.BoardID, block.ID, modifiedBy)
+	block.ModifiedBy = modifiedBy
+	block.UpdateAt = now
+	block.DeleteAt = 0
+	return s.indexBlocksForSearch(db, []*model.Block{block})
 }
 
 func (s *SQLStore) getBlockCountsByType(db sq.BaseRunner) (map[string]int64, error) {
@@ -579,6 +640,22 @@ func (s *SQLStore) getBlock(db sq.BaseRunner, blockID string) (*model.Block, err
 		From(s.tablePrefix + "blocks").
 		Where(sq.Eq{"id": blockID})
 
+	return s.getBlockByQuery(query, blockID)
+}
+
+// getBlockForUpdate returns a block and locks it until the end of the
+// transaction, so its version can be checked before patching it.
+func (s *SQLStore) getBlockForUpdate(db sq.BaseRunner, blockID string) (*model.Block, error) {
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"id": blockID}).
+		Suffix(s.forUpdateClause())
+
+	return s.getBlockByQuery(query, blockID)
+}
+
+func (s *SQLStore) getBlockByQuery(query sq.SelectBuilder, blockID string) (*model.Block, error) {
 	rows, err := query.Query()
 	if err != nil {
 		s.logger.Error(`GetBlock ERROR`, mlog.Err(err))
@@ -669,6 +746,27 @@ func (s *SQLStore) getBlockHistoryDescendants(db sq.BaseRunner, boardID string,
 // getBlockHistoryNewestChildren returns the newest (latest) version child blocks for the
 // specified parent from the blocks_history table. This includes any deleted children.
 func (s *SQLStore) getBlockHistoryNewestChildren(db sq.BaseRunner, parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	blocks, hasMore, err := s.getBlockHistoryNewest(db, sq.Eq{"bh2.parent_id": parentID}, opts)
+	if err != nil {
+		return nil, false, fmt.Errorf("getBlockHistoryNewestChildren: %w", err)
+	}
+	return blocks, hasMore, nil
+}
+
+// getBlockHistoryNewestDescendants returns the newest (latest) version of
+// each block of the specified board from the blocks_history table. This
+// includes any deleted blocks.
+func (s *SQLStore) getBlockHistoryNewestDescendants(db sq.BaseRunner, boardID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	blocks, hasMore, err := s.getBlockHistoryNewest(db, sq.Eq{"bh2.board_id": boardID}, opts)
+	if err != nil {
+		return nil, false, fmt.Errorf("getBlockHistoryNewestDescendants: %w", err)
+	}
+	return blocks, hasMore, nil
+}
+
+// getBlockHistoryNewest returns the newest (latest) version of each block
+// of the blocks_history table, aliased bh2, that matches the condition.
+func (s *SQLStore) getBlockHistoryNewest(db sq.BaseRunner, cond sq.Sqlizer, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
 	// as we're joining 2 queries, we need to avoid numbered
 	// placeholders until the join is done, so we use the default
 	// question mark placeholder here
@@ -677,7 +775,7 @@ func (s *SQLStore) getBlockHistoryNewestChildren(db sq.BaseRunner, parentID stri
 	sub := builder.
 		Select("bh2.id", "MAX(bh2.insert_at) AS max_insert_at").
 		From(s.tablePrefix + "blocks_history AS bh2").
-		Where(sq.Eq{"bh2.parent_id": parentID}).
+		Where(cond).
 		GroupBy("bh2.id")
 
 	if opts.AfterUpdateAt != 0 {
@@ -690,7 +788,7 @@ func (s *SQLStore) getBlockHistoryNewestChildren(db sq.BaseRunner, parentID stri
 
 	subQuery, subArgs, err := sub.ToSql()
 	if err != nil {
-		return nil, false, fmt.Errorf("getBlockHistoryNewestChildren unable to generate subquery: %w", err)
+		return nil, false, fmt.Errorf("unable to generate subquery: %w", err)
 	}
 
 	query := s.getQueryBuilder(db).
@@ -709,7 +807,7 @@ func (s *SQLStore) getBlockHistoryNewestChildren(db sq.BaseRunner, parentID stri
 
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return nil, false, fmt.Errorf("getBlockHistoryNewestChildren unable to generate sql: %w", err)
+		return nil, false, fmt.Errorf("unable to generate sql: %w", err)
 	}
 
 	// if we're using postgres or sqlite, we need to replace the
@@ -719,13 +817,13 @@ func (s *SQLStore) getBlockHistoryNewestChildren(db sq.BaseRunner, parentID stri
 		var rErr error
 		sql, rErr = sq.Dollar.ReplacePlaceholders(sql)
 		if rErr != nil {
-			return nil, false, fmt.Errorf("getBlockHistoryNewestChildren unable to replace sql placeholders: %w", rErr)
+			return nil, false, fmt.Errorf("unable to replace sql placeholders: %w", rErr)
 		}
 	}
 
 	rows, err := db.Query(sql, args...)
 	if err != nil {
-		s.logger.Error(`getBlockHistoryNewestChildren ERROR`, mlog.Err(err))
+		s.logger.Error(`getBlockHistoryNewest ERROR`, mlog.Err(err))
 		return nil, false, err
 	}
 	defer s.CloseRows(rows)
@@ -993,6 +1091,18 @@ func (s *SQLStore) deleteBlockChildren(db sq.BaseRunner, boardID string, parentI
 		}
 	}
 
+	var searchCondition sq.Sqlizer = sq.Eq{"board_id": boardID}
+	if parentID != "" {
+		blockIDs := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			blockIDs = append(blockIDs, block.ID)
+		}
+		searchCondition = sq.Eq{"block_id": blockIDs}
+	}
+	if err := s.removeBlocksFromSearch(db, searchCondition); err != nil {
+		return err
+	}
+
 	deleteQuery := s.getQueryBuilder(db).
 		Delete(s.tablePrefix + "blocks").
 		Where(sq.Eq{"board_id": boardID})
@@ -1096,5 +1206,15 @@ func (s *SQLStore) undeleteBlockChildren(db sq.BaseRunner, boardID string, paren
 	rowsAffected, _ = result.RowsAffected()
 	s.logger.Debug("undeleteBlockChildren - insertHistoryQuery", mlog.Int("rows_affected", rowsAffected))
 
-	return nil
+	var restoredBlocks []*model.Block
+	if parentID != "" {
+		restoredBlocks, err = s.getBlocksWithParent(db, boardID, parentID)
+	} else {
+		restoredBlocks, err = s.getBlocksForBoard(db, boardID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.indexBlocksForSearch(db, restoredBlocks)
 }
@@ -234,6 +234,34 @@ func (s *SQLStore) getBoard(db sq.BaseRunner, boardID string) (*model.Board, err
 	return s.getBoardByCondition(db, sq.Eq{"id": boardID})
 }
 
+// getBoardForUpdate returns a board and locks it until the end of the
+// transaction, so its version can be checked before patching it.
+func (s *SQLStore) getBoardForUpdate(db sq.BaseRunner, boardID string) (*model.Board, error) {
+	query := s.getQueryBuilder(db).
+		Select(boardFields("")...).
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"id": boardID}).
+		Suffix(s.forUpdateClause())
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardForUpdate ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	boards, err := s.boardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(boards) == 0 {
+		return nil, model.NewErrNotFound("board ID=" + boardID)
+	}
+
+	return boards[0], nil
+}
+
 func (s *SQLStore) getBoardsForUserAndTeam(db sq.BaseRunner, userID, teamID string, includePublicBoards bool) ([]*model.Board, error) {
 	query := s.getQueryBuilder(db).
 		Select(boardFields("b.")...).
@@ -402,13 +430,34 @@ func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID stri
 }
 
 func (s *SQLStore) patchBoard(db sq.BaseRunner, boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error) {
-	existingBoard, err := s.getBoard(db, boardID)
+	var existingBoard *model.Board
+	var err error
+	if boardPatch.ExpectedUpdateAt != nil {
+		existingBoard, err = s.getBoardForUpdate(db, boardID)
+	} else {
+		existingBoard, err = s.getBoard(db, boardID)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if !boardPatch.MatchesVersion(existingBoard) {
+		return nil, model.NewErrConflict("board ID="+boardID, existingBoard)
+	}
+
 	board := boardPatch.Patch(existingBoard)
-	return s.insertBoard(db, board, userID)
+	board, err = s.insertBoard(db, board, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the indexed card property values depend on the board schema
+	if len(boardPatch.UpdatedCardProperties) > 0 || len(boardPatch.DeletedCardProperties) > 0 {
+		if err := s.reindexBoardCardsForSearch(db, boardID); err != nil {
+			return nil, err
+		}
+	}
+	return board, nil
 }
 
 func (s *SQLStore) deleteBoard(db sq.BaseRunner, boardID, userID string) error {
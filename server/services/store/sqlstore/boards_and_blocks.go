@@ -73,6 +73,33 @@ func (s *SQLStore) createBoardsAndBlocks(db sq.BaseRunner, bab *model.BoardsAndB
 }
 
 func (s *SQLStore) patchBoardsAndBlocks(db sq.BaseRunner, pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
+	// all the versions are checked before patching anything, so the
+	// conflict error lists every board and block that has been modified
+	conflicts := &model.BoardsAndBlocks{Boards: []*model.Board{}}
+	for i, boardID := range pbab.BoardIDs {
+		if pbab.BoardPatches[i].ExpectedUpdateAt == nil {
+			continue
+		}
+
+		board, err := s.getBoardForUpdate(db, boardID)
+		if err != nil {
+			return nil, err
+		}
+		if !pbab.BoardPatches[i].MatchesVersion(board) {
+			conflicts.Boards = append(conflicts.Boards, board)
+		}
+	}
+
+	blockConflicts, err := s.checkBlockPatchesVersions(db, pbab.BlockIDs, pbab.BlockPatches)
+	if err != nil {
+		return nil, err
+	}
+	conflicts.Blocks = blockConflicts
+
+	if len(conflicts.Boards) > 0 || len(conflicts.Blocks) > 0 {
+		return nil, model.NewErrConflict("boards and blocks", conflicts)
+	}
+
 	bab := &model.BoardsAndBlocks{}
 	for i, boardID := range pbab.BoardIDs {
 		board, err := s.patchBoard(db, boardID, pbab.BoardPatches[i], userID)
@@ -131,6 +158,50 @@ func (s *SQLStore) deleteBoardsAndBlocks(db sq.BaseRunner, dbab *model.DeleteBoa
 	return nil
 }
 
+// restoreBoardAndBlocks writes a new revision of the board and of the
+// given blocks with their content, bringing back the ones that were
+// deleted, and deletes the blocks with the given IDs. The children of the
+// deleted blocks are kept, as the caller is expected to list every block
+// to delete.
+func (s *SQLStore) restoreBoardAndBlocks(db sq.BaseRunner, board *model.Board, blocks []*model.Block, deletedBlockIDs []string, userID string) error {
+	if board != nil {
+		if _, err := s.insertBoard(db, board, userID); err != nil {
+			return err
+		}
+	}
+
+	for _, block := range blocks {
+		_, err := s.getBlock(db, block.ID)
+		if model.IsErrNotFound(err) {
+			if err := s.reinsertBlock(db, block, userID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		block.DeleteAt = 0
+		if err := s.insertBlock(db, block, userID); err != nil {
+			return err
+		}
+	}
+
+	for _, blockID := range deletedBlockIDs {
+		if err := s.deleteBlockAndChildren(db, blockID, userID, true); err != nil {
+			return err
+		}
+	}
+
+	if board != nil {
+		// the card properties may have changed, so the values of the
+		// properties indexed for search must be recomputed
+		return s.reindexBoardCardsForSearch(db, board.ID)
+	}
+	return nil
+}
+
 func (s *SQLStore) duplicateBoard(db sq.BaseRunner, boardID string, userID string, toTeam string, asTemplate bool) (*model.BoardsAndBlocks, []*model.BoardMember, error) {
 	bab := &model.BoardsAndBlocks{
 		Boards: []*model.Board{},
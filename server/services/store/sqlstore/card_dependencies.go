@@ -0,0 +1,124 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func cardDependencyFields() []string {
+	return []string{
+		"blocker_id",
+		"blocker_board_id",
+		"blocked_id",
+		"blocked_board_id",
+		"created_by",
+		"create_at",
+	}
+}
+
+func (s *SQLStore) cardDependenciesFromRows(rows *sql.Rows) ([]*model.CardDependency, error) {
+	dependencies := []*model.CardDependency{}
+	for rows.Next() {
+		var dependency model.CardDependency
+		err := rows.Scan(
+			&dependency.BlockerID,
+			&dependency.BlockerBoardID,
+			&dependency.BlockedID,
+			&dependency.BlockedBoardID,
+			&dependency.CreatedBy,
+			&dependency.CreateAt,
+		)
+		if err != nil {
+			s.logger.Error("cardDependenciesFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		dependencies = append(dependencies, &dependency)
+	}
+	return dependencies, nil
+}
+
+// getCardDependencies returns the dependencies whose blocker or blocked
+// card is one of the cards of the options, ordered by creation.
+func (s *SQLStore) getCardDependencies(db sq.BaseRunner, opts model.QueryCardDependenciesOptions) ([]*model.CardDependency, error) {
+	if len(opts.BlockerIDs) == 0 && len(opts.BlockedIDs) == 0 {
+		return []*model.CardDependency{}, nil
+	}
+
+	conditions := sq.Or{}
+	if len(opts.BlockerIDs) > 0 {
+		conditions = append(conditions, sq.Eq{"blocker_id": opts.BlockerIDs})
+	}
+	if len(opts.BlockedIDs) > 0 {
+		conditions = append(conditions, sq.Eq{"blocked_id": opts.BlockedIDs})
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(cardDependencyFields()...).
+		From(s.tablePrefix+"card_dependencies").
+		Where(conditions).
+		OrderBy("create_at", "blocker_id", "blocked_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getCardDependencies ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardDependenciesFromRows(rows)
+}
+
+func (s *SQLStore) insertCardDependency(db sq.BaseRunner, dependency *model.CardDependency) (*model.CardDependency, error) {
+	if err := dependency.IsValid(); err != nil {
+		return nil, err
+	}
+
+	inserted := *dependency
+	inserted.CreateAt = utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"card_dependencies").
+		Columns(cardDependencyFields()...).
+		Values(
+			inserted.BlockerID,
+			inserted.BlockerBoardID,
+			inserted.BlockedID,
+			inserted.BlockedBoardID,
+			inserted.CreatedBy,
+			inserted.CreateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertCardDependency ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+func (s *SQLStore) deleteCardDependency(db sq.BaseRunner, blockerID, blockedID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_dependencies").
+		Where(sq.Eq{"blocker_id": blockerID}).
+		Where(sq.Eq{"blocked_id": blockedID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("card dependency blockerID=" + blockerID + " blockedID=" + blockedID)
+	}
+	return nil
+}
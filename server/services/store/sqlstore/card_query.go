@@ -0,0 +1,395 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// likeEscape is the escape character of the LIKE patterns of the card
+// filters. It isn't a backslash, which MySQL would need escaped in the
+// query itself.
+const likeEscape = "!"
+
+// cardQuery translates the filters and sort options of a card query to
+// SQL on the blocks table aliased as `b`. Only the conditions on the
+// title and on the properties stored as plain strings are translated: the
+// others depend on values parsed or computed by the model, and are
+// evaluated by it once the cards are loaded.
+//
+// The comparisons are case insensitive as the model's, but follow the
+// case folding of the database, which may be limited to ASCII.
+type cardQuery struct {
+	store  *SQLStore
+	schema model.PropSchema
+}
+
+// stringPropTypes are the types of the properties whose values are stored
+// as plain strings.
+var stringPropTypes = map[string]bool{
+	"text":   true,
+	"select": true,
+	"person": true,
+	"url":    true,
+	"email":  true,
+	"phone":  true,
+}
+
+// filter returns the SQL condition of the part of the filter group that
+// can be evaluated by the database, and the part left to the model, which
+// is nil if the whole group was translated. Only the clauses of a root
+// `and` group can be split between both.
+func (q *cardQuery) filter(fg *model.FilterGroup) (sq.Sqlizer, *model.FilterGroup) {
+	if fg == nil {
+		return nil, nil
+	}
+	if cond, ok := q.filterGroup(fg); ok {
+		return cond, nil
+	}
+	if fg.Operation != model.FilterGroupOperationAnd {
+		return nil, fg
+	}
+
+	conds := sq.And{}
+	rest := &model.FilterGroup{Operation: model.FilterGroupOperationAnd}
+	for _, item := range fg.Filters {
+		if cond, ok := q.filterItem(item); ok {
+			conds = append(conds, cond)
+			continue
+		}
+		rest.Filters = append(rest.Filters, item)
+	}
+	if len(conds) == 0 {
+		return nil, fg
+	}
+	return conds, rest
+}
+
+func (q *cardQuery) filterGroup(fg *model.FilterGroup) (sq.Sqlizer, bool) {
+	if len(fg.Filters) == 0 {
+		return sq.Expr("1 = 1"), true
+	}
+
+	conds := make([]sq.Sqlizer, 0, len(fg.Filters))
+	for _, item := range fg.Filters {
+		cond, ok := q.filterItem(item)
+		if !ok {
+			return nil, false
+		}
+		conds = append(conds, cond)
+	}
+	if fg.Operation == model.FilterGroupOperationOr {
+		return sq.Or(conds), true
+	}
+	return sq.And(conds), true
+}
+
+func (q *cardQuery) filterItem(item model.FilterGroupItem) (sq.Sqlizer, bool) {
+	if item.Group != nil {
+		return q.filterGroup(item.Group)
+	}
+	if item.Clause != nil {
+		return q.filterClause(item.Clause)
+	}
+	return sq.Expr("1 = 1"), true
+}
+
+// filterClause translates a clause following FilterClause.IsMet.
+func (q *cardQuery) filterClause(fc *model.FilterClause) (sq.Sqlizer, bool) {
+	value, args, ok := q.stringValue(fc.PropertyID)
+	if !ok {
+		return nil, false
+	}
+	if len(fc.Values) == 0 {
+		switch fc.Condition {
+		case model.FilterConditionIsEmpty, model.FilterConditionIsNotSet,
+			model.FilterConditionIsNotEmpty, model.FilterConditionIsSet:
+		default:
+			return sq.Expr("1 = 1"), true
+		}
+	}
+
+	isEmpty := fmt.Sprintf("(%[1]s IS NULL OR %[1]s = '')", value)
+	repeat := func(n int) []interface{} {
+		repeated := make([]interface{}, 0, n*len(args))
+		for i := 0; i < n; i++ {
+			repeated = append(repeated, args...)
+		}
+		return repeated
+	}
+	like := func(not bool, prefix, suffix string) sq.Sqlizer {
+		op := "LIKE"
+		if not {
+			op = "NOT LIKE"
+		}
+		pattern := prefix + escapeLike(strings.ToLower(fc.Values[0])) + suffix
+		return sq.Expr(
+			fmt.Sprintf("LOWER(COALESCE(%s, '')) %s ? ESCAPE '%s'", value, op, likeEscape),
+			append(repeat(1), pattern)...,
+		)
+	}
+
+	switch fc.Condition {
+	case model.FilterConditionIncludes, model.FilterConditionNotIncludes:
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(fc.Values)), ", ")
+		values := make([]interface{}, 0, len(fc.Values))
+		for _, v := range fc.Values {
+			values = append(values, v)
+		}
+		if fc.Condition == model.FilterConditionIncludes {
+			return sq.Expr(fmt.Sprintf("%s IN (%s)", value, placeholders), append(repeat(1), values...)...), true
+		}
+		return sq.Expr(
+			fmt.Sprintf("(%[1]s IS NULL OR %[1]s NOT IN (%[2]s))", value, placeholders),
+			append(repeat(2), values...)...,
+		), true
+	case model.FilterConditionIsEmpty, model.FilterConditionIsNotSet:
+		return sq.Expr(isEmpty, repeat(2)...), true
+	case model.FilterConditionIsNotEmpty, model.FilterConditionIsSet:
+		return sq.Expr("NOT "+isEmpty, repeat(2)...), true
+	case model.FilterConditionIs:
+		return sq.Expr(fmt.Sprintf("LOWER(COALESCE(%s, '')) = ?", value), append(repeat(1), strings.ToLower(fc.Values[0]))...), true
+	case model.FilterConditionContains, model.FilterConditionNotContains:
+		return like(fc.Condition == model.FilterConditionNotContains, "%", "%"), true
+	case model.FilterConditionStartsWith, model.FilterConditionNotStartsWith:
+		return like(fc.Condition == model.FilterConditionNotStartsWith, "", "%"), true
+	case model.FilterConditionEndsWith, model.FilterConditionNotEndsWith:
+		return like(fc.Condition == model.FilterConditionNotEndsWith, "%", ""), true
+	}
+	// the date conditions are only met by date properties
+	return nil, false
+}
+
+// stringValue returns the SQL expression of the title or of the value of
+// a property stored as a plain string, NULL if the card has no value,
+// with its arguments. It returns false for the other properties.
+func (q *cardQuery) stringValue(propertyID string) (string, []interface{}, bool) {
+	if propertyID == model.CardQueryTitlePropertyID || propertyID == model.CardQuerySortTitlePropertyID {
+		return "b.title", nil, true
+	}
+	def, ok := q.schema[propertyID]
+	if !ok || !stringPropTypes[def.Type] {
+		return "", nil, false
+	}
+	value, args := q.store.cardPropertyValue("b", propertyID)
+	return value, args, true
+}
+
+// orderBy returns the ORDER BY clauses of the sort options, following
+// model.SortCards, or false if one of them can't be evaluated by the
+// database. Without sort options, the cards follow the manual card order.
+// The cards are finally sorted by ID so the pages are stable.
+func (q *cardQuery) orderBy(sortOptions []model.SortOption, cardOrder []string) ([]sq.Sqlizer, bool) {
+	clauses := []sq.Sqlizer{}
+	if len(sortOptions) == 0 {
+		if len(cardOrder) > 0 {
+			position, arg := q.store.cardOrderPosition("b", cardOrder)
+			clauses = append(clauses,
+				sq.Expr(fmt.Sprintf("CASE WHEN %s = 0 THEN 1 ELSE 0 END", position), arg),
+				sq.Expr(position, arg),
+			)
+		}
+		clauses = append(clauses, q.titleOrder(false)...)
+		return append(clauses, sq.Expr("b.id")), true
+	}
+
+	for _, option := range sortOptions {
+		direction := "ASC"
+		if option.Reversed {
+			direction = "DESC"
+		}
+
+		if option.PropertyID == model.CardQuerySortTitlePropertyID || option.PropertyID == model.CardQueryTitlePropertyID {
+			clauses = append(clauses, q.titleOrder(option.Reversed)...)
+			continue
+		}
+		def, ok := q.schema[option.PropertyID]
+		if !ok {
+			// unknown properties don't change the order
+			continue
+		}
+
+		switch {
+		case def.Type == "createdTime":
+			clauses = append(clauses, sq.Expr("b.create_at "+direction))
+		case def.Type == "updatedTime":
+			clauses = append(clauses, sq.Expr("b.update_at "+direction))
+		case stringPropTypes[def.Type]:
+			value, args := q.store.cardPropertyValue("b", option.PropertyID)
+			sortValue := fmt.Sprintf("COALESCE(%s, '')", value)
+			if def.Type == "select" {
+				// select properties are sorted by the value of their option
+				sortValue, args = q.optionValue(value, args, def)
+			}
+			// the cards without value always go at the bottom
+			clauses = append(clauses,
+				sq.Expr(fmt.Sprintf("CASE WHEN %s = '' THEN 1 ELSE 0 END", sortValue), args...),
+				sq.Expr(q.store.bytewiseOrder(fmt.Sprintf("LOWER(%s)", sortValue))+" "+direction, args...),
+			)
+		default:
+			return nil, false
+		}
+	}
+	clauses = append(clauses, q.titleOrder(false)...)
+	return append(clauses, sq.Expr("b.id")), true
+}
+
+// titleOrder returns the clauses that sort the cards by title, the
+// untitled ones at the bottom, and then by creation time.
+func (q *cardQuery) titleOrder(reversed bool) []sq.Sqlizer {
+	direction := "ASC"
+	if reversed {
+		direction = "DESC"
+	}
+	return []sq.Sqlizer{
+		sq.Expr("CASE WHEN b.title = '' THEN 1 ELSE 0 END " + direction),
+		sq.Expr(q.store.bytewiseOrder("LOWER(b.title)") + " " + direction),
+		sq.Expr("b.create_at " + direction),
+	}
+}
+
+// optionValue returns the expression of the option value of a select
+// property value, empty for unknown options.
+func (q *cardQuery) optionValue(value string, args []interface{}, def model.PropDef) (string, []interface{}) {
+	if len(def.Options) == 0 {
+		return "''", nil
+	}
+	var sb strings.Builder
+	sb.WriteString("CASE ")
+	sb.WriteString(value)
+	caseArgs := append([]interface{}{}, args...)
+	for id, option := range def.Options {
+		sb.WriteString(" WHEN ? THEN ?")
+		caseArgs = append(caseArgs, id, option.Value)
+	}
+	sb.WriteString(" ELSE '' END")
+	return sb.String(), caseArgs
+}
+
+// groupValue returns the expression grouping the cards by the group by
+// property, or false if the cards must be grouped by the model. Only
+// select properties are grouped by the database.
+func (q *cardQuery) groupValue(groupByID string) (string, []interface{}, bool) {
+	def, ok := q.schema[groupByID]
+	if !ok {
+		return "", nil, true
+	}
+	if def.Type != "select" {
+		return "", nil, false
+	}
+	value, args := q.store.cardPropertyValue("b", groupByID)
+	return fmt.Sprintf("COALESCE(%s, '')", value), args, true
+}
+
+// cardPropertyValue returns the SQL expression of the value of a card
+// property stored as a string in the fields of a block, NULL if the card
+// has no value, with its arguments.
+func (s *SQLStore) cardPropertyValue(alias, propertyID string) (string, []interface{}) {
+	switch s.dbType {
+	case model.PostgresDBType:
+		return fmt.Sprintf("(%s.fields->'properties'->>CAST(? AS TEXT))", alias), []interface{}{propertyID}
+	case model.MysqlDBType:
+		path := cardPropertyPath(propertyID)
+		// JSON_UNQUOTE turns JSON nulls into the "null" string
+		return fmt.Sprintf(
+			"(CASE WHEN JSON_TYPE(JSON_EXTRACT(%[1]s.fields, ?)) = 'NULL' THEN NULL ELSE JSON_UNQUOTE(JSON_EXTRACT(%[1]s.fields, ?)) END)",
+			alias,
+		), []interface{}{path, path}
+	default:
+		return fmt.Sprintf("JSON_EXTRACT(%s.fields, ?)", alias), []interface{}{cardPropertyPath(propertyID)}
+	}
+}
+
+// cardPropertyPath returns the JSON path of a card property in the fields
+// of a block.
+func cardPropertyPath(propertyID string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(propertyID)
+	return `$.properties."` + escaped + `"`
+}
+
+// cardOrderPosition returns the SQL expression of the position of a card
+// in the manual card order, 0 if it isn't in it, with its argument. The
+// position is the offset of the card ID in the comma separated order,
+// which sorts the cards as their index.
+func (s *SQLStore) cardOrderPosition(alias string, cardOrder []string) (string, interface{}) {
+	order := "," + strings.Join(cardOrder, ",") + ","
+	switch s.dbType {
+	case model.PostgresDBType:
+		return fmt.Sprintf("position((',' || %s.id || ',') in CAST(? AS TEXT))", alias), order
+	case model.MysqlDBType:
+		return fmt.Sprintf("LOCATE(CONCAT(',', %s.id, ','), ?)", alias), order
+	default:
+		return fmt.Sprintf("instr(?, ',' || %s.id || ',')", alias), order
+	}
+}
+
+// bytewiseOrder makes a text expression sort by its bytes, as the strings
+// are compared by the model, instead of following the collation of the
+// database where it can be set without knowing the charset.
+func (s *SQLStore) bytewiseOrder(expr string) string {
+	if s.dbType == model.PostgresDBType {
+		return expr + ` COLLATE "C"`
+	}
+	return expr
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern with likeEscape.
+func escapeLike(s string) string {
+	return strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_").Replace(s)
+}
+
+// checklistProgressJoin returns the join of the cards aliased as `b` with
+// the number of checkboxes of their checklists, `cl.total`, and of the
+// checked ones, `cl.done`, with its arguments. Both are NULL for the
+// cards without checkboxes.
+func (s *SQLStore) checklistProgressJoin(boardID string) (string, []interface{}) {
+	var checked string
+	switch s.dbType {
+	case model.PostgresDBType:
+		checked = "COALESCE((fields->'value')::text = 'true', false)"
+	case model.MysqlDBType:
+		checked = "JSON_EXTRACT(fields, '$.value') = CAST('true' AS JSON)"
+	default:
+		checked = "COALESCE(JSON_TYPE(fields, '$.value') = 'true', 0)"
+	}
+
+	join := fmt.Sprintf(
+		"(SELECT parent_id, COUNT(*) AS total, SUM(CASE WHEN %s THEN 1 ELSE 0 END) AS done"+
+			" FROM %sblocks WHERE board_id = ? AND type = ? AND delete_at = 0 GROUP BY parent_id) AS cl ON cl.parent_id = b.id",
+		checked, s.tablePrefix,
+	)
+	return join, []interface{}{boardID, model.TypeCheckbox}
+}
+
+// checklistFilter returns the condition of a checklist filter on the
+// cards joined with checklistProgressJoin, nil if it keeps all the cards.
+func checklistFilter(filter string) sq.Sqlizer {
+	switch filter {
+	case model.ChecklistFilterNone:
+		return sq.Expr("cl.total IS NULL")
+	case model.ChecklistFilterIncomplete:
+		return sq.Expr("cl.done < cl.total")
+	case model.ChecklistFilterComplete:
+		return sq.Expr("cl.done = cl.total")
+	}
+	return nil
+}
+
+// checklistOrder returns the clauses that sort the cards joined with
+// checklistProgressJoin by the checked fraction of their checklists, the
+// cards without checkboxes at the bottom in both directions.
+func checklistOrder(order string) []sq.Sqlizer {
+	direction := "ASC"
+	switch order {
+	case "":
+		return nil
+	case model.ChecklistSortDescending:
+		direction = "DESC"
+	}
+	return []sq.Sqlizer{
+		sq.Expr("CASE WHEN cl.total IS NULL THEN 1 ELSE 0 END"),
+		sq.Expr("cl.done * 1.0 / cl.total " + direction),
+	}
+}
@@ -0,0 +1,274 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// queryCards returns the non template cards of a board that match the
+// filter of the options, sorted and paginated, along with the number of
+// matching cards per option of the group by property.
+func (s *SQLStore) queryCards(db sq.BaseRunner, boardID string, opts model.QueryCardsOptions) (*model.CardQueryResult, error) {
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.ViewID != "" {
+		view, vErr := s.getBlock(db, opts.ViewID)
+		if vErr != nil {
+			return nil, vErr
+		}
+		if view.BoardID != boardID {
+			return nil, model.NewErrNotFound(fmt.Sprintf("view ID=%s in board ID=%s", opts.ViewID, boardID))
+		}
+		if vErr = opts.ApplyView(view); vErr != nil {
+			return nil, vErr
+		}
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &cardQuery{store: s, schema: schema}
+	filter, rest := q.filter(opts.Filter)
+	orderBy, sortable := q.orderBy(opts.SortOptions, opts.CardOrder)
+	groupValue, groupArgs, groupable := q.groupValue(opts.GroupByID)
+
+	query := s.getQueryBuilder(db).
+		Select().
+		From(s.tablePrefix + "blocks AS b").
+		Where(sq.Eq{"b.board_id": boardID}).
+		Where(sq.Eq{"b.type": model.TypeCard}).
+		Where(s.notTemplateCardCondition("b"))
+	if filter != nil {
+		query = query.Where(filter)
+	}
+	if !opts.Checklist.IsEmpty() {
+		join, args := s.checklistProgressJoin(boardID)
+		query = query.LeftJoin(join, args...)
+		if cond := checklistFilter(opts.Checklist.Filter); cond != nil {
+			query = query.Where(cond)
+		}
+		orderBy = append(checklistOrder(opts.Checklist.Sort), orderBy...)
+	}
+
+	total, err := s.countQueriedCards(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != nil || !sortable || !groupable {
+		if total > queryCardsMaxInMemory {
+			return nil, model.NewErrBadRequest(fmt.Sprintf(
+				"too many cards (%d) to apply the filters and sort options of the query, the limit is %d: filter or sort on other properties",
+				total, queryCardsMaxInMemory,
+			))
+		}
+		return s.queryCardsInMemory(query, boardID, opts, rest, schema)
+	}
+
+	result := &model.CardQueryResult{Total: total}
+
+	if opts.GroupByID != "" && groupValue != "" {
+		counts, cErr := s.countQueriedCardsByGroup(query, groupValue, groupArgs)
+		if cErr != nil {
+			return nil, cErr
+		}
+		result.Groups = model.CardGroupCountsFromCounts(counts, opts.GroupByID, schema)
+	}
+
+	pageQuery := query.Columns(s.blockFields("b")...)
+	for _, clause := range orderBy {
+		sql, args, sErr := clause.ToSql()
+		if sErr != nil {
+			return nil, sErr
+		}
+		pageQuery = pageQuery.OrderByClause(sql, args...)
+	}
+	if opts.PerPage > 0 {
+		pageQuery = pageQuery.
+			Limit(uint64(opts.PerPage)).
+			Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	blocks, err := s.queryCardBlocks(pageQuery)
+	if err != nil {
+		return nil, err
+	}
+	result.Cards = s.cardsFromBlocks(blocks, boardID, schema, opts.Location)
+	return result, nil
+}
+
+// queryCardsMaxInMemory is the maximum number of cards loaded to be
+// filtered, sorted or grouped by the model, when the query uses
+// properties that can't be evaluated by the database.
+var queryCardsMaxInMemory = 5000
+
+// queryCardsInMemory loads the cards matching the query, and applies the
+// rest of the filter, the sort options, the grouping and the pagination
+// in memory.
+func (s *SQLStore) queryCardsInMemory(query sq.SelectBuilder, boardID string, opts model.QueryCardsOptions, rest *model.FilterGroup, schema model.PropSchema) (*model.CardQueryResult, error) {
+	blocks, err := s.queryCardBlocks(query.Columns(s.blockFields("b")...))
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*model.Card, 0, len(blocks))
+	for _, card := range s.cardsFromBlocks(blocks, boardID, schema, opts.Location) {
+		if rest != nil && !rest.IsMet(card, schema) {
+			continue
+		}
+		cards = append(cards, card)
+	}
+
+	model.SortCards(cards, opts.SortOptions, opts.CardOrder, schema)
+	if opts.Checklist.Sort != "" {
+		// the checklist filter is applied by the query, and the stable
+		// sort by checklist keeps the sort options as the next criteria
+		if err = s.setCardsChecklist(cards); err != nil {
+			return nil, err
+		}
+		cards = opts.Checklist.Apply(cards)
+	}
+
+	result := &model.CardQueryResult{
+		Total: len(cards),
+	}
+
+	if opts.GroupByID != "" {
+		result.Groups = model.GroupCardCounts(cards, opts.GroupByID, schema)
+	}
+
+	if opts.PerPage > 0 {
+		start := opts.Page * opts.PerPage
+		end := start + opts.PerPage
+		if start > len(cards) {
+			start = len(cards)
+		}
+		if end > len(cards) {
+			end = len(cards)
+		}
+		cards = cards[start:end]
+	}
+	result.Cards = cards
+
+	return result, nil
+}
+
+func (s *SQLStore) countQueriedCards(query sq.SelectBuilder) (int, error) {
+	var count int
+	if err := query.Columns("COUNT(*)").QueryRow().Scan(&count); err != nil {
+		s.logger.Error(`countQueriedCards ERROR`, mlog.Err(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// countQueriedCardsByGroup returns the number of cards matching the query
+// per value of the group expression.
+func (s *SQLStore) countQueriedCardsByGroup(query sq.SelectBuilder, groupValue string, groupArgs []interface{}) (map[string]int, error) {
+	rows, err := query.
+		Column(groupValue, groupArgs...).
+		Column("COUNT(*)").
+		GroupBy("1").
+		Query()
+	if err != nil {
+		s.logger.Error(`countQueriedCardsByGroup ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		counts[value] += count
+	}
+	return counts, rows.Err()
+}
+
+func (s *SQLStore) queryCardBlocks(query sq.SelectBuilder) ([]*model.Block, error) {
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`queryCardBlocks ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.blocksFromRows(rows)
+}
+
+// cardsFromBlocks converts the blocks to cards with their formulas
+// evaluated in a time zone. Invalid cards are skipped.
+func (s *SQLStore) cardsFromBlocks(blocks []*model.Block, boardID string, schema model.PropSchema, loc *time.Location) []*model.Card {
+	fctx := model.NewFormulaContext(loc)
+	cards := make([]*model.Card, 0, len(blocks))
+	for _, block := range blocks {
+		card, err := model.Block2Card(block)
+		if err != nil {
+			s.logger.Warn("queryCards skipping invalid card",
+				mlog.String("board_id", boardID),
+				mlog.String("card_id", block.ID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		model.EvaluateCardFormulas(card, schema, fctx)
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// setCardsChecklist sets the checklist progress of cards.
+func (s *SQLStore) setCardsChecklist(cards []*model.Card) error {
+	cardIDs := make([]string, 0, len(cards))
+	for _, card := range cards {
+		cardIDs = append(cardIDs, card.ID)
+	}
+	progress, err := s.getChecklistProgress(s.db, cardIDs)
+	if err != nil {
+		return err
+	}
+	for _, card := range cards {
+		card.Checklist = progress[card.ID]
+	}
+	return nil
+}
+
+// getChecklistProgress returns the progress of the checklists of cards, by
+// card ID. Cards without checkbox blocks are left out.
+func (s *SQLStore) getChecklistProgress(db sq.BaseRunner, cardIDs []string) (map[string]model.ChecklistProgress, error) {
+	if len(cardIDs) == 0 {
+		return map[string]model.ChecklistProgress{}, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("")...).
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"parent_id": cardIDs}).
+		Where(sq.Eq{"type": model.TypeCheckbox})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getChecklistProgress ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	blocks, err := s.blocksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return model.ComputeChecklistProgress(blocks), nil
+}
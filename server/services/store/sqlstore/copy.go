@@ -0,0 +1,394 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// DefaultCopyBatchSize is the default number of rows read at once
+	// from each table when copying a database.
+	DefaultCopyBatchSize = 1000
+
+	// maxCopyInsertParams is the maximum number of parameters of each
+	// insert of a copy, which fits the lowest limit of the databases.
+	maxCopyInsertParams = 999
+)
+
+type copyTable struct {
+	name        string
+	primaryKeys []string
+}
+
+// copyTables are the tables copied by CopyDatabase, with the columns
+// used to read their rows in a stable order. Parent tables are listed
+// before the tables that reference them.
+var copyTables = []copyTable{
+	{name: "teams", primaryKeys: []string{"id"}},
+	{name: "users", primaryKeys: []string{"id"}},
+	{name: "preferences", primaryKeys: []string{"userid", "category", "name"}},
+	{name: "sessions", primaryKeys: []string{"id"}},
+	{name: "system_settings", primaryKeys: []string{"id"}},
+	{name: "boards", primaryKeys: []string{"id"}},
+	{name: "boards_history", primaryKeys: []string{"id", "insert_at"}},
+	{name: "board_members", primaryKeys: []string{"board_id", "user_id"}},
+	{name: "board_members_history", primaryKeys: []string{"board_id", "user_id", "insert_at"}},
+	{name: "blocks", primaryKeys: []string{"id"}},
+	{name: "blocks_history", primaryKeys: []string{"id", "insert_at"}},
+	{name: "categories", primaryKeys: []string{"id"}},
+	{name: "category_boards", primaryKeys: []string{"id"}},
+	{name: "view_categories", primaryKeys: []string{"id"}},
+	{name: "view_category_views", primaryKeys: []string{"view_id"}},
+	{name: "sharing", primaryKeys: []string{"id"}},
+	{name: "subscriptions", primaryKeys: []string{"block_id", "subscriber_id"}},
+	{name: "notification_hints", primaryKeys: []string{"block_id"}},
+	{name: "file_info", primaryKeys: []string{"id"}},
+	{name: "retention_policies", primaryKeys: []string{"team_id", "board_id"}},
+	{name: "card_dependencies", primaryKeys: []string{"blocker_id", "blocked_id"}},
+	{name: "recurring_card_rules", primaryKeys: []string{"id"}},
+	{name: "due_date_reminder_settings", primaryKeys: []string{"board_id"}},
+	{name: "due_date_reminders", primaryKeys: []string{"card_id", "user_id", "due_at", "days_before"}},
+	{name: "webhooks", primaryKeys: []string{"id"}},
+	{name: "webhook_deliveries", primaryKeys: []string{"id"}},
+	{name: "incoming_webhooks", primaryKeys: []string{"id"}},
+	{name: "automation_rules", primaryKeys: []string{"id"}},
+	{name: "automation_executions", primaryKeys: []string{"id"}},
+	{name: "automation_due_date_runs", primaryKeys: []string{"rule_id", "card_id", "due_at"}},
+}
+
+// TableCopyResult is the result of the copy of a table.
+type TableCopyResult struct {
+	Table           string `json:"table"`
+	CopiedRows      int64  `json:"copiedRows"`
+	SourceRows      int64  `json:"sourceRows"`
+	DestinationRows int64  `json:"destinationRows"`
+}
+
+// CopyDatabase copies every table of the source store into the
+// destination store, reading batchSize rows at a time. Both stores must
+// be migrated, and the destination must not contain any board or block
+// yet. The block search index isn't copied but rebuilt, as its format
+// depends on the database.
+//
+// The source can be in use during the copy, but the rows changed while
+// it runs may be missed, so the row counts of both stores are compared
+// once each table is copied and the copy fails if they differ. The last
+// copy must be run with the server stopped.
+func CopyDatabase(src, dst *SQLStore, batchSize int) ([]TableCopyResult, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultCopyBatchSize
+	}
+
+	for _, table := range []string{"boards", "blocks"} {
+		count, err := dst.countTableRows(table)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("the destination database is not empty, table %s has %d rows", dst.tablePrefix+table, count)
+		}
+	}
+
+	// the migrations of the destination may have inserted some rows
+	tablesToClear := append([]copyTable{{name: "block_search"}}, copyTables...)
+	for i := len(tablesToClear) - 1; i >= 0; i-- {
+		if _, err := dst.getQueryBuilder(dst.db).Delete(dst.tablePrefix + tablesToClear[i].name).Exec(); err != nil {
+			return nil, fmt.Errorf("cannot clear destination table %s: %w", dst.tablePrefix+tablesToClear[i].name, err)
+		}
+	}
+
+	results := make([]TableCopyResult, 0, len(copyTables))
+	for _, table := range copyTables {
+		result, err := copyTableRows(src, dst, table, batchSize)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+
+		if result.SourceRows != result.DestinationRows {
+			return results, fmt.Errorf("table %s has %d rows in the source and %d rows in the destination",
+				table.name, result.SourceRows, result.DestinationRows)
+		}
+
+		src.logger.Info("Table copied",
+			mlog.String("table", table.name),
+			mlog.Int("rows", result.CopiedRows),
+		)
+	}
+
+	if err := dst.indexAllBlocksForSearch(dst.db); err != nil {
+		return results, fmt.Errorf("cannot rebuild the search index: %w", err)
+	}
+	return results, nil
+}
+
+// copyTableRows copies the columns of a table that both stores have.
+func copyTableRows(src, dst *SQLStore, table copyTable, batchSize int) (TableCopyResult, error) {
+	result := TableCopyResult{Table: table.name}
+
+	srcColumns, err := src.tableColumns(table.name)
+	if err != nil {
+		return result, err
+	}
+	dstColumns, err := dst.tableColumns(table.name)
+	if err != nil {
+		return result, err
+	}
+
+	dstColumnSet := make(map[string]bool, len(dstColumns))
+	for _, column := range dstColumns {
+		dstColumnSet[strings.ToLower(column)] = true
+	}
+	columns := []string{}
+	for _, column := range srcColumns {
+		if dstColumnSet[strings.ToLower(column)] {
+			columns = append(columns, column)
+		}
+	}
+
+	reader := &copyReader{store: src, table: table, columns: columns, batchSize: batchSize}
+	for {
+		batch, err := reader.next()
+		if err != nil {
+			return result, fmt.Errorf("cannot read table %s: %w", table.name, err)
+		}
+
+		if err = dst.insertCopiedRows(table.name, columns, batch); err != nil {
+			return result, fmt.Errorf("cannot write table %s: %w", table.name, err)
+		}
+		result.CopiedRows += int64(len(batch))
+
+		if reader.done {
+			break
+		}
+	}
+
+	if result.SourceRows, err = src.countTableRows(table.name); err != nil {
+		return result, err
+	}
+	if result.DestinationRows, err = dst.countTableRows(table.name); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// copyReader reads the rows of a table by batches in the order of its
+// primary key, each batch starting after the last row read, so the rows
+// inserted or deleted in the source meanwhile don't shift the next
+// batches as an offset would.
+type copyReader struct {
+	store     *SQLStore
+	table     copyTable
+	columns   []string
+	batchSize int
+
+	// last is the primary key of the last row read, and tied the number
+	// of rows read with it, as the primary key isn't enforced by all the
+	// schemas of the history tables
+	last []interface{}
+	tied int
+
+	done bool
+}
+
+// next returns the values of the columns of the next rows, and sets done
+// once the last rows are read.
+func (r *copyReader) next() ([][]interface{}, error) {
+	keys := r.store.quoteColumns(r.table.primaryKeys)
+	keyValues := keys
+	if r.store.dbType == model.SqliteDBType {
+		// the dates are stored as text, in another format than the
+		// driver writes them
+		keyValues = make([]string, 0, len(keys))
+		for _, key := range keys {
+			keyValues = append(keyValues, "CAST("+key+" AS TEXT)")
+		}
+	}
+
+	query := r.store.getQueryBuilder(r.store.db).
+		Select(r.store.quoteColumns(r.columns)...).
+		Columns(keyValues...).
+		From(r.store.tablePrefix + r.table.name).
+		OrderBy(keys...).
+		Limit(uint64(r.batchSize + r.tied))
+
+	if r.last != nil {
+		// (k1, k2, ...) >= (v1, v2, ...), which not all the databases
+		// support as a row comparison
+		from := sq.Or{}
+		for i := range keys {
+			cond := sq.And{}
+			for j := 0; j < i; j++ {
+				cond = append(cond, sq.Eq{keys[j]: r.last[j]})
+			}
+			if i == len(keys)-1 {
+				cond = append(cond, sq.GtOrEq{keys[i]: r.last[i]})
+			} else {
+				cond = append(cond, sq.Gt{keys[i]: r.last[i]})
+			}
+			from = append(from, cond)
+		}
+		query = query.Where(from)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer r.store.CloseRows(rows)
+
+	read := 0
+	batch := [][]interface{}{}
+	var last []interface{}
+	tied := 0
+	for rows.Next() {
+		read++
+		values := make([]interface{}, len(r.columns)+len(keys))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			// text is returned as bytes by some drivers, and would be
+			// written as binary data
+			if b, ok := value.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+
+		key := values[len(r.columns):]
+		// the rows tied with the last one read were already read
+		if read <= r.tied && sameCopyKey(key, r.last) {
+			continue
+		}
+		batch = append(batch, values[:len(r.columns)])
+
+		if sameCopyKey(key, last) {
+			tied++
+		} else {
+			last, tied = key, 1
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if read < r.batchSize+r.tied {
+		r.done = true
+	}
+	if len(batch) == 0 {
+		return batch, nil
+	}
+
+	if len(batch) == tied && sameCopyKey(last, r.last) {
+		tied += r.tied
+	}
+	r.last, r.tied = last, tied
+	return batch, nil
+}
+
+func sameCopyKey(a, b []interface{}) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	for i := range a {
+		if fmt.Sprint(a[i]) != fmt.Sprint(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// insertCopiedRows inserts a batch of rows in a transaction, split in as
+// many statements as the parameter limit requires.
+func (s *SQLStore) insertCopiedRows(table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 || len(columns) == 0 {
+		return nil
+	}
+
+	rowsPerInsert := maxCopyInsertParams / len(columns)
+	if rowsPerInsert < 1 {
+		rowsPerInsert = 1
+	}
+
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(rows); start += rowsPerInsert {
+		end := start + rowsPerInsert
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		query := s.getQueryBuilder(tx).
+			Insert(s.tablePrefix + table).
+			Columns(s.quoteColumns(columns)...)
+		for _, row := range rows[start:end] {
+			query = query.Values(row...)
+		}
+
+		if _, err := query.Exec(); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				s.logger.Error("copy transaction rollback error", mlog.Err(rollbackErr), mlog.String("table", table))
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// tableColumns returns the names of the columns of a table.
+func (s *SQLStore) tableColumns(table string) ([]string, error) {
+	rows, err := s.getQueryBuilder(s.db).
+		Select("*").
+		From(s.tablePrefix + table).
+		Limit(1).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the columns of table %s: %w", s.tablePrefix+table, err)
+	}
+	defer s.CloseRows(rows)
+
+	return rows.Columns()
+}
+
+func (s *SQLStore) countTableRows(table string) (int64, error) {
+	var count int64
+	err := s.getQueryBuilder(s.db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + table).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("cannot count the rows of table %s: %w", s.tablePrefix+table, err)
+	}
+	return count, nil
+}
+
+// quoteColumns quotes the column names that are reserved words in MySQL.
+func (s *SQLStore) quoteColumns(columns []string) []string {
+	if s.dbType != model.MysqlDBType {
+		return columns
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = "`" + column + "`"
+	}
+	return quoted
+}
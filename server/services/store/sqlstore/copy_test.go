@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func setupCopyTestStore(t *testing.T, tablePrefix string) *SQLStore {
+	dbType, connectionString, err := PrepareNewTestDatabase()
+	require.NoError(t, err)
+
+	logger := mlog.CreateConsoleTestLogger(t)
+
+	sqlDB, err := sql.Open(dbType, connectionString)
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Ping())
+
+	store, err := New(Params{
+		DBType:           dbType,
+		ConnectionString: connectionString,
+		DBPingAttempts:   5,
+		TablePrefix:      tablePrefix,
+		Logger:           logger,
+		DB:               sqlDB,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, store.Shutdown())
+		_ = os.Remove(connectionString)
+	})
+	return store
+}
+
+func TestCopyDatabase(t *testing.T) {
+	origUnitTesting := os.Getenv("FOCALBOARD_UNIT_TESTING")
+	os.Setenv("FOCALBOARD_UNIT_TESTING", "1")
+	defer os.Setenv("FOCALBOARD_UNIT_TESTING", origUnitTesting)
+
+	src := setupCopyTestStore(t, "src_")
+	dst := setupCopyTestStore(t, "dst_")
+
+	userID := utils.NewID(utils.IDTypeUser)
+	teamID := "team-id"
+
+	_, err := src.CreateUser(&model.User{ID: userID, Username: "copied-user", Email: "copied@example.com"})
+	require.NoError(t, err)
+
+	board, err := src.InsertBoard(&model.Board{ID: "board-id", TeamID: teamID, Type: model.BoardTypeOpen, Title: "Copied board"}, userID)
+	require.NoError(t, err)
+	_, err = src.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: userID, SchemeAdmin: true})
+	require.NoError(t, err)
+
+	blocks := []*model.Block{
+		{ID: "card-1", BoardID: board.ID, Type: model.TypeCard, Title: "first searchable card", ModifiedBy: userID},
+		{ID: "card-2", BoardID: board.ID, Type: model.TypeCard, Title: "second card", ModifiedBy: userID},
+		{ID: "text-1", BoardID: board.ID, ParentID: "card-1", Type: model.TypeText, Title: "some text", ModifiedBy: userID},
+	}
+	require.NoError(t, src.InsertBlocks(blocks, userID))
+
+	// a second revision of a block, so its history has two rows
+	blocks[1].Title = "second card renamed"
+	require.NoError(t, src.InsertBlock(blocks[1], userID))
+
+	err = src.CreateCategory(model.Category{ID: "category-id", Name: "Copied", UserID: userID, TeamID: teamID, Type: model.CategoryTypeCustom})
+	require.NoError(t, err)
+
+	results, err := CopyDatabase(src, dst, 2)
+	require.NoError(t, err)
+	require.Len(t, results, len(copyTables))
+
+	rowsByTable := map[string]TableCopyResult{}
+	for _, result := range results {
+		require.Equal(t, result.SourceRows, result.DestinationRows, result.Table)
+		require.Equal(t, result.SourceRows, result.CopiedRows, result.Table)
+		rowsByTable[result.Table] = result
+	}
+	require.EqualValues(t, 3, rowsByTable["blocks"].CopiedRows)
+	require.EqualValues(t, 4, rowsByTable["blocks_history"].CopiedRows)
+
+	copiedBoard, err := dst.GetBoard(board.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Copied board", copiedBoard.Title)
+
+	copiedBlocks, err := dst.GetBlocksForBoard(board.ID)
+	require.NoError(t, err)
+	require.Len(t, copiedBlocks, 3)
+
+	copiedBlock, err := dst.GetBlock("card-2")
+	require.NoError(t, err)
+	require.Equal(t, "second card renamed", copiedBlock.Title)
+
+	members, err := dst.GetMembersForBoard(board.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	user, err := dst.GetUserByID(userID)
+	require.NoError(t, err)
+	require.Equal(t, "copied-user", user.Username)
+
+	srcSearchRows, err := src.countTableRows("block_search")
+	require.NoError(t, err)
+	dstSearchRows, err := dst.countTableRows("block_search")
+	require.NoError(t, err)
+	require.NotZero(t, dstSearchRows)
+	require.Equal(t, srcSearchRows, dstSearchRows)
+
+	t.Run("the destination must be empty", func(t *testing.T) {
+		_, err := CopyDatabase(src, dst, 2)
+		require.ErrorContains(t, err, "not empty")
+	})
+}
+
+func TestCopyReader(t *testing.T) {
+	origUnitTesting := os.Getenv("FOCALBOARD_UNIT_TESTING")
+	os.Setenv("FOCALBOARD_UNIT_TESTING", "1")
+	defer os.Setenv("FOCALBOARD_UNIT_TESTING", origUnitTesting)
+
+	src := setupCopyTestStore(t, "src_")
+	userID := utils.NewID(utils.IDTypeUser)
+
+	// two revisions of each block, so the history has a composite key
+	blocks := []*model.Block{
+		{ID: "block-1", BoardID: "board-id", Type: model.TypeCard, ModifiedBy: userID},
+		{ID: "block-2", BoardID: "board-id", Type: model.TypeCard, ModifiedBy: userID},
+		{ID: "block-3", BoardID: "board-id", Type: model.TypeCard, ModifiedBy: userID},
+	}
+	require.NoError(t, src.InsertBlocks(blocks, userID))
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, src.InsertBlocks(blocks, userID))
+
+	ids := func(batch [][]interface{}) []interface{} {
+		values := make([]interface{}, 0, len(batch))
+		for _, row := range batch {
+			values = append(values, row[0])
+		}
+		return values
+	}
+
+	t.Run("rows deleted between batches", func(t *testing.T) {
+		table := copyTable{name: "blocks_history", primaryKeys: []string{"id", "insert_at"}}
+		reader := &copyReader{store: src, table: table, columns: []string{"id", "title"}, batchSize: 3}
+
+		batch, err := reader.next()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"block-1", "block-1", "block-2"}, ids(batch))
+		require.False(t, reader.done)
+
+		// with an offset, the next batch would skip the rows of block-2
+		_, err = src.getQueryBuilder(src.db).Delete(src.tablePrefix + "blocks_history").Where(sq.Eq{"id": "block-1"}).Exec()
+		require.NoError(t, err)
+
+		batch, err = reader.next()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"block-2", "block-3", "block-3"}, ids(batch))
+		require.False(t, reader.done)
+
+		batch, err = reader.next()
+		require.NoError(t, err)
+		require.Empty(t, batch)
+		require.True(t, reader.done)
+	})
+
+	t.Run("rows with the same key", func(t *testing.T) {
+		table := copyTable{name: "blocks_history", primaryKeys: []string{"board_id"}}
+		reader := &copyReader{store: src, table: table, columns: []string{"id"}, batchSize: 1}
+
+		count := 0
+		for !reader.done {
+			batch, err := reader.next()
+			require.NoError(t, err)
+			count += len(batch)
+		}
+		require.Equal(t, 4, count)
+	})
+}
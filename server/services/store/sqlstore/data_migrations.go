@@ -28,6 +28,7 @@ const (
 	TeamLessBoardsMigrationKey                = "TeamLessBoardsMigrationComplete"
 	DeletedMembershipBoardsMigrationKey       = "DeletedMembershipBoardsMigrationComplete"
 	DeDuplicateCategoryBoardTableMigrationKey = "DeDuplicateCategoryBoardTableComplete"
+	BlockSearchIndexMigrationKey              = "BlockSearchIndexMigrationComplete"
 )
 
 func (s *SQLStore) getBlocksWithSameID(db sq.BaseRunner) ([]*model.Block, error) {
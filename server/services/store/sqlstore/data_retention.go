@@ -12,6 +12,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	_ "github.com/lib/pq" // postgres driver
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -22,53 +23,125 @@ type RetentionTableDeletionInfo struct {
 	BoardIDColumn string
 }
 
-func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64, batchSize int64) (int64, error) {
+// boardDataTables are the tables holding the data of a board, with the
+// column that references it.
+var boardDataTables = []RetentionTableDeletionInfo{
+	{
+		Table:         "blocks",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "blocks_history",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "boards",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "id",
+	},
+	{
+		Table:         "boards_history",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "id",
+	},
+	{
+		Table:         "board_members",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "board_members_history",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "sharing",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "id",
+	},
+	{
+		Table:         "category_boards",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "block_search",
+		PrimaryKeys:   []string{"block_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "retention_policies",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "card_dependencies",
+		PrimaryKeys:   []string{"blocker_board_id"},
+		BoardIDColumn: "blocker_board_id",
+	},
+	{
+		Table:         "card_dependencies",
+		PrimaryKeys:   []string{"blocked_board_id"},
+		BoardIDColumn: "blocked_board_id",
+	},
+	{
+		Table:         "recurring_card_rules",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "due_date_reminder_settings",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "due_date_reminders",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "webhooks",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "webhook_deliveries",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "incoming_webhooks",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "automation_rules",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "automation_executions",
+		PrimaryKeys:   []string{"id"},
+		BoardIDColumn: "board_id",
+	},
+	{
+		Table:         "automation_due_date_runs",
+		PrimaryKeys:   []string{"board_id"},
+		BoardIDColumn: "board_id",
+	},
+}
+
+// runDataRetention deletes the boards without activity since their
+// retention date. The retention date of a board comes from its own
+// retention policy, the one of its team, or the global retention date, in
+// that order, and the boards with a legal hold are never deleted. A
+// retention date of 0 keeps the boards forever.
+func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64, batchSize int64) (*model.DataRetentionReport, error) {
 	s.logger.Info("Start Boards Data Retention",
 		mlog.String("Global Retention Date", time.Unix(globalRetentionDate/1000, 0).String()),
 		mlog.Int("Raw Date", globalRetentionDate))
-	deleteTables := []RetentionTableDeletionInfo{
-		{
-			Table:         "blocks",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "blocks_history",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "boards",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "id",
-		},
-		{
-			Table:         "boards_history",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "id",
-		},
-		{
-			Table:         "board_members",
-			PrimaryKeys:   []string{"board_id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "board_members_history",
-			PrimaryKeys:   []string{"board_id"},
-			BoardIDColumn: "board_id",
-		},
-		{
-			Table:         "sharing",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "id",
-		},
-		{
-			Table:         "category_boards",
-			PrimaryKeys:   []string{"id"},
-			BoardIDColumn: "board_id",
-		},
-	}
-
 	subBuilder := s.getQueryBuilder(db).
 		Select("board_id, MAX(update_at) AS maxDate").
 		From(s.tablePrefix + "blocks").
@@ -77,38 +150,112 @@ func (s *SQLStore) runDataRetention(db sq.BaseRunner, globalRetentionDate int64,
 	subQuery, _, _ := subBuilder.ToSql()
 
 	builder := s.getQueryBuilder(db).
-		Select("id").
+		Select("id", "team_id", "maxDate").
 		From(s.tablePrefix + "boards").
 		LeftJoin("( " + subQuery + " ) As subquery ON (subquery.board_id = id)").
-		Where(sq.Lt{"maxDate": globalRetentionDate}).
+		Where(sq.NotEq{"maxDate": nil}).
 		Where(sq.NotEq{"team_id": "0"}).
-		Where(sq.Eq{"is_template": false})
+		Where(sq.Eq{"is_template": false}).
+		OrderBy("id")
 
 	rows, err := builder.Query()
 	if err != nil {
 		s.logger.Error(`dataRetention subquery ERROR`, mlog.Err(err))
-		return 0, err
+		return nil, err
 	}
 	defer s.CloseRows(rows)
-	deleteIds, err := idsFromRows(rows)
+
+	type boardActivity struct {
+		boardID        string
+		teamID         string
+		lastActivityAt int64
+	}
+	boards := []boardActivity{}
+	for rows.Next() {
+		var board boardActivity
+		if err = rows.Scan(&board.boardID, &board.teamID, &board.lastActivityAt); err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+
+	policies, err := s.getAllRetentionPolicies(db)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	teamPolicies := map[string]*model.RetentionPolicy{}
+	boardPolicies := map[string]*model.RetentionPolicy{}
+	for _, policy := range policies {
+		if policy.BoardID == "" {
+			teamPolicies[policy.TeamID] = policy
+		} else {
+			boardPolicies[policy.BoardID] = policy
+		}
+	}
+
+	report := &model.DataRetentionReport{
+		GlobalRetentionDate: globalRetentionDate,
+		Boards:              []*model.BoardRetentionResult{},
+		LegalHoldBoardIDs:   []string{},
 	}
+	now := utils.GetMillis()
+	for _, board := range boards {
+		scope := model.RetentionPolicyScopeGlobal
+		retentionDate := globalRetentionDate
+		if policy, ok := boardPolicies[board.boardID]; ok {
+			if policy.LegalHold {
+				report.LegalHoldBoardIDs = append(report.LegalHoldBoardIDs, board.boardID)
+				continue
+			}
+			scope = model.RetentionPolicyScopeBoard
+			retentionDate = retentionDateForDays(now, policy.RetentionDays)
+		} else if policy, ok := teamPolicies[board.teamID]; ok {
+			scope = model.RetentionPolicyScopeTeam
+			retentionDate = retentionDateForDays(now, policy.RetentionDays)
+		}
+
+		if retentionDate <= 0 || board.lastActivityAt >= retentionDate {
+			continue
+		}
 
-	totalAffected := 0
-	if len(deleteIds) > 0 {
-		for _, table := range deleteTables {
-			affected, err := s.genericRetentionPoliciesDeletion(db, table, deleteIds, batchSize)
+		result := &model.BoardRetentionResult{
+			BoardID:        board.boardID,
+			TeamID:         board.teamID,
+			Scope:          scope,
+			RetentionDate:  retentionDate,
+			LastActivityAt: board.lastActivityAt,
+			DeletedRows:    map[string]int64{},
+		}
+		for _, table := range boardDataTables {
+			affected, err := s.genericRetentionPoliciesDeletion(db, table, []string{board.boardID}, batchSize)
 			if err != nil {
-				return int64(totalAffected), err
+				return report, err
 			}
-			totalAffected += int(affected)
+			result.DeletedRows[table.Table] += affected
+			result.TotalDeleted += affected
 		}
+		report.Boards = append(report.Boards, result)
+		report.TotalDeleted += result.TotalDeleted
+
+		s.logger.Info("Boards Data Retention deleted board",
+			mlog.String("board_id", board.boardID),
+			mlog.String("scope", string(scope)),
+			mlog.Int("TotalAffected", result.TotalDeleted))
 	}
 	s.logger.Info("Complete Boards Data Retention",
-		mlog.Int("Total deletion ids", len(deleteIds)),
-		mlog.Int("TotalAffected", totalAffected))
-	return int64(totalAffected), nil
+		mlog.Int("Total deletion ids", len(report.Boards)),
+		mlog.Int("Legal hold ids", len(report.LegalHoldBoardIDs)),
+		mlog.Int("TotalAffected", report.TotalDeleted))
+	return report, nil
+}
+
+// retentionDateForDays returns the retention date of a period of days,
+// or 0 if the period is 0, which keeps the boards forever.
+func retentionDateForDays(now int64, days int) int64 {
+	if days <= 0 {
+		return 0
+	}
+	return now - int64(days)*int64(24*time.Hour/time.Millisecond)
 }
 
 func idsFromRows(rows *sql.Rows) ([]string, error) {
@@ -170,7 +317,9 @@ func (s *SQLStore) genericRetentionPoliciesDeletion(
 			return 0, errors.Wrap(err, "failed to get rows affected for "+info.Table)
 		}
 		totalRowsAffected += batchRowsAffected
-		if batchRowsAffected != batchSize {
+		// without batches everything is deleted at once, even when
+		// there was nothing to delete
+		if batchSize <= 0 || batchRowsAffected != batchSize {
 			break
 		}
 	}
@@ -0,0 +1,194 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func dueDateReminderSettingsFields() []string {
+	return []string{
+		"board_id",
+		"date_property_id",
+		"assignee_property_id",
+		"days_before",
+		"send_hour",
+		"enabled",
+		"modified_by",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) dueDateReminderSettingsFromRows(rows *sql.Rows) ([]*model.DueDateReminderSettings, error) {
+	results := []*model.DueDateReminderSettings{}
+	for rows.Next() {
+		var settings model.DueDateReminderSettings
+		var daysBeforeJSON string
+		err := rows.Scan(
+			&settings.BoardID,
+			&settings.DatePropertyID,
+			&settings.AssigneePropertyID,
+			&daysBeforeJSON,
+			&settings.Hour,
+			&settings.Enabled,
+			&settings.ModifiedBy,
+			&settings.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("dueDateReminderSettingsFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(daysBeforeJSON), &settings.DaysBefore); err != nil {
+			s.logger.Error("dueDateReminderSettingsFromRows days before error", mlog.Err(err))
+			return nil, err
+		}
+		results = append(results, &settings)
+	}
+	return results, nil
+}
+
+func (s *SQLStore) getDueDateReminderSettings(db sq.BaseRunner, boardID string) (*model.DueDateReminderSettings, error) {
+	query := s.getQueryBuilder(db).
+		Select(dueDateReminderSettingsFields()...).
+		From(s.tablePrefix + "due_date_reminder_settings").
+		Where(sq.Eq{"board_id": boardID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDueDateReminderSettings ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	results, err := s.dueDateReminderSettingsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, model.NewErrNotFound("due date reminder settings boardID=" + boardID)
+	}
+	return results[0], nil
+}
+
+func (s *SQLStore) getEnabledDueDateReminderSettings(db sq.BaseRunner) ([]*model.DueDateReminderSettings, error) {
+	query := s.getQueryBuilder(db).
+		Select(dueDateReminderSettingsFields()...).
+		From(s.tablePrefix + "due_date_reminder_settings").
+		Where(sq.Eq{"enabled": true}).
+		OrderBy("board_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getEnabledDueDateReminderSettings ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.dueDateReminderSettingsFromRows(rows)
+}
+
+func (s *SQLStore) saveDueDateReminderSettings(db sq.BaseRunner, settings *model.DueDateReminderSettings) (*model.DueDateReminderSettings, error) {
+	if err := settings.IsValid(); err != nil {
+		return nil, err
+	}
+
+	saved := *settings
+	saved.UpdateAt = utils.GetMillis()
+
+	daysBeforeJSON, err := json.Marshal(saved.DaysBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"due_date_reminder_settings").
+		Columns(dueDateReminderSettingsFields()...).
+		Values(
+			saved.BoardID,
+			saved.DatePropertyID,
+			saved.AssigneePropertyID,
+			string(daysBeforeJSON),
+			saved.Hour,
+			saved.Enabled,
+			saved.ModifiedBy,
+			saved.UpdateAt,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix(`ON DUPLICATE KEY UPDATE date_property_id = ?, assignee_property_id = ?, days_before = ?,
+			send_hour = ?, enabled = ?, modified_by = ?, update_at = ?`,
+			saved.DatePropertyID, saved.AssigneePropertyID, string(daysBeforeJSON),
+			saved.Hour, saved.Enabled, saved.ModifiedBy, saved.UpdateAt)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (board_id)
+			 DO UPDATE SET date_property_id = EXCLUDED.date_property_id, assignee_property_id = EXCLUDED.assignee_property_id,
+			 days_before = EXCLUDED.days_before, send_hour = EXCLUDED.send_hour, enabled = EXCLUDED.enabled,
+			 modified_by = EXCLUDED.modified_by, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`saveDueDateReminderSettings ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (s *SQLStore) deleteDueDateReminderSettings(db sq.BaseRunner, boardID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "due_date_reminder_settings").
+		Where(sq.Eq{"board_id": boardID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("due date reminder settings boardID=" + boardID)
+	}
+	return nil
+}
+
+// insertDueDateReminder records a reminder as sent, and returns false if
+// it had already been sent.
+func (s *SQLStore) insertDueDateReminder(db sq.BaseRunner, reminder *model.DueDateReminder) (bool, error) {
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"due_date_reminders").
+		Columns("board_id", "card_id", "user_id", "due_at", "days_before", "sent_at").
+		Values(
+			reminder.BoardID,
+			reminder.CardID,
+			reminder.UserID,
+			reminder.DueAt,
+			reminder.DaysBefore,
+			reminder.SentAt,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE sent_at = sent_at")
+	} else {
+		query = query.Suffix("ON CONFLICT (card_id, user_id, due_at, days_before) DO NOTHING")
+	}
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`insertDueDateReminder ERROR`, mlog.Err(err))
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func incomingWebhookFields() []string {
+	return []string{
+		"id",
+		"board_id",
+		"name",
+		"token",
+		"mapping",
+		"disabled",
+		"created_by",
+		"modified_by",
+		"create_at",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) incomingWebhooksFromRows(rows *sql.Rows) ([]*model.IncomingWebhook, error) {
+	webhooks := []*model.IncomingWebhook{}
+	for rows.Next() {
+		var webhook model.IncomingWebhook
+		var mappingJSON string
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.BoardID,
+			&webhook.Name,
+			&webhook.Token,
+			&mappingJSON,
+			&webhook.Disabled,
+			&webhook.CreatedBy,
+			&webhook.ModifiedBy,
+			&webhook.CreateAt,
+			&webhook.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("incomingWebhooksFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(mappingJSON), &webhook.Mapping); err != nil {
+			s.logger.Error("incomingWebhooksFromRows mapping error", mlog.Err(err))
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	return webhooks, nil
+}
+
+func (s *SQLStore) getIncomingWebhooks(db sq.BaseRunner, condition interface{}) ([]*model.IncomingWebhook, error) {
+	query := s.getQueryBuilder(db).
+		Select(incomingWebhookFields()...).
+		From(s.tablePrefix+"incoming_webhooks").
+		Where(condition).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getIncomingWebhooks ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.incomingWebhooksFromRows(rows)
+}
+
+func (s *SQLStore) getIncomingWebhook(db sq.BaseRunner, webhookID string) (*model.IncomingWebhook, error) {
+	webhooks, err := s.getIncomingWebhooks(db, sq.Eq{"id": webhookID})
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, model.NewErrNotFound("incoming webhook ID=" + webhookID)
+	}
+	return webhooks[0], nil
+}
+
+func (s *SQLStore) getIncomingWebhooksForBoard(db sq.BaseRunner, boardID string) ([]*model.IncomingWebhook, error) {
+	return s.getIncomingWebhooks(db, sq.Eq{"board_id": boardID})
+}
+
+func (s *SQLStore) insertIncomingWebhook(db sq.BaseRunner, webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	inserted := *webhook
+	if inserted.ID == "" {
+		inserted.ID = utils.NewID(utils.IDTypeNone)
+	}
+	inserted.CreateAt = utils.GetMillis()
+	inserted.UpdateAt = inserted.CreateAt
+
+	mappingJSON, err := json.Marshal(inserted.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"incoming_webhooks").
+		Columns(incomingWebhookFields()...).
+		Values(
+			inserted.ID,
+			inserted.BoardID,
+			inserted.Name,
+			inserted.Token,
+			mappingJSON,
+			inserted.Disabled,
+			inserted.CreatedBy,
+			inserted.ModifiedBy,
+			inserted.CreateAt,
+			inserted.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertIncomingWebhook ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+// updateIncomingWebhook saves the name, mapping and disabled state of an
+// incoming webhook.
+func (s *SQLStore) updateIncomingWebhook(db sq.BaseRunner, webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	updated := *webhook
+	updated.UpdateAt = utils.GetMillis()
+
+	mappingJSON, err := json.Marshal(updated.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"incoming_webhooks").
+		Set("name", updated.Name).
+		Set("mapping", mappingJSON).
+		Set("disabled", updated.Disabled).
+		Set("modified_by", updated.ModifiedBy).
+		Set("update_at", updated.UpdateAt).
+		Where(sq.Eq{"id": updated.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`updateIncomingWebhook ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrNotFound("incoming webhook ID=" + updated.ID)
+	}
+	return &updated, nil
+}
+
+func (s *SQLStore) deleteIncomingWebhook(db sq.BaseRunner, webhookID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "incoming_webhooks").
+		Where(sq.Eq{"id": webhookID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`deleteIncomingWebhook ERROR`, mlog.Err(err))
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("incoming webhook ID=" + webhookID)
+	}
+	return nil
+}
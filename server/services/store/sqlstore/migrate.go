@@ -240,6 +240,14 @@ func (s *SQLStore) runMigrationSequence(engine *morph.Morph, driver drivers.Driv
 		return err
 	}
 
+	if mErr := s.setupSQLiteFullTextSearch(); mErr != nil {
+		return fmt.Errorf("error setting up SQLite full-text search: %w", mErr)
+	}
+
+	if mErr := s.RunBlockSearchIndexMigration(); mErr != nil {
+		return fmt.Errorf("error running block search index migration: %w", mErr)
+	}
+
 	// always run the collations & charset fix-ups
 	if mErr := s.RunFixCollationsAndCharsetsMigration(); mErr != nil {
 		return fmt.Errorf("error running fix collations and charsets migration: %w", mErr)
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}block_search (
+    block_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    type TEXT NOT NULL,
+    content TEXT,
+    {{if .postgres}}content_tsv TSVECTOR,{{end}}
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (block_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "block_search" "board_id" }}
+{{ createIndexIfNeeded "block_search" "card_id" }}
+
+{{if .postgres}}
+CREATE INDEX IF NOT EXISTS idx_block_search_content_tsv ON {{.prefix}}block_search USING GIN (content_tsv);
+{{end}}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.prefix}}card_dependencies;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}card_dependencies (
+    blocker_id VARCHAR(36) NOT NULL,
+    blocker_board_id VARCHAR(36) NOT NULL,
+    blocked_id VARCHAR(36) NOT NULL,
+    blocked_board_id VARCHAR(36) NOT NULL,
+    created_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "card_dependencies" "blocked_id" }}
+{{ createIndexIfNeeded "card_dependencies" "blocker_board_id" }}
+{{ createIndexIfNeeded "card_dependencies" "blocked_board_id" }}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.prefix}}recurring_card_rules;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}recurring_card_rules (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    template_id VARCHAR(36) NOT NULL,
+    schedule VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    overrides {{if .postgres}}JSON{{else}}TEXT{{end}},
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by VARCHAR(36) NOT NULL,
+    modified_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    next_run_at BIGINT NOT NULL DEFAULT 0,
+    last_run_at BIGINT NOT NULL DEFAULT 0,
+    last_card_id VARCHAR(36) NOT NULL DEFAULT '',
+    last_error TEXT,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "recurring_card_rules" "board_id" }}
+{{ createIndexIfNeeded "recurring_card_rules" "paused, next_run_at" }}
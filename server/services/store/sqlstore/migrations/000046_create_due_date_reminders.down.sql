@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS {{.prefix}}due_date_reminders;
+DROP TABLE IF EXISTS {{.prefix}}due_date_reminder_settings;
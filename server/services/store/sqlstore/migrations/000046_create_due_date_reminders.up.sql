@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}due_date_reminder_settings (
+    board_id VARCHAR(36) NOT NULL,
+    date_property_id VARCHAR(36) NOT NULL,
+    assignee_property_id VARCHAR(36) NOT NULL,
+    days_before VARCHAR(255) NOT NULL,
+    send_hour INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    modified_by VARCHAR(36) NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (board_id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}due_date_reminders (
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    due_at BIGINT NOT NULL,
+    days_before INTEGER NOT NULL,
+    sent_at BIGINT NOT NULL,
+    PRIMARY KEY (card_id, user_id, due_at, days_before)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "due_date_reminder_settings" "enabled" }}
+{{ createIndexIfNeeded "due_date_reminders" "board_id" }}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS {{.prefix}}webhook_deliveries;
+DROP TABLE IF EXISTS {{.prefix}}webhooks;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}webhooks (
+    id VARCHAR(36) NOT NULL,
+    team_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL DEFAULT '',
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    event_types {{if .postgres}}JSON{{else}}TEXT{{end}},
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by VARCHAR(36) NOT NULL,
+    modified_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}webhook_deliveries (
+    id VARCHAR(36) NOT NULL,
+    webhook_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload {{if .postgres}}JSON{{else}}TEXT{{end}},
+    status VARCHAR(16) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at BIGINT NOT NULL DEFAULT 0,
+    last_attempt_at BIGINT NOT NULL DEFAULT 0,
+    response_code INTEGER NOT NULL DEFAULT 0,
+    response_body TEXT,
+    last_error TEXT,
+    redelivery_of VARCHAR(36) NOT NULL DEFAULT '',
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "webhooks" "team_id, board_id" }}
+{{ createIndexIfNeeded "webhooks" "board_id" }}
+{{ createIndexIfNeeded "webhook_deliveries" "webhook_id, create_at" }}
+{{ createIndexIfNeeded "webhook_deliveries" "status, next_attempt_at" }}
+{{ createIndexIfNeeded "webhook_deliveries" "board_id" }}
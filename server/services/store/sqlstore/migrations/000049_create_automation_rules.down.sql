@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS {{.prefix}}automation_due_date_runs;
+DROP TABLE IF EXISTS {{.prefix}}automation_executions;
+DROP TABLE IF EXISTS {{.prefix}}automation_rules;
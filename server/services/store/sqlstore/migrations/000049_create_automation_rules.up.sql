@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}automation_rules (
+    id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    trigger_type VARCHAR(64) NOT NULL,
+    trigger_params {{if .postgres}}JSON{{else}}TEXT{{end}},
+    conditions {{if .postgres}}JSON{{else}}TEXT{{end}},
+    actions {{if .postgres}}JSON{{else}}TEXT{{end}},
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    run_count BIGINT NOT NULL DEFAULT 0,
+    created_by VARCHAR(36) NOT NULL,
+    modified_by VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}automation_executions (
+    id VARCHAR(36) NOT NULL,
+    rule_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    trigger_type VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error_message TEXT,
+    depth INTEGER NOT NULL DEFAULT 0,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+CREATE TABLE IF NOT EXISTS {{.prefix}}automation_due_date_runs (
+    rule_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    card_id VARCHAR(36) NOT NULL,
+    due_at BIGINT NOT NULL,
+    run_at BIGINT NOT NULL,
+    PRIMARY KEY (rule_id, card_id, due_at)
+) {{if .mysql}}DEFAULT CHARACTER SET utf8mb4{{end}};
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "automation_rules" "board_id" }}
+{{ createIndexIfNeeded "automation_rules" "trigger_type, disabled" }}
+{{ createIndexIfNeeded "automation_executions" "rule_id, create_at" }}
+{{ createIndexIfNeeded "automation_executions" "board_id" }}
+{{ createIndexIfNeeded "automation_executions" "create_at" }}
+{{ createIndexIfNeeded "automation_due_date_runs" "board_id" }}
@@ -46,11 +46,45 @@ func (s *SQLStore) AddUpdateCategoryBoard(userID string, categoryID string, boar
 
 }
 
+func (s *SQLStore) AddUpdateViewCategoryView(userID string, categoryID string, viewIDs []string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.addUpdateViewCategoryView(s.db, userID, categoryID, viewIDs)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.addUpdateViewCategoryView(tx, userID, categoryID, viewIDs)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "AddUpdateViewCategoryView"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) CanSeeUser(seerID string, seenID string) (bool, error) {
 	return s.canSeeUser(s.db, seerID, seenID)
 
 }
 
+func (s *SQLStore) ClaimRecurringCardRuleRun(ruleID string, nextRunAt int64, newNextRunAt int64) (bool, error) {
+	return s.claimRecurringCardRuleRun(s.db, ruleID, nextRunAt, newNextRunAt)
+
+}
+
+func (s *SQLStore) ClaimWebhookDelivery(deliveryID string, nextAttemptAt int64, newNextAttemptAt int64) (bool, error) {
+	return s.claimWebhookDelivery(s.db, deliveryID, nextAttemptAt, newNextAttemptAt)
+
+}
+
 func (s *SQLStore) CleanUpSessions(expireTime int64) error {
 	return s.cleanUpSessions(s.db, expireTime)
 
@@ -143,6 +177,59 @@ func (s *SQLStore) CreateUser(user *model.User) (*model.User, error) {
 
 }
 
+func (s *SQLStore) CreateViewCategory(viewCategory model.ViewCategory) error {
+	if s.dbType == model.SqliteDBType {
+		return s.createViewCategory(s.db, viewCategory)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.createViewCategory(tx, viewCategory)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CreateViewCategory"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteAutomationExecutionsBefore(createAt int64) (int64, error) {
+	return s.deleteAutomationExecutionsBefore(s.db, createAt)
+
+}
+
+func (s *SQLStore) DeleteAutomationRule(ruleID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteAutomationRule(s.db, ruleID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteAutomationRule(tx, ruleID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteAutomationRule"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) DeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBlock(s.db, blockID, modifiedBy)
@@ -225,11 +312,26 @@ func (s *SQLStore) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, user
 
 }
 
+func (s *SQLStore) DeleteCardDependency(blockerID string, blockedID string) error {
+	return s.deleteCardDependency(s.db, blockerID, blockedID)
+
+}
+
 func (s *SQLStore) DeleteCategory(categoryID string, userID string, teamID string) error {
 	return s.deleteCategory(s.db, categoryID, userID, teamID)
 
 }
 
+func (s *SQLStore) DeleteDueDateReminderSettings(boardID string) error {
+	return s.deleteDueDateReminderSettings(s.db, boardID)
+
+}
+
+func (s *SQLStore) DeleteIncomingWebhook(webhookID string) error {
+	return s.deleteIncomingWebhook(s.db, webhookID)
+
+}
+
 func (s *SQLStore) DeleteMember(boardID string, userID string) error {
 	return s.deleteMember(s.db, boardID, userID)
 
@@ -240,6 +342,16 @@ func (s *SQLStore) DeleteNotificationHint(blockID string) error {
 
 }
 
+func (s *SQLStore) DeleteRecurringCardRule(ruleID string) error {
+	return s.deleteRecurringCardRule(s.db, ruleID)
+
+}
+
+func (s *SQLStore) DeleteRetentionPolicy(teamID string, boardID string) error {
+	return s.deleteRetentionPolicy(s.db, teamID, boardID)
+
+}
+
 func (s *SQLStore) DeleteSession(sessionID string) error {
 	return s.deleteSession(s.db, sessionID)
 
@@ -250,6 +362,40 @@ func (s *SQLStore) DeleteSubscription(blockID string, subscriberID string) error
 
 }
 
+func (s *SQLStore) DeleteViewCategory(categoryID string, userID string, boardID string) error {
+	return s.deleteViewCategory(s.db, categoryID, userID, boardID)
+
+}
+
+func (s *SQLStore) DeleteWebhook(webhookID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteWebhook(s.db, webhookID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteWebhook(tx, webhookID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteWebhook"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteWebhookDeliveriesBefore(createAt int64) (int64, error) {
+	return s.deleteWebhookDeliveriesBefore(s.db, createAt)
+
+}
+
 func (s *SQLStore) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.duplicateBlock(s.db, boardID, blockID, userID, asTemplate)
@@ -303,11 +449,31 @@ func (s *SQLStore) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
 
 }
 
+func (s *SQLStore) GetActiveWebhooks(teamID string, boardID string) ([]*model.Webhook, error) {
+	return s.getActiveWebhooks(s.db, teamID, boardID)
+
+}
+
 func (s *SQLStore) GetAllTeams() ([]*model.Team, error) {
 	return s.getAllTeams(s.db)
 
 }
 
+func (s *SQLStore) GetAutomationExecutions(opts model.QueryAutomationExecutionsOptions) ([]*model.AutomationExecution, error) {
+	return s.getAutomationExecutions(s.db, opts)
+
+}
+
+func (s *SQLStore) GetAutomationRule(ruleID string) (*model.AutomationRule, error) {
+	return s.getAutomationRule(s.db, ruleID)
+
+}
+
+func (s *SQLStore) GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error) {
+	return s.getAutomationRulesForBoard(s.db, boardID)
+
+}
+
 func (s *SQLStore) GetBlock(blockID string) (*model.Block, error) {
 	return s.getBlock(s.db, blockID)
 
@@ -333,6 +499,11 @@ func (s *SQLStore) GetBlockHistoryNewestChildren(parentID string, opts model.Que
 
 }
 
+func (s *SQLStore) GetBlockHistoryNewestDescendants(boardID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error) {
+	return s.getBlockHistoryNewestDescendants(s.db, boardID, opts)
+
+}
+
 func (s *SQLStore) GetBlocks(opts model.QueryBlocksOptions) ([]*model.Block, error) {
 	return s.getBlocks(s.db, opts)
 
@@ -418,6 +589,11 @@ func (s *SQLStore) GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*mo
 
 }
 
+func (s *SQLStore) GetCardDependencies(opts model.QueryCardDependenciesOptions) ([]*model.CardDependency, error) {
+	return s.getCardDependencies(s.db, opts)
+
+}
+
 func (s *SQLStore) GetCardLimitTimestamp() (int64, error) {
 	return s.getCardLimitTimestamp(s.db)
 
@@ -428,101 +604,63 @@ func (s *SQLStore) GetCategory(id string) (*model.Category, error) {
 
 }
 
-func (s *SQLStore) GetViewCategory(id string) (*model.ViewCategory, error) {
-	return s.getViewCategory(s.db, id)
+func (s *SQLStore) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
+	return s.getChannel(s.db, teamID, channelID)
 
 }
 
-func (s *SQLStore) GetUserViewCategories(userID string, boardID string) ([]model.ViewCategory, error) {
-	return s.getUserViewCategories(s.db, userID, boardID)
+func (s *SQLStore) GetChecklistProgress(cardIDs []string) (map[string]model.ChecklistProgress, error) {
+	return s.getChecklistProgress(s.db, cardIDs)
 
 }
 
-func (s *SQLStore) CreateViewCategory(viewCategory model.ViewCategory) error {
-	if s.dbType == model.SqliteDBType {
-		return s.createViewCategory(s.db, viewCategory)
-	}
-	tx, txErr := s.db.BeginTx(context.Background(), nil)
-	if txErr != nil {
-		return txErr
-	}
-	err := s.createViewCategory(tx, viewCategory)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CreateViewCategory"))
-		}
-		return err
-	}
-
-	if err := tx.Commit(); err != nil {
-		return err
-	}
-
-	return nil
+func (s *SQLStore) GetDeletedBlocksForBoard(boardID string, blockTypes []model.BlockType) ([]*model.Block, error) {
+	return s.getDeletedBlocksForBoard(s.db, boardID, blockTypes)
 
 }
 
-func (s *SQLStore) UpdateViewCategory(viewCategory model.ViewCategory) error {
-	return s.updateViewCategory(s.db, viewCategory)
+func (s *SQLStore) GetDeletedBoardsForTeam(teamID string) ([]*model.Board, error) {
+	return s.getDeletedBoardsForTeam(s.db, teamID)
 
 }
 
-func (s *SQLStore) DeleteViewCategory(categoryID string, userID string, boardID string) error {
-	return s.deleteViewCategory(s.db, categoryID, userID, boardID)
+func (s *SQLStore) GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error) {
+	return s.getDueDateReminderSettings(s.db, boardID)
 
 }
 
-func (s *SQLStore) ReorderViewCategories(userID string, boardID string, newCategoryOrder []string) ([]string, error) {
-	return s.reorderViewCategories(s.db, userID, boardID, newCategoryOrder)
+func (s *SQLStore) GetDueRecurringCardRules(now int64, limit int) ([]*model.RecurringCardRule, error) {
+	return s.getDueRecurringCardRules(s.db, now, limit)
 
 }
 
-func (s *SQLStore) GetUserViewCategoryViews(userID string, boardID string) ([]model.ViewCategoryViews, error) {
-	return s.getUserViewCategoryViews(s.db, userID, boardID)
+func (s *SQLStore) GetDueWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error) {
+	return s.getDueWebhookDeliveries(s.db, now, limit)
 
 }
 
-func (s *SQLStore) AddUpdateViewCategoryView(userID string, categoryID string, viewIDs []string) error {
-	if s.dbType == model.SqliteDBType {
-		return s.addUpdateViewCategoryView(s.db, userID, categoryID, viewIDs)
-	}
-	tx, txErr := s.db.BeginTx(context.Background(), nil)
-	if txErr != nil {
-		return txErr
-	}
-	err := s.addUpdateViewCategoryView(tx, userID, categoryID, viewIDs)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "AddUpdateViewCategoryView"))
-		}
-		return err
-	}
-
-	if err := tx.Commit(); err != nil {
-		return err
-	}
-
-	return nil
+func (s *SQLStore) GetEnabledAutomationRules(triggerType string) ([]*model.AutomationRule, error) {
+	return s.getEnabledAutomationRules(s.db, triggerType)
 
 }
 
-func (s *SQLStore) ReorderViewCategoryViews(categoryID string, newViewsOrder []string) ([]string, error) {
-	return s.reorderViewCategoryViews(s.db, categoryID, newViewsOrder)
+func (s *SQLStore) GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error) {
+	return s.getEnabledDueDateReminderSettings(s.db)
 
 }
 
-func (s *SQLStore) SetViewVisibility(userID string, categoryID string, viewID string, visible bool) error {
-	return s.setViewVisibility(s.db, userID, categoryID, viewID, visible)
+func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
+	return s.getFileInfo(s.db, id)
 
 }
 
-func (s *SQLStore) GetChannel(teamID string, channelID string) (*mmModel.Channel, error) {
-	return s.getChannel(s.db, teamID, channelID)
+func (s *SQLStore) GetIncomingWebhook(webhookID string) (*model.IncomingWebhook, error) {
+	return s.getIncomingWebhook(s.db, webhookID)
 
 }
 
-func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
-	return s.getFileInfo(s.db, id)
+func (s *SQLStore) GetIncomingWebhooksForBoard(boardID string) ([]*model.IncomingWebhook, error) {
+	return s.getIncomingWebhooksForBoard(s.db, boardID)
 
 }
 
@@ -556,11 +694,26 @@ func (s *SQLStore) GetNotificationHint(blockID string) (*model.NotificationHint,
 
 }
 
+func (s *SQLStore) GetRecurringCardRule(ruleID string) (*model.RecurringCardRule, error) {
+	return s.getRecurringCardRule(s.db, ruleID)
+
+}
+
+func (s *SQLStore) GetRecurringCardRulesForBoard(boardID string) ([]*model.RecurringCardRule, error) {
+	return s.getRecurringCardRulesForBoard(s.db, boardID)
+
+}
+
 func (s *SQLStore) GetRegisteredUserCount() (int, error) {
 	return s.getRegisteredUserCount(s.db)
 
 }
 
+func (s *SQLStore) GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error) {
+	return s.getRetentionPolicies(s.db, teamID)
+
+}
+
 func (s *SQLStore) GetSession(token string, expireTime int64) (*model.Session, error) {
 	return s.getSession(s.db, token, expireTime)
 
@@ -666,6 +819,16 @@ func (s *SQLStore) GetUserTimezone(userID string) (string, error) {
 
 }
 
+func (s *SQLStore) GetUserViewCategories(userID string, boardID string) ([]model.ViewCategory, error) {
+	return s.getUserViewCategories(s.db, userID, boardID)
+
+}
+
+func (s *SQLStore) GetUserViewCategoryViews(userID string, boardID string) ([]model.ViewCategoryViews, error) {
+	return s.getUserViewCategoryViews(s.db, userID, boardID)
+
+}
+
 func (s *SQLStore) GetUsersByTeam(teamID string, asGuestID string, showEmail bool, showName bool) ([]*model.User, error) {
 	return s.getUsersByTeam(s.db, teamID, asGuestID, showEmail, showName)
 
@@ -676,6 +839,56 @@ func (s *SQLStore) GetUsersList(userIDs []string, showEmail bool, showName bool)
 
 }
 
+func (s *SQLStore) GetViewCategory(id string) (*model.ViewCategory, error) {
+	return s.getViewCategory(s.db, id)
+
+}
+
+func (s *SQLStore) GetWebhook(webhookID string) (*model.Webhook, error) {
+	return s.getWebhook(s.db, webhookID)
+
+}
+
+func (s *SQLStore) GetWebhookDeliveries(opts model.QueryWebhookDeliveriesOptions) ([]*model.WebhookDelivery, error) {
+	return s.getWebhookDeliveries(s.db, opts)
+
+}
+
+func (s *SQLStore) GetWebhookDelivery(deliveryID string) (*model.WebhookDelivery, error) {
+	return s.getWebhookDelivery(s.db, deliveryID)
+
+}
+
+func (s *SQLStore) GetWebhooksForBoard(boardID string) ([]*model.Webhook, error) {
+	return s.getWebhooksForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetWebhooksForTeam(teamID string) ([]*model.Webhook, error) {
+	return s.getWebhooksForTeam(s.db, teamID)
+
+}
+
+func (s *SQLStore) IncrementAutomationRuleRunCount(ruleID string) (int64, error) {
+	return s.incrementAutomationRuleRunCount(s.db, ruleID)
+
+}
+
+func (s *SQLStore) InsertAutomationDueDateRun(run *model.AutomationDueDateRun) (bool, error) {
+	return s.insertAutomationDueDateRun(s.db, run)
+
+}
+
+func (s *SQLStore) InsertAutomationExecution(execution *model.AutomationExecution) (*model.AutomationExecution, error) {
+	return s.insertAutomationExecution(s.db, execution)
+
+}
+
+func (s *SQLStore) InsertAutomationRule(rule *model.AutomationRule) (*model.AutomationRule, error) {
+	return s.insertAutomationRule(s.db, rule)
+
+}
+
 func (s *SQLStore) InsertBlock(block *model.Block, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.insertBlock(s.db, block, userID)
@@ -753,6 +966,36 @@ func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*mod
 
 }
 
+func (s *SQLStore) InsertCardDependency(dependency *model.CardDependency) (*model.CardDependency, error) {
+	return s.insertCardDependency(s.db, dependency)
+
+}
+
+func (s *SQLStore) InsertDueDateReminder(reminder *model.DueDateReminder) (bool, error) {
+	return s.insertDueDateReminder(s.db, reminder)
+
+}
+
+func (s *SQLStore) InsertIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	return s.insertIncomingWebhook(s.db, webhook)
+
+}
+
+func (s *SQLStore) InsertRecurringCardRule(rule *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	return s.insertRecurringCardRule(s.db, rule)
+
+}
+
+func (s *SQLStore) InsertWebhook(webhook *model.Webhook) (*model.Webhook, error) {
+	return s.insertWebhook(s.db, webhook)
+
+}
+
+func (s *SQLStore) InsertWebhookDelivery(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return s.insertWebhookDelivery(s.db, delivery)
+
+}
+
 func (s *SQLStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBlock(s.db, blockID, blockPatch, userID)
@@ -859,6 +1102,59 @@ func (s *SQLStore) PostMessage(message string, postType string, channelID string
 
 }
 
+func (s *SQLStore) PurgeBlock(blockID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeBlock(s.db, blockID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.purgeBlock(tx, blockID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeBlock"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) PurgeBoard(boardID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.purgeBoard(s.db, boardID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.purgeBoard(tx, boardID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "PurgeBoard"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) QueryCards(boardID string, opts model.QueryCardsOptions) (*model.CardQueryResult, error) {
+	return s.queryCards(s.db, boardID, opts)
+
+}
+
 func (s *SQLStore) RefreshSession(session *model.Session) error {
 	return s.refreshSession(s.db, session)
 
@@ -879,30 +1175,69 @@ func (s *SQLStore) ReorderCategoryBoards(categoryID string, newBoardsOrder []str
 
 }
 
-func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
+func (s *SQLStore) ReorderViewCategories(userID string, boardID string, newCategoryOrder []string) ([]string, error) {
+	return s.reorderViewCategories(s.db, userID, boardID, newCategoryOrder)
+
+}
+
+func (s *SQLStore) ReorderViewCategoryViews(categoryID string, newViewsOrder []string) ([]string, error) {
+	return s.reorderViewCategoryViews(s.db, categoryID, newViewsOrder)
+
+}
+
+func (s *SQLStore) RestoreBoardAndBlocks(board *model.Board, blocks []*model.Block, deletedBlockIDs []string, userID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.restoreBoardAndBlocks(s.db, board, blocks, deletedBlockIDs, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.restoreBoardAndBlocks(tx, board, blocks, deletedBlockIDs, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RestoreBoardAndBlocks"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64) (*model.DataRetentionReport, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.runDataRetention(s.db, globalRetentionDate, batchSize)
 	}
 	tx, txErr := s.db.BeginTx(context.Background(), nil)
 	if txErr != nil {
-		return 0, txErr
+		return nil, txErr
 	}
 	result, err := s.runDataRetention(tx, globalRetentionDate, batchSize)
 	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "RunDataRetention"))
 		}
-		return 0, err
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	return result, nil
 
 }
 
+func (s *SQLStore) SaveDueDateReminderSettings(settings *model.DueDateReminderSettings) (*model.DueDateReminderSettings, error) {
+	return s.saveDueDateReminderSettings(s.db, settings)
+
+}
+
 func (s *SQLStore) SaveFileInfo(fileInfo *mmModel.FileInfo) error {
 	return s.saveFileInfo(s.db, fileInfo)
 
@@ -913,6 +1248,11 @@ func (s *SQLStore) SaveMember(bm *model.BoardMember) (*model.BoardMember, error)
 
 }
 
+func (s *SQLStore) SaveRetentionPolicy(policy *model.RetentionPolicy) (*model.RetentionPolicy, error) {
+	return s.saveRetentionPolicy(s.db, policy)
+
+}
+
 func (s *SQLStore) SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error) {
 	return s.searchBoardsForUser(s.db, term, searchField, userID, includePublicBoards)
 
@@ -923,6 +1263,11 @@ func (s *SQLStore) SearchBoardsForUserInTeam(teamID string, term string, userID
 
 }
 
+func (s *SQLStore) SearchCards(opts model.CardSearchOptions) ([]*model.CardSearchResult, error) {
+	return s.searchCards(s.db, opts)
+
+}
+
 func (s *SQLStore) SearchUserChannels(teamID string, userID string, query string) ([]*mmModel.Channel, error) {
 	return s.searchUserChannels(s.db, teamID, userID, query)
 
@@ -948,6 +1293,11 @@ func (s *SQLStore) SetSystemSetting(key string, value string) error {
 
 }
 
+func (s *SQLStore) SetViewVisibility(userID string, categoryID string, viewID string, visible bool) error {
+	return s.setViewVisibility(s.db, userID, categoryID, viewID, visible)
+
+}
+
 func (s *SQLStore) UndeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.undeleteBlock(s.db, blockID, modifiedBy)
@@ -996,6 +1346,11 @@ func (s *SQLStore) UndeleteBoard(boardID string, modifiedBy string) error {
 
 }
 
+func (s *SQLStore) UpdateAutomationRule(rule *model.AutomationRule) (*model.AutomationRule, error) {
+	return s.updateAutomationRule(s.db, rule)
+
+}
+
 func (s *SQLStore) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
 	return s.updateCardLimitTimestamp(s.db, cardLimit)
 
@@ -1006,6 +1361,21 @@ func (s *SQLStore) UpdateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) UpdateIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	return s.updateIncomingWebhook(s.db, webhook)
+
+}
+
+func (s *SQLStore) UpdateRecurringCardRule(rule *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	return s.updateRecurringCardRule(s.db, rule)
+
+}
+
+func (s *SQLStore) UpdateRecurringCardRuleLastRun(ruleID string, lastRunAt int64, lastCardID string, lastError string) error {
+	return s.updateRecurringCardRuleLastRun(s.db, ruleID, lastRunAt, lastCardID, lastError)
+
+}
+
 func (s *SQLStore) UpdateSession(session *model.Session) error {
 	return s.updateSession(s.db, session)
 
@@ -1036,6 +1406,21 @@ func (s *SQLStore) UpdateUserUsername(userID string, username string) error {
 
 }
 
+func (s *SQLStore) UpdateViewCategory(viewCategory model.ViewCategory) error {
+	return s.updateViewCategory(s.db, viewCategory)
+
+}
+
+func (s *SQLStore) UpdateWebhook(webhook *model.Webhook) (*model.Webhook, error) {
+	return s.updateWebhook(s.db, webhook)
+
+}
+
+func (s *SQLStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return s.updateWebhookDelivery(s.db, delivery)
+
+}
+
 func (s *SQLStore) UpsertNotificationHint(hint *model.NotificationHint, notificationFreq time.Duration) (*model.NotificationHint, error) {
 	return s.upsertNotificationHint(s.db, hint, notificationFreq)
 
@@ -0,0 +1,268 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func recurringCardRuleFields() []string {
+	return []string{
+		"id",
+		"board_id",
+		"template_id",
+		"schedule",
+		"timezone",
+		"overrides",
+		"paused",
+		"created_by",
+		"modified_by",
+		"create_at",
+		"update_at",
+		"next_run_at",
+		"last_run_at",
+		"last_card_id",
+		"last_error",
+	}
+}
+
+func (s *SQLStore) recurringCardRulesFromRows(rows *sql.Rows) ([]*model.RecurringCardRule, error) {
+	rules := []*model.RecurringCardRule{}
+	for rows.Next() {
+		var rule model.RecurringCardRule
+		var overridesJSON string
+		err := rows.Scan(
+			&rule.ID,
+			&rule.BoardID,
+			&rule.TemplateID,
+			&rule.Schedule,
+			&rule.Timezone,
+			&overridesJSON,
+			&rule.Paused,
+			&rule.CreatedBy,
+			&rule.ModifiedBy,
+			&rule.CreateAt,
+			&rule.UpdateAt,
+			&rule.NextRunAt,
+			&rule.LastRunAt,
+			&rule.LastCardID,
+			&rule.LastError,
+		)
+		if err != nil {
+			s.logger.Error("recurringCardRulesFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(overridesJSON), &rule.Overrides); err != nil {
+			s.logger.Error("recurringCardRulesFromRows overrides error", mlog.Err(err))
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStore) getRecurringCardRules(db sq.BaseRunner, condition interface{}) ([]*model.RecurringCardRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(recurringCardRuleFields()...).
+		From(s.tablePrefix+"recurring_card_rules").
+		Where(condition).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getRecurringCardRules ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.recurringCardRulesFromRows(rows)
+}
+
+func (s *SQLStore) getRecurringCardRulesForBoard(db sq.BaseRunner, boardID string) ([]*model.RecurringCardRule, error) {
+	return s.getRecurringCardRules(db, sq.Eq{"board_id": boardID})
+}
+
+func (s *SQLStore) getRecurringCardRule(db sq.BaseRunner, ruleID string) (*model.RecurringCardRule, error) {
+	rules, err := s.getRecurringCardRules(db, sq.Eq{"id": ruleID})
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, model.NewErrNotFound("recurring card rule ID=" + ruleID)
+	}
+	return rules[0], nil
+}
+
+// getDueRecurringCardRules returns the rules that aren't paused and whose
+// next run is due, oldest first.
+func (s *SQLStore) getDueRecurringCardRules(db sq.BaseRunner, now int64, limit int) ([]*model.RecurringCardRule, error) {
+	query := s.getQueryBuilder(db).
+		Select(recurringCardRuleFields()...).
+		From(s.tablePrefix+"recurring_card_rules").
+		Where(sq.Eq{"paused": false}).
+		Where(sq.Gt{"next_run_at": 0}).
+		Where(sq.LtOrEq{"next_run_at": now}).
+		OrderBy("next_run_at", "id")
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDueRecurringCardRules ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.recurringCardRulesFromRows(rows)
+}
+
+func (s *SQLStore) insertRecurringCardRule(db sq.BaseRunner, rule *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	inserted := *rule
+	if inserted.ID == "" {
+		inserted.ID = utils.NewID(utils.IDTypeNone)
+	}
+	inserted.CreateAt = utils.GetMillis()
+	inserted.UpdateAt = inserted.CreateAt
+
+	overridesJSON, err := json.Marshal(inserted.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"recurring_card_rules").
+		Columns(recurringCardRuleFields()...).
+		Values(
+			inserted.ID,
+			inserted.BoardID,
+			inserted.TemplateID,
+			inserted.Schedule,
+			inserted.Timezone,
+			overridesJSON,
+			inserted.Paused,
+			inserted.CreatedBy,
+			inserted.ModifiedBy,
+			inserted.CreateAt,
+			inserted.UpdateAt,
+			inserted.NextRunAt,
+			inserted.LastRunAt,
+			inserted.LastCardID,
+			inserted.LastError,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertRecurringCardRule ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+// updateRecurringCardRule saves the settings and the next run of a rule.
+func (s *SQLStore) updateRecurringCardRule(db sq.BaseRunner, rule *model.RecurringCardRule) (*model.RecurringCardRule, error) {
+	if err := rule.IsValid(); err != nil {
+		return nil, err
+	}
+
+	updated := *rule
+	updated.UpdateAt = utils.GetMillis()
+
+	overridesJSON, err := json.Marshal(updated.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"recurring_card_rules").
+		Set("schedule", updated.Schedule).
+		Set("timezone", updated.Timezone).
+		Set("overrides", overridesJSON).
+		Set("paused", updated.Paused).
+		Set("modified_by", updated.ModifiedBy).
+		Set("update_at", updated.UpdateAt).
+		Set("next_run_at", updated.NextRunAt).
+		Where(sq.Eq{"id": updated.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`updateRecurringCardRule ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrNotFound("recurring card rule ID=" + updated.ID)
+	}
+	return &updated, nil
+}
+
+// claimRecurringCardRuleRun moves the next run of a rule from nextRunAt to
+// newNextRunAt, and returns false if the rule has been changed or claimed
+// by another server in the meantime.
+func (s *SQLStore) claimRecurringCardRuleRun(db sq.BaseRunner, ruleID string, nextRunAt, newNextRunAt int64) (bool, error) {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"recurring_card_rules").
+		Set("next_run_at", newNextRunAt).
+		Where(sq.Eq{"id": ruleID}).
+		Where(sq.Eq{"paused": false}).
+		Where(sq.Eq{"next_run_at": nextRunAt})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`claimRecurringCardRuleRun ERROR`, mlog.Err(err))
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+func (s *SQLStore) updateRecurringCardRuleLastRun(db sq.BaseRunner, ruleID string, lastRunAt int64, lastCardID, lastError string) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"recurring_card_rules").
+		Set("last_run_at", lastRunAt).
+		Set("last_card_id", lastCardID).
+		Set("last_error", lastError).
+		Where(sq.Eq{"id": ruleID})
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`updateRecurringCardRuleLastRun ERROR`, mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteRecurringCardRule(db sq.BaseRunner, ruleID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "recurring_card_rules").
+		Where(sq.Eq{"id": ruleID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("recurring card rule ID=" + ruleID)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func retentionPolicyFields() []string {
+	return []string{
+		"team_id",
+		"board_id",
+		"retention_days",
+		"legal_hold",
+		"modified_by",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) retentionPoliciesFromRows(rows *sql.Rows) ([]*model.RetentionPolicy, error) {
+	policies := []*model.RetentionPolicy{}
+	for rows.Next() {
+		var policy model.RetentionPolicy
+		err := rows.Scan(
+			&policy.TeamID,
+			&policy.BoardID,
+			&policy.RetentionDays,
+			&policy.LegalHold,
+			&policy.ModifiedBy,
+			&policy.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("retentionPoliciesFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		policies = append(policies, &policy)
+	}
+	return policies, nil
+}
+
+// getRetentionPolicies returns the default policy of a team, if it has
+// one, followed by the policies of its boards.
+func (s *SQLStore) getRetentionPolicies(db sq.BaseRunner, teamID string) ([]*model.RetentionPolicy, error) {
+	query := s.getQueryBuilder(db).
+		Select(retentionPolicyFields()...).
+		From(s.tablePrefix + "retention_policies").
+		Where(sq.Eq{"team_id": teamID}).
+		OrderBy("board_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getRetentionPolicies ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.retentionPoliciesFromRows(rows)
+}
+
+func (s *SQLStore) getAllRetentionPolicies(db sq.BaseRunner) ([]*model.RetentionPolicy, error) {
+	query := s.getQueryBuilder(db).
+		Select(retentionPolicyFields()...).
+		From(s.tablePrefix + "retention_policies")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getAllRetentionPolicies ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.retentionPoliciesFromRows(rows)
+}
+
+// saveRetentionPolicy creates or replaces the policy of a team or a board.
+func (s *SQLStore) saveRetentionPolicy(db sq.BaseRunner, policy *model.RetentionPolicy) (*model.RetentionPolicy, error) {
+	if err := policy.IsValid(); err != nil {
+		return nil, err
+	}
+
+	saved := *policy
+	saved.UpdateAt = utils.GetMillis()
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"retention_policies").
+		Columns(retentionPolicyFields()...).
+		Values(
+			saved.TeamID,
+			saved.BoardID,
+			saved.RetentionDays,
+			saved.LegalHold,
+			saved.ModifiedBy,
+			saved.UpdateAt,
+		)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("ON DUPLICATE KEY UPDATE retention_days = ?, legal_hold = ?, modified_by = ?, update_at = ?",
+			saved.RetentionDays, saved.LegalHold, saved.ModifiedBy, saved.UpdateAt)
+	} else {
+		query = query.Suffix(
+			`ON CONFLICT (team_id, board_id)
+			 DO UPDATE SET retention_days = EXCLUDED.retention_days, legal_hold = EXCLUDED.legal_hold, modified_by = EXCLUDED.modified_by, update_at = EXCLUDED.update_at`,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`saveRetentionPolicy ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// deleteRetentionPolicy deletes the policy of a team, if boardID is
+// empty, or of a board.
+func (s *SQLStore) deleteRetentionPolicy(db sq.BaseRunner, teamID, boardID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "retention_policies").
+		Where(sq.Eq{"team_id": teamID}).
+		Where(sq.Eq{"board_id": boardID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("retention policy teamID=" + teamID + " boardID=" + boardID)
+	}
+	return nil
+}
@@ -0,0 +1,573 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	// the snippets returned by the database wrap the matching words with
+	// these private use characters, which are replaced by the
+	// highlight tags once the rest of the snippet has been escaped.
+	searchSnippetStart = "\uE000"
+	searchSnippetEnd   = "\uE001"
+
+	// number of characters of context that surround the first match in
+	// the snippets built for databases without a full-text index.
+	searchSnippetContext = 60
+)
+
+var searchContentReplacer = strings.NewReplacer(searchSnippetStart, "", searchSnippetEnd, "")
+
+// sqliteFTS5Triggers keep the FTS5 table in sync with the block_search
+// table, which acts as its external content table.
+var sqliteFTS5Triggers = []struct {
+	name string
+	body string
+}{
+	{
+		name: "block_search_ai",
+		body: "AFTER INSERT ON {prefix}block_search BEGIN " +
+			"INSERT INTO {prefix}block_search_fts(rowid, content) VALUES (new.rowid, new.content); " +
+			"END",
+	},
+	{
+		name: "block_search_ad",
+		body: "AFTER DELETE ON {prefix}block_search BEGIN " +
+			"INSERT INTO {prefix}block_search_fts({prefix}block_search_fts, rowid, content) VALUES ('delete', old.rowid, old.content); " +
+			"END",
+	},
+	{
+		name: "block_search_au",
+		body: "AFTER UPDATE ON {prefix}block_search BEGIN " +
+			"INSERT INTO {prefix}block_search_fts({prefix}block_search_fts, rowid, content) VALUES ('delete', old.rowid, old.content); " +
+			"INSERT INTO {prefix}block_search_fts(rowid, content) VALUES (new.rowid, new.content); " +
+			"END",
+	},
+}
+
+// hasSQLiteFTS5 returns true if the SQLite library the server has been
+// built with includes the FTS5 extension.
+func (s *SQLStore) hasSQLiteFTS5() bool {
+	if s.dbType != model.SqliteDBType {
+		return false
+	}
+
+	var enabled bool
+	if err := s.db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		s.logger.Warn("Cannot check for SQLite FTS5 support", mlog.Err(err))
+		return false
+	}
+	return enabled
+}
+
+// setupSQLiteFullTextSearch creates the FTS5 table that indexes the
+// block_search table when the SQLite library supports it. Without FTS5
+// the triggers that feed the table are dropped, so block writes don't
+// fail, and searches fall back to pattern matching. The FTS5 table is
+// rebuilt when the triggers are recreated.
+func (s *SQLStore) setupSQLiteFullTextSearch() error {
+	if s.dbType != model.SqliteDBType {
+		return nil
+	}
+
+	if !s.sqliteFTS5 {
+		s.logger.Info("SQLite FTS5 extension not available, card search will use pattern matching")
+		for _, trigger := range sqliteFTS5Triggers {
+			if _, err := s.db.Exec("DROP TRIGGER IF EXISTS " + s.tablePrefix + trigger.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var count int
+	row := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = $1", s.tablePrefix+sqliteFTS5Triggers[0].name)
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	s.logger.Debug("Creating SQLite FTS5 card search index")
+
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %[1]sblock_search_fts USING fts5(content, content='%[1]sblock_search', content_rowid='rowid')", s.tablePrefix),
+	}
+	for _, trigger := range sqliteFTS5Triggers {
+		body := strings.ReplaceAll(trigger.body, "{prefix}", s.tablePrefix)
+		statements = append(statements, fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s%s %s", s.tablePrefix, trigger.name, body))
+	}
+	statements = append(statements, fmt.Sprintf("INSERT INTO %[1]sblock_search_fts(%[1]sblock_search_fts) VALUES ('rebuild')", s.tablePrefix))
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				s.logger.Error("SQLite FTS5 setup transaction rollback error", mlog.Err(rollbackErr))
+			}
+			return fmt.Errorf("cannot create SQLite FTS5 card search index: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RunBlockSearchIndexMigration indexes the cards and content blocks that
+// were created before the search index existed.
+func (s *SQLStore) RunBlockSearchIndexMigration() error {
+	setting, err := s.GetSystemSetting(BlockSearchIndexMigrationKey)
+	if err != nil {
+		return fmt.Errorf("cannot get migration state: %w", err)
+	}
+
+	// If the migration is already completed, do not run it again.
+	if hasAlreadyRun, _ := strconv.ParseBool(setting); hasAlreadyRun {
+		return nil
+	}
+
+	s.logger.Debug("Running block search index migration")
+
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+
+	if err := s.indexAllBlocksForSearch(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("block search index transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "indexAllBlocksForSearch"))
+		}
+		return fmt.Errorf("cannot index blocks for search: %w", err)
+	}
+
+	if err := s.setSystemSetting(tx, BlockSearchIndexMigrationKey, strconv.FormatBool(true)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("block search index transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "setSystemSetting"))
+		}
+		return fmt.Errorf("cannot mark migration as completed: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit block search index transaction: %w", err)
+	}
+
+	s.logger.Debug("block search index migration finished successfully")
+	return nil
+}
+
+func (s *SQLStore) indexAllBlocksForSearch(db sq.BaseRunner) error {
+	rows, err := s.getQueryBuilder(db).
+		Select("DISTINCT board_id").
+		From(s.tablePrefix + "blocks").
+		Where(sq.Eq{"type": model.TypeCard}).
+		Query()
+	if err != nil {
+		return err
+	}
+	boardIDs, err := idsFromRows(rows)
+	s.CloseRows(rows)
+	if err != nil {
+		return err
+	}
+
+	for _, boardID := range boardIDs {
+		blocks, err := s.getBlocksForBoard(db, boardID)
+		if err != nil {
+			return err
+		}
+		if err := s.indexBlocksForSearch(db, blocks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexBlocksForSearch updates the search index entries of the blocks.
+// Blocks that aren't searchable are skipped.
+func (s *SQLStore) indexBlocksForSearch(db sq.BaseRunner, blocks []*model.Block) error {
+	schemas := map[string]model.PropSchema{}
+	for _, block := range blocks {
+		if !model.IsSearchableBlockType(block.Type) {
+			continue
+		}
+
+		var schema model.PropSchema
+		if block.Type == model.TypeCard {
+			var ok bool
+			if schema, ok = schemas[block.BoardID]; !ok {
+				schema = s.getBoardPropertySchema(db, block.BoardID)
+				schemas[block.BoardID] = schema
+			}
+		}
+
+		if err := s.indexBlockForSearch(db, block, schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) indexBlockForSearch(db sq.BaseRunner, block *model.Block, schema model.PropSchema) error {
+	if err := s.removeBlocksFromSearch(db, sq.Eq{"block_id": block.ID}); err != nil {
+		return err
+	}
+
+	content := blockSearchContent(block, schema)
+	if block.DeleteAt != 0 || content == "" {
+		return nil
+	}
+
+	cardID := block.ParentID
+	if block.Type == model.TypeCard {
+		cardID = block.ID
+	}
+
+	values := map[string]interface{}{
+		"block_id":  block.ID,
+		"board_id":  block.BoardID,
+		"card_id":   cardID,
+		"type":      block.Type,
+		"content":   content,
+		"update_at": block.UpdateAt,
+	}
+	if s.dbType == model.PostgresDBType {
+		values["content_tsv"] = sq.Expr("to_tsvector('simple', ?)", content)
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix + "block_search").
+		SetMap(values)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("indexBlockForSearch ERROR", mlog.String("block_id", block.ID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) removeBlocksFromSearch(db sq.BaseRunner, condition sq.Sqlizer) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "block_search").
+		Where(condition)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("removeBlocksFromSearch ERROR", mlog.Err(err))
+		return err
+	}
+	return nil
+}
+
+// reindexBoardCardsForSearch updates the index entries of all the cards
+// of a board, as the indexed property values depend on the board schema.
+func (s *SQLStore) reindexBoardCardsForSearch(db sq.BaseRunner, boardID string) error {
+	cards, err := s.getBlocksWithType(db, boardID, model.TypeCard)
+	if err != nil {
+		return err
+	}
+	return s.indexBlocksForSearch(db, cards)
+}
+
+// getBoardPropertySchema returns the card property schema of a board,
+// or an empty schema if the board doesn't exist or can't be parsed, in
+// which case only the card titles are indexed.
+func (s *SQLStore) getBoardPropertySchema(db sq.BaseRunner, boardID string) model.PropSchema {
+	board, err := s.getBoard(db, boardID)
+	if err != nil {
+		if !model.IsErrNotFound(err) {
+			s.logger.Warn("Cannot get board for search index", mlog.String("board_id", boardID), mlog.Err(err))
+		}
+		return model.PropSchema{}
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		s.logger.Warn("Cannot parse board schema for search index", mlog.String("board_id", boardID), mlog.Err(err))
+		return model.PropSchema{}
+	}
+	return schema
+}
+
+// blockSearchContent returns the text of a block that gets indexed. For
+// cards this is the title and the values of the text like properties,
+// and for content blocks their text, along with the items of lists and
+// the headers and cells of tables.
+func blockSearchContent(block *model.Block, schema model.PropSchema) string {
+	if block.Type != model.TypeCard {
+		return searchContentReplacer.Replace(strings.Join(contentBlockSearchParts(block), "\n"))
+	}
+
+	parts := []string{strings.TrimSpace(block.Title)}
+
+	props, _ := block.Fields["properties"].(map[string]interface{})
+	defs := make([]model.PropDef, 0, len(schema))
+	for _, def := range schema {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].Index != defs[j].Index {
+			return defs[i].Index < defs[j].Index
+		}
+		return defs[i].ID < defs[j].ID
+	})
+
+	for _, def := range defs {
+		value, ok := props[def.ID]
+		if !ok {
+			continue
+		}
+
+		switch def.Type {
+		case "text", "number", "email", "phone", "url":
+			if str := strings.TrimSpace(fmt.Sprintf("%v", value)); str != "" {
+				parts = append(parts, str)
+			}
+		case "select":
+			if id, ok := value.(string); ok {
+				if option, ok := def.Options[id]; ok {
+					parts = append(parts, option.Value)
+				}
+			}
+		case "multiSelect":
+			ids, _ := value.([]interface{})
+			for _, id := range ids {
+				if idStr, ok := id.(string); ok {
+					if option, ok := def.Options[idStr]; ok {
+						parts = append(parts, option.Value)
+					}
+				}
+			}
+		}
+	}
+
+	return searchContentReplacer.Replace(strings.TrimSpace(strings.Join(parts, "\n")))
+}
+
+// contentBlockSearchParts returns the non empty texts of a content block.
+func contentBlockSearchParts(block *model.Block) []string {
+	texts := []string{block.Title}
+	switch block.Type {
+	case model.TypeBulletList, model.TypeNumberedList:
+		texts = append(texts, model.ListItems(block)...)
+	case model.TypeTable:
+		columns, rows := model.TableContent(block)
+		texts = append(texts, columns...)
+		for _, row := range rows {
+			texts = append(texts, row...)
+		}
+	}
+
+	parts := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return parts
+}
+
+// searchCards returns the cards and card content blocks of the given
+// boards that contain all the search terms, as a prefix of a word when a
+// full-text index is available and anywhere in the text otherwise.
+func (s *SQLStore) searchCards(db sq.BaseRunner, opts model.CardSearchOptions) ([]*model.CardSearchResult, error) {
+	if len(opts.Terms) == 0 || len(opts.BoardIDs) == 0 {
+		return []*model.CardSearchResult{}, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(
+			"bs.board_id",
+			"bs.card_id",
+			"bs.block_id",
+			"bs.type",
+			"c.title",
+		).
+		Join(s.tablePrefix + "blocks AS c ON c.id = bs.card_id").
+		Where(sq.Eq{"bs.board_id": opts.BoardIDs}).
+		Where(s.notTemplateCardCondition("c"))
+
+	useIndex := true
+	switch {
+	case s.dbType == model.PostgresDBType:
+		tsQuery := strings.Join(opts.Terms, ":* & ") + ":*"
+		options := fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=24, MinWords=8`, searchSnippetStart, searchSnippetEnd)
+		query = query.
+			Column(sq.Expr("ts_headline('simple', bs.content, to_tsquery('simple', ?), ?)", tsQuery, options)).
+			Column("bs.update_at").
+			From(s.tablePrefix+"block_search AS bs").
+			Where("bs.content_tsv @@ to_tsquery('simple', ?)", tsQuery).
+			OrderByClause("ts_rank(bs.content_tsv, to_tsquery('simple', ?)) DESC", tsQuery)
+	case s.dbType == model.SqliteDBType && s.sqliteFTS5:
+		// the FTS5 auxiliary functions don't accept a table alias
+		fts := s.tablePrefix + "block_search_fts"
+		ftsQuery := "\"" + strings.Join(opts.Terms, "\"* \"") + "\"*"
+		query = query.
+			Column(sq.Expr("snippet("+fts+", 0, ?, ?, '…', 16)", searchSnippetStart, searchSnippetEnd)).
+			Column("bs.update_at").
+			From(fts).
+			Join(s.tablePrefix+"block_search AS bs ON bs.rowid = "+fts+".rowid").
+			Where(fts+" MATCH ?", ftsQuery).
+			OrderBy(fts + ".rank")
+	default:
+		useIndex = false
+		conditions := sq.And{}
+		for _, term := range opts.Terms {
+			conditions = append(conditions, sq.Like{"lower(bs.content)": "%" + term + "%"})
+		}
+		query = query.
+			Column("bs.content").
+			Column("bs.update_at").
+			From(s.tablePrefix + "block_search AS bs").
+			Where(conditions)
+	}
+
+	query = query.OrderBy("bs.update_at DESC", "bs.block_id")
+
+	if opts.PerPage > 0 {
+		query = query.
+			Limit(uint64(opts.PerPage)).
+			Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`searchCards ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.cardSearchResultsFromRows(rows, opts.Terms, useIndex)
+}
+
+func (s *SQLStore) cardSearchResultsFromRows(rows *sql.Rows, terms []string, useIndex bool) ([]*model.CardSearchResult, error) {
+	results := []*model.CardSearchResult{}
+	for rows.Next() {
+		var result model.CardSearchResult
+		var snippet string
+
+		err := rows.Scan(
+			&result.BoardID,
+			&result.CardID,
+			&result.BlockID,
+			&result.BlockType,
+			&result.CardTitle,
+			&snippet,
+			&result.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("cardSearchResultsFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+
+		if !useIndex {
+			snippet = buildSearchSnippet(snippet, terms)
+		}
+		result.Snippet = formatSearchSnippet(snippet)
+
+		results = append(results, &result)
+	}
+	return results, nil
+}
+
+// notTemplateCardCondition filters out the cards that are templates,
+// which are flagged in the block fields.
+func (s *SQLStore) notTemplateCardCondition(alias string) string {
+	switch s.dbType {
+	case model.PostgresDBType:
+		return fmt.Sprintf("COALESCE((%s.fields->'isTemplate')::text::boolean, false) = false", alias)
+	case model.MysqlDBType:
+		return fmt.Sprintf("COALESCE(JSON_UNQUOTE(JSON_EXTRACT(%s.fields, '$.isTemplate')), 'false') != 'true'", alias)
+	default:
+		return fmt.Sprintf("COALESCE(JSON_EXTRACT(%s.fields, '$.isTemplate'), 0) = 0", alias)
+	}
+}
+
+// buildSearchSnippet extracts the text around the first match of the
+// terms and marks all the matches in it, as the full-text indexes do.
+func buildSearchSnippet(content string, terms []string) string {
+	text := []rune(content)
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+	runeTerms := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		runeTerms = append(runeTerms, []rune(term))
+	}
+
+	matchAt := func(pos int) int {
+		longest := 0
+		for _, term := range runeTerms {
+			if len(term) <= longest || pos+len(term) > len(lower) {
+				continue
+			}
+			if string(lower[pos:pos+len(term)]) == string(term) {
+				longest = len(term)
+			}
+		}
+		return longest
+	}
+
+	first := -1
+	for i := range lower {
+		if matchAt(i) > 0 {
+			first = i
+			break
+		}
+	}
+
+	start := 0
+	if first > searchSnippetContext {
+		start = first - searchSnippetContext
+	}
+	end := len(text)
+	if first >= 0 && first+2*searchSnippetContext < end {
+		end = first + 2*searchSnippetContext
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		if n := matchAt(i); n > 0 && i+n <= end {
+			sb.WriteString(searchSnippetStart)
+			sb.WriteString(string(text[i : i+n]))
+			sb.WriteString(searchSnippetEnd)
+			i += n
+			continue
+		}
+		sb.WriteRune(text[i])
+		i++
+	}
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// formatSearchSnippet escapes the snippet text, collapses its white
+// space and replaces the match markers with the highlight tags.
+func formatSearchSnippet(snippet string) string {
+	snippet = html.EscapeString(strings.Join(strings.Fields(snippet), " "))
+	return strings.NewReplacer(
+		searchSnippetStart, model.CardSearchHighlightStart,
+		searchSnippetEnd, model.CardSearchHighlightEnd,
+	).Replace(snippet)
+}
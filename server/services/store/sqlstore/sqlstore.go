@@ -30,6 +30,7 @@ type SQLStore struct {
 	isBinaryParam    bool
 	schemaName       string
 	configFn         func() *mmModel.Config
+	sqliteFTS5       bool
 }
 
 // MutexFactory is used by the store in plugin mode to generate
@@ -66,6 +67,8 @@ func New(params Params) (*SQLStore, error) {
 		return nil, err
 	}
 
+	store.sqliteFTS5 = store.hasSQLiteFTS5()
+
 	if !params.SkipMigrations {
 		if mErr := store.Migrate(); mErr != nil {
 			params.Logger.Error(`Table creation / migration failed`, mlog.Err(mErr))
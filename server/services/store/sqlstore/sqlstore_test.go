@@ -9,11 +9,13 @@ import (
 	"github.com/mattermost/focalboard/server/services/store/storetests"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
 	"github.com/stretchr/testify/require"
 )
 
 func TestSQLStore(t *testing.T) {
 	t.Run("BlocksStore", func(t *testing.T) { storetests.StoreTestBlocksStore(t, SetupTests) })
+	t.Run("CardsStore", func(t *testing.T) { storetests.StoreTestCardsStore(t, SetupTests) })
 	t.Run("SharingStore", func(t *testing.T) { storetests.StoreTestSharingStore(t, SetupTests) })
 	t.Run("SystemStore", func(t *testing.T) { storetests.StoreTestSystemStore(t, SetupTests) })
 	t.Run("UserStore", func(t *testing.T) { storetests.StoreTestUserStore(t, SetupTests) })
@@ -29,6 +31,13 @@ func TestSQLStore(t *testing.T) {
 	t.Run("StoreTestCategoryStore", func(t *testing.T) { storetests.StoreTestCategoryStore(t, SetupTests) })
 	t.Run("StoreTestCategoryBoardsStore", func(t *testing.T) { storetests.StoreTestCategoryBoardsStore(t, SetupTests) })
 	t.Run("ComplianceHistoryStore", func(t *testing.T) { storetests.StoreTestComplianceHistoryStore(t, SetupTests) })
+	t.Run("TrashStore", func(t *testing.T) { storetests.StoreTestTrashStore(t, SetupTests) })
+	t.Run("CardDependencyStore", func(t *testing.T) { storetests.StoreTestCardDependencyStore(t, SetupTests) })
+	t.Run("RecurringCardRuleStore", func(t *testing.T) { storetests.StoreTestRecurringCardRuleStore(t, SetupTests) })
+	t.Run("DueDateReminderStore", func(t *testing.T) { storetests.StoreTestDueDateReminderStore(t, SetupTests) })
+	t.Run("WebhookStore", func(t *testing.T) { storetests.StoreTestWebhookStore(t, SetupTests) })
+	t.Run("IncomingWebhookStore", func(t *testing.T) { storetests.StoreTestIncomingWebhookStore(t, SetupTests) })
+	t.Run("AutomationStore", func(t *testing.T) { storetests.StoreTestAutomationStore(t, SetupTests) })
 }
 
 //  tests for  utility functions inside sqlstore.go
@@ -63,3 +72,60 @@ func TestElementInColumn(t *testing.T) {
 		require.Equal(t, inLiteral, "position(? in test_column) > 0")
 	}
 }
+
+func TestQueryCardsMaxInMemory(t *testing.T) {
+	store, tearDown := SetupTests(t)
+	sqlStore := store.(*SQLStore)
+	defer tearDown()
+
+	defer func(limit int) { queryCardsMaxInMemory = limit }(queryCardsMaxInMemory)
+	queryCardsMaxInMemory = 2
+
+	board := &model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: "team-id",
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{"id": "estimate", "name": "Estimate", "type": "number"},
+		},
+	}
+	_, err := sqlStore.InsertBoard(board, "user-id")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		card := &model.Card{
+			ID:         utils.NewID(utils.IDTypeCard),
+			BoardID:    board.ID,
+			Title:      "card",
+			Properties: map[string]any{"estimate": "1"},
+		}
+		card.Populate()
+		require.NoError(t, sqlStore.InsertBlock(model.Card2Block(card), "user-id"))
+	}
+
+	// the database evaluates the queries on titles without limit
+	result, err := sqlStore.QueryCards(board.ID, model.QueryCardsOptions{
+		SortOptions: []model.SortOption{{PropertyID: model.CardQuerySortTitlePropertyID}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Total)
+
+	_, err = sqlStore.QueryCards(board.ID, model.QueryCardsOptions{
+		SortOptions: []model.SortOption{{PropertyID: "estimate"}},
+	})
+	require.True(t, model.IsErrBadRequest(err))
+
+	// the filters evaluated by the database reduce the cards to load
+	result, err = sqlStore.QueryCards(board.ID, model.QueryCardsOptions{
+		Filter: &model.FilterGroup{
+			Operation: model.FilterGroupOperationAnd,
+			Filters: []model.FilterGroupItem{
+				{Clause: &model.FilterClause{PropertyID: "title", Condition: model.FilterConditionIs, Values: []string{"other"}}},
+				{Clause: &model.FilterClause{PropertyID: "estimate", Condition: model.FilterConditionIsNotEmpty}},
+			},
+		},
+		SortOptions: []model.SortOption{{PropertyID: "estimate"}},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Total)
+}
@@ -43,6 +43,10 @@ func (s *SQLStore) removeDefaultTemplates(db sq.BaseRunner, boards []*model.Boar
 			return fmt.Errorf("cannot delete default template %s: %w", board.ID, err)
 		}
 
+		if err := s.removeBlocksFromSearch(db, sq.Eq{"board_id": board.ID}); err != nil {
+			return fmt.Errorf("cannot delete default template %s: %w", board.ID, err)
+		}
+
 		s.logger.Trace("removed default template block",
 			mlog.String("board_id", board.ID),
 		)
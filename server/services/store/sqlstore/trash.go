@@ -0,0 +1,246 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// getDeletedBoardsForTeam returns the newest revision of the boards of a
+// team that are deleted, most recently deleted first. The revision
+// contains the user that deleted the board and the deletion time.
+func (s *SQLStore) getDeletedBoardsForTeam(db sq.BaseRunner, teamID string) ([]*model.Board, error) {
+	// as we're joining 2 queries, we need to avoid numbered
+	// placeholders until the join is done, so we use the default
+	// question mark placeholder here
+	builder := s.getQueryBuilder(db).PlaceholderFormat(sq.Question)
+
+	sub := builder.
+		Select("bh2.id", "MAX(bh2.insert_at) AS max_insert_at").
+		From(s.tablePrefix + "boards_history AS bh2").
+		Where(sq.Eq{"bh2.team_id": teamID}).
+		GroupBy("bh2.id")
+
+	subQuery, subArgs, err := sub.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getDeletedBoardsForTeam unable to generate subquery: %w", err)
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(boardFields("bh")...).
+		From(s.tablePrefix+"boards_history AS bh").
+		InnerJoin("("+subQuery+") AS sub ON bh.id=sub.id AND bh.insert_at=sub.max_insert_at", subArgs...).
+		LeftJoin(s.tablePrefix+"boards AS b ON b.id=bh.id").
+		Where(sq.Eq{"b.id": nil}).
+		Where(sq.Gt{"bh.delete_at": 0}).
+		OrderBy("bh.delete_at DESC", "bh.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getDeletedBoardsForTeam unable to generate sql: %w", err)
+	}
+
+	// if we're using postgres or sqlite, we need to replace the
+	// question mark placeholder with the numbered dollar one, now
+	// that the full query is built
+	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
+		var rErr error
+		sql, rErr = sq.Dollar.ReplacePlaceholders(sql)
+		if rErr != nil {
+			return nil, fmt.Errorf("getDeletedBoardsForTeam unable to replace sql placeholders: %w", rErr)
+		}
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		s.logger.Error(`getDeletedBoardsForTeam ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	boards, err := s.boardsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// two revisions of a board can share the same insert time, so we
+	// keep only the first one
+	seen := map[string]bool{}
+	deletedBoards := make([]*model.Board, 0, len(boards))
+	for _, board := range boards {
+		if seen[board.ID] {
+			continue
+		}
+		seen[board.ID] = true
+		deletedBoards = append(deletedBoards, board)
+	}
+	return deletedBoards, nil
+}
+
+// getDeletedBlocksForBoard returns the newest revision of the blocks of a
+// board that are deleted, most recently deleted first. If blockTypes is
+// not empty, only the blocks of those types are returned.
+func (s *SQLStore) getDeletedBlocksForBoard(db sq.BaseRunner, boardID string, blockTypes []model.BlockType) ([]*model.Block, error) {
+	// as we're joining 2 queries, we need to avoid numbered
+	// placeholders until the join is done, so we use the default
+	// question mark placeholder here
+	builder := s.getQueryBuilder(db).PlaceholderFormat(sq.Question)
+
+	sub := builder.
+		Select("bh2.id", "MAX(bh2.insert_at) AS max_insert_at").
+		From(s.tablePrefix + "blocks_history AS bh2").
+		Where(sq.Eq{"bh2.board_id": boardID}).
+		GroupBy("bh2.id")
+
+	if len(blockTypes) > 0 {
+		sub = sub.Where(sq.Eq{"bh2.type": blockTypes})
+	}
+
+	subQuery, subArgs, err := sub.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getDeletedBlocksForBoard unable to generate subquery: %w", err)
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(s.blockFields("bh")...).
+		From(s.tablePrefix+"blocks_history AS bh").
+		InnerJoin("("+subQuery+") AS sub ON bh.id=sub.id AND bh.insert_at=sub.max_insert_at", subArgs...).
+		LeftJoin(s.tablePrefix+"blocks AS b ON b.id=bh.id").
+		Where(sq.Eq{"b.id": nil}).
+		Where(sq.Gt{"bh.delete_at": 0}).
+		OrderBy("bh.delete_at DESC", "bh.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("getDeletedBlocksForBoard unable to generate sql: %w", err)
+	}
+
+	if s.dbType == model.PostgresDBType || s.dbType == model.SqliteDBType {
+		var rErr error
+		sql, rErr = sq.Dollar.ReplacePlaceholders(sql)
+		if rErr != nil {
+			return nil, fmt.Errorf("getDeletedBlocksForBoard unable to replace sql placeholders: %w", rErr)
+		}
+	}
+
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		s.logger.Error(`getDeletedBlocksForBoard ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	blocks, err := s.blocksFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// two revisions of a block can share the same insert time, so we
+	// keep only the first one
+	seen := map[string]bool{}
+	deletedBlocks := make([]*model.Block, 0, len(blocks))
+	for _, block := range blocks {
+		if seen[block.ID] {
+			continue
+		}
+		seen[block.ID] = true
+		deletedBlocks = append(deletedBlocks, block)
+	}
+	return deletedBlocks, nil
+}
+
+// purgeBoard permanently removes a deleted board, its blocks, their
+// history, its members and every other row that references it. Live
+// boards can't be purged.
+func (s *SQLStore) purgeBoard(db sq.BaseRunner, boardID string) error {
+	if _, err := s.getBoard(db, boardID); err == nil {
+		return model.NewErrBadRequest("board ID=" + boardID + " is not deleted")
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	for _, table := range boardDataTables {
+		query := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + table.Table).
+			Where(sq.Eq{table.BoardIDColumn: boardID})
+
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error("purgeBoard error",
+				mlog.String("table", table.Table),
+				mlog.String("board_id", boardID),
+				mlog.Err(err),
+			)
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeBlock permanently removes a deleted block and its deleted children,
+// including their history. Live blocks can't be purged.
+func (s *SQLStore) purgeBlock(db sq.BaseRunner, blockID string) error {
+	if _, err := s.getBlock(db, blockID); err == nil {
+		return model.NewErrBadRequest("block ID=" + blockID + " is not deleted")
+	} else if !model.IsErrNotFound(err) {
+		return err
+	}
+
+	childrenQuery := s.getQueryBuilder(db).
+		Select("DISTINCT bh.id").
+		From(s.tablePrefix + "blocks_history AS bh").
+		LeftJoin(s.tablePrefix + "blocks AS b ON b.id=bh.id").
+		Where(sq.Eq{"bh.parent_id": blockID}).
+		Where(sq.Eq{"b.id": nil})
+
+	rows, err := childrenQuery.Query()
+	if err != nil {
+		s.logger.Error(`purgeBlock children ERROR`, mlog.Err(err))
+		return err
+	}
+	defer s.CloseRows(rows)
+
+	blockIDs, err := idsFromRows(rows)
+	if err != nil {
+		return err
+	}
+	blockIDs = append(blockIDs, blockID)
+
+	deleteHistoryQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "blocks_history").
+		Where(sq.Eq{"id": blockIDs})
+
+	if _, err := deleteHistoryQuery.Exec(); err != nil {
+		return err
+	}
+
+	deleteSearchQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "block_search").
+		Where(sq.Eq{"block_id": blockIDs})
+
+	if _, err := deleteSearchQuery.Exec(); err != nil {
+		return err
+	}
+
+	deleteDependenciesQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_dependencies").
+		Where(sq.Or{sq.Eq{"blocker_id": blockIDs}, sq.Eq{"blocked_id": blockIDs}})
+
+	if _, err := deleteDependenciesQuery.Exec(); err != nil {
+		return err
+	}
+
+	deleteRemindersQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "due_date_reminders").
+		Where(sq.Eq{"card_id": blockIDs})
+
+	if _, err := deleteRemindersQuery.Exec(); err != nil {
+		return err
+	}
+	return nil
+}
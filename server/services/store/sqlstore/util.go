@@ -139,6 +139,16 @@ func (s *SQLStore) castInt(val int64, as string) string {
 	return fmt.Sprintf("cast(%d as bigint) AS %s", val, as)
 }
 
+// forUpdateClause returns the suffix that locks the selected rows until
+// the end of the transaction. SQLite locks the whole database on write
+// transactions and doesn't support it.
+func (s *SQLStore) forUpdateClause() string {
+	if s.dbType == model.SqliteDBType {
+		return ""
+	}
+	return "FOR UPDATE"
+}
+
 func (s *SQLStore) GetSchemaName() (string, error) {
 	var query sq.SelectBuilder
 
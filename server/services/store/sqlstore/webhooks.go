@@ -0,0 +1,464 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+func webhookFields() []string {
+	return []string{
+		"id",
+		"team_id",
+		"board_id",
+		"url",
+		"secret",
+		"event_types",
+		"disabled",
+		"created_by",
+		"modified_by",
+		"create_at",
+		"update_at",
+	}
+}
+
+func webhookDeliveryFields() []string {
+	return []string{
+		"id",
+		"webhook_id",
+		"board_id",
+		"event_type",
+		"payload",
+		"status",
+		"attempts",
+		"next_attempt_at",
+		"last_attempt_at",
+		"response_code",
+		"response_body",
+		"last_error",
+		"redelivery_of",
+		"create_at",
+		"update_at",
+	}
+}
+
+func (s *SQLStore) webhooksFromRows(rows *sql.Rows) ([]*model.Webhook, error) {
+	webhooks := []*model.Webhook{}
+	for rows.Next() {
+		var webhook model.Webhook
+		var eventTypesJSON string
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.TeamID,
+			&webhook.BoardID,
+			&webhook.URL,
+			&webhook.Secret,
+			&eventTypesJSON,
+			&webhook.Disabled,
+			&webhook.CreatedBy,
+			&webhook.ModifiedBy,
+			&webhook.CreateAt,
+			&webhook.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("webhooksFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(eventTypesJSON), &webhook.EventTypes); err != nil {
+			s.logger.Error("webhooksFromRows event types error", mlog.Err(err))
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	return webhooks, nil
+}
+
+func (s *SQLStore) webhookDeliveriesFromRows(rows *sql.Rows) ([]*model.WebhookDelivery, error) {
+	deliveries := []*model.WebhookDelivery{}
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		var payload string
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.BoardID,
+			&delivery.Event,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastAttemptAt,
+			&delivery.ResponseCode,
+			&delivery.ResponseBody,
+			&delivery.Error,
+			&delivery.RedeliveryOf,
+			&delivery.CreateAt,
+			&delivery.UpdateAt,
+		)
+		if err != nil {
+			s.logger.Error("webhookDeliveriesFromRows scan error", mlog.Err(err))
+			return nil, err
+		}
+		delivery.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
+
+func (s *SQLStore) getWebhooks(db sq.BaseRunner, condition interface{}) ([]*model.Webhook, error) {
+	query := s.getQueryBuilder(db).
+		Select(webhookFields()...).
+		From(s.tablePrefix+"webhooks").
+		Where(condition).
+		OrderBy("create_at", "id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getWebhooks ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.webhooksFromRows(rows)
+}
+
+func (s *SQLStore) getWebhook(db sq.BaseRunner, webhookID string) (*model.Webhook, error) {
+	webhooks, err := s.getWebhooks(db, sq.Eq{"id": webhookID})
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, model.NewErrNotFound("webhook ID=" + webhookID)
+	}
+	return webhooks[0], nil
+}
+
+func (s *SQLStore) getWebhooksForBoard(db sq.BaseRunner, boardID string) ([]*model.Webhook, error) {
+	return s.getWebhooks(db, sq.Eq{"board_id": boardID})
+}
+
+// getWebhooksForTeam returns the webhooks of a team that aren't limited to
+// a board.
+func (s *SQLStore) getWebhooksForTeam(db sq.BaseRunner, teamID string) ([]*model.Webhook, error) {
+	return s.getWebhooks(db, sq.Eq{"team_id": teamID, "board_id": ""})
+}
+
+// getActiveWebhooks returns the enabled webhooks that receive the events
+// of a board: its own and the ones of its team.
+func (s *SQLStore) getActiveWebhooks(db sq.BaseRunner, teamID, boardID string) ([]*model.Webhook, error) {
+	return s.getWebhooks(db, sq.And{
+		sq.Eq{"disabled": false},
+		sq.Or{
+			sq.Eq{"board_id": boardID},
+			sq.Eq{"team_id": teamID, "board_id": ""},
+		},
+	})
+}
+
+func (s *SQLStore) insertWebhook(db sq.BaseRunner, webhook *model.Webhook) (*model.Webhook, error) {
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	inserted := *webhook
+	if inserted.ID == "" {
+		inserted.ID = utils.NewID(utils.IDTypeNone)
+	}
+	if inserted.EventTypes == nil {
+		inserted.EventTypes = []string{}
+	}
+	inserted.CreateAt = utils.GetMillis()
+	inserted.UpdateAt = inserted.CreateAt
+
+	eventTypesJSON, err := json.Marshal(inserted.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"webhooks").
+		Columns(webhookFields()...).
+		Values(
+			inserted.ID,
+			inserted.TeamID,
+			inserted.BoardID,
+			inserted.URL,
+			inserted.Secret,
+			eventTypesJSON,
+			inserted.Disabled,
+			inserted.CreatedBy,
+			inserted.ModifiedBy,
+			inserted.CreateAt,
+			inserted.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertWebhook ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+// updateWebhook saves the URL, events and disabled state of a webhook.
+func (s *SQLStore) updateWebhook(db sq.BaseRunner, webhook *model.Webhook) (*model.Webhook, error) {
+	if err := webhook.IsValid(); err != nil {
+		return nil, err
+	}
+
+	updated := *webhook
+	if updated.EventTypes == nil {
+		updated.EventTypes = []string{}
+	}
+	updated.UpdateAt = utils.GetMillis()
+
+	eventTypesJSON, err := json.Marshal(updated.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"webhooks").
+		Set("url", updated.URL).
+		Set("event_types", eventTypesJSON).
+		Set("disabled", updated.Disabled).
+		Set("modified_by", updated.ModifiedBy).
+		Set("update_at", updated.UpdateAt).
+		Where(sq.Eq{"id": updated.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`updateWebhook ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, model.NewErrNotFound("webhook ID=" + updated.ID)
+	}
+	return &updated, nil
+}
+
+// deleteWebhook deletes a webhook and its deliveries.
+func (s *SQLStore) deleteWebhook(db sq.BaseRunner, webhookID string) error {
+	deleteDeliveriesQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "webhook_deliveries").
+		Where(sq.Eq{"webhook_id": webhookID})
+
+	if _, err := deleteDeliveriesQuery.Exec(); err != nil {
+		s.logger.Error(`deleteWebhook deliveries ERROR`, mlog.Err(err))
+		return err
+	}
+
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "webhooks").
+		Where(sq.Eq{"id": webhookID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("webhook ID=" + webhookID)
+	}
+	return nil
+}
+
+func (s *SQLStore) insertWebhookDelivery(db sq.BaseRunner, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	inserted := *delivery
+	if inserted.ID == "" {
+		inserted.ID = utils.NewID(utils.IDTypeNone)
+	}
+	if inserted.Status == "" {
+		inserted.Status = model.WebhookDeliveryPending
+	}
+	inserted.CreateAt = utils.GetMillis()
+	inserted.UpdateAt = inserted.CreateAt
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"webhook_deliveries").
+		Columns(webhookDeliveryFields()...).
+		Values(
+			inserted.ID,
+			inserted.WebhookID,
+			inserted.BoardID,
+			inserted.Event,
+			string(inserted.Payload),
+			inserted.Status,
+			inserted.Attempts,
+			inserted.NextAttemptAt,
+			inserted.LastAttemptAt,
+			inserted.ResponseCode,
+			inserted.ResponseBody,
+			inserted.Error,
+			inserted.RedeliveryOf,
+			inserted.CreateAt,
+			inserted.UpdateAt,
+		)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error(`insertWebhookDelivery ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	return &inserted, nil
+}
+
+func (s *SQLStore) getWebhookDelivery(db sq.BaseRunner, deliveryID string) (*model.WebhookDelivery, error) {
+	query := s.getQueryBuilder(db).
+		Select(webhookDeliveryFields()...).
+		From(s.tablePrefix + "webhook_deliveries").
+		Where(sq.Eq{"id": deliveryID})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getWebhookDelivery ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	deliveries, err := s.webhookDeliveriesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, model.NewErrNotFound("webhook delivery ID=" + deliveryID)
+	}
+	return deliveries[0], nil
+}
+
+// getWebhookDeliveries returns a page of the deliveries of a webhook,
+// newest first.
+func (s *SQLStore) getWebhookDeliveries(db sq.BaseRunner, opts model.QueryWebhookDeliveriesOptions) ([]*model.WebhookDelivery, error) {
+	query := s.getQueryBuilder(db).
+		Select(webhookDeliveryFields()...).
+		From(s.tablePrefix+"webhook_deliveries").
+		Where(sq.Eq{"webhook_id": opts.WebhookID}).
+		OrderBy("create_at DESC", "id DESC")
+
+	if opts.Status != "" {
+		query = query.Where(sq.Eq{"status": opts.Status})
+	}
+
+	if opts.Page != 0 {
+		query = query.Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		query = query.Limit(uint64(opts.PerPage))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getWebhookDeliveries ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.webhookDeliveriesFromRows(rows)
+}
+
+// getDueWebhookDeliveries returns the pending deliveries whose next
+// attempt is due, oldest first.
+func (s *SQLStore) getDueWebhookDeliveries(db sq.BaseRunner, now int64, limit int) ([]*model.WebhookDelivery, error) {
+	query := s.getQueryBuilder(db).
+		Select(webhookDeliveryFields()...).
+		From(s.tablePrefix+"webhook_deliveries").
+		Where(sq.Eq{"status": model.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at", "id")
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getDueWebhookDeliveries ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.webhookDeliveriesFromRows(rows)
+}
+
+// claimWebhookDelivery moves the next attempt of a pending delivery from
+// nextAttemptAt to newNextAttemptAt, and returns false if the delivery has
+// been attempted or claimed by another server in the meantime.
+func (s *SQLStore) claimWebhookDelivery(db sq.BaseRunner, deliveryID string, nextAttemptAt, newNextAttemptAt int64) (bool, error) {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"webhook_deliveries").
+		Set("next_attempt_at", newNextAttemptAt).
+		Where(sq.Eq{"id": deliveryID}).
+		Where(sq.Eq{"status": model.WebhookDeliveryPending}).
+		Where(sq.Eq{"next_attempt_at": nextAttemptAt})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`claimWebhookDelivery ERROR`, mlog.Err(err))
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// updateWebhookDelivery saves the status and the last attempt of a
+// delivery.
+func (s *SQLStore) updateWebhookDelivery(db sq.BaseRunner, delivery *model.WebhookDelivery) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("last_attempt_at", delivery.LastAttemptAt).
+		Set("response_code", delivery.ResponseCode).
+		Set("response_body", delivery.ResponseBody).
+		Set("last_error", delivery.Error).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": delivery.ID})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`updateWebhookDelivery ERROR`, mlog.Err(err))
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return model.NewErrNotFound("webhook delivery ID=" + delivery.ID)
+	}
+	return nil
+}
+
+// deleteWebhookDeliveriesBefore deletes the deliveries created before a
+// time in milliseconds since the epoch that are no longer pending, and
+// returns the number of deliveries deleted.
+func (s *SQLStore) deleteWebhookDeliveriesBefore(db sq.BaseRunner, createAt int64) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "webhook_deliveries").
+		Where(sq.Lt{"create_at": createAt}).
+		Where(sq.NotEq{"status": model.WebhookDeliveryPending})
+
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error(`deleteWebhookDeliveriesBefore ERROR`, mlog.Err(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
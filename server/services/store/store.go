@@ -21,24 +21,42 @@ type Store interface {
 	GetBlocksWithType(boardID, blockType string) ([]*model.Block, error)
 	GetSubTree2(boardID, blockID string, opts model.QuerySubtreeOptions) ([]*model.Block, error)
 	GetBlocksForBoard(boardID string) ([]*model.Block, error)
+	QueryCards(boardID string, opts model.QueryCardsOptions) (*model.CardQueryResult, error)
+	SearchCards(opts model.CardSearchOptions) ([]*model.CardSearchResult, error)
+	GetChecklistProgress(cardIDs []string) (map[string]model.ChecklistProgress, error)
 	// @withTransaction
+	// @invalidate block(block.ID)
 	InsertBlock(block *model.Block, userID string) error
 	// @withTransaction
+	// @invalidate block
 	DeleteBlock(blockID string, modifiedBy string) error
 	// @withTransaction
+	// @invalidate block
 	InsertBlocks(blocks []*model.Block, userID string) error
 	// @withTransaction
+	// @invalidate block
 	UndeleteBlock(blockID string, modifiedBy string) error
 	// @withTransaction
+	// @invalidate board(boardID) member(boardID) block
 	UndeleteBoard(boardID string, modifiedBy string) error
+	GetDeletedBoardsForTeam(teamID string) ([]*model.Board, error)
+	GetDeletedBlocksForBoard(boardID string, blockTypes []model.BlockType) ([]*model.Block, error)
+	// @withTransaction
+	// @invalidate member(boardID)
+	PurgeBoard(boardID string) error
+	// @withTransaction
+	PurgeBlock(blockID string) error
 	GetBlockCountsByType() (map[string]int64, error)
 	GetBoardCount() (int64, error)
+	// @cache block
 	GetBlock(blockID string) (*model.Block, error)
 	// @withTransaction
+	// @invalidate block(blockID)
 	PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error
 	GetBlockHistory(blockID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryDescendants(boardID string, opts model.QueryBlockHistoryOptions) ([]*model.Block, error)
 	GetBlockHistoryNewestChildren(parentID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error)
+	GetBlockHistoryNewestDescendants(boardID string, opts model.QueryBlockHistoryChildOptions) ([]*model.Block, bool, error)
 	GetBoardHistory(boardID string, opts model.QueryBoardHistoryOptions) ([]*model.Board, error)
 	GetBoardAndCardByID(blockID string) (board *model.Board, card *model.Block, err error)
 	GetBoardAndCard(block *model.Block) (board *model.Board, card *model.Block, err error)
@@ -47,6 +65,7 @@ type Store interface {
 	// @withTransaction
 	DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error)
 	// @withTransaction
+	// @invalidate block
 	PatchBlocks(blockPatches *model.BlockPatchBatch, userID string) error
 
 	Shutdown() error
@@ -88,21 +107,30 @@ type Store interface {
 	GetAllTeams() ([]*model.Team, error)
 	GetTeamCount() (int64, error)
 
+	// @invalidate board(board.ID) member(board.ID)
 	InsertBoard(board *model.Board, userID string) (*model.Board, error)
 	// @withTransaction
+	// @invalidate board(board.ID) member(board.ID)
 	InsertBoardWithAdmin(board *model.Board, userID string) (*model.Board, *model.BoardMember, error)
 	// @withTransaction
+	// @invalidate board(boardID) member(boardID)
 	PatchBoard(boardID string, boardPatch *model.BoardPatch, userID string) (*model.Board, error)
+	// @cache board
 	GetBoard(id string) (*model.Board, error)
 	GetBoardsForUserAndTeam(userID, teamID string, includePublicBoards bool) ([]*model.Board, error)
 	GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error)
 	// @withTransaction
+	// @invalidate board(boardID) member(boardID) block
 	DeleteBoard(boardID, userID string) error
 
+	// @invalidate member(bm.BoardID)
 	SaveMember(bm *model.BoardMember) (*model.BoardMember, error)
+	// @invalidate member(boardID)
 	DeleteMember(boardID, userID string) error
+	// @cache member
 	GetMemberForBoard(boardID, userID string) (*model.BoardMember, error)
 	GetBoardMemberHistory(boardID, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error)
+	// @cache member
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
 	GetMembersForUser(userID string) ([]*model.BoardMember, error)
 	CanSeeUser(seerID string, seenID string) (bool, error)
@@ -110,13 +138,20 @@ type Store interface {
 	SearchBoardsForUserInTeam(teamID, term, userID string) ([]*model.Board, error)
 
 	// @withTransaction
+	// @invalidate board member block
 	CreateBoardsAndBlocksWithAdmin(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, []*model.BoardMember, error)
 	// @withTransaction
+	// @invalidate board member block
 	CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
+	// @invalidate board member block
 	PatchBoardsAndBlocks(pbab *model.PatchBoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error)
 	// @withTransaction
+	// @invalidate board member block
 	DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID string) error
+	// @withTransaction
+	// @invalidate board member block
+	RestoreBoardAndBlocks(board *model.Board, blocks []*model.Block, deletedBlockIDs []string, userID string) error
 
 	GetCategory(id string) (*model.Category, error)
 
@@ -164,11 +199,71 @@ type Store interface {
 	GetNotificationHint(blockID string) (*model.NotificationHint, error)
 	GetNextNotificationHint(remove bool) (*model.NotificationHint, error)
 
+	// @invalidate board member block
 	RemoveDefaultTemplates(boards []*model.Board) error
 	GetTemplateBoards(teamID, userID string) ([]*model.Board, error)
 
 	// @withTransaction
-	RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error)
+	// @invalidate board member block
+	RunDataRetention(globalRetentionDate int64, batchSize int64) (*model.DataRetentionReport, error)
+
+	GetRetentionPolicies(teamID string) ([]*model.RetentionPolicy, error)
+	SaveRetentionPolicy(policy *model.RetentionPolicy) (*model.RetentionPolicy, error)
+	DeleteRetentionPolicy(teamID, boardID string) error
+
+	GetCardDependencies(opts model.QueryCardDependenciesOptions) ([]*model.CardDependency, error)
+	InsertCardDependency(dependency *model.CardDependency) (*model.CardDependency, error)
+	DeleteCardDependency(blockerID, blockedID string) error
+
+	GetRecurringCardRulesForBoard(boardID string) ([]*model.RecurringCardRule, error)
+	GetRecurringCardRule(ruleID string) (*model.RecurringCardRule, error)
+	GetDueRecurringCardRules(now int64, limit int) ([]*model.RecurringCardRule, error)
+	InsertRecurringCardRule(rule *model.RecurringCardRule) (*model.RecurringCardRule, error)
+	UpdateRecurringCardRule(rule *model.RecurringCardRule) (*model.RecurringCardRule, error)
+	ClaimRecurringCardRuleRun(ruleID string, nextRunAt, newNextRunAt int64) (bool, error)
+	UpdateRecurringCardRuleLastRun(ruleID string, lastRunAt int64, lastCardID, lastError string) error
+	DeleteRecurringCardRule(ruleID string) error
+
+	GetDueDateReminderSettings(boardID string) (*model.DueDateReminderSettings, error)
+	GetEnabledDueDateReminderSettings() ([]*model.DueDateReminderSettings, error)
+	SaveDueDateReminderSettings(settings *model.DueDateReminderSettings) (*model.DueDateReminderSettings, error)
+	DeleteDueDateReminderSettings(boardID string) error
+	InsertDueDateReminder(reminder *model.DueDateReminder) (bool, error)
+
+	GetWebhook(webhookID string) (*model.Webhook, error)
+	GetWebhooksForBoard(boardID string) ([]*model.Webhook, error)
+	GetWebhooksForTeam(teamID string) ([]*model.Webhook, error)
+	GetActiveWebhooks(teamID, boardID string) ([]*model.Webhook, error)
+	InsertWebhook(webhook *model.Webhook) (*model.Webhook, error)
+	UpdateWebhook(webhook *model.Webhook) (*model.Webhook, error)
+	// @withTransaction
+	DeleteWebhook(webhookID string) error
+	InsertWebhookDelivery(delivery *model.WebhookDelivery) (*model.WebhookDelivery, error)
+	GetWebhookDelivery(deliveryID string) (*model.WebhookDelivery, error)
+	GetWebhookDeliveries(opts model.QueryWebhookDeliveriesOptions) ([]*model.WebhookDelivery, error)
+	GetDueWebhookDeliveries(now int64, limit int) ([]*model.WebhookDelivery, error)
+	ClaimWebhookDelivery(deliveryID string, nextAttemptAt, newNextAttemptAt int64) (bool, error)
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	DeleteWebhookDeliveriesBefore(createAt int64) (int64, error)
+
+	GetIncomingWebhook(webhookID string) (*model.IncomingWebhook, error)
+	GetIncomingWebhooksForBoard(boardID string) ([]*model.IncomingWebhook, error)
+	InsertIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error)
+	UpdateIncomingWebhook(webhook *model.IncomingWebhook) (*model.IncomingWebhook, error)
+	DeleteIncomingWebhook(webhookID string) error
+
+	GetAutomationRule(ruleID string) (*model.AutomationRule, error)
+	GetAutomationRulesForBoard(boardID string) ([]*model.AutomationRule, error)
+	GetEnabledAutomationRules(triggerType string) ([]*model.AutomationRule, error)
+	InsertAutomationRule(rule *model.AutomationRule) (*model.AutomationRule, error)
+	UpdateAutomationRule(rule *model.AutomationRule) (*model.AutomationRule, error)
+	IncrementAutomationRuleRunCount(ruleID string) (int64, error)
+	// @withTransaction
+	DeleteAutomationRule(ruleID string) error
+	InsertAutomationExecution(execution *model.AutomationExecution) (*model.AutomationExecution, error)
+	GetAutomationExecutions(opts model.QueryAutomationExecutionsOptions) ([]*model.AutomationExecution, error)
+	DeleteAutomationExecutionsBefore(createAt int64) (int64, error)
+	InsertAutomationDueDateRun(run *model.AutomationDueDateRun) (bool, error)
 
 	GetUsedCardsCount() (int, error)
 	GetCardLimitTimestamp() (int64, error)
@@ -191,7 +286,9 @@ type Store interface {
 	GetBlocksComplianceHistory(opts model.QueryBlocksComplianceHistoryOptions) ([]*model.BlockHistory, bool, error)
 
 	// For unit testing only
+	// @invalidate board(boardID) member(boardID) block
 	DeleteBoardRecord(boardID, modifiedBy string) error
+	// @invalidate block
 	DeleteBlockRecord(blockID, modifiedBy string) error
 }
 
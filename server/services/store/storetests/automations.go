@@ -0,0 +1,207 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestAutomationStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("AutomationRules", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAutomationRules(t, store)
+	})
+	t.Run("AutomationExecutions", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAutomationExecutions(t, store)
+	})
+	t.Run("AutomationDueDateRuns", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testAutomationDueDateRuns(t, store)
+	})
+}
+
+func testAutomationRules(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		_, err := store.InsertAutomationRule(&model.AutomationRule{BoardID: "board-1", Trigger: model.AutomationTrigger{Type: model.AutomationTriggerCardCreated}})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	rule, err := store.InsertAutomationRule(&model.AutomationRule{
+		BoardID: "board-1",
+		Name:    "Complete",
+		Trigger: model.AutomationTrigger{Type: model.AutomationTriggerPropertyChanged, PropertyID: "status", To: []string{"done"}},
+		Conditions: &model.FilterGroup{
+			Operation: model.FilterGroupOperationAnd,
+			Filters: []model.FilterGroupItem{
+				{Clause: &model.FilterClause{PropertyID: "priority", Condition: model.FilterConditionIncludes, Values: []string{"high"}}},
+			},
+		},
+		Actions: []model.AutomationAction{
+			{Type: model.AutomationActionSetProperty, PropertyID: "completed", Value: "{{today}}"},
+			{Type: model.AutomationActionClearProperty, PropertyID: "assignee"},
+		},
+		CreatedBy:  userID,
+		ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, rule.ID)
+	require.NotZero(t, rule.CreateAt)
+
+	dueDateRule, err := store.InsertAutomationRule(&model.AutomationRule{
+		BoardID:    "board-2",
+		Trigger:    model.AutomationTrigger{Type: model.AutomationTriggerDueDatePassed, PropertyID: "due"},
+		Actions:    []model.AutomationAction{{Type: model.AutomationActionSetProperty, PropertyID: "status", Value: "overdue"}},
+		CreatedBy:  userID,
+		ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("get rules", func(t *testing.T) {
+		fetched, err := store.GetAutomationRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, rule, fetched)
+
+		rules, err := store.GetAutomationRulesForBoard("board-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.AutomationRule{rule}, rules)
+
+		rules, err = store.GetEnabledAutomationRules(model.AutomationTriggerDueDatePassed)
+		require.NoError(t, err)
+		require.Equal(t, []*model.AutomationRule{dueDateRule}, rules)
+
+		_, err = store.GetAutomationRule("missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("count the runs", func(t *testing.T) {
+		for i := int64(0); i < 3; i++ {
+			previous, err := store.IncrementAutomationRuleRunCount(rule.ID)
+			require.NoError(t, err)
+			require.Equal(t, i, previous)
+		}
+
+		_, err := store.IncrementAutomationRuleRunCount("missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("update a rule", func(t *testing.T) {
+		rule.Name = "Done"
+		rule.Conditions = nil
+		rule.Disabled = true
+		updated, err := store.UpdateAutomationRule(rule)
+		require.NoError(t, err)
+		require.Equal(t, "Done", updated.Name)
+		require.Nil(t, updated.Conditions)
+		require.True(t, updated.Disabled)
+		require.Equal(t, rule.Actions, updated.Actions)
+		require.Equal(t, int64(3), updated.RunCount)
+
+		dueDateRule.Disabled = true
+		_, err = store.UpdateAutomationRule(dueDateRule)
+		require.NoError(t, err)
+		rules, err := store.GetEnabledAutomationRules(model.AutomationTriggerDueDatePassed)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+
+		missing := *rule
+		missing.ID = "missing"
+		_, err = store.UpdateAutomationRule(&missing)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("delete a rule", func(t *testing.T) {
+		_, err := store.InsertAutomationExecution(&model.AutomationExecution{
+			RuleID: rule.ID, BoardID: "board-1", CardID: "card-1", TriggerType: rule.Trigger.Type, Status: model.AutomationExecutionSuccess,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteAutomationRule(rule.ID))
+		_, err = store.GetAutomationRule(rule.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		executions, err := store.GetAutomationExecutions(model.QueryAutomationExecutionsOptions{RuleID: rule.ID})
+		require.NoError(t, err)
+		require.Empty(t, executions)
+
+		require.True(t, model.IsErrNotFound(store.DeleteAutomationRule(rule.ID)))
+	})
+}
+
+func testAutomationExecutions(t *testing.T, store store.Store) {
+	for i, status := range []string{model.AutomationExecutionSuccess, model.AutomationExecutionFailed, model.AutomationExecutionSkipped} {
+		execution := &model.AutomationExecution{
+			RuleID:      "rule-1",
+			BoardID:     "board-1",
+			CardID:      "card-1",
+			TriggerType: model.AutomationTriggerCardCreated,
+			Status:      status,
+			Depth:       i,
+		}
+		if status != model.AutomationExecutionSuccess {
+			execution.Error = "error " + status
+		}
+		_, err := store.InsertAutomationExecution(execution)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, err := store.InsertAutomationExecution(&model.AutomationExecution{
+		RuleID: "rule-2", BoardID: "board-1", CardID: "card-1", TriggerType: model.AutomationTriggerCardCreated, Status: model.AutomationExecutionSuccess,
+	})
+	require.NoError(t, err)
+
+	t.Run("get the executions of a rule, newest first", func(t *testing.T) {
+		executions, err := store.GetAutomationExecutions(model.QueryAutomationExecutionsOptions{RuleID: "rule-1"})
+		require.NoError(t, err)
+		require.Len(t, executions, 3)
+		require.Equal(t, model.AutomationExecutionSkipped, executions[0].Status)
+		require.Equal(t, "error skipped", executions[0].Error)
+		require.Equal(t, 2, executions[0].Depth)
+		require.Equal(t, model.AutomationExecutionSuccess, executions[2].Status)
+		require.Empty(t, executions[2].Error)
+
+		page, err := store.GetAutomationExecutions(model.QueryAutomationExecutionsOptions{RuleID: "rule-1", Page: 1, PerPage: 2})
+		require.NoError(t, err)
+		require.Equal(t, executions[2:], page)
+	})
+
+	t.Run("delete the old executions", func(t *testing.T) {
+		deleted, err := store.DeleteAutomationExecutionsBefore(utils.GetMillis() + 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), deleted)
+
+		executions, err := store.GetAutomationExecutions(model.QueryAutomationExecutionsOptions{RuleID: "rule-1"})
+		require.NoError(t, err)
+		require.Empty(t, executions)
+	})
+}
+
+func testAutomationDueDateRuns(t *testing.T, store store.Store) {
+	run := &model.AutomationDueDateRun{RuleID: "rule-1", BoardID: "board-1", CardID: "card-1", DueAt: 1000, RunAt: 2000}
+
+	inserted, err := store.InsertAutomationDueDateRun(run)
+	require.NoError(t, err)
+	require.True(t, inserted)
+
+	inserted, err = store.InsertAutomationDueDateRun(run)
+	require.NoError(t, err)
+	require.False(t, inserted)
+
+	// a new due date runs the rule again
+	run.DueAt = 3000
+	inserted, err = store.InsertAutomationDueDateRun(run)
+	require.NoError(t, err)
+	require.True(t, inserted)
+}
@@ -87,6 +87,11 @@ func StoreTestBlocksStore(t *testing.T, setup func(t *testing.T) (store.Store, f
 		defer tearDown()
 		testGetBlockHistoryNewestChildren(t, store)
 	})
+	t.Run("GetBlockHistoryNewestDescendants", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBlockHistoryNewestDescendants(t, store)
+	})
 }
 
 func testInsertBlock(t *testing.T, store store.Store) {
@@ -452,6 +457,52 @@ func testPatchBlock(t *testing.T, store store.Store) {
 		require.Equal(t, "test value 2", retrievedBlock.Fields["test2"])
 		require.Equal(t, nil, retrievedBlock.Fields["test3"])
 	})
+
+	t.Run("expected version matches", func(t *testing.T) {
+		currentBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+
+		newTitle := "Versioned title"
+		blockPatch := &model.BlockPatch{
+			Title:            &newTitle,
+			ExpectedUpdateAt: &currentBlock.UpdateAt,
+		}
+
+		time.Sleep(1 * time.Millisecond)
+		err = store.PatchBlock("id-test", blockPatch, "user-id-2")
+		require.NoError(t, err)
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, newTitle, retrievedBlock.Title)
+	})
+
+	t.Run("expected version is outdated", func(t *testing.T) {
+		currentBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+
+		outdated := currentBlock.UpdateAt - 1
+		newTitle := "Stale title"
+		blockPatch := &model.BlockPatch{
+			Title:            &newTitle,
+			ExpectedUpdateAt: &outdated,
+		}
+
+		time.Sleep(1 * time.Millisecond)
+		err = store.PatchBlock("id-test", blockPatch, "user-id-2")
+		require.True(t, model.IsErrConflict(err))
+
+		var conflict *model.ErrConflict
+		require.ErrorAs(t, err, &conflict)
+		current, ok := conflict.Current.(*model.Block)
+		require.True(t, ok)
+		require.Equal(t, currentBlock.Title, current.Title)
+		require.Equal(t, currentBlock.UpdateAt, current.UpdateAt)
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.Equal(t, currentBlock.Title, retrievedBlock.Title)
+	})
 }
 
 func testPatchBlocks(t *testing.T, store store.Store) {
@@ -523,6 +574,45 @@ func testPatchBlocks(t *testing.T, store store.Store) {
 		require.NoError(t, err)
 		require.NotEqual(t, title, retrievedBlock.Title)
 	})
+
+	t.Run("outdated expected version, nothing updated and conflicts reported", func(t *testing.T) {
+		currentBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		currentBlock2, err := store.GetBlock("id-test2")
+		require.NoError(t, err)
+
+		outdated := currentBlock2.UpdateAt - 1
+		title := "Conflicting Title"
+		blockPatch := model.BlockPatch{
+			Title:            &title,
+			ExpectedUpdateAt: &currentBlock.UpdateAt,
+		}
+
+		blockPatch2 := model.BlockPatch{
+			Title:            &title,
+			ExpectedUpdateAt: &outdated,
+		}
+
+		blockIds := []string{"id-test", "id-test2"}
+		blockPatches := []model.BlockPatch{blockPatch, blockPatch2}
+
+		time.Sleep(1 * time.Millisecond)
+		err = store.PatchBlocks(&model.BlockPatchBatch{BlockIDs: blockIds, BlockPatches: blockPatches}, "user-id-1")
+		var conflict *model.ErrConflict
+		require.ErrorAs(t, err, &conflict)
+		conflicts, ok := conflict.Current.([]*model.Block)
+		require.True(t, ok)
+		require.Len(t, conflicts, 1)
+		require.Equal(t, "id-test2", conflicts[0].ID)
+
+		retrievedBlock, err := store.GetBlock("id-test")
+		require.NoError(t, err)
+		require.NotEqual(t, title, retrievedBlock.Title)
+
+		retrievedBlock2, err := store.GetBlock("id-test2")
+		require.NoError(t, err)
+		require.NotEqual(t, title, retrievedBlock2.Title)
+	})
 }
 
 var (
@@ -767,35 +857,35 @@ func testGetBlocks(t *testing.T, store store.Store) {
 			BoardID:    boardID,
 			ParentID:   "",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 		{
 			ID:         "block2",
 			BoardID:    boardID,
 			ParentID:   "block1",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 		{
 			ID:         "block3",
 			BoardID:    boardID,
 			ParentID:   "block1",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 		{
 			ID:         "block4",
 			BoardID:    boardID,
 			ParentID:   "block1",
 			ModifiedBy: testUserID,
-			Type:       "test2",
+			Type:       model.TypeComment,
 		},
 		{
 			ID:         "block5",
 			BoardID:    boardID,
 			ParentID:   "block2",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 	}
 	InsertBlocks(t, store, blocksToInsert, "user-id-1")
@@ -803,7 +893,7 @@ func testGetBlocks(t *testing.T, store store.Store) {
 
 	t.Run("not existing parent", func(t *testing.T) {
 		time.Sleep(1 * time.Millisecond)
-		blocks, err = store.GetBlocksWithParentAndType(boardID, "not-exists", "test")
+		blocks, err = store.GetBlocksWithParentAndType(boardID, "not-exists", model.TypeText)
 		require.NoError(t, err)
 		require.Empty(t, blocks)
 	})
@@ -817,7 +907,7 @@ func testGetBlocks(t *testing.T, store store.Store) {
 
 	t.Run("valid parent and type", func(t *testing.T) {
 		time.Sleep(1 * time.Millisecond)
-		blocks, err = store.GetBlocksWithParentAndType(boardID, "block1", "test")
+		blocks, err = store.GetBlocksWithParentAndType(boardID, "block1", model.TypeText)
 		require.NoError(t, err)
 		require.Len(t, blocks, 2)
 	})
@@ -845,7 +935,7 @@ func testGetBlocks(t *testing.T, store store.Store) {
 
 	t.Run("valid type", func(t *testing.T) {
 		time.Sleep(1 * time.Millisecond)
-		blocks, err = store.GetBlocksWithType(boardID, "test")
+		blocks, err = store.GetBlocksWithType(boardID, model.TypeText)
 		require.NoError(t, err)
 		require.Len(t, blocks, 4)
 	})
@@ -987,35 +1077,35 @@ func testGetBlockMetadata(t *testing.T, store store.Store) {
 			BoardID:    boardID,
 			ParentID:   "",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 		{
 			ID:         "block2",
 			BoardID:    boardID,
 			ParentID:   "block1",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 		{
 			ID:         "block3",
 			BoardID:    boardID,
 			ParentID:   "block1",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 		{
 			ID:         "block4",
 			BoardID:    boardID,
 			ParentID:   "block1",
 			ModifiedBy: testUserID,
-			Type:       "test2",
+			Type:       model.TypeComment,
 		},
 		{
 			ID:         "block5",
 			BoardID:    boardID,
 			ParentID:   "block2",
 			ModifiedBy: testUserID,
-			Type:       "test",
+			Type:       model.TypeText,
 		},
 	}
 
@@ -1347,3 +1437,75 @@ func testGetBlockHistoryNewestChildren(t *testing.T, store store.Store) {
 		}
 	})
 }
+
+func testGetBlockHistoryNewestDescendants(t *testing.T, store store.Store) {
+	boards := createTestBoards(t, store, testTeamID, testUserID, 2)
+	board := boards[0]
+
+	const contentCount = 4
+	const patchCount = 4
+
+	card := createTestCards(t, store, testUserID, board.ID, 1)[0]
+	content := createTestBlocksForCard(t, store, card.ID, contentCount)
+	otherCard := createTestCards(t, store, testUserID, boards[1].ID, 1)[0]
+	createTestBlocksForCard(t, store, otherCard.ID, 1)
+
+	// patch the content blocks to create some history records, and keep
+	// the time between the second and the third patches
+	var middle int64
+	for i := 1; i <= patchCount; i++ {
+		time.Sleep(1 * time.Millisecond)
+		for _, block := range content {
+			title := strconv.FormatInt(int64(i), 10)
+			err := store.PatchBlock(block.ID, &model.BlockPatch{Title: &title}, testUserID)
+			require.NoError(t, err, "error patching content blocks")
+		}
+		if i == 2 {
+			time.Sleep(1 * time.Millisecond)
+			middle = utils.GetMillis()
+		}
+	}
+
+	time.Sleep(1 * time.Millisecond)
+	require.NoError(t, store.DeleteBlock(content[0].ID, testUserID))
+
+	t.Run("invalid board", func(t *testing.T) {
+		blocks, hasMore, err := store.GetBlockHistoryNewestDescendants(utils.NewID(utils.IDTypeBoard), model.QueryBlockHistoryChildOptions{})
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.Empty(t, blocks)
+	})
+
+	t.Run("newest revisions including the deleted blocks", func(t *testing.T) {
+		blocks, hasMore, err := store.GetBlockHistoryNewestDescendants(board.ID, model.QueryBlockHistoryChildOptions{})
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.ElementsMatch(t, extractIDs(t, blocks), append(extractIDs(t, content), card.ID))
+
+		for _, b := range blocks {
+			switch b.ID {
+			case card.ID:
+				require.Zero(t, b.DeleteAt)
+			case content[0].ID:
+				require.NotZero(t, b.DeleteAt)
+			default:
+				require.Equal(t, strconv.FormatInt(patchCount, 10), b.Title)
+			}
+		}
+	})
+
+	t.Run("newest revisions before a time", func(t *testing.T) {
+		opts := model.QueryBlockHistoryChildOptions{BeforeUpdateAt: middle}
+		blocks, hasMore, err := store.GetBlockHistoryNewestDescendants(board.ID, opts)
+		require.NoError(t, err)
+		require.False(t, hasMore)
+		require.ElementsMatch(t, extractIDs(t, blocks), append(extractIDs(t, content), card.ID))
+
+		for _, b := range blocks {
+			require.Zero(t, b.DeleteAt)
+			if b.ID != card.ID {
+				require.Equal(t, "2", b.Title)
+			}
+		}
+	})
+}
@@ -416,6 +416,45 @@ func testPatchBoard(t *testing.T, store store.Store) {
 		require.Equal(t, userID2, patchedBoard.ModifiedBy)
 	})
 
+	t.Run("should fail with a conflict if the expected version is outdated", func(t *testing.T) {
+		boardID := utils.NewID(utils.IDTypeBoard)
+
+		board := &model.Board{
+			ID:     boardID,
+			TeamID: testTeamID,
+			Type:   model.BoardTypeOpen,
+			Title:  "A versioned title",
+		}
+
+		newBoard, err := store.InsertBoard(board, userID)
+		require.NoError(t, err)
+
+		// wait to avoid hitting pk uniqueness constraint in history
+		time.Sleep(10 * time.Millisecond)
+
+		newTitle := "First title"
+		patch := &model.BoardPatch{Title: &newTitle, ExpectedUpdateAt: &newBoard.UpdateAt}
+		patchedBoard, err := store.PatchBoard(boardID, patch, userID)
+		require.NoError(t, err)
+		require.Equal(t, newTitle, patchedBoard.Title)
+
+		time.Sleep(10 * time.Millisecond)
+
+		// the same precondition is now outdated
+		staleTitle := "Stale title"
+		patch = &model.BoardPatch{Title: &staleTitle, ExpectedUpdateAt: &newBoard.UpdateAt}
+		rBoard, err := store.PatchBoard(boardID, patch, userID)
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, rBoard)
+
+		var conflict *model.ErrConflict
+		require.ErrorAs(t, err, &conflict)
+		current, ok := conflict.Current.(*model.Board)
+		require.True(t, ok)
+		require.Equal(t, newTitle, current.Title)
+		require.Equal(t, patchedBoard.UpdateAt, current.UpdateAt)
+	})
+
 	t.Run("should correctly update the board properties", func(t *testing.T) {
 		boardID := utils.NewID(utils.IDTypeBoard)
 
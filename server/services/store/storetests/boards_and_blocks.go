@@ -30,6 +30,12 @@ func StoreTestBoardsAndBlocksStore(t *testing.T, setup func(t *testing.T) (store
 		testDeleteBoardsAndBlocks(t, store)
 	})
 
+	t.Run("restoreBoardAndBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRestoreBoardAndBlocks(t, store)
+	})
+
 	t.Run("duplicateBoard", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
@@ -332,6 +338,138 @@ func testPatchBoardsAndBlocks(t *testing.T, store store.Store) {
 		require.NoError(t, err)
 		require.Equal(t, newSchema, block2.Schema)
 	})
+
+	t.Run("should report every conflict and save nothing", func(t *testing.T) {
+		board1, err := store.GetBoard("board-id-1")
+		require.NoError(t, err)
+		board3, err := store.GetBoard("board-id-3")
+		require.NoError(t, err)
+		block1, err := store.GetBlock("block-id-1")
+		require.NoError(t, err)
+		block2, err := store.GetBlock("block-id-2")
+		require.NoError(t, err)
+
+		outdatedBoard := board3.UpdateAt - 1
+		outdatedBlock := block2.UpdateAt - 1
+		conflictingTitle := "conflicting title"
+
+		pbab := &model.PatchBoardsAndBlocks{
+			BoardIDs: []string{"board-id-1", "board-id-3"},
+			BoardPatches: []*model.BoardPatch{
+				{Title: &conflictingTitle, ExpectedUpdateAt: &board1.UpdateAt},
+				{Title: &conflictingTitle, ExpectedUpdateAt: &outdatedBoard},
+			},
+			BlockIDs: []string{"block-id-1", "block-id-2"},
+			BlockPatches: []*model.BlockPatch{
+				{Title: &conflictingTitle, ExpectedUpdateAt: &block1.UpdateAt},
+				{Title: &conflictingTitle, ExpectedUpdateAt: &outdatedBlock},
+			},
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		bab, err := store.PatchBoardsAndBlocks(pbab, userID)
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, bab)
+
+		var conflict *model.ErrConflict
+		require.ErrorAs(t, err, &conflict)
+		current, ok := conflict.Current.(*model.BoardsAndBlocks)
+		require.True(t, ok)
+		require.Len(t, current.Boards, 1)
+		require.Equal(t, "board-id-3", current.Boards[0].ID)
+		require.Len(t, current.Blocks, 1)
+		require.Equal(t, "block-id-2", current.Blocks[0].ID)
+
+		// check that things have not changed
+		rBoard1, err := store.GetBoard("board-id-1")
+		require.NoError(t, err)
+		require.Equal(t, board1.Title, rBoard1.Title)
+
+		rBlock1, err := store.GetBlock("block-id-1")
+		require.NoError(t, err)
+		require.Equal(t, block1.Title, rBlock1.Title)
+	})
+}
+
+func testRestoreBoardAndBlocks(t *testing.T, store store.Store) {
+	teamID := testTeamID
+	userID := testUserID
+	userID2 := "user-id-2"
+
+	newBab := &model.BoardsAndBlocks{
+		Boards: []*model.Board{
+			{ID: "board-id-1", Title: "initial title", TeamID: teamID, Type: model.BoardTypeOpen},
+		},
+		Blocks: []*model.Block{
+			{ID: "block-id-1", Title: "initial title", BoardID: "board-id-1", Type: model.TypeCard},
+			{ID: "block-id-2", Title: "deleted block", BoardID: "board-id-1", Type: model.TypeCard},
+			{ID: "block-id-3", Title: "new block", BoardID: "board-id-1", Type: model.TypeCard},
+			{ID: "block-id-4", Title: "new child", BoardID: "board-id-1", ParentID: "block-id-3", Type: model.TypeText},
+		},
+	}
+	_, err := store.CreateBoardsAndBlocks(newBab, userID)
+	require.NoError(t, err)
+
+	initialBoard, err := store.GetBoard("board-id-1")
+	require.NoError(t, err)
+	initialBlock1, err := store.GetBlock("block-id-1")
+	require.NoError(t, err)
+	initialBlock2, err := store.GetBlock("block-id-2")
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, store.DeleteBlock("block-id-2", userID))
+
+	time.Sleep(10 * time.Millisecond)
+	restoredBoard := *initialBoard
+	restoredBoard.Title = "restored title"
+	initialBlock1.Title = "restored title"
+	err = store.RestoreBoardAndBlocks(&restoredBoard, []*model.Block{initialBlock1, initialBlock2}, []string{"block-id-3"}, userID2)
+	require.NoError(t, err)
+
+	t.Run("the board is updated", func(t *testing.T) {
+		board, err := store.GetBoard("board-id-1")
+		require.NoError(t, err)
+		require.Equal(t, "restored title", board.Title)
+		require.Equal(t, userID2, board.ModifiedBy)
+	})
+
+	t.Run("existing blocks are updated", func(t *testing.T) {
+		block, err := store.GetBlock("block-id-1")
+		require.NoError(t, err)
+		require.Equal(t, "restored title", block.Title)
+		require.Equal(t, userID2, block.ModifiedBy)
+	})
+
+	t.Run("deleted blocks are brought back with their creation metadata", func(t *testing.T) {
+		block, err := store.GetBlock("block-id-2")
+		require.NoError(t, err)
+		require.Equal(t, "deleted block", block.Title)
+		require.Equal(t, userID, block.CreatedBy)
+		require.Equal(t, initialBlock2.CreateAt, block.CreateAt)
+		require.Equal(t, userID2, block.ModifiedBy)
+		require.Zero(t, block.DeleteAt)
+	})
+
+	t.Run("blocks are deleted keeping their children", func(t *testing.T) {
+		_, err := store.GetBlock("block-id-3")
+		require.True(t, model.IsErrNotFound(err))
+
+		child, err := store.GetBlock("block-id-4")
+		require.NoError(t, err)
+		require.Equal(t, "new child", child.Title)
+	})
+
+	t.Run("the restore is recorded in the history", func(t *testing.T) {
+		blocks, err := store.GetBlockHistory("block-id-2", model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Len(t, blocks, 3)
+
+		boards, err := store.GetBoardHistory("board-id-1", model.QueryBoardHistoryOptions{})
+		require.NoError(t, err)
+		require.Len(t, boards, 2)
+	})
 }
 
 func testDeleteBoardsAndBlocks(t *testing.T, store store.Store) {
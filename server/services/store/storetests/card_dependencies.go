@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestCardDependencyStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CardDependencies", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCardDependencies(t, store)
+	})
+	t.Run("PurgeBlockDeletesDependencies", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeBlockDeletesDependencies(t, store)
+	})
+}
+
+func testCardDependencies(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	t.Run("invalid dependencies are rejected", func(t *testing.T) {
+		_, err := store.InsertCardDependency(&model.CardDependency{BlockerID: "card-1", BlockerBoardID: "board-1"})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = store.InsertCardDependency(&model.CardDependency{
+			BlockerID: "card-1", BlockerBoardID: "board-1", BlockedID: "card-1", BlockedBoardID: "board-1",
+		})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	for _, dependency := range []*model.CardDependency{
+		{BlockerID: "card-1", BlockerBoardID: "board-1", BlockedID: "card-2", BlockedBoardID: "board-1", CreatedBy: userID},
+		{BlockerID: "card-2", BlockerBoardID: "board-1", BlockedID: "card-3", BlockedBoardID: "board-2", CreatedBy: userID},
+		{BlockerID: "card-4", BlockerBoardID: "board-2", BlockedID: "card-3", BlockedBoardID: "board-2", CreatedBy: userID},
+	} {
+		inserted, err := store.InsertCardDependency(dependency)
+		require.NoError(t, err)
+		require.NotZero(t, inserted.CreateAt)
+	}
+
+	t.Run("duplicated dependencies are rejected", func(t *testing.T) {
+		_, err := store.InsertCardDependency(&model.CardDependency{
+			BlockerID: "card-1", BlockerBoardID: "board-1", BlockedID: "card-2", BlockedBoardID: "board-1", CreatedBy: userID,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("get dependencies by blocker and blocked cards", func(t *testing.T) {
+		dependencies, err := store.GetCardDependencies(model.QueryCardDependenciesOptions{})
+		require.NoError(t, err)
+		require.Empty(t, dependencies)
+
+		dependencies, err = store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockedIDs: []string{"card-3"}})
+		require.NoError(t, err)
+		require.Len(t, dependencies, 2)
+		for _, dependency := range dependencies {
+			require.Equal(t, "card-3", dependency.BlockedID)
+			require.Equal(t, "board-2", dependency.BlockedBoardID)
+		}
+
+		dependencies, err = store.GetCardDependencies(model.QueryCardDependenciesOptions{
+			BlockerIDs: []string{"card-2"},
+			BlockedIDs: []string{"card-2"},
+		})
+		require.NoError(t, err)
+		require.Len(t, dependencies, 2)
+	})
+
+	t.Run("delete a dependency", func(t *testing.T) {
+		require.NoError(t, store.DeleteCardDependency("card-4", "card-3"))
+
+		err := store.DeleteCardDependency("card-4", "card-3")
+		require.True(t, model.IsErrNotFound(err))
+
+		dependencies, err := store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockedIDs: []string{"card-3"}})
+		require.NoError(t, err)
+		require.Len(t, dependencies, 1)
+		require.Equal(t, "card-2", dependencies[0].BlockerID)
+	})
+}
+
+func testPurgeBlockDeletesDependencies(t *testing.T, store store.Store) {
+	boardID := testBoardID
+	userID := testUserID
+
+	blocks := []*model.Block{
+		{ID: "card-1", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, ModifiedBy: userID},
+		{ID: "card-2", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, ModifiedBy: userID},
+	}
+	require.NoError(t, store.InsertBlocks(blocks, userID))
+	_, err := store.InsertCardDependency(&model.CardDependency{
+		BlockerID: "card-1", BlockerBoardID: boardID, BlockedID: "card-2", BlockedBoardID: boardID, CreatedBy: userID,
+	})
+	require.NoError(t, err)
+
+	// deleted cards keep their dependencies, so that they can be restored
+	require.NoError(t, store.DeleteBlock("card-1", userID))
+	dependencies, err := store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockerIDs: []string{"card-1"}})
+	require.NoError(t, err)
+	require.Len(t, dependencies, 1)
+
+	require.NoError(t, store.PurgeBlock("card-1"))
+	dependencies, err = store.GetCardDependencies(model.QueryCardDependenciesOptions{BlockerIDs: []string{"card-1"}})
+	require.NoError(t, err)
+	require.Empty(t, dependencies)
+}
@@ -0,0 +1,528 @@
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestCardsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("QueryCards", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testQueryCards(t, store)
+	})
+	t.Run("SearchCards", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSearchCards(t, store)
+	})
+	t.Run("GetChecklistProgress", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetChecklistProgress(t, store)
+	})
+}
+
+func testGetChecklistProgress(t *testing.T, store store.Store) {
+	userID := testUserID
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	insertBlock := func(blockType model.BlockType, parentID string, fields map[string]interface{}) *model.Block {
+		block := &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  boardID,
+			ParentID: parentID,
+			Type:     blockType,
+			Fields:   fields,
+			CreateAt: 1,
+			UpdateAt: 1,
+		}
+		require.NoError(t, store.InsertBlock(block, userID))
+		return block
+	}
+
+	card1 := insertBlock(model.TypeCard, boardID, nil)
+	card2 := insertBlock(model.TypeCard, boardID, nil)
+	card3 := insertBlock(model.TypeCard, boardID, nil)
+	insertBlock(model.TypeCheckbox, card1.ID, map[string]interface{}{"value": true})
+	insertBlock(model.TypeCheckbox, card1.ID, map[string]interface{}{"value": false})
+	deleted := insertBlock(model.TypeCheckbox, card1.ID, map[string]interface{}{"value": true})
+	insertBlock(model.TypeCheckbox, card2.ID, map[string]interface{}{"value": true})
+	insertBlock(model.TypeText, card3.ID, nil)
+	require.NoError(t, store.DeleteBlock(deleted.ID, userID))
+
+	progress, err := store.GetChecklistProgress([]string{card1.ID, card2.ID, card3.ID})
+	require.NoError(t, err)
+	require.Equal(t, map[string]model.ChecklistProgress{
+		card1.ID: {Done: 1, Total: 2},
+		card2.ID: {Done: 1, Total: 1},
+	}, progress)
+
+	progress, err = store.GetChecklistProgress(nil)
+	require.NoError(t, err)
+	require.Empty(t, progress)
+}
+
+func testQueryCards(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	board := &model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "todo", "value": "To do"},
+					map[string]interface{}{"id": "done", "value": "Done"},
+				},
+			},
+			{
+				"id":   "estimate",
+				"name": "Estimate",
+				"type": "number",
+			},
+		},
+	}
+	_, err := store.InsertBoard(board, userID)
+	require.NoError(t, err)
+
+	newCard := func(title, status, estimate string, isTemplate bool) *model.Block {
+		card := &model.Card{
+			ID:         utils.NewID(utils.IDTypeCard),
+			BoardID:    board.ID,
+			Title:      title,
+			IsTemplate: isTemplate,
+			Properties: map[string]any{"status": status, "estimate": estimate},
+		}
+		card.Populate()
+		block := model.Card2Block(card)
+		require.NoError(t, store.InsertBlock(block, userID))
+		return block
+	}
+
+	cardA := newCard("A", "todo", "3", false)
+	cardB := newCard("B", "done", "1", false)
+	cardC := newCard("C", "todo", "2", false)
+	newCard("Template", "todo", "5", true)
+
+	cardIDs := func(result *model.CardQueryResult) []string {
+		ids := make([]string, 0, len(result.Cards))
+		for _, c := range result.Cards {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	t.Run("no options returns all non template cards", func(t *testing.T) {
+		result, err := store.QueryCards(board.ID, model.QueryCardsOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, []string{cardA.ID, cardB.ID, cardC.ID}, cardIDs(result))
+		assert.Empty(t, result.Groups)
+	})
+
+	t.Run("filter, sort and group", func(t *testing.T) {
+		opts := model.QueryCardsOptions{
+			Filter: &model.FilterGroup{
+				Operation: model.FilterGroupOperationAnd,
+				Filters: []model.FilterGroupItem{
+					{Clause: &model.FilterClause{PropertyID: "status", Condition: model.FilterConditionIncludes, Values: []string{"todo"}}},
+				},
+			},
+			SortOptions: []model.SortOption{{PropertyID: "estimate"}},
+			GroupByID:   "status",
+		}
+		result, err := store.QueryCards(board.ID, opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, []string{cardC.ID, cardA.ID}, cardIDs(result))
+		require.Len(t, result.Groups, 3)
+		assert.Equal(t, model.CardGroupCount{OptionID: "todo", Value: "To do", Count: 2}, result.Groups[1])
+		assert.Equal(t, model.CardGroupCount{OptionID: "done", Value: "Done", Count: 0}, result.Groups[2])
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		opts := model.QueryCardsOptions{
+			SortOptions: []model.SortOption{{PropertyID: model.CardQuerySortTitlePropertyID, Reversed: true}},
+			Page:        1,
+			PerPage:     2,
+		}
+		result, err := store.QueryCards(board.ID, opts)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, []string{cardA.ID}, cardIDs(result))
+
+		opts.Page = 5
+		result, err = store.QueryCards(board.ID, opts)
+		require.NoError(t, err)
+		assert.Empty(t, result.Cards)
+	})
+
+	t.Run("checklist progress", func(t *testing.T) {
+		addCheckbox := func(cardID string, value bool) {
+			checkbox := &model.Block{
+				ID:       utils.NewID(utils.IDTypeBlock),
+				BoardID:  board.ID,
+				ParentID: cardID,
+				Type:     model.TypeCheckbox,
+				Fields:   map[string]interface{}{"value": value},
+			}
+			require.NoError(t, store.InsertBlock(checkbox, userID))
+		}
+		addCheckbox(cardA.ID, true)
+		addCheckbox(cardA.ID, false)
+		addCheckbox(cardB.ID, true)
+
+		query := func(checklist model.ChecklistQuery) *model.CardQueryResult {
+			result, err := store.QueryCards(board.ID, model.QueryCardsOptions{Checklist: checklist})
+			require.NoError(t, err)
+			return result
+		}
+
+		assert.Equal(t, []string{cardC.ID}, cardIDs(query(model.ChecklistQuery{Filter: model.ChecklistFilterNone})))
+		assert.Equal(t, []string{cardA.ID}, cardIDs(query(model.ChecklistQuery{Filter: model.ChecklistFilterIncomplete})))
+		assert.Equal(t, []string{cardB.ID}, cardIDs(query(model.ChecklistQuery{Filter: model.ChecklistFilterComplete})))
+
+		// the cards without checkboxes go at the bottom in both directions
+		assert.Equal(t, []string{cardA.ID, cardB.ID, cardC.ID}, cardIDs(query(model.ChecklistQuery{Sort: model.ChecklistSortAscending})))
+		assert.Equal(t, []string{cardB.ID, cardA.ID, cardC.ID}, cardIDs(query(model.ChecklistQuery{Sort: model.ChecklistSortDescending})))
+
+		// the checklist sort goes before the sort options and the pagination
+		result, err := store.QueryCards(board.ID, model.QueryCardsOptions{
+			Checklist:   model.ChecklistQuery{Sort: model.ChecklistSortDescending},
+			SortOptions: []model.SortOption{{PropertyID: "estimate"}},
+			PerPage:     1,
+			Page:        1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, []string{cardA.ID}, cardIDs(result))
+	})
+
+	t.Run("view settings", func(t *testing.T) {
+		view := &model.Block{
+			ID:       utils.NewID(utils.IDTypeView),
+			BoardID:  board.ID,
+			ParentID: board.ID,
+			Type:     model.TypeView,
+			Fields: map[string]interface{}{
+				"filter": map[string]interface{}{
+					"operation": "or",
+					"filters": []interface{}{
+						map[string]interface{}{"propertyId": "status", "condition": "includes", "values": []interface{}{"done"}},
+						map[string]interface{}{"propertyId": "title", "condition": "is", "values": []interface{}{"c"}},
+					},
+				},
+				"sortOptions": []interface{}{},
+				"cardOrder":   []interface{}{cardC.ID, cardB.ID},
+				"groupById":   "status",
+			},
+		}
+		require.NoError(t, store.InsertBlock(view, userID))
+
+		result, err := store.QueryCards(board.ID, model.QueryCardsOptions{ViewID: view.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{cardC.ID, cardB.ID}, cardIDs(result))
+		require.Len(t, result.Groups, 3)
+
+		_, err = store.QueryCards(board.ID, model.QueryCardsOptions{ViewID: cardA.ID})
+		require.Error(t, err)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("view from another board", func(t *testing.T) {
+		view := &model.Block{
+			ID:      utils.NewID(utils.IDTypeView),
+			BoardID: utils.NewID(utils.IDTypeBoard),
+			Type:    model.TypeView,
+			Fields:  map[string]interface{}{},
+		}
+		require.NoError(t, store.InsertBlock(view, userID))
+
+		_, err := store.QueryCards(board.ID, model.QueryCardsOptions{ViewID: view.ID})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("text and select properties", func(t *testing.T) {
+		textBoard := &model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: testTeamID,
+			Type:   model.BoardTypeOpen,
+			CardProperties: []map[string]interface{}{
+				{
+					"id":   "priority",
+					"name": "Priority",
+					"type": "select",
+					"options": []interface{}{
+						map[string]interface{}{"id": "high", "value": "1. High"},
+						map[string]interface{}{"id": "low", "value": "2. Low"},
+					},
+				},
+				{"id": "notes", "name": "Notes", "type": "text"},
+			},
+		}
+		_, err := store.InsertBoard(textBoard, userID)
+		require.NoError(t, err)
+
+		newTextCard := func(title, priority, notes string) *model.Block {
+			properties := map[string]any{}
+			if priority != "" {
+				properties["priority"] = priority
+			}
+			if notes != "" {
+				properties["notes"] = notes
+			}
+			card := &model.Card{
+				ID:         utils.NewID(utils.IDTypeCard),
+				BoardID:    textBoard.ID,
+				Title:      title,
+				Properties: properties,
+			}
+			card.Populate()
+			block := model.Card2Block(card)
+			require.NoError(t, store.InsertBlock(block, userID))
+			return block
+		}
+
+		card1 := newTextCard("beta", "low", "50% done")
+		card2 := newTextCard("Alpha", "high", "")
+		card3 := newTextCard("", "low", "500 done")
+		card4 := newTextCard("gamma", "", "Half DONE")
+
+		query := func(opts model.QueryCardsOptions) *model.CardQueryResult {
+			result, qErr := store.QueryCards(textBoard.ID, opts)
+			require.NoError(t, qErr)
+			return result
+		}
+		clause := func(propertyID string, condition model.FilterCondition, values ...string) model.FilterGroupItem {
+			return model.FilterGroupItem{Clause: &model.FilterClause{PropertyID: propertyID, Condition: condition, Values: values}}
+		}
+		and := func(items ...model.FilterGroupItem) *model.FilterGroup {
+			return &model.FilterGroup{Operation: model.FilterGroupOperationAnd, Filters: items}
+		}
+
+		result := query(model.QueryCardsOptions{})
+		assert.Equal(t, []string{card2.ID, card1.ID, card4.ID, card3.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{Filter: and(clause("notes", model.FilterConditionContains, "0%"))})
+		assert.Equal(t, []string{card1.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{Filter: and(clause("notes", model.FilterConditionEndsWith, "done"))})
+		assert.Equal(t, []string{card1.ID, card4.ID, card3.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{Filter: and(clause("priority", model.FilterConditionNotIncludes, "low"))})
+		assert.Equal(t, []string{card2.ID, card4.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{Filter: and(clause("notes", model.FilterConditionIsEmpty))})
+		assert.Equal(t, []string{card2.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{Filter: &model.FilterGroup{
+			Operation: model.FilterGroupOperationOr,
+			Filters: []model.FilterGroupItem{
+				clause("title", model.FilterConditionStartsWith, "GAM"),
+				{Group: and(clause("priority", model.FilterConditionIncludes, "low"), clause("notes", model.FilterConditionNotContains, "%"))},
+			},
+		}})
+		assert.Equal(t, []string{card4.ID, card3.ID}, cardIDs(result))
+
+		// the cards without value go at the bottom in both directions
+		result = query(model.QueryCardsOptions{SortOptions: []model.SortOption{{PropertyID: "priority"}}})
+		assert.Equal(t, []string{card2.ID, card1.ID, card3.ID, card4.ID}, cardIDs(result))
+		result = query(model.QueryCardsOptions{SortOptions: []model.SortOption{{PropertyID: "priority", Reversed: true}}})
+		assert.Equal(t, []string{card1.ID, card3.ID, card2.ID, card4.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{CardOrder: []string{card3.ID, utils.NewID(utils.IDTypeCard), card1.ID}, PerPage: 3})
+		assert.Equal(t, 4, result.Total)
+		assert.Equal(t, []string{card3.ID, card1.ID, card2.ID}, cardIDs(result))
+
+		result = query(model.QueryCardsOptions{
+			Filter:    and(clause("notes", model.FilterConditionIsNotEmpty)),
+			GroupByID: "priority",
+		})
+		assert.Equal(t, []model.CardGroupCount{
+			{OptionID: "", Count: 1},
+			{OptionID: "high", Value: "1. High", Count: 0},
+			{OptionID: "low", Value: "2. Low", Count: 2},
+		}, result.Groups)
+	})
+}
+
+func testSearchCards(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	board := &model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "blocked", "value": "Blocked upstream"},
+				},
+			},
+			{
+				"id":   "notes",
+				"name": "Notes",
+				"type": "text",
+			},
+		},
+	}
+	_, err := store.InsertBoard(board, userID)
+	require.NoError(t, err)
+
+	otherBoard := &model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: testTeamID,
+		Type:   model.BoardTypeOpen,
+	}
+	_, err = store.InsertBoard(otherBoard, userID)
+	require.NoError(t, err)
+
+	newCard := func(boardID, title string, properties map[string]any, isTemplate bool) *model.Block {
+		card := &model.Card{
+			ID:         utils.NewID(utils.IDTypeCard),
+			BoardID:    boardID,
+			Title:      title,
+			IsTemplate: isTemplate,
+			Properties: properties,
+		}
+		card.Populate()
+		block := model.Card2Block(card)
+		require.NoError(t, store.InsertBlock(block, userID))
+		return block
+	}
+
+	newContent := func(card *model.Block, blockType model.BlockType, title string) *model.Block {
+		block := &model.Block{
+			ID:       utils.NewID(utils.IDTypeBlock),
+			BoardID:  card.BoardID,
+			ParentID: card.ID,
+			Type:     blockType,
+			Title:    title,
+		}
+		require.NoError(t, store.InsertBlock(block, userID))
+		return block
+	}
+
+	cardA := newCard(board.ID, "Fix the rollback", map[string]any{"status": "blocked"}, false)
+	cardB := newCard(board.ID, "Release notes", map[string]any{"notes": "Mention the <b>rollback</b> fix"}, false)
+	textA := newContent(cardA, model.TypeText, "Steps to reproduce the crash after a migration")
+	checkboxB := newContent(cardB, model.TypeCheckbox, "Publish the changelog")
+	newCard(board.ID, "Rollback template", nil, true)
+	newCard(otherBoard.ID, "Rollback on another board", nil, false)
+
+	search := func(terms string, boardIDs ...string) []*model.CardSearchResult {
+		if len(boardIDs) == 0 {
+			boardIDs = []string{board.ID}
+		}
+		results, err := store.SearchCards(model.CardSearchOptions{
+			Terms:    model.SplitSearchTerms(terms),
+			BoardIDs: boardIDs,
+		})
+		require.NoError(t, err)
+		return results
+	}
+
+	blockIDs := func(results []*model.CardSearchResult) []string {
+		ids := make([]string, 0, len(results))
+		for _, r := range results {
+			ids = append(ids, r.BlockID)
+		}
+		return ids
+	}
+
+	t.Run("matches card titles and property values", func(t *testing.T) {
+		results := search("rollback")
+		assert.ElementsMatch(t, []string{cardA.ID, cardB.ID}, blockIDs(results))
+
+		for _, result := range results {
+			assert.Equal(t, board.ID, result.BoardID)
+			assert.Equal(t, result.BlockID, result.CardID)
+			assert.Equal(t, model.BlockType(model.TypeCard), result.BlockType)
+			assert.Contains(t, result.Snippet, "<mark>rollback</mark>")
+			if result.CardID == cardB.ID {
+				assert.Equal(t, "Release notes", result.CardTitle)
+				assert.Contains(t, result.Snippet, "&lt;b&gt;")
+				assert.NotContains(t, result.Snippet, "<b>")
+			}
+		}
+
+		assert.Equal(t, []string{cardA.ID}, blockIDs(search("upstream")))
+	})
+
+	t.Run("matches content blocks", func(t *testing.T) {
+		results := search("migration crash")
+		require.Len(t, results, 1)
+		assert.Equal(t, textA.ID, results[0].BlockID)
+		assert.Equal(t, cardA.ID, results[0].CardID)
+		assert.Equal(t, "Fix the rollback", results[0].CardTitle)
+		assert.Equal(t, model.BlockType(model.TypeText), results[0].BlockType)
+		assert.Contains(t, results[0].Snippet, "<mark>migration</mark>")
+		assert.Contains(t, results[0].Snippet, "<mark>crash</mark>")
+	})
+
+	t.Run("all terms must match", func(t *testing.T) {
+		assert.Empty(t, search("migration changelog"))
+		assert.Equal(t, []string{checkboxB.ID}, blockIDs(search("publish changelog")))
+	})
+
+	t.Run("matches word prefixes", func(t *testing.T) {
+		assert.Equal(t, []string{checkboxB.ID}, blockIDs(search("changel")))
+	})
+
+	t.Run("only searches in the given boards", func(t *testing.T) {
+		assert.Len(t, search("rollback", board.ID, otherBoard.ID), 3)
+		assert.Empty(t, search("rollback", utils.NewID(utils.IDTypeBoard)))
+		assert.Empty(t, search(""))
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		results, err := store.SearchCards(model.CardSearchOptions{
+			Terms:    []string{"rollback"},
+			BoardIDs: []string{board.ID},
+			Page:     1,
+			PerPage:  1,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+	})
+
+	t.Run("index follows patches and deletions", func(t *testing.T) {
+		title := "Capture the crash logs"
+		require.NoError(t, store.PatchBlock(textA.ID, &model.BlockPatch{Title: &title}, userID))
+		assert.Empty(t, search("migration"))
+		assert.Equal(t, []string{textA.ID}, blockIDs(search("logs")))
+
+		require.NoError(t, store.DeleteBlock(cardA.ID, userID))
+		assert.Empty(t, search("logs"))
+		assert.Equal(t, []string{cardB.ID}, blockIDs(search("rollback")))
+
+		require.NoError(t, store.UndeleteBlock(cardA.ID, userID))
+		assert.Equal(t, []string{textA.ID}, blockIDs(search("logs")))
+		assert.Len(t, search("rollback"), 2)
+	})
+
+	t.Run("index follows board schema changes", func(t *testing.T) {
+		_, err := store.PatchBoard(board.ID, &model.BoardPatch{
+			DeletedCardProperties: []string{"notes"},
+		}, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{cardA.ID}, blockIDs(search("rollback")))
+
+		require.NoError(t, store.DeleteBoard(board.ID, userID))
+		assert.Empty(t, search("rollback"))
+	})
+}
@@ -36,6 +36,18 @@ func StoreTestDataRetention(t *testing.T, setup func(t *testing.T) (store.Store,
 		testRunDataRetention(t, store, 2)
 		testRunDataRetention(t, store, 10)
 	})
+
+	t.Run("RunDataRetention with policies", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRunDataRetentionWithPolicies(t, store)
+	})
+
+	t.Run("RetentionPolicies", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRetentionPolicies(t, store)
+	})
 }
 
 func LoadData(t *testing.T, store store.Store) {
@@ -105,15 +117,21 @@ func testRunDataRetention(t *testing.T, store store.Store, batchSize int) {
 	initialCount := len(blocks)
 
 	t.Run("test no deletions", func(t *testing.T) {
-		deletions, err := store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(-time.Hour*1)), int64(batchSize))
+		report, err := store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(-time.Hour*1)), int64(batchSize))
 		require.NoError(t, err)
-		require.Equal(t, int64(0), deletions)
+		require.Equal(t, int64(0), report.TotalDeleted)
+		require.Empty(t, report.Boards)
 	})
 
 	t.Run("test all deletions", func(t *testing.T) {
-		deletions, err := store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(time.Hour*1)), int64(batchSize))
+		report, err := store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(time.Hour*1)), int64(batchSize))
 		require.NoError(t, err)
-		require.True(t, deletions > int64(initialCount))
+		require.True(t, report.TotalDeleted > int64(initialCount))
+		require.Len(t, report.Boards, 1)
+		require.Equal(t, boardID, report.Boards[0].BoardID)
+		require.Equal(t, model.RetentionPolicyScopeGlobal, report.Boards[0].Scope)
+		require.Equal(t, int64(initialCount), report.Boards[0].DeletedRows["blocks"])
+		require.Equal(t, report.TotalDeleted, report.Boards[0].TotalDeleted)
 
 		// expect all blocks to be deleted.
 		blocks, errBlocks := store.GetBlocksForBoard(boardID)
@@ -137,3 +155,96 @@ func testRunDataRetention(t *testing.T, store store.Store, batchSize int) {
 		require.Empty(t, category)
 	})
 }
+
+func createRetentionTestBoard(t *testing.T, store store.Store, id, teamID string) {
+	_, err := store.InsertBoard(&model.Board{ID: id, TeamID: teamID, Type: model.BoardTypeOpen}, testUserID)
+	require.NoError(t, err)
+	err = store.InsertBlock(&model.Block{ID: id + "-card", BoardID: id, Type: model.TypeCard, ModifiedBy: testUserID}, testUserID)
+	require.NoError(t, err)
+}
+
+func testRunDataRetentionWithPolicies(t *testing.T, store store.Store) {
+	// the boards of team-1 are kept for a day, and the ones of team-2
+	// follow the global retention date
+	createRetentionTestBoard(t, store, "board-team-policy", "team-1")
+	createRetentionTestBoard(t, store, "board-legal-hold", "team-1")
+	createRetentionTestBoard(t, store, "board-global", "team-2")
+	createRetentionTestBoard(t, store, "board-keep-forever", "team-2")
+
+	policies := []*model.RetentionPolicy{
+		{TeamID: "team-1", RetentionDays: 1, ModifiedBy: testUserID},
+		{TeamID: "team-1", BoardID: "board-legal-hold", RetentionDays: 1, LegalHold: true, ModifiedBy: testUserID},
+		{TeamID: "team-2", BoardID: "board-keep-forever", RetentionDays: 0, ModifiedBy: testUserID},
+	}
+	for _, policy := range policies {
+		_, err := store.SaveRetentionPolicy(policy)
+		require.NoError(t, err)
+	}
+
+	report, err := store.RunDataRetention(utils.GetMillisForTime(time.Now().Add(time.Hour)), 0)
+	require.NoError(t, err)
+	require.Len(t, report.Boards, 1)
+	require.Equal(t, "board-global", report.Boards[0].BoardID)
+	require.Equal(t, "team-2", report.Boards[0].TeamID)
+	require.Equal(t, model.RetentionPolicyScopeGlobal, report.Boards[0].Scope)
+	require.Equal(t, int64(1), report.Boards[0].DeletedRows["blocks"])
+	require.Equal(t, []string{"board-legal-hold"}, report.LegalHoldBoardIDs)
+
+	for _, id := range []string{"board-team-policy", "board-legal-hold", "board-keep-forever"} {
+		_, err = store.GetBoard(id)
+		require.NoError(t, err, id)
+	}
+	_, err = store.GetBoard("board-global")
+	require.True(t, model.IsErrNotFound(err))
+
+	// without a global retention date, only the policies apply
+	report, err = store.RunDataRetention(0, 0)
+	require.NoError(t, err)
+	require.Empty(t, report.Boards)
+}
+
+func testRetentionPolicies(t *testing.T, store store.Store) {
+	t.Run("invalid policies are rejected", func(t *testing.T) {
+		_, err := store.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, RetentionDays: -1})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = store.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, LegalHold: true})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("save, replace and delete policies", func(t *testing.T) {
+		teamPolicy, err := store.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, RetentionDays: 30, ModifiedBy: testUserID})
+		require.NoError(t, err)
+		require.NotZero(t, teamPolicy.UpdateAt)
+
+		_, err = store.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, BoardID: boardID, LegalHold: true, ModifiedBy: testUserID})
+		require.NoError(t, err)
+
+		_, err = store.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: "other-team", RetentionDays: 10, ModifiedBy: testUserID})
+		require.NoError(t, err)
+
+		policies, err := store.GetRetentionPolicies(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+		require.Empty(t, policies[0].BoardID)
+		require.Equal(t, 30, policies[0].RetentionDays)
+		require.Equal(t, boardID, policies[1].BoardID)
+		require.True(t, policies[1].LegalHold)
+
+		_, err = store.SaveRetentionPolicy(&model.RetentionPolicy{TeamID: testTeamID, RetentionDays: 90, ModifiedBy: testUserID})
+		require.NoError(t, err)
+
+		policies, err = store.GetRetentionPolicies(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+		require.Equal(t, 90, policies[0].RetentionDays)
+
+		require.NoError(t, store.DeleteRetentionPolicy(testTeamID, boardID))
+		err = store.DeleteRetentionPolicy(testTeamID, boardID)
+		require.True(t, model.IsErrNotFound(err))
+
+		policies, err = store.GetRetentionPolicies(testTeamID)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestDueDateReminderStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("DueDateReminderSettings", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDueDateReminderSettings(t, store)
+	})
+	t.Run("DueDateReminders", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDueDateReminders(t, store)
+	})
+}
+
+func testDueDateReminderSettings(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		_, err := store.SaveDueDateReminderSettings(&model.DueDateReminderSettings{
+			BoardID: "board-1", DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{1, 1},
+		})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = store.SaveDueDateReminderSettings(&model.DueDateReminderSettings{
+			BoardID: "board-1", DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{0}, Hour: 24,
+		})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	_, err := store.GetDueDateReminderSettings("board-1")
+	require.True(t, model.IsErrNotFound(err))
+
+	for _, settings := range []*model.DueDateReminderSettings{
+		{BoardID: "board-1", DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{1, 0}, Hour: 9, Enabled: true, ModifiedBy: userID},
+		{BoardID: "board-2", DatePropertyID: "due", AssigneePropertyID: "owner", DaysBefore: []int{0}, Hour: 8, Enabled: false, ModifiedBy: userID},
+	} {
+		saved, err := store.SaveDueDateReminderSettings(settings)
+		require.NoError(t, err)
+		require.NotZero(t, saved.UpdateAt)
+	}
+
+	settings, err := store.GetDueDateReminderSettings("board-1")
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 0}, settings.DaysBefore)
+	require.Equal(t, 9, settings.Hour)
+	require.True(t, settings.Enabled)
+
+	t.Run("save replaces the settings of the board", func(t *testing.T) {
+		settings.DaysBefore = []int{3}
+		settings.Enabled = false
+		_, err := store.SaveDueDateReminderSettings(settings)
+		require.NoError(t, err)
+
+		saved, err := store.GetDueDateReminderSettings("board-1")
+		require.NoError(t, err)
+		require.Equal(t, []int{3}, saved.DaysBefore)
+		require.False(t, saved.Enabled)
+	})
+
+	t.Run("get the enabled settings", func(t *testing.T) {
+		enabled, err := store.GetEnabledDueDateReminderSettings()
+		require.NoError(t, err)
+		require.Empty(t, enabled)
+
+		settings.Enabled = true
+		_, err = store.SaveDueDateReminderSettings(settings)
+		require.NoError(t, err)
+
+		enabled, err = store.GetEnabledDueDateReminderSettings()
+		require.NoError(t, err)
+		require.Len(t, enabled, 1)
+		require.Equal(t, "board-1", enabled[0].BoardID)
+	})
+
+	t.Run("delete the settings", func(t *testing.T) {
+		require.NoError(t, store.DeleteDueDateReminderSettings("board-1"))
+		require.True(t, model.IsErrNotFound(store.DeleteDueDateReminderSettings("board-1")))
+
+		_, err := store.GetDueDateReminderSettings("board-1")
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testDueDateReminders(t *testing.T, store store.Store) {
+	boardID := testBoardID
+	userID := testUserID
+
+	reminder := &model.DueDateReminder{BoardID: boardID, CardID: "card-1", UserID: userID, DueAt: 1000, DaysBefore: 1, SentAt: 500}
+	inserted, err := store.InsertDueDateReminder(reminder)
+	require.NoError(t, err)
+	require.True(t, inserted)
+
+	// a reminder is sent once
+	inserted, err = store.InsertDueDateReminder(reminder)
+	require.NoError(t, err)
+	require.False(t, inserted)
+
+	// another reminder of the same card
+	inserted, err = store.InsertDueDateReminder(&model.DueDateReminder{BoardID: boardID, CardID: "card-1", UserID: userID, DueAt: 1000, DaysBefore: 0, SentAt: 900})
+	require.NoError(t, err)
+	require.True(t, inserted)
+
+	t.Run("purged cards forget their reminders", func(t *testing.T) {
+		block := &model.Block{ID: "card-1", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, ModifiedBy: userID}
+		require.NoError(t, store.InsertBlock(block, userID))
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(t, store.DeleteBlock("card-1", userID))
+		require.NoError(t, store.PurgeBlock("card-1"))
+
+		inserted, err := store.InsertDueDateReminder(reminder)
+		require.NoError(t, err)
+		require.True(t, inserted)
+	})
+}
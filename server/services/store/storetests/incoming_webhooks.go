@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestIncomingWebhookStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("IncomingWebhooks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testIncomingWebhooks(t, store)
+	})
+}
+
+func testIncomingWebhooks(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	t.Run("invalid webhooks are rejected", func(t *testing.T) {
+		_, err := store.InsertIncomingWebhook(&model.IncomingWebhook{BoardID: "board-1"})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	webhook, err := store.InsertIncomingWebhook(&model.IncomingWebhook{
+		BoardID: "board-1",
+		Name:    "Alerts",
+		Token:   "token",
+		Mapping: model.IncomingWebhookMapping{
+			Title:              "{{payload.alert}}",
+			Properties:         map[string]interface{}{"status": "{{payload.status}}", "tags": []interface{}{"alert"}},
+			ExternalIDProperty: "ext",
+			ExternalID:         "{{payload.id}}",
+		},
+		CreatedBy:  userID,
+		ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, webhook.ID)
+	require.NotZero(t, webhook.CreateAt)
+
+	other, err := store.InsertIncomingWebhook(&model.IncomingWebhook{
+		BoardID: "board-2", Token: "other-token", Mapping: model.IncomingWebhookMapping{Title: "{{payload.title}}"}, CreatedBy: userID, ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("get webhooks", func(t *testing.T) {
+		fetched, err := store.GetIncomingWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, webhook, fetched)
+
+		webhooks, err := store.GetIncomingWebhooksForBoard("board-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.IncomingWebhook{webhook}, webhooks)
+
+		webhooks, err = store.GetIncomingWebhooksForBoard("board-2")
+		require.NoError(t, err)
+		require.Equal(t, []*model.IncomingWebhook{other}, webhooks)
+
+		_, err = store.GetIncomingWebhook("missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("update a webhook", func(t *testing.T) {
+		webhook.Name = "Monitoring"
+		webhook.Disabled = true
+		webhook.Mapping.Content = "{{payload.description}}"
+		updated, err := store.UpdateIncomingWebhook(webhook)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, updated.UpdateAt, webhook.UpdateAt)
+
+		fetched, err := store.GetIncomingWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Monitoring", fetched.Name)
+		require.True(t, fetched.Disabled)
+		require.Equal(t, webhook.Mapping, fetched.Mapping)
+		require.Equal(t, "token", fetched.Token)
+
+		_, err = store.UpdateIncomingWebhook(&model.IncomingWebhook{ID: "missing", BoardID: "board-1", Mapping: model.IncomingWebhookMapping{Title: "title"}})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("delete a webhook", func(t *testing.T) {
+		require.NoError(t, store.DeleteIncomingWebhook(webhook.ID))
+		_, err := store.GetIncomingWebhook(webhook.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		require.True(t, model.IsErrNotFound(store.DeleteIncomingWebhook(webhook.ID)))
+	})
+}
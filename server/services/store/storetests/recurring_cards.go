@@ -0,0 +1,155 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestRecurringCardRuleStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("RecurringCardRules", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testRecurringCardRules(t, store)
+	})
+	t.Run("DueRecurringCardRules", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDueRecurringCardRules(t, store)
+	})
+}
+
+func testRecurringCardRules(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	t.Run("invalid rules are rejected", func(t *testing.T) {
+		_, err := store.InsertRecurringCardRule(&model.RecurringCardRule{BoardID: "board-1", TemplateID: "template-1", Schedule: "every monday"})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = store.InsertRecurringCardRule(&model.RecurringCardRule{BoardID: "board-1", TemplateID: "template-1", Schedule: "@weekly", Timezone: "Mars/Olympus"})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	rule, err := store.InsertRecurringCardRule(&model.RecurringCardRule{
+		BoardID:    "board-1",
+		TemplateID: "template-1",
+		Schedule:   "0 9 * * mon",
+		Timezone:   "Europe/Paris",
+		Overrides: model.RecurringCardOverrides{
+			Title:       "Weekly ops",
+			Properties:  map[string]interface{}{"status": "todo"},
+			DateOffsets: map[string]int{"due": 4},
+		},
+		CreatedBy:  userID,
+		ModifiedBy: userID,
+		NextRunAt:  1000,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, rule.ID)
+	require.NotZero(t, rule.CreateAt)
+
+	_, err = store.InsertRecurringCardRule(&model.RecurringCardRule{
+		BoardID: "board-2", TemplateID: "template-2", Schedule: "@daily", CreatedBy: userID, ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("get rules", func(t *testing.T) {
+		rules, err := store.GetRecurringCardRulesForBoard("board-1")
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule, rules[0])
+
+		fetched, err := store.GetRecurringCardRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, rule, fetched)
+		require.Equal(t, "todo", fetched.Overrides.Properties["status"])
+		require.Equal(t, 4, fetched.Overrides.DateOffsets["due"])
+
+		_, err = store.GetRecurringCardRule("missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("update a rule", func(t *testing.T) {
+		rule.Paused = true
+		rule.NextRunAt = 0
+		rule.Overrides = model.RecurringCardOverrides{}
+		updated, err := store.UpdateRecurringCardRule(rule)
+		require.NoError(t, err)
+
+		fetched, err := store.GetRecurringCardRule(rule.ID)
+		require.NoError(t, err)
+		require.Equal(t, updated, fetched)
+		require.True(t, fetched.Paused)
+		require.Empty(t, fetched.Overrides.Properties)
+
+		_, err = store.UpdateRecurringCardRule(&model.RecurringCardRule{ID: "missing", BoardID: "board-1", TemplateID: "template-1", Schedule: "@daily"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("delete a rule", func(t *testing.T) {
+		require.NoError(t, store.DeleteRecurringCardRule(rule.ID))
+		require.True(t, model.IsErrNotFound(store.DeleteRecurringCardRule(rule.ID)))
+
+		rules, err := store.GetRecurringCardRulesForBoard("board-1")
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
+
+func testDueRecurringCardRules(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	insert := func(nextRunAt int64, paused bool) *model.RecurringCardRule {
+		rule, err := store.InsertRecurringCardRule(&model.RecurringCardRule{
+			BoardID: "board-1", TemplateID: "template-1", Schedule: "@hourly",
+			Paused: paused, CreatedBy: userID, ModifiedBy: userID, NextRunAt: nextRunAt,
+		})
+		require.NoError(t, err)
+		return rule
+	}
+	later := insert(2000, false)
+	due := insert(1000, false)
+	insert(1000, true)
+	insert(0, false)
+
+	rules, err := store.GetDueRecurringCardRules(1500, 0)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, due.ID, rules[0].ID)
+
+	rules, err = store.GetDueRecurringCardRules(2000, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, due.ID, rules[0].ID)
+
+	t.Run("a run is claimed once", func(t *testing.T) {
+		claimed, err := store.ClaimRecurringCardRuleRun(due.ID, 1000, 5000)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		claimed, err = store.ClaimRecurringCardRuleRun(due.ID, 1000, 5000)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		rules, err := store.GetDueRecurringCardRules(2000, 0)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, later.ID, rules[0].ID)
+	})
+
+	t.Run("record the last run", func(t *testing.T) {
+		require.NoError(t, store.UpdateRecurringCardRuleLastRun(due.ID, 1000, "card-1", "boom"))
+
+		rule, err := store.GetRecurringCardRule(due.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), rule.LastRunAt)
+		require.Equal(t, "card-1", rule.LastCardID)
+		require.Equal(t, "boom", rule.LastError)
+		require.Equal(t, int64(5000), rule.NextRunAt)
+	})
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestTrashStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetDeletedBoardsForTeam", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDeletedBoardsForTeam(t, store)
+	})
+	t.Run("GetDeletedBlocksForBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetDeletedBlocksForBoard(t, store)
+	})
+	t.Run("PurgeBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeBoard(t, store)
+	})
+	t.Run("PurgeBlock", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPurgeBlock(t, store)
+	})
+}
+
+func testGetDeletedBoardsForTeam(t *testing.T, store store.Store) {
+	teamID := testTeamID
+	userID := testUserID
+
+	for _, id := range []string{"board-1", "board-2", "board-3"} {
+		_, _, err := store.InsertBoardWithAdmin(&model.Board{ID: id, TeamID: teamID, Type: model.BoardTypeOpen, Title: id}, userID)
+		require.NoError(t, err)
+	}
+	_, err := store.InsertBoard(&model.Board{ID: "other-team-board", TeamID: "other-team", Type: model.BoardTypeOpen}, userID)
+	require.NoError(t, err)
+
+	t.Run("no deleted boards", func(t *testing.T) {
+		boards, err := store.GetDeletedBoardsForTeam(teamID)
+		require.NoError(t, err)
+		require.Empty(t, boards)
+	})
+
+	require.NoError(t, store.DeleteBoard("board-1", "deleter-1"))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, store.DeleteBoard("board-2", "deleter-2"))
+	require.NoError(t, store.DeleteBoard("other-team-board", userID))
+
+	t.Run("deleted boards, newest first", func(t *testing.T) {
+		boards, err := store.GetDeletedBoardsForTeam(teamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 2)
+		require.Equal(t, "board-2", boards[0].ID)
+		require.Equal(t, "deleter-2", boards[0].ModifiedBy)
+		require.NotZero(t, boards[0].DeleteAt)
+		require.Equal(t, "board-1", boards[1].ID)
+		require.Equal(t, "deleter-1", boards[1].ModifiedBy)
+		require.Equal(t, "board-1", boards[1].Title)
+	})
+
+	t.Run("undeleted boards are not in the trash", func(t *testing.T) {
+		require.NoError(t, store.UndeleteBoard("board-1", userID))
+
+		boards, err := store.GetDeletedBoardsForTeam(teamID)
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, "board-2", boards[0].ID)
+	})
+}
+
+func testGetDeletedBlocksForBoard(t *testing.T, store store.Store) {
+	boardID := testBoardID
+	userID := testUserID
+
+	blocks := []*model.Block{
+		{ID: "card-1", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, Title: "card 1"},
+		{ID: "card-2", BoardID: boardID, ParentID: boardID, Type: model.TypeCard, Title: "card 2"},
+		{ID: "view-1", BoardID: boardID, ParentID: boardID, Type: model.TypeView, Title: "view 1"},
+		{ID: "text-1", BoardID: boardID, ParentID: "card-1", Type: model.TypeText, Title: "text 1"},
+	}
+	for _, block := range blocks {
+		block.ModifiedBy = userID
+	}
+	require.NoError(t, store.InsertBlocks(blocks, userID))
+
+	require.NoError(t, store.DeleteBlock("card-1", "deleter"))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, store.DeleteBlock("view-1", "deleter"))
+
+	t.Run("all deleted blocks", func(t *testing.T) {
+		deleted, err := store.GetDeletedBlocksForBoard(boardID, nil)
+		require.NoError(t, err)
+
+		ids := []string{}
+		for _, block := range deleted {
+			require.Equal(t, "deleter", block.ModifiedBy)
+			require.NotZero(t, block.DeleteAt)
+			ids = append(ids, block.ID)
+		}
+		require.ElementsMatch(t, []string{"card-1", "view-1", "text-1"}, ids)
+	})
+
+	t.Run("deleted blocks by type, newest first", func(t *testing.T) {
+		deleted, err := store.GetDeletedBlocksForBoard(boardID, []model.BlockType{model.TypeCard, model.TypeView})
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		require.Equal(t, "view-1", deleted[0].ID)
+		require.Equal(t, "card-1", deleted[1].ID)
+		require.Equal(t, "card 1", deleted[1].Title)
+	})
+
+	t.Run("undeleted blocks are not in the trash", func(t *testing.T) {
+		require.NoError(t, store.UndeleteBlock("card-1", userID))
+
+		deleted, err := store.GetDeletedBlocksForBoard(boardID, []model.BlockType{model.TypeCard, model.TypeView})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, "view-1", deleted[0].ID)
+	})
+}
+
+func testPurgeBoard(t *testing.T, store store.Store) {
+	teamID := testTeamID
+	userID := testUserID
+
+	board, _, err := store.InsertBoardWithAdmin(&model.Board{ID: "board-id", TeamID: teamID, Type: model.BoardTypeOpen}, userID)
+	require.NoError(t, err)
+	_, _, err = store.InsertBoardWithAdmin(&model.Board{ID: "other-board-id", TeamID: teamID, Type: model.BoardTypeOpen}, userID)
+	require.NoError(t, err)
+
+	blocks := []*model.Block{
+		{ID: "card-id", BoardID: board.ID, ParentID: board.ID, Type: model.TypeCard, ModifiedBy: userID},
+		{ID: "other-card-id", BoardID: "other-board-id", ParentID: "other-board-id", Type: model.TypeCard, ModifiedBy: userID},
+	}
+	require.NoError(t, store.InsertBlocks(blocks, userID))
+
+	t.Run("live boards can't be purged", func(t *testing.T) {
+		err := store.PurgeBoard(board.ID)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	require.NoError(t, store.DeleteBoard(board.ID, userID))
+
+	t.Run("purge a deleted board", func(t *testing.T) {
+		require.NoError(t, store.PurgeBoard(board.ID))
+
+		boards, err := store.GetDeletedBoardsForTeam(teamID)
+		require.NoError(t, err)
+		require.Empty(t, boards)
+
+		history, err := store.GetBoardHistory(board.ID, model.QueryBoardHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, history)
+
+		blockHistory, err := store.GetBlockHistoryDescendants(board.ID, model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, blockHistory)
+
+		members, err := store.GetMembersForBoard(board.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+
+		// the undelete of a purged board is a no-op
+		require.NoError(t, store.UndeleteBoard(board.ID, userID))
+		_, err = store.GetBoard(board.ID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("other boards are untouched", func(t *testing.T) {
+		_, err := store.GetBoard("other-board-id")
+		require.NoError(t, err)
+
+		_, err = store.GetBlock("other-card-id")
+		require.NoError(t, err)
+
+		members, err := store.GetMembersForBoard("other-board-id")
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+	})
+}
+
+func testPurgeBlock(t *testing.T, store store.Store) {
+	boardID := testBoardID
+	userID := testUserID
+
+	blocks := []*model.Block{
+		{ID: "card-id", BoardID: boardID, ParentID: boardID, Type: model.TypeCard},
+		{ID: "text-id", BoardID: boardID, ParentID: "card-id", Type: model.TypeText},
+		{ID: "other-card-id", BoardID: boardID, ParentID: boardID, Type: model.TypeCard},
+	}
+	for _, block := range blocks {
+		block.ModifiedBy = userID
+	}
+	require.NoError(t, store.InsertBlocks(blocks, userID))
+
+	t.Run("live blocks can't be purged", func(t *testing.T) {
+		err := store.PurgeBlock("card-id")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	require.NoError(t, store.DeleteBlock("card-id", userID))
+
+	t.Run("purge a deleted block and its children", func(t *testing.T) {
+		require.NoError(t, store.PurgeBlock("card-id"))
+
+		for _, id := range []string{"card-id", "text-id"} {
+			history, err := store.GetBlockHistory(id, model.QueryBlockHistoryOptions{})
+			require.NoError(t, err)
+			require.Empty(t, history)
+		}
+
+		deleted, err := store.GetDeletedBlocksForBoard(boardID, nil)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+
+	t.Run("other blocks are untouched", func(t *testing.T) {
+		block, err := store.GetBlock("other-card-id")
+		require.NoError(t, err)
+		require.Equal(t, "other-card-id", block.ID)
+
+		history, err := store.GetBlockHistory("other-card-id", model.QueryBlockHistoryOptions{})
+		require.NoError(t, err)
+		require.NotEmpty(t, history)
+	})
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package storetests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+
+	"github.com/stretchr/testify/require"
+)
+
+func StoreTestWebhookStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("Webhooks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testWebhooks(t, store)
+	})
+	t.Run("WebhookDeliveries", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testWebhookDeliveries(t, store)
+	})
+}
+
+func testWebhooks(t *testing.T, store store.Store) {
+	userID := testUserID
+
+	t.Run("invalid webhooks are rejected", func(t *testing.T) {
+		_, err := store.InsertWebhook(&model.Webhook{TeamID: "team-1", URL: "ftp://example.com"})
+		require.True(t, model.IsErrBadRequest(err))
+
+		_, err = store.InsertWebhook(&model.Webhook{TeamID: "team-1", URL: "https://example.com", EventTypes: []string{"card.deleted"}})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	boardWebhook, err := store.InsertWebhook(&model.Webhook{
+		TeamID:     "team-1",
+		BoardID:    "board-1",
+		URL:        "https://example.com/board",
+		Secret:     "secret",
+		EventTypes: []string{model.WebhookEventCardCreated},
+		CreatedBy:  userID,
+		ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, boardWebhook.ID)
+	require.NotZero(t, boardWebhook.CreateAt)
+
+	teamWebhook, err := store.InsertWebhook(&model.Webhook{
+		TeamID: "team-1", URL: "https://example.com/team", Secret: "secret", CreatedBy: userID, ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{}, teamWebhook.EventTypes)
+
+	_, err = store.InsertWebhook(&model.Webhook{
+		TeamID: "team-2", BoardID: "board-2", URL: "https://example.com/other", Secret: "secret", CreatedBy: userID, ModifiedBy: userID,
+	})
+	require.NoError(t, err)
+
+	t.Run("get webhooks", func(t *testing.T) {
+		webhooks, err := store.GetWebhooksForBoard("board-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.Webhook{boardWebhook}, webhooks)
+
+		webhooks, err = store.GetWebhooksForTeam("team-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.Webhook{teamWebhook}, webhooks)
+
+		fetched, err := store.GetWebhook(boardWebhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, boardWebhook, fetched)
+
+		_, err = store.GetWebhook("missing")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("get the active webhooks of a board", func(t *testing.T) {
+		webhooks, err := store.GetActiveWebhooks("team-1", "board-1")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*model.Webhook{boardWebhook, teamWebhook}, webhooks)
+
+		// the other boards of the team only get the team webhooks
+		webhooks, err = store.GetActiveWebhooks("team-1", "board-3")
+		require.NoError(t, err)
+		require.Equal(t, []*model.Webhook{teamWebhook}, webhooks)
+	})
+
+	t.Run("update a webhook", func(t *testing.T) {
+		boardWebhook.Disabled = true
+		boardWebhook.EventTypes = []string{model.WebhookEventCommentAdded, model.WebhookEventMemberAdded}
+		updated, err := store.UpdateWebhook(boardWebhook)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, updated.UpdateAt, boardWebhook.UpdateAt)
+
+		fetched, err := store.GetWebhook(boardWebhook.ID)
+		require.NoError(t, err)
+		require.True(t, fetched.Disabled)
+		require.Equal(t, boardWebhook.EventTypes, fetched.EventTypes)
+		require.Equal(t, "secret", fetched.Secret)
+
+		webhooks, err := store.GetActiveWebhooks("team-1", "board-1")
+		require.NoError(t, err)
+		require.Equal(t, []*model.Webhook{teamWebhook}, webhooks)
+
+		_, err = store.UpdateWebhook(&model.Webhook{ID: "missing", TeamID: "team-1", URL: "https://example.com"})
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("delete a webhook and its deliveries", func(t *testing.T) {
+		delivery, err := store.InsertWebhookDelivery(&model.WebhookDelivery{
+			WebhookID: teamWebhook.ID, BoardID: "board-1", Event: model.WebhookEventCardCreated, Payload: json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteWebhook(teamWebhook.ID))
+		_, err = store.GetWebhook(teamWebhook.ID)
+		require.True(t, model.IsErrNotFound(err))
+		_, err = store.GetWebhookDelivery(delivery.ID)
+		require.True(t, model.IsErrNotFound(err))
+
+		require.True(t, model.IsErrNotFound(store.DeleteWebhook(teamWebhook.ID)))
+	})
+}
+
+func testWebhookDeliveries(t *testing.T, store store.Store) {
+	newDelivery := func(webhookID string, nextAttemptAt int64) *model.WebhookDelivery {
+		delivery, err := store.InsertWebhookDelivery(&model.WebhookDelivery{
+			WebhookID:     webhookID,
+			BoardID:       "board-1",
+			Event:         model.WebhookEventCardCreated,
+			Payload:       json.RawMessage(`{"event":"card.created"}`),
+			NextAttemptAt: nextAttemptAt,
+		})
+		require.NoError(t, err)
+		return delivery
+	}
+
+	first := newDelivery("webhook-1", 1000)
+	second := newDelivery("webhook-1", 2000)
+	other := newDelivery("webhook-2", 1000)
+	require.Equal(t, model.WebhookDeliveryPending, first.Status)
+
+	t.Run("get deliveries", func(t *testing.T) {
+		fetched, err := store.GetWebhookDelivery(first.ID)
+		require.NoError(t, err)
+		require.Equal(t, first, fetched)
+		require.JSONEq(t, `{"event":"card.created"}`, string(fetched.Payload))
+
+		_, err = store.GetWebhookDelivery("missing")
+		require.True(t, model.IsErrNotFound(err))
+
+		deliveries, err := store.GetWebhookDeliveries(model.QueryWebhookDeliveriesOptions{WebhookID: "webhook-1"})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+
+		deliveries, err = store.GetWebhookDeliveries(model.QueryWebhookDeliveriesOptions{WebhookID: "webhook-1", PerPage: 1, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+	})
+
+	t.Run("get due deliveries", func(t *testing.T) {
+		deliveries, err := store.GetDueWebhookDeliveries(1500, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		require.ElementsMatch(t, []string{first.ID, other.ID}, []string{deliveries[0].ID, deliveries[1].ID})
+
+		deliveries, err = store.GetDueWebhookDeliveries(5000, 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+	})
+
+	t.Run("claim a delivery", func(t *testing.T) {
+		claimed, err := store.ClaimWebhookDelivery(first.ID, 1000, 10000)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// already claimed
+		claimed, err = store.ClaimWebhookDelivery(first.ID, 1000, 10000)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		deliveries, err := store.GetDueWebhookDeliveries(1500, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, other.ID, deliveries[0].ID)
+	})
+
+	t.Run("record attempts", func(t *testing.T) {
+		second.RecordAttempt(3000, 200, "ok", nil)
+		require.NoError(t, store.UpdateWebhookDelivery(second))
+
+		fetched, err := store.GetWebhookDelivery(second.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliverySuccess, fetched.Status)
+		require.Equal(t, 1, fetched.Attempts)
+		require.Equal(t, 200, fetched.ResponseCode)
+		require.Equal(t, "ok", fetched.ResponseBody)
+		require.Zero(t, fetched.NextAttemptAt)
+
+		// a delivery that succeeded can't be claimed
+		claimed, err := store.ClaimWebhookDelivery(second.ID, 0, 10000)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		deliveries, err := store.GetWebhookDeliveries(model.QueryWebhookDeliveriesOptions{WebhookID: "webhook-1", Status: model.WebhookDeliverySuccess})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, second.ID, deliveries[0].ID)
+
+		require.True(t, model.IsErrNotFound(store.UpdateWebhookDelivery(&model.WebhookDelivery{ID: "missing"})))
+	})
+
+	t.Run("delete old deliveries", func(t *testing.T) {
+		deleted, err := store.DeleteWebhookDeliveriesBefore(second.CreateAt + 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		// the pending deliveries are kept
+		_, err = store.GetWebhookDelivery(first.ID)
+		require.NoError(t, err)
+		_, err = store.GetWebhookDelivery(second.ID)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
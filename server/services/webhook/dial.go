@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// ErrInternalAddress is returned when an outgoing webhook resolves to an
+// internal address that isn't allowed.
+var ErrInternalAddress = errors.New("the webhook URL resolves to an internal address")
+
+// newDeliveryClient returns the HTTP client of the deliveries of the
+// outgoing webhooks. The addresses are checked when connecting, after the
+// host was resolved, so a host can't be changed to resolve to an internal
+// address once the webhook was created. No proxy is used, as the proxy
+// would be the only address checked.
+func newDeliveryClient(allowedIPRanges []string, logger mlog.LoggerIFace) *http.Client {
+	allowed := parseIPRanges(allowedIPRanges, logger)
+	dialer := &net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("invalid address %s: %w", address, ErrInternalAddress)
+			}
+			if isInternalIP(ip) && !containsIP(allowed, ip) {
+				return fmt.Errorf("%s: %w", ip, ErrInternalAddress)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: deliveryTimeout, Transport: transport}
+}
+
+// isInternalIP returns true for the loopback, private, link-local and
+// unspecified addresses.
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// parseIPRanges parses a list of IP addresses and CIDR ranges. The invalid
+// ones are logged and ignored.
+func parseIPRanges(ranges []string, logger mlog.LoggerIFace) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(ranges))
+	for _, r := range ranges {
+		r = strings.TrimSpace(r)
+		if !strings.Contains(r, "/") {
+			if ip := net.ParseIP(r); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip = ip.To4()
+					bits = 8 * net.IPv4len
+				}
+				nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(r)
+		if err != nil {
+			logger.Warn("ignoring invalid webhook allowed IP range", mlog.String("range", r))
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,9 +2,15 @@ package webhook
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/config"
@@ -12,6 +18,21 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+const (
+	// deliveryTimeout is the time an outgoing webhook has to respond.
+	deliveryTimeout = 10 * time.Second
+
+	userAgent = "Focalboard-Webhook"
+)
+
+// The headers of the requests of the outgoing webhooks.
+const (
+	HeaderEvent     = "X-Focalboard-Event"
+	HeaderDelivery  = "X-Focalboard-Delivery"
+	HeaderTimestamp = "X-Focalboard-Timestamp"
+	HeaderSignature = "X-Focalboard-Signature"
+)
+
 // NotifyUpdate calls webhooks.
 func (wh *Client) NotifyUpdate(block *model.Block) {
 	if len(wh.config.WebhookUpdate) < 1 {
@@ -23,7 +44,11 @@ func (wh *Client) NotifyUpdate(block *model.Block) {
 		wh.logger.Fatal("NotifyUpdate: json.Marshal", mlog.Err(err))
 	}
 	for _, url := range wh.config.WebhookUpdate {
-		resp, _ := http.Post(url, "application/json", bytes.NewBuffer(json)) //nolint:gosec
+		resp, err := wh.httpClient.Post(url, "application/json", bytes.NewBuffer(json)) //nolint:gosec
+		if err != nil {
+			wh.logger.Warn("webhook.NotifyUpdate failed", mlog.String("url", url), mlog.Err(err))
+			continue
+		}
 		_, _ = io.ReadAll(resp.Body)
 		resp.Body.Close()
 
@@ -31,16 +56,67 @@ func (wh *Client) NotifyUpdate(block *model.Block) {
 	}
 }
 
+// Deliver posts a delivery of an outgoing webhook to its URL, signed with
+// the secret of the webhook, and returns the status code and the
+// beginning of the body of the response. Internal addresses can only be
+// reached if they are in the WebhookAllowedIPRanges of the configuration.
+func (wh *Client) Deliver(url, secret string, delivery *model.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, delivery.Payload))
+
+	resp, err := wh.deliveryClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, model.WebhookResponseBodyMaxLength))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	// the rest of the body is drained so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// Sign returns the signature of the body of a request sent at a Unix
+// time: the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with
+// the secret of the webhook, prefixed with "sha256=".
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 // Client is a webhook client.
 type Client struct {
-	config *config.Configuration
-	logger mlog.LoggerIFace
+	config         *config.Configuration
+	logger         mlog.LoggerIFace
+	httpClient     *http.Client
+	deliveryClient *http.Client
 }
 
 // NewClient creates a new Client.
 func NewClient(config *config.Configuration, logger mlog.LoggerIFace) *Client {
 	return &Client{
-		config: config,
-		logger: logger,
+		config:         config,
+		logger:         logger,
+		httpClient:     &http.Client{Timeout: deliveryTimeout},
+		deliveryClient: newDeliveryClient(config.WebhookAllowedIPRanges, logger),
 	}
 }
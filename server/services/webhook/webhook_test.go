@@ -1,13 +1,19 @@
 package webhook
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
@@ -37,3 +43,85 @@ func TestClientUpdateNotify(t *testing.T) {
 		t.Error("webhook url not be notified")
 	}
 }
+
+func TestClientNotifyUpdateUnreachable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+
+	cfg := &config.Configuration{
+		WebhookUpdate: []string{ts.URL},
+	}
+	client := NewClient(cfg, mlog.CreateConsoleTestLogger(t))
+
+	assert.NotPanics(t, func() { client.NotifyUpdate(&model.Block{}) })
+}
+
+func TestClientDeliver(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("received"))
+	}))
+	defer ts.Close()
+
+	// the test server listens on the loopback address
+	client := NewClient(&config.Configuration{WebhookAllowedIPRanges: []string{"127.0.0.1", "::1/128"}}, mlog.CreateConsoleTestLogger(t))
+	delivery := &model.WebhookDelivery{
+		ID:      "delivery-id",
+		Event:   model.WebhookEventCardCreated,
+		Payload: []byte(`{"event":"card.created"}`),
+	}
+
+	t.Run("signed request", func(t *testing.T) {
+		code, body, err := client.Deliver(ts.URL, "secret", delivery)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "received", body)
+
+		assert.Equal(t, `{"event":"card.created"}`, string(receivedBody))
+		assert.Equal(t, model.WebhookEventCardCreated, received.Header.Get(HeaderEvent))
+		assert.Equal(t, "delivery-id", received.Header.Get(HeaderDelivery))
+
+		timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(received.Header.Get(HeaderTimestamp) + "." + string(receivedBody)))
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), received.Header.Get(HeaderSignature))
+		assert.Equal(t, Sign("secret", timestamp, receivedBody), received.Header.Get(HeaderSignature))
+		assert.NotEqual(t, Sign("other", timestamp, receivedBody), received.Header.Get(HeaderSignature))
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		code, body, err := client.Deliver(ts.URL, "secret", delivery)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, "received", body)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		closed.Close()
+		code, _, err := client.Deliver(closed.URL, "secret", delivery)
+		require.Error(t, err)
+		assert.Zero(t, code)
+	})
+
+	t.Run("internal addresses are refused", func(t *testing.T) {
+		received = nil
+		defaultClient := NewClient(&config.Configuration{}, mlog.CreateConsoleTestLogger(t))
+		code, _, err := defaultClient.Deliver(ts.URL, "secret", delivery)
+		require.ErrorIs(t, err, ErrInternalAddress)
+		assert.Zero(t, code)
+		assert.Nil(t, received)
+
+		for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://10.0.0.1", "http://[::1]:8080", "http://0.0.0.0"} {
+			_, _, err = defaultClient.Deliver(url, "secret", delivery)
+			require.ErrorIs(t, err, ErrInternalAddress, url)
+		}
+	})
+}
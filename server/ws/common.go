@@ -19,6 +19,15 @@ type UpdateBlockMsg struct {
 	Block  *model.Block `json:"block"`
 }
 
+// UpdateCardChecklistMsg is sent when the checklist progress of a card changes.
+type UpdateCardChecklistMsg struct {
+	Action    string                  `json:"action"`
+	TeamID    string                  `json:"teamId"`
+	BoardID   string                  `json:"boardId"`
+	CardID    string                  `json:"cardId"`
+	Checklist model.ChecklistProgress `json:"checklist"`
+}
+
 // UpdateBoardMsg is sent on block updates.
 type UpdateBoardMsg struct {
 	Action string       `json:"action"`
@@ -574,6 +574,24 @@ func (pa *PluginAdapter) BroadcastCategoryBoardChange(teamID, userID string, boa
 	pa.sendUserMessageSkipCluster(websocketActionUpdateCategoryBoard, utils.StructToMap(message), userID)
 }
 
+func (pa *PluginAdapter) BroadcastCardChecklistChange(teamID, boardID, cardID string, checklist model.ChecklistProgress) {
+	pa.logger.Trace("BroadcastingCardChecklistChange",
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+		mlog.String("cardID", cardID),
+	)
+
+	message := UpdateCardChecklistMsg{
+		Action:    websocketActionUpdateCardChecklist,
+		TeamID:    teamID,
+		BoardID:   boardID,
+		CardID:    cardID,
+		Checklist: checklist,
+	}
+
+	pa.sendBoardMessage(teamID, boardID, utils.StructToMap(message))
+}
+
 func (pa *PluginAdapter) BroadcastBlockDelete(teamID, blockID, boardID string) {
 	now := utils.GetMillis()
 	block := &model.Block{}
@@ -514,6 +514,41 @@ func (ws *Server) getListenersForTeamAndBoard(teamID, boardID string, ensureUser
 }
 
 // BroadcastBlockDelete broadcasts delete messages to clients.
+// BroadcastCardChecklistChange broadcasts the checklist progress of a card
+// to the clients of its board and of the card.
+func (ws *Server) BroadcastCardChecklistChange(teamID, boardID, cardID string, checklist model.ChecklistProgress) {
+	message := UpdateCardChecklistMsg{
+		Action:    websocketActionUpdateCardChecklist,
+		TeamID:    teamID,
+		BoardID:   boardID,
+		CardID:    cardID,
+		Checklist: checklist,
+	}
+
+	listeners := ws.getListenersForTeamAndBoard(teamID, boardID)
+	listeners = append(listeners, ws.getListenersForBlock(cardID)...)
+	ws.logger.Trace("listener(s) for teamID, boardID and cardID",
+		mlog.Int("listener_count", len(listeners)),
+		mlog.String("teamID", teamID),
+		mlog.String("boardID", boardID),
+		mlog.String("cardID", cardID),
+	)
+
+	for _, listener := range listeners {
+		ws.logger.Debug("Broadcast card checklist change",
+			mlog.String("teamID", teamID),
+			mlog.String("cardID", cardID),
+			mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
+		)
+
+		err := listener.WriteJSON(message)
+		if err != nil {
+			ws.logger.Error("broadcast error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
 func (ws *Server) BroadcastBlockDelete(teamID, blockID, boardID string) {
 	now := utils.GetMillis()
 	block := &model.Block{}